@@ -78,6 +78,12 @@ func (a *App) initConfig(config *d2config.Configuration) error {
 		}
 	}
 
+	for _, manifestPath := range a.config.ModManifests {
+		if err := a.asset.AddManifest(manifestPath); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
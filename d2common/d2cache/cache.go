@@ -18,7 +18,7 @@ type cacheNode struct {
 	weight int
 }
 
-// Cache stores arbitrary data for fast retrieval
+// Cache stores arbitrary data for fast retrieval, it is safe for concurrent use
 type Cache struct {
 	head    *cacheNode
 	tail    *cacheNode
@@ -26,6 +26,7 @@ type Cache struct {
 	weight  int
 	budget  int
 	verbose bool
+	evicted func(key string, value interface{})
 	mutex   sync.Mutex
 }
 
@@ -36,11 +37,26 @@ func CreateCache(budget int) d2interface.Cache {
 
 // SetVerbose turns on verbose printing (warnings and stuff)
 func (c *Cache) SetVerbose(verbose bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	c.verbose = verbose
 }
 
+// SetEvictHandler sets a function called with the entries the cache drops to stay within its
+// budget, or when it's cleared. It's called without the cache locked.
+func (c *Cache) SetEvictHandler(handler func(key string, value interface{})) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.evicted = handler
+}
+
 // GetWeight gets the "weight" of a cache
 func (c *Cache) GetWeight() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	return c.weight
 }
 
@@ -52,9 +68,9 @@ func (c *Cache) GetBudget() int {
 // Insert inserts an object into the cache
 func (c *Cache) Insert(key string, value interface{}, weight int) error {
 	c.mutex.Lock()
-	defer c.mutex.Unlock()
 
 	if _, found := c.lookup[key]; found {
+		c.mutex.Unlock()
 		return errors.New("key already exists in Cache")
 	}
 
@@ -77,6 +93,8 @@ func (c *Cache) Insert(key string, value interface{}, weight int) error {
 	c.lookup[key] = node
 	c.weight += node.weight
 
+	var evicted []*cacheNode
+
 	for ; c.tail != nil && c.tail != c.head && c.weight > c.budget; c.tail = c.tail.prev {
 		c.weight -= c.tail.weight
 		c.tail.prev.next = nil
@@ -93,6 +111,17 @@ func (c *Cache) Insert(key string, value interface{}, weight int) error {
 		}
 
 		delete(c.lookup, c.tail.key)
+
+		evicted = append(evicted, c.tail)
+	}
+
+	handler := c.evicted
+	c.mutex.Unlock()
+
+	if handler != nil {
+		for _, node := range evicted {
+			handler(node.key, node.value)
+		}
 	}
 
 	return nil
@@ -134,13 +163,51 @@ func (c *Cache) Retrieve(key string) (interface{}, bool) {
 	return node.value, true
 }
 
+// Remove removes an object from the cache, it returns false when the cache doesn't have it
+func (c *Cache) Remove(key string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	node, found := c.lookup[key]
+	if !found {
+		return false
+	}
+
+	if node.prev != nil {
+		node.prev.next = node.next
+	} else {
+		c.head = node.next
+	}
+
+	if node.next != nil {
+		node.next.prev = node.prev
+	} else {
+		c.tail = node.prev
+	}
+
+	delete(c.lookup, key)
+	c.weight -= node.weight
+
+	return true
+}
+
 // Clear removes all cache entries
 func (c *Cache) Clear() {
 	c.mutex.Lock()
-	defer c.mutex.Unlock()
+
+	lookup := c.lookup
+	handler := c.evicted
 
 	c.head = nil
 	c.tail = nil
 	c.lookup = make(map[string]*cacheNode)
 	c.weight = 0
+
+	c.mutex.Unlock()
+
+	if handler != nil {
+		for key, node := range lookup {
+			handler(key, node.value)
+		}
+	}
 }
@@ -91,3 +91,93 @@ func TestClear(t *testing.T) {
 		t.Fatal("Still able to retrieve nodes after cache was cleared")
 	}
 }
+
+func TestCacheRemove(t *testing.T) {
+	cache := CreateCache(3)
+	_ = cache.Insert("A", "", 1)
+	_ = cache.Insert("removed", "", 1)
+	_ = cache.Insert("B", "", 1)
+
+	if !cache.Remove("removed") || cache.Remove("removed") {
+		t.Fatal("Cache remove did not report whether the key was found")
+	}
+
+	if cache.GetWeight() != 2 {
+		t.Fatal("Cache remove did not update weight")
+	}
+
+	// the list is still intact, so that the oldest node is evicted next
+	_ = cache.Insert("C", "", 1)
+	_ = cache.Insert("D", "", 1)
+
+	_, foundA := cache.Retrieve("A")
+	_, foundB := cache.Retrieve("B")
+
+	if foundA || !foundB {
+		t.Fatal("Cache remove broke least recently used eviction")
+	}
+}
+
+func TestCacheEvictHandler(t *testing.T) {
+	cache := CreateCache(2)
+	evicted := make(map[string]interface{})
+
+	cache.SetEvictHandler(func(key string, value interface{}) {
+		evicted[key] = value
+	})
+
+	_ = cache.Insert("evicted", 1, 1)
+	_ = cache.Insert("A", 2, 1)
+	_ = cache.Insert("B", 3, 1)
+
+	if len(evicted) != 1 || evicted["evicted"] != 1 {
+		t.Fatalf("Cache evict handler was called with %v, want the evicted node only", evicted)
+	}
+
+	// removed keys are not evicted
+	cache.Remove("A")
+	cache.Clear()
+
+	if len(evicted) != 2 || evicted["B"] != 3 {
+		t.Fatalf("Cache evict handler was called with %v, want the cleared node as well", evicted)
+	}
+}
+
+func TestCacheConcurrent(t *testing.T) {
+	const (
+		workers = 8
+		keys    = 64
+	)
+
+	cache := CreateCache(keys / 2)
+	done := make(chan struct{})
+
+	for worker := 0; worker < workers; worker++ {
+		go func(worker int) {
+			defer func() { done <- struct{}{} }()
+
+			for idx := 0; idx < keys; idx++ {
+				key := string(rune('A' + (idx+worker)%keys))
+
+				if _, found := cache.Retrieve(key); !found {
+					// another worker may have inserted the key in the meantime
+					_ = cache.Insert(key, idx, 1)
+				}
+
+				if idx%8 == 0 {
+					cache.Remove(key)
+				}
+
+				_ = cache.GetWeight()
+			}
+		}(worker)
+	}
+
+	for worker := 0; worker < workers; worker++ {
+		<-done
+	}
+
+	if weight := cache.GetWeight(); weight > cache.GetBudget() {
+		t.Errorf("want weight within budget %d, have %d", cache.GetBudget(), weight)
+	}
+}
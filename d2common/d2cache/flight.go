@@ -0,0 +1,61 @@
+package d2cache
+
+import (
+	"errors"
+	"sync"
+)
+
+// errFlightPanic is returned to the waiting calls when the call in progress panics
+var errFlightPanic = errors.New("call in progress panicked")
+
+// flight is a call of FlightGroup.Do in progress
+type flight struct {
+	done  sync.WaitGroup
+	value interface{}
+	err   error
+	// dups counts the calls waiting for this one
+	dups int
+}
+
+// FlightGroup de-duplicates calls with the same key. While a call for a key is in progress,
+// the other calls for the key wait for it and share its result instead of doing the work again.
+// The zero value is ready to use.
+type FlightGroup struct {
+	flights map[string]*flight
+	mutex   sync.Mutex
+}
+
+// Do calls fn and returns its result, unless a call for the key is in progress already. In that
+// case it waits for the call in progress and returns its result, shared is true then.
+func (g *FlightGroup) Do(key string, fn func() (interface{}, error)) (value interface{}, shared bool, err error) {
+	g.mutex.Lock()
+
+	if g.flights == nil {
+		g.flights = make(map[string]*flight)
+	}
+
+	if f, found := g.flights[key]; found {
+		f.dups++
+		g.mutex.Unlock()
+		f.done.Wait()
+
+		return f.value, true, f.err
+	}
+
+	f := &flight{err: errFlightPanic}
+	f.done.Add(1)
+	g.flights[key] = f
+	g.mutex.Unlock()
+
+	defer func() {
+		g.mutex.Lock()
+		delete(g.flights, key)
+		g.mutex.Unlock()
+
+		f.done.Done()
+	}()
+
+	f.value, f.err = fn()
+
+	return f.value, false, f.err
+}
@@ -0,0 +1,89 @@
+package d2cache
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestFlightGroupShares(t *testing.T) {
+	const callers = 8
+
+	var (
+		group   FlightGroup
+		calls   int32
+		wg      sync.WaitGroup
+		release = make(chan struct{})
+		started = make(chan struct{})
+	)
+
+	results := make([]interface{}, callers)
+
+	// the first call blocks until it's released, once every other call waits for it
+	go func() {
+		_, _, _ = group.Do("key", func() (interface{}, error) {
+			close(started)
+			<-release
+			atomic.AddInt32(&calls, 1)
+
+			return "value", nil
+		})
+	}()
+
+	<-started
+
+	for idx := 0; idx < callers; idx++ {
+		wg.Add(1)
+
+		go func(idx int) {
+			defer wg.Done()
+
+			value, shared, err := group.Do("key", func() (interface{}, error) {
+				atomic.AddInt32(&calls, 1)
+				return "other", nil
+			})
+
+			if err != nil || !shared {
+				t.Errorf("want a shared result, have shared %t, error %v", shared, err)
+			}
+
+			results[idx] = value
+		}(idx)
+	}
+
+	for waiting := 0; waiting < callers; {
+		group.mutex.Lock()
+		waiting = group.flights["key"].dups
+		group.mutex.Unlock()
+	}
+
+	close(release)
+	wg.Wait()
+
+	if calls := atomic.LoadInt32(&calls); calls != 1 {
+		t.Errorf("want 1 call, have %d", calls)
+	}
+
+	for _, value := range results {
+		if value != "value" {
+			t.Errorf("want shared value, have %v", value)
+		}
+	}
+}
+
+func TestFlightGroupSequential(t *testing.T) {
+	var group FlightGroup
+
+	testErr := errors.New("test")
+
+	for idx := 0; idx < 2; idx++ {
+		value, shared, err := group.Do("key", func() (interface{}, error) {
+			return idx, testErr
+		})
+
+		if value != idx || shared || !errors.Is(err, testErr) {
+			t.Errorf("want a call of its own, have %v, shared %t, error %v", value, shared, err)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+//go:build go1.18
+// +build go1.18
+
+package d2compression
+
+import (
+	"bytes"
+	"testing"
+)
+
+func FuzzHuffmanRoundTrip(f *testing.F) {
+	for _, data := range huffmanTestData() {
+		f.Add(data, byte(2))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte, compType byte) {
+		compType = compType%maxHuffmanType + minHuffmanType
+
+		compressed, err := HuffmanCompress(data, compType)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if have := HuffmanDecompress(compressed); !bytes.Equal(have, data) {
+			t.Errorf("type %d: data changed after compression", compType)
+		}
+	})
+}
+
+func FuzzWavRoundTrip(f *testing.F) {
+	f.Add(wavTestData(1)[:512], 1, WavLevelMedium)
+	f.Add(wavTestData(2)[:512], 2, WavLevelHigh)
+
+	f.Fuzz(func(t *testing.T, data []byte, channelCount, level int) {
+		channelCount = 1 + int(uint(channelCount)%wavMaxChannels)
+		level = WavLevelLow + int(uint(level)%(WavLevelHigh-WavLevelLow+1))
+
+		if len(data) < channelCount*2 {
+			return
+		}
+
+		compressed, err := WavCompress(data, channelCount, level)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decompressed, err := WavDecompress(compressed, channelCount)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(decompressed) != len(data)&^1 {
+			t.Errorf("want %d bytes, have %d", len(data)&^1, len(decompressed))
+		}
+	})
+}
@@ -1,4 +1,4 @@
-// Package d2compression is used for decompressing WAV files.
+// Package d2compression is used for decompressing the compression methods of MPQ archives.
 package d2compression
 
 // MpqHuffman.go based on the original CS file
@@ -31,6 +31,8 @@ package d2compression
 //
 
 import (
+	"errors"
+	"fmt"
 	"log"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2datautils"
@@ -410,3 +412,93 @@ Loop:
 
 	return outputstream.GetBytes()
 }
+
+const (
+	minHuffmanType = 1
+	maxHuffmanType = 8
+	byteBits       = 8
+)
+
+var errHuffmanType = errors.New("unsupported huffman compression type")
+
+// HuffmanCompress compresses data with the huffman tree of a compression type, from 1 to 8, so
+// that HuffmanDecompress restores it. The type is stored in the first byte.
+func HuffmanCompress(data []byte, compType byte) ([]byte, error) {
+	if compType < minHuffmanType || compType > maxHuffmanType {
+		return nil, fmt.Errorf("%w: %d", errHuffmanType, compType)
+	}
+
+	tail := buildList(getPrimes()[compType])
+	head := buildTree(tail)
+
+	codes := huffmanCodes(head)
+
+	output := d2datautils.CreateStreamWriter()
+	output.PushBytes(compType)
+
+	bitCount := 0
+
+	encode := func(code []bool) {
+		for _, bit := range code {
+			output.PushBit(bit)
+		}
+
+		bitCount += len(code)
+	}
+
+	for _, value := range data {
+		if code, found := codes[int(value)]; found {
+			encode(code)
+			continue
+		}
+
+		encode(codes[decompVal2])
+		output.PushBits(value, byteBits)
+
+		bitCount += byteBits
+
+		tail = insertNode(tail, int(value))
+		codes = huffmanCodes(head)
+	}
+
+	encode(codes[decompVal1])
+
+	for ; bitCount%byteBits != 0; bitCount++ {
+		output.PushBit(false)
+	}
+
+	return output.GetBytes(), nil
+}
+
+// huffmanCodes returns the bits the decoder reads to reach each value, following the links it
+// follows. After nodes were moved the second child of a node isn't always next to the first one
+// in the tree, so the codes can't be read off the parent links of the leaves.
+func huffmanCodes(head *linkedNode) map[int][]bool {
+	codes := make(map[int][]bool)
+	visited := make(map[*linkedNode]bool)
+
+	var walk func(node *linkedNode, code []bool)
+
+	walk = func(node *linkedNode, code []bool) {
+		if node == nil || visited[node] {
+			return
+		}
+
+		visited[node] = true
+
+		if node.child0 == nil {
+			if _, found := codes[node.decompressedValue]; !found {
+				codes[node.decompressedValue] = append([]bool(nil), code...)
+			}
+
+			return
+		}
+
+		walk(node.child0, append(code, false))
+		walk(node.getChild1(), append(code, true))
+	}
+
+	walk(head, nil)
+
+	return codes
+}
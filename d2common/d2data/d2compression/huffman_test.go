@@ -0,0 +1,80 @@
+package d2compression
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+func huffmanTestData() map[string][]byte {
+	random := make([]byte, 4096)
+	rand.New(rand.NewSource(1)).Read(random)
+
+	every := make([]byte, 256)
+	for i := range every {
+		every[i] = byte(i)
+	}
+
+	return map[string][]byte{
+		"empty":  {},
+		"single": {'a'},
+		"text":   bytes.Repeat([]byte("name\tversion\tcompactsave\r\n"), 100),
+		"zeros":  make([]byte, 1000),
+		"every":  bytes.Repeat(every, 4),
+		"random": random,
+	}
+}
+
+func TestHuffmanRoundTrip(t *testing.T) {
+	for compType := byte(minHuffmanType); compType <= maxHuffmanType; compType++ {
+		for name, data := range huffmanTestData() {
+			compressed, err := HuffmanCompress(data, compType)
+			if err != nil {
+				t.Fatalf("type %d, %s: %v", compType, name, err)
+			}
+
+			if compressed[0] != compType {
+				t.Errorf("type %d, %s: compressed as type %d", compType, name, compressed[0])
+			}
+
+			if have := HuffmanDecompress(compressed); !bytes.Equal(have, data) {
+				t.Errorf("type %d, %s: data changed after compression", compType, name)
+			}
+		}
+	}
+}
+
+func TestHuffmanCompressTypes(t *testing.T) {
+	for _, compType := range []byte{0, maxHuffmanType + 1} {
+		if _, err := HuffmanCompress([]byte("data"), compType); err == nil {
+			t.Errorf("type %d: want an error", compType)
+		}
+	}
+}
+
+func BenchmarkHuffmanCompress(b *testing.B) {
+	data := huffmanTestData()["text"]
+
+	b.SetBytes(int64(len(data)))
+
+	for i := 0; i < b.N; i++ {
+		if _, err := HuffmanCompress(data, 2); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkHuffmanDecompress(b *testing.B) {
+	data := huffmanTestData()["text"]
+
+	compressed, err := HuffmanCompress(data, 2)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.SetBytes(int64(len(data)))
+
+	for i := 0; i < b.N; i++ {
+		HuffmanDecompress(compressed)
+	}
+}
@@ -0,0 +1,401 @@
+package d2compression
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// LZMA decoder, based on the LZMA specification and reference decoder from the LZMA SDK
+// by Igor Pavlov (public domain).
+
+const (
+	lzmaPropsSize  = 5
+	lzmaHeaderSize = lzmaPropsSize + 8
+
+	lzmaNumStates       = 12
+	lzmaNumPosBitsMax   = 4
+	lzmaNumLenToPosStat = 4
+	lzmaNumAlignBits    = 4
+	lzmaStartPosModel   = 4
+	lzmaEndPosModel     = 14
+	lzmaNumFullDistance = 1 << (lzmaEndPosModel >> 1)
+	lzmaMatchMinLen     = 2
+
+	lzmaProbBits   = 11
+	lzmaProbInit   = 1 << (lzmaProbBits - 1)
+	lzmaMoveBits   = 5
+	lzmaTopValue   = 1 << 24
+	lzmaEndMarker  = 0xFFFFFFFF
+	lzmaMaxLcLpPb  = 9 * 5 * 5
+	lzmaLiteralLen = 0x300
+)
+
+var errLZMACorrupt = errors.New("lzma: corrupt data")
+
+type lzmaRangeDecoder struct {
+	data   []byte
+	pos    int
+	rng    uint32
+	code   uint32
+	failed bool
+}
+
+func (rc *lzmaRangeDecoder) init() bool {
+	if len(rc.data) < 5 || rc.data[0] != 0 { //nolint:gomnd // range coder init bytes
+		return false
+	}
+
+	rc.rng = 0xFFFFFFFF
+	rc.code = binary.BigEndian.Uint32(rc.data[1:5])
+	rc.pos = 5
+
+	return rc.code != rc.rng
+}
+
+func (rc *lzmaRangeDecoder) nextByte() uint32 {
+	if rc.pos >= len(rc.data) {
+		rc.failed = true
+		return 0
+	}
+
+	b := rc.data[rc.pos]
+	rc.pos++
+
+	return uint32(b)
+}
+
+func (rc *lzmaRangeDecoder) normalize() {
+	if rc.rng < lzmaTopValue {
+		rc.rng <<= 8
+		rc.code = (rc.code << 8) | rc.nextByte()
+	}
+}
+
+func (rc *lzmaRangeDecoder) decodeBit(prob *uint16) uint32 {
+	bound := (rc.rng >> lzmaProbBits) * uint32(*prob)
+
+	var bit uint32
+
+	if rc.code < bound {
+		*prob += ((1 << lzmaProbBits) - *prob) >> lzmaMoveBits
+		rc.rng = bound
+	} else {
+		*prob -= *prob >> lzmaMoveBits
+		rc.code -= bound
+		rc.rng -= bound
+		bit = 1
+	}
+
+	rc.normalize()
+
+	return bit
+}
+
+func (rc *lzmaRangeDecoder) decodeDirectBits(numBits uint) uint32 {
+	var result uint32
+
+	for ; numBits > 0; numBits-- {
+		rc.rng >>= 1
+		result <<= 1
+
+		if rc.code >= rc.rng {
+			rc.code -= rc.rng
+			result |= 1
+		}
+
+		rc.normalize()
+	}
+
+	return result
+}
+
+func (rc *lzmaRangeDecoder) decodeBitTree(probs []uint16, numBits uint) uint32 {
+	m := uint32(1)
+
+	for i := uint(0); i < numBits; i++ {
+		m = (m << 1) + rc.decodeBit(&probs[m])
+	}
+
+	return m - (1 << numBits)
+}
+
+func (rc *lzmaRangeDecoder) decodeReverseBitTree(probs []uint16, numBits uint) uint32 {
+	m, symbol := uint32(1), uint32(0)
+
+	for i := uint(0); i < numBits; i++ {
+		bit := rc.decodeBit(&probs[m])
+		m = (m << 1) + bit
+		symbol |= bit << i
+	}
+
+	return symbol
+}
+
+type lzmaLenDecoder struct {
+	choice  uint16
+	choice2 uint16
+	low     [1 << lzmaNumPosBitsMax][1 << 3]uint16
+	mid     [1 << lzmaNumPosBitsMax][1 << 3]uint16
+	high    [1 << 8]uint16
+}
+
+func newLZMALenDecoder() *lzmaLenDecoder {
+	d := &lzmaLenDecoder{choice: lzmaProbInit, choice2: lzmaProbInit}
+
+	for posState := range d.low {
+		initProbs(d.low[posState][:])
+		initProbs(d.mid[posState][:])
+	}
+
+	initProbs(d.high[:])
+
+	return d
+}
+
+//nolint:gomnd // length coder ranges
+func (d *lzmaLenDecoder) decode(rc *lzmaRangeDecoder, posState uint32) uint32 {
+	if rc.decodeBit(&d.choice) == 0 {
+		return rc.decodeBitTree(d.low[posState][:], 3)
+	}
+
+	if rc.decodeBit(&d.choice2) == 0 {
+		return 8 + rc.decodeBitTree(d.mid[posState][:], 3)
+	}
+
+	return 16 + rc.decodeBitTree(d.high[:], 8)
+}
+
+func initProbs(probs []uint16) {
+	for i := range probs {
+		probs[i] = lzmaProbInit
+	}
+}
+
+type lzmaDecoder struct {
+	rc         *lzmaRangeDecoder
+	out        []byte
+	lc, lp, pb uint
+
+	literalProbs []uint16
+	posSlot      [lzmaNumLenToPosStat][1 << 6]uint16
+	posDecoders  [1 + lzmaNumFullDistance - lzmaEndPosModel]uint16
+	align        [1 << lzmaNumAlignBits]uint16
+	isMatch      [lzmaNumStates << lzmaNumPosBitsMax]uint16
+	isRep        [lzmaNumStates]uint16
+	isRepG0      [lzmaNumStates]uint16
+	isRepG1      [lzmaNumStates]uint16
+	isRepG2      [lzmaNumStates]uint16
+	isRep0Long   [lzmaNumStates << lzmaNumPosBitsMax]uint16
+	lenDecoder   *lzmaLenDecoder
+	repLen       *lzmaLenDecoder
+}
+
+// LZMADecompress decompresses LZMA data with the 13 byte header of the .lzma format, the
+// properties and the uncompressed size, to the given size. The size in the header is ignored.
+//
+//nolint:gomnd // binary decode magic
+func LZMADecompress(data []byte, size int) ([]byte, error) {
+	if len(data) < lzmaHeaderSize || data[0] >= lzmaMaxLcLpPb {
+		return nil, errLZMACorrupt
+	}
+
+	props := uint(data[0])
+
+	d := &lzmaDecoder{
+		rc:         &lzmaRangeDecoder{data: data[lzmaHeaderSize:]},
+		out:        make([]byte, 0, size),
+		lc:         props % 9,
+		lp:         (props / 9) % 5,
+		pb:         props / 45,
+		lenDecoder: newLZMALenDecoder(),
+		repLen:     newLZMALenDecoder(),
+	}
+
+	d.literalProbs = make([]uint16, lzmaLiteralLen<<(d.lc+d.lp))
+
+	for _, probs := range [][]uint16{d.literalProbs, d.posDecoders[:], d.align[:], d.isMatch[:], d.isRep[:],
+		d.isRepG0[:], d.isRepG1[:], d.isRepG2[:], d.isRep0Long[:]} {
+		initProbs(probs)
+	}
+
+	for lenState := range d.posSlot {
+		initProbs(d.posSlot[lenState][:])
+	}
+
+	if !d.rc.init() {
+		return nil, errLZMACorrupt
+	}
+
+	if err := d.decode(size); err != nil {
+		return nil, err
+	}
+
+	return d.out, nil
+}
+
+//nolint:funlen,gocyclo,gocognit,gomnd // follows the reference decoder
+func (d *lzmaDecoder) decode(size int) error {
+	var rep0, rep1, rep2, rep3, state uint32
+
+	for len(d.out) < size {
+		if d.rc.failed {
+			return errLZMACorrupt
+		}
+
+		posState := uint32(len(d.out)) & ((1 << d.pb) - 1)
+
+		if d.rc.decodeBit(&d.isMatch[(state<<lzmaNumPosBitsMax)+posState]) == 0 {
+			d.decodeLiteral(state, rep0)
+
+			switch {
+			case state < 4:
+				state = 0
+			case state < 10:
+				state -= 3
+			default:
+				state -= 6
+			}
+
+			continue
+		}
+
+		var length uint32
+
+		if d.rc.decodeBit(&d.isRep[state]) != 0 {
+			if d.rc.decodeBit(&d.isRepG0[state]) == 0 {
+				if d.rc.decodeBit(&d.isRep0Long[(state<<lzmaNumPosBitsMax)+posState]) == 0 {
+					if int(rep0) >= len(d.out) {
+						return errLZMACorrupt
+					}
+
+					if state < 7 {
+						state = 9
+					} else {
+						state = 11
+					}
+
+					d.out = append(d.out, d.out[len(d.out)-int(rep0)-1])
+
+					continue
+				}
+			} else {
+				var dist uint32
+
+				if d.rc.decodeBit(&d.isRepG1[state]) == 0 {
+					dist = rep1
+				} else {
+					if d.rc.decodeBit(&d.isRepG2[state]) == 0 {
+						dist = rep2
+					} else {
+						dist = rep3
+						rep3 = rep2
+					}
+
+					rep2 = rep1
+				}
+
+				rep1 = rep0
+				rep0 = dist
+			}
+
+			length = d.repLen.decode(d.rc, posState)
+
+			if state < 7 {
+				state = 8
+			} else {
+				state = 11
+			}
+		} else {
+			rep3, rep2, rep1 = rep2, rep1, rep0
+			length = d.lenDecoder.decode(d.rc, posState)
+
+			if state < 7 {
+				state = 7
+			} else {
+				state = 10
+			}
+
+			rep0 = d.decodeDistance(length)
+			if rep0 == lzmaEndMarker {
+				break
+			}
+		}
+
+		if int(rep0) >= len(d.out) {
+			return errLZMACorrupt
+		}
+
+		length += lzmaMatchMinLen
+		if remaining := size - len(d.out); int(length) > remaining {
+			length = uint32(remaining)
+		}
+
+		for start := len(d.out) - int(rep0) - 1; length > 0; length-- {
+			d.out = append(d.out, d.out[start])
+			start++
+		}
+	}
+
+	if d.rc.failed || len(d.out) != size {
+		return errLZMACorrupt
+	}
+
+	return nil
+}
+
+//nolint:gomnd // binary decode magic
+func (d *lzmaDecoder) decodeLiteral(state, rep0 uint32) {
+	var prevByte uint32
+	if len(d.out) > 0 {
+		prevByte = uint32(d.out[len(d.out)-1])
+	}
+
+	litState := ((uint32(len(d.out)) & ((1 << d.lp) - 1)) << d.lc) + (prevByte >> (8 - d.lc))
+	probs := d.literalProbs[lzmaLiteralLen*litState : lzmaLiteralLen*(litState+1)]
+	symbol := uint32(1)
+
+	if state >= 7 {
+		matchByte := uint32(d.out[len(d.out)-int(rep0)-1])
+
+		for symbol < 0x100 {
+			matchBit := (matchByte >> 7) & 1
+			matchByte <<= 1
+			bit := d.rc.decodeBit(&probs[((1+matchBit)<<8)+symbol])
+			symbol = (symbol << 1) | bit
+
+			if matchBit != bit {
+				break
+			}
+		}
+	}
+
+	for symbol < 0x100 {
+		symbol = (symbol << 1) | d.rc.decodeBit(&probs[symbol])
+	}
+
+	d.out = append(d.out, byte(symbol-0x100))
+}
+
+//nolint:gomnd // binary decode magic
+func (d *lzmaDecoder) decodeDistance(length uint32) uint32 {
+	lenState := length
+	if lenState > lzmaNumLenToPosStat-1 {
+		lenState = lzmaNumLenToPosStat - 1
+	}
+
+	posSlot := d.rc.decodeBitTree(d.posSlot[lenState][:], 6)
+	if posSlot < lzmaStartPosModel {
+		return posSlot
+	}
+
+	numDirectBits := uint((posSlot >> 1) - 1)
+	dist := (2 | (posSlot & 1)) << numDirectBits
+
+	if posSlot < lzmaEndPosModel {
+		return dist + d.rc.decodeReverseBitTree(d.posDecoders[dist-posSlot:], numDirectBits)
+	}
+
+	dist += d.rc.decodeDirectBits(numDirectBits-lzmaNumAlignBits) << lzmaNumAlignBits
+
+	return dist + d.rc.decodeReverseBitTree(d.align[:], lzmaNumAlignBits)
+}
@@ -0,0 +1,60 @@
+package d2compression
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	sparseHeaderSize  = 4
+	sparseLiteralFlag = 0x80
+	sparseLengthMask  = 0x7F
+	sparseMinLiterals = 1
+	sparseMinZeros    = 3
+)
+
+// SparseDecompress decompresses data compressed with the sparse compression of MPQ archives,
+// which replaces runs of zeros. The data starts with the big endian size of the output,
+// followed by chunks of literal bytes or zeros.
+func SparseDecompress(data []byte) ([]byte, error) {
+	if len(data) < sparseHeaderSize {
+		return nil, errors.New("sparse: missing output size")
+	}
+
+	size := int(binary.BigEndian.Uint32(data))
+	output := make([]byte, 0, size)
+
+	for pos := sparseHeaderSize; pos < len(data) && len(output) < size; {
+		chunk := data[pos]
+		pos++
+
+		if chunk&sparseLiteralFlag == 0 {
+			length := int(chunk&sparseLengthMask) + sparseMinZeros
+			if length > size-len(output) {
+				length = size - len(output)
+			}
+
+			output = append(output, make([]byte, length)...)
+
+			continue
+		}
+
+		length := int(chunk&sparseLengthMask) + sparseMinLiterals
+		if length > size-len(output) {
+			length = size - len(output)
+		}
+
+		if pos+length > len(data) {
+			return nil, errors.New("sparse: unexpected end of data")
+		}
+
+		output = append(output, data[pos:pos+length]...)
+		pos += length
+	}
+
+	if len(output) != size {
+		return nil, errors.New("sparse: unexpected end of data")
+	}
+
+	return output, nil
+}
@@ -1,37 +1,62 @@
 package d2compression
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2datautils"
 )
 
+// Compression levels of WavCompress, the ones the original archives are compressed with. Higher
+// levels keep more bits of each sample.
+const (
+	WavLevelLow    = 4
+	WavLevelMedium = 5
+	WavLevelHigh   = 6
+)
+
+const (
+	wavInitialStepIndex = 0x2c
+	wavMaxStepIndex     = 0x58
+	wavMaxChannels      = 2
+	wavMaxBitMask       = 0x20
+	wavSignBit          = 0x40
+	wavRepeatSample     = 0x80 // the sample is the last one of the channel again
+	wavStepIndexUp      = 0x81 // the step index grows, no sample is coded
+)
+
+var errWavFormat = errors.New("unsupported wav compression format")
+
+// wavStepSizes are the ADPCM step sizes, indexed by the step index of a channel
+var wavStepSizes = []int{
+	0x0007, 0x0008, 0x0009, 0x000A, 0x000B, 0x000C, 0x000D, 0x000E,
+	0x0010, 0x0011, 0x0013, 0x0015, 0x0017, 0x0019, 0x001C, 0x001F,
+	0x0022, 0x0025, 0x0029, 0x002D, 0x0032, 0x0037, 0x003C, 0x0042,
+	0x0049, 0x0050, 0x0058, 0x0061, 0x006B, 0x0076, 0x0082, 0x008F,
+	0x009D, 0x00AD, 0x00BE, 0x00D1, 0x00E6, 0x00FD, 0x0117, 0x0133,
+	0x0151, 0x0173, 0x0198, 0x01C1, 0x01EE, 0x0220, 0x0256, 0x0292,
+	0x02D4, 0x031C, 0x036C, 0x03C3, 0x0424, 0x048E, 0x0502, 0x0583,
+	0x0610, 0x06AB, 0x0756, 0x0812, 0x08E0, 0x09C3, 0x0ABD, 0x0BD0,
+	0x0CFF, 0x0E4C, 0x0FBA, 0x114C, 0x1307, 0x14EE, 0x1706, 0x1954,
+	0x1BDC, 0x1EA5, 0x21B6, 0x2515, 0x28CA, 0x2CDF, 0x315B, 0x364B,
+	0x3BB9, 0x41B2, 0x4844, 0x4F7E, 0x5771, 0x602F, 0x69CE, 0x7462,
+	0x7FFF,
+}
+
+// wavIndexSteps change the step index of a channel by the low 5 bits of an encoded sample
+var wavIndexSteps = []int{
+	-1, 0, -1, 4, -1, 2, -1, 6,
+	-1, 1, -1, 5, -1, 3, -1, 7,
+	-1, 1, -1, 5, -1, 3, -1, 7,
+	-1, 2, -1, 4, -1, 6, -1, 8,
+}
+
 // WavDecompress decompresses wav files
 //nolint:gomnd // binary decode magic
 func WavDecompress(data []byte, channelCount int) ([]byte, error) { //nolint:funlen,gocognit,gocyclo // can't reduce
 	Array1 := []int{0x2c, 0x2c}
 	Array2 := make([]int, channelCount)
 
-	var sLookup = []int{
-		0x0007, 0x0008, 0x0009, 0x000A, 0x000B, 0x000C, 0x000D, 0x000E,
-		0x0010, 0x0011, 0x0013, 0x0015, 0x0017, 0x0019, 0x001C, 0x001F,
-		0x0022, 0x0025, 0x0029, 0x002D, 0x0032, 0x0037, 0x003C, 0x0042,
-		0x0049, 0x0050, 0x0058, 0x0061, 0x006B, 0x0076, 0x0082, 0x008F,
-		0x009D, 0x00AD, 0x00BE, 0x00D1, 0x00E6, 0x00FD, 0x0117, 0x0133,
-		0x0151, 0x0173, 0x0198, 0x01C1, 0x01EE, 0x0220, 0x0256, 0x0292,
-		0x02D4, 0x031C, 0x036C, 0x03C3, 0x0424, 0x048E, 0x0502, 0x0583,
-		0x0610, 0x06AB, 0x0756, 0x0812, 0x08E0, 0x09C3, 0x0ABD, 0x0BD0,
-		0x0CFF, 0x0E4C, 0x0FBA, 0x114C, 0x1307, 0x14EE, 0x1706, 0x1954,
-		0x1BDC, 0x1EA5, 0x21B6, 0x2515, 0x28CA, 0x2CDF, 0x315B, 0x364B,
-		0x3BB9, 0x41B2, 0x4844, 0x4F7E, 0x5771, 0x602F, 0x69CE, 0x7462,
-		0x7FFF,
-	}
-
-	var sLookup2 = []int{
-		-1, 0, -1, 4, -1, 2, -1, 6,
-		-1, 1, -1, 5, -1, 3, -1, 7,
-		-1, 1, -1, 5, -1, 3, -1, 7,
-		-1, 2, -1, 4, -1, 6, -1, 8,
-	}
-
 	input := d2datautils.CreateStreamReader(data)
 	output := d2datautils.CreateStreamWriter()
 
@@ -96,7 +121,7 @@ func WavDecompress(data []byte, channelCount int) ([]byte, error) { //nolint:fun
 				}
 			}
 		} else {
-			temp1 := sLookup[Array1[channel]]
+			temp1 := wavStepSizes[Array1[channel]]
 			temp2 := temp1 >> shift
 
 			if (value & 1) != 0 {
@@ -132,7 +157,7 @@ func WavDecompress(data []byte, channelCount int) ([]byte, error) { //nolint:fun
 			}
 			Array2[channel] = temp3
 			output.PushInt16(int16(temp3))
-			Array1[channel] += sLookup2[value&0x1f]
+			Array1[channel] += wavIndexSteps[value&0x1f]
 
 			if Array1[channel] < 0 {
 				Array1[channel] = 0
@@ -144,3 +169,126 @@ func WavDecompress(data []byte, channelCount int) ([]byte, error) { //nolint:fun
 
 	return output.GetBytes(), nil
 }
+
+// WavCompress compresses 16 bit PCM samples to IMA ADPCM, samples of stereo data are interleaved.
+// WavDecompress restores the samples, within the precision of the level.
+func WavCompress(data []byte, channelCount, level int) ([]byte, error) {
+	if channelCount < 1 || channelCount > wavMaxChannels {
+		return nil, fmt.Errorf("%w: %d channels", errWavFormat, channelCount)
+	}
+
+	if level < WavLevelLow || level > WavLevelHigh {
+		return nil, fmt.Errorf("%w: level %d", errWavFormat, level)
+	}
+
+	input := d2datautils.CreateStreamReader(data)
+	output := d2datautils.CreateStreamWriter()
+
+	shift := level - 1
+	output.PushBytes(0, byte(shift))
+
+	stepIndex := []int{wavInitialStepIndex, wavInitialStepIndex}
+	predicted := make([]int, channelCount)
+
+	// the first sample of each channel is stored as is
+	for i := 0; i < channelCount; i++ {
+		sample, err := input.ReadInt16()
+		if err != nil {
+			return output.GetBytes(), nil
+		}
+
+		predicted[i] = int(sample)
+		output.PushInt16(sample)
+	}
+
+	maxBitMask := 1 << (shift - 1)
+	if maxBitMask > wavMaxBitMask {
+		maxBitMask = wavMaxBitMask
+	}
+
+	channel := channelCount - 1
+
+	for input.Size()-input.Position() >= 2 { //nolint:gomnd // 16 bit samples
+		value, err := input.ReadInt16()
+		if err != nil {
+			return nil, err
+		}
+
+		channel = (channel + 1) % channelCount
+
+		encoded := 0
+		difference := int(value) - predicted[channel]
+
+		if difference < 0 {
+			difference = -difference
+			encoded |= wavSignBit
+		}
+
+		stepSize := wavStepSizes[stepIndex[channel]]
+
+		// differences below the precision of the level repeat the last sample and use a smaller step
+		if difference < stepSize>>level {
+			if stepIndex[channel] != 0 {
+				stepIndex[channel]--
+			}
+
+			output.PushBytes(wavRepeatSample)
+
+			continue
+		}
+
+		for difference > stepSize<<1 && stepIndex[channel] < wavMaxStepIndex {
+			stepIndex[channel] += 8 //nolint:gomnd // step of the marker, see WavDecompress
+			if stepIndex[channel] > wavMaxStepIndex {
+				stepIndex[channel] = wavMaxStepIndex
+			}
+
+			stepSize = wavStepSizes[stepIndex[channel]]
+
+			output.PushBytes(wavStepIndexUp)
+		}
+
+		// each bit of the sample adds the step size shifted by the bit position
+		base, total := stepSize>>shift, 0
+
+		for bit := 1; bit <= maxBitMask; bit <<= 1 {
+			if total+stepSize <= difference {
+				total += stepSize
+				encoded |= bit
+			}
+
+			stepSize >>= 1
+		}
+
+		predicted[channel] = predictSample(predicted[channel], encoded, base+total)
+		output.PushBytes(byte(encoded))
+
+		stepIndex[channel] += wavIndexSteps[encoded&0x1f] //nolint:gomnd // bits of the index change
+		if stepIndex[channel] < 0 {
+			stepIndex[channel] = 0
+		} else if stepIndex[channel] > wavMaxStepIndex {
+			stepIndex[channel] = wavMaxStepIndex
+		}
+	}
+
+	return output.GetBytes(), nil
+}
+
+// predictSample applies a difference to a sample the way WavDecompress does
+func predictSample(sample, encoded, difference int) int {
+	if encoded&wavSignBit != 0 {
+		sample -= difference
+		if sample <= -32768 {
+			sample = -32768
+		}
+
+		return sample
+	}
+
+	sample += difference
+	if sample >= 32767 {
+		sample = 32767
+	}
+
+	return sample
+}
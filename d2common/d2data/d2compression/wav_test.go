@@ -0,0 +1,120 @@
+package d2compression
+
+import (
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// wavTestData returns a second of interleaved 16 bit samples, sine waves with some noise
+func wavTestData(channelCount int) []byte {
+	const sampleRate = 22050
+
+	random := rand.New(rand.NewSource(1))
+	data := make([]byte, 0, sampleRate*channelCount*2)
+
+	for i := 0; i < sampleRate; i++ {
+		for ch := 0; ch < channelCount; ch++ {
+			freq := 440 * float64(ch+1)
+			value := 12000*math.Sin(2*math.Pi*freq*float64(i)/sampleRate) + random.Float64()*500
+			data = append(data, 0, 0)
+			binary.LittleEndian.PutUint16(data[len(data)-2:], uint16(int16(value)))
+		}
+	}
+
+	return data
+}
+
+// wavError returns the RMS difference of two sample buffers, relative to the first one
+func wavError(want, have []byte) float64 {
+	var signal, noise float64
+
+	for i := 0; i+1 < len(want); i += 2 {
+		w := float64(int16(binary.LittleEndian.Uint16(want[i:])))
+		h := float64(int16(binary.LittleEndian.Uint16(have[i:])))
+		signal += w * w
+		noise += (w - h) * (w - h)
+	}
+
+	return math.Sqrt(noise / signal)
+}
+
+func TestWavRoundTrip(t *testing.T) {
+	tests := []struct {
+		level    int
+		maxError float64
+	}{
+		{WavLevelLow, 0.2},
+		{WavLevelMedium, 0.1},
+		{WavLevelHigh, 0.05},
+	}
+
+	for channelCount := 1; channelCount <= wavMaxChannels; channelCount++ {
+		data := wavTestData(channelCount)
+
+		for _, test := range tests {
+			compressed, err := WavCompress(data, channelCount, test.level)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(compressed) >= len(data)*6/10 {
+				t.Errorf("%d channels, level %d: compressed to %d of %d bytes", channelCount, test.level,
+					len(compressed), len(data))
+			}
+
+			decompressed, err := WavDecompress(compressed, channelCount)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(decompressed) != len(data) {
+				t.Fatalf("%d channels, level %d: want %d bytes, have %d", channelCount, test.level,
+					len(data), len(decompressed))
+			}
+
+			if e := wavError(data, decompressed); e > test.maxError {
+				t.Errorf("%d channels, level %d: error %f, want at most %f", channelCount, test.level,
+					e, test.maxError)
+			}
+		}
+	}
+}
+
+func TestWavCompressFormats(t *testing.T) {
+	for _, format := range [][2]int{{0, WavLevelMedium}, {3, WavLevelMedium}, {1, 1}, {2, 7}} {
+		if _, err := WavCompress(make([]byte, 16), format[0], format[1]); err == nil {
+			t.Errorf("%d channels, level %d: want an error", format[0], format[1])
+		}
+	}
+}
+
+func BenchmarkWavCompress(b *testing.B) {
+	data := wavTestData(2)
+
+	b.SetBytes(int64(len(data)))
+
+	for i := 0; i < b.N; i++ {
+		if _, err := WavCompress(data, 2, WavLevelMedium); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkWavDecompress(b *testing.B) {
+	data := wavTestData(2)
+
+	compressed, err := WavCompress(data, 2, WavLevelMedium)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.SetBytes(int64(len(data)))
+
+	for i := 0; i < b.N; i++ {
+		if _, err := WavDecompress(compressed, 2); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
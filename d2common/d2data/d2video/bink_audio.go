@@ -0,0 +1,375 @@
+package d2video
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+	"math/cmplx"
+)
+
+var errAudioPacket = errors.New("invalid bink audio packet")
+
+const (
+	maxAudioChannels = 2
+	numQuantLevels   = 96
+	audioHeaderBits  = 32
+	floatMantissa    = 23
+	pcmScale         = 32768
+)
+
+// binkAudio decodes the packets of an audio track to interleaved 16 bit samples
+type binkAudio struct {
+	versionB    bool
+	useDCT      bool
+	channels    int // channels decoded separately, RDFT tracks code them interleaved as one
+	outChannels int
+	frameLen    int
+	overlapLen  int
+	root        float64
+	quantTable  [numQuantLevels]float64
+	bands       []int
+	first       bool
+	previous    [maxAudioChannels][]float64
+	coefs       [maxAudioChannels][]float64
+	transform   *audioTransform
+}
+
+func newBinkAudio(track *BinkAudioTrack, versionB bool) *binkAudio {
+	sampleRate := int(track.AudioSampleRateHz)
+
+	channels := 1
+	if track.Stereo {
+		channels = maxAudioChannels
+	}
+
+	a := &binkAudio{
+		versionB:    versionB,
+		useDCT:      track.Algorithm == BinkAudioAlgorithmDCT,
+		channels:    channels,
+		outChannels: channels,
+		first:       true,
+	}
+
+	frameLenBits := 11
+
+	switch {
+	case sampleRate < 22050: //nolint:gomnd // sample rate limits of the frame lengths
+		frameLenBits = 9
+	case sampleRate < 44100: //nolint:gomnd // sample rate limits of the frame lengths
+		frameLenBits = 10
+	}
+
+	// RDFT tracks code the channels interleaved, as one channel at a multiple of the rate
+	if !a.useDCT {
+		sampleRate *= channels
+		a.channels = 1
+
+		if !versionB {
+			frameLenBits += bits.Len(uint(channels)) - 1
+		}
+	}
+
+	a.frameLen = 1 << frameLenBits
+	a.overlapLen = a.frameLen / 16 //nolint:gomnd // overlap of blocks
+
+	if a.useDCT {
+		a.root = float64(a.frameLen) / (math.Sqrt(float64(a.frameLen)) * pcmScale)
+	} else {
+		a.root = 2 / (math.Sqrt(float64(a.frameLen)) * pcmScale)
+	}
+
+	for i := range a.quantTable {
+		// 0.066399999 / log10(e)
+		a.quantTable[i] = float64(float32(math.Exp(float64(float32(i)*0.15289164787221953823)))) * a.root
+	}
+
+	sampleRateHalf := (sampleRate + 1) / 2
+
+	numBands := 1
+	for ; numBands < len(binkAudioCriticalFreqs); numBands++ {
+		if sampleRateHalf <= binkAudioCriticalFreqs[numBands-1] {
+			break
+		}
+	}
+
+	a.bands = make([]int, numBands+1)
+	a.bands[0] = 2
+
+	for i := 1; i < numBands; i++ {
+		a.bands[i] = (binkAudioCriticalFreqs[i-1] * a.frameLen / sampleRateHalf) &^ 1
+	}
+
+	a.bands[numBands] = a.frameLen
+
+	for ch := 0; ch < a.channels; ch++ {
+		a.previous[ch] = make([]float64, a.overlapLen)
+		a.coefs[ch] = make([]float64, a.frameLen)
+	}
+
+	a.transform = newAudioTransform(a.frameLen)
+
+	return a
+}
+
+func (a *binkAudio) reset() {
+	a.first = true
+}
+
+// decodePacket decodes the blocks of a packet
+func (a *binkAudio) decodePacket(data []byte) ([]int16, error) {
+	br := newBitReader(data)
+	br.skipBits(audioHeaderBits) // the number of decoded bytes
+
+	samples := make([]int16, 0)
+
+	for br.bitsLeft() > 0 {
+		if err := a.decodeBlock(br); err != nil {
+			return samples, err
+		}
+
+		samples = a.appendBlock(samples)
+
+		br.align32()
+	}
+
+	return samples, nil
+}
+
+// readFloat reads a float of a 5 bit exponent, 23 bit mantissa and a sign bit
+func readFloat(br *bitReader) float64 {
+	power := br.readBits(5) //nolint:gomnd // exponent bits
+	f := math.Ldexp(float64(br.readBits(floatMantissa)), power-floatMantissa)
+
+	if br.readBit() {
+		f = -f
+	}
+
+	return f
+}
+
+//nolint:gocyclo,funlen // follows the layout of a block
+func (a *binkAudio) decodeBlock(br *bitReader) error {
+	var quant [25]float64
+
+	if a.useDCT {
+		br.skipBits(2) //nolint:gomnd // unused
+	}
+
+	for ch := 0; ch < a.channels; ch++ {
+		coefs := a.coefs[ch]
+
+		if a.versionB {
+			coefs[0] = float64(math.Float32frombits(uint32(br.readBits(32)))) * a.root //nolint:gomnd // float bits
+			coefs[1] = float64(math.Float32frombits(uint32(br.readBits(32)))) * a.root //nolint:gomnd // float bits
+		} else {
+			coefs[0] = readFloat(br) * a.root
+			coefs[1] = readFloat(br) * a.root
+		}
+
+		for i := 0; i < len(a.bands)-1; i++ {
+			value := br.readBits(8) //nolint:gomnd // quantizer bits
+			if value >= numQuantLevels {
+				value = numQuantLevels - 1
+			}
+
+			quant[i] = a.quantTable[value]
+		}
+
+		k := 0
+		q := quant[0]
+
+		for i := 2; i < a.frameLen; {
+			j := i + 16 //nolint:gomnd // fixed run of revision b
+
+			if !a.versionB {
+				j = i + 8 //nolint:gomnd // a run is at least 8 coefficients
+				if br.readBit() {
+					j = i + binkAudioRunLengths[br.readBits(nibbleBits)]*8 //nolint:gomnd // runs of 8
+				}
+			}
+
+			if j > a.frameLen {
+				j = a.frameLen
+			}
+
+			width := br.readBits(nibbleBits)
+
+			if width == 0 {
+				for ; i < j; i++ {
+					coefs[i] = 0
+				}
+
+				for a.bands[k] < i {
+					q = quant[k]
+					k++
+				}
+
+				continue
+			}
+
+			for ; i < j; i++ {
+				if a.bands[k] == i {
+					q = quant[k]
+					k++
+				}
+
+				coef := br.readBits(width)
+
+				switch {
+				case coef == 0:
+					coefs[i] = 0
+				case br.readBit():
+					coefs[i] = -q * float64(coef)
+				default:
+					coefs[i] = q * float64(coef)
+				}
+			}
+		}
+
+		if err := br.err(); err != nil {
+			return errAudioPacket
+		}
+
+		if a.useDCT {
+			coefs[0] *= 2
+			a.transform.dctIII(coefs)
+		} else {
+			a.transform.inverseRDFT(coefs)
+		}
+	}
+
+	// blocks overlap, the start of a block fades in from the end of the previous one
+	for ch := 0; ch < a.channels; ch++ {
+		out := a.coefs[ch]
+
+		if !a.first {
+			count := float64(a.overlapLen * a.channels)
+
+			for i, j := 0, ch; i < a.overlapLen; i, j = i+1, j+a.channels {
+				out[i] = (a.previous[ch][i]*(count-float64(j)) + out[i]*float64(j)) / count
+			}
+		}
+
+		copy(a.previous[ch], out[a.frameLen-a.overlapLen:])
+	}
+
+	a.first = false
+
+	return nil
+}
+
+// appendBlock appends the samples of the last decoded block, interleaved
+func (a *binkAudio) appendBlock(samples []int16) []int16 {
+	n := a.frameLen - a.overlapLen
+
+	for i := 0; i < n; i++ {
+		for ch := 0; ch < a.channels; ch++ {
+			samples = append(samples, toPCM(a.coefs[ch][i]))
+		}
+	}
+
+	return samples
+}
+
+func toPCM(v float64) int16 {
+	v = math.Round(v * pcmScale)
+
+	switch {
+	case v > math.MaxInt16:
+		return math.MaxInt16
+	case v < math.MinInt16:
+		return math.MinInt16
+	}
+
+	return int16(v)
+}
+
+// audioTransform does the inverse transforms of bink audio with a complex FFT
+type audioTransform struct {
+	n       int
+	buf     []complex128
+	twiddle []complex128
+}
+
+func newAudioTransform(n int) *audioTransform {
+	// the DCT uses an FFT twice the size of a block
+	size := n * 2
+	t := &audioTransform{n: n, buf: make([]complex128, size), twiddle: make([]complex128, size/2)}
+
+	for i := range t.twiddle {
+		t.twiddle[i] = cmplx.Rect(1, 2*math.Pi*float64(i)/float64(size))
+	}
+
+	return t
+}
+
+// inverseFFT computes sum(x[k] * e^(2 pi i k n / N)) in place, N being a power of 2 up to the
+// size of the transform
+func (t *audioTransform) inverseFFT(x []complex128) {
+	n := len(x)
+	shift := bits.LeadingZeros(uint(n)) + 1
+
+	for i := range x {
+		if j := int(bits.Reverse(uint(i)) >> uint(shift)); j > i {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+
+	step := len(t.twiddle) * 2 / n
+
+	for size := 2; size <= n; size <<= 1 {
+		half := size >> 1
+		stride := step * (n / size)
+
+		for start := 0; start < n; start += size {
+			for k := 0; k < half; k++ {
+				w := t.twiddle[k*stride] * x[start+k+half]
+				x[start+k+half] = x[start+k] - w
+				x[start+k] += w
+			}
+		}
+	}
+}
+
+// inverseRDFT transforms coefficients packed as the DC, the Nyquist frequency and the pairs of
+// the frequencies between to samples, x[n] = X0/2 + XN/2 (-1)^n + sum(Re Xk cos + Im Xk sin)
+func (t *audioTransform) inverseRDFT(data []float64) {
+	n := t.n
+	x := t.buf[:n]
+
+	x[0] = complex(data[0], 0)
+	x[n/2] = complex(data[1], 0)
+
+	for k := 1; k < n/2; k++ {
+		x[k] = complex(data[2*k], -data[2*k+1])
+		x[n-k] = complex(data[2*k], data[2*k+1])
+	}
+
+	t.inverseFFT(x)
+
+	for i := range data {
+		data[i] = real(x[i]) / 2 //nolint:gomnd // half the sum of the full spectrum
+	}
+}
+
+// dctIII transforms data in place, y[k] = 2/N (x0/2 + sum(x[n] cos(pi n (k + 1/2) / N)))
+func (t *audioTransform) dctIII(data []float64) {
+	n := t.n
+	x := t.buf[:2*n]
+
+	for i := range x {
+		x[i] = 0
+	}
+
+	x[0] = complex(data[0]/2, 0)
+
+	for i := 1; i < n; i++ {
+		x[i] = complex(data[i], 0) * cmplx.Rect(1, math.Pi*float64(i)/float64(2*n))
+	}
+
+	t.inverseFFT(x)
+
+	for k := range data {
+		data[k] = real(x[k]) * 2 / float64(n)
+	}
+}
@@ -0,0 +1,311 @@
+package d2video
+
+// coefficient list modes of DCT and residue blocks
+const (
+	modeGroup    = iota // 4 coefficients from ccoef on, then the 3 groups after them
+	modeSubGroup        // the 3 groups of 4 coefficients after ccoef
+	modeQuad            // 4 coefficients from ccoef on
+	modeSingle          // the coefficient ccoef
+)
+
+const (
+	coefListSize  = 128
+	coefListStart = 64
+)
+
+// coefList is a list of coefficients yet to be coded, entries are added at both ends
+type coefList struct {
+	coefs [coefListSize]int
+	modes [coefListSize]int
+	start int
+	end   int
+}
+
+func newCoefList() *coefList {
+	return &coefList{start: coefListStart, end: coefListStart}
+}
+
+func (l *coefList) append(coef, mode int) {
+	l.coefs[l.end] = coef
+	l.modes[l.end] = mode
+	l.end++
+}
+
+func (l *coefList) prepend(coef, mode int) {
+	l.start--
+	l.coefs[l.start] = coef
+	l.modes[l.start] = mode
+}
+
+// readCoef reads a coefficient of at least 1 << bits
+func readCoef(br *bitReader, bitCount int) int32 {
+	if bitCount == 0 {
+		if br.readBit() {
+			return -1
+		}
+
+		return 1
+	}
+
+	return int32(br.readSigned(br.readBits(bitCount) | 1<<bitCount))
+}
+
+// readDCTCoefs reads the AC coefficients of a DCT block from the highest bit down and returns the
+// scan positions of the coded coefficients. The quantizer is read after them when q is negative.
+//
+//nolint:gocyclo // the coefficient list is a state machine
+func readDCTCoefs(br *bitReader, block *[blockPixels]int32, q int) (coefs []int, quantIdx int, err error) {
+	coefs = make([]int, 0, blockPixels)
+	list := newCoefList()
+
+	list.append(4, modeGroup)  //nolint:gomnd // first group
+	list.append(24, modeGroup) //nolint:gomnd // second group
+	list.append(44, modeGroup) //nolint:gomnd // third group
+	list.append(1, modeSingle)
+	list.append(2, modeSingle) //nolint:gomnd // coefficient 2
+	list.append(3, modeSingle) //nolint:gomnd // coefficient 3
+
+	code := func(ccoef, bitCount int) {
+		block[binkScan[ccoef]] = readCoef(br, bitCount)
+		coefs = append(coefs, ccoef)
+	}
+
+	for bitCount := br.readBits(nibbleBits) - 1; bitCount >= 0; bitCount-- {
+		for pos := list.start; pos < list.end; {
+			if list.coefs[pos]|list.modes[pos] == 0 || !br.readBit() {
+				pos++
+				continue
+			}
+
+			ccoef, mode := list.coefs[pos], list.modes[pos]
+
+			switch mode {
+			case modeGroup, modeQuad:
+				if mode == modeGroup {
+					list.coefs[pos] = ccoef + 4 //nolint:gomnd // the groups after this one
+					list.modes[pos] = modeSubGroup
+				} else {
+					list.coefs[pos] = 0
+					list.modes[pos] = 0
+					pos++
+				}
+
+				for i := 0; i < 4; i, ccoef = i+1, ccoef+1 {
+					if br.readBit() {
+						list.prepend(ccoef, modeSingle)
+					} else {
+						code(ccoef, bitCount)
+					}
+				}
+			case modeSubGroup:
+				list.modes[pos] = modeQuad
+
+				for i := 0; i < 3; i++ {
+					ccoef += 4 //nolint:gomnd // group size
+					list.append(ccoef, modeQuad)
+				}
+			case modeSingle:
+				code(ccoef, bitCount)
+				list.coefs[pos] = 0
+				list.modes[pos] = 0
+				pos++
+			}
+		}
+	}
+
+	if q < 0 {
+		quantIdx = br.readBits(nibbleBits)
+	} else if quantIdx = q; quantIdx > 15 { //nolint:gomnd // number of quantizers
+		return nil, 0, errInvalidBlock
+	}
+
+	return coefs, quantIdx, br.err()
+}
+
+// readResidue reads the differences of a residue block, coded in bit planes. masks is the number
+// of bits set before the block ends.
+//
+//nolint:gocyclo,funlen // the coefficient list is a state machine
+func readResidue(br *bitReader, masks int) *[blockPixels]int32 {
+	var block [blockPixels]int32
+
+	nonZero := make([]int, 0, blockPixels)
+	list := newCoefList()
+
+	list.append(4, modeGroup)  //nolint:gomnd // first group
+	list.append(24, modeGroup) //nolint:gomnd // second group
+	list.append(44, modeGroup) //nolint:gomnd // third group
+	list.append(0, modeQuad)
+
+	// code sets a new coefficient to mask, false when the block has ended
+	code := func(ccoef int, mask int32) bool {
+		idx := binkScan[ccoef]
+		nonZero = append(nonZero, idx)
+
+		if br.readBit() {
+			block[idx] = -mask
+		} else {
+			block[idx] = mask
+		}
+
+		masks--
+
+		return masks >= 0
+	}
+
+	for mask := int32(1) << br.readBits(3); mask != 0; mask >>= 1 {
+		for _, idx := range nonZero {
+			if !br.readBit() {
+				continue
+			}
+
+			if block[idx] < 0 {
+				block[idx] -= mask
+			} else {
+				block[idx] += mask
+			}
+
+			masks--
+			if masks < 0 {
+				return &block
+			}
+		}
+
+		for pos := list.start; pos < list.end; {
+			if list.coefs[pos]|list.modes[pos] == 0 || !br.readBit() {
+				pos++
+				continue
+			}
+
+			ccoef, mode := list.coefs[pos], list.modes[pos]
+
+			switch mode {
+			case modeGroup, modeQuad:
+				if mode == modeGroup {
+					list.coefs[pos] = ccoef + 4 //nolint:gomnd // the groups after this one
+					list.modes[pos] = modeSubGroup
+				} else {
+					list.coefs[pos] = 0
+					list.modes[pos] = 0
+					pos++
+				}
+
+				for i := 0; i < 4; i, ccoef = i+1, ccoef+1 {
+					if br.readBit() {
+						list.prepend(ccoef, modeSingle)
+					} else if !code(ccoef, mask) {
+						return &block
+					}
+				}
+			case modeSubGroup:
+				list.modes[pos] = modeQuad
+
+				for i := 0; i < 3; i++ {
+					ccoef += 4 //nolint:gomnd // group size
+					list.append(ccoef, modeQuad)
+				}
+			case modeSingle:
+				list.coefs[pos] = 0
+				list.modes[pos] = 0
+				pos++
+
+				if !code(ccoef, mask) {
+					return &block
+				}
+			}
+		}
+	}
+
+	return &block
+}
+
+// unquantize scales the coded coefficients, with the 32 bit overflow of the reference decoder
+func unquantize(block *[blockPixels]int32, quant *[64]uint32, coefs []int) {
+	block[0] = int32(uint32(block[0])*quant[0]) >> quantShift
+
+	for _, ccoef := range coefs {
+		idx := binkScan[ccoef]
+		block[idx] = int32(uint32(block[idx])*quant[ccoef]) >> quantShift
+	}
+}
+
+// IDCT constants, as fixed point numbers with 11 fraction bits
+const (
+	idctA1 = 2896 // cos(pi/4)
+	idctA2 = 2217
+	idctA3 = 3784
+	idctA4 = -5352
+)
+
+// idct8 transforms 8 values at the given offset and step
+func idct8(dst []int32, src []int32, offset, step int, munge func(int32) int32) {
+	s := func(i int) int32 { return src[offset+i*step] }
+
+	a0 := s(0) + s(4)
+	a1 := s(0) - s(4)
+	a2 := s(2) + s(6)
+	a3 := (idctA1 * (s(2) - s(6))) >> 11
+	a4 := s(5) + s(3)
+	a5 := s(5) - s(3)
+	a6 := s(1) + s(7)
+	a7 := s(1) - s(7)
+	b0 := a4 + a6
+	b1 := (idctA3 * (a5 + a7)) >> 11
+	b2 := ((idctA4 * a5) >> 11) - b0 + b1
+	b3 := ((idctA1 * (a6 - a4)) >> 11) - b2
+	b4 := ((idctA2 * a7) >> 11) + b3 - b1
+
+	d := func(i int, v int32) { dst[offset+i*step] = munge(v) }
+
+	d(0, a0+a2+b0)
+	d(1, a1+a3-a2+b2)
+	d(2, a1-a3+a2+b3)
+	d(3, a0-a2-b4)
+	d(4, a0-a2+b4)
+	d(5, a1-a3+a2-b3)
+	d(6, a1+a3-a2-b2)
+	d(7, a0+a2-b0)
+}
+
+func mungeNone(v int32) int32 {
+	return v
+}
+
+func mungeRow(v int32) int32 {
+	return (v + 0x7f) >> 8 //nolint:gomnd // round off 8 fraction bits
+}
+
+// idct transforms a block in place
+func idct(block *[blockPixels]int32) {
+	var tmp [blockPixels]int32
+
+	for col := 0; col < blockSize; col++ {
+		idctColumn(tmp[:], block[:], col)
+	}
+
+	for row := 0; row < blockSize; row++ {
+		idct8(block[:], tmp[:], row*blockSize, 1, mungeRow)
+	}
+}
+
+func idctColumn(dst, src []int32, col int) {
+	for i := 1; i < blockSize; i++ {
+		if src[col+i*blockSize] != 0 {
+			idct8(dst, src, col, blockSize, mungeNone)
+			return
+		}
+	}
+
+	for i := 0; i < blockSize; i++ {
+		dst[col+i*blockSize] = src[col]
+	}
+}
+
+// idctPut transforms a block and writes it, the pixels wrap around like in the reference decoder
+func idctPut(w blockWriter, block *[blockPixels]int32) {
+	idct(block)
+
+	for pos, v := range block {
+		w.set(pos, int(v))
+	}
+}
@@ -0,0 +1,44 @@
+package d2video
+
+import "image/color"
+
+const rgbaBytes = 4
+
+// BinkFrame is a decoded frame of a bink video
+type BinkFrame struct {
+	Index    int
+	KeyFrame bool
+	Width    int
+	Height   int
+
+	// Planes are the Y, Cb, Cr and alpha planes, the chroma planes have half the size of the
+	// frame. Alpha is nil for videos without an alpha plane.
+	Planes  [numPlanes][]byte
+	Strides [numPlanes]int
+
+	// Audio holds the interleaved samples of each audio track decoded with the frame
+	Audio [][]int16
+}
+
+// RGBA converts the frame to RGBA pixels, as used by Surface.ReplacePixels
+func (f *BinkFrame) RGBA() []byte {
+	pixels := make([]byte, f.Width*f.Height*rgbaBytes)
+
+	for y := 0; y < f.Height; y++ {
+		for x := 0; x < f.Width; x++ {
+			luma := f.Planes[0][y*f.Strides[0]+x]
+			chroma := (y>>1)*f.Strides[1] + x>>1
+			r, g, b := color.YCbCrToRGB(luma, f.Planes[1][chroma], f.Planes[2][chroma])
+
+			alpha := byte(0xff)
+			if f.Planes[alphaPlane] != nil {
+				alpha = f.Planes[alphaPlane][y*f.Strides[alphaPlane]+x]
+			}
+
+			idx := (y*f.Width + x) * rgbaBytes
+			pixels[idx], pixels[idx+1], pixels[idx+2], pixels[idx+3] = r, g, b, alpha
+		}
+	}
+
+	return pixels
+}
@@ -0,0 +1,281 @@
+package d2video
+
+// tables of the bink video and audio codecs
+
+// binkScan is the order in which DCT coefficients are coded
+var binkScan = [64]int{
+	0, 1, 8, 9, 2, 3, 10, 11,
+	4, 5, 12, 13, 6, 7, 14, 15,
+	20, 21, 28, 29, 22, 23, 30, 31,
+	16, 17, 24, 25, 32, 33, 40, 41,
+	34, 35, 42, 43, 48, 49, 56, 57,
+	50, 51, 58, 59, 18, 19, 26, 27,
+	36, 37, 44, 45, 38, 39, 46, 47,
+	52, 53, 60, 61, 54, 55, 62, 63,
+}
+
+// binkPatterns are the pixel orders of run blocks
+var binkPatterns = [16][64]int{
+	{
+		0x00, 0x08, 0x10, 0x18, 0x20, 0x28, 0x30, 0x38,
+		0x39, 0x31, 0x29, 0x21, 0x19, 0x11, 0x09, 0x01,
+		0x02, 0x0A, 0x12, 0x1A, 0x22, 0x2A, 0x32, 0x3A,
+		0x3B, 0x33, 0x2B, 0x23, 0x1B, 0x13, 0x0B, 0x03,
+		0x04, 0x0C, 0x14, 0x1C, 0x24, 0x2C, 0x34, 0x3C,
+		0x3D, 0x35, 0x2D, 0x25, 0x1D, 0x15, 0x0D, 0x05,
+		0x06, 0x0E, 0x16, 0x1E, 0x26, 0x2E, 0x36, 0x3E,
+		0x3F, 0x37, 0x2F, 0x27, 0x1F, 0x17, 0x0F, 0x07,
+	},
+	{
+		0x3B, 0x3A, 0x39, 0x38, 0x30, 0x31, 0x32, 0x33,
+		0x2B, 0x2A, 0x29, 0x28, 0x20, 0x21, 0x22, 0x23,
+		0x1B, 0x1A, 0x19, 0x18, 0x10, 0x11, 0x12, 0x13,
+		0x0B, 0x0A, 0x09, 0x08, 0x00, 0x01, 0x02, 0x03,
+		0x04, 0x05, 0x06, 0x07, 0x0F, 0x0E, 0x0D, 0x0C,
+		0x14, 0x15, 0x16, 0x17, 0x1F, 0x1E, 0x1D, 0x1C,
+		0x24, 0x25, 0x26, 0x27, 0x2F, 0x2E, 0x2D, 0x2C,
+		0x34, 0x35, 0x36, 0x37, 0x3F, 0x3E, 0x3D, 0x3C,
+	},
+	{
+		0x19, 0x11, 0x12, 0x1A, 0x1B, 0x13, 0x0B, 0x03,
+		0x02, 0x0A, 0x09, 0x01, 0x00, 0x08, 0x10, 0x18,
+		0x20, 0x28, 0x30, 0x38, 0x39, 0x31, 0x29, 0x2A,
+		0x32, 0x3A, 0x3B, 0x33, 0x2B, 0x23, 0x22, 0x21,
+		0x1D, 0x15, 0x16, 0x1E, 0x1F, 0x17, 0x0F, 0x07,
+		0x06, 0x0E, 0x0D, 0x05, 0x04, 0x0C, 0x14, 0x1C,
+		0x24, 0x2C, 0x34, 0x3C, 0x3D, 0x35, 0x2D, 0x2E,
+		0x36, 0x3E, 0x3F, 0x37, 0x2F, 0x27, 0x26, 0x25,
+	},
+	{
+		0x03, 0x0B, 0x02, 0x0A, 0x01, 0x09, 0x00, 0x08,
+		0x10, 0x18, 0x11, 0x19, 0x12, 0x1A, 0x13, 0x1B,
+		0x23, 0x2B, 0x22, 0x2A, 0x21, 0x29, 0x20, 0x28,
+		0x30, 0x38, 0x31, 0x39, 0x32, 0x3A, 0x33, 0x3B,
+		0x3C, 0x34, 0x3D, 0x35, 0x3E, 0x36, 0x3F, 0x37,
+		0x2F, 0x27, 0x2E, 0x26, 0x2D, 0x25, 0x2C, 0x24,
+		0x1C, 0x14, 0x1D, 0x15, 0x1E, 0x16, 0x1F, 0x17,
+		0x0F, 0x07, 0x0E, 0x06, 0x0D, 0x05, 0x0C, 0x04,
+	},
+	{
+		0x18, 0x19, 0x10, 0x11, 0x08, 0x09, 0x00, 0x01,
+		0x02, 0x03, 0x0A, 0x0B, 0x12, 0x13, 0x1A, 0x1B,
+		0x1C, 0x1D, 0x14, 0x15, 0x0C, 0x0D, 0x04, 0x05,
+		0x06, 0x07, 0x0E, 0x0F, 0x16, 0x17, 0x1E, 0x1F,
+		0x27, 0x26, 0x2F, 0x2E, 0x37, 0x36, 0x3F, 0x3E,
+		0x3D, 0x3C, 0x35, 0x34, 0x2D, 0x2C, 0x25, 0x24,
+		0x23, 0x22, 0x2B, 0x2A, 0x33, 0x32, 0x3B, 0x3A,
+		0x39, 0x38, 0x31, 0x30, 0x29, 0x28, 0x21, 0x20,
+	},
+	{
+		0x00, 0x01, 0x02, 0x03, 0x08, 0x09, 0x0A, 0x0B,
+		0x10, 0x11, 0x12, 0x13, 0x18, 0x19, 0x1A, 0x1B,
+		0x20, 0x21, 0x22, 0x23, 0x28, 0x29, 0x2A, 0x2B,
+		0x30, 0x31, 0x32, 0x33, 0x38, 0x39, 0x3A, 0x3B,
+		0x04, 0x05, 0x06, 0x07, 0x0C, 0x0D, 0x0E, 0x0F,
+		0x14, 0x15, 0x16, 0x17, 0x1C, 0x1D, 0x1E, 0x1F,
+		0x24, 0x25, 0x26, 0x27, 0x2C, 0x2D, 0x2E, 0x2F,
+		0x34, 0x35, 0x36, 0x37, 0x3C, 0x3D, 0x3E, 0x3F,
+	},
+	{
+		0x06, 0x07, 0x0F, 0x0E, 0x0D, 0x05, 0x0C, 0x04,
+		0x03, 0x0B, 0x02, 0x0A, 0x09, 0x01, 0x00, 0x08,
+		0x10, 0x18, 0x11, 0x19, 0x12, 0x1A, 0x13, 0x1B,
+		0x14, 0x1C, 0x15, 0x1D, 0x16, 0x1E, 0x17, 0x1F,
+		0x27, 0x2F, 0x26, 0x2E, 0x25, 0x2D, 0x24, 0x2C,
+		0x23, 0x2B, 0x22, 0x2A, 0x21, 0x29, 0x20, 0x28,
+		0x31, 0x30, 0x38, 0x39, 0x3A, 0x32, 0x3B, 0x33,
+		0x3C, 0x34, 0x3D, 0x35, 0x3E, 0x36, 0x3F, 0x37,
+	},
+	{
+		0x00, 0x08, 0x09, 0x01, 0x02, 0x03, 0x0B, 0x0A,
+		0x12, 0x13, 0x1B, 0x1A, 0x19, 0x11, 0x10, 0x18,
+		0x20, 0x28, 0x29, 0x21, 0x22, 0x23, 0x2B, 0x2A,
+		0x32, 0x31, 0x30, 0x38, 0x39, 0x3A, 0x3B, 0x33,
+		0x34, 0x3C, 0x3D, 0x3E, 0x3F, 0x37, 0x36, 0x35,
+		0x2D, 0x2C, 0x24, 0x25, 0x26, 0x2E, 0x2F, 0x27,
+		0x1F, 0x17, 0x16, 0x1E, 0x1D, 0x1C, 0x14, 0x15,
+		0x0D, 0x0C, 0x04, 0x05, 0x06, 0x0E, 0x0F, 0x07,
+	},
+	{
+		0x00, 0x08, 0x10, 0x18, 0x19, 0x1A, 0x1B, 0x13,
+		0x0B, 0x03, 0x02, 0x01, 0x09, 0x11, 0x12, 0x0A,
+		0x04, 0x0C, 0x14, 0x1C, 0x1D, 0x1E, 0x1F, 0x17,
+		0x0F, 0x07, 0x06, 0x05, 0x0D, 0x15, 0x16, 0x0E,
+		0x24, 0x2C, 0x34, 0x3C, 0x3D, 0x3E, 0x3F, 0x37,
+		0x2F, 0x27, 0x26, 0x25, 0x2D, 0x35, 0x36, 0x2E,
+		0x20, 0x28, 0x30, 0x38, 0x39, 0x3A, 0x3B, 0x33,
+		0x2B, 0x23, 0x22, 0x21, 0x29, 0x31, 0x32, 0x2A,
+	},
+	{
+		0x00, 0x08, 0x10, 0x18, 0x20, 0x28, 0x30, 0x38,
+		0x39, 0x3A, 0x3B, 0x3C, 0x3D, 0x3E, 0x3F, 0x37,
+		0x2F, 0x27, 0x1F, 0x17, 0x0F, 0x07, 0x06, 0x05,
+		0x04, 0x03, 0x02, 0x01, 0x09, 0x11, 0x19, 0x21,
+		0x29, 0x31, 0x32, 0x33, 0x34, 0x35, 0x36, 0x2E,
+		0x26, 0x1E, 0x16, 0x0E, 0x0D, 0x0C, 0x0B, 0x0A,
+		0x12, 0x1A, 0x22, 0x2A, 0x2B, 0x2C, 0x2D, 0x25,
+		0x1D, 0x15, 0x14, 0x13, 0x1B, 0x23, 0x24, 0x1C,
+	},
+	{
+		0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07,
+		0x0F, 0x0E, 0x0D, 0x0C, 0x0B, 0x0A, 0x09, 0x08,
+		0x10, 0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17,
+		0x1F, 0x1E, 0x1D, 0x1C, 0x1B, 0x1A, 0x19, 0x18,
+		0x20, 0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x27,
+		0x2F, 0x2E, 0x2D, 0x2C, 0x2B, 0x2A, 0x29, 0x28,
+		0x30, 0x31, 0x32, 0x33, 0x34, 0x35, 0x36, 0x37,
+		0x3F, 0x3E, 0x3D, 0x3C, 0x3B, 0x3A, 0x39, 0x38,
+	},
+	{
+		0x00, 0x08, 0x01, 0x09, 0x02, 0x0A, 0x03, 0x0B,
+		0x04, 0x0C, 0x05, 0x0D, 0x06, 0x0E, 0x07, 0x0F,
+		0x17, 0x1F, 0x16, 0x1E, 0x15, 0x1D, 0x14, 0x1C,
+		0x13, 0x1B, 0x12, 0x1A, 0x11, 0x19, 0x10, 0x18,
+		0x20, 0x28, 0x21, 0x29, 0x22, 0x2A, 0x23, 0x2B,
+		0x24, 0x2C, 0x25, 0x2D, 0x26, 0x2E, 0x27, 0x2F,
+		0x37, 0x3F, 0x36, 0x3E, 0x35, 0x3D, 0x34, 0x3C,
+		0x33, 0x3B, 0x32, 0x3A, 0x31, 0x39, 0x30, 0x38,
+	},
+	{
+		0x00, 0x01, 0x08, 0x09, 0x10, 0x11, 0x18, 0x19,
+		0x20, 0x21, 0x28, 0x29, 0x30, 0x31, 0x38, 0x39,
+		0x3A, 0x3B, 0x32, 0x33, 0x2A, 0x2B, 0x22, 0x23,
+		0x1A, 0x1B, 0x12, 0x13, 0x0A, 0x0B, 0x02, 0x03,
+		0x04, 0x05, 0x0C, 0x0D, 0x14, 0x15, 0x1C, 0x1D,
+		0x24, 0x25, 0x2C, 0x2D, 0x34, 0x35, 0x3C, 0x3D,
+		0x3E, 0x3F, 0x36, 0x37, 0x2E, 0x2F, 0x26, 0x27,
+		0x1E, 0x1F, 0x16, 0x17, 0x0E, 0x0F, 0x06, 0x07,
+	},
+	{
+		0x00, 0x08, 0x01, 0x02, 0x09, 0x10, 0x18, 0x11,
+		0x0A, 0x03, 0x04, 0x0B, 0x12, 0x19, 0x20, 0x28,
+		0x21, 0x1A, 0x13, 0x0C, 0x05, 0x06, 0x0D, 0x14,
+		0x1B, 0x22, 0x29, 0x30, 0x38, 0x31, 0x2A, 0x23,
+		0x1C, 0x15, 0x0E, 0x07, 0x0F, 0x16, 0x1D, 0x24,
+		0x2B, 0x32, 0x39, 0x3A, 0x33, 0x2C, 0x25, 0x1E,
+		0x17, 0x1F, 0x26, 0x2D, 0x34, 0x3B, 0x3C, 0x35,
+		0x2E, 0x27, 0x2F, 0x36, 0x3D, 0x3E, 0x37, 0x3F,
+	},
+	{
+		0x18, 0x10, 0x08, 0x00, 0x01, 0x02, 0x03, 0x0B,
+		0x13, 0x1B, 0x1A, 0x19, 0x11, 0x0A, 0x09, 0x12,
+		0x1C, 0x14, 0x0C, 0x04, 0x05, 0x06, 0x07, 0x0F,
+		0x17, 0x1F, 0x1E, 0x1D, 0x15, 0x0E, 0x0D, 0x16,
+		0x3C, 0x34, 0x2C, 0x24, 0x25, 0x26, 0x27, 0x2F,
+		0x37, 0x3F, 0x3E, 0x3D, 0x35, 0x2E, 0x2D, 0x36,
+		0x38, 0x30, 0x28, 0x20, 0x21, 0x22, 0x23, 0x2B,
+		0x33, 0x3B, 0x3A, 0x39, 0x31, 0x2A, 0x29, 0x32,
+	},
+	{
+		0x00, 0x08, 0x09, 0x01, 0x02, 0x03, 0x0B, 0x0A,
+		0x12, 0x13, 0x1B, 0x1A, 0x19, 0x11, 0x10, 0x18,
+		0x20, 0x28, 0x30, 0x38, 0x39, 0x31, 0x29, 0x21,
+		0x22, 0x2A, 0x32, 0x3A, 0x3B, 0x33, 0x2B, 0x23,
+		0x24, 0x2C, 0x34, 0x3C, 0x3D, 0x35, 0x2D, 0x25,
+		0x26, 0x2E, 0x36, 0x3E, 0x3F, 0x37, 0x2F, 0x27,
+		0x1F, 0x17, 0x16, 0x1E, 0x1D, 0x1C, 0x14, 0x15,
+		0x0D, 0x0C, 0x04, 0x05, 0x06, 0x0E, 0x0F, 0x07,
+	},
+}
+
+// binkTreeCodes are the codes of the 16 huffman trees bundle values are coded with. The bits of
+// a code are read lowest first.
+var binkTreeCodes = [16][16]uint8{
+	{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E, 0x0F},
+	{0x00, 0x01, 0x03, 0x05, 0x07, 0x09, 0x0B, 0x0D, 0x0F, 0x13, 0x15, 0x17, 0x19, 0x1B, 0x1D, 0x1F},
+	{0x00, 0x02, 0x01, 0x09, 0x05, 0x15, 0x0D, 0x1D, 0x03, 0x13, 0x0B, 0x1B, 0x07, 0x17, 0x0F, 0x1F},
+	{0x00, 0x02, 0x06, 0x01, 0x09, 0x05, 0x0D, 0x1D, 0x03, 0x13, 0x0B, 0x1B, 0x07, 0x17, 0x0F, 0x1F},
+	{0x00, 0x04, 0x02, 0x06, 0x01, 0x09, 0x05, 0x0D, 0x03, 0x13, 0x0B, 0x1B, 0x07, 0x17, 0x0F, 0x1F},
+	{0x00, 0x04, 0x02, 0x0A, 0x06, 0x0E, 0x01, 0x09, 0x05, 0x0D, 0x03, 0x0B, 0x07, 0x17, 0x0F, 0x1F},
+	{0x00, 0x02, 0x0A, 0x06, 0x0E, 0x01, 0x09, 0x05, 0x0D, 0x03, 0x0B, 0x1B, 0x07, 0x17, 0x0F, 0x1F},
+	{0x00, 0x01, 0x05, 0x03, 0x13, 0x0B, 0x1B, 0x3B, 0x07, 0x27, 0x17, 0x37, 0x0F, 0x2F, 0x1F, 0x3F},
+	{0x00, 0x01, 0x03, 0x13, 0x0B, 0x2B, 0x1B, 0x3B, 0x07, 0x27, 0x17, 0x37, 0x0F, 0x2F, 0x1F, 0x3F},
+	{0x00, 0x01, 0x05, 0x0D, 0x03, 0x13, 0x0B, 0x1B, 0x07, 0x27, 0x17, 0x37, 0x0F, 0x2F, 0x1F, 0x3F},
+	{0x00, 0x02, 0x01, 0x05, 0x03, 0x13, 0x0B, 0x1B, 0x07, 0x27, 0x17, 0x37, 0x0F, 0x2F, 0x1F, 0x3F},
+	{0x00, 0x01, 0x09, 0x05, 0x0D, 0x03, 0x13, 0x0B, 0x1B, 0x07, 0x17, 0x37, 0x0F, 0x2F, 0x1F, 0x3F},
+	{0x00, 0x02, 0x01, 0x03, 0x13, 0x0B, 0x1B, 0x3B, 0x07, 0x27, 0x17, 0x37, 0x0F, 0x2F, 0x1F, 0x3F},
+	{0x00, 0x01, 0x05, 0x03, 0x07, 0x27, 0x17, 0x37, 0x0F, 0x4F, 0x2F, 0x6F, 0x1F, 0x5F, 0x3F, 0x7F},
+	{0x00, 0x01, 0x05, 0x03, 0x07, 0x17, 0x37, 0x77, 0x0F, 0x4F, 0x2F, 0x6F, 0x1F, 0x5F, 0x3F, 0x7F},
+	{0x00, 0x02, 0x01, 0x05, 0x03, 0x07, 0x27, 0x17, 0x37, 0x0F, 0x2F, 0x6F, 0x1F, 0x5F, 0x3F, 0x7F},
+}
+
+// binkTreeLengths are the lengths of the codes in binkTreeCodes
+var binkTreeLengths = [16][16]uint8{
+	{4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4},
+	{1, 4, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5},
+	{2, 2, 4, 4, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5},
+	{2, 3, 3, 4, 4, 4, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5},
+	{3, 3, 3, 3, 4, 4, 4, 4, 5, 5, 5, 5, 5, 5, 5, 5},
+	{3, 3, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 5, 5, 5, 5},
+	{2, 4, 4, 4, 4, 4, 4, 4, 4, 4, 5, 5, 5, 5, 5, 5},
+	{1, 3, 3, 5, 5, 5, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6},
+	{1, 2, 5, 5, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6},
+	{1, 3, 4, 4, 5, 5, 5, 5, 6, 6, 6, 6, 6, 6, 6, 6},
+	{2, 2, 3, 3, 5, 5, 5, 5, 6, 6, 6, 6, 6, 6, 6, 6},
+	{1, 4, 4, 4, 4, 5, 5, 5, 5, 5, 6, 6, 6, 6, 6, 6},
+	{2, 2, 2, 5, 5, 5, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6},
+	{1, 3, 3, 3, 6, 6, 6, 6, 7, 7, 7, 7, 7, 7, 7, 7},
+	{1, 3, 3, 3, 5, 6, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7},
+	{2, 2, 3, 3, 3, 6, 6, 6, 6, 6, 7, 7, 7, 7, 7, 7},
+}
+
+// binkRunLengths are the runs of repeated block types
+var binkRunLengths = [4]int{4, 8, 12, 32}
+
+// binkIntraSeed and binkInterSeed are the quantizer matrices the quantizer tables are built
+// from
+var binkIntraSeed = [64]int64{
+	16, 16, 16, 19, 16, 19, 22, 22,
+	22, 22, 26, 24, 26, 22, 22, 27,
+	27, 27, 26, 26, 26, 29, 29, 29,
+	27, 27, 27, 26, 34, 34, 34, 29,
+	29, 29, 27, 27, 37, 34, 34, 32,
+	32, 29, 29, 38, 37, 35, 35, 34,
+	35, 40, 40, 40, 38, 38, 48, 48,
+	46, 46, 58, 56, 56, 69, 69, 83,
+}
+
+var binkInterSeed = [64]int64{
+	16, 17, 17, 18, 18, 18, 19, 19,
+	19, 19, 20, 20, 20, 20, 20, 21,
+	21, 21, 21, 21, 21, 22, 22, 22,
+	22, 22, 22, 22, 23, 23, 23, 23,
+	23, 23, 23, 23, 24, 24, 24, 25,
+	24, 24, 24, 25, 26, 26, 26, 26,
+	25, 27, 27, 27, 27, 27, 28, 28,
+	28, 28, 30, 30, 30, 31, 31, 33,
+}
+
+// binkDCTScale are the scale factors of the IDCT, as fixed point numbers with 30 fraction bits
+var binkDCTScale = [64]int64{
+	1073741824, 1489322693, 1402911301, 1262586814,
+	1073741824, 843633538, 581104888, 296244703,
+	1489322693, 2065749918, 1945893874, 1751258219,
+	1489322693, 1170153332, 806015634, 410903207,
+	1402911301, 1945893874, 1832991949, 1649649171,
+	1402911301, 1102260336, 759250125, 387062357,
+	1262586814, 1751258219, 1649649171, 1484645031,
+	1262586814, 992008094, 683307060, 348346918,
+	1073741824, 1489322693, 1402911301, 1262586814,
+	1073741824, 843633538, 581104888, 296244703,
+	843633538, 1170153332, 1102260336, 992008094,
+	843633538, 662838617, 456571181, 232757132,
+	581104888, 806015634, 759250125, 683307060,
+	581104888, 456571181, 314491872, 160326136,
+	296244703, 410903207, 387062357, 348346918,
+	296244703, 232757132, 160326136, 81733001,
+}
+
+// binkQuantNum and binkQuantDen give the quantizer scale of each quantizer index
+var binkQuantNum = [16]int64{1, 4, 5, 2, 7, 8, 3, 7, 4, 9, 5, 6, 7, 8, 9, 10}
+
+var binkQuantDen = [16]int64{1, 3, 3, 1, 3, 3, 1, 2, 1, 2, 1, 1, 1, 1, 1, 1}
+
+// binkAudioCriticalFreqs are the band edges of bink audio, in Hz
+var binkAudioCriticalFreqs = [25]int{
+	100, 200, 300, 400, 510, 630, 770, 920, 1080, 1270, 1480, 1720, 2000, 2320, 2700, 3150, 3700,
+	4400, 5300, 6400, 7700, 9500, 12000, 15500, 24500,
+}
+
+// binkAudioRunLengths are the coefficient runs of bink audio, in units of 8 coefficients
+var binkAudioRunLengths = [16]int{2, 3, 4, 5, 6, 8, 9, 10, 11, 12, 13, 14, 15, 16, 32, 64}
@@ -0,0 +1,1082 @@
+package d2video
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+var (
+	errInvalidBlock  = errors.New("invalid bink block")
+	errInvalidBundle = errors.New("invalid bink bundle")
+	errInvalidMotion = errors.New("bink motion vector out of bounds")
+)
+
+// block types of revisions after b
+const (
+	blockSkip = iota
+	blockScaled
+	blockMotion
+	blockRun
+	blockResidue
+	blockIntra
+	blockFill
+	blockInter
+	blockPattern
+	blockRaw
+)
+
+// block types of revision b
+const (
+	blockBSkip = iota
+	blockBRun
+	blockBIntra
+	blockBResidue
+	blockBInter
+	blockBFill
+	blockBPattern
+	blockBMotion
+	blockBRaw
+)
+
+// bundles of values, each plane row reads the values its blocks use
+const (
+	srcBlockTypes = iota
+	srcSubBlockTypes
+	srcColors
+	srcPattern
+	srcXOff
+	srcYOff
+	srcIntraDC
+	srcInterDC
+	srcRun
+	numSources
+)
+
+// bundles of revision b
+const (
+	srcBBlockTypes = iota
+	srcBColors
+	srcBPattern
+	srcBXOff
+	srcBYOff
+	srcBIntraDC
+	srcBInterDC
+	srcBIntraQ
+	srcBInterQ
+	srcBInterCoefs
+	numSourcesB
+)
+
+var (
+	binkBBundleBits   = [numSourcesB]int{4, 8, 8, 5, 5, 11, 11, 4, 4, 7}
+	binkBBundleSigned = [numSourcesB]bool{false, false, false, true, true, false, true, false, false, false}
+)
+
+const (
+	blockSize      = 8
+	blockPixels    = blockSize * blockSize
+	numPlanes      = 4
+	alphaPlane     = 3
+	dcStartBits    = 11
+	bundleBLength  = 13
+	quantShift     = 11
+	residueBits    = 7
+	colorHighTrees = 16
+	nibbleBits     = 4
+)
+
+var binkIntraQuant, binkInterQuant = binkQuantTables()
+
+// binkQuantTables builds the quantizer tables, indexed by quantizer and by coefficient in scan
+// order
+func binkQuantTables() (intra, inter [16][64]uint32) {
+	const scaleShift = 18 // 30 fraction bits of the scale, 12 of the quantizer
+
+	for q := range intra {
+		for i, k := range binkScan {
+			den := binkQuantDen[q] << scaleShift
+			intra[q][i] = uint32(binkIntraSeed[k] * binkDCTScale[k] * binkQuantNum[q] / den)
+			inter[q][i] = uint32(binkInterSeed[k] * binkDCTScale[k] * binkQuantNum[q] / den)
+		}
+	}
+
+	return intra, inter
+}
+
+// vlcEntry is a symbol of a huffman tree and the length of its code
+type vlcEntry struct {
+	symbol, length int
+}
+
+type vlcTable struct {
+	bits    int
+	entries []vlcEntry
+}
+
+var binkVLC = buildBinkVLC()
+
+// buildBinkVLC builds lookup tables of the huffman trees, indexed by the next bits of the stream
+func buildBinkVLC() (tables [16]vlcTable) {
+	for idx := range tables {
+		maxBits := int(binkTreeLengths[idx][15])
+		tables[idx] = vlcTable{bits: maxBits, entries: make([]vlcEntry, 1<<maxBits)}
+
+		for symbol, code := range binkTreeCodes[idx] {
+			length := int(binkTreeLengths[idx][symbol])
+
+			for high := 0; high < 1<<(maxBits-length); high++ {
+				tables[idx].entries[int(code)|high<<length] = vlcEntry{symbol: symbol, length: length}
+			}
+		}
+	}
+
+	return tables
+}
+
+// huffTree is one of the huffman trees with its leaves mapped to the values they code
+type huffTree struct {
+	vlc     int
+	symbols [16]int
+}
+
+func (t *huffTree) read(br *bitReader) {
+	t.vlc = br.readBits(nibbleBits)
+	if t.vlc == 0 {
+		for i := range t.symbols {
+			t.symbols[i] = i
+		}
+
+		return
+	}
+
+	if br.readBit() {
+		var used [16]bool
+
+		length := br.readBits(3) //nolint:gomnd // number of coded symbols - 1
+
+		for i := 0; i <= length; i++ {
+			t.symbols[i] = br.readBits(nibbleBits)
+			used[t.symbols[i]] = true
+		}
+
+		for i := 0; i < 16 && length < 15; i++ {
+			if !used[i] {
+				length++
+				t.symbols[length] = i
+			}
+		}
+
+		return
+	}
+
+	// the symbols are shuffled by merging runs of growing size
+	var tmp1, tmp2 [16]int
+
+	in, out := tmp1[:], tmp2[:]
+
+	for i := range in {
+		in[i] = i
+	}
+
+	depth := br.readBits(2) //nolint:gomnd // number of merge passes - 1
+
+	for i := 0; i <= depth; i++ {
+		size := 1 << i
+
+		for pos := 0; pos < 16; pos += size << 1 {
+			mergeSymbols(br, out[pos:], in[pos:], size)
+		}
+
+		in, out = out, in
+	}
+
+	copy(t.symbols[:], in)
+}
+
+func mergeSymbols(br *bitReader, dst, src []int, size int) {
+	src1, src2 := src[:size], src[size:size*2]
+
+	for len(src1) > 0 && len(src2) > 0 {
+		if br.readBit() {
+			dst[0], src2 = src2[0], src2[1:]
+		} else {
+			dst[0], src1 = src1[0], src1[1:]
+		}
+
+		dst = dst[1:]
+	}
+
+	dst = dst[copy(dst, src1):]
+	copy(dst, src2)
+}
+
+func (t *huffTree) decode(br *bitReader) int {
+	table := &binkVLC[t.vlc]
+	entry := table.entries[br.peekBits(table.bits)]
+	br.skipBits(entry.length)
+
+	return t.symbols[entry.symbol]
+}
+
+// bundle holds the decoded values of a source, curDec is where values are decoded to and curPtr
+// where they are read from
+type bundle struct {
+	length int
+	tree   huffTree
+	data   []int
+	curDec int
+	curPtr int
+}
+
+func (b *bundle) reset() {
+	b.curDec = 0
+	b.curPtr = 0
+}
+
+// chunkLength reads the number of values a plane row adds to the bundle, 0 while earlier values
+// are still unread or after the bundle ended
+func (b *bundle) chunkLength(br *bitReader) (int, error) {
+	if b.curDec < 0 || b.curDec > b.curPtr {
+		return 0, nil
+	}
+
+	n := br.readBits(b.length)
+	if n == 0 {
+		b.curDec = -1
+		return 0, nil
+	}
+
+	if b.curDec+n > len(b.data) {
+		return 0, errInvalidBundle
+	}
+
+	return n, nil
+}
+
+func (b *bundle) fill(value, n int) {
+	for i := 0; i < n; i++ {
+		b.data[b.curDec+i] = value
+	}
+
+	b.curDec += n
+}
+
+func (b *bundle) push(value int) {
+	b.data[b.curDec] = value
+	b.curDec++
+}
+
+// binkPlanes are the luma, chroma and alpha planes of a frame
+type binkPlanes [numPlanes][]byte
+
+// binkVideo decodes the video packets of a bink file
+type binkVideo struct {
+	revision      byte
+	width, height int
+	hasAlpha      bool
+
+	strides [numPlanes]int
+	cur     binkPlanes
+	last    binkPlanes
+
+	bundles    [numSourcesB]bundle
+	colHigh    [colorHighTrees]huffTree
+	colLastVal int
+	frameNum   int
+
+	br  *bitReader
+	err error
+}
+
+func newBinkVideo(revision byte, width, height int, hasAlpha bool) *binkVideo {
+	v := &binkVideo{revision: revision, width: width, height: height, hasAlpha: hasAlpha}
+
+	for idx := 0; idx < numPlanes; idx++ {
+		bw, bh := v.planeBlocks(idx == 1 || idx == 2)
+
+		// a 16x16 block in the last column or row can reach a block past the plane
+		v.strides[idx] = (bw + 1) * blockSize
+		size := v.strides[idx] * (bh + 1) * blockSize
+
+		v.cur[idx] = make([]byte, size)
+
+		if revision > 'b' {
+			v.last[idx] = make([]byte, size)
+		} else {
+			// revision b decodes in place
+			v.last[idx] = v.cur[idx]
+		}
+	}
+
+	blocks := ((width + blockSize - 1) / blockSize) * ((height + blockSize - 1) / blockSize)
+
+	for idx := range v.bundles {
+		v.bundles[idx].data = make([]int, blocks*blockPixels)
+	}
+
+	return v
+}
+
+// planeBlocks returns the number of 8x8 blocks of a plane, a chroma block covers 16x16 luma
+// pixels
+func (v *binkVideo) planeBlocks(isChroma bool) (bw, bh int) {
+	if isChroma {
+		return (v.width + 15) >> 4, (v.height + 15) >> 4 //nolint:gomnd // 16 pixel blocks
+	}
+
+	return (v.width + 7) >> 3, (v.height + 7) >> 3 //nolint:gomnd // 8 pixel blocks
+}
+
+func (v *binkVideo) reset() {
+	v.frameNum = 0
+
+	for idx := range v.cur {
+		for i := range v.cur[idx] {
+			v.cur[idx][i] = 0
+			v.last[idx][i] = 0
+		}
+	}
+}
+
+// decodeFrame decodes a video packet, the decoded planes are in v.last afterwards
+func (v *binkVideo) decodeFrame(data []byte) error {
+	v.br = newBitReader(data)
+	v.err = nil
+
+	if v.hasAlpha {
+		if v.revision >= 'i' {
+			v.br.skipBits(32) //nolint:gomnd // size of the alpha plane
+		}
+
+		if err := v.decodePlane(alphaPlane, false); err != nil {
+			return err
+		}
+	}
+
+	if v.revision >= 'i' {
+		v.br.skipBits(32) //nolint:gomnd // size of the planes
+	}
+
+	for plane := 0; plane < 3; plane++ {
+		idx := plane
+
+		// revisions from h on store the chroma planes the other way around
+		if plane != 0 && v.revision >= 'h' {
+			idx = plane ^ 3
+		}
+
+		var err error
+
+		if v.revision > 'b' {
+			err = v.decodePlane(idx, plane != 0)
+		} else {
+			err = v.decodePlaneB(idx, plane != 0)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if v.br.bitsLeft() <= 0 {
+			break
+		}
+	}
+
+	v.frameNum++
+
+	if v.revision > 'b' {
+		v.cur, v.last = v.last, v.cur
+	}
+
+	return nil
+}
+
+func (v *binkVideo) initLengths(width, bw int) {
+	width = (width + 7) &^ 7 //nolint:gomnd // whole blocks
+
+	length := func(n int) int {
+		return bits.Len(uint(n + 511)) //nolint:gomnd // at least 10 bits
+	}
+
+	v.bundles[srcBlockTypes].length = length(width >> 3)
+	v.bundles[srcSubBlockTypes].length = length(width >> 4)
+	v.bundles[srcColors].length = length(bw * blockPixels)
+	v.bundles[srcIntraDC].length = length(width >> 3)
+	v.bundles[srcInterDC].length = length(width >> 3)
+	v.bundles[srcXOff].length = length(width >> 3)
+	v.bundles[srcYOff].length = length(width >> 3)
+	v.bundles[srcPattern].length = length(bw << 3)
+	v.bundles[srcRun].length = length(bw * 48) //nolint:gomnd // runs per block
+}
+
+func (v *binkVideo) readBundle(idx int) {
+	if idx == srcColors {
+		for i := range v.colHigh {
+			v.colHigh[i].read(v.br)
+		}
+
+		v.colLastVal = 0
+	}
+
+	if idx != srcIntraDC && idx != srcInterDC {
+		v.bundles[idx].tree.read(v.br)
+	}
+
+	v.bundles[idx].reset()
+}
+
+// value reads the next value of a bundle, reading past the decoded values is an error
+func (v *binkVideo) value(idx int) int {
+	b := &v.bundles[idx]
+
+	if b.curPtr >= len(b.data) {
+		v.err = errInvalidBundle
+		return 0
+	}
+
+	b.curPtr++
+
+	return b.data[b.curPtr-1]
+}
+
+func (v *binkVideo) readBlockTypes(b *bundle) error {
+	n, err := b.chunkLength(v.br)
+	if err != nil || n == 0 {
+		return err
+	}
+
+	if v.br.readBit() {
+		b.fill(v.br.readBits(nibbleBits), n)
+		return nil
+	}
+
+	last := 0
+
+	for end := b.curDec + n; b.curDec < end; {
+		t := b.tree.decode(v.br)
+		if t < 12 { //nolint:gomnd // values from 12 on repeat the last type
+			last = t
+			b.push(t)
+
+			continue
+		}
+
+		run := binkRunLengths[t-12]
+		if end-b.curDec < run {
+			return errInvalidBundle
+		}
+
+		b.fill(last, run)
+	}
+
+	return nil
+}
+
+func (v *binkVideo) readColor(b *bundle) int {
+	v.colLastVal = v.colHigh[v.colLastVal].decode(v.br)
+	c := v.colLastVal<<4 | b.tree.decode(v.br)
+
+	// before revision i colors are signed
+	if v.revision < 'i' {
+		if c&0x80 != 0 {
+			c = 0x80 - c&0x7f
+		} else {
+			c = 0x80 + c
+		}
+	}
+
+	return c
+}
+
+func (v *binkVideo) readColors(b *bundle) error {
+	n, err := b.chunkLength(v.br)
+	if err != nil || n == 0 {
+		return err
+	}
+
+	if v.br.readBit() {
+		b.fill(v.readColor(b), n)
+		return nil
+	}
+
+	for i := 0; i < n; i++ {
+		b.push(v.readColor(b))
+	}
+
+	return nil
+}
+
+func (v *binkVideo) readPatterns(b *bundle) error {
+	n, err := b.chunkLength(v.br)
+	if err != nil || n == 0 {
+		return err
+	}
+
+	for i := 0; i < n; i++ {
+		low := b.tree.decode(v.br)
+		b.push(low | b.tree.decode(v.br)<<4)
+	}
+
+	return nil
+}
+
+func (v *binkVideo) readMotionValues(b *bundle) error {
+	n, err := b.chunkLength(v.br)
+	if err != nil || n == 0 {
+		return err
+	}
+
+	if v.br.readBit() {
+		b.fill(v.br.readSigned(v.br.readBits(nibbleBits)), n)
+		return nil
+	}
+
+	for i := 0; i < n; i++ {
+		b.push(v.br.readSigned(b.tree.decode(v.br)))
+	}
+
+	return nil
+}
+
+func (v *binkVideo) readRuns(b *bundle) error {
+	n, err := b.chunkLength(v.br)
+	if err != nil || n == 0 {
+		return err
+	}
+
+	if v.br.readBit() {
+		b.fill(v.br.readBits(nibbleBits), n)
+		return nil
+	}
+
+	for i := 0; i < n; i++ {
+		b.push(b.tree.decode(v.br))
+	}
+
+	return nil
+}
+
+// readDCs reads DC values, coded as a start value and differences in groups of 8
+func (v *binkVideo) readDCs(b *bundle, hasSign bool) error {
+	n, err := b.chunkLength(v.br)
+	if err != nil || n == 0 {
+		return err
+	}
+
+	startBits := dcStartBits
+	if hasSign {
+		startBits--
+	}
+
+	dc := v.br.readBits(startBits)
+	if hasSign {
+		dc = v.br.readSigned(dc)
+	}
+
+	b.push(dc)
+
+	for i := 1; i < n; i += 8 {
+		group := n - i
+		if group > 8 { //nolint:gomnd // group size
+			group = 8
+		}
+
+		size := v.br.readBits(nibbleBits)
+
+		for j := 0; j < group; j++ {
+			if size != 0 {
+				dc += v.br.readSigned(v.br.readBits(size))
+			}
+
+			if dc < -32768 || dc > 32767 {
+				return errInvalidBundle
+			}
+
+			b.push(dc)
+		}
+	}
+
+	return nil
+}
+
+// readRowBundles reads the bundle values a plane row uses
+func (v *binkVideo) readRowBundles() error {
+	readers := []func() error{
+		func() error { return v.readBlockTypes(&v.bundles[srcBlockTypes]) },
+		func() error { return v.readBlockTypes(&v.bundles[srcSubBlockTypes]) },
+		func() error { return v.readColors(&v.bundles[srcColors]) },
+		func() error { return v.readPatterns(&v.bundles[srcPattern]) },
+		func() error { return v.readMotionValues(&v.bundles[srcXOff]) },
+		func() error { return v.readMotionValues(&v.bundles[srcYOff]) },
+		func() error { return v.readDCs(&v.bundles[srcIntraDC], false) },
+		func() error { return v.readDCs(&v.bundles[srcInterDC], true) },
+		func() error { return v.readRuns(&v.bundles[srcRun]) },
+	}
+
+	for _, read := range readers {
+		if err := read(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// blockWriter addresses the pixels of a block in a plane
+type blockWriter struct {
+	pix    []byte
+	offset int
+	stride int
+}
+
+func (w blockWriter) set(pos, value int) {
+	w.pix[w.offset+pos&7+(pos>>3)*w.stride] = byte(value)
+}
+
+func (w blockWriter) fill(value, size int) {
+	for y := 0; y < size; y++ {
+		row := w.pix[w.offset+y*w.stride:]
+		for x := 0; x < size; x++ {
+			row[x] = byte(value)
+		}
+	}
+}
+
+// copyFrom copies an 8x8 block, through a buffer as the blocks can overlap
+func (w blockWriter) copyFrom(src []byte, offset int) {
+	var tmp [blockPixels]byte
+
+	for y := 0; y < blockSize; y++ {
+		copy(tmp[y*blockSize:(y+1)*blockSize], src[offset+y*w.stride:])
+	}
+
+	for y := 0; y < blockSize; y++ {
+		copy(w.pix[w.offset+y*w.stride:], tmp[y*blockSize:(y+1)*blockSize])
+	}
+}
+
+func (w blockWriter) put(block *[blockPixels]byte) {
+	for y := 0; y < blockSize; y++ {
+		copy(w.pix[w.offset+y*w.stride:], block[y*blockSize:(y+1)*blockSize])
+	}
+}
+
+// scale draws an 8x8 block as 16x16 pixels
+func (w blockWriter) scale(block *[blockPixels]byte) {
+	for y := 0; y < blockSize*2; y++ {
+		row := w.pix[w.offset+y*w.stride:]
+		for x := 0; x < blockSize*2; x++ {
+			row[x] = block[(y>>1)*blockSize+x>>1]
+		}
+	}
+}
+
+func (w blockWriter) add(block *[blockPixels]int32) {
+	for y := 0; y < blockSize; y++ {
+		row := w.pix[w.offset+y*w.stride:]
+		for x := 0; x < blockSize; x++ {
+			row[x] += byte(block[y*blockSize+x])
+		}
+	}
+}
+
+// motionOffset returns where the block of a motion vector starts in the previous frame
+func (v *binkVideo) motionOffset(idx, bw, bh, bx, by, xoff, yoff int) (int, error) {
+	stride := v.strides[idx]
+	offset := (by*blockSize+yoff)*stride + bx*blockSize + xoff
+
+	if offset < 0 || offset > ((bh-1)*stride+bw-1)*blockSize {
+		return 0, errInvalidMotion
+	}
+
+	return offset, nil
+}
+
+// runBlock fills the pixels of a block in the order of a pattern with runs of colors
+func (v *binkVideo) runBlock(set func(pos, value int)) error {
+	scan := binkPatterns[v.br.readBits(nibbleBits)][:]
+	i := 0
+
+	for i < 63 { //nolint:gomnd // the last pixel needs no run
+		run := v.value(srcRun) + 1
+
+		i += run
+		if i > blockPixels {
+			return errInvalidBlock
+		}
+
+		if v.br.readBit() {
+			c := v.value(srcColors)
+			for j := 0; j < run; j++ {
+				set(scan[j], c)
+			}
+		} else {
+			for j := 0; j < run; j++ {
+				set(scan[j], v.value(srcColors))
+			}
+		}
+
+		scan = scan[run:]
+	}
+
+	if i == 63 { //nolint:gomnd // the last pixel
+		set(scan[0], v.value(srcColors))
+	}
+
+	return nil
+}
+
+func (v *binkVideo) patternBlock(set func(pos, value int)) {
+	var colors [2]int
+
+	colors[0] = v.value(srcColors)
+	colors[1] = v.value(srcColors)
+
+	for y := 0; y < blockSize; y++ {
+		pattern := v.value(srcPattern)
+
+		for x := 0; x < blockSize; x++ {
+			set(y*blockSize+x, colors[pattern>>x&1])
+		}
+	}
+}
+
+// dctBlock reads the coefficients of a DCT block and transforms them
+func (v *binkVideo) dctBlock(dc int, quant *[16][64]uint32, q int) (*[blockPixels]int32, error) {
+	var block [blockPixels]int32
+
+	block[0] = int32(dc)
+
+	coefs, quantIdx, err := readDCTCoefs(v.br, &block, q)
+	if err != nil {
+		return nil, err
+	}
+
+	unquantize(&block, &quant[quantIdx], coefs)
+
+	return &block, nil
+}
+
+//nolint:funlen,gocyclo // one case per block type
+func (v *binkVideo) decodePlane(idx int, isChroma bool) error {
+	bw, bh := v.planeBlocks(isChroma)
+	stride := v.strides[idx]
+
+	width := v.width
+	if isChroma {
+		width >>= 1
+	}
+
+	if width < blockSize {
+		width = blockSize
+	}
+
+	v.initLengths(width, bw)
+
+	for src := 0; src < numSources; src++ {
+		v.readBundle(src)
+	}
+
+	dst, prev := v.cur[idx], v.last[idx]
+
+	for by := 0; by < bh; by++ {
+		if err := v.readRowBundles(); err != nil {
+			return err
+		}
+
+		for bx := 0; bx < bw; bx++ {
+			w := blockWriter{pix: dst, offset: by*blockSize*stride + bx*blockSize, stride: stride}
+
+			blk := v.value(srcBlockTypes)
+
+			// a 16x16 block on an odd row was decoded with the row above
+			if by&1 == 1 && blk == blockScaled {
+				bx++
+				continue
+			}
+
+			var err error
+
+			switch blk {
+			case blockSkip:
+				w.copyFrom(prev, w.offset)
+			case blockScaled:
+				err = v.scaledBlock(w)
+				bx++
+			case blockMotion:
+				var ref int
+
+				ref, err = v.motionOffset(idx, bw, bh, bx, by, v.value(srcXOff), v.value(srcYOff))
+				if err == nil {
+					w.copyFrom(prev, ref)
+				}
+			case blockRun:
+				err = v.runBlock(w.set)
+			case blockResidue:
+				var ref int
+
+				ref, err = v.motionOffset(idx, bw, bh, bx, by, v.value(srcXOff), v.value(srcYOff))
+				if err == nil {
+					w.copyFrom(prev, ref)
+					w.add(readResidue(v.br, v.br.readBits(residueBits)))
+				}
+			case blockIntra:
+				var block *[blockPixels]int32
+
+				block, err = v.dctBlock(v.value(srcIntraDC), &binkIntraQuant, -1)
+				if err == nil {
+					idctPut(w, block)
+				}
+			case blockFill:
+				w.fill(v.value(srcColors), blockSize)
+			case blockInter:
+				var ref int
+
+				ref, err = v.motionOffset(idx, bw, bh, bx, by, v.value(srcXOff), v.value(srcYOff))
+				if err != nil {
+					break
+				}
+
+				w.copyFrom(prev, ref)
+
+				var block *[blockPixels]int32
+
+				block, err = v.dctBlock(v.value(srcInterDC), &binkInterQuant, -1)
+				if err == nil {
+					idct(block)
+					w.add(block)
+				}
+			case blockPattern:
+				v.patternBlock(w.set)
+			case blockRaw:
+				for pos := 0; pos < blockPixels; pos++ {
+					w.set(pos, v.value(srcColors))
+				}
+			default:
+				err = fmt.Errorf("%w: type %d", errInvalidBlock, blk)
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if v.err != nil {
+				return v.err
+			}
+		}
+	}
+
+	v.br.align32()
+
+	return v.br.err()
+}
+
+// scaledBlock decodes an 8x8 block drawn as 16x16 pixels
+func (v *binkVideo) scaledBlock(w blockWriter) error {
+	var block [blockPixels]byte
+
+	set := func(pos, value int) {
+		block[pos] = byte(value)
+	}
+
+	blk := v.value(srcSubBlockTypes)
+
+	switch blk {
+	case blockRun:
+		if err := v.runBlock(set); err != nil {
+			return err
+		}
+	case blockIntra:
+		coefs, err := v.dctBlock(v.value(srcIntraDC), &binkIntraQuant, -1)
+		if err != nil {
+			return err
+		}
+
+		idctPut(blockWriter{pix: block[:], stride: blockSize}, coefs)
+	case blockFill:
+		w.fill(v.value(srcColors), blockSize*2)
+		return nil
+	case blockPattern:
+		v.patternBlock(set)
+	case blockRaw:
+		for pos := 0; pos < blockPixels; pos++ {
+			set(pos, v.value(srcColors))
+		}
+	default:
+		return fmt.Errorf("%w: scaled type %d", errInvalidBlock, blk)
+	}
+
+	w.scale(&block)
+
+	return nil
+}
+
+// bundle values of revision b are stored as plain numbers
+func (v *binkVideo) readBundleB(idx int) error {
+	b := &v.bundles[idx]
+
+	n, err := b.chunkLength(v.br)
+	if err != nil || n == 0 {
+		return err
+	}
+
+	bitCount := binkBBundleBits[idx]
+
+	for i := 0; i < n; i++ {
+		value := v.br.readBits(bitCount)
+		if binkBBundleSigned[idx] {
+			value -= 1 << (bitCount - 1)
+		}
+
+		b.push(value)
+	}
+
+	return nil
+}
+
+// motionOffsetB returns where the block of a motion vector starts, revision b copies from the
+// frame being decoded and ignores vectors out of it
+func (v *binkVideo) motionOffsetB(idx, bw, bh, bx, by int) (int, bool) {
+	stride := v.strides[idx]
+
+	yoff := v.value(srcBYOff)
+	if v.frameNum == 0 {
+		yoff -= 15 //nolint:gomnd // bias of key frame vectors
+	}
+
+	offset := (by*blockSize+yoff)*stride + bx*blockSize + v.value(srcBXOff)
+
+	return offset, offset >= 0 && offset+blockSize*stride <= (bh*stride+bw)*blockSize
+}
+
+// runBitsB is the number of bits of a run of revision b after i pixels
+func runBitsB(i int) int {
+	return bits.Len(uint(blockPixels - 1 - i))
+}
+
+//nolint:funlen,gocyclo // one case per block type
+func (v *binkVideo) decodePlaneB(idx int, isChroma bool) error {
+	bw, bh := v.planeBlocks(isChroma)
+	stride := v.strides[idx]
+	dst := v.cur[idx]
+
+	for src := 0; src < numSourcesB; src++ {
+		v.bundles[src].length = bundleBLength
+		v.bundles[src].reset()
+	}
+
+	for by := 0; by < bh; by++ {
+		for src := 0; src < numSourcesB; src++ {
+			if err := v.readBundleB(src); err != nil {
+				return err
+			}
+		}
+
+		for bx := 0; bx < bw; bx++ {
+			w := blockWriter{pix: dst, offset: by*blockSize*stride + bx*blockSize, stride: stride}
+
+			var err error
+
+			switch blk := v.value(srcBBlockTypes); blk {
+			case blockBSkip:
+			case blockBRun:
+				scan := binkPatterns[v.br.readBits(nibbleBits)][:]
+				i := 0
+
+				for i < 63 && err == nil { //nolint:gomnd // the last pixel needs no run
+					fill := v.br.readBit()
+					run := v.br.readBits(runBitsB(i)) + 1
+
+					i += run
+					if i > blockPixels {
+						err = errInvalidBlock
+						break
+					}
+
+					c := v.value(srcBColors)
+
+					for j := 0; j < run; j++ {
+						if !fill && j > 0 {
+							c = v.value(srcBColors)
+						}
+
+						w.set(scan[j], c)
+					}
+
+					scan = scan[run:]
+				}
+
+				if i == 63 { //nolint:gomnd // the last pixel
+					w.set(scan[0], v.value(srcBColors))
+				}
+			case blockBIntra:
+				var block *[blockPixels]int32
+
+				dc := v.value(srcBIntraDC)
+
+				block, err = v.dctBlock(dc, &binkIntraQuant, v.value(srcBIntraQ))
+				if err == nil {
+					idctPut(w, block)
+				}
+			case blockBResidue:
+				if ref, ok := v.motionOffsetB(idx, bw, bh, bx, by); ok {
+					w.copyFrom(dst, ref)
+				}
+
+				w.add(readResidue(v.br, v.value(srcBInterCoefs)))
+			case blockBInter:
+				if ref, ok := v.motionOffsetB(idx, bw, bh, bx, by); ok {
+					w.copyFrom(dst, ref)
+				}
+
+				var block *[blockPixels]int32
+
+				dc := v.value(srcBInterDC)
+
+				block, err = v.dctBlock(dc, &binkInterQuant, v.value(srcBInterQ))
+				if err == nil {
+					idct(block)
+					w.add(block)
+				}
+			case blockBFill:
+				w.fill(v.value(srcBColors), blockSize)
+			case blockBPattern:
+				var colors [2]int
+
+				colors[0] = v.value(srcBColors)
+				colors[1] = v.value(srcBColors)
+
+				for y := 0; y < blockSize; y++ {
+					pattern := v.value(srcBPattern)
+
+					for x := 0; x < blockSize; x++ {
+						w.set(y*blockSize+x, colors[pattern>>x&1])
+					}
+				}
+			case blockBMotion:
+				if ref, ok := v.motionOffsetB(idx, bw, bh, bx, by); ok {
+					w.copyFrom(dst, ref)
+				}
+			case blockBRaw:
+				for pos := 0; pos < blockPixels; pos++ {
+					w.set(pos, v.value(srcBColors))
+				}
+			default:
+				err = fmt.Errorf("%w: type %d", errInvalidBlock, blk)
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if v.err != nil {
+				return v.err
+			}
+		}
+	}
+
+	v.br.align32()
+
+	return v.br.err()
+}
@@ -1,12 +1,25 @@
 package d2video
 
 import (
+	"encoding/binary"
 	"errors"
-	"log"
+	"fmt"
+	"io"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2datautils"
 )
 
+var (
+	// ErrInvalidHeader is returned for data that isn't a bink video
+	ErrInvalidHeader = errors.New("invalid header for bink video")
+
+	// ErrUnsupportedRevision is returned for bink revisions the decoder can't decode
+	ErrUnsupportedRevision = errors.New("unsupported bink revision")
+
+	// ErrInvalidFrame is returned when the frame index points outside of the file
+	ErrInvalidFrame = errors.New("invalid bink frame")
+)
+
 // BinkVideoMode is the video mode type
 type BinkVideoMode uint32
 
@@ -34,6 +47,8 @@ const (
 	numHeaderBytes            = 3
 	bikHeaderStr              = "BIK"
 	numAudioTrackUnknownBytes = 2
+	keyFrameFlag              = 1
+	audioPacketHeaderBytes    = 4
 )
 
 // BinkAudioAlgorithm represents the type of bink audio algorithm
@@ -68,14 +83,16 @@ type BinkDecoder struct {
 	VideoHeight           uint32
 	FPS                   uint32
 	FrameTimeMS           uint32
+	fpsDividend           uint32
+	fpsDivider            uint32
 	VideoMode             BinkVideoMode
 	frameIndex            uint32
 	videoCodecRevision    byte
 	HasAlphaPlane         bool
 	Grayscale             bool
 
-	// Mask bit 0, as this is defined as a keyframe
-
+	video *binkVideo
+	audio []*binkAudio
 }
 
 // CreateBinkDecoder returns a new instance of the bink decoder
@@ -84,31 +101,171 @@ func CreateBinkDecoder(source []byte) (*BinkDecoder, error) {
 		streamReader: d2datautils.CreateStreamReader(source),
 	}
 
-	err := result.loadHeaderInformation()
+	if err := result.loadHeaderInformation(); err != nil {
+		return nil, err
+	}
+
+	result.video = newBinkVideo(result.videoCodecRevision, int(result.VideoWidth), int(result.VideoHeight),
+		result.HasAlphaPlane)
 
-	return result, err
+	result.audio = make([]*binkAudio, len(result.AudioTracks))
+	for i := range result.AudioTracks {
+		result.audio[i] = newBinkAudio(&result.AudioTracks[i], result.videoCodecRevision == 'b')
+	}
+
+	return result, nil
 }
 
-// GetNextFrame gets the next frame
-func (v *BinkDecoder) GetNextFrame() error {
-	//nolint:gocritic // v.streamReader.SetPosition(uint64(v.FrameIndexTable[i] & 0xFFFFFFFE))
-	lengthOfAudioPackets, err := v.streamReader.ReadUInt32()
-	if err != nil {
-		return err
+// NumberOfFrames returns the number of frames of the video
+func (v *BinkDecoder) NumberOfFrames() int {
+	return int(v.numberOfFrames)
+}
+
+// FramesDecoded returns the number of frames decoded since the start
+func (v *BinkDecoder) FramesDecoded() int {
+	return int(v.frameIndex)
+}
+
+// FrameDuration returns how long a frame is shown, in seconds
+func (v *BinkDecoder) FrameDuration() float64 {
+	return float64(v.fpsDivider) / float64(v.fpsDividend)
+}
+
+// Reset starts decoding from the first frame again
+func (v *BinkDecoder) Reset() {
+	v.frameIndex = 0
+	v.video.reset()
+
+	for _, track := range v.audio {
+		track.reset()
 	}
+}
 
-	samplesInPacket, err := v.streamReader.ReadUInt32()
+// GetNextFrame decodes the next frame, io.EOF is returned after the last frame. The planes of the
+// frame are only valid until the next call.
+func (v *BinkDecoder) GetNextFrame() (*BinkFrame, error) {
+	if v.frameIndex >= v.numberOfFrames {
+		return nil, io.EOF
+	}
+
+	data, err := v.frameData(v.frameIndex)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	frame := &BinkFrame{
+		Index:    int(v.frameIndex),
+		KeyFrame: v.FrameIndexTable[v.frameIndex]&keyFrameFlag != 0,
+		Width:    int(v.VideoWidth),
+		Height:   int(v.VideoHeight),
+		Audio:    make([][]int16, len(v.AudioTracks)),
+	}
+
+	for track := range v.AudioTracks {
+		var packet []byte
+
+		packet, data, err = splitAudioPacket(data)
+		if err != nil {
+			return nil, err
+		}
+
+		if packet != nil {
+			if frame.Audio[track], err = v.audio[track].decodePacket(packet); err != nil {
+				return nil, fmt.Errorf("frame %d, audio track %d: %w", v.frameIndex, track, err)
+			}
+		}
 	}
 
-	v.streamReader.SkipBytes(int(lengthOfAudioPackets) - 4) //nolint:gomnd // decode magic
+	if err := v.video.decodeFrame(data); err != nil {
+		return nil, fmt.Errorf("frame %d: %w", v.frameIndex, err)
+	}
 
-	log.Printf("Frame %d:\tSamp: %d", v.frameIndex, samplesInPacket)
+	for idx := range frame.Planes {
+		frame.Planes[idx] = v.video.last[idx]
+		frame.Strides[idx] = v.video.strides[idx]
+	}
+
+	if !v.HasAlphaPlane {
+		frame.Planes[alphaPlane] = nil
+	}
 
 	v.frameIndex++
 
-	return nil
+	return frame, nil
+}
+
+// DecodeAudio decodes all samples of an audio track, interleaved. It doesn't change which frame
+// is decoded next.
+func (v *BinkDecoder) DecodeAudio(track int) ([]int16, error) {
+	if track < 0 || track >= len(v.AudioTracks) {
+		return nil, fmt.Errorf("%w: no audio track %d", ErrInvalidFrame, track)
+	}
+
+	decoder := newBinkAudio(&v.AudioTracks[track], v.videoCodecRevision == 'b')
+	samples := make([]int16, 0)
+
+	for idx := uint32(0); idx < v.numberOfFrames; idx++ {
+		data, err := v.frameData(idx)
+		if err != nil {
+			return samples, err
+		}
+
+		var packet []byte
+
+		for i := 0; i <= track; i++ {
+			if packet, data, err = splitAudioPacket(data); err != nil {
+				return samples, err
+			}
+		}
+
+		if packet == nil {
+			continue
+		}
+
+		decoded, err := decoder.decodePacket(packet)
+		if err != nil {
+			return samples, fmt.Errorf("frame %d: %w", idx, err)
+		}
+
+		samples = append(samples, decoded...)
+	}
+
+	return samples, nil
+}
+
+// frameData returns the packets of a frame
+func (v *BinkDecoder) frameData(idx uint32) ([]byte, error) {
+	start := uint64(v.FrameIndexTable[idx] &^ keyFrameFlag)
+	end := uint64(v.FrameIndexTable[idx+1] &^ keyFrameFlag)
+
+	if start > end || end > v.streamReader.Size() {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidFrame, idx)
+	}
+
+	v.streamReader.SetPosition(start)
+
+	return v.streamReader.ReadBytes(int(end - start))
+}
+
+// splitAudioPacket splits the audio packet of a track off the frame data, packets without
+// samples are returned as nil
+func splitAudioPacket(data []byte) (packet, rest []byte, err error) {
+	if len(data) < audioPacketHeaderBytes {
+		return nil, nil, ErrInvalidFrame
+	}
+
+	size := int(binary.LittleEndian.Uint32(data))
+	data = data[audioPacketHeaderBytes:]
+
+	if size > len(data) {
+		return nil, nil, ErrInvalidFrame
+	}
+
+	if size > audioPacketHeaderBytes {
+		packet = data[:size]
+	}
+
+	return packet, data[size:], nil
 }
 
 //nolint:gomnd,funlen,gocyclo // Decoder magic, can't help the long function length for now
@@ -123,7 +280,7 @@ func (v *BinkDecoder) loadHeaderInformation() error {
 	}
 
 	if string(headerBytes) != bikHeaderStr {
-		return errors.New("invalid header for bink video")
+		return ErrInvalidHeader
 	}
 
 	v.videoCodecRevision, err = v.streamReader.ReadByte()
@@ -131,6 +288,12 @@ func (v *BinkDecoder) loadHeaderInformation() error {
 		return err
 	}
 
+	switch v.videoCodecRevision {
+	case 'b', 'd', 'f', 'g', 'h', 'i':
+	default:
+		return fmt.Errorf("%w: %c", ErrUnsupportedRevision, v.videoCodecRevision)
+	}
+
 	v.fileSize, err = v.streamReader.ReadUInt32()
 	if err != nil {
 		return err
@@ -160,18 +323,22 @@ func (v *BinkDecoder) loadHeaderInformation() error {
 		return err
 	}
 
-	fpsDividend, err := v.streamReader.ReadUInt32()
+	v.fpsDividend, err = v.streamReader.ReadUInt32()
 	if err != nil {
 		return err
 	}
 
-	fpsDivider, err := v.streamReader.ReadUInt32()
+	v.fpsDivider, err = v.streamReader.ReadUInt32()
 	if err != nil {
 		return err
 	}
 
-	v.FPS = uint32(float32(fpsDividend) / float32(fpsDivider))
-	v.FrameTimeMS = 1000 / v.FPS
+	if v.fpsDividend == 0 || v.fpsDivider == 0 {
+		return ErrInvalidHeader
+	}
+
+	v.FPS = uint32(float32(v.fpsDividend) / float32(v.fpsDivider))
+	v.FrameTimeMS = 1000 * v.fpsDivider / v.fpsDividend
 
 	videoFlags, err := v.streamReader.ReadUInt32()
 	if err != nil {
@@ -0,0 +1,712 @@
+package d2video
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"math"
+	"math/bits"
+	"math/rand"
+	"testing"
+)
+
+// bitWriter writes a little endian bitstream like the encoder does
+type bitWriter struct {
+	data []byte
+	pos  int
+}
+
+func (w *bitWriter) write(value, n int) {
+	for i := 0; i < n; i++ {
+		if w.pos>>3 == len(w.data) {
+			w.data = append(w.data, 0)
+		}
+
+		w.data[w.pos>>3] |= byte(value>>i&1) << (w.pos & 7)
+		w.pos++
+	}
+}
+
+func (w *bitWriter) signed(value, n int) {
+	magnitude := value
+	if value < 0 {
+		magnitude = -value
+	}
+
+	w.write(magnitude, n)
+
+	if value > 0 {
+		w.write(0, 1)
+	} else if value < 0 {
+		w.write(1, 1)
+	}
+}
+
+func (w *bitWriter) align32() {
+	for w.pos&31 != 0 {
+		w.write(0, 1)
+	}
+}
+
+// testBundle writes the values of a bundle in the chunks the decoder asks for, all values left
+// as one chunk and an empty chunk once they are used up
+type testBundle struct {
+	rows     [][]int
+	length   int
+	code     func(w *bitWriter, values []int)
+	ended    bool
+	dec, ptr int
+}
+
+func (b *testBundle) writeRow(w *bitWriter, row int) {
+	if !b.ended && b.dec <= b.ptr {
+		var rest []int
+		for _, values := range b.rows[row:] {
+			rest = append(rest, values...)
+		}
+
+		w.write(len(rest), b.length)
+
+		if len(rest) == 0 {
+			b.ended = true
+		} else {
+			b.code(w, rest)
+			b.dec += len(rest)
+		}
+	}
+
+	b.ptr += len(b.rows[row])
+}
+
+// testPlane is a plane of a test clip, the bundle values by source and row and the bits the
+// blocks of a row read themselves
+type testPlane struct {
+	values    [numSourcesB][][]int
+	blockBits [][]func(w *bitWriter)
+}
+
+func (p *testPlane) bundles(length [numSourcesB]int, code [numSourcesB]func(*bitWriter, []int)) []*testBundle {
+	rows := len(p.blockBits)
+	bundles := make([]*testBundle, 0, numSourcesB)
+
+	for src := range code {
+		if code[src] == nil {
+			break
+		}
+
+		values := append([][]int(nil), p.values[src]...)
+		for len(values) < rows {
+			values = append(values, nil)
+		}
+
+		bundles = append(bundles, &testBundle{rows: values, length: length[src], code: code[src]})
+	}
+
+	return bundles
+}
+
+func (p *testPlane) write(w *bitWriter, bundles []*testBundle) {
+	for row, blockBits := range p.blockBits {
+		for _, b := range bundles {
+			b.writeRow(w, row)
+		}
+
+		for _, write := range blockBits {
+			write(w)
+		}
+	}
+
+	w.align32()
+}
+
+func flagged(code func(w *bitWriter, value int)) func(w *bitWriter, values []int) {
+	return func(w *bitWriter, values []int) {
+		w.write(0, 1) // not a single repeated value
+
+		for _, v := range values {
+			code(w, v)
+		}
+	}
+}
+
+func dcs(hasSign bool) func(w *bitWriter, values []int) {
+	return func(w *bitWriter, values []int) {
+		if hasSign {
+			w.signed(values[0], dcStartBits-1)
+		} else {
+			w.write(values[0], dcStartBits)
+		}
+
+		for i := 1; i < len(values); i += 8 {
+			w.write(15, nibbleBits)
+
+			for j := i; j < i+8 && j < len(values); j++ {
+				w.signed(values[j]-values[j-1], 15)
+			}
+		}
+	}
+}
+
+// writePlane writes a plane of a revision after b, all huffman trees code plain nibbles
+func writePlane(w *bitWriter, p *testPlane, width, bw int) {
+	length := func(n int) int { return bits.Len(uint(n + 511)) }
+	nibble := func(w *bitWriter, v int) { w.write(v, nibbleBits) }
+	motion := func(w *bitWriter, v int) { w.signed(v, nibbleBits) }
+	color := func(w *bitWriter, v int) { w.write(v>>4, nibbleBits); w.write(v&15, nibbleBits) }
+	pattern := func(w *bitWriter, values []int) {
+		for _, v := range values {
+			w.write(v&15, nibbleBits)
+			w.write(v>>4, nibbleBits)
+		}
+	}
+
+	// the trees of the bundles and of the high color nibbles
+	w.write(0, nibbleBits*(numSources-2+colorHighTrees))
+
+	width = (width + 7) &^ 7
+	p.write(w, p.bundles([numSourcesB]int{
+		length(width >> 3), length(width >> 4), length(bw * 64), length(bw << 3), length(width >> 3),
+		length(width >> 3), length(width >> 3), length(width >> 3), length(bw * 48),
+	}, [numSourcesB]func(*bitWriter, []int){
+		flagged(nibble), flagged(nibble), flagged(color), pattern, flagged(motion), flagged(motion),
+		dcs(false), dcs(true), flagged(nibble),
+	}))
+}
+
+// writePlaneB writes a plane of revision b
+func writePlaneB(w *bitWriter, p *testPlane) {
+	var (
+		length [numSourcesB]int
+		code   [numSourcesB]func(*bitWriter, []int)
+	)
+
+	for src := range code {
+		bitCount, signed := binkBBundleBits[src], binkBBundleSigned[src]
+		length[src] = bundleBLength
+		code[src] = func(w *bitWriter, values []int) {
+			for _, v := range values {
+				if signed {
+					v += 1 << (bitCount - 1)
+				}
+
+				w.write(v, bitCount)
+			}
+		}
+	}
+
+	p.write(w, p.bundles(length, code))
+}
+
+// writeClip writes a bink file of the given frames, an audio track is added when audio is set
+func writeClip(revision byte, width, height int, frames, audio [][]byte) []byte {
+	var buf bytes.Buffer
+
+	le := func(v uint32) {
+		_ = binary.Write(&buf, binary.LittleEndian, v)
+	}
+
+	tracks := 0
+	if audio != nil {
+		tracks = 1
+	}
+
+	offsets := make([]uint32, len(frames)+1)
+	offset := uint32(44 + tracks*12 + len(offsets)*4)
+
+	for idx, frame := range frames {
+		offsets[idx] = offset
+		offset += uint32(len(frame))
+
+		if audio != nil {
+			offset += audioPacketHeaderBytes + uint32(len(audio[idx]))
+		}
+	}
+
+	offsets[len(frames)] = offset
+	offsets[0] |= keyFrameFlag
+
+	buf.WriteString(bikHeaderStr)
+	buf.WriteByte(revision)
+
+	for _, v := range []uint32{offset - 8, uint32(len(frames)), 0, uint32(len(frames)),
+		uint32(width), uint32(height), 25, 1, 0, uint32(tracks)} {
+		le(v)
+	}
+
+	if audio != nil {
+		le(1 << 16) // mono
+		le(22050)   // RDFT
+		le(0)       // track id
+	}
+
+	for _, o := range offsets {
+		le(o)
+	}
+
+	for idx, frame := range frames {
+		if audio != nil {
+			le(uint32(len(audio[idx])))
+			buf.Write(audio[idx])
+		}
+
+		buf.Write(frame)
+	}
+
+	return buf.Bytes()
+}
+
+// testImage is the expected Y, Cb and Cr planes of a 16x16 frame
+type testImage [3][]byte
+
+func newTestImage(y, cb, cr byte) testImage {
+	img := testImage{make([]byte, 256), make([]byte, 64), make([]byte, 64)}
+
+	for plane, value := range []byte{y, cb, cr} {
+		for i := range img[plane] {
+			img[plane][i] = value
+		}
+	}
+
+	return img
+}
+
+func (img testImage) clone() testImage {
+	return testImage{append([]byte(nil), img[0]...), append([]byte(nil), img[1]...), append([]byte(nil), img[2]...)}
+}
+
+func (img testImage) setBlock(bx, by int, pixel func(x, y int) byte) {
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			img[0][(by*8+y)*16+bx*8+x] = pixel(x, y)
+		}
+	}
+}
+
+func checkFrame(t *testing.T, frame *BinkFrame, want testImage) {
+	t.Helper()
+
+	for plane, size := range []int{16, 8, 8} {
+		have := make([]byte, 0, size*size)
+		for y := 0; y < size; y++ {
+			have = append(have, frame.Planes[plane][y*frame.Strides[plane]:][:size]...)
+		}
+
+		if !bytes.Equal(have, want[plane]) {
+			t.Errorf("frame %d plane %d:\nwant %v\nhave %v", frame.Index, plane, want[plane], have)
+		}
+	}
+}
+
+// the colors of a raw block and the pixels of a run block of two runs and a single pixel
+func rawPixels() []int {
+	raw := make([]int, blockPixels)
+	for i := range raw {
+		raw[i] = i * 3
+	}
+
+	return raw
+}
+
+func runPixels(pattern int, colors ...byte) func(x, y int) byte {
+	var run [blockPixels]byte
+
+	for i, pos := range binkPatterns[pattern] {
+		switch {
+		case i < 32:
+			run[pos] = colors[0]
+		case i < 63:
+			run[pos] = colors[1]
+		default:
+			run[pos] = colors[2]
+		}
+	}
+
+	return func(x, y int) byte { return run[y*8+x] }
+}
+
+func patternPixels(x, y int) byte {
+	return []byte{10, 200}[(0x0f>>x&1)^(y&1)]
+}
+
+// testClip builds a 16x16 clip of revision i, a key frame and an inter frame using every block
+// type, and the planes it decodes to
+func testClip() (frames [][]byte, want []testImage) {
+	raw := rawPixels()
+
+	// key frame, the luma blocks are fill, raw, pattern and an intra DC
+	var w bitWriter
+
+	w.write(0, 32)
+
+	luma := &testPlane{blockBits: [][]func(*bitWriter){nil, {
+		func(w *bitWriter) {
+			w.write(0, nibbleBits) // no AC coefficients
+			w.write(0, nibbleBits) // quantizer 0
+		},
+	}}}
+	luma.values[srcBlockTypes] = [][]int{{blockFill, blockRaw}, {blockPattern, blockIntra}}
+	luma.values[srcColors] = [][]int{append([]int{0x40}, raw...), {10, 200}}
+	luma.values[srcPattern] = [][]int{nil, {0x0f, 0xf0, 0x0f, 0xf0, 0x0f, 0xf0, 0x0f, 0xf0}}
+	luma.values[srcIntraDC] = [][]int{nil, {800}}
+	writePlane(&w, luma, 16, 2)
+
+	// revision i codes Cr before Cb
+	for _, value := range []int{160, 90} {
+		chroma := &testPlane{blockBits: [][]func(*bitWriter){nil}}
+		chroma.values[srcBlockTypes] = [][]int{{blockFill}}
+		chroma.values[srcColors] = [][]int{{value}}
+		writePlane(&w, chroma, 8, 1)
+	}
+
+	frames = append(frames, w.data)
+
+	img := newTestImage(0x40, 90, 160)
+	img.setBlock(1, 0, func(x, y int) byte { return byte(raw[y*8+x]) })
+	img.setBlock(0, 1, patternPixels)
+	img.setBlock(1, 1, func(x, y int) byte { return 100 })
+	want = append(want, img)
+
+	// inter frame, skip, motion, run and residue blocks, an inter DC and a scaled fill in chroma
+	w = bitWriter{}
+	w.write(0, 32)
+
+	luma = &testPlane{blockBits: [][]func(*bitWriter){nil, {
+		func(w *bitWriter) {
+			w.write(5, nibbleBits) // pattern
+			w.write(0xf, 4)        // the runs are one color each
+		},
+		func(w *bitWriter) {
+			w.write(10, residueBits) // number of coded bits
+			w.write(0, 3)            // a single bit plane
+			w.write(0x8, 4)          // the groups are empty, coefficients 0 to 3 are coded
+			w.write(0, 8)            // all of them, positive
+		},
+	}}}
+	luma.values[srcBlockTypes] = [][]int{{blockSkip, blockMotion}, {blockRun, blockResidue}}
+	luma.values[srcXOff] = [][]int{{-8}, {0}}
+	luma.values[srcYOff] = [][]int{{0}, {0}}
+	luma.values[srcRun] = [][]int{nil, {15, 15, 15, 14}}
+	luma.values[srcColors] = [][]int{nil, {7, 7, 9, 9, 11}}
+	writePlane(&w, luma, 16, 2)
+
+	chroma := &testPlane{blockBits: [][]func(*bitWriter){{
+		func(w *bitWriter) {
+			w.write(0, nibbleBits)
+			w.write(0, nibbleBits)
+		},
+	}}}
+	chroma.values[srcBlockTypes] = [][]int{{blockInter}}
+	chroma.values[srcXOff] = [][]int{{0}}
+	chroma.values[srcYOff] = [][]int{{0}}
+	chroma.values[srcInterDC] = [][]int{{80}}
+	writePlane(&w, chroma, 8, 1)
+
+	chroma = &testPlane{blockBits: [][]func(*bitWriter){nil}}
+	chroma.values[srcBlockTypes] = [][]int{{blockScaled}}
+	chroma.values[srcSubBlockTypes] = [][]int{{blockFill}}
+	chroma.values[srcColors] = [][]int{{50}}
+	writePlane(&w, chroma, 8, 1)
+
+	frames = append(frames, w.data)
+
+	img = img.clone()
+	img.setBlock(1, 0, func(x, y int) byte { return 0x40 })
+	img.setBlock(0, 1, runPixels(5, 7, 9, 11))
+	img.setBlock(1, 1, func(x, y int) byte {
+		if x < 2 && y < 2 {
+			return 101
+		}
+
+		return 100
+	})
+	img[1] = newTestImage(0, 50, 0)[1]
+	img[2] = newTestImage(0, 0, 170)[2]
+	want = append(want, img)
+
+	return frames, want
+}
+
+// testClipB builds a 16x16 clip of revision b, a key frame of fill, raw, pattern and run blocks
+// and a frame of skipped blocks
+func testClipB() (frames [][]byte, want []testImage) {
+	raw := rawPixels()
+
+	var w bitWriter
+
+	luma := &testPlane{blockBits: [][]func(*bitWriter){nil, {
+		func(w *bitWriter) {
+			w.write(2, nibbleBits) // pattern
+			w.write(1, 1)          // one color
+			w.write(31, 6)         // for 32 pixels
+			w.write(1, 1)
+			w.write(30, 5) // then 31 pixels
+		},
+	}}}
+	luma.values[srcBBlockTypes] = [][]int{{blockBFill, blockBRaw}, {blockBPattern, blockBRun}}
+	luma.values[srcBColors] = [][]int{append([]int{0x30}, raw...), {10, 200, 1, 2, 3}}
+	luma.values[srcBPattern] = [][]int{nil, {0x0f, 0xf0, 0x0f, 0xf0, 0x0f, 0xf0, 0x0f, 0xf0}}
+	writePlaneB(&w, luma)
+
+	for _, value := range []int{70, 180} {
+		chroma := &testPlane{blockBits: [][]func(*bitWriter){nil}}
+		chroma.values[srcBBlockTypes] = [][]int{{blockBFill}}
+		chroma.values[srcBColors] = [][]int{{value}}
+		writePlaneB(&w, chroma)
+	}
+
+	frames = append(frames, w.data)
+
+	img := newTestImage(0x30, 70, 180)
+	img.setBlock(1, 0, func(x, y int) byte { return byte(raw[y*8+x]) })
+	img.setBlock(0, 1, patternPixels)
+	img.setBlock(1, 1, runPixels(2, 1, 2, 3))
+	want = append(want, img)
+
+	w = bitWriter{}
+
+	for _, blocks := range [][][]int{{{blockBSkip, blockBSkip}, {blockBSkip, blockBSkip}}, {{blockBSkip}}, {{blockBSkip}}} {
+		plane := &testPlane{blockBits: make([][]func(*bitWriter), len(blocks))}
+		plane.values[srcBBlockTypes] = blocks
+		writePlaneB(&w, plane)
+	}
+
+	frames = append(frames, w.data)
+	want = append(want, img)
+
+	return frames, want
+}
+
+// signedColor is the coded color of a pixel value before revision i, where colors are signed
+func signedColor(v int) int {
+	if v >= 0x80 {
+		return v - 0x80
+	}
+
+	return 0x80 | (0x80 - v)
+}
+
+// testClipSigned builds a 16x16 key frame of fill and raw blocks for the revisions between b and
+// i, which have no plane sizes and signed colors, and code Cr before Cb from revision h on
+func testClipSigned(revision byte) func() ([][]byte, []testImage) {
+	return func() (frames [][]byte, want []testImage) {
+		raw := rawPixels()
+		colors := []int{signedColor(0x40)}
+
+		// 0 can't be coded as a signed color
+		for i := range raw {
+			raw[i]++
+			colors = append(colors, signedColor(raw[i]))
+		}
+
+		var w bitWriter
+
+		luma := &testPlane{blockBits: [][]func(*bitWriter){nil, nil}}
+		luma.values[srcBlockTypes] = [][]int{{blockFill, blockRaw}, {blockFill, blockFill}}
+		luma.values[srcColors] = [][]int{colors, {signedColor(200), signedColor(10)}}
+		writePlane(&w, luma, 16, 2)
+
+		chroma := []int{90, 160}
+		if revision >= 'h' {
+			chroma[0], chroma[1] = chroma[1], chroma[0]
+		}
+
+		for _, value := range chroma {
+			plane := &testPlane{blockBits: [][]func(*bitWriter){nil}}
+			plane.values[srcBlockTypes] = [][]int{{blockFill}}
+			plane.values[srcColors] = [][]int{{signedColor(value)}}
+			writePlane(&w, plane, 8, 1)
+		}
+
+		frames = append(frames, w.data)
+
+		img := newTestImage(0x40, 90, 160)
+		img.setBlock(1, 0, func(x, y int) byte { return byte(raw[y*8+x]) })
+		img.setBlock(0, 1, func(x, y int) byte { return 200 })
+		img.setBlock(1, 1, func(x, y int) byte { return 10 })
+		want = append(want, img)
+
+		return frames, want
+	}
+}
+
+func TestBinkVideo(t *testing.T) {
+	tests := []struct {
+		name     string
+		revision byte
+		clip     func() ([][]byte, []testImage)
+	}{
+		{"revision i", 'i', testClip},
+		{"revision b", 'b', testClipB},
+		{"revision f", 'f', testClipSigned('f')},
+		{"revision g", 'g', testClipSigned('g')},
+		{"revision h", 'h', testClipSigned('h')},
+	}
+
+	for _, test := range tests {
+		frames, want := test.clip()
+
+		decoder, err := CreateBinkDecoder(writeClip(test.revision, 16, 16, frames, nil))
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		for idx := range frames {
+			frame, err := decoder.GetNextFrame()
+			if err != nil {
+				t.Fatalf("%s: frame %d: %v", test.name, idx, err)
+			}
+
+			checkFrame(t, frame, want[idx])
+		}
+
+		if _, err := decoder.GetNextFrame(); !errors.Is(err, io.EOF) {
+			t.Errorf("%s: want io.EOF after the last frame, have %v", test.name, err)
+		}
+	}
+}
+
+// TestBinkChecksums checks the RGBA pixels of the test clip, after decoding it again from the
+// start. The checksums were taken from this decoder, they only catch changes to its output. The
+// clips of the game aren't redistributable, so decoding them is not covered by the tests.
+func TestBinkChecksums(t *testing.T) {
+	frames, _ := testClip()
+
+	decoder, err := CreateBinkDecoder(writeClip('i', 16, 16, frames, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = decoder.GetNextFrame(); err != nil {
+		t.Fatal(err)
+	}
+
+	decoder.Reset()
+
+	want := []uint32{0xd76462c4, 0xbcbdaafe}
+
+	for idx := range frames {
+		frame, err := decoder.GetNextFrame()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if idx == 0 && !frame.KeyFrame {
+			t.Error("the first frame isn't a key frame")
+		}
+
+		if sum := crc32.ChecksumIEEE(frame.RGBA()); sum != want[idx] {
+			t.Errorf("frame %d: want checksum %08x, have %08x", idx, want[idx], sum)
+		}
+	}
+}
+
+func TestBinkHeader(t *testing.T) {
+	if _, err := CreateBinkDecoder([]byte("KB2a")); !errors.Is(err, ErrInvalidHeader) {
+		t.Errorf("want ErrInvalidHeader, have %v", err)
+	}
+
+	frames, _ := testClip()
+
+	_, err := CreateBinkDecoder(writeClip('k', 16, 16, frames, nil))
+	if !errors.Is(err, ErrUnsupportedRevision) {
+		t.Errorf("want ErrUnsupportedRevision, have %v", err)
+	}
+}
+
+// audioPacket writes a packet of an RDFT block of 1024 coefficients with only a DC value,
+// 2^15 * 2 / (sqrt(1024) * 32768) / 2 * 32768 = 1024
+func audioPacket() []byte {
+	var w bitWriter
+
+	w.write(960*2, 32)     // decoded bytes
+	w.write(16, 5)         // DC exponent
+	w.write(1<<22, 23)     // DC mantissa, 0.5
+	w.write(0, 1)          // positive
+	w.write(0, 5+23+1)     // no Nyquist frequency
+	w.write(0, 23*8)       // band quantizers
+	w.write(1|15<<1, 5)    // 512 coefficients
+	w.write(0, nibbleBits) // all zero
+	w.write(1|15<<1, 5)
+	w.write(0, nibbleBits)
+	w.align32()
+
+	return w.data
+}
+
+func TestBinkAudio(t *testing.T) {
+	frames, _ := testClip()
+	audio := [][]byte{audioPacket(), audioPacket()}
+
+	decoder, err := CreateBinkDecoder(writeClip('i', 16, 16, frames, audio))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	samples, err := decoder.DecodeAudio(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(samples) != 2*960 {
+		t.Fatalf("want %d samples, have %d", 2*960, len(samples))
+	}
+
+	for i, sample := range samples {
+		if sample != 1024 {
+			t.Fatalf("sample %d: want 1024, have %d", i, sample)
+		}
+	}
+
+	frame, err := decoder.GetNextFrame()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(frame.Audio[0]) != 960 {
+		t.Errorf("want 960 samples with the first frame, have %d", len(frame.Audio[0]))
+	}
+}
+
+func TestAudioTransforms(t *testing.T) {
+	const n = 64
+
+	random := rand.New(rand.NewSource(1)) //nolint:gosec // test data
+	transform := newAudioTransform(n)
+
+	data := make([]float64, n)
+	for i := range data {
+		data[i] = random.Float64()*2 - 1
+	}
+
+	rdft := append([]float64(nil), data...)
+	transform.inverseRDFT(rdft)
+
+	dct := append([]float64(nil), data...)
+	transform.dctIII(dct)
+
+	for k := 0; k < n; k++ {
+		wantRDFT := (data[0] + data[1]*math.Cos(math.Pi*float64(k))) / 2
+		wantDCT := data[0] / 2
+
+		for i := 1; i < n/2; i++ {
+			angle := 2 * math.Pi * float64(i*k) / n
+			wantRDFT += data[2*i]*math.Cos(angle) + data[2*i+1]*math.Sin(angle)
+		}
+
+		for i := 1; i < n; i++ {
+			wantDCT += data[i] * math.Cos(math.Pi*float64(i)*(float64(k)+0.5)/n)
+		}
+
+		wantDCT *= 2.0 / n
+
+		if math.Abs(rdft[k]-wantRDFT) > 1e-9 {
+			t.Errorf("inverse RDFT %d: want %f, have %f", k, wantRDFT, rdft[k])
+		}
+
+		if math.Abs(dct[k]-wantDCT) > 1e-9 {
+			t.Errorf("DCT-III %d: want %f, have %f", k, wantDCT, dct[k])
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package d2video
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+var errBitstreamEnd = errors.New("bink bitstream ends too soon")
+
+// bitReader reads a little endian bitstream, the first bit of a value is its lowest bit
+type bitReader struct {
+	data []byte
+	pos  int
+	size int
+}
+
+func newBitReader(data []byte) *bitReader {
+	return &bitReader{data: data, size: len(data) * 8} //nolint:gomnd // bits per byte
+}
+
+// peekBits returns the next n bits (n <= 32) without reading them, bits past the end read as 0
+func (b *bitReader) peekBits(n int) uint32 {
+	if n == 0 {
+		return 0
+	}
+
+	idx := b.pos >> 3 //nolint:gomnd // bits per byte
+
+	var word uint64
+
+	if idx+8 <= len(b.data) {
+		word = binary.LittleEndian.Uint64(b.data[idx:])
+	} else {
+		for i := len(b.data) - 1; i >= idx; i-- {
+			word = word<<8 | uint64(b.data[i])
+		}
+	}
+
+	return uint32(word>>(uint(b.pos)&7)) & (math.MaxUint32 >> (32 - uint(n))) //nolint:gomnd // 32 bit values
+}
+
+func (b *bitReader) skipBits(n int) {
+	b.pos += n
+}
+
+func (b *bitReader) readBits(n int) int {
+	v := b.peekBits(n)
+	b.pos += n
+
+	return int(v)
+}
+
+func (b *bitReader) readBit() bool {
+	return b.readBits(1) == 1
+}
+
+// readSigned reads a sign bit for a nonzero magnitude
+func (b *bitReader) readSigned(v int) int {
+	if v != 0 && b.readBit() {
+		return -v
+	}
+
+	return v
+}
+
+func (b *bitReader) bitsLeft() int {
+	return b.size - b.pos
+}
+
+// align32 skips to the next 32 bit boundary
+func (b *bitReader) align32() {
+	if rem := b.pos & 31; rem != 0 { //nolint:gomnd // 32 bit alignment
+		b.pos += 32 - rem //nolint:gomnd // 32 bit alignment
+	}
+}
+
+// err reports reading past the end of the data
+func (b *bitReader) err() error {
+	if b.pos > b.size {
+		return errBitstreamEnd
+	}
+
+	return nil
+}
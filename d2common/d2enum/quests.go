@@ -23,18 +23,8 @@ const (
 	Act5
 )
 
-/* I think, It should looks like that:
-   each quest has its own position in questStatus map
-   which should come from save file.
-   quests status values:
-           - -2 - done
-           - -1 - done, need to play animation
-           -  0 - not started yet
-           - and after that we have "in progress status"
-             so for status (from 1 to n) we have appropriate
-             quest descriptions and we'll have appropriate
-             actions
-*/
+// Quest statuses, see d2quest.State. The statuses from QuestStatusInProgress up are the
+// progress states of each quest, which select the quest description in the quest log.
 const (
 	QuestStatusCompleted  = iota - 2 // quest completed
 	QuestStatusCompleting            // quest completed (need to play animation)
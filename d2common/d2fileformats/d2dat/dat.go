@@ -1,6 +1,8 @@
 package d2dat
 
 import (
+	"fmt"
+
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
 )
 
@@ -34,3 +36,23 @@ func (p *DATPalette) Marshal() []byte {
 
 	return result
 }
+
+// Shift returns a copy of the palette with its colors remapped by a color map, color i of the
+// result is the color of the palette at colorMap[i]. The color maps of a palshift.dat file
+// recolor the animations of monster sub-types.
+func Shift(palette d2interface.Palette, colorMap []byte) (d2interface.Palette, error) {
+	if len(colorMap) < numColors {
+		return nil, fmt.Errorf("color map has %d colors, needs %d", len(colorMap), numColors)
+	}
+
+	result := New()
+	colors := palette.GetColors()
+
+	for idx := range result.colors {
+		if source := colors[colorMap[idx]]; source != nil {
+			result.colors[idx].SetRGBA(source.RGBA())
+		}
+	}
+
+	return result, nil
+}
@@ -10,6 +10,10 @@ const (
 
 	terminationSize = 4
 	terminatorSize  = 3
+
+	headerSize      = 24
+	frameHeaderSize = 32
+	terminatorValue = 0xEE
 )
 
 type scanlineState int
@@ -246,6 +250,91 @@ loop: // this is a label for the loop, so the switch can break the loop (and not
 	return indexData
 }
 
+// EncodeFrame encodes the given indexed color texture, where index 0 is transparent, into the
+// frame at the given index, and updates the frame pointers. The offsets of an existing frame
+// are kept.
+func (d *DC6) EncodeFrame(frameIndex, width, height int, indexData []byte) {
+	frame := d.Frames[frameIndex]
+	if frame == nil {
+		frame = &DC6Frame{}
+		d.Frames[frameIndex] = frame
+	}
+
+	frame.Width = uint32(width)
+	frame.Height = uint32(height)
+	frame.FrameData = encodeFrameData(width, height, indexData)
+	frame.Length = uint32(len(frame.FrameData))
+	frame.Terminator = []byte{terminatorValue, terminatorValue, terminatorValue}
+
+	d.updateFramePointers()
+}
+
+// encodeFrameData is the inverse of DecodeFrame, scanlines are stored from the bottom up
+func encodeFrameData(width, height int, indexData []byte) []byte {
+	data := make([]byte, 0, len(indexData))
+
+	for y := height - 1; y >= 0; y-- {
+		line := indexData[y*width : (y+1)*width]
+
+		for x := 0; x < width; {
+			run := 0
+			for x+run < width && line[x+run] == 0 && run < maxRunLength {
+				run++
+			}
+
+			if run > 0 {
+				x += run
+
+				// trailing transparency is implied by the end of the scanline
+				if x < width && lineHasPixels(line[x:]) {
+					data = append(data, byte(endOfScanLine|run))
+				} else {
+					x = width
+				}
+
+				continue
+			}
+
+			for x+run < width && line[x+run] != 0 && run < maxRunLength {
+				run++
+			}
+
+			data = append(data, byte(run))
+			data = append(data, line[x:x+run]...)
+			x += run
+		}
+
+		data = append(data, endOfScanLine)
+	}
+
+	return data
+}
+
+func lineHasPixels(line []byte) bool {
+	for _, index := range line {
+		if index != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// updateFramePointers lays the frames out one after the other
+func (d *DC6) updateFramePointers() {
+	d.FramePointers = make([]uint32, len(d.Frames))
+	pointer := uint32(headerSize + len(d.Frames)*4) //nolint:gomnd // frame pointers are 4 bytes
+
+	for i, frame := range d.Frames {
+		d.FramePointers[i] = pointer
+
+		if frame != nil {
+			pointer += frameHeaderSize + frame.Length + terminatorSize
+			frame.NextBlock = pointer
+		}
+	}
+}
+
 func scanlineType(b int) scanlineState {
 	if b == endOfScanLine {
 		return endOfLine
@@ -1,6 +1,7 @@
 package d2dc6
 
 import (
+	"bytes"
 	"testing"
 )
 
@@ -67,3 +68,35 @@ func TestDC6Clone(t *testing.T) {
 		t.Fatal("cloned dc6 isn't equal to original")
 	}
 }
+
+func TestDC6EncodeFrame(t *testing.T) {
+	const width, height = 300, 3
+
+	dc6 := New()
+	dc6.Directions, dc6.FramesPerDirection = 1, 2
+	dc6.Frames = make([]*DC6Frame, 2)
+
+	// long opaque and transparent runs, transparent scanline ends and an empty scanline
+	indexData := make([]byte, width*height)
+	for x := 0; x < 200; x++ {
+		indexData[x] = byte(x%255 + 1)
+	}
+
+	indexData[width+250] = 7
+
+	dc6.EncodeFrame(0, width, height, indexData)
+	dc6.EncodeFrame(1, 1, 1, []byte{9})
+
+	decoded, err := Load(dc6.Marshal())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(decoded.DecodeFrame(0), indexData) {
+		t.Error("encoded frame decodes to different pixels")
+	}
+
+	if frame := decoded.DecodeFrame(1); len(frame) != 1 || frame[0] != 9 {
+		t.Errorf("second frame decodes to %v", frame)
+	}
+}
@@ -18,6 +18,12 @@ const (
 
 const cellsPerRow = 4
 
+// the bit counts the fields of the direction and frame headers can be encoded with
+// nolint:gochecknoglobals,gomnd // constant
+var crazyBitTable = []int{0, 1, 2, 4, 6, 8, 10, 12, 14, 16, 20, 24, 26, 28, 30, 32}
+
+const bitsPerByte = 8
+
 // DCCDirection represents a DCCDirection file.
 type DCCDirection struct {
 	OutSizeCoded               int
@@ -46,9 +52,6 @@ type DCCDirection struct {
 // CreateDCCDirection creates an instance of a DCCDirection.
 // nolint:funlen // no need to reduce
 func CreateDCCDirection(bm *d2datautils.BitMuncher, file *DCC) *DCCDirection {
-	// nolint:gomnd // constant
-	var crazyBitTable = []byte{0, 1, 2, 4, 6, 8, 10, 12, 14, 16, 20, 24, 26, 28, 30, 32}
-
 	result := &DCCDirection{
 		OutSizeCoded:     int(bm.GetUInt32()),
 		CompressionFlags: int(bm.GetBits(2)),                //nolint:gomnd // binary data
@@ -79,7 +82,7 @@ func CreateDCCDirection(bm *d2datautils.BitMuncher, file *DCC) *DCCDirection {
 	result.Box = d2geom.Rectangle{Left: minx, Top: miny, Width: maxx - minx, Height: maxy - miny}
 
 	if result.OptionalDataBits > 0 {
-		log.Panic("Optional bits in DCC data is not currently supported.")
+		result.readOptionalBytes(bm)
 	}
 
 	// nolint:gomnd // byte operation
@@ -150,6 +153,22 @@ func CreateDCCDirection(bm *d2datautils.BitMuncher, file *DCC) *DCCDirection {
 	return result
 }
 
+// readOptionalBytes reads the optional bytes of the frames, which follow the frame headers
+// from the next byte boundary
+func (v *DCCDirection) readOptionalBytes(bm *d2datautils.BitMuncher) {
+	if remainder := bm.Offset() % bitsPerByte; remainder != 0 {
+		bm.SkipBits(bitsPerByte - remainder)
+	}
+
+	for _, frame := range v.Frames {
+		frame.OptionalBytes = make([]byte, frame.NumberOfOptionalBytes)
+
+		for i := range frame.OptionalBytes {
+			frame.OptionalBytes[i] = bm.GetByte()
+		}
+	}
+}
+
 func (v *DCCDirection) verify(
 	equalCellsBitstream,
 	pixelMaskBitstream,
@@ -17,6 +17,7 @@ type DCCDirectionFrame struct {
 	XOffset               int
 	YOffset               int
 	NumberOfOptionalBytes int
+	OptionalBytes         []byte
 	NumberOfCodedBytes    int
 	HorizontalCellCount   int
 	VerticalCellCount     int
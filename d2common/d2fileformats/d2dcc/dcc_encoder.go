@@ -0,0 +1,786 @@
+package d2dcc
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2datautils"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2geom"
+)
+
+const (
+	dccVersion         = 6
+	dccFileHeaderSize  = 15
+	directionSizeBits  = 32
+	flagsBits          = 2
+	bitsCodeBits       = 4
+	bitstreamSizeBits  = 20
+	maxBitstreamSize   = 1<<bitstreamSizeBits - 1
+	pixelMaskBits      = 4
+	rawPixelBits       = 8
+	displacementBits   = 4
+	maxDisplacement    = 1<<displacementBits - 1
+	paletteSize        = 256
+	maxCellColors      = 4
+	fullPixelMask      = 0x0F
+	compressionRaw     = 0x1
+	compressionEqual   = 0x2
+	compressionOptions = 4
+)
+
+// ErrTooManyColors is returned when a cell of a frame holds more colors than DCC can encode
+var ErrTooManyColors = errors.New("cell holds more than 4 colors")
+
+// New creates a DCC from palettized frames, given by direction and frame. The PixelData of
+// each frame holds Width*Height palette indices from the top left of the frame, and the frame
+// is positioned by its XOffset and YOffset, the bottom left of the frame. The cells of a
+// frame, 4x4 pixels on the grid of the direction box, can hold at most 4 colors each.
+func New(directions [][]*DCCDirectionFrame) (*DCC, error) {
+	result := &DCC{
+		Signature:          dccFileSignature,
+		Version:            dccVersion,
+		NumberOfDirections: len(directions),
+		Directions:         make([]*DCCDirection, len(directions)),
+	}
+
+	if len(directions) > 0 {
+		result.FramesPerDirection = len(directions[0])
+	}
+
+	for dirIdx, frames := range directions {
+		if len(frames) != result.FramesPerDirection {
+			return nil, fmt.Errorf("direction %d has %d frames, expected %d", dirIdx, len(frames),
+				result.FramesPerDirection)
+		}
+
+		direction := &DCCDirection{Frames: make([]*DCCDirectionFrame, len(frames))}
+
+		for frameIdx, frame := range frames {
+			if len(frame.PixelData) != frame.Width*frame.Height {
+				return nil, fmt.Errorf("frame %d of direction %d has %d pixels, expected %d", frameIdx, dirIdx,
+					len(frame.PixelData), frame.Width*frame.Height)
+			}
+
+			direction.Frames[frameIdx] = &DCCDirectionFrame{
+				Width:                 frame.Width,
+				Height:                frame.Height,
+				XOffset:               frame.XOffset,
+				YOffset:               frame.YOffset,
+				NumberOfOptionalBytes: len(frame.OptionalBytes),
+				OptionalBytes:         frame.OptionalBytes,
+				Box:                   frameBox(frame),
+				valid:                 true,
+			}
+		}
+
+		direction.Box = directionBox(direction.Frames)
+
+		for frameIdx, frame := range direction.Frames {
+			frame.PixelData = make([]byte, direction.Box.Width*direction.Box.Height)
+			left, top := frame.Box.Left-direction.Box.Left, frame.Box.Top-direction.Box.Top
+
+			for y := 0; y < frame.Height; y++ {
+				copy(frame.PixelData[(top+y)*direction.Box.Width+left:],
+					frames[frameIdx].PixelData[y*frame.Width:(y+1)*frame.Width])
+			}
+		}
+
+		result.Directions[dirIdx] = direction
+	}
+
+	return result, nil
+}
+
+func frameBox(frame *DCCDirectionFrame) d2geom.Rectangle {
+	return d2geom.Rectangle{
+		Left:   frame.XOffset,
+		Top:    frame.YOffset - frame.Height + 1,
+		Width:  frame.Width,
+		Height: frame.Height,
+	}
+}
+
+// directionBox returns the box holding all frames, like the decoder computes it
+func directionBox(frames []*DCCDirectionFrame) d2geom.Rectangle {
+	minx, miny, maxx, maxy := baseMinx, baseMiny, baseMaxx, baseMaxy
+
+	for _, frame := range frames {
+		box := frameBox(frame)
+
+		if box.Left < minx {
+			minx = box.Left
+		}
+
+		if box.Top < miny {
+			miny = box.Top
+		}
+
+		if box.Right() > maxx {
+			maxx = box.Right()
+		}
+
+		if box.Bottom() > maxy {
+			maxy = box.Bottom()
+		}
+	}
+
+	return d2geom.Rectangle{Left: minx, Top: miny, Width: maxx - minx, Height: maxy - miny}
+}
+
+// Marshal encodes the DCC. The frames are encoded from their dimensions, offsets and their
+// PixelData in the box of their direction, as the decoder produces them.
+func (d *DCC) Marshal() ([]byte, error) {
+	directions := make([][]byte, len(d.Directions))
+	totalSize := 0
+
+	for dirIdx, direction := range d.Directions {
+		if len(direction.Frames) != d.FramesPerDirection {
+			return nil, fmt.Errorf("direction %d has %d frames, expected %d", dirIdx, len(direction.Frames),
+				d.FramesPerDirection)
+		}
+
+		data, err := direction.marshal()
+		if err != nil {
+			return nil, fmt.Errorf("direction %d: %w", dirIdx, err)
+		}
+
+		directions[dirIdx] = data
+		totalSize += len(data)
+	}
+
+	sw := d2datautils.CreateStreamWriter()
+
+	sw.PushBytes(dccFileSignature, byte(d.Version), byte(len(d.Directions)))
+	sw.PushInt32(int32(d.FramesPerDirection))
+	sw.PushInt32(1)
+	sw.PushInt32(int32(totalSize))
+
+	offset := dccFileHeaderSize + len(directions)*4 //nolint:gomnd // int32 size
+
+	for _, data := range directions {
+		sw.PushInt32(int32(offset))
+		offset += len(data)
+	}
+
+	for _, data := range directions {
+		sw.PushBytes(data...)
+	}
+
+	return sw.GetBytes(), nil
+}
+
+// marshal encodes the direction with each combination of compression flags, and returns the
+// smallest encoding
+func (v *DCCDirection) marshal() ([]byte, error) {
+	var result []byte
+
+	for flags := 0; flags < compressionOptions; flags++ {
+		encoder, err := newDirectionEncoder(v, flags)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := encoder.encodeCells(); err != nil {
+			return nil, err
+		}
+
+		data, err := encoder.encode()
+		if err != nil {
+			return nil, err
+		}
+
+		if result == nil || len(data) < len(result) {
+			result = data
+		}
+	}
+
+	return result, nil
+}
+
+// directionEncoder encodes a direction, keeping the same state as the decoder does while
+// decoding it
+type directionEncoder struct {
+	direction *DCCDirection
+	frames    []*DCCDirectionFrame
+	flags     int
+
+	// the palette entries used by the direction, and their index by palette entry
+	paletteEntries []byte
+	paletteIndex   [paletteSize]byte
+
+	// the pixel buffer entry of each cell of the direction, and if the cell was decoded yet
+	cellBuffer [][maxCellColors]byte
+	cellSeen   []bool
+	// the pixels of the direction, as palette indices
+	pixels []byte
+
+	equalCells    bitWriter
+	pixelMask     bitWriter
+	encodingType  bitWriter
+	rawPixelCodes bitWriter
+	displacement  bitWriter
+	pixelCodes    bitWriter
+}
+
+func newDirectionEncoder(direction *DCCDirection, flags int) (*directionEncoder, error) {
+	box := directionBox(direction.Frames)
+
+	e := &directionEncoder{
+		direction: &DCCDirection{Box: box},
+		frames:    make([]*DCCDirectionFrame, len(direction.Frames)),
+		flags:     flags,
+		pixels:    make([]byte, box.Width*box.Height),
+	}
+
+	e.direction.calculateCells()
+	e.cellBuffer = make([][maxCellColors]byte, len(e.direction.Cells))
+	e.cellSeen = make([]bool, len(e.direction.Cells))
+
+	var used [paletteSize]bool
+
+	used[0] = true
+
+	for frameIdx, frame := range direction.Frames {
+		if len(frame.PixelData) != box.Width*box.Height {
+			return nil, fmt.Errorf("frame %d has %d pixels, expected %d", frameIdx, len(frame.PixelData),
+				box.Width*box.Height)
+		}
+
+		if len(frame.OptionalBytes) != frame.NumberOfOptionalBytes {
+			return nil, fmt.Errorf("frame %d has %d optional bytes, expected %d", frameIdx,
+				len(frame.OptionalBytes), frame.NumberOfOptionalBytes)
+		}
+
+		scratch := *frame
+		scratch.Box = frameBox(frame)
+		scratch.recalculateCells(e.direction)
+		e.frames[frameIdx] = &scratch
+
+		for _, cell := range scratch.Cells {
+			for y := 0; y < cell.Height; y++ {
+				for x := 0; x < cell.Width; x++ {
+					used[frame.PixelData[e.pixelOffset(cell, x, y)]] = true
+				}
+			}
+		}
+	}
+
+	for color := range used {
+		if used[color] {
+			e.paletteIndex[color] = byte(len(e.paletteEntries))
+			e.paletteEntries = append(e.paletteEntries, byte(color))
+		}
+	}
+
+	return e, nil
+}
+
+func (e *directionEncoder) pixelOffset(cell DCCCell, x, y int) int {
+	return x + cell.XOffset + (y+cell.YOffset)*e.direction.Box.Width
+}
+
+// encodeCells encodes the cells of every frame, in the order the decoder reads them
+func (e *directionEncoder) encodeCells() error {
+	for frameIdx, frame := range e.frames {
+		originCellX := (frame.Box.Left - e.direction.Box.Left) / cellsPerRow
+		originCellY := (frame.Box.Top - e.direction.Box.Top) / cellsPerRow
+
+		for cellY := 0; cellY < frame.VerticalCellCount; cellY++ {
+			for cellX := 0; cellX < frame.HorizontalCellCount; cellX++ {
+				cell := frame.Cells[cellX+cellY*frame.HorizontalCellCount]
+				current := originCellX + cellX + (cellY+originCellY)*e.direction.HorizontalCellCount
+				bufferCell := e.direction.Cells[cell.XOffset/cellsPerRow+
+					(cell.YOffset/cellsPerRow)*e.direction.HorizontalCellCount]
+
+				target := make([]byte, 0, cell.Width*cell.Height)
+
+				for y := 0; y < cell.Height; y++ {
+					for x := 0; x < cell.Width; x++ {
+						target = append(target, e.paletteIndex[e.sourcePixel(frameIdx, cell, x, y)])
+					}
+				}
+
+				if err := e.encodeCell(current, cell, bufferCell, target); err != nil {
+					return fmt.Errorf("frame %d, cell %d,%d: %w", frameIdx, cellX, cellY, err)
+				}
+
+				bufferCell.LastWidth = cell.Width
+				bufferCell.LastHeight = cell.Height
+				bufferCell.LastXOffset = cell.XOffset
+				bufferCell.LastYOffset = cell.YOffset
+			}
+		}
+	}
+
+	return nil
+}
+
+func (e *directionEncoder) sourcePixel(frameIdx int, cell DCCCell, x, y int) byte {
+	return e.frames[frameIdx].PixelData[e.pixelOffset(cell, x, y)]
+}
+
+// encodeCell encodes a cell of a frame, either as equal to the last frame cell at the same
+// place, or with a pixel buffer entry holding its colors
+func (e *directionEncoder) encodeCell(current int, cell DCCCell, bufferCell *DCCCell, target []byte) error {
+	if e.cellSeen[current] && e.flags&compressionEqual != 0 {
+		equal := e.tryEqualCell(cell, bufferCell, target)
+		e.equalCells.pushBool(equal)
+
+		if equal {
+			return nil
+		}
+	}
+
+	colors := colorSet(target)
+	if len(colors) > maxCellColors {
+		return ErrTooManyColors
+	}
+
+	choice := e.chooseEntry(current, colors, len(target))
+	if choice == nil {
+		return ErrTooManyColors
+	}
+
+	if e.cellSeen[current] {
+		e.pixelMask.push(uint32(choice.mask), pixelMaskBits)
+	}
+
+	if count := bits.OnesCount(uint(choice.mask)); count > 0 {
+		e.pushPixelValues(choice, count)
+	}
+
+	e.cellBuffer[current] = choice.values
+	e.cellSeen[current] = true
+
+	for y := 0; y < cell.Height; y++ {
+		for x := 0; x < cell.Width; x++ {
+			pixel := target[x+y*cell.Width]
+			e.pixels[e.pixelOffset(cell, x, y)] = pixel
+
+			if choice.codeBits == 0 {
+				continue
+			}
+
+			for code := 0; code < 1<<choice.codeBits; code++ {
+				if choice.values[code] == pixel {
+					e.pixelCodes.push(uint32(code), choice.codeBits)
+					break
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// tryEqualCell returns true if the decoder produces the target pixels for the cell when it's
+// marked as equal, and updates the pixels of the direction like the decoder does
+func (e *directionEncoder) tryEqualCell(cell DCCCell, bufferCell *DCCCell, target []byte) bool {
+	saved := make([]byte, 0, len(target))
+
+	for y := 0; y < cell.Height; y++ {
+		for x := 0; x < cell.Width; x++ {
+			saved = append(saved, e.pixels[e.pixelOffset(cell, x, y)])
+		}
+	}
+
+	sameSize := cell.Width == bufferCell.LastWidth && cell.Height == bufferCell.LastHeight
+	equal := true
+
+	for y := 0; y < cell.Height; y++ {
+		for x := 0; x < cell.Width; x++ {
+			var pixel byte
+
+			if sameSize {
+				pixel = e.pixels[x+bufferCell.LastXOffset+(y+bufferCell.LastYOffset)*e.direction.Box.Width]
+				e.pixels[e.pixelOffset(cell, x, y)] = pixel
+			} else {
+				// cells of a different size are cleared, and left empty in the frame
+				e.pixels[e.pixelOffset(cell, x, y)] = 0
+			}
+
+			equal = equal && pixel == target[x+y*cell.Width]
+		}
+	}
+
+	if !equal {
+		for y := 0; y < cell.Height; y++ {
+			for x := 0; x < cell.Width; x++ {
+				e.pixels[e.pixelOffset(cell, x, y)] = saved[x+y*cell.Width]
+			}
+		}
+	}
+
+	return equal
+}
+
+// cellEntry is a pixel buffer entry for a cell, with how its values are encoded
+type cellEntry struct {
+	mask     int
+	values   [maxCellColors]byte
+	decoded  []byte // the new values, in the order they are decoded
+	raw      bool
+	codeBits int
+	cost     int
+}
+
+// chooseEntry returns the cheapest pixel buffer entry that decodes to the colors of the cell.
+// The decoder replaces the values of the entry selected by the pixel mask with the decoded
+// values, from the highest to the lowest, followed by zeros.
+func (e *directionEncoder) chooseEntry(current int, colors []byte, pixelCount int) *cellEntry {
+	masks := []int{fullPixelMask}
+	if e.cellSeen[current] {
+		masks = masks[:0]
+		for mask := 0; mask <= fullPixelMask; mask++ {
+			masks = append(masks, mask)
+		}
+	}
+
+	nonZero := make([]byte, 0, len(colors))
+
+	for _, color := range colors {
+		if color != 0 {
+			nonZero = append(nonZero, color)
+		}
+	}
+
+	var best *cellEntry
+
+	for _, mask := range masks {
+		count := bits.OnesCount(uint(mask))
+
+		for subset := 0; subset < 1<<len(nonZero); subset++ {
+			if bits.OnesCount(uint(subset)) > count {
+				continue
+			}
+
+			entry := &cellEntry{mask: mask, values: e.cellBuffer[current]}
+
+			for idx, color := range nonZero {
+				if subset&(1<<idx) != 0 {
+					entry.decoded = append(entry.decoded, color)
+				}
+			}
+
+			next := len(entry.decoded) - 1
+
+			for idx := 0; idx < maxCellColors; idx++ {
+				if mask&(1<<idx) == 0 {
+					continue
+				}
+
+				entry.values[idx] = 0
+
+				if next >= 0 {
+					entry.values[idx] = entry.decoded[next]
+					next--
+				}
+			}
+
+			if !entry.decodesTo(colors) {
+				continue
+			}
+
+			entry.cost = entry.codeBits * pixelCount
+
+			if e.cellSeen[current] {
+				entry.cost += pixelMaskBits
+			}
+
+			if count > 0 {
+				entry.cost += e.valuesCost(entry, count)
+			}
+
+			if best == nil || entry.cost < best.cost {
+				best = entry
+			}
+		}
+	}
+
+	return best
+}
+
+// decodesTo sets the pixel code bits the decoder uses for the entry, and returns true if all
+// colors can be coded with them
+func (c *cellEntry) decodesTo(colors []byte) bool {
+	var available []byte
+
+	switch {
+	case c.values[0] == c.values[1]:
+		c.codeBits = 0
+		available = c.values[:1]
+	case c.values[1] == c.values[2]:
+		c.codeBits = 1
+		available = c.values[:2]
+	default:
+		c.codeBits = 2
+		available = c.values[:]
+	}
+
+	for _, color := range colors {
+		found := false
+
+		for _, value := range available {
+			found = found || value == color
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// valuesCost returns the bits needed for the decoded values of the entry, and chooses
+// between raw and displacement coding
+func (e *directionEncoder) valuesCost(entry *cellEntry, count int) int {
+	terminated := len(entry.decoded) < count
+	displacementCost := 0
+	last := 0
+
+	for _, value := range entry.decoded {
+		displacementCost += displacementBits * (1 + (int(value)-last)/maxDisplacement)
+		last = int(value)
+	}
+
+	rawCost := rawPixelBits * len(entry.decoded)
+
+	if terminated {
+		displacementCost += displacementBits
+		rawCost += rawPixelBits
+	}
+
+	if e.flags&compressionRaw == 0 {
+		return displacementCost
+	}
+
+	entry.raw = rawCost < displacementCost
+
+	if entry.raw {
+		return 1 + rawCost
+	}
+
+	return 1 + displacementCost
+}
+
+func (e *directionEncoder) pushPixelValues(entry *cellEntry, count int) {
+	if e.flags&compressionRaw != 0 {
+		e.encodingType.pushBool(entry.raw)
+	}
+
+	last := 0
+
+	for _, value := range entry.decoded {
+		if entry.raw {
+			e.rawPixelCodes.push(uint32(value), rawPixelBits)
+		} else {
+			for displacement := int(value) - last; ; displacement -= maxDisplacement {
+				if displacement < maxDisplacement {
+					e.displacement.push(uint32(displacement), displacementBits)
+					break
+				}
+
+				e.displacement.push(maxDisplacement, displacementBits)
+			}
+		}
+
+		last = int(value)
+	}
+
+	// a value equal to the last one ends the values early
+	if len(entry.decoded) < count {
+		if entry.raw {
+			e.rawPixelCodes.push(uint32(last), rawPixelBits)
+		} else {
+			e.displacement.push(0, displacementBits)
+		}
+	}
+}
+
+// encode encodes the direction header, the frame headers and the bitstreams
+//
+//nolint:funlen // follows the layout of the direction
+func (e *directionEncoder) encode() ([]byte, error) {
+	var widthBits, heightBits, xOffsetBits, yOffsetBits, optionalBits int
+
+	for _, frame := range e.frames {
+		widthBits = maxInt(widthBits, unsignedBits(frame.Width))
+		heightBits = maxInt(heightBits, unsignedBits(frame.Height))
+		xOffsetBits = maxInt(xOffsetBits, signedBits(frame.XOffset))
+		yOffsetBits = maxInt(yOffsetBits, signedBits(frame.YOffset))
+		optionalBits = maxInt(optionalBits, unsignedBits(frame.NumberOfOptionalBytes))
+	}
+
+	codes := []int{0, bitsCode(widthBits), bitsCode(heightBits), bitsCode(xOffsetBits), bitsCode(yOffsetBits),
+		bitsCode(optionalBits), 0}
+
+	for _, code := range codes {
+		if code < 0 {
+			return nil, errors.New("frame dimensions or offsets are too large")
+		}
+	}
+
+	w := &bitWriter{}
+
+	w.push(0, directionSizeBits) // the size of the direction, set below
+	w.push(uint32(e.flags), flagsBits)
+
+	for _, code := range codes {
+		w.push(uint32(code), bitsCodeBits)
+	}
+
+	for _, frame := range e.frames {
+		w.push(uint32(frame.Width), crazyBitTable[codes[1]])
+		w.push(uint32(frame.Height), crazyBitTable[codes[2]])
+		w.push(uint32(frame.XOffset), crazyBitTable[codes[3]])
+		w.push(uint32(frame.YOffset), crazyBitTable[codes[4]])
+		w.push(uint32(frame.NumberOfOptionalBytes), crazyBitTable[codes[5]])
+		w.pushBool(false) // top down
+	}
+
+	if optionalBits > 0 {
+		w.align()
+
+		for _, frame := range e.frames {
+			for _, b := range frame.OptionalBytes {
+				w.push(uint32(b), bitsPerByte)
+			}
+		}
+	}
+
+	for _, stream := range []*bitWriter{&e.equalCells, &e.pixelMask, &e.encodingType, &e.rawPixelCodes} {
+		if stream.bits > maxBitstreamSize {
+			return nil, errors.New("direction is too large")
+		}
+	}
+
+	if e.flags&compressionEqual != 0 {
+		w.push(uint32(e.equalCells.bits), bitstreamSizeBits)
+	}
+
+	w.push(uint32(e.pixelMask.bits), bitstreamSizeBits)
+
+	if e.flags&compressionRaw != 0 {
+		w.push(uint32(e.encodingType.bits), bitstreamSizeBits)
+		w.push(uint32(e.rawPixelCodes.bits), bitstreamSizeBits)
+	}
+
+	var used [paletteSize]bool
+	for _, color := range e.paletteEntries {
+		used[color] = true
+	}
+
+	for _, isUsed := range used {
+		w.pushBool(isUsed)
+	}
+
+	for _, stream := range []*bitWriter{&e.equalCells, &e.pixelMask, &e.encodingType, &e.rawPixelCodes,
+		&e.displacement, &e.pixelCodes} {
+		w.append(stream)
+	}
+
+	w.align()
+
+	data := w.data
+	size := len(data)
+
+	for i := 0; i < directionSizeBits/bitsPerByte; i++ {
+		data[i] = byte(size >> (i * bitsPerByte))
+	}
+
+	return data, nil
+}
+
+// colorSet returns the distinct values of the pixels, in ascending order
+func colorSet(pixels []byte) []byte {
+	var found [paletteSize]bool
+
+	for _, pixel := range pixels {
+		found[pixel] = true
+	}
+
+	colors := make([]byte, 0, maxCellColors)
+
+	for color := range found {
+		if found[color] {
+			colors = append(colors, byte(color))
+		}
+	}
+
+	return colors
+}
+
+// bitsCode returns the code of the smallest bit count in the bit table that holds the given
+// number of bits, or -1 if there is none
+func bitsCode(needed int) int {
+	for code, count := range crazyBitTable {
+		if count >= needed {
+			return code
+		}
+	}
+
+	return -1
+}
+
+func unsignedBits(value int) int {
+	return bits.Len(uint(value))
+}
+
+// signedBits returns the bits needed for the value in two's complement, a single bit holds 0
+// and -1
+func signedBits(value int) int {
+	if value == 0 {
+		return 0
+	}
+
+	if value < 0 {
+		return bits.Len(uint(^value)) + 1
+	}
+
+	return bits.Len(uint(value)) + 1
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
+// bitWriter writes values of any bit count, least significant bit first, as the bit
+// muncher reads them
+type bitWriter struct {
+	data []byte
+	bits int
+}
+
+func (w *bitWriter) push(value uint32, count int) {
+	for i := 0; i < count; i++ {
+		w.pushBool(value&(1<<uint(i)) != 0)
+	}
+}
+
+func (w *bitWriter) pushBool(bit bool) {
+	if w.bits%bitsPerByte == 0 {
+		w.data = append(w.data, 0)
+	}
+
+	if bit {
+		w.data[w.bits/bitsPerByte] |= 1 << uint(w.bits%bitsPerByte)
+	}
+
+	w.bits++
+}
+
+func (w *bitWriter) append(other *bitWriter) {
+	for i := 0; i < other.bits; i++ {
+		w.pushBool(other.data[i/bitsPerByte]&(1<<uint(i%bitsPerByte)) != 0)
+	}
+}
+
+func (w *bitWriter) align() {
+	w.bits = len(w.data) * bitsPerByte
+}
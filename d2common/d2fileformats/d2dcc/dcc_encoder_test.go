@@ -0,0 +1,145 @@
+package d2dcc
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2geom"
+)
+
+// testFrame creates a frame whose cells, laid out in a direction box with the given top left,
+// hold at most 4 colors: transparency and 3 colors picked for the cell
+func testFrame(random *rand.Rand, originX, originY, xOffset, yOffset, width, height int) *DCCDirectionFrame {
+	frame := &DCCDirectionFrame{
+		Width:     width,
+		Height:    height,
+		XOffset:   xOffset,
+		YOffset:   yOffset,
+		PixelData: make([]byte, width*height),
+	}
+
+	frame.Box = frameBox(frame)
+	frame.recalculateCells(&DCCDirection{Box: d2geom.Rectangle{Left: originX, Top: originY}})
+
+	for _, cell := range frame.Cells {
+		colors := [3]byte{byte(random.Intn(256)), byte(random.Intn(256)), byte(random.Intn(16))}
+		left, top := cell.XOffset-(frame.Box.Left-originX), cell.YOffset-(frame.Box.Top-originY)
+
+		for y := top; y < top+cell.Height; y++ {
+			for x := left; x < left+cell.Width; x++ {
+				if pick := random.Intn(4); pick < 3 {
+					frame.PixelData[x+y*width] = colors[pick]
+				}
+			}
+		}
+	}
+
+	frame.Cells = nil
+
+	return frame
+}
+
+// framePixels returns the pixels of a frame decoded into the box of its direction, cropped
+// to the frame
+func framePixels(direction *DCCDirection, frame *DCCDirectionFrame) []byte {
+	pixels := make([]byte, 0, frame.Width*frame.Height)
+	left, top := frame.Box.Left-direction.Box.Left, frame.Box.Top-direction.Box.Top
+
+	for y := 0; y < frame.Height; y++ {
+		offset := (top+y)*direction.Box.Width + left
+		pixels = append(pixels, frame.PixelData[offset:offset+frame.Width]...)
+	}
+
+	return pixels
+}
+
+func TestDCCMarshalRoundTrip(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+
+	// the origin is the top left of the direction box, the first frame covers it
+	directions := make([][]*DCCDirectionFrame, 2)
+
+	for dirIdx := range directions {
+		first := testFrame(random, -20, -60, -20, -1, 37, 60)
+		still := *first
+		moved := testFrame(random, -20, -60, -13, 5, 21, 40)
+
+		directions[dirIdx] = []*DCCDirectionFrame{first, &still, moved}
+	}
+
+	directions[1][2].OptionalBytes = []byte{1, 2, 3}
+
+	dcc, err := New(directions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := dcc.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := Load(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.NumberOfDirections != 2 || decoded.FramesPerDirection != 3 {
+		t.Fatalf("want 2 directions with 3 frames, have %d with %d", decoded.NumberOfDirections,
+			decoded.FramesPerDirection)
+	}
+
+	for dirIdx, direction := range decoded.Directions {
+		for frameIdx, frame := range direction.Frames {
+			input := directions[dirIdx][frameIdx]
+
+			if frame.Width != input.Width || frame.Height != input.Height ||
+				frame.XOffset != input.XOffset || frame.YOffset != input.YOffset {
+				t.Errorf("direction %d frame %d: dimensions changed", dirIdx, frameIdx)
+			}
+
+			if !bytes.Equal(framePixels(direction, frame), input.PixelData) {
+				t.Errorf("direction %d frame %d: pixels changed", dirIdx, frameIdx)
+			}
+
+			if !bytes.Equal(frame.OptionalBytes, input.OptionalBytes) {
+				t.Errorf("direction %d frame %d: optional bytes changed", dirIdx, frameIdx)
+			}
+		}
+	}
+
+	// a decoded DCC encodes to the same frames again
+	encoded, err := decoded.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	redecoded, err := Load(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for dirIdx, direction := range redecoded.Directions {
+		for frameIdx, frame := range direction.Frames {
+			if !bytes.Equal(frame.PixelData, decoded.Directions[dirIdx].Frames[frameIdx].PixelData) {
+				t.Errorf("direction %d frame %d: pixels changed after encoding a decoded DCC", dirIdx, frameIdx)
+			}
+		}
+	}
+}
+
+func TestDCCMarshalTooManyColors(t *testing.T) {
+	frame := &DCCDirectionFrame{Width: 4, Height: 4, PixelData: []byte{1, 2, 3, 4, 5}}
+	frame.PixelData = append(frame.PixelData, make([]byte, 11)...)
+
+	dcc, err := New([][]*DCCDirectionFrame{{frame}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := dcc.Marshal(); !errors.Is(err, ErrTooManyColors) {
+		t.Errorf("want ErrTooManyColors, have %v", err)
+	}
+}
@@ -99,9 +99,11 @@ func (mpq *MPQ) ReadFile(fileName string) ([]byte, error) {
 		return []byte{}, err
 	}
 
-	fileBlockData.FileName = strings.ToLower(fileName)
+	// the stream gets its own copy of the block, as it sets the file name and encryption seed
+	block := *fileBlockData
+	block.FileName = strings.ToLower(fileName)
 
-	stream, err := CreateStream(mpq, fileBlockData, fileName)
+	stream, err := CreateStream(mpq, &block, fileName)
 	if err != nil {
 		return []byte{}, err
 	}
@@ -121,9 +123,11 @@ func (mpq *MPQ) ReadFileStream(fileName string) (d2interface.DataStream, error)
 		return nil, err
 	}
 
-	fileBlockData.FileName = strings.ToLower(fileName)
+	// the stream gets its own copy of the block, as it sets the file name and encryption seed
+	block := *fileBlockData
+	block.FileName = strings.ToLower(fileName)
 
-	stream, err := CreateStream(mpq, fileBlockData, fileName)
+	stream, err := CreateStream(mpq, &block, fileName)
 	if err != nil {
 		return nil, err
 	}
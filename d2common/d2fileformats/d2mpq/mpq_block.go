@@ -49,8 +49,11 @@ func (b *Block) HasFlag(flag FileFlag) bool {
 
 func (b *Block) calculateEncryptionSeed(fileName string) {
 	fileName = fileName[strings.LastIndex(fileName, `\`)+1:]
-	seed := hashString(fileName, 3)
-	b.EncryptionSeed = (seed + b.FilePosition) ^ b.UncompressedFileSize
+	b.EncryptionSeed = hashString(fileName, 3)
+
+	if b.HasFlag(FileFixKey) {
+		b.EncryptionSeed = (b.EncryptionSeed + b.FilePosition) ^ b.UncompressedFileSize
+	}
 }
 
 //nolint:gomnd // number
@@ -2,6 +2,7 @@ package d2mpq
 
 import (
 	"bytes"
+	"compress/bzip2"
 	"compress/zlib"
 	"encoding/binary"
 	"errors"
@@ -33,7 +34,7 @@ func CreateStream(mpq *MPQ, block *Block, fileName string) (*Stream, error) {
 		Index: 0xFFFFFFFF, //nolint:gomnd // MPQ magic
 	}
 
-	if s.Block.HasFlag(FileFixKey) {
+	if s.Block.HasFlag(FileEncrypted) {
 		s.Block.calculateEncryptionSeed(fileName)
 	}
 
@@ -53,14 +54,14 @@ func CreateStream(mpq *MPQ, block *Block, fileName string) (*Stream, error) {
 }
 
 func (v *Stream) loadBlockOffsets() error {
-	if _, err := v.MPQ.file.Seek(int64(v.Block.FilePosition), io.SeekStart); err != nil {
-		return err
-	}
-
 	blockPositionCount := ((v.Block.UncompressedFileSize + v.Size - 1) / v.Size) + 1
 	v.Positions = make([]uint32, blockPositionCount)
 
-	if err := binary.Read(v.MPQ.file, binary.LittleEndian, &v.Positions); err != nil {
+	// the file is read at an offset, instead of seeking the shared file, so that streams of an
+	// MPQ can be read concurrently
+	positions := io.NewSectionReader(v.MPQ.file, int64(v.Block.FilePosition), int64(blockPositionCount)*4) //nolint:gomnd // uint32
+
+	if err := binary.Read(positions, binary.LittleEndian, &v.Positions); err != nil {
 		return err
 	}
 
@@ -155,13 +156,9 @@ func (v *Stream) bufferData() (err error) {
 }
 
 func (v *Stream) loadSingleUnit() (err error) {
-	if _, err = v.MPQ.file.Seek(int64(v.MPQ.header.HeaderSize), io.SeekStart); err != nil {
-		return err
-	}
-
 	fileData := make([]byte, v.Size)
 
-	if _, err = v.MPQ.file.Read(fileData); err != nil {
+	if _, err = v.MPQ.file.ReadAt(fileData, int64(v.MPQ.header.HeaderSize)); err != nil {
 		return err
 	}
 
@@ -192,11 +189,7 @@ func (v *Stream) loadBlock(blockIndex, expectedLength uint32) ([]byte, error) {
 	offset += v.Block.FilePosition
 	data := make([]byte, toRead)
 
-	if _, err := v.MPQ.file.Seek(int64(offset), io.SeekStart); err != nil {
-		return []byte{}, err
-	}
-
-	if _, err := v.MPQ.file.Read(data); err != nil {
+	if _, err := v.MPQ.file.ReadAt(data, int64(offset)); err != nil {
 		return []byte{}, err
 	}
 
@@ -224,31 +217,41 @@ func (v *Stream) loadBlock(blockIndex, expectedLength uint32) ([]byte, error) {
 }
 
 //nolint:gomnd,funlen,gocyclo // Will fix enum values later, can't help function length
-func decompressMulti(data []byte /*expectedLength*/, _ uint32) ([]byte, error) {
+func decompressMulti(data []byte, expectedLength uint32) ([]byte, error) {
 	compressionType := data[0]
 
 	switch compressionType {
 	case 1: // Huffman
-		return []byte{}, errors.New("huffman decompression not supported")
+		return d2compression.HuffmanDecompress(data[1:]), nil
 	case 2: // ZLib/Deflate
 		return deflate(data[1:])
 	case 8: // PKLib/Impode
 		return pkDecompress(data[1:])
 	case 0x10: // BZip2
-		return []byte{}, errors.New("bzip2 decompression not supported")
+		return bzip2Decompress(data[1:])
 	case 0x80: // IMA ADPCM Stereo
 		return d2compression.WavDecompress(data[1:], 2)
 	case 0x40: // IMA ADPCM Mono
 		return d2compression.WavDecompress(data[1:], 1)
-	case 0x12:
-		return []byte{}, errors.New("lzma decompression not supported")
+	case 0x12: // LZMA
+		return lzmaDecompress(data[1:], expectedLength)
 	// Combos
 	case 0x22:
 		// sparse then zlib
-		return []byte{}, errors.New("sparse decompression + deflate decompression not supported")
+		sinput, err := deflate(data[1:])
+		if err != nil {
+			return nil, err
+		}
+
+		return d2compression.SparseDecompress(sinput)
 	case 0x30:
 		// sparse then bzip2
-		return []byte{}, errors.New("sparse decompression + bzip2 decompression not supported")
+		sinput, err := bzip2Decompress(data[1:])
+		if err != nil {
+			return nil, err
+		}
+
+		return d2compression.SparseDecompress(sinput)
 	case 0x41:
 		sinput, err := d2compression.WavDecompress(d2compression.HuffmanDecompress(data[1:]), 1)
 		if err != nil {
@@ -261,9 +264,13 @@ func decompressMulti(data []byte /*expectedLength*/, _ uint32) ([]byte, error) {
 
 		return tmp, nil
 	case 0x48:
-		// byte[] result = PKDecompress(sinput, outputLength);
-		// return MpqWavCompression.Decompress(new MemoryStream(result), 1);
-		return []byte{}, errors.New("pk + mpqwav decompression not supported")
+		// mono wav then pk
+		sinput, err := pkDecompress(data[1:])
+		if err != nil {
+			return nil, err
+		}
+
+		return d2compression.WavDecompress(sinput, 1)
 	case 0x81:
 		sinput, err := d2compression.WavDecompress(d2compression.HuffmanDecompress(data[1:]), 2)
 		if err != nil {
@@ -275,9 +282,13 @@ func decompressMulti(data []byte /*expectedLength*/, _ uint32) ([]byte, error) {
 
 		return tmp, nil
 	case 0x88:
-		// byte[] result = PKDecompress(sinput, outputLength);
-		// return MpqWavCompression.Decompress(new MemoryStream(result), 2);
-		return []byte{}, errors.New("pk + wav decompression not supported")
+		// stereo wav then pk
+		sinput, err := pkDecompress(data[1:])
+		if err != nil {
+			return nil, err
+		}
+
+		return d2compression.WavDecompress(sinput, 2)
 	}
 
 	return []byte{}, fmt.Errorf("decompression not supported for unknown compression type %X", compressionType)
@@ -306,6 +317,26 @@ func deflate(data []byte) ([]byte, error) {
 	return buffer.Bytes(), nil
 }
 
+func bzip2Decompress(data []byte) ([]byte, error) {
+	buffer := new(bytes.Buffer)
+
+	if _, err := buffer.ReadFrom(bzip2.NewReader(bytes.NewReader(data))); err != nil {
+		return []byte{}, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// lzmaDecompress decompresses LZMA data, which starts with a filter byte followed by the
+// header of the .lzma format
+func lzmaDecompress(data []byte, expectedLength uint32) ([]byte, error) {
+	if len(data) == 0 || data[0] != 0 {
+		return []byte{}, errors.New("lzma filters are not supported")
+	}
+
+	return d2compression.LZMADecompress(data[1:], int(expectedLength))
+}
+
 func pkDecompress(data []byte) ([]byte, error) {
 	b := bytes.NewReader(data)
 
@@ -0,0 +1,65 @@
+package d2mpq
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+const compressionTestdata = "testdata/compression"
+
+func TestDecompressMulti(t *testing.T) {
+	// the compressed fixture for each compression mask, and the data it decompresses to
+	tests := []struct {
+		mask     byte
+		expected string
+	}{
+		{0x01, "plain.bin"},  // Huffman
+		{0x02, "plain.bin"},  // zlib
+		{0x08, "plain.bin"},  // PKWARE implode
+		{0x10, "plain.bin"},  // bzip2
+		{0x12, "plain.bin"},  // LZMA
+		{0x22, "plain.bin"},  // sparse, zlib
+		{0x30, "plain.bin"},  // sparse, bzip2
+		{0x40, "mono.out"},   // IMA ADPCM mono
+		{0x41, "mono.out"},   // IMA ADPCM mono, Huffman
+		{0x48, "mono.out"},   // IMA ADPCM mono, PKWARE implode
+		{0x80, "stereo.out"}, // IMA ADPCM stereo
+		{0x81, "stereo.out"}, // IMA ADPCM stereo, Huffman
+		{0x88, "stereo.out"}, // IMA ADPCM stereo, PKWARE implode
+	}
+
+	for _, test := range tests {
+		compressed, err := ioutil.ReadFile(filepath.Join(compressionTestdata, fmt.Sprintf("%02x.bin", test.mask)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected, err := ioutil.ReadFile(filepath.Join(compressionTestdata, test.expected))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if compressed[0] != test.mask {
+			t.Fatalf("fixture of mask %02X starts with mask %02X", test.mask, compressed[0])
+		}
+
+		decompressed, err := decompressMulti(compressed, uint32(len(expected)))
+		if err != nil {
+			t.Errorf("mask %02X: %v", test.mask, err)
+			continue
+		}
+
+		if !bytes.Equal(decompressed, expected) {
+			t.Errorf("mask %02X: decompressed data differs from %s", test.mask, test.expected)
+		}
+	}
+}
+
+func TestDecompressMultiUnknown(t *testing.T) {
+	if _, err := decompressMulti([]byte{0x03, 0x00}, 1); err == nil {
+		t.Error("unknown compression mask didn't fail")
+	}
+}
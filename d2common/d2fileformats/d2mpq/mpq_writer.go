@@ -0,0 +1,580 @@
+package d2mpq
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/md5" //nolint:gosec // md5 is what the attributes format uses
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/JoshVarga/blast"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2compression"
+)
+
+const (
+	listfileName   = "(listfile)"
+	attributesName = "(attributes)"
+
+	headerSize        = 32
+	writerSectorShift = 3 // sectors of 0x200 << 3 = 4096 bytes
+	minHashTableSize  = 16
+
+	hashTableEmpty = 0xFFFFFFFF
+
+	compressionMaskZlib = 2
+	// sectors of wav files are compressed with IMA ADPCM and then huffman
+	compressionMaskWavMono   = 0x41
+	compressionMaskWavStereo = 0x81
+	// the huffman tree of ADPCM data is chosen by the ADPCM level, like the original archives
+	wavHuffmanTypeOffset = 2
+
+	attributesVersion = 100
+	attributesCRC32   = 1
+	attributesMD5     = 4
+)
+
+var _ io.WriterTo = &Writer{} // Static check to confirm struct conforms to interface
+
+// Compression is the compression of a file in an MPQ archive
+type Compression int
+
+// Compressions of files written to an MPQ archive
+const (
+	CompressionNone    Compression = iota // the file is stored as is
+	CompressionZlib                       // each sector is compressed with zlib
+	CompressionImplode                    // each sector is compressed with PKWARE implode
+	// the samples of a 16 bit PCM wav file are compressed lossy with IMA ADPCM and huffman, the
+	// first sector with the wav header is compressed with zlib
+	CompressionWavMono
+	CompressionWavStereo
+)
+
+// FileOptions are the options a file is written to an MPQ archive with
+type FileOptions struct {
+	Compression Compression
+	// Encrypted files are encrypted with a key derived from the file name
+	Encrypted bool
+	// FixKey adjusts the encryption key by the position and size of the file in the archive,
+	// only used for encrypted files
+	FixKey bool
+}
+
+type writerFile struct {
+	name    string
+	data    []byte
+	options FileOptions
+}
+
+// Writer creates MPQ v1 archives. Files are added in memory and written when the archive
+// is saved, along with a generated listfile and attributes file.
+type Writer struct {
+	files map[uint64]*writerFile
+	// Listfile enables writing the (listfile) with the names of the files
+	Listfile bool
+	// Attributes enables writing the (attributes) with the CRC32 and MD5 of the files
+	Attributes bool
+}
+
+// NewWriter creates a writer for a new, empty MPQ archive
+func NewWriter() *Writer {
+	return &Writer{
+		files:      make(map[uint64]*writerFile),
+		Listfile:   true,
+		Attributes: true,
+	}
+}
+
+// NewWriterFromFile creates a writer with the files of an existing MPQ archive, to append
+// to or replace files in it. The files are found through the listfile of the archive, so
+// archives with files missing from their listfile can't be edited.
+func NewWriterFromFile(fileName string) (*Writer, error) {
+	mpq, err := FromFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = mpq.Close()
+	}()
+
+	fileNames, err := mpq.Listfile()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read listfile: %v", err)
+	}
+
+	w := NewWriter()
+	found := make(map[uint64]bool)
+
+	for _, name := range fileNames {
+		if name == "" || !mpq.Contains(name) || found[hashFilename(name)] {
+			continue
+		}
+
+		found[hashFilename(name)] = true
+
+		if name == listfileName || name == attributesName {
+			continue
+		}
+
+		block, err := mpq.getFileBlockData(name)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := mpq.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", name, err)
+		}
+
+		if err := w.AddFile(name, data, optionsOf(block)); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, special := range []string{listfileName, attributesName} {
+		if mpq.Contains(special) {
+			found[hashFilename(special)] = true
+		}
+	}
+
+	for key, hash := range mpq.hashes {
+		if hash.BlockIndex >= uint32(len(mpq.blocks)) || !mpq.blocks[hash.BlockIndex].HasFlag(FileExists) {
+			continue
+		}
+
+		if !found[key] {
+			return nil, errors.New("archive contains files which are not in its listfile")
+		}
+	}
+
+	return w, nil
+}
+
+func optionsOf(block *Block) FileOptions {
+	options := FileOptions{
+		Encrypted: block.HasFlag(FileEncrypted),
+		FixKey:    block.HasFlag(FileFixKey),
+	}
+
+	switch {
+	case block.HasFlag(FileImplode):
+		options.Compression = CompressionImplode
+	case block.HasFlag(FileCompress):
+		options.Compression = CompressionZlib
+	}
+
+	return options
+}
+
+// AddFile adds a file to the archive, a file with the same name is replaced
+func (w *Writer) AddFile(fileName string, data []byte, options FileOptions) error {
+	if fileName == "" {
+		return errors.New("file name is empty")
+	}
+
+	if fileName == listfileName || fileName == attributesName {
+		return fmt.Errorf("%s is generated by the writer", fileName)
+	}
+
+	switch options.Compression {
+	case CompressionNone, CompressionZlib, CompressionImplode, CompressionWavMono, CompressionWavStereo:
+	default:
+		return fmt.Errorf("unknown compression %d", options.Compression)
+	}
+
+	w.files[hashFilename(fileName)] = &writerFile{name: fileName, data: data, options: options}
+
+	return nil
+}
+
+// RemoveFile removes a file from the archive, returns false if there was no such file
+func (w *Writer) RemoveFile(fileName string) bool {
+	key := hashFilename(fileName)
+
+	if _, found := w.files[key]; !found {
+		return false
+	}
+
+	delete(w.files, key)
+
+	return true
+}
+
+// Contains returns true if the archive contains the file
+func (w *Writer) Contains(fileName string) bool {
+	_, found := w.files[hashFilename(fileName)]
+	return found
+}
+
+// Save writes the archive to the file. The archive is written to a temporary file first, so
+// an archive the writer was created from can be replaced.
+func (w *Writer) Save(fileName string) error {
+	data, err := w.Marshal()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(fileName), filepath.Base(fileName)+".*")
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), fileName)
+}
+
+// Marshal encodes the archive
+func (w *Writer) Marshal() ([]byte, error) {
+	files := w.sortedFiles()
+
+	if w.Listfile {
+		names := make([]string, len(files))
+		for idx := range files {
+			names[idx] = files[idx].name
+		}
+
+		files = append(files, &writerFile{
+			name:    listfileName,
+			data:    []byte(strings.Join(names, "\r\n")),
+			options: FileOptions{Compression: CompressionZlib},
+		})
+	}
+
+	buffer := &bytes.Buffer{}
+	buffer.Write(make([]byte, headerSize))
+
+	blocks := make([]*Block, 0, len(files)+1)
+
+	for _, file := range files {
+		block, err := writeFileData(buffer, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write %s: %v", file.name, err)
+		}
+
+		blocks = append(blocks, block)
+	}
+
+	if w.Attributes {
+		files = append(files, &writerFile{
+			name:    attributesName,
+			data:    attributesData(files, len(blocks)+1),
+			options: FileOptions{Compression: CompressionZlib},
+		})
+
+		block, err := writeFileData(buffer, files[len(files)-1])
+		if err != nil {
+			return nil, fmt.Errorf("failed to write %s: %v", attributesName, err)
+		}
+
+		blocks = append(blocks, block)
+	}
+
+	hashTable := hashTableData(files)
+
+	header := Header{
+		HeaderSize:        headerSize,
+		FormatVersion:     0,
+		BlockSize:         writerSectorShift,
+		HashTableOffset:   uint32(buffer.Len()),
+		BlockTableOffset:  uint32(buffer.Len() + len(hashTable)*4),
+		HashTableEntries:  uint32(len(hashTable) / 4), //nolint:gomnd // 4 values per entry
+		BlockTableEntries: uint32(len(blocks)),
+	}
+
+	copy(header.Magic[:], "MPQ\x1A")
+
+	encrypt(hashTable, hashString("(hash table)", 3))
+
+	blockTable := make([]uint32, 0, len(blocks)*4)
+	for _, block := range blocks {
+		blockTable = append(blockTable, block.FilePosition, block.CompressedFileSize, block.UncompressedFileSize,
+			uint32(block.Flags))
+	}
+
+	encrypt(blockTable, hashString("(block table)", 3))
+
+	for _, table := range [][]uint32{hashTable, blockTable} {
+		if err := binary.Write(buffer, binary.LittleEndian, table); err != nil {
+			return nil, err
+		}
+	}
+
+	header.ArchiveSize = uint32(buffer.Len())
+
+	data := buffer.Bytes()
+	headerData := &bytes.Buffer{}
+
+	if err := binary.Write(headerData, binary.LittleEndian, &header); err != nil {
+		return nil, err
+	}
+
+	copy(data, headerData.Bytes())
+
+	return data, nil
+}
+
+func (w *Writer) sortedFiles() []*writerFile {
+	files := make([]*writerFile, 0, len(w.files))
+	for _, file := range w.files {
+		files = append(files, file)
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return strings.ToLower(files[i].name) < strings.ToLower(files[j].name)
+	})
+
+	return files
+}
+
+// writeFileData appends the sectors of the file to the archive, and returns its block
+func writeFileData(buffer *bytes.Buffer, file *writerFile) (*Block, error) {
+	block := &Block{
+		FilePosition:         uint32(buffer.Len()),
+		UncompressedFileSize: uint32(len(file.data)),
+		Flags:                FileExists,
+		FileName:             file.name,
+	}
+
+	// empty files are stored as is, there's nothing to compress or encrypt
+	compression := file.options.Compression
+	if len(file.data) == 0 {
+		compression = CompressionNone
+	}
+
+	switch compression {
+	case CompressionZlib, CompressionWavMono, CompressionWavStereo:
+		block.Flags |= FileCompress
+	case CompressionImplode:
+		block.Flags |= FileImplode
+	}
+
+	if file.options.Encrypted && len(file.data) > 0 {
+		block.Flags |= FileEncrypted
+
+		if file.options.FixKey {
+			block.Flags |= FileFixKey
+		}
+
+		block.calculateEncryptionSeed(file.name)
+	}
+
+	sectorSize := 0x200 << writerSectorShift
+	sectors := make([][]byte, 0, len(file.data)/sectorSize+1)
+
+	for offset := 0; offset < len(file.data); offset += sectorSize {
+		end := offset + sectorSize
+		if end > len(file.data) {
+			end = len(file.data)
+		}
+
+		sectorCompression := compression
+		if offset == 0 && (compression == CompressionWavMono || compression == CompressionWavStereo) {
+			sectorCompression = CompressionZlib
+		}
+
+		sector, err := compressSector(file.data[offset:end], sectorCompression)
+		if err != nil {
+			return nil, err
+		}
+
+		if block.HasFlag(FileEncrypted) && block.UncompressedFileSize > 3 {
+			encryptBytes(sector, uint32(len(sectors))+block.EncryptionSeed)
+		}
+
+		sectors = append(sectors, sector)
+	}
+
+	if compression != CompressionNone {
+		positions := make([]uint32, len(sectors)+1)
+		positions[0] = uint32(len(positions) * 4) //nolint:gomnd // uint32 size
+
+		for idx, sector := range sectors {
+			positions[idx+1] = positions[idx] + uint32(len(sector))
+		}
+
+		if block.HasFlag(FileEncrypted) {
+			encrypt(positions, block.EncryptionSeed-1)
+		}
+
+		if err := binary.Write(buffer, binary.LittleEndian, positions); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, sector := range sectors {
+		buffer.Write(sector)
+	}
+
+	block.CompressedFileSize = uint32(buffer.Len()) - block.FilePosition
+
+	return block, nil
+}
+
+// compressSector compresses a sector, sectors which don't get smaller are stored as is
+func compressSector(data []byte, compression Compression) ([]byte, error) {
+	var compressed []byte
+
+	var err error
+
+	switch compression {
+	case CompressionZlib:
+		if compressed, err = zlibCompress(data); err == nil {
+			compressed = append([]byte{compressionMaskZlib}, compressed...)
+		}
+	case CompressionImplode:
+		compressed, err = pkCompress(data)
+	case CompressionWavMono:
+		compressed, err = wavCompress(data, 1, compressionMaskWavMono)
+	case CompressionWavStereo:
+		compressed, err = wavCompress(data, 2, compressionMaskWavStereo) //nolint:gomnd // stereo
+	default:
+		compressed = data
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(compressed) >= len(data) {
+		compressed = make([]byte, len(data))
+		copy(compressed, data)
+	}
+
+	return compressed, nil
+}
+
+func zlibCompress(data []byte) ([]byte, error) {
+	buffer := new(bytes.Buffer)
+	w := zlib.NewWriter(buffer)
+
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// wavCompress compresses the samples of a sector, sectors that don't hold whole samples of all
+// channels are compressed with zlib
+func wavCompress(data []byte, channelCount int, mask byte) ([]byte, error) {
+	if len(data)%2 != 0 || len(data) < channelCount*2 {
+		return compressSector(data, CompressionZlib)
+	}
+
+	adpcm, err := d2compression.WavCompress(data, channelCount, d2compression.WavLevelMedium)
+	if err != nil {
+		return nil, err
+	}
+
+	compressed, err := d2compression.HuffmanCompress(adpcm, d2compression.WavLevelMedium+wavHuffmanTypeOffset)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte{mask}, compressed...), nil
+}
+
+func pkCompress(data []byte) ([]byte, error) {
+	buffer := new(bytes.Buffer)
+	w := blast.NewWriter(buffer, blast.Binary, blast.DictionarySize4096)
+
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// hashTableData builds the unencrypted hash table of the files, the block index of each file
+// is its index in the slice
+func hashTableData(files []*writerFile) []uint32 {
+	size := uint32(minHashTableSize)
+	for size < uint32(len(files))*2 {
+		size <<= 1
+	}
+
+	table := make([]uint32, size*4)
+	for idx := range table {
+		table[idx] = hashTableEmpty
+	}
+
+	for blockIndex, file := range files {
+		index := hashString(file.name, 0) & (size - 1)
+
+		for table[index*4+3] != hashTableEmpty {
+			index = (index + 1) & (size - 1)
+		}
+
+		entry := table[index*4 : index*4+4]
+		entry[0] = hashString(file.name, 1)
+		entry[1] = hashString(file.name, 2)
+		entry[2] = 0 // neutral locale and platform
+		entry[3] = uint32(blockIndex)
+	}
+
+	return table
+}
+
+// attributesData builds the attributes file with the CRC32 and MD5 of each file, the entries
+// of the attributes file itself are left empty
+func attributesData(files []*writerFile, blockCount int) []byte {
+	crcs := make([]uint32, blockCount)
+	hashes := make([][md5.Size]byte, blockCount)
+
+	for idx, file := range files {
+		crcs[idx] = crc32.ChecksumIEEE(file.data)
+		hashes[idx] = md5.Sum(file.data) //nolint:gosec // md5 is what the attributes format uses
+	}
+
+	buffer := new(bytes.Buffer)
+
+	_ = binary.Write(buffer, binary.LittleEndian, uint32(attributesVersion))
+	_ = binary.Write(buffer, binary.LittleEndian, uint32(attributesCRC32|attributesMD5))
+	_ = binary.Write(buffer, binary.LittleEndian, crcs)
+
+	for idx := range hashes {
+		buffer.Write(hashes[idx][:])
+	}
+
+	return buffer.Bytes()
+}
+
+// WriteTo writes the archive to the writer
+func (w *Writer) WriteTo(out io.Writer) (int64, error) {
+	data, err := w.Marshal()
+	if err != nil {
+		return 0, err
+	}
+
+	written, err := out.Write(data)
+
+	return int64(written), err
+}
@@ -0,0 +1,240 @@
+package d2mpq
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testFiles() map[string][]byte {
+	random := make([]byte, 10000)
+	rand.New(rand.NewSource(1)).Read(random)
+
+	return map[string][]byte{
+		`data\global\excel\armor.txt`: bytes.Repeat([]byte("name\tversion\tcompactsave\r\n"), 600),
+		`data\global\random.bin`:      random,
+		`data\local\empty.txt`:        {},
+		`data\local\tiny.txt`:         []byte("ab"),
+	}
+}
+
+func testOptions() []FileOptions {
+	return []FileOptions{
+		{Compression: CompressionNone},
+		{Compression: CompressionZlib},
+		{Compression: CompressionImplode},
+		{Compression: CompressionNone, Encrypted: true},
+		{Compression: CompressionZlib, Encrypted: true, FixKey: true},
+		{Compression: CompressionImplode, Encrypted: true, FixKey: true},
+	}
+}
+
+func saveTestArchive(t *testing.T, w *Writer) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "d2mpq")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	fileName := filepath.Join(dir, "test.mpq")
+
+	if err := w.Save(fileName); err != nil {
+		t.Fatal(err)
+	}
+
+	return fileName
+}
+
+func checkArchive(t *testing.T, fileName string, files map[string][]byte) {
+	t.Helper()
+
+	mpq, err := FromFile(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = mpq.Close()
+	}()
+
+	for name, want := range files {
+		have, err := mpq.ReadFile(name)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+
+		if !bytes.Equal(have, want) {
+			t.Errorf("%s: content changed after writing", name)
+		}
+	}
+
+	listfile, err := mpq.Listfile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(listfile) != len(files) {
+		t.Errorf("want %d files in the listfile, have %v", len(files), listfile)
+	}
+
+	if !mpq.Contains(attributesName) {
+		t.Errorf("archive has no attributes")
+	}
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	for _, options := range testOptions() {
+		w := NewWriter()
+		files := testFiles()
+
+		for name, data := range files {
+			if err := w.AddFile(name, data, options); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		checkArchive(t, saveTestArchive(t, w), files)
+	}
+}
+
+func TestWriterFromFile(t *testing.T) {
+	w := NewWriter()
+	files := testFiles()
+
+	for name, data := range files {
+		if err := w.AddFile(name, data, FileOptions{Compression: CompressionZlib, Encrypted: true, FixKey: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fileName := saveTestArchive(t, w)
+
+	edited, err := NewWriterFromFile(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files[`DATA\LOCAL\TINY.TXT`] = []byte("replaced")
+	files[`data\local\new.txt`] = []byte("appended")
+
+	delete(files, `data\local\tiny.txt`)
+
+	for _, name := range []string{`DATA\LOCAL\TINY.TXT`, `data\local\new.txt`} {
+		if err := edited.AddFile(name, files[name], FileOptions{Compression: CompressionImplode}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := edited.Save(fileName); err != nil {
+		t.Fatal(err)
+	}
+
+	checkArchive(t, fileName, files)
+}
+
+func TestWriterWav(t *testing.T) {
+	const sampleCount = 20000
+
+	header := bytes.Repeat([]byte{'R'}, 44)
+	samples := make([]byte, sampleCount*2)
+
+	for i := 0; i < sampleCount; i++ {
+		binary.LittleEndian.PutUint16(samples[i*2:], uint16(int16(8000*math.Sin(float64(i)/10))))
+	}
+
+	for _, compression := range []Compression{CompressionWavMono, CompressionWavStereo} {
+		w := NewWriter()
+		data := append(append([]byte(nil), header...), samples...)
+
+		if err := w.AddFile(`data\global\sfx\test.wav`, data, FileOptions{Compression: compression}); err != nil {
+			t.Fatal(err)
+		}
+
+		mpq, err := FromFile(saveTestArchive(t, w))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		have, err := mpq.ReadFile(`data\global\sfx\test.wav`)
+		_ = mpq.Close()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(have) != len(data) || !bytes.Equal(have[:len(header)], header) {
+			t.Fatalf("compression %d: the header or size of the file changed", compression)
+		}
+
+		// the samples are compressed lossy
+		for i := len(header); i < len(data); i += 2 {
+			want := int16(binary.LittleEndian.Uint16(data[i:]))
+			sample := int16(binary.LittleEndian.Uint16(have[i:]))
+
+			if math.Abs(float64(want)-float64(sample)) > 1000 {
+				t.Fatalf("compression %d: sample %d is %d, want about %d", compression, i, sample, want)
+			}
+		}
+	}
+}
+
+func TestConcurrentReads(t *testing.T) {
+	const readers = 8
+
+	w := NewWriter()
+	files := testFiles()
+
+	for name, data := range files {
+		if err := w.AddFile(name, data, FileOptions{Compression: CompressionZlib, Encrypted: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mpq, err := FromFile(saveTestArchive(t, w))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = mpq.Close()
+	}()
+
+	errs := make(chan error, readers)
+
+	for idx := 0; idx < readers; idx++ {
+		go func() {
+			for name, want := range files {
+				stream, err := mpq.ReadFileStream(name)
+				if err != nil {
+					errs <- err
+					return
+				}
+
+				if have, err := ioutil.ReadAll(stream); err != nil || !bytes.Equal(have, want) {
+					errs <- fmt.Errorf("%s: content differs, %v", name, err)
+					return
+				}
+			}
+
+			errs <- nil
+		}()
+	}
+
+	for idx := 0; idx < readers; idx++ {
+		if err := <-errs; err != nil {
+			t.Error(err)
+		}
+	}
+}
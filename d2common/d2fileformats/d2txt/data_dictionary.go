@@ -1,92 +1,72 @@
 package d2txt
 
-import (
-	"bytes"
-	"encoding/csv"
-	"io"
-	"log"
-	"strconv"
-	"strings"
-)
-
-// DataDictionary represents a data file (Excel)
+// DataDictionary represents a data file (Excel), read one record at a time
 type DataDictionary struct {
-	lookup map[string]int
-	r      *csv.Reader
-	record []string
+	*Table
+	next   int
+	record *Row
 	Err    error
 }
 
 // LoadDataDictionary loads the contents of a spreadsheet style txt file
-func LoadDataDictionary(buf []byte) *DataDictionary {
-	cr := csv.NewReader(bytes.NewReader(buf))
-	cr.Comma = '\t'
-	cr.ReuseRecord = true
-
-	fieldNames, err := cr.Read()
+func LoadDataDictionary(buf []byte) (*DataDictionary, error) {
+	table, err := LoadTable(buf)
 	if err != nil {
-		panic(err)
-	}
-
-	data := &DataDictionary{
-		lookup: make(map[string]int, len(fieldNames)),
-		r:      cr,
+		return nil, err
 	}
 
-	for i, name := range fieldNames {
-		data.lookup[name] = i
-	}
-
-	return data
+	return &DataDictionary{Table: table}, nil
 }
 
-// Next reads the next row, skips Expansion lines or
+// Next reads the next row, skips Expansion, comment and blank lines or
 // returns false when the end of a file is reached or an error occurred
 func (d *DataDictionary) Next() bool {
-	var err error
-	d.record, err = d.r.Read()
+	for d.Err == nil && d.next < len(d.Rows) {
+		d.record = d.Rows[d.next]
+		d.next++
 
-	if err == io.EOF {
-		return false
-	} else if err != nil {
-		d.Err = err
-		return false
+		if d.record.Kind == RowRecord {
+			return true
+		}
 	}
 
-	if d.record[0] == "Expansion" {
-		return d.Next()
-	}
+	return false
+}
+
+// Reset starts reading from the first record again
+func (d *DataDictionary) Reset() {
+	d.next = 0
+	d.record = nil
+	d.Err = nil
+}
 
-	return true
+// Record returns the row of the current record
+func (d *DataDictionary) Record() *Row {
+	return d.record
 }
 
 // String gets a string from the given column
 func (d *DataDictionary) String(field string) string {
-	return d.record[d.lookup[field]]
+	return d.record.String(field)
 }
 
 // Number gets a number for the given column
 func (d *DataDictionary) Number(field string) int {
-	n, err := strconv.Atoi(d.String(field))
-	if err != nil {
-		return 0
-	}
-
-	return n
+	return d.record.Number(field)
 }
 
 // List splits a delimited list from the given column
 func (d *DataDictionary) List(field string) []string {
-	str := d.String(field)
-	return strings.Split(str, ",")
+	return d.record.List(field)
 }
 
-// Bool gets a bool value for the given column
+// Bool gets a bool value for the given column, a field that isn't a bool sets Err and stops
+// reading
 func (d *DataDictionary) Bool(field string) bool {
-	n := d.Number(field)
-	if n > 1 {
-		log.Panic("Bool on non-bool field ", field)
+	value, err := d.record.Bool(field)
+	if err != nil && d.Err == nil {
+		d.Err = err
 	}
 
-	return n == 1
+	return value
 }
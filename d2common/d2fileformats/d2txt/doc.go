@@ -1,2 +1,2 @@
-// Package d2txt provides a parser implementation for diablo TSV data files
+// Package d2txt provides a parser and writer implementation for diablo TSV data files
 package d2txt
@@ -0,0 +1,67 @@
+package d2txt
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Append appends the records and expansion rows of another table. Fields are matched to the
+// columns by name, columns this table doesn't have are added.
+func (t *Table) Append(other *Table) {
+	sameColumns := reflect.DeepEqual(t.Columns, other.Columns)
+
+	for _, src := range other.Rows {
+		if src.Kind != RowRecord && src.Kind != RowExpansion {
+			continue
+		}
+
+		row := t.NewRecord()
+		row.Kind = src.Kind
+
+		if sameColumns || src.Kind == RowExpansion {
+			row.Fields = append(row.Fields[:0], src.Fields...)
+			continue
+		}
+
+		t.copyFields(row, src)
+	}
+}
+
+// Patch applies the records of another table to the records with the same value in the key
+// column. Only the columns of the other table are changed, records without a match are appended.
+func (t *Table) Patch(other *Table, key string) error {
+	if _, found := t.Column(key); !found {
+		return fmt.Errorf("%w: %s", ErrNoColumn, key)
+	}
+
+	if _, found := other.Column(key); !found {
+		return fmt.Errorf("%w: %s", ErrNoColumn, key)
+	}
+
+	for _, src := range other.Records() {
+		row := t.Find(key, src.String(key))
+		if row == nil {
+			row = t.NewRecord()
+		}
+
+		t.copyFields(row, src)
+	}
+
+	return nil
+}
+
+// copyFields sets the fields of a row from a row of another table by column name
+func (t *Table) copyFields(dst, src *Row) {
+	for idx, name := range src.table.Columns {
+		if idx >= len(src.Fields) {
+			break
+		}
+
+		if _, found := t.Column(name); !found {
+			t.AddColumn(name)
+		}
+
+		// the column exists, setting it can't fail
+		_ = dst.SetString(name, src.Fields[idx])
+	}
+}
@@ -0,0 +1,287 @@
+package d2txt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	fieldSeparator = "\t"
+	listSeparator  = ","
+	lineFeed       = "\n"
+	carriageReturn = "\r"
+
+	expansionRow = "Expansion"
+	commentStart = "#"
+)
+
+var (
+	// ErrNoHeader is returned when a table has no header row
+	ErrNoHeader = errors.New("table has no header")
+
+	// ErrNoColumn is returned when setting a field of a column the table doesn't have
+	ErrNoColumn = errors.New("no such column")
+
+	// ErrNotBool is returned when reading a bool from a field that isn't 0 or 1
+	ErrNotBool = errors.New("not a bool field")
+)
+
+// RowKind tells records apart from the rows that only format a table
+type RowKind int
+
+// Row kinds
+const (
+	RowRecord RowKind = iota
+	RowExpansion
+	RowComment
+	RowBlank
+)
+
+// Row is a line of a table. The fields are kept as they are in the file, so that a row which
+// isn't edited is written back byte for byte.
+type Row struct {
+	Kind   RowKind
+	Fields []string
+
+	lineEnding string
+	table      *Table
+}
+
+// Table is a spreadsheet style txt file. Fields are separated by tabs and taken verbatim, there
+// is no quoting.
+type Table struct {
+	Columns []string
+	Rows    []*Row
+
+	headerEnding string
+	lineEnding   string
+	lookup       map[string]int
+}
+
+// LoadTable parses the contents of a spreadsheet style txt file
+func LoadTable(buf []byte) (*Table, error) {
+	lines := strings.SplitAfter(string(buf), lineFeed)
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	if len(lines) == 0 {
+		return nil, ErrNoHeader
+	}
+
+	t := &Table{lineEnding: lineFeed}
+
+	header, headerEnding := splitLineEnding(lines[0])
+	if header == "" {
+		return nil, ErrNoHeader
+	}
+
+	t.Columns = strings.Split(header, fieldSeparator)
+	t.headerEnding = headerEnding
+
+	if headerEnding != "" {
+		t.lineEnding = headerEnding
+	}
+
+	t.updateLookup()
+
+	t.Rows = make([]*Row, 0, len(lines)-1)
+
+	for _, line := range lines[1:] {
+		text, lineEnding := splitLineEnding(line)
+		row := &Row{Fields: strings.Split(text, fieldSeparator), lineEnding: lineEnding, table: t}
+
+		switch {
+		case text == "":
+			row.Kind = RowBlank
+		case strings.HasPrefix(text, commentStart):
+			row.Kind = RowComment
+		case row.Fields[0] == expansionRow:
+			row.Kind = RowExpansion
+		}
+
+		t.Rows = append(t.Rows, row)
+	}
+
+	return t, nil
+}
+
+func splitLineEnding(line string) (text, lineEnding string) {
+	text = strings.TrimSuffix(line, lineFeed)
+	text = strings.TrimSuffix(text, carriageReturn)
+
+	return text, line[len(text):]
+}
+
+// updateLookup maps column names to their index, the last of duplicate columns wins
+func (t *Table) updateLookup() {
+	t.lookup = make(map[string]int, len(t.Columns))
+
+	for idx, name := range t.Columns {
+		t.lookup[name] = idx
+	}
+}
+
+// Marshal encodes the table back into a byte slice
+func (t *Table) Marshal() []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString(strings.Join(t.Columns, fieldSeparator))
+	lineEnding := t.headerEnding
+
+	for _, row := range t.Rows {
+		// a row appended after a last line without line ending starts a new line
+		if lineEnding == "" {
+			lineEnding = t.lineEnding
+		}
+
+		buf.WriteString(lineEnding)
+		buf.WriteString(strings.Join(row.Fields, fieldSeparator))
+		lineEnding = row.lineEnding
+	}
+
+	buf.WriteString(lineEnding)
+
+	return buf.Bytes()
+}
+
+// Column returns the index of the named column
+func (t *Table) Column(name string) (int, bool) {
+	idx, found := t.lookup[name]
+	return idx, found
+}
+
+// AddColumn appends a column to the table, existing rows get an empty field when written
+func (t *Table) AddColumn(name string) {
+	t.Columns = append(t.Columns, name)
+	t.updateLookup()
+}
+
+// Records returns the rows holding records
+func (t *Table) Records() []*Row {
+	records := make([]*Row, 0, len(t.Rows))
+
+	for _, row := range t.Rows {
+		if row.Kind == RowRecord {
+			records = append(records, row)
+		}
+	}
+
+	return records
+}
+
+// Find returns the first record with the given value in the named column
+func (t *Table) Find(field, value string) *Row {
+	for _, row := range t.Rows {
+		if row.Kind == RowRecord && row.String(field) == value {
+			return row
+		}
+	}
+
+	return nil
+}
+
+// NewRecord appends an empty record to the table
+func (t *Table) NewRecord() *Row {
+	row := &Row{
+		Fields:     make([]string, len(t.Columns)),
+		lineEnding: t.lineEnding,
+		table:      t,
+	}
+
+	t.Rows = append(t.Rows, row)
+
+	return row
+}
+
+// Remove removes the given row from the table
+func (t *Table) Remove(row *Row) {
+	for idx := range t.Rows {
+		if t.Rows[idx] == row {
+			t.Rows = append(t.Rows[:idx], t.Rows[idx+1:]...)
+			return
+		}
+	}
+}
+
+// String gets a string from the given column, empty when the row or table doesn't have it
+func (r *Row) String(field string) string {
+	idx, found := r.table.lookup[field]
+	if !found || idx >= len(r.Fields) {
+		return ""
+	}
+
+	return r.Fields[idx]
+}
+
+// Number gets a number for the given column, 0 when it isn't a number
+func (r *Row) Number(field string) int {
+	n, err := strconv.Atoi(r.String(field))
+	if err != nil {
+		return 0
+	}
+
+	return n
+}
+
+// List splits a delimited list from the given column
+func (r *Row) List(field string) []string {
+	return strings.Split(r.String(field), listSeparator)
+}
+
+// Bool gets a bool value for the given column
+func (r *Row) Bool(field string) (bool, error) {
+	n := r.Number(field)
+	if n > 1 {
+		return false, fmt.Errorf("%w: %s", ErrNotBool, field)
+	}
+
+	return n == 1, nil
+}
+
+// SetString sets the field of the given column
+func (r *Row) SetString(field, value string) error {
+	idx, found := r.table.lookup[field]
+	if !found {
+		return fmt.Errorf("%w: %s", ErrNoColumn, field)
+	}
+
+	if idx >= len(r.Fields) {
+		if value == "" {
+			return nil
+		}
+
+		r.Fields = append(r.Fields, make([]string, idx+1-len(r.Fields))...)
+	}
+
+	r.Fields[idx] = value
+
+	return nil
+}
+
+// SetNumber sets a number for the given column. A field which already reads as the number is
+// left as it is, so that empty fields stay empty.
+func (r *Row) SetNumber(field string, value int) error {
+	if _, found := r.table.lookup[field]; found && r.Number(field) == value {
+		return nil
+	}
+
+	return r.SetString(field, strconv.Itoa(value))
+}
+
+// SetList joins a list into the given column
+func (r *Row) SetList(field string, values []string) error {
+	return r.SetString(field, strings.Join(values, listSeparator))
+}
+
+// SetBool sets a bool value for the given column
+func (r *Row) SetBool(field string, value bool) error {
+	if value {
+		return r.SetNumber(field, 1)
+	}
+
+	return r.SetNumber(field, 0)
+}
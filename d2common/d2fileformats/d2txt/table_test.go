@@ -0,0 +1,172 @@
+package d2txt
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const testTable = "Name\tId\tEnabled\tList\r\n" +
+	"first\t1\t1\ta,b\r\n" +
+	"Expansion\r\n" +
+	"\r\n" +
+	"# a comment\r\n" +
+	"second\t\t0\t\r\n" +
+	"short\t3"
+
+func TestTableRoundTrip(t *testing.T) {
+	for _, data := range []string{testTable, testTable + "\r\n", "Name\tId\n\nfirst\t1\n"} {
+		table, err := LoadTable([]byte(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if have := string(table.Marshal()); have != data {
+			t.Errorf("want %q, have %q", data, have)
+		}
+	}
+}
+
+func TestTableRows(t *testing.T) {
+	table, err := LoadTable([]byte(testTable))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kinds := make([]RowKind, len(table.Rows))
+	for idx, row := range table.Rows {
+		kinds[idx] = row.Kind
+	}
+
+	want := []RowKind{RowRecord, RowExpansion, RowBlank, RowComment, RowRecord, RowRecord}
+	if !reflect.DeepEqual(kinds, want) {
+		t.Errorf("want row kinds %v, have %v", want, kinds)
+	}
+
+	records := table.Records()
+	if len(records) != 3 {
+		t.Fatalf("want 3 records, have %d", len(records))
+	}
+
+	if records[0].Number("Id") != 1 || !reflect.DeepEqual(records[0].List("List"), []string{"a", "b"}) {
+		t.Errorf("first record read as %v", records[0].Fields)
+	}
+
+	if records[2].String("Enabled") != "" || records[2].String("Missing") != "" {
+		t.Error("fields the row or table doesn't have aren't empty")
+	}
+}
+
+func TestTableEdit(t *testing.T) {
+	table, err := LoadTable([]byte(testTable))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second := table.Find("Name", "second")
+	if second == nil {
+		t.Fatal("record not found")
+	}
+
+	// unchanged values keep their formatting
+	if err := second.SetNumber("Id", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := second.SetBool("Enabled", true); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := second.SetString("Missing", "x"); !errors.Is(err, ErrNoColumn) {
+		t.Errorf("want ErrNoColumn, have %v", err)
+	}
+
+	table.Remove(table.Find("Name", "first"))
+	table.AddColumn("Extra")
+
+	added := table.NewRecord()
+	if err := added.SetString("Name", "third"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := added.SetString("Extra", "x"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Name\tId\tEnabled\tList\tExtra\r\n" +
+		"Expansion\r\n" +
+		"\r\n" +
+		"# a comment\r\n" +
+		"second\t\t1\t\r\n" +
+		"short\t3\r\n" +
+		"third\t\t\t\tx\r\n"
+
+	if have := string(table.Marshal()); have != want {
+		t.Errorf("want %q, have %q", want, have)
+	}
+}
+
+func TestDataDictionary(t *testing.T) {
+	if _, err := LoadDataDictionary(nil); !errors.Is(err, ErrNoHeader) {
+		t.Errorf("want ErrNoHeader, have %v", err)
+	}
+
+	dict, err := LoadDataDictionary([]byte("Name\tEnabled\nfirst\t1\nsecond\t2\nthird\t0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := make([]string, 0)
+
+	for dict.Next() {
+		dict.Bool("Enabled")
+		names = append(names, dict.String("Name"))
+	}
+
+	if !errors.Is(dict.Err, ErrNotBool) || len(names) != 2 {
+		t.Errorf("want to stop at a bad bool with ErrNotBool, have %v after %v", dict.Err, names)
+	}
+
+	dict.Reset()
+
+	if !dict.Next() || dict.Record().String("Name") != "first" {
+		t.Error("reset doesn't start over")
+	}
+}
+
+func TestTableMerge(t *testing.T) {
+	table, err := LoadTable([]byte("Name\tId\tEnabled\nfirst\t1\t1\nsecond\t2\t0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := LoadTable([]byte("Name\tId\tEnabled\n# added\nthird\t3\t1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	patch, err := LoadTable([]byte("Extra\tName\tEnabled\nx\tfirst\t0\ny\tfourth\t1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table.Append(rows)
+
+	if err := table.Patch(patch, "Missing"); !errors.Is(err, ErrNoColumn) {
+		t.Errorf("want ErrNoColumn, have %v", err)
+	}
+
+	if err := table.Patch(patch, "Name"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Name\tId\tEnabled\tExtra\n" +
+		"first\t1\t0\tx\n" +
+		"second\t2\t0\n" +
+		"third\t3\t1\n" +
+		"fourth\t\t1\ty\n"
+
+	if have := string(table.Marshal()); have != want {
+		t.Errorf("want %q, have %q", want, have)
+	}
+}
@@ -5,5 +5,6 @@ package d2interface
 type AudioProvider interface {
 	PlayBGM(song string)
 	LoadSound(sfx string, loop bool, bgm bool) (SoundEffect, error)
+	LoadPCM(samples []int16, sampleRate, channels int) (PCMSound, error)
 	SetVolumes(bgmVolume, sfxVolume float64)
 }
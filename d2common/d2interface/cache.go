@@ -3,10 +3,12 @@ package d2interface
 // Cache stores arbitrary data for fast retrieval
 type Cache interface {
 	SetVerbose(verbose bool)
+	SetEvictHandler(handler func(key string, value interface{}))
 	GetWeight() int
 	GetBudget() int
 	Insert(key string, value interface{}, weight int) error
 	Retrieve(key string) (interface{}, bool)
+	Remove(key string) bool
 	Clear()
 }
 
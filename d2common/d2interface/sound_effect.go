@@ -8,3 +8,10 @@ type SoundEffect interface {
 	IsPlaying() bool
 	SetVolume(volume float64)
 }
+
+// PCMSound is a SoundEffect of decoded samples, like the audio of a video
+type PCMSound interface {
+	SoundEffect
+	// Position returns how far the sound was played, in seconds
+	Position() float64
+}
@@ -0,0 +1,2 @@
+// Package archive provides zip and tar.gz Source implementations for d2loader
+package archive
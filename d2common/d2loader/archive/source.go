@@ -0,0 +1,175 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2loader/asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2loader/asset/types"
+)
+
+// ErrFileNotFound is returned when opening a file the archive doesn't contain
+var ErrFileNotFound = errors.New("file not found in archive")
+
+// static check that Source implements AssetSource
+var _ asset.Source = &Source{}
+
+// Source is an asset source for zip and tar.gz archives. File names are matched like in MPQ
+// archives, without regard to case or the kind of path separator.
+type Source struct {
+	sourceType  types.SourceType
+	archivePath string
+	files       map[string]func() (io.ReadCloser, error)
+	// closer closes the archive file, which zip sources keep open
+	closer io.Closer
+}
+
+// NewZipSource creates a Source for a zip archive
+func NewZipSource(sourcePath string) (asset.Source, error) {
+	reader, err := zip.OpenReader(sourcePath)
+	if err != nil {
+		return nil, err
+	}
+
+	source := &Source{
+		sourceType:  types.AssetSourceZip,
+		archivePath: sourcePath,
+		files:       make(map[string]func() (io.ReadCloser, error), len(reader.File)),
+		closer:      reader,
+	}
+
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+
+		source.files[cleanName(file.Name)] = file.Open
+	}
+
+	return source, nil
+}
+
+// NewTarGzSource creates a Source for a gzip compressed tar archive. The files can't be read
+// out of order from a compressed stream, so the whole archive is unpacked into memory.
+func NewTarGzSource(sourcePath string) (asset.Source, error) {
+	file, err := os.Open(sourcePath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", sourcePath, err)
+	}
+
+	source := &Source{
+		sourceType:  types.AssetSourceTarGz,
+		archivePath: sourcePath,
+		files:       make(map[string]func() (io.ReadCloser, error)),
+	}
+
+	tarReader := tar.NewReader(gzipReader)
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", sourcePath, err)
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		data, err := ioutil.ReadAll(tarReader)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", sourcePath, err)
+		}
+
+		source.files[cleanName(header.Name)] = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(data)), nil
+		}
+	}
+
+	return source, nil
+}
+
+// Type returns the type of this asset source
+func (s *Source) Type() types.SourceType {
+	return s.sourceType
+}
+
+// Open reads a file of the archive
+func (s *Source) Open(name string) (io.ReadSeeker, error) {
+	open, found := s.files[cleanName(name)]
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrFileNotFound, name)
+	}
+
+	reader, err := open()
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(data), nil
+}
+
+// Exists returns true if the archive contains the file
+func (s *Source) Exists(subPath string) bool {
+	_, found := s.files[cleanName(subPath)]
+	return found
+}
+
+// Path returns the path of the archive on the host filesystem
+func (s *Source) Path() string {
+	return s.archivePath
+}
+
+// String returns the path
+func (s *Source) String() string {
+	return s.Path()
+}
+
+// Close closes the archive file, the files of the archive can't be opened after
+func (s *Source) Close() error {
+	s.files = nil
+
+	if s.closer == nil {
+		return nil
+	}
+
+	return s.closer.Close()
+}
+
+// cleanName makes a file name into the key of the files lookup
+func cleanName(name string) string {
+	name = strings.ReplaceAll(name, "\\", "/")
+	name = path.Clean("/" + name)
+
+	return strings.ToLower(name[1:])
+}
@@ -15,6 +15,8 @@ const (
 	AssetSourceUnknown SourceType = iota
 	AssetSourceFileSystem
 	AssetSourceMPQ
+	AssetSourceZip
+	AssetSourceTarGz
 )
 
 // Ext2SourceType returns the SourceType from the given file extension
@@ -24,6 +26,9 @@ func Ext2SourceType(ext string) SourceType {
 
 	lookup := map[string]SourceType{
 		"mpq": AssetSourceMPQ,
+		"zip": AssetSourceZip,
+		"gz":  AssetSourceTarGz,
+		"tgz": AssetSourceTarGz,
 	}
 
 	if knownType, found := lookup[ext]; found {
@@ -0,0 +1,192 @@
+package d2loader
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2loader/asset"
+)
+
+const (
+	// files that take more than this part of the cache budget aren't cached, they would evict
+	// everything else
+	maxCachedFraction = 8
+)
+
+// cachedFile is the content of a loaded file, or a lookup of a file no source has
+type cachedFile struct {
+	data   []byte
+	origin []string
+}
+
+// missing returns true for the lookup of a file no source has
+func (f *cachedFile) missing() bool {
+	return f.origin == nil
+}
+
+// CacheStats counts how the file loads of a Loader were served
+type CacheStats struct {
+	// Hits is the number of files served from the cache
+	Hits int
+	// MissingHits is the number of files found missing in the cache, without asking the sources
+	MissingHits int
+	// Misses is the number of files the sources were asked for
+	Misses int
+	// Invalidations is the number of cached files dropped because the sources changed
+	Invalidations int
+}
+
+// CacheStats returns the cache statistics of the loader
+func (l *Loader) CacheStats() CacheStats {
+	l.cacheMutex.Lock()
+	defer l.cacheMutex.Unlock()
+
+	return l.stats
+}
+
+// retrieve gets a file from the cache
+func (l *Loader) retrieve(subPath string) (*cachedFile, bool) {
+	cached, found := l.Retrieve(subPath)
+
+	l.cacheMutex.Lock()
+	defer l.cacheMutex.Unlock()
+
+	if !found {
+		l.stats.Misses++
+		return nil, false
+	}
+
+	file := cached.(*cachedFile)
+
+	if file.missing() {
+		l.stats.MissingHits++
+	} else {
+		l.stats.Hits++
+	}
+
+	return file, true
+}
+
+// insert caches a file, weighted by its size. The sources must be locked for reading.
+func (l *Loader) insert(subPath string, file *cachedFile) {
+	weight := len(file.data)
+	if file.missing() {
+		weight = len(subPath)
+	}
+
+	if weight > l.GetBudget()/maxCachedFraction {
+		return
+	}
+
+	l.cacheMutex.Lock()
+	l.files[subPath] = file
+	l.cacheMutex.Unlock()
+
+	// the cache calls evicted for the files it drops, which locks cacheMutex
+	if err := l.Insert(subPath, file, weight); err != nil {
+		l.Warningf("caching %s: %v", subPath, err)
+		l.evicted(subPath, file)
+	}
+}
+
+// evicted forgets a file the cache dropped
+func (l *Loader) evicted(subPath string, value interface{}) {
+	l.cacheMutex.Lock()
+	defer l.cacheMutex.Unlock()
+
+	// the file may have been loaded again since
+	if l.files[subPath] == value {
+		delete(l.files, subPath)
+	}
+}
+
+// invalidate drops the cached files the given function matches, with the sources that served
+// a file, or nil for a missing file. The sources must be locked for writing.
+func (l *Loader) invalidate(match func(subPath string, origin []string) bool) {
+	l.cacheMutex.Lock()
+	defer l.cacheMutex.Unlock()
+
+	for subPath, file := range l.files {
+		if match(subPath, file.origin) {
+			delete(l.files, subPath)
+
+			if l.Remove(subPath) {
+				l.stats.Invalidations++
+			}
+		}
+	}
+}
+
+// invalidateSource drops the cached files the source served and the files it has, which may
+// be served from the source now. The sources must be locked for writing.
+func (l *Loader) invalidateSource(source asset.Source) {
+	l.invalidate(func(subPath string, origin []string) bool {
+		for _, sourcePath := range origin {
+			if sourcePath == source.Path() {
+				return true
+			}
+		}
+
+		return source.Exists(subPath)
+	})
+}
+
+// InvalidateSource drops the cached files of the source with the given path, for example after
+// the files of a directory source were edited
+func (l *Loader) InvalidateSource(path string) error {
+	l.sourcesMutex.Lock()
+	defer l.sourcesMutex.Unlock()
+
+	source, err := l.source(path)
+	if err != nil {
+		return err
+	}
+
+	l.invalidateSource(source)
+
+	return nil
+}
+
+// RemoveSource removes the source with the given path, and closes it if it holds a file open
+func (l *Loader) RemoveSource(path string) error {
+	l.sourcesMutex.Lock()
+	defer l.sourcesMutex.Unlock()
+
+	source, err := l.source(path)
+	if err != nil {
+		return err
+	}
+
+	for idx := range l.Sources {
+		if l.Sources[idx] == source {
+			l.Sources = append(l.Sources[:idx], l.Sources[idx+1:]...)
+			break
+		}
+	}
+
+	delete(l.priorities, source)
+	delete(l.rules, source)
+
+	l.Infof("Removing source: '%s'", source.Path())
+	l.invalidateSource(source)
+
+	if closer, ok := source.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+// source returns the source with the given path, the sources must be locked
+func (l *Loader) source(path string) (asset.Source, error) {
+	cleanPath := filepath.Clean(path)
+
+	for _, source := range l.Sources {
+		if source.Path() == cleanPath {
+			return source, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrNoSource, cleanPath)
+}
@@ -1,11 +1,18 @@
 package d2loader
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"path"
 	"path/filepath"
 	"strings"
+	"sync"
 
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2txt"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2loader/archive"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2loader/mpq"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2loader/filesystem"
@@ -20,13 +27,23 @@ import (
 
 const (
 	defaultCacheBudget = 1024 * 1024 * 512
-	errFmtFileNotFound = "file not found: %s"
 )
 
 const (
 	logPrefix = "File Loader"
 )
 
+var (
+	// ErrFileNotFound is returned when no source has the file
+	ErrFileNotFound = errors.New("file not found")
+
+	// ErrNoSource is returned when the loader has no source with the given path
+	ErrNoSource = errors.New("no such asset source")
+
+	// ErrSourceType is returned when adding a source of an unknown type
+	ErrSourceType = errors.New("unknown asset source type")
+)
+
 const (
 	fontToken  = d2resource.LanguageFontToken
 	tableToken = d2resource.LanguageTableToken
@@ -35,13 +52,19 @@ const (
 // NewLoader creates a new loader
 func NewLoader(l d2util.LogLevel) (*Loader, error) {
 	loader := &Loader{
-		LoaderProviders: make(map[types.SourceType]func(path string) (asset.Source, error), 2),
+		LoaderProviders: make(map[types.SourceType]func(path string) (asset.Source, error), 4),
+		priorities:      make(map[asset.Source]int),
+		rules:           make(map[asset.Source]map[string]MergeRule),
+		files:           make(map[string]*cachedFile),
 	}
 
 	loader.LoaderProviders[types.AssetSourceMPQ] = mpq.NewSource
 	loader.LoaderProviders[types.AssetSourceFileSystem] = filesystem.OnAddSource
+	loader.LoaderProviders[types.AssetSourceZip] = archive.NewZipSource
+	loader.LoaderProviders[types.AssetSourceTarGz] = archive.NewTarGzSource
 
 	loader.Cache = d2cache.CreateCache(defaultCacheBudget)
+	loader.Cache.SetEvictHandler(loader.evicted)
 	loader.Logger = d2util.NewLogger()
 
 	loader.Logger.SetPrefix(logPrefix)
@@ -51,14 +74,29 @@ func NewLoader(l d2util.LogLevel) (*Loader, error) {
 }
 
 // Loader represents the manager that handles loading and caching assets with the asset Sources
-// that have been added
+// that have been added. Sources are ordered by priority, the first source that has a file
+// serves it, unless a merge rule of the source merges it onto the file of the next sources.
+// Files can be loaded concurrently, concurrent loads of the same file are done once.
 type Loader struct {
 	language *string
 	charset  *string
 	d2interface.Cache
 	*d2util.Logger
 	LoaderProviders map[types.SourceType]func(path string) (asset.Source, error)
-	Sources         []asset.Source
+	// Sources must not be changed directly when files are loaded concurrently, use AddSource
+	// and RemoveSource instead
+	Sources    []asset.Source
+	priorities map[asset.Source]int
+	rules      map[asset.Source]map[string]MergeRule
+	// sourcesMutex guards the sources, their priorities and rules. Loads hold it for reading,
+	// until the file is cached, so that changing the sources invalidates every file loaded before.
+	sourcesMutex sync.RWMutex
+	loads        d2cache.FlightGroup
+	// files are the cached files and missing file lookups, by sub-path
+	files map[string]*cachedFile
+	stats CacheStats
+	// cacheMutex guards the cached files and statistics, it is locked after sourcesMutex
+	cacheMutex sync.Mutex
 }
 
 // SetLanguage sets the language for loader
@@ -74,18 +112,67 @@ func (l *Loader) SetCharset(charset *string) {
 // Load attempts to load an asset with the given sub-path. The sub-path is relative to the root
 // of each asset source root (regardless of the type of asset source)
 func (l *Loader) Load(subPath string) (io.ReadSeeker, error) {
-	subPath = filepath.Clean(subPath)
+	subPath = l.localize(subPath)
+
+	file, found := l.retrieve(subPath)
+	if !found {
+		// concurrent loads of the file wait for the first one
+		loaded, _, err := l.loads.Do(subPath, func() (interface{}, error) {
+			return l.load(subPath)
+		})
+		if err != nil {
+			return nil, err
+		}
 
-	if l.language != nil {
-		charset := l.charset
-		language := l.language
+		file = loaded.(*cachedFile)
+	}
 
-		subPath = strings.ReplaceAll(subPath, fontToken, *charset)
-		subPath = strings.ReplaceAll(subPath, tableToken, *language)
+	if file.missing() {
+		return nil, fmt.Errorf("%w: %s", ErrFileNotFound, subPath)
+	}
+
+	return bytes.NewReader(file.data), nil
+}
+
+// load reads a file from the sources and caches it
+func (l *Loader) load(subPath string) (*cachedFile, error) {
+	l.sourcesMutex.RLock()
+	defer l.sourcesMutex.RUnlock()
+
+	// a load that finished just before this one started may have cached the file
+	if cached, found := l.Retrieve(subPath); found {
+		return cached.(*cachedFile), nil
+	}
+
+	loadedAsset, origin, err := l.open(subPath, 0)
+	if errors.Is(err, ErrFileNotFound) {
+		file := &cachedFile{}
+		l.insert(subPath, file)
+
+		return file, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadAll(loadedAsset)
+	if err != nil {
+		return nil, err
 	}
 
+	file := &cachedFile{data: data, origin: origin}
+	l.insert(subPath, file)
+
+	return file, nil
+}
+
+// open loads the file from the first source at or after the given index. When the source has
+// a merge rule for the file, the file is merged onto what the following sources serve. The paths
+// of the sources used are returned as well.
+func (l *Loader) open(subPath string, start int) (io.ReadSeeker, []string, error) {
 	// if it isn't in the cache, we check if each source can open the file
-	for idx := range l.Sources {
+	for idx := start; idx < len(l.Sources); idx++ {
 		source := l.Sources[idx]
 
 		// if the source can open the file, then we cache it and return it
@@ -98,10 +185,86 @@ func (l *Loader) Load(subPath string) (io.ReadSeeker, error) {
 		srcBase, _ := filepath.Abs(source.Path())
 		l.Info(fmt.Sprintf("Loaded %s -> %s", srcBase, subPath))
 
-		return loadedAsset, nil
+		rule, found := l.rules[source][ruleKey(subPath)]
+		if !found {
+			return loadedAsset, []string{source.Path()}, nil
+		}
+
+		// without a file to merge onto, the file is served as it is
+		base, origin, err := l.open(subPath, idx+1)
+		if err != nil {
+			return loadedAsset, []string{source.Path()}, nil
+		}
+
+		merged, err := mergeTables(base, loadedAsset, rule)
+		if err != nil {
+			return nil, nil, fmt.Errorf("merging %s from %s: %w", subPath, source.Path(), err)
+		}
+
+		return merged, append([]string{source.Path()}, origin...), nil
+	}
+
+	return nil, nil, fmt.Errorf("%w: %s", ErrFileNotFound, subPath)
+}
+
+// mergeTables applies a merge rule to the contents of two txt tables
+func mergeTables(base, overlay io.Reader, rule MergeRule) (io.ReadSeeker, error) {
+	tables := make([]*d2txt.Table, 2)
+
+	for idx, reader := range []io.Reader{base, overlay} {
+		data, err := ioutil.ReadAll(reader)
+		if err != nil {
+			return nil, err
+		}
+
+		if tables[idx], err = d2txt.LoadTable(data); err != nil {
+			return nil, err
+		}
+	}
+
+	switch rule.Mode {
+	case MergeAppend:
+		tables[0].Append(tables[1])
+	case MergePatch:
+		if err := tables[0].Patch(tables[1], rule.Key); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrMergeMode, rule.Mode)
+	}
+
+	return bytes.NewReader(tables[0].Marshal()), nil
+}
+
+// Origin returns the paths of the sources that served the cached file with the given sub-path,
+// the source of a merged file first
+func (l *Loader) Origin(subPath string) []string {
+	subPath = l.localize(subPath)
+
+	l.cacheMutex.Lock()
+	defer l.cacheMutex.Unlock()
+
+	if file, found := l.files[subPath]; found {
+		return file.origin
+	}
+
+	return nil
+}
+
+// Origins returns the source paths of every cached file, as returned by Origin
+func (l *Loader) Origins() map[string][]string {
+	l.cacheMutex.Lock()
+	defer l.cacheMutex.Unlock()
+
+	origins := make(map[string][]string, len(l.files))
+
+	for subPath, file := range l.files {
+		if !file.missing() {
+			origins[subPath] = file.origin
+		}
 	}
 
-	return nil, fmt.Errorf(errFmtFileNotFound, subPath)
+	return origins
 }
 
 // AddSource adds an asset source with the given path. The path will either resolve to a directory
@@ -109,35 +272,81 @@ func (l *Loader) Load(subPath string) (io.ReadSeeker, error) {
 // to determine the type of asset source. In the case that the path points to a directory, a
 // FileSystemSource will be added.
 func (l *Loader) AddSource(path string, sourceType types.SourceType) error {
-	if l.Sources == nil {
-		l.Sources = make([]asset.Source, 0)
-	}
+	return l.AddSourceWithPriority(path, sourceType, 0)
+}
 
+// AddSourceWithPriority adds an asset source like AddSource. Sources with a higher priority are
+// checked first, sources with the same priority are checked in the order they were added. The
+// merge rules decide which txt tables of the source are merged onto those of the next sources,
+// instead of replacing them.
+func (l *Loader) AddSourceWithPriority(path string, sourceType types.SourceType, priority int,
+	rules ...MergeRule) error {
 	cleanPath := filepath.Clean(path)
 
-	source, err := l.LoaderProviders[sourceType](cleanPath)
+	provider, found := l.LoaderProviders[sourceType]
+	if !found {
+		return fmt.Errorf("%w: %s", ErrSourceType, cleanPath)
+	}
+
+	sourceRules := make(map[string]MergeRule, len(rules))
+
+	for _, rule := range rules {
+		if err := rule.validate(); err != nil {
+			return err
+		}
+
+		sourceRules[ruleKey(rule.File)] = rule
+	}
+
+	source, err := provider(cleanPath)
 
 	if err != nil {
 		return err
 	}
 
 	l.Infof("Adding source: '%s'", cleanPath)
-	l.Sources = append(l.Sources, source)
+
+	l.sourcesMutex.Lock()
+	defer l.sourcesMutex.Unlock()
+
+	idx := len(l.Sources)
+	for idx > 0 && l.priorities[l.Sources[idx-1]] < priority {
+		idx--
+	}
+
+	l.Sources = append(l.Sources, nil)
+	copy(l.Sources[idx+1:], l.Sources[idx:])
+	l.Sources[idx] = source
+	l.priorities[source] = priority
+	l.rules[source] = sourceRules
+
+	l.invalidateSource(source)
 
 	return nil
 }
 
+// Priority returns the priority of a source
+func (l *Loader) Priority(source asset.Source) int {
+	l.sourcesMutex.RLock()
+	defer l.sourcesMutex.RUnlock()
+
+	return l.priorities[source]
+}
+
+// ListSources returns the sources in the order they are checked
+func (l *Loader) ListSources() []asset.Source {
+	l.sourcesMutex.RLock()
+	defer l.sourcesMutex.RUnlock()
+
+	return append([]asset.Source(nil), l.Sources...)
+}
+
 // Exists checks if the given path exists in at least one source
 func (l *Loader) Exists(subPath string) bool {
-	subPath = filepath.Clean(subPath)
-
-	if l.language != nil {
-		charset := l.charset
-		language := l.language
+	subPath = l.localize(subPath)
 
-		subPath = strings.ReplaceAll(subPath, fontToken, *charset)
-		subPath = strings.ReplaceAll(subPath, tableToken, *language)
-	}
+	l.sourcesMutex.RLock()
+	defer l.sourcesMutex.RUnlock()
 
 	// if it isn't in the cache, we check if each source can open the file
 	for idx := range l.Sources {
@@ -151,3 +360,27 @@ func (l *Loader) Exists(subPath string) bool {
 
 	return false
 }
+
+// localize cleans the sub-path and fills in the language tokens
+func (l *Loader) localize(subPath string) string {
+	subPath = filepath.Clean(subPath)
+
+	if l.language != nil {
+		charset := l.charset
+		language := l.language
+
+		subPath = strings.ReplaceAll(subPath, fontToken, *charset)
+		subPath = strings.ReplaceAll(subPath, tableToken, *language)
+	}
+
+	return subPath
+}
+
+// ruleKey makes a sub-path into the key of the merge rules lookup, like MPQ file names the
+// sub-paths are matched without regard to case or the kind of path separator
+func ruleKey(subPath string) string {
+	subPath = strings.ReplaceAll(subPath, "\\", "/")
+	subPath = path.Clean("/" + subPath)
+
+	return strings.ToLower(subPath[1:])
+}
@@ -1,11 +1,16 @@
 package d2loader
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
+	"path/filepath"
+	"reflect"
 	"testing"
 
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2cache"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2loader/asset/types"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2util"
@@ -16,11 +21,17 @@ const (
 	sourcePathB   = "testdata/B"
 	sourcePathC   = "testdata/C"
 	sourcePathD   = "testdata/D.mpq"
+	sourcePathE   = "testdata/E.zip"
+	sourcePathF   = "testdata/F.tar.gz"
+	modManifest   = "testdata/mod.json"
 	commonFile    = "common.txt"
 	exclusiveA    = "exclusive_a.txt"
 	exclusiveB    = "exclusive_b.txt"
 	exclusiveC    = "exclusive_c.txt"
 	exclusiveD    = "exclusive_d.txt"
+	exclusiveE    = "exclusive_e.txt"
+	exclusiveF    = "exclusive_f.txt"
+	tableFile     = "dir/table.txt"
 	subdirCommonD = "dir\\common.txt"
 	badSourcePath = "/x/y/z.mpq"
 	badFilePath   = "a/bad/file/path.txt"
@@ -157,3 +168,378 @@ func TestLoader_Load(t *testing.T) {
 		}
 	}
 }
+
+func readAll(t *testing.T, loader *Loader, subPath string) string {
+	t.Helper()
+
+	entry, err := loader.Load(subPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadAll(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+func TestLoader_Archives(t *testing.T) {
+	loader, _ := NewLoader(d2util.LogLevelDefault)
+
+	if err := loader.AddSource(sourcePathE, types.AssetSourceZip); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := loader.AddSource(sourcePathF, types.AssetSourceTarGz); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		subPath string
+		data    string
+	}{
+		{commonFile, "e"},
+		{exclusiveE, "e"},
+		{exclusiveF, "f"},
+		{"\\DIR\\table.TXT", "Name\tValue\nthird\t3\n"},
+	}
+
+	for _, test := range tests {
+		if have := readAll(t, loader, test.subPath); have != test.data {
+			t.Errorf("%s: want %q, have %q", test.subPath, test.data, have)
+		}
+	}
+
+	if !loader.Exists(exclusiveF) || loader.Exists(badFilePath) {
+		t.Error("archive files aren't found as expected")
+	}
+
+	zipSource := loader.ListSources()[0]
+
+	if err := loader.RemoveSource(sourcePathE); err != nil {
+		t.Fatal(err)
+	}
+
+	// removing the source closed the archive file
+	if err := zipSource.(io.Closer).Close(); err == nil {
+		t.Error("want the zip archive closed when the source is removed")
+	}
+
+	if _, err := zipSource.Open(exclusiveE); err == nil {
+		t.Error("want no files of a removed source")
+	}
+}
+
+func TestLoader_Priority(t *testing.T) {
+	loader, _ := NewLoader(d2util.LogLevelDefault)
+
+	sources := []struct {
+		path       string
+		sourceType types.SourceType
+		priority   int
+	}{
+		{sourcePathA, types.AssetSourceFileSystem, 0},
+		{sourcePathB, types.AssetSourceFileSystem, 5},
+		{sourcePathC, types.AssetSourceFileSystem, 5},
+		{sourcePathE, types.AssetSourceZip, -1},
+	}
+
+	for _, source := range sources {
+		if err := loader.AddSourceWithPriority(source.path, source.sourceType, source.priority); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the highest priority wins, the first added of the same priority
+	if have := readAll(t, loader, commonFile); have[:1] != "b" {
+		t.Errorf("want common file from B, have %q", have)
+	}
+
+	if origin := loader.Origin(commonFile); !reflect.DeepEqual(origin, []string{sourcePathB}) {
+		t.Errorf("want common file served by B, have %v", origin)
+	}
+
+	order := make([]int, len(loader.Sources))
+	for idx, source := range loader.Sources {
+		order[idx] = loader.Priority(source)
+	}
+
+	if want := []int{5, 5, 0, -1}; !reflect.DeepEqual(order, want) {
+		t.Errorf("want sources in order of priority %v, have %v", want, order)
+	}
+
+	err := loader.AddSourceWithPriority(sourcePathF, types.AssetSourceTarGz, 0,
+		MergeRule{File: tableFile, Mode: MergePatch})
+	if !errors.Is(err, ErrMergeRule) {
+		t.Errorf("want ErrMergeRule for a patch without key, have %v", err)
+	}
+
+	if err := loader.AddSource(sourcePathA, types.AssetSourceUnknown); !errors.Is(err, ErrSourceType) {
+		t.Errorf("want ErrSourceType, have %v", err)
+	}
+}
+
+func TestLoader_Manifest(t *testing.T) {
+	loader, _ := NewLoader(d2util.LogLevelDefault)
+
+	if err := loader.AddManifest(modManifest); err != nil {
+		t.Fatal(err)
+	}
+
+	if have := readAll(t, loader, commonFile); have != "f" {
+		t.Errorf("want common file from F, have %q", have)
+	}
+
+	// A has the table, E appends a row and F patches the first one
+	want := "Name\tValue\nfirst\t10\nsecond\t2\nthird\t3\n"
+	if have := readAll(t, loader, tableFile); have != want {
+		t.Errorf("want merged table %q, have %q", want, have)
+	}
+
+	origins := loader.Origins()
+
+	wantOrigins := map[string][]string{
+		commonFile: {sourcePathF},
+		tableFile:  {sourcePathF, sourcePathE, sourcePathA},
+	}
+
+	if !reflect.DeepEqual(origins, wantOrigins) {
+		t.Errorf("want origins %v, have %v", wantOrigins, origins)
+	}
+}
+
+func TestLoadManifest(t *testing.T) {
+	tests := []struct {
+		manifest string
+		err      error
+	}{
+		{`{"sources": [{"path": "a", "merge": [{"file": "a.txt", "mode": "append"}]}]}`, nil},
+		{`{"sources": [{"path": "a", "merge": [{"file": "a.txt", "mode": "replace"}]}]}`, ErrMergeMode},
+		{`{"sources": [{"path": "a", "merge": [{"file": "a.dc6", "mode": "append"}]}]}`, ErrMergeRule},
+		{`{"sources": [{"path": "a", "merge": [{"file": "a.txt", "mode": "patch"}]}]}`, ErrMergeRule},
+	}
+
+	for _, test := range tests {
+		if _, err := LoadManifest([]byte(test.manifest)); !errors.Is(err, test.err) {
+			t.Errorf("%s: want %v, have %v", test.manifest, test.err, err)
+		}
+	}
+
+	if _, err := LoadManifest([]byte("{")); err == nil {
+		t.Error("want error for bad json")
+	}
+}
+
+func TestLoader_Cache(t *testing.T) {
+	loader, _ := NewLoader(d2util.LogLevelDefault)
+	dir := t.TempDir()
+
+	if err := ioutil.WriteFile(filepath.Join(dir, commonFile), []byte("x"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := loader.AddSource(dir, types.AssetSourceFileSystem); err != nil {
+		t.Fatal(err)
+	}
+
+	readAll(t, loader, commonFile)
+	readAll(t, loader, commonFile)
+
+	for i := 0; i < 2; i++ {
+		if _, err := loader.Load(exclusiveA); !errors.Is(err, ErrFileNotFound) {
+			t.Fatalf("want ErrFileNotFound, have %v", err)
+		}
+	}
+
+	want := CacheStats{Hits: 1, MissingHits: 1, Misses: 2}
+	if stats := loader.CacheStats(); stats != want {
+		t.Errorf("want stats %+v, have %+v", want, stats)
+	}
+
+	// the file stays missing until the source is invalidated
+	if err := ioutil.WriteFile(filepath.Join(dir, exclusiveA), []byte("x"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := loader.Load(exclusiveA); err == nil {
+		t.Error("want the missing file lookup to be cached")
+	}
+
+	if err := loader.InvalidateSource(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	readAll(t, loader, exclusiveA)
+	readAll(t, loader, commonFile)
+
+	// adding and removing sources drops the files they have
+	if err := loader.AddSourceWithPriority(sourcePathB, types.AssetSourceFileSystem, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if have := readAll(t, loader, commonFile); have[:1] != "b" {
+		t.Errorf("want common file from the added source, have %q", have)
+	}
+
+	if err := loader.RemoveSource(sourcePathB); err != nil {
+		t.Fatal(err)
+	}
+
+	if have := readAll(t, loader, commonFile); have != "x" {
+		t.Errorf("want common file from the remaining source, have %q", have)
+	}
+
+	if err := loader.RemoveSource(sourcePathB); !errors.Is(err, ErrNoSource) {
+		t.Errorf("want ErrNoSource, have %v", err)
+	}
+
+	if stats := loader.CacheStats(); stats.Invalidations != 4 {
+		t.Errorf("want 4 invalidations, have %+v", stats)
+	}
+}
+
+func TestLoader_CacheEviction(t *testing.T) {
+	loader, _ := NewLoader(d2util.LogLevelNone)
+
+	// room for a few files of the test sources
+	loader.Cache = d2cache.CreateCache(64)
+	loader.Cache.SetEvictHandler(loader.evicted)
+
+	if err := loader.AddSource(sourcePathA, types.AssetSourceFileSystem); err != nil {
+		t.Fatal(err)
+	}
+
+	for idx := 0; idx < 100; idx++ {
+		_, _ = loader.Load(fmt.Sprintf("missing%d.txt", idx))
+	}
+
+	readAll(t, loader, commonFile)
+
+	// the loader forgets the files the cache evicted
+	loader.cacheMutex.Lock()
+	files := len(loader.files)
+	loader.cacheMutex.Unlock()
+
+	if files > 64/len("missing0.txt") {
+		t.Errorf("want the evicted files forgotten, have %d files", files)
+	}
+
+	if origin := loader.Origin(commonFile); !reflect.DeepEqual(origin, []string{sourcePathA}) {
+		t.Errorf("want origin of the cached file, have %v", origin)
+	}
+
+	loader.Cache.Clear()
+
+	if origins := loader.Origins(); len(origins) != 0 {
+		t.Errorf("want no origins after clearing the cache, have %v", origins)
+	}
+}
+
+func BenchmarkLoader_Load(b *testing.B) {
+	benchmarks := []struct {
+		name   string
+		cached bool
+	}{
+		{"uncached", false},
+		{"cached", true},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			loader, _ := NewLoader(d2util.LogLevelNone)
+
+			if err := loader.AddManifest(modManifest); err != nil {
+				b.Fatal(err)
+			}
+
+			if err := loader.AddSource(sourcePathD, types.AssetSourceMPQ); err != nil {
+				b.Fatal(err)
+			}
+
+			for i := 0; i < b.N; i++ {
+				if !bm.cached {
+					loader.Cache.Clear()
+				}
+
+				for _, subPath := range []string{tableFile, exclusiveD, subdirCommonD} {
+					if _, err := loader.Load(subPath); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestLoader_Concurrent(t *testing.T) {
+	const loaders = 8
+
+	loader, _ := NewLoader(d2util.LogLevelNone)
+
+	if err := loader.AddManifest(modManifest); err != nil {
+		t.Fatal(err)
+	}
+
+	want := readAll(t, loader, tableFile)
+	done := make(chan struct{})
+
+	// sources change while files are loaded
+	go func() {
+		defer close(done)
+
+		for idx := 0; idx < loaders; idx++ {
+			if err := loader.AddSource(sourcePathD, types.AssetSourceMPQ); err != nil {
+				t.Error(err)
+				return
+			}
+
+			if err := loader.RemoveSource(sourcePathD); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+
+	errs := make(chan error, loaders)
+
+	for idx := 0; idx < loaders; idx++ {
+		go func() {
+			for _, subPath := range []string{tableFile, commonFile, badFilePath} {
+				entry, err := loader.Load(subPath)
+				if subPath == badFilePath {
+					if !errors.Is(err, ErrFileNotFound) {
+						errs <- fmt.Errorf("want ErrFileNotFound, have %v", err)
+						return
+					}
+
+					continue
+				}
+
+				if err != nil {
+					errs <- err
+					return
+				}
+
+				if data, _ := ioutil.ReadAll(entry); subPath == tableFile && string(data) != want {
+					errs <- fmt.Errorf("want merged table %q, have %q", want, data)
+					return
+				}
+			}
+
+			errs <- nil
+		}()
+	}
+
+	for idx := 0; idx < loaders; idx++ {
+		if err := <-errs; err != nil {
+			t.Error(err)
+		}
+	}
+
+	<-done
+
+	_ = loader.Origins()
+	_ = loader.CacheStats()
+}
@@ -0,0 +1,157 @@
+package d2loader
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2loader/asset/types"
+)
+
+var (
+	// ErrMergeMode is returned for merge rules with an unknown mode
+	ErrMergeMode = errors.New("unknown merge mode")
+
+	// ErrMergeRule is returned for merge rules that can't be applied
+	ErrMergeRule = errors.New("invalid merge rule")
+)
+
+// MergeMode tells how a txt table is merged onto the table of the next sources
+type MergeMode string
+
+// Merge modes
+const (
+	// MergeAppend appends the records of the table
+	MergeAppend MergeMode = "append"
+
+	// MergePatch replaces the columns of the table in the records with the same key
+	MergePatch MergeMode = "patch"
+)
+
+// MergeRule is a rule for merging a txt table of a source, instead of replacing the table of the
+// next sources
+type MergeRule struct {
+	// File is the sub-path of the table
+	File string    `json:"file"`
+	Mode MergeMode `json:"mode"`
+	// Key is the column that identifies the records of a patch
+	Key string `json:"key,omitempty"`
+}
+
+func (r MergeRule) validate() error {
+	if types.Ext2AssetType(filepath.Ext(r.File)) != types.AssetTypeDataDictionary {
+		return fmt.Errorf("%w: %s is not a txt table", ErrMergeRule, r.File)
+	}
+
+	switch r.Mode {
+	case MergeAppend:
+	case MergePatch:
+		if r.Key == "" {
+			return fmt.Errorf("%w: patch of %s has no key column", ErrMergeRule, r.File)
+		}
+	default:
+		return fmt.Errorf("%w: %q", ErrMergeMode, r.Mode)
+	}
+
+	return nil
+}
+
+// Manifest declares the asset sources of a mod
+type Manifest struct {
+	Name    string           `json:"name"`
+	Sources []ManifestSource `json:"sources"`
+}
+
+// ManifestSource is an asset source of a mod manifest
+type ManifestSource struct {
+	// Path is the path of the source, relative to the manifest
+	Path string `json:"path"`
+	// Type is one of filesystem, mpq, zip or targz, the type is detected when it's left out
+	Type     string      `json:"type,omitempty"`
+	Priority int         `json:"priority"`
+	Merge    []MergeRule `json:"merge,omitempty"`
+}
+
+// LoadManifest parses a mod manifest
+func LoadManifest(data []byte) (*Manifest, error) {
+	manifest := &Manifest{}
+
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, err
+	}
+
+	for _, source := range manifest.Sources {
+		for _, rule := range source.Merge {
+			if err := rule.validate(); err != nil {
+				return nil, fmt.Errorf("%s: %w", source.Path, err)
+			}
+		}
+	}
+
+	return manifest, nil
+}
+
+// AddManifest adds the sources of the mod manifest at the given path
+func (l *Loader) AddManifest(manifestPath string) error {
+	data, err := ioutil.ReadFile(filepath.Clean(manifestPath))
+	if err != nil {
+		return err
+	}
+
+	manifest, err := LoadManifest(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", manifestPath, err)
+	}
+
+	l.Infof("Adding mod: '%s'", manifest.Name)
+
+	for _, source := range manifest.Sources {
+		sourcePath := source.Path
+		if !filepath.IsAbs(sourcePath) {
+			sourcePath = filepath.Join(filepath.Dir(manifestPath), sourcePath)
+		}
+
+		sourceType, err := manifestSourceType(sourcePath, source.Type)
+		if err != nil {
+			return fmt.Errorf("%s: %w", manifestPath, err)
+		}
+
+		if err := l.AddSourceWithPriority(sourcePath, sourceType, source.Priority, source.Merge...); err != nil {
+			return fmt.Errorf("%s: %w", manifestPath, err)
+		}
+	}
+
+	return nil
+}
+
+// manifestSourceType returns the source type of the given name, or detects it from the path
+func manifestSourceType(sourcePath, name string) (types.SourceType, error) {
+	lookup := map[string]types.SourceType{
+		"filesystem": types.AssetSourceFileSystem,
+		"mpq":        types.AssetSourceMPQ,
+		"zip":        types.AssetSourceZip,
+		"targz":      types.AssetSourceTarGz,
+	}
+
+	if name != "" {
+		if sourceType, found := lookup[strings.ToLower(name)]; found {
+			return sourceType, nil
+		}
+
+		return types.AssetSourceUnknown, fmt.Errorf("%w: %s", ErrSourceType, name)
+	}
+
+	if info, err := os.Stat(sourcePath); err == nil && info.IsDir() {
+		return types.AssetSourceFileSystem, nil
+	}
+
+	if sourceType := types.CheckSourceType(sourcePath); sourceType != types.AssetSourceUnknown {
+		return sourceType, nil
+	}
+
+	return types.AssetSourceUnknown, fmt.Errorf("%w: %s", ErrSourceType, sourcePath)
+}
@@ -49,6 +49,11 @@ func (v *Source) String() string {
 	return v.Path()
 }
 
+// Close closes the MPQ file
+func (v *Source) Close() error {
+	return v.MPQ.Close()
+}
+
 func cleanName(name string) string {
 	name = strings.ReplaceAll(name, "/", "\\")
 
@@ -195,9 +195,13 @@ const (
 	QuestLogAQuestAnimation = "/data/global/ui/MENU/a%dq%d.dc6"
 	QuestLogDoneSfx         = "cursor/questdone.wav"
 
+	// --- Automap ---
+	AutoMapCells = "/data/global/ui/AUTOMAP/MaxiMap.dc6"
+
 	// --- Mouse Pointers ---
 
-	CursorDefault = "/data/global/ui/CURSOR/ohand.DC6"
+	CursorDefault  = "/data/global/ui/CURSOR/ohand.DC6"
+	CursorIdentify = "/data/global/ui/CURSOR/identify.DC6"
 
 	// --- Fonts & Locale (strings) ---
 	Font6                = "/data/local/FONT/" + LanguageFontToken + "/font6"
@@ -250,6 +254,7 @@ const (
 	PartyButton               = "/data/global/ui/MENU/partybuttons.dc6"
 	PartyBoxes                = "/data/global/ui/MENU/partyboxes.dc6"
 	PartyBar                  = "/data/global/ui/MENU/partybar.dc6"
+	HirelingPanel             = "/data/global/ui/PANEL/NPCInv.dc6"
 	HeroStatsPanelStatsPoints = "/data/global/ui/PANEL/skillpoints.dc6"
 	HeroStatsPanelSocket      = "/data/global/ui/PANEL/levelsocket.dc6"
 	InventoryWeaponsTab       = "/data/global/ui/PANEL/invchar6Tab.DC6"
@@ -5,7 +5,12 @@ import (
 	"io"
 	"io/ioutil"
 	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2cache"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2cof"
 
@@ -48,23 +53,29 @@ const (
 )
 
 const (
-	defaultLanguage    = "ENG"
-	logPrefix          = "Asset Manager"
-	fmtLoadAsset       = "could not load file stream %s (%v)"
-	fmtLoadAnimation   = "loading animation %s with palette %s, draw effect %d"
-	fmtLoadComposite   = "loading composite: type %d, token %s, palette %s"
-	fmtLoadFont        = "loading font: table %s, sprite %s, palette %s"
-	fmtLoadPalette     = "loading palette %s"
-	fmtLoadStringTable = "loading string table: %s"
-	fmtLoadTransform   = "loading palette transform: %s"
-	fmtLoadDict        = "loading data dictionary: %s"
+	defaultLanguage         = "ENG"
+	logPrefix               = "Asset Manager"
+	fmtLoadAsset            = "could not load file stream %s (%v)"
+	fmtLoadAnimation        = "loading animation %s with palette %s, draw effect %d"
+	fmtLoadComposite        = "loading composite: type %d, token %s, palette %s"
+	fmtLoadFont             = "loading font: table %s, sprite %s, palette %s"
+	fmtLoadPalette          = "loading palette %s"
+	fmtLoadShiftedPalette   = "loading palette %s shifted by %s, color map %d"
+	fmtLoadShiftedAnimation = "loading animation %s with palette %s shifted by %s, color map %d, draw effect %d"
+	fmtLoadStringTable      = "loading string table: %s"
+	fmtLoadTransform        = "loading palette transform: %s"
+	fmtLoadDict             = "loading data dictionary: %s"
 )
 
-// AssetManager loads files and game objects
+// AssetManager loads files and game objects. Assets can be loaded from several goroutines at
+// once, loads of the same asset that run at the same time are done once.
 type AssetManager struct {
 	*d2util.Logger
 	*d2loader.Loader
 
+	loads            d2cache.FlightGroup
+	prefetches       chan prefetchTask
+	startPrefetch    sync.Once
 	tables           []d2tbl.TextDictionary
 	dt1s             d2interface.Cache
 	ds1s             d2interface.Cache
@@ -77,6 +88,8 @@ type AssetManager struct {
 	Records          *d2records.RecordManager
 	language         string
 	languageModifier int
+	// tablesMutex guards the string tables and the language
+	tablesMutex sync.RWMutex
 }
 
 // SetLogLevel sets the log level for the asset manager,  record manager, and file loader
@@ -142,8 +155,10 @@ func (am *AssetManager) LoadLanguage(languagePath string) string {
 	language := d2resource.GetLanguageLiteral(languageCode)
 	am.Infof("Language: %s", language)
 
+	am.tablesMutex.Lock()
 	am.language = language
 	am.languageModifier = d2resource.GetLabelModifier(language)
+	am.tablesMutex.Unlock()
 
 	return language
 }
@@ -158,18 +173,53 @@ func (am *AssetManager) LoadAnimationWithEffect(animationPath, palettePath strin
 	effect d2enum.DrawEffect) (d2interface.Animation, error) {
 	cachePath := fmt.Sprintf("%s;%s;%d", animationPath, palettePath, effect)
 
-	if animation, found := am.animations.Retrieve(cachePath); found {
-		return animation.(d2interface.Animation).Clone(), nil
+	// every caller gets a clone, the cached animation is shared
+	animation, err := am.loadCached(am.animations, "animation", cachePath, func() (interface{}, error) {
+		am.Debugf(fmtLoadAnimation, animationPath, palettePath, effect)
+
+		palette, err := am.LoadPalette(palettePath)
+		if err != nil {
+			return nil, err
+		}
+
+		return am.loadAnimation(animationPath, palette, effect)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	am.Debugf(fmtLoadAnimation, animationPath, palettePath, effect)
+	return animation.(d2interface.Animation).Clone(), nil
+}
+
+// LoadShiftedAnimation loads an Animation with the palette recolored by one of the color maps
+// of a palshift.dat file
+func (am *AssetManager) LoadShiftedAnimation(animationPath, palettePath, shiftPath string, shift int,
+	effect d2enum.DrawEffect) (d2interface.Animation, error) {
+	cachePath := fmt.Sprintf("%s;%s;%s;%d;%d", animationPath, palettePath, shiftPath, shift, effect)
+
+	animation, err := am.loadCached(am.animations, "animation", cachePath, func() (interface{}, error) {
+		am.Debugf(fmtLoadShiftedAnimation, animationPath, palettePath, shiftPath, shift, effect)
+
+		palette, err := am.LoadShiftedPalette(palettePath, shiftPath, shift)
+		if err != nil {
+			return nil, err
+		}
 
-	palette, err := am.LoadPalette(palettePath)
+		return am.loadAnimation(animationPath, palette, effect)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	var animation d2interface.Animation
+	return animation.(d2interface.Animation).Clone(), nil
+}
+
+func (am *AssetManager) loadAnimation(animationPath string, palette d2interface.Palette,
+	effect d2enum.DrawEffect) (d2interface.Animation, error) {
+	var (
+		animation d2interface.Animation
+		err       error
+	)
 
 	switch types.Ext2AssetType(filepath.Ext(animationPath)) {
 	case types.AssetTypeDC6:
@@ -186,9 +236,7 @@ func (am *AssetManager) LoadAnimationWithEffect(animationPath, palettePath strin
 		return nil, fmt.Errorf("unknown Animation format for file: %s", animationPath)
 	}
 
-	err = am.animations.Insert(cachePath, animation, defaultCacheEntryWeight)
-
-	return animation, err
+	return animation, nil
 }
 
 // LoadComposite creates a composite object from a ObjectLookupRecord and palettePath describing it
@@ -212,10 +260,17 @@ func (am *AssetManager) LoadComposite(baseType d2enum.ObjectType, token, palette
 func (am *AssetManager) LoadFont(tablePath, spritePath, palettePath string) (*d2font.Font, error) {
 	cachePath := fmt.Sprintf("%s;%s;%s", tablePath, spritePath, palettePath)
 
-	if cached, found := am.fonts.Retrieve(cachePath); found {
-		return cached.(*d2font.Font), nil
+	font, err := am.loadCached(am.fonts, "font", cachePath, func() (interface{}, error) {
+		return am.loadFont(tablePath, spritePath, palettePath)
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	return font.(*d2font.Font), nil
+}
+
+func (am *AssetManager) loadFont(tablePath, spritePath, palettePath string) (*d2font.Font, error) {
 	sheet, err := am.LoadAnimation(spritePath, palettePath)
 	if err != nil {
 		return nil, err
@@ -235,36 +290,62 @@ func (am *AssetManager) LoadFont(tablePath, spritePath, palettePath string) (*d2
 
 	font.SetBackground(sheet)
 
-	err = am.fonts.Insert(cachePath, font, defaultCacheEntryWeight)
-
-	return font, err
+	return font, nil
 }
 
 // LoadPalette loads a palette from a given palette path
 func (am *AssetManager) LoadPalette(palettePath string) (d2interface.Palette, error) {
-	if cached, found := am.palettes.Retrieve(palettePath); found {
-		return cached.(d2interface.Palette), nil
-	}
-
 	if types.Ext2AssetType(filepath.Ext(palettePath)) != types.AssetTypePalette {
 		return nil, fmt.Errorf("not an instance of a palette: %s", palettePath)
 	}
 
-	am.Debugf(fmtLoadPalette, palettePath)
+	palette, err := am.loadCached(am.palettes, "palette", palettePath, func() (interface{}, error) {
+		am.Debugf(fmtLoadPalette, palettePath)
+
+		data, err := am.LoadFile(palettePath)
+		if err != nil {
+			return nil, err
+		}
 
-	data, err := am.LoadFile(palettePath)
+		return d2dat.Load(data)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	palette, err := d2dat.Load(data)
+	return palette.(d2interface.Palette), nil
+}
+
+// LoadShiftedPalette loads a palette with its colors remapped by the color map at the given
+// index of a palshift.dat file
+func (am *AssetManager) LoadShiftedPalette(palettePath, shiftPath string, shift int) (d2interface.Palette, error) {
+	cachePath := fmt.Sprintf("%s;%s;%d", palettePath, shiftPath, shift)
+
+	palette, err := am.loadCached(am.palettes, "palette", cachePath, func() (interface{}, error) {
+		am.Debugf(fmtLoadShiftedPalette, palettePath, shiftPath, shift)
+
+		base, err := am.LoadPalette(palettePath)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := am.LoadFile(shiftPath)
+		if err != nil {
+			return nil, err
+		}
+
+		start := shift * base.NumColors()
+		if shift < 0 || start+base.NumColors() > len(data) {
+			return nil, fmt.Errorf("palette shift %d not found in %s", shift, shiftPath)
+		}
+
+		return d2dat.Shift(base, data[start:start+base.NumColors()])
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	err = am.palettes.Insert(palettePath, palette, defaultCacheEntryWeight)
-
-	return palette, err
+	return palette.(d2interface.Palette), nil
 }
 
 // LoadStringTable loads a string table from the given path
@@ -281,7 +362,9 @@ func (am *AssetManager) LoadStringTable(tablePath string) (d2tbl.TextDictionary,
 
 	am.Debugf(fmtLoadStringTable, tablePath)
 
+	am.tablesMutex.Lock()
 	am.tables = append(am.tables, table)
+	am.tablesMutex.Unlock()
 
 	return table, err
 }
@@ -290,6 +373,9 @@ func (am *AssetManager) LoadStringTable(tablePath string) (d2tbl.TextDictionary,
 // the loaded string tables. If input value is int (e.g. from d2enum/numeric_labels.go)
 // output string is translation for # + input
 func (am *AssetManager) TranslateString(input interface{}) string {
+	am.tablesMutex.RLock()
+	defer am.tablesMutex.RUnlock()
+
 	var key string
 
 	switch s := input.(type) {
@@ -314,37 +400,28 @@ func (am *AssetManager) TranslateString(input interface{}) string {
 
 // LoadPaletteTransform loads a palette transform file
 func (am *AssetManager) LoadPaletteTransform(path string) (*d2pl2.PL2, error) {
-	if pl2, found := am.transforms.Retrieve(path); found {
-		return pl2.(*d2pl2.PL2), nil
-	}
+	pl2, err := am.loadCached(am.transforms, "transform", path, func() (interface{}, error) {
+		data, err := am.LoadFile(path)
+		if err != nil {
+			return nil, err
+		}
 
-	data, err := am.LoadFile(path)
-	if err != nil {
-		return nil, err
-	}
+		am.Debugf(fmtLoadTransform, path)
 
-	pl2, err := d2pl2.Load(data)
+		return d2pl2.Load(data)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	am.Debugf(fmtLoadTransform, path)
-
-	if err := am.transforms.Insert(path, pl2, 1); err != nil {
-		return nil, err
-	}
-
-	return pl2, nil
+	return pl2.(*d2pl2.PL2), nil
 }
 
 // LoadDataDictionary loads a txt data file
 func (am *AssetManager) LoadDataDictionary(path string) (*d2txt.DataDictionary, error) {
 	// we purposefully do not cache data dictionaries because we are already
-	// caching the file data. The underlying csv.Reader does not implement io.Seeker,
-	// so after it has been iterated through, we cannot iterate through it again.
-	//
-	// The easy way around this is to not cache d2txt.DataDictionary objects, and just create
-	// a new instance from cached file data if/when we ever need to reload the data dict
+	// caching the file data. A data dictionary keeps its read position and may be
+	// edited, so every caller gets its own instance created from the cached file data
 	data, err := am.LoadFile(path)
 	if err != nil {
 		return nil, err
@@ -352,7 +429,7 @@ func (am *AssetManager) LoadDataDictionary(path string) (*d2txt.DataDictionary,
 
 	am.Debugf(fmtLoadDict, path)
 
-	return d2txt.LoadDataDictionary(data), nil
+	return d2txt.LoadDataDictionary(data)
 }
 
 // LoadRecords will load the records for the given path into the record manager.
@@ -419,12 +496,17 @@ func (am *AssetManager) BindTerminalCommands(term d2interface.Terminal) error {
 		return err
 	}
 
+	if err := term.Bind("assetsources", "list asset sources and which served each asset", nil,
+		am.commandAssetSources(term)); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 // UnbindTerminalCommands unbinds commands from the terminal
 func (am *AssetManager) UnbindTerminalCommands(term d2interface.Terminal) error {
-	return term.Unbind("assetspam", "assetstat", "assetclear")
+	return term.Unbind("assetspam", "assetstat", "assetclear", "assetsources")
 }
 
 func (am *AssetManager) commandAssetSpam(term d2interface.Terminal) func([]string) error {
@@ -449,6 +531,7 @@ func (am *AssetManager) commandAssetSpam(term d2interface.Terminal) func([]strin
 		am.ds1s.SetVerbose(verbose)
 		am.dccs.SetVerbose(verbose)
 		am.cofs.SetVerbose(verbose)
+		am.Loader.Cache.SetVerbose(verbose)
 
 		return nil
 	}
@@ -465,6 +548,35 @@ func (am *AssetManager) commandAssetStat(term d2interface.Terminal) func([]strin
 		term.Infof("palette transform cache: %f", cacheStatistics(am.transforms))
 		term.Infof("Animation cache: %f", cacheStatistics(am.animations))
 		term.Infof("font cache: %f", cacheStatistics(am.fonts))
+		term.Infof("file cache: %f", cacheStatistics(am.Loader.Cache))
+
+		stats := am.Loader.CacheStats()
+		term.Infof("file cache hits: %d, missing file hits: %d, misses: %d, invalidated: %d",
+			stats.Hits, stats.MissingHits, stats.Misses, stats.Invalidations)
+
+		return nil
+	}
+}
+
+func (am *AssetManager) commandAssetSources(term d2interface.Terminal) func([]string) error {
+	return func([]string) error {
+		for _, source := range am.Loader.ListSources() {
+			term.Infof("source %s, priority %d", source.Path(), am.Loader.Priority(source))
+		}
+
+		origins := am.Loader.Origins()
+		paths := make([]string, 0, len(origins))
+
+		for subPath := range origins {
+			paths = append(paths, subPath)
+		}
+
+		sort.Strings(paths)
+
+		// a merged asset lists the source it was merged from first
+		for _, subPath := range paths {
+			term.Infof("%s <- %s", subPath, strings.Join(origins[subPath], " + "))
+		}
 
 		return nil
 	}
@@ -479,98 +591,109 @@ func (am *AssetManager) commandAssetClear([]string) error {
 	am.ds1s.Clear()
 	am.dccs.Clear()
 	am.cofs.Clear()
+	am.Loader.Cache.Clear()
 
 	return nil
 }
 
 // LoadDT1 loads and returns the given path as a DT1
 func (am *AssetManager) LoadDT1(dt1Path string) (*d2dt1.DT1, error) {
-	if dt1Value, found := am.dt1s.Retrieve(dt1Path); found {
-		return dt1Value.(*d2dt1.DT1), nil
-	}
-
-	fileData, err := am.LoadFile("/data/global/tiles/" + dt1Path)
-	if err != nil {
-		return nil, fmt.Errorf("could not load /data/global/tiles/%s", dt1Path)
-	}
+	dt1, err := am.loadCached(am.dt1s, "dt1", dt1Path, func() (interface{}, error) {
+		fileData, err := am.LoadFile("/data/global/tiles/" + dt1Path)
+		if err != nil {
+			return nil, fmt.Errorf("could not load /data/global/tiles/%s", dt1Path)
+		}
 
-	dt1, err := d2dt1.LoadDT1(fileData)
+		return d2dt1.LoadDT1(fileData)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if err := am.dt1s.Insert(dt1Path, dt1, defaultCacheEntryWeight); err != nil {
-		return nil, err
-	}
-
-	return dt1, nil
+	return dt1.(*d2dt1.DT1), nil
 }
 
 // LoadDS1 loads and returns the given path as a DS1
 func (am *AssetManager) LoadDS1(ds1Path string) (*d2ds1.DS1, error) {
-	if ds1Value, found := am.dt1s.Retrieve(ds1Path); found {
-		return ds1Value.(*d2ds1.DS1), nil
-	}
+	ds1, err := am.loadCached(am.ds1s, "ds1", ds1Path, func() (interface{}, error) {
+		fileData, err := am.LoadFile("/data/global/tiles/" + ds1Path)
+		if err != nil {
+			return nil, err
+		}
 
-	fileData, err := am.LoadFile("/data/global/tiles/" + ds1Path)
-	if err != nil {
-		return nil, err
-	}
+		ds1, err := d2ds1.Unmarshal(fileData)
+		if err != nil {
+			return nil, fmt.Errorf("loading ds1 file %s: %v", "/data/global/tiles"+ds1Path, err)
+		}
 
-	ds1, err := d2ds1.Unmarshal(fileData)
+		return ds1, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("loading ds1 file %s: %v", "/data/global/tiles"+ds1Path, err)
-	}
-
-	if err := am.dt1s.Insert(ds1Path, ds1, defaultCacheEntryWeight); err != nil {
 		return nil, err
 	}
 
-	return ds1, nil
+	return ds1.(*d2ds1.DS1), nil
 }
 
 // LoadCOF loads and returns the given path as a COF
 func (am *AssetManager) LoadCOF(cofPath string) (*d2cof.COF, error) {
-	if cofValue, found := am.cofs.Retrieve(cofPath); found {
-		return cofValue.(*d2cof.COF), nil
-	}
-
-	fileData, err := am.LoadFile(cofPath)
-	if err != nil {
-		return nil, err
-	}
+	cof, err := am.loadCached(am.cofs, "cof", cofPath, func() (interface{}, error) {
+		fileData, err := am.LoadFile(cofPath)
+		if err != nil {
+			return nil, err
+		}
 
-	cof, err := d2cof.Unmarshal(fileData)
+		return d2cof.Unmarshal(fileData)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if err := am.cofs.Insert(cofPath, cof, defaultCacheEntryWeight); err != nil {
-		return nil, err
-	}
-
-	return cof, nil
+	return cof.(*d2cof.COF), nil
 }
 
 // LoadDCC loads and returns the given path as a DCC
 func (am *AssetManager) LoadDCC(dccPath string) (*d2dcc.DCC, error) {
-	if dccValue, found := am.dccs.Retrieve(dccPath); found {
-		return dccValue.(*d2dcc.DCC), nil
-	}
+	dcc, err := am.loadCached(am.dccs, "dcc", dccPath, func() (interface{}, error) {
+		fileData, err := am.LoadFile(dccPath)
+		if err != nil {
+			return nil, err
+		}
 
-	fileData, err := am.LoadFile(dccPath)
+		return d2dcc.Load(fileData)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	dcc, err := d2dcc.Load(fileData)
-	if err != nil {
-		return nil, err
-	}
+	return dcc.(*d2dcc.DCC), nil
+}
 
-	if err := am.dccs.Insert(dccPath, dcc, defaultCacheEntryWeight); err != nil {
-		return nil, err
+// loadCached returns the object cached under the key, or loads and caches it. Loads of the same
+// kind and key that run at the same time wait for the first one and share its object.
+func (am *AssetManager) loadCached(cache d2interface.Cache, kind, key string,
+	load func() (interface{}, error)) (interface{}, error) {
+	if value, found := cache.Retrieve(key); found {
+		return value, nil
 	}
 
-	return dcc, nil
+	value, _, err := am.loads.Do(kind+";"+key, func() (interface{}, error) {
+		// a load that finished just before this one started may have cached the object
+		if value, found := cache.Retrieve(key); found {
+			return value, nil
+		}
+
+		value, err := load()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := cache.Insert(key, value, defaultCacheEntryWeight); err != nil {
+			return nil, err
+		}
+
+		return value, nil
+	})
+
+	return value, err
 }
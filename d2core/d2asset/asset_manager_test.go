@@ -0,0 +1,150 @@
+package d2asset
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2cof"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2loader/asset/types"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2util"
+)
+
+const (
+	testPalette = "/pal.dat"
+	testFile    = "/file.txt"
+	testMissing = "/missing.dat"
+	testCOF     = "/data/global/monsters/xx/COF/xxNUHTH.COF"
+	paletteSize = 768
+)
+
+func testAssetManager(t *testing.T) *AssetManager {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	// a COF with a torso layer, for the composite prefetch
+	cof := d2cof.New()
+	cof.NumberOfLayers = 1
+	cof.FramesPerDirection = 1
+	cof.NumberOfDirections = 1
+	cof.CofLayers = []d2cof.CofLayer{{Type: d2enum.CompositeTypeTorso, WeaponClass: d2enum.WeaponClassHandToHand}}
+	cof.AnimationFrames = []d2enum.AnimationFrame{d2enum.AnimationFrameNoEvent}
+	cof.Priority = [][][]d2enum.CompositeType{{{d2enum.CompositeTypeTorso}}}
+
+	files := map[string][]byte{
+		testPalette: make([]byte, paletteSize),
+		testFile:    []byte("file"),
+		testCOF:     cof.Marshal(),
+	}
+
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ioutil.WriteFile(path, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	am, err := NewAssetManager(d2util.LogLevelNone)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := am.AddSource(dir, types.AssetSourceFileSystem); err != nil {
+		t.Fatal(err)
+	}
+
+	return am
+}
+
+func TestAssetManagerConcurrentLoads(t *testing.T) {
+	const loaders = 16
+
+	am := testAssetManager(t)
+	palettes := make([]d2interface.Palette, loaders)
+
+	var wg sync.WaitGroup
+
+	for idx := 0; idx < loaders; idx++ {
+		wg.Add(1)
+
+		go func(idx int) {
+			defer wg.Done()
+
+			palette, err := am.LoadPalette(testPalette)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			palettes[idx] = palette
+
+			if data, err := am.LoadFile(testFile); err != nil || string(data) != "file" {
+				t.Errorf("want file content, have %q, error %v", data, err)
+			}
+
+			am.TranslateString("key")
+		}(idx)
+	}
+
+	wg.Wait()
+
+	// every load shares the palette that was loaded first
+	for _, palette := range palettes {
+		if palette != palettes[0] {
+			t.Fatal("want the same palette for every load")
+		}
+	}
+}
+
+func TestAssetManagerPrefetch(t *testing.T) {
+	am := testAssetManager(t)
+
+	if err := am.Prefetch(testPalette, testFile).Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, found := am.palettes.Retrieve(testPalette); !found {
+		t.Error("want the palette cached by the prefetch")
+	}
+
+	if err := am.Prefetch(testFile, testMissing).Wait(); err == nil {
+		t.Error("want an error for prefetching a missing file")
+	}
+
+	if err := am.Prefetch().Wait(); err != nil {
+		t.Errorf("want no error for prefetching nothing, have %v", err)
+	}
+}
+
+func TestAssetManagerPrefetchComposite(t *testing.T) {
+	am := testAssetManager(t)
+
+	var equipment [d2enum.CompositeTypeMax][]string
+
+	equipment[d2enum.CompositeTypeTorso] = []string{"med", "hvy"}
+
+	// the COF loads, then a DCC is prefetched for each torso option, which the test doesn't have
+	err := am.PrefetchComposite(d2enum.ObjectTypeCharacter, "xx", "NU", "HTH", &equipment).Wait()
+	if err == nil || !strings.Contains(strings.ToLower(err.Error()), "xxtr") {
+		t.Fatalf("want an error for the missing torso DCC, have %v", err)
+	}
+
+	if _, found := am.cofs.Retrieve(testCOF); !found {
+		t.Error("want the COF cached by the prefetch")
+	}
+
+	if stats := am.Loader.CacheStats(); stats.Misses != 3 {
+		t.Errorf("want the COF and 2 DCC files loaded, have %+v", stats)
+	}
+}
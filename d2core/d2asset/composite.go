@@ -24,6 +24,7 @@ type Composite struct {
 	basePath    string
 	token       string
 	palettePath string
+	shift       int
 	direction   int
 	equipment   [d2enum.CompositeTypeMax]string
 	mode        *compositeMode
@@ -127,6 +128,12 @@ func (c *Composite) SetMode(animationMode animationMode, weaponClass string) err
 	return nil
 }
 
+// SetPaletteShift recolors the composite with one of the color maps of the palshift.dat file of
+// its token, zero keeps the colors of the palette. It must be set before the mode.
+func (c *Composite) SetPaletteShift(shift int) {
+	c.shift = shift
+}
+
 // Equip changes the current layer configuration
 func (c *Composite) Equip(equipment *[d2enum.CompositeTypeMax]string) error {
 	c.equipment = *equipment
@@ -324,6 +331,16 @@ func (c *Composite) loadCompositeLayer(layerKey, layerValue, animationMode, weap
 			return nil, fmt.Errorf("animation path '%s' not found: %v", animationPaths[idx], err)
 		}
 
+		if c.shift > 0 {
+			shiftPath := fmt.Sprintf("%s/%s/COF/palshift.dat", c.basePath, c.token)
+
+			// tokens without a palshift.dat keep the colors of the palette
+			animation, err := c.LoadShiftedAnimation(animationPaths[idx], palettePath, shiftPath, c.shift, drawEffect)
+			if err == nil {
+				return animation, nil
+			}
+		}
+
 		animation, err := c.LoadAnimationWithEffect(animationPaths[idx], palettePath, drawEffect)
 		if err == nil {
 			return animation, nil
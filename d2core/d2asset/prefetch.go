@@ -0,0 +1,171 @@
+package d2asset
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2loader/asset/types"
+)
+
+const (
+	prefetchQueueLength = 256
+)
+
+// prefetchTask is an asset of a Prefetch, which a worker loads
+type prefetchTask struct {
+	load     func() error
+	prefetch *Prefetch
+}
+
+// Prefetch is a set of assets loaded in the background
+type Prefetch struct {
+	done  sync.WaitGroup
+	err   error
+	mutex sync.Mutex
+}
+
+// Wait waits until all assets are loaded, it returns the first error of the loads
+func (p *Prefetch) Wait() error {
+	p.done.Wait()
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.err
+}
+
+func (p *Prefetch) finish(err error) {
+	if err != nil {
+		p.mutex.Lock()
+
+		if p.err == nil {
+			p.err = err
+		}
+
+		p.mutex.Unlock()
+	}
+
+	p.done.Done()
+}
+
+// Prefetch loads and caches assets in the background, on a pool of workers. The paths are
+// those of the load function of the asset type, so DT1 and DS1 paths are relative to the tiles
+// directory. Files of other types are only cached by the loader. Prefetch doesn't block, the
+// returned Prefetch tells when the assets are loaded.
+func (am *AssetManager) Prefetch(paths ...string) *Prefetch {
+	am.startPrefetch.Do(am.startPrefetchWorkers)
+
+	loads := make([]func() error, len(paths))
+
+	for idx := range paths {
+		path := paths[idx]
+		loads[idx] = func() error {
+			return am.prefetch(path)
+		}
+	}
+
+	prefetch := &Prefetch{}
+	am.queuePrefetch(prefetch, loads...)
+
+	return prefetch
+}
+
+// PrefetchComposite loads the COF of a composite mode in the background, then the DCC files of
+// its layers for every equipment option of the layer. Layers without options use "lit", like
+// Composite does.
+func (am *AssetManager) PrefetchComposite(baseType d2enum.ObjectType, token, mode, weaponClass string,
+	equipment *[d2enum.CompositeTypeMax][]string) *Prefetch {
+	am.startPrefetch.Do(am.startPrefetchWorkers)
+
+	basePath := baseString(baseType)
+	cofPath := fmt.Sprintf("%s/%s/COF/%s%s%s.COF", basePath, token, token, mode, weaponClass)
+	prefetch := &Prefetch{}
+
+	am.queuePrefetch(prefetch, func() error {
+		cof, err := am.LoadCOF(cofPath)
+		if err != nil {
+			am.Debugf("prefetching %s: %v", cofPath, err)
+			return err
+		}
+
+		loads := make([]func() error, 0, len(cof.CofLayers))
+
+		for _, cofLayer := range cof.CofLayers {
+			options := equipment[cofLayer.Type]
+			if len(options) == 0 {
+				options = []string{"lit"}
+			}
+
+			layerKey := cofLayer.Type.String()
+
+			for _, option := range options {
+				dccPath := fmt.Sprintf("%s/%s/%s/%s%s%s%s%s.dcc", basePath, token, layerKey, token, layerKey,
+					option, mode, cofLayer.WeaponClass.String())
+				loads = append(loads, func() error {
+					return am.prefetch(dccPath)
+				})
+			}
+		}
+
+		am.queuePrefetch(prefetch, loads...)
+
+		return nil
+	})
+
+	return prefetch
+}
+
+// queuePrefetch adds loads to a prefetch and queues them for the workers, without blocking
+func (am *AssetManager) queuePrefetch(prefetch *Prefetch, loads ...func() error) {
+	prefetch.done.Add(len(loads))
+
+	go func() {
+		for _, load := range loads {
+			am.prefetches <- prefetchTask{load: load, prefetch: prefetch}
+		}
+	}()
+}
+
+// startPrefetchWorkers starts a worker per CPU, the workers run as long as the program
+func (am *AssetManager) startPrefetchWorkers() {
+	am.prefetches = make(chan prefetchTask, prefetchQueueLength)
+
+	for idx := 0; idx < runtime.NumCPU(); idx++ {
+		go func() {
+			for task := range am.prefetches {
+				task.prefetch.finish(task.load())
+			}
+		}()
+	}
+}
+
+// prefetch loads an asset with the load function of its type
+func (am *AssetManager) prefetch(path string) error {
+	var err error
+
+	switch types.Ext2AssetType(filepath.Ext(path)) {
+	case types.AssetTypeDT1:
+		_, err = am.LoadDT1(path)
+	case types.AssetTypeDS1:
+		_, err = am.LoadDS1(path)
+	case types.AssetTypeDCC:
+		_, err = am.LoadDCC(path)
+	case types.AssetTypeCOF:
+		_, err = am.LoadCOF(path)
+	case types.AssetTypePalette:
+		_, err = am.LoadPalette(path)
+	case types.AssetTypePaletteTransform:
+		_, err = am.LoadPaletteTransform(path)
+	default:
+		_, err = am.Loader.Load(path)
+	}
+
+	if err != nil {
+		am.Debugf("prefetching %s: %v", path, err)
+	}
+
+	return err
+}
@@ -0,0 +1,82 @@
+package ebiten
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+const (
+	outputChannels   = 2
+	bytesPerSample   = 2
+	maxInputChannels = 2
+)
+
+var errInvalidPCM = errors.New("invalid pcm format")
+
+// PCMSound represents an ebiten implementation of a sound of decoded samples
+type PCMSound struct {
+	*SoundEffect
+}
+
+// Position returns how far the sound was played, in seconds
+func (v *PCMSound) Position() float64 {
+	return v.player.Current().Seconds()
+}
+
+// LoadPCM creates a sound of interleaved 16 bit samples, it is played at the volume of the music
+func (eap *AudioProvider) LoadPCM(samples []int16, rate, channels int) (d2interface.PCMSound, error) {
+	if rate <= 0 || channels < 1 || channels > maxInputChannels {
+		return nil, fmt.Errorf("%w: %d channels at %d Hz", errInvalidPCM, channels, rate)
+	}
+
+	stream := newPanStreamFromReader(bytes.NewReader(resamplePCM(samples, rate, channels)))
+
+	player, err := audio.NewPlayer(eap.audioContext, stream)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PCMSound{SoundEffect: &SoundEffect{player: player, panStream: stream}}
+
+	result.volumeScale = eap.bgmVolume
+	result.SetVolume(eap.bgmVolume)
+
+	return result, nil
+}
+
+// resamplePCM converts samples to the stereo little endian bytes of the audio context, with
+// linear interpolation between the samples
+func resamplePCM(samples []int16, rate, channels int) []byte {
+	frames := len(samples) / channels
+	outFrames := int(int64(frames) * sampleRate / int64(rate))
+	out := make([]byte, outFrames*outputChannels*bytesPerSample)
+
+	sample := func(frame, channel int) float64 {
+		if frame >= frames {
+			frame = frames - 1
+		}
+
+		return float64(samples[frame*channels+channel%channels])
+	}
+
+	for i := 0; i < outFrames; i++ {
+		pos := float64(i) * float64(rate) / sampleRate
+		frame := int(pos)
+		t := pos - float64(frame)
+
+		for ch := 0; ch < outputChannels; ch++ {
+			v := int16(sample(frame, ch)*(1-t) + sample(frame+1, ch)*t)
+			offset := (i*outputChannels + ch) * bytesPerSample
+
+			out[offset] = byte(v)
+			out[offset+1] = byte(v >> bitsPerByte)
+		}
+	}
+
+	return out
+}
@@ -230,10 +230,15 @@ func (s *SoundEngine) PlaySoundID(id int) *Sound {
 	return &snd
 }
 
-// PlaySoundHandle plays a sound by sounds.txt handle
+// PlaySoundHandle plays a sound by sounds.txt handle, returns nil if there is no sound with the handle
 func (s *SoundEngine) PlaySoundHandle(handle string) *Sound {
-	sound := s.asset.Records.Sound.Details[handle].Index
-	return s.PlaySoundID(sound)
+	entry, found := s.asset.Records.Sound.Details[handle]
+	if !found {
+		s.Warningf("no sound with the handle %s", handle)
+		return nil
+	}
+
+	return s.PlaySoundID(entry.Index)
 }
 
 func (s *SoundEngine) commandPlaySoundID(args []string) error {
@@ -0,0 +1,3 @@
+// Package d2automap provides the automap state of a hero, the tiles of each level which the
+// hero has explored.
+package d2automap
@@ -0,0 +1,145 @@
+package d2automap
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// tile positions are packed into a single int, tile coordinates are smaller than the stride
+const tileStride = 1 << 16
+
+// NewState creates the automap state of a new hero, with nothing explored
+func NewState() *State {
+	return &State{Levels: make(map[int]*Level)}
+}
+
+// State is the serializable automap state of a hero, the explored tiles by level id. The tile
+// positions only hold for the map layout of the seed they were explored with.
+type State struct {
+	Seed   int64          `json:"seed"`
+	Levels map[int]*Level `json:"levels"`
+}
+
+// Level is the set of explored tiles of a level. It is saved as a sorted list of the packed
+// tile positions.
+type Level struct {
+	tiles map[int]struct{}
+}
+
+// Reseed drops the explored tiles when the map seed changed, as the map layout changed with it,
+// and returns true if they were dropped
+func (s *State) Reseed(seed int64) bool {
+	if s.Seed == seed {
+		return false
+	}
+
+	s.Seed = seed
+	s.Levels = make(map[int]*Level)
+
+	return true
+}
+
+func packTile(x, y int) int {
+	return y*tileStride + x
+}
+
+func (s *State) level(levelID int) *Level {
+	if s.Levels == nil {
+		s.Levels = make(map[int]*Level)
+	}
+
+	level, found := s.Levels[levelID]
+	if !found {
+		level = &Level{tiles: make(map[int]struct{})}
+		s.Levels[levelID] = level
+	}
+
+	return level
+}
+
+// Explore marks the tiles of the level within the radius of the given tile as explored, and
+// returns the number of tiles which weren't explored before
+func (s *State) Explore(levelID, x, y, radius int) int {
+	level := s.level(levelID)
+	explored := 0
+
+	for ty := y - radius; ty <= y+radius; ty++ {
+		for tx := x - radius; tx <= x+radius; tx++ {
+			dx, dy := tx-x, ty-y
+			if tx < 0 || ty < 0 || tx >= tileStride || dx*dx+dy*dy > radius*radius {
+				continue
+			}
+
+			if level.add(tx, ty) {
+				explored++
+			}
+		}
+	}
+
+	return explored
+}
+
+// IsExplored returns true if the tile of the level was explored
+func (s *State) IsExplored(levelID, x, y int) bool {
+	level, found := s.Levels[levelID]
+	if !found || x < 0 || y < 0 || x >= tileStride {
+		return false
+	}
+
+	_, explored := level.tiles[packTile(x, y)]
+
+	return explored
+}
+
+// Explored returns the number of explored tiles of the level
+func (s *State) Explored(levelID int) int {
+	level, found := s.Levels[levelID]
+	if !found {
+		return 0
+	}
+
+	return len(level.tiles)
+}
+
+func (l *Level) add(x, y int) bool {
+	if l.tiles == nil {
+		l.tiles = make(map[int]struct{})
+	}
+
+	key := packTile(x, y)
+	if _, found := l.tiles[key]; found {
+		return false
+	}
+
+	l.tiles[key] = struct{}{}
+
+	return true
+}
+
+// MarshalJSON writes the explored tiles as a sorted list
+func (l *Level) MarshalJSON() ([]byte, error) {
+	tiles := make([]int, 0, len(l.tiles))
+	for key := range l.tiles {
+		tiles = append(tiles, key)
+	}
+
+	sort.Ints(tiles)
+
+	return json.Marshal(tiles)
+}
+
+// UnmarshalJSON reads the explored tiles from a list
+func (l *Level) UnmarshalJSON(data []byte) error {
+	var tiles []int
+	if err := json.Unmarshal(data, &tiles); err != nil {
+		return err
+	}
+
+	l.tiles = make(map[int]struct{}, len(tiles))
+
+	for _, key := range tiles {
+		l.tiles[key] = struct{}{}
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package d2automap
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestStateExplore(t *testing.T) {
+	const town, wilderness = 1, 2
+
+	state := NewState()
+
+	if explored := state.Explore(town, 10, 10, 1); explored != 5 {
+		t.Fatalf("unexpected number of explored tiles, want 5, have %d", explored)
+	}
+
+	if explored := state.Explore(town, 10, 10, 1); explored != 0 {
+		t.Errorf("tiles explored twice, want 0 new tiles, have %d", explored)
+	}
+
+	tests := []struct {
+		level, x, y int
+		explored    bool
+	}{
+		{town, 10, 10, true},
+		{town, 11, 10, true},
+		{town, 10, 9, true},
+		{town, 11, 11, false},
+		{wilderness, 10, 10, false},
+		{town, -1, 10, false},
+	}
+
+	for _, test := range tests {
+		if explored := state.IsExplored(test.level, test.x, test.y); explored != test.explored {
+			t.Errorf("tile %d,%d of level %d: want explored %t, have %t", test.x, test.y, test.level,
+				test.explored, explored)
+		}
+	}
+
+	if state.Explore(town, 0, 0, 1) != 3 {
+		t.Errorf("tiles with negative positions can't be explored")
+	}
+}
+
+func TestStateJSON(t *testing.T) {
+	state := NewState()
+	state.Explore(1, 3, 4, 2)
+	state.Explore(5, 700, 300, 3)
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := &State{}
+	if err := json.Unmarshal(data, loaded); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, level := range []int{1, 5} {
+		if loaded.Explored(level) != state.Explored(level) {
+			t.Errorf("level %d: want %d explored tiles, have %d", level, state.Explored(level),
+				loaded.Explored(level))
+		}
+	}
+
+	if !loaded.IsExplored(5, 702, 301) || loaded.IsExplored(5, 704, 300) {
+		t.Errorf("explored tiles changed after loading")
+	}
+}
+
+func TestStateReseed(t *testing.T) {
+	state := NewState()
+	state.Reseed(42)
+	state.Explore(1, 10, 10, 1)
+
+	if state.Reseed(42) || !state.IsExplored(1, 10, 10) {
+		t.Fatalf("explored tiles dropped for the same seed")
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := &State{}
+	if err := json.Unmarshal(data, loaded); err != nil {
+		t.Fatal(err)
+	}
+
+	if !loaded.Reseed(7) || loaded.Explored(1) != 0 || loaded.Seed != 7 {
+		t.Errorf("want no explored tiles with a new seed, have %d", loaded.Explored(1))
+	}
+}
@@ -0,0 +1,126 @@
+package d2combat
+
+import (
+	"math/rand"
+)
+
+const (
+	percent = 100
+
+	// the chance to hit is always between these
+	minHitChance = 5
+	maxHitChance = 95
+
+	// the chance to block can't go above this
+	maxBlockChance = 75
+	// dexterity below this doesn't help blocking
+	blockDexterityBase = 15
+
+	// the attack rating of the heroes is this many points per point of dexterity, less the base
+	attackRatingPerDexterity = 5
+	attackRatingBase         = 35
+
+	// the defense of the heroes is a quarter of their dexterity
+	defensePerDexterity = 4
+
+	// unarmed attacks deal this damage
+	unarmedDamageMin = 1
+	unarmedDamageMax = 2
+)
+
+// HitChance returns the chance in percent of an attack to hit, from the attack rating and level
+// of the attacker, and the defense and level of the defender
+func HitChance(attackRating, attackerLevel, defense, defenderLevel int) int {
+	if attackRating <= 0 || attackerLevel <= 0 {
+		return minHitChance
+	}
+
+	if defense < 0 {
+		defense = 0
+	}
+
+	if defenderLevel < 0 {
+		defenderLevel = 0
+	}
+
+	// nolint:gomnd // 200% of the rating ratio times the level ratio
+	chance := 2 * percent * attackRating * attackerLevel /
+		((attackRating + defense) * (attackerLevel + defenderLevel))
+
+	return clamp(chance, minHitChance, maxHitChance)
+}
+
+// BlockChance returns the chance in percent to block an attack, from the block of the shield,
+// and the dexterity and level of the defender
+func BlockChance(block, dexterity, level int) int {
+	if block <= 0 || level <= 0 || dexterity <= blockDexterityBase {
+		return 0
+	}
+
+	// nolint:gomnd // the level counts twice
+	return clamp(block*(dexterity-blockDexterityBase)/(level*2), 0, maxBlockChance)
+}
+
+// AttackRating returns the attack rating of a hero from their dexterity
+func AttackRating(dexterity int) int {
+	if rating := dexterity*attackRatingPerDexterity - attackRatingBase; rating > 0 {
+		return rating
+	}
+
+	return 0
+}
+
+// Defense returns the defense of a hero from their dexterity and the defense of their armor
+func Defense(dexterity, armor int) int {
+	return dexterity/defensePerDexterity + armor
+}
+
+// StrengthBonus raises the damage of a melee attack by one percent per point of strength
+func StrengthBonus(damage, strength int) int {
+	if strength <= 0 {
+		return damage
+	}
+
+	return damage * (percent + strength) / percent
+}
+
+// ApplyResistance reduces the damage by the resistance in percent, a resistance of 100 or
+// more makes the defender immune
+func ApplyResistance(damage, resistance int) int {
+	if resistance >= percent {
+		return 0
+	}
+
+	return damage * (percent - resistance) / percent
+}
+
+// Roll returns true if a roll of 0 to 99 is below the chance
+func Roll(r *rand.Rand, chance int) bool {
+	return r.Intn(percent) < chance
+}
+
+// RollDamage returns the damage between min and max, unarmed damage is used when there is no
+// damage range
+func RollDamage(r *rand.Rand, min, max int) int {
+	if max <= 0 {
+		min, max = unarmedDamageMin, unarmedDamageMax
+	}
+
+	if min > max {
+		min = max
+	}
+
+	return min + r.Intn(max-min+1)
+}
+
+func clamp(value, min, max int) int {
+	if value < min {
+		return min
+	}
+
+	if value > max {
+		return max
+	}
+
+	return value
+}
@@ -0,0 +1,99 @@
+package d2combat
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestHitChance(t *testing.T) {
+	tests := []struct {
+		name          string
+		attackRating  int
+		attackerLevel int
+		defense       int
+		defenderLevel int
+		want          int
+	}{
+		{"even", 100, 10, 100, 10, 50},
+		{"no defense", 100, 10, 0, 10, 95},
+		{"no attack rating", 0, 10, 100, 10, 5},
+		{"high defense", 10, 1, 1000, 30, 5},
+		{"high level", 100, 30, 100, 10, 75},
+	}
+
+	for _, test := range tests {
+		if have := HitChance(test.attackRating, test.attackerLevel, test.defense, test.defenderLevel); have != test.want {
+			t.Errorf("%s: want %d, have %d", test.name, test.want, have)
+		}
+	}
+}
+
+func TestBlockChance(t *testing.T) {
+	tests := []struct {
+		name                    string
+		block, dexterity, level int
+		want                    int
+	}{
+		{"no shield", 0, 100, 10, 0},
+		{"base dexterity", 50, 15, 10, 0},
+		{"some dexterity", 20, 35, 1, 75},
+		{"high level", 20, 35, 10, 20},
+	}
+
+	for _, test := range tests {
+		if have := BlockChance(test.block, test.dexterity, test.level); have != test.want {
+			t.Errorf("%s: want %d, have %d", test.name, test.want, have)
+		}
+	}
+}
+
+func TestApplyResistance(t *testing.T) {
+	tests := []struct {
+		damage, resistance, want int
+	}{
+		{100, 0, 100},
+		{100, 25, 75},
+		{100, 100, 0},
+		{100, 150, 0},
+		{100, -50, 150},
+	}
+
+	for _, test := range tests {
+		if have := ApplyResistance(test.damage, test.resistance); have != test.want {
+			t.Errorf("%d damage, %d resistance: want %d, have %d", test.damage, test.resistance, test.want, have)
+		}
+	}
+}
+
+func TestRollDamage(t *testing.T) {
+	// nolint:gosec // not concerned with crypto-strong randomness
+	r := rand.New(rand.NewSource(1))
+
+	for idx := 0; idx < 100; idx++ {
+		if damage := RollDamage(r, 3, 7); damage < 3 || damage > 7 {
+			t.Fatalf("want damage between 3 and 7, have %d", damage)
+		}
+
+		if damage := RollDamage(r, 0, 0); damage < unarmedDamageMin || damage > unarmedDamageMax {
+			t.Fatalf("want unarmed damage, have %d", damage)
+		}
+	}
+}
+
+func TestHeroRatings(t *testing.T) {
+	if have := AttackRating(20); have != 65 {
+		t.Errorf("want an attack rating of 65, have %d", have)
+	}
+
+	if have := AttackRating(5); have != 0 {
+		t.Errorf("want no negative attack rating, have %d", have)
+	}
+
+	if have := Defense(20, 10); have != 15 {
+		t.Errorf("want a defense of 15, have %d", have)
+	}
+
+	if have := StrengthBonus(10, 50); have != 15 {
+		t.Errorf("want 15 damage, have %d", have)
+	}
+}
@@ -0,0 +1,3 @@
+// Package d2combat provides the formulas of melee combat: the chance to hit, the damage
+// dealt and the chance to block.
+package d2combat
@@ -10,6 +10,7 @@ import (
 type Configuration struct {
 	MpqLoadOrder    []string
 	MpqPath         string
+	ModManifests    []string
 	TicksPerSecond  int
 	FpsCap          int
 	SfxVolume       float64
@@ -0,0 +1,144 @@
+package d2dialogue
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2quest"
+)
+
+// TopicType is the kind of a dialogue topic
+type TopicType int
+
+// Topic types
+const (
+	// TopicIntro is the introduction of the npc
+	TopicIntro TopicType = iota
+	// TopicGossip is small talk, the npc has a few lines which are told in turn
+	TopicGossip
+	// TopicQuest is about a quest, it is only available in some statuses of the quest
+	TopicQuest
+)
+
+// noQuest is the quest id of the topics which are not about a quest
+const noQuest = -1
+
+// Topic is something an npc can talk about. The speech is looked up by its sounds.txt handle,
+// and the text is looked up in the string tables by the same key.
+type Topic struct {
+	Type TopicType
+	// Speech is the sounds.txt handle of the speech, and the string table key of the text
+	Speech string
+	// Quest is the id of the quest the topic is about, or -1. Talking about a quest
+	// topic takes the step of the quest.
+	Quest int
+
+	statuses []int
+}
+
+// IsAvailable returns true if the npc can talk about the topic, given the quest state of the hero
+func (t *Topic) IsAvailable(quests *d2quest.State, difficulty d2enum.DifficultyType) bool {
+	if t.Type != TopicQuest {
+		return true
+	}
+
+	status := d2enum.QuestStatusNotStarted
+	if quests != nil {
+		status = quests.Status(difficulty, t.Quest)
+	}
+
+	for _, available := range t.statuses {
+		if available == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Tree is the dialogue tree of an npc
+type Tree struct {
+	// NPC is the monstats.txt id of the npc
+	NPC string
+	// Act is the act the npc is in
+	Act    int
+	Intro  *Topic
+	Gossip []*Topic
+	Quests []*Topic
+}
+
+// Topics returns the topics the npc can talk about: the introduction, one of the gossip lines
+// chosen by the number of times the hero talked to the npc, and the available quest topics
+func (t *Tree) Topics(quests *d2quest.State, difficulty d2enum.DifficultyType, talkCount int) []*Topic {
+	topics := []*Topic{t.Intro}
+
+	if len(t.Gossip) > 0 {
+		topics = append(topics, t.Gossip[talkCount%len(t.Gossip)])
+	}
+
+	for _, topic := range t.Quests {
+		if topic.IsAvailable(quests, difficulty) {
+			topics = append(topics, topic)
+		}
+	}
+
+	return topics
+}
+
+// ForNPC returns the dialogue tree of the npc with the given monstats.txt id, or nil if the npc has none
+func ForNPC(npc string) *Tree {
+	for _, tree := range dialogueTrees {
+		if strings.EqualFold(tree.NPC, npc) {
+			return tree
+		}
+	}
+
+	return nil
+}
+
+// newTree creates the dialogue tree of an npc. The speech handles follow the sounds.txt naming,
+// e.g. Akara_act1_intro, Akara_act1_gossip_1 and Akara_act1_q1_init. The quest topics are
+// derived from the quest steps taken by talking to the npc.
+func newTree(npc, speaker string, act, gossipLines int) *Tree {
+	prefix := fmt.Sprintf("%s_act%d_", speaker, act)
+
+	tree := &Tree{
+		NPC:    npc,
+		Act:    act,
+		Intro:  &Topic{Type: TopicIntro, Speech: prefix + "intro", Quest: noQuest},
+		Gossip: make([]*Topic, gossipLines),
+		Quests: make([]*Topic, 0),
+	}
+
+	for idx := range tree.Gossip {
+		tree.Gossip[idx] = &Topic{Type: TopicGossip, Speech: fmt.Sprintf("%sgossip_%d", prefix, idx+1), Quest: noQuest}
+	}
+
+	for _, quest := range d2quest.Quests() {
+		for _, step := range quest.TalkSteps(npc) {
+			tree.Quests = append(tree.Quests, &Topic{
+				Type:     TopicQuest,
+				Speech:   fmt.Sprintf("%sq%d_%s", prefix, quest.Number, stepSpeech(step)),
+				Quest:    quest.ID,
+				statuses: step.From,
+			})
+		}
+	}
+
+	return tree
+}
+
+func stepSpeech(step d2quest.TalkStep) string {
+	for _, from := range step.From {
+		if from == d2enum.QuestStatusNotStarted {
+			return "init"
+		}
+	}
+
+	if step.To == d2enum.QuestStatusCompleting {
+		return "successful"
+	}
+
+	return fmt.Sprintf("inprogress%d", step.To)
+}
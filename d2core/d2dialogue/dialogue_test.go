@@ -0,0 +1,74 @@
+package d2dialogue
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2quest"
+)
+
+func TestTreeTopics(t *testing.T) {
+	const denOfEvil = 0
+
+	tree := ForNPC("Akara")
+	if tree == nil {
+		t.Fatal("expected akara to have a dialogue tree")
+	}
+
+	state := d2quest.NewState()
+	normal := d2enum.DifficultyNormal
+
+	hasTopic := func(speech string) bool {
+		for _, topic := range tree.Topics(state, normal, 0) {
+			if topic.Speech == speech {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if !hasTopic("Akara_act1_intro") || !hasTopic("Akara_act1_gossip_1") {
+		t.Error("expected the intro and the first gossip line")
+	}
+
+	if !hasTopic("Akara_act1_q1_init") || hasTopic("Akara_act1_q1_successful") {
+		t.Error("expected only the start of the den of evil quest")
+	}
+
+	state.SetStatus(normal, denOfEvil, 2)
+
+	if hasTopic("Akara_act1_q1_init") || !hasTopic("Akara_act1_q1_successful") {
+		t.Error("expected only the end of the den of evil quest")
+	}
+
+	if topics := tree.Topics(state, normal, 1); topics[1].Speech != "Akara_act1_gossip_2" {
+		t.Errorf("unexpected gossip line %s", topics[1].Speech)
+	}
+}
+
+func TestCainTrees(t *testing.T) {
+	// cain2 is Deckard Cain caged in Tristram, who doesn't talk
+	tests := []struct {
+		npc string
+		act int
+	}{
+		{"cain1", d2enum.Act1},
+		{"cain3", d2enum.Act2},
+		{"cain4", d2enum.Act3},
+		{"cain5", d2enum.Act4},
+		{"cain6", d2enum.Act5},
+	}
+
+	for _, test := range tests {
+		tree := ForNPC(test.npc)
+		if tree == nil || tree.Act != test.act || tree.Intro.Speech != fmt.Sprintf("Cain_act%d_intro", test.act) {
+			t.Errorf("%s: want the tree of cain in act %d, have %+v", test.npc, test.act, tree)
+		}
+	}
+
+	if tree := ForNPC("cain2"); tree != nil {
+		t.Errorf("want no tree for the caged cain, have %+v", tree)
+	}
+}
@@ -0,0 +1,57 @@
+package d2dialogue
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+// the number of gossip lines of most npcs
+const gossipLines = 3
+
+// dialogueTrees are the dialogue trees of the town npcs, by monstats.txt id
+// nolint:gochecknoglobals // lookup table
+var dialogueTrees = []*Tree{
+	// Act 1
+	newTree("akara", "Akara", d2enum.Act1, gossipLines),
+	newTree("kashya", "Kashya", d2enum.Act1, gossipLines),
+	newTree("charsi", "Charsi", d2enum.Act1, gossipLines),
+	newTree("gheed", "Gheed", d2enum.Act1, gossipLines),
+	newTree("warriv1", "Warriv", d2enum.Act1, gossipLines),
+	newTree("cain1", "Cain", d2enum.Act1, gossipLines),
+
+	// Act 2
+	newTree("atma", "Atma", d2enum.Act2, gossipLines),
+	newTree("drognan", "Drognan", d2enum.Act2, gossipLines),
+	newTree("elzix", "Elzix", d2enum.Act2, gossipLines),
+	newTree("fara", "Fara", d2enum.Act2, gossipLines),
+	newTree("geglash", "Geglash", d2enum.Act2, gossipLines),
+	newTree("greiz", "Greiz", d2enum.Act2, gossipLines),
+	newTree("jerhyn", "Jerhyn", d2enum.Act2, gossipLines),
+	newTree("lysander", "Lysander", d2enum.Act2, gossipLines),
+	newTree("meshif1", "Meshif", d2enum.Act2, gossipLines),
+	newTree("warriv2", "Warriv", d2enum.Act2, gossipLines),
+	newTree("cain3", "Cain", d2enum.Act2, gossipLines),
+	newTree("tyrael1", "Tyrael", d2enum.Act2, 0),
+
+	// Act 3
+	newTree("alkor", "Alkor", d2enum.Act3, gossipLines),
+	newTree("asheara", "Asheara", d2enum.Act3, gossipLines),
+	newTree("hratli", "Hratli", d2enum.Act3, gossipLines),
+	newTree("ormus", "Ormus", d2enum.Act3, gossipLines),
+	newTree("meshif2", "Meshif", d2enum.Act3, gossipLines),
+	newTree("natalya", "Natalya", d2enum.Act3, gossipLines),
+	newTree("cain4", "Cain", d2enum.Act3, gossipLines),
+
+	// Act 4
+	newTree("tyrael2", "Tyrael", d2enum.Act4, gossipLines),
+	newTree("halbu", "Halbu", d2enum.Act4, gossipLines),
+	newTree("jamella", "Jamella", d2enum.Act4, gossipLines),
+	newTree("cain5", "Cain", d2enum.Act4, gossipLines),
+
+	// Act 5
+	newTree("larzuk", "Larzuk", d2enum.Act5, gossipLines),
+	newTree("malah", "Malah", d2enum.Act5, gossipLines),
+	newTree("qual-kehk", "QualKehk", d2enum.Act5, gossipLines),
+	newTree("drehya", "Anya", d2enum.Act5, gossipLines),
+	newTree("nihlathak", "Nihlathak", d2enum.Act5, gossipLines),
+	newTree("cain6", "Cain", d2enum.Act5, gossipLines),
+}
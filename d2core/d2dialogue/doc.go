@@ -0,0 +1,2 @@
+// Package d2dialogue provides the dialogue trees of the town npcs, with the topics they can talk about.
+package d2dialogue
@@ -0,0 +1,100 @@
+package d2hero
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2inventory"
+)
+
+// Corpse is the body a hero leaves where they died, it holds the items the hero had equipped
+// until the hero takes them back. The position is in tiles. Potions holds the potions of the belt
+// rows the equipped belt added, BeltRows is the number of rows of the belt.
+type Corpse struct {
+	ID         string                           `json:"id"`
+	Act        int                              `json:"act"`
+	Difficulty d2enum.DifficultyType            `json:"difficulty"`
+	X          float64                          `json:"x"`
+	Y          float64                          `json:"y"`
+	Equipment  d2inventory.CharacterEquipment   `json:"equipment"`
+	Potions    []*d2inventory.InventoryItemMisc `json:"potions"`
+	BeltRows   int                              `json:"beltRows"`
+}
+
+// IsEmpty returns true if the corpse holds no items
+func (c *Corpse) IsEmpty() bool {
+	return c.Equipment.IsEmpty() && len(c.Potions) == 0
+}
+
+// Die leaves a corpse with the given id at the position of the hero, the equipped items of the
+// hero are moved to the corpse. Without the belt the potion belt shrinks to its default rows,
+// the potions which don't fit are moved to the corpse too. Hardcore heroes are dead for good.
+func (s *HeroState) Die(corpseID string) *Corpse {
+	corpse := &Corpse{
+		ID:         corpseID,
+		Act:        s.Act,
+		Difficulty: s.Difficulty,
+		X:          s.X,
+		Y:          s.Y,
+		Equipment:  s.Equipment,
+	}
+
+	if s.Belt != nil && corpse.Equipment.Belt != nil {
+		corpse.BeltRows = s.Belt.NumRows()
+		corpse.Potions = s.Belt.SetRows(d2inventory.DefaultBeltRows)
+	}
+
+	s.Equipment = d2inventory.CharacterEquipment{}
+	s.Corpses = append(s.Corpses, corpse)
+	s.IsDead = s.Hardcore
+
+	return corpse
+}
+
+// Corpse returns the corpse of the hero with the given id
+func (s *HeroState) Corpse(corpseID string) *Corpse {
+	for _, corpse := range s.Corpses {
+		if corpse.ID == corpseID {
+			return corpse
+		}
+	}
+
+	return nil
+}
+
+// RetrieveCorpse moves the items of the corpse back to the empty slots of the hero's equipment,
+// taking the belt back restores the rows of the potion belt, and the potions of the corpse are
+// put back into the belt where they fit. The corpse is removed once it holds no items. Returns
+// true if the corpse was removed.
+func (s *HeroState) RetrieveCorpse(corpse *Corpse) bool {
+	hadBelt := corpse.Equipment.Belt != nil
+
+	s.Equipment.TakeFrom(&corpse.Equipment)
+
+	if s.Belt != nil {
+		if hadBelt && corpse.Equipment.Belt == nil && corpse.BeltRows > s.Belt.NumRows() {
+			s.Belt.SetRows(corpse.BeltRows)
+		}
+
+		potions := make([]*d2inventory.InventoryItemMisc, 0)
+
+		for _, potion := range corpse.Potions {
+			if !s.Belt.Add(potion) {
+				potions = append(potions, potion)
+			}
+		}
+
+		corpse.Potions = potions
+	}
+
+	if !corpse.IsEmpty() {
+		return false
+	}
+
+	for idx := range s.Corpses {
+		if s.Corpses[idx] == corpse {
+			s.Corpses = append(s.Corpses[:idx], s.Corpses[idx+1:]...)
+			break
+		}
+	}
+
+	return true
+}
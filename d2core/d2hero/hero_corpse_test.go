@@ -0,0 +1,157 @@
+package d2hero
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2inventory"
+)
+
+func testEquipment() d2inventory.CharacterEquipment {
+	return d2inventory.CharacterEquipment{
+		Head:      &d2inventory.InventoryItemArmor{ItemCode: "cap"},
+		RightHand: &d2inventory.InventoryItemWeapon{ItemCode: "hax"},
+	}
+}
+
+func TestHeroStateDie(t *testing.T) {
+	tests := []struct {
+		name     string
+		hardcore bool
+		wantDead bool
+	}{
+		{"softcore", false, false},
+		{"hardcore", true, true},
+	}
+
+	for _, test := range tests {
+		state := &HeroState{
+			Act:        2,
+			Difficulty: d2enum.DifficultyNightmare,
+			X:          10,
+			Y:          20,
+			Equipment:  testEquipment(),
+			Hardcore:   test.hardcore,
+		}
+
+		corpse := state.Die("corpse")
+
+		if state.IsDead != test.wantDead {
+			t.Errorf("%s: want dead %v, have %v", test.name, test.wantDead, state.IsDead)
+		}
+
+		if corpse.ID != "corpse" || corpse.Act != 2 || corpse.Difficulty != d2enum.DifficultyNightmare ||
+			corpse.X != 10 || corpse.Y != 20 {
+			t.Errorf("%s: want the corpse where the hero died, have %+v", test.name, corpse)
+		}
+
+		if !state.Equipment.IsEmpty() || corpse.Equipment.Head == nil || corpse.Equipment.RightHand == nil {
+			t.Errorf("%s: want the equipment to be moved to the corpse", test.name)
+		}
+
+		if state.Corpse("corpse") != corpse || state.Corpse("unknown") != nil {
+			t.Errorf("%s: want the corpse to be found by its id", test.name)
+		}
+	}
+}
+
+func TestHeroStateRetrieveCorpse(t *testing.T) {
+	tests := []struct {
+		name         string
+		equipped     d2inventory.CharacterEquipment
+		wantRemoved  bool
+		wantOnCorpse bool
+	}{
+		{"empty slots", d2inventory.CharacterEquipment{}, true, false},
+		{"occupied slots", d2inventory.CharacterEquipment{
+			Head: &d2inventory.InventoryItemArmor{ItemCode: "skp"},
+		}, false, true},
+	}
+
+	for _, test := range tests {
+		state := &HeroState{Equipment: testEquipment()}
+		corpse := state.Die("corpse")
+		state.Equipment = test.equipped
+
+		if removed := state.RetrieveCorpse(corpse); removed != test.wantRemoved {
+			t.Errorf("%s: want removed %v, have %v", test.name, test.wantRemoved, removed)
+		}
+
+		if onCorpse := corpse.Equipment.Head != nil; onCorpse != test.wantOnCorpse {
+			t.Errorf("%s: want the helm on the corpse %v, have %v", test.name, test.wantOnCorpse, onCorpse)
+		}
+
+		if state.Equipment.RightHand == nil {
+			t.Errorf("%s: want the weapon back in the empty slot", test.name)
+		}
+
+		if found := state.Corpse("corpse") != nil; found == test.wantRemoved {
+			t.Errorf("%s: want the corpse kept %v, have %v", test.name, !test.wantRemoved, found)
+		}
+	}
+}
+
+func TestHeroStateDieBelt(t *testing.T) {
+	belt := d2inventory.NewBelt(3)
+	for idx := 0; idx < 3; idx++ {
+		belt.Add(&d2inventory.InventoryItemMisc{ItemCode: "hp1"})
+	}
+
+	belt.Add(&d2inventory.InventoryItemMisc{ItemCode: "mp1"})
+
+	equipment := testEquipment()
+	equipment.Belt = &d2inventory.InventoryItemArmor{ItemCode: "mbl"}
+	state := &HeroState{Equipment: equipment, Belt: belt}
+
+	corpse := state.Die("corpse")
+
+	if state.Belt.NumRows() != d2inventory.DefaultBeltRows || len(state.Belt.Items()) != 2 {
+		t.Fatalf("want the default belt with a potion per column, have %d rows and %d items",
+			state.Belt.NumRows(), len(state.Belt.Items()))
+	}
+
+	if len(corpse.Potions) != 2 || corpse.BeltRows != 3 {
+		t.Fatalf("want the overflowing potions on the corpse, have %d potions and %d rows",
+			len(corpse.Potions), corpse.BeltRows)
+	}
+
+	// a potion picked up after respawning takes a column, the potions of the corpse still fit
+	state.Belt.Add(&d2inventory.InventoryItemMisc{ItemCode: "rvs"})
+
+	if !state.RetrieveCorpse(corpse) {
+		t.Fatal("want the corpse removed after retrieving it")
+	}
+
+	if state.Belt.NumRows() != 3 || len(state.Belt.Items()) != 5 || len(corpse.Potions) != 0 {
+		t.Errorf("want the belt rows and the potions back, have %d rows and %d items",
+			state.Belt.NumRows(), len(state.Belt.Items()))
+	}
+}
+
+func TestHeroStateRetrieveCorpseOtherBelt(t *testing.T) {
+	belt := d2inventory.NewBelt(2)
+	belt.Add(&d2inventory.InventoryItemMisc{ItemCode: "hp1"})
+	belt.Add(&d2inventory.InventoryItemMisc{ItemCode: "hp1"})
+
+	state := &HeroState{
+		Equipment: d2inventory.CharacterEquipment{Belt: &d2inventory.InventoryItemArmor{ItemCode: "lbl"}},
+		Belt:      belt,
+	}
+
+	corpse := state.Die("corpse")
+
+	// the hero equipped another belt and filled the belt, the belt and the potion stay on the corpse
+	state.Equipment.Belt = &d2inventory.InventoryItemArmor{ItemCode: "vbl"}
+	for column := 0; column < d2inventory.BeltColumns; column++ {
+		state.Belt.Add(&d2inventory.InventoryItemMisc{ItemCode: "mp1"})
+	}
+
+	if state.RetrieveCorpse(corpse) {
+		t.Fatal("want the corpse kept while it holds items")
+	}
+
+	if corpse.Equipment.Belt == nil || len(corpse.Potions) != 1 || state.Belt.NumRows() != 1 {
+		t.Errorf("want the belt and the potion left on the corpse, have %+v and %d potions",
+			corpse.Equipment.Belt, len(corpse.Potions))
+	}
+}
@@ -0,0 +1,70 @@
+package d2hero
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2quest"
+)
+
+// MaxResistance is the highest resistance a hero can have
+const MaxResistance = 75
+
+// Resistance returns the base of all resistances of the hero on the given difficulty, the bonus
+// from quests lowered by the resistance penalty of the difficulty. The penalties of
+// difficultylevels.txt are negative.
+func (f *HeroStateFactory) Resistance(quests *d2quest.State, difficulty d2enum.DifficultyType) int {
+	resistance := 0
+
+	if quests != nil {
+		resistance += quests.Resistance()
+	}
+
+	if record := f.asset.Records.DifficultyLevels[difficulty]; record != nil {
+		resistance += record.ResistancePenalty
+	}
+
+	return resistance
+}
+
+// DeathExperienceLoss returns the experience the hero loses when dying on the given difficulty,
+// a percentage of the experience of the hero's current level. Heroes never lose a level.
+func (f *HeroStateFactory) DeathExperienceLoss(heroType d2enum.Hero, stats *HeroStatsState,
+	difficulty d2enum.DifficultyType) int {
+	record := f.asset.Records.DifficultyLevels[difficulty]
+	if record == nil || stats.Level < 1 {
+		return 0
+	}
+
+	levelStart := 0
+	if stats.Level > 1 {
+		levelStart = f.asset.Records.GetExperienceBreakpoint(heroType, stats.Level-1)
+	}
+
+	levelEnd := f.asset.Records.GetExperienceBreakpoint(heroType, stats.Level)
+	loss := (levelEnd - levelStart) * record.DeathExperiencePenalty / 100 // nolint:gomnd // percentage
+
+	if gained := stats.Experience - levelStart; loss > gained {
+		loss = gained
+	}
+
+	if loss < 0 {
+		return 0
+	}
+
+	return loss
+}
+
+// the percentage of the carried gold a hero loses when dying, for each level of the hero
+const deathGoldPenaltyPerLevel = 1
+
+// DeathGoldLoss returns the gold the hero loses when dying, a percentage of the carried gold
+// which grows with the level of the hero
+func DeathGoldLoss(level, gold int) int {
+	const maxPercent = 100
+
+	percent := level * deathGoldPenaltyPerLevel
+	if percent > maxPercent {
+		percent = maxPercent
+	}
+
+	return gold * percent / maxPercent
+}
@@ -0,0 +1,75 @@
+package d2hero
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2records"
+)
+
+func testDifficultyFactory() *HeroStateFactory {
+	asset := &d2asset.AssetManager{}
+	asset.Records = &d2records.RecordManager{}
+	asset.Records.DifficultyLevels = d2records.DifficultyLevels{
+		d2enum.DifficultyNormal:    {DeathExperiencePenalty: 0},
+		d2enum.DifficultyNightmare: {DeathExperiencePenalty: 5},
+		d2enum.DifficultyHell:      {DeathExperiencePenalty: 10},
+	}
+	asset.Records.Character.Experience = d2records.ExperienceBreakpoints{
+		1: {Level: 1, HeroBreakpoints: map[d2enum.Hero]int{d2enum.HeroBarbarian: 500}},
+		2: {Level: 2, HeroBreakpoints: map[d2enum.Hero]int{d2enum.HeroBarbarian: 1500}},
+		3: {Level: 3, HeroBreakpoints: map[d2enum.Hero]int{d2enum.HeroBarbarian: 3750}},
+	}
+
+	return &HeroStateFactory{asset: asset}
+}
+
+func TestDeathExperienceLoss(t *testing.T) {
+	factory := testDifficultyFactory()
+
+	tests := []struct {
+		name       string
+		level      int
+		experience int
+		difficulty d2enum.DifficultyType
+		want       int
+	}{
+		{"no penalty on normal", 2, 1400, d2enum.DifficultyNormal, 0},
+		{"percentage of the level on nightmare", 2, 1400, d2enum.DifficultyNightmare, 50},
+		{"percentage of the level on hell", 3, 3000, d2enum.DifficultyHell, 225},
+		{"first level starts at zero", 1, 400, d2enum.DifficultyHell, 50},
+		{"never lose a level", 3, 1600, d2enum.DifficultyHell, 100},
+		{"nothing gained in the level", 2, 500, d2enum.DifficultyHell, 0},
+		{"unknown difficulty", 2, 1400, d2enum.DifficultyType(-1), 0},
+		{"no level", 0, 0, d2enum.DifficultyHell, 0},
+	}
+
+	for _, test := range tests {
+		stats := &HeroStatsState{Level: test.level, Experience: test.experience}
+
+		if loss := factory.DeathExperienceLoss(d2enum.HeroBarbarian, stats, test.difficulty); loss != test.want {
+			t.Errorf("%s: want %d, have %d", test.name, test.want, loss)
+		}
+	}
+}
+
+func TestDeathGoldLoss(t *testing.T) {
+	tests := []struct {
+		name  string
+		level int
+		gold  int
+		want  int
+	}{
+		{"one percent per level", 1, 1000, 10},
+		{"grows with the level", 30, 1000, 300},
+		{"capped at all of the gold", 120, 1000, 1000},
+		{"no gold", 50, 0, 0},
+	}
+
+	for _, test := range tests {
+		if loss := DeathGoldLoss(test.level, test.gold); loss != test.want {
+			t.Errorf("%s: want %d, have %d", test.name, test.want, loss)
+		}
+	}
+}
@@ -0,0 +1,569 @@
+package d2hero
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2inventory"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2records"
+)
+
+const (
+	// number of hirelings of each type offered by a seller
+	hirelingsPerType = 2
+
+	// the Str/Lvl and Dex/Lvl columns of hireling.txt are in eighths
+	hirelingAttributeDivisor = 8
+
+	// the hire cost goes up by this percentage of the base cost for each level above the record's level
+	hireCostPercentPerLevel = 15
+	percentDivisor          = 100
+
+	// revive cost, see ReviveHirelingCost
+	reviveCostBase          = 1500
+	reviveCostPerLevelSqNum = 15
+	reviveCostPerLevelSqDen = 2
+	reviveCostMax           = 50000
+
+	percentChance = 100
+
+	// prefix of the ids of hirelings, see HirelingID
+	hirelingIDPrefix = "hireling-"
+)
+
+var (
+	errHirelingSlotNotAllowed = errors.New("hireling can't use this equipment slot")
+	errHirelingItemNotAllowed = errors.New("hireling can't use this item")
+	errHirelingNothingToSwap  = errors.New("neither the hero nor the hireling has an item in this slot")
+)
+
+// HirelingSlot is an equipment slot of a hireling
+type HirelingSlot int
+
+// Hireling equipment slots, hirelings can only use some of them, see hireling.txt
+const (
+	HirelingSlotHead HirelingSlot = iota
+	HirelingSlotTorso
+	HirelingSlotWeapon
+	HirelingSlotShield
+)
+
+// HirelingEquipment stores the equipped items of a hireling
+type HirelingEquipment struct {
+	Head   *d2inventory.InventoryItemArmor  `json:"head"`
+	Torso  *d2inventory.InventoryItemArmor  `json:"torso"`
+	Weapon *d2inventory.InventoryItemWeapon `json:"weapon"`
+	Shield *d2inventory.InventoryItemArmor  `json:"shield"`
+}
+
+// HirelingState is the serializable state of a hired (or hireable) mercenary
+type HirelingState struct {
+	ID         int               `json:"id"`         // the Id column of hireling.txt
+	Difficulty int               `json:"difficulty"` // the Difficulty column of hireling.txt, starts at 1
+	Name       string            `json:"name"`       // string table key of the hireling's name
+	Level      int               `json:"level"`
+	Experience int               `json:"experience"`
+	Health     int               `json:"health"`
+	IsDead     bool              `json:"isDead"`
+	Equipment  HirelingEquipment `json:"equipment"`
+}
+
+// HirelingStats are the stats of a hireling at its current level
+type HirelingStats struct {
+	MaxHealth    int
+	Defense      int
+	Strength     int
+	Dexterity    int
+	AttackRating int
+	DamageMin    int
+	DamageMax    int
+	Resistance   int
+}
+
+// HirelingSkill is a skill used by a hireling, with the chance (in percent) of using it
+type HirelingSkill struct {
+	Name   string
+	Level  int
+	Chance int
+	Mode   d2enum.MonsterAnimationMode
+}
+
+// HirelingID returns the id of the hireling of the player with the given id, the server and
+// the clients use it for the hireling in hit packets
+func HirelingID(playerID string) string {
+	return hirelingIDPrefix + playerID
+}
+
+// Kill marks the hireling as dead, it must be revived before it can fight again
+func (h *HirelingState) Kill() {
+	h.Health = 0
+	h.IsDead = true
+}
+
+// HirelingRecord returns the hireling.txt record for the level bracket of the hireling,
+// which is the record with the highest level that is not above the hireling's level
+func (f *HeroStateFactory) HirelingRecord(state *HirelingState) *d2records.HirelingRecord {
+	var best, lowest *d2records.HirelingRecord
+
+	for _, record := range f.asset.Records.Hireling.Details {
+		if record.ID != state.ID || record.Difficulty != state.Difficulty {
+			continue
+		}
+
+		if lowest == nil || record.Level < lowest.Level {
+			lowest = record
+		}
+
+		if record.Level <= state.Level && (best == nil || record.Level > best.Level) {
+			best = record
+		}
+	}
+
+	if best == nil {
+		return lowest
+	}
+
+	return best
+}
+
+// IsHirelingSeller returns whether the NPC with the given monstats id sells hirelings
+// in the given act and difficulty
+func (f *HeroStateFactory) IsHirelingSeller(npcID, act int, difficulty d2enum.DifficultyType) bool {
+	for _, record := range f.asset.Records.Hireling.Details {
+		if record.Seller == npcID && record.Act == act && record.Difficulty == int(difficulty)+1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CreateHireableHirelings generates the list of hirelings offered for hire in the given act
+// and difficulty. The hirelings are about the level of the hero.
+func (f *HeroStateFactory) CreateHireableHirelings(act int, difficulty d2enum.DifficultyType,
+	heroLevel int, r *rand.Rand) []*HirelingState {
+	lowestLevels := make(map[int]*d2records.HirelingRecord)
+
+	for _, record := range f.asset.Records.Hireling.Details {
+		if record.Act != act || record.Difficulty != int(difficulty)+1 {
+			continue
+		}
+
+		if lowest, found := lowestLevels[record.ID]; !found || record.Level < lowest.Level {
+			lowestLevels[record.ID] = record
+		}
+	}
+
+	ids := make([]int, 0, len(lowestLevels))
+	for id := range lowestLevels {
+		ids = append(ids, id)
+	}
+
+	sort.Ints(ids)
+
+	result := make([]*HirelingState, 0)
+
+	for _, id := range ids {
+		record := lowestLevels[id]
+		names := hirelingNameKeys(record.NameFirst, record.NameLast)
+
+		level := heroLevel
+		if level < record.Level {
+			level = record.Level
+		}
+
+		for idx := 0; idx < hirelingsPerType; idx++ {
+			state := &HirelingState{
+				ID:         record.ID,
+				Difficulty: record.Difficulty,
+				Level:      level,
+			}
+
+			if len(names) > 0 {
+				state.Name = names[r.Intn(len(names))]
+			}
+
+			state.Experience = f.HirelingExperienceForLevel(state, level)
+			state.Health = f.HirelingStats(state).MaxHealth
+			result = append(result, state)
+		}
+	}
+
+	return result
+}
+
+// IsHireableHireling returns whether the hireling could have been offered for hire in the
+// given act and difficulty, to a hero of the given level
+func (f *HeroStateFactory) IsHireableHireling(state *HirelingState, act int, difficulty d2enum.DifficultyType,
+	heroLevel int) bool {
+	record := f.HirelingRecord(state)
+	if record == nil || record.Act != act || state.Difficulty != int(difficulty)+1 {
+		return false
+	}
+
+	return state.Level >= record.Level && (state.Level <= heroLevel || state.Level == record.Level)
+}
+
+// HirelingStats returns the stats of the hireling at its current level
+func (f *HeroStateFactory) HirelingStats(state *HirelingState) HirelingStats {
+	record := f.HirelingRecord(state)
+	if record == nil {
+		return HirelingStats{}
+	}
+
+	levels := state.Level - record.Level
+	if levels < 0 {
+		levels = 0
+	}
+
+	return HirelingStats{
+		MaxHealth:    record.HP + record.HPPerLvl*levels,
+		Defense:      record.Defense + record.DefPerLvl*levels,
+		Strength:     record.Str + record.StrPerLvl*levels/hirelingAttributeDivisor,
+		Dexterity:    record.Dex + record.DexPerLvl*levels/hirelingAttributeDivisor,
+		AttackRating: record.AR + record.ARPerLvl*levels,
+		DamageMin:    record.DmgMin + record.DmgPerLvl*levels,
+		DamageMax:    record.DmgMax + record.DmgPerLvl*levels,
+		Resistance:   record.Resist + record.ResistPerLvl*levels,
+	}
+}
+
+// HirelingSkills returns the skills the hireling uses at its current level
+func (f *HeroStateFactory) HirelingSkills(state *HirelingState) []HirelingSkill {
+	record := f.HirelingRecord(state)
+	if record == nil {
+		return nil
+	}
+
+	levels := state.Level - record.Level
+
+	skills := []struct {
+		name                                      string
+		mode, chance, chancePerLvl, lvl, lvlPerLv int
+	}{
+		{record.Skill1, record.Mode1, record.Chance1, record.ChancePerLevel1, record.Level1, record.LvlPerLvl1},
+		{record.Skill2, record.Mode2, record.Chance2, record.ChancePerLevel2, record.Level2, record.LvlPerLvl2},
+		{record.Skill3, record.Mode3, record.Chance3, record.ChancePerLevel3, record.Level3, record.LvlPerLvl3},
+		{record.Skill4, record.Mode4, record.Chance4, record.ChancePerLevel4, record.Level4, record.LvlPerLvl4},
+		{record.Skill5, record.Mode5, record.Chance5, record.ChancePerLevel5, record.Level5, record.LvlPerLvl5},
+		{record.Skill6, record.Mode6, record.Chance6, record.ChancePerLevel6, record.Level6, record.LvlPerLvl6},
+	}
+
+	result := make([]HirelingSkill, 0)
+
+	for _, skill := range skills {
+		if skill.name == "" {
+			continue
+		}
+
+		// the skill level per hireling level is in 32nds, like in monstats
+		const skillLevelDivisor = 32
+
+		result = append(result, HirelingSkill{
+			Name:   skill.name,
+			Level:  skill.lvl + skill.lvlPerLv*levels/skillLevelDivisor,
+			Chance: skill.chance + skill.chancePerLvl*levels,
+			Mode:   d2enum.MonsterAnimationMode(skill.mode),
+		})
+	}
+
+	return result
+}
+
+// ChooseHirelingSkill picks one of the hireling's skills by their chance, the first
+// skill is the default attack
+func (f *HeroStateFactory) ChooseHirelingSkill(state *HirelingState, r *rand.Rand) HirelingSkill {
+	skills := f.HirelingSkills(state)
+	if len(skills) == 0 {
+		return HirelingSkill{Mode: d2enum.MonsterAnimationModeAttack1}
+	}
+
+	roll := r.Intn(percentChance)
+
+	for idx := len(skills) - 1; idx > 0; idx-- {
+		if roll < skills[idx].Chance {
+			return skills[idx]
+		}
+	}
+
+	return skills[0]
+}
+
+// HirelingHireCost returns the gold cost of hiring the hireling
+func (f *HeroStateFactory) HirelingHireCost(state *HirelingState) int {
+	record := f.HirelingRecord(state)
+	if record == nil {
+		return 0
+	}
+
+	levels := state.Level - record.Level
+	if levels < 0 {
+		levels = 0
+	}
+
+	return record.Gold + record.Gold*levels*hireCostPercentPerLevel/percentDivisor
+}
+
+// ReviveHirelingCost returns the gold cost of reviving the hireling, this grows with the
+// square of the hireling's level, up to a maximum
+func (f *HeroStateFactory) ReviveHirelingCost(state *HirelingState) int {
+	cost := reviveCostBase + state.Level*state.Level*reviveCostPerLevelSqNum/reviveCostPerLevelSqDen
+
+	if cost > reviveCostMax {
+		cost = reviveCostMax
+	}
+
+	return cost
+}
+
+// ReviveHireling brings the hireling back to life with full health
+func (f *HeroStateFactory) ReviveHireling(state *HirelingState) {
+	state.IsDead = false
+	state.Health = f.HirelingStats(state).MaxHealth
+}
+
+// HirelingExperienceForLevel returns the experience a hireling needs to reach the given level
+func (f *HeroStateFactory) HirelingExperienceForLevel(state *HirelingState, level int) int {
+	record := f.HirelingRecord(state)
+	if record == nil {
+		return 0
+	}
+
+	return record.ExpPerLvl * level * level * (level + 1)
+}
+
+// GiveHirelingExperience adds experience to the hireling, which levels up alongside the hero.
+// A hireling can't have a higher level than the hero. Returns the number of levels gained.
+func (f *HeroStateFactory) GiveHirelingExperience(state *HirelingState, experience, heroLevel int) int {
+	if state.IsDead {
+		return 0
+	}
+
+	state.Experience += experience
+	levelsGained := 0
+
+	for state.Level < heroLevel && state.Experience >= f.HirelingExperienceForLevel(state, state.Level+1) {
+		state.Level++
+		levelsGained++
+	}
+
+	if levelsGained > 0 {
+		state.Health = f.HirelingStats(state).MaxHealth
+	}
+
+	return levelsGained
+}
+
+// CanHirelingUseSlot returns whether the hireling can equip items in the given slot
+func (f *HeroStateFactory) CanHirelingUseSlot(state *HirelingState, slot HirelingSlot) bool {
+	record := f.HirelingRecord(state)
+	if record == nil {
+		return false
+	}
+
+	switch slot {
+	case HirelingSlotHead:
+		return record.Head > 0
+	case HirelingSlotTorso:
+		return record.Torso > 0
+	case HirelingSlotWeapon:
+		return record.Weapon > 0
+	case HirelingSlotShield:
+		return record.Shield > 0
+	}
+
+	return false
+}
+
+// CanHirelingEquip returns whether the hireling can equip the item with the given code in the given slot
+func (f *HeroStateFactory) CanHirelingEquip(state *HirelingState, slot HirelingSlot, code string) bool {
+	if !f.CanHirelingUseSlot(state, slot) {
+		return false
+	}
+
+	switch slot {
+	case HirelingSlotWeapon:
+		return f.isAllowedHirelingWeapon(state, code)
+	case HirelingSlotShield:
+		record := f.asset.Records.Item.Armors[code]
+		return record != nil && f.hasItemType(record, "shie")
+	default:
+		return f.asset.Records.Item.Armors[code] != nil
+	}
+}
+
+// EquipHirelingArmor equips the given armor to the head, torso or shield slot of the hireling,
+// returns the armor which was previously in the slot
+func (f *HeroStateFactory) EquipHirelingArmor(state *HirelingState, slot HirelingSlot,
+	armor *d2inventory.InventoryItemArmor) (*d2inventory.InventoryItemArmor, error) {
+	if !f.CanHirelingUseSlot(state, slot) || slot == HirelingSlotWeapon {
+		return nil, errHirelingSlotNotAllowed
+	}
+
+	if !f.CanHirelingEquip(state, slot, armor.GetItemCode()) {
+		return nil, fmt.Errorf("%w: %s", errHirelingItemNotAllowed, armor.GetItemCode())
+	}
+
+	equipped := hirelingArmorSlot(state, slot)
+	previous := *equipped
+	*equipped = armor
+
+	return previous, nil
+}
+
+// EquipHirelingWeapon equips the given weapon to the hireling, returns the previous weapon
+func (f *HeroStateFactory) EquipHirelingWeapon(state *HirelingState,
+	weapon *d2inventory.InventoryItemWeapon) (*d2inventory.InventoryItemWeapon, error) {
+	if !f.CanHirelingUseSlot(state, HirelingSlotWeapon) {
+		return nil, errHirelingSlotNotAllowed
+	}
+
+	if !f.CanHirelingEquip(state, HirelingSlotWeapon, weapon.GetItemCode()) {
+		return nil, fmt.Errorf("%w: %s", errHirelingItemNotAllowed, weapon.GetItemCode())
+	}
+
+	previous := state.Equipment.Weapon
+	state.Equipment.Weapon = weapon
+
+	return previous, nil
+}
+
+// SwapHirelingEquipment gives the hero's item in the slot matching the hireling slot to the
+// hireling, and the hireling's previous item in the slot to the hero. The hireling gives its
+// item back when the hero's slot is empty.
+func (f *HeroStateFactory) SwapHirelingEquipment(state *HirelingState, equipment *d2inventory.CharacterEquipment,
+	slot HirelingSlot) error {
+	if slot == HirelingSlotWeapon {
+		weapon := equipment.RightHand
+		if weapon == nil || weapon.ItemCode == "" {
+			if state.Equipment.Weapon == nil {
+				return errHirelingNothingToSwap
+			}
+
+			equipment.RightHand, state.Equipment.Weapon = state.Equipment.Weapon, nil
+
+			return nil
+		}
+
+		previous, err := f.EquipHirelingWeapon(state, weapon)
+		if err != nil {
+			return err
+		}
+
+		equipment.RightHand = previous
+
+		return nil
+	}
+
+	heroSlots := map[HirelingSlot]**d2inventory.InventoryItemArmor{
+		HirelingSlotHead:   &equipment.Head,
+		HirelingSlotTorso:  &equipment.Torso,
+		HirelingSlotShield: &equipment.Shield,
+	}
+
+	heroSlot, found := heroSlots[slot]
+	if !found {
+		return errHirelingSlotNotAllowed
+	}
+
+	armor := *heroSlot
+	if armor == nil || armor.ItemCode == "" {
+		equipped := hirelingArmorSlot(state, slot)
+		if *equipped == nil {
+			return errHirelingNothingToSwap
+		}
+
+		*heroSlot, *equipped = *equipped, nil
+
+		return nil
+	}
+
+	previous, err := f.EquipHirelingArmor(state, slot, armor)
+	if err != nil {
+		return err
+	}
+
+	*heroSlot = previous
+
+	return nil
+}
+
+// hirelingArmorSlot returns the head, torso or shield slot of the hireling's equipment
+func hirelingArmorSlot(state *HirelingState, slot HirelingSlot) **d2inventory.InventoryItemArmor {
+	switch slot {
+	case HirelingSlotHead:
+		return &state.Equipment.Head
+	case HirelingSlotTorso:
+		return &state.Equipment.Torso
+	default:
+		return &state.Equipment.Shield
+	}
+}
+
+func (f *HeroStateFactory) isAllowedHirelingWeapon(state *HirelingState, code string) bool {
+	record := f.HirelingRecord(state)
+	weapon := f.asset.Records.Item.Weapons[code]
+
+	if record == nil || weapon == nil {
+		return false
+	}
+
+	for _, allowed := range []string{record.WType1, record.WType2} {
+		if allowed != "" && f.hasItemType(weapon, allowed) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f *HeroStateFactory) hasItemType(record *d2records.ItemCommonRecord, itemType string) bool {
+	for _, equivalent := range f.asset.Records.FindEquivalentTypesByItemCommonRecord(record) {
+		if equivalent == itemType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hirelingNameKeys returns all of the string keys from first to last, the NameFirst
+// and NameLast columns of hireling.txt are a range of keys like merc01 to merc41
+func hirelingNameKeys(first, last string) []string {
+	prefix, firstNum, width := splitNumericSuffix(first)
+	lastPrefix, lastNum, _ := splitNumericSuffix(last)
+
+	if width == 0 || prefix != lastPrefix || lastNum < firstNum {
+		if first == "" {
+			return nil
+		}
+
+		return []string{first}
+	}
+
+	result := make([]string, 0, lastNum-firstNum+1)
+
+	for num := firstNum; num <= lastNum; num++ {
+		result = append(result, fmt.Sprintf("%s%0*d", prefix, width, num))
+	}
+
+	return result
+}
+
+func splitNumericSuffix(str string) (prefix string, num, width int) {
+	idx := len(str)
+	for idx > 0 && str[idx-1] >= '0' && str[idx-1] <= '9' {
+		idx--
+	}
+
+	num, err := strconv.Atoi(str[idx:])
+	if err != nil {
+		return str, 0, 0
+	}
+
+	return str[:idx], num, len(str) - idx
+}
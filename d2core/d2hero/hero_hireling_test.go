@@ -0,0 +1,206 @@
+package d2hero
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2inventory"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2records"
+)
+
+func testHirelingFactory() *HeroStateFactory {
+	asset := &d2asset.AssetManager{}
+	asset.Records = &d2records.RecordManager{}
+	asset.Records.Hireling.Details = d2records.Hirelings{
+		{ID: 1, Difficulty: 1, Act: 1, Level: 3, Gold: 500, ExpPerLvl: 10, HP: 50, HPPerLvl: 5, Head: 1, Torso: 1,
+			Skill1: "Attack", Mode1: int(d2enum.MonsterAnimationModeAttack1), Chance1: 100,
+			Skill2: "Inner Sight", Mode2: int(d2enum.MonsterAnimationModeCast), Chance2: 0, ChancePerLevel2: 10},
+		{ID: 1, Difficulty: 1, Act: 1, Level: 10, Gold: 1000, ExpPerLvl: 10, HP: 100, HPPerLvl: 10, Head: 1, Torso: 1,
+			Skill1: "Attack", Mode1: int(d2enum.MonsterAnimationModeAttack1), Chance1: 100,
+			Skill2: "Inner Sight", Mode2: int(d2enum.MonsterAnimationModeCast), Chance2: 0, ChancePerLevel2: 10},
+	}
+	asset.Records.Item.Armors = d2records.CommonItems{
+		"cap": {Code: "cap"},
+		"skp": {Code: "skp"},
+	}
+
+	return &HeroStateFactory{asset: asset}
+}
+
+func TestHirelingRecord(t *testing.T) {
+	factory := testHirelingFactory()
+
+	tests := []struct {
+		level int
+		want  int
+	}{
+		{1, 3},
+		{3, 3},
+		{9, 3},
+		{10, 10},
+		{30, 10},
+	}
+
+	for _, test := range tests {
+		record := factory.HirelingRecord(&HirelingState{ID: 1, Difficulty: 1, Level: test.level})
+		if record == nil || record.Level != test.want {
+			t.Errorf("level %d: want the record of level %d, have %+v", test.level, test.want, record)
+		}
+	}
+
+	if record := factory.HirelingRecord(&HirelingState{ID: 2, Difficulty: 1, Level: 10}); record != nil {
+		t.Errorf("want no record for an unknown hireling, have %+v", record)
+	}
+}
+
+func TestHirelingCosts(t *testing.T) {
+	factory := testHirelingFactory()
+
+	tests := []struct {
+		level      int
+		wantHire   int
+		wantRevive int
+	}{
+		{3, 500, 1567},
+		{10, 1000, 2250},
+		{12, 1300, 2580},
+		{100, 14500, reviveCostMax},
+	}
+
+	for _, test := range tests {
+		state := &HirelingState{ID: 1, Difficulty: 1, Level: test.level}
+
+		if cost := factory.HirelingHireCost(state); cost != test.wantHire {
+			t.Errorf("level %d: want hire cost %d, have %d", test.level, test.wantHire, cost)
+		}
+
+		if cost := factory.ReviveHirelingCost(state); cost != test.wantRevive {
+			t.Errorf("level %d: want revive cost %d, have %d", test.level, test.wantRevive, cost)
+		}
+	}
+}
+
+func TestGiveHirelingExperience(t *testing.T) {
+	factory := testHirelingFactory()
+
+	tests := []struct {
+		name       string
+		experience int
+		heroLevel  int
+		dead       bool
+		wantLevels int
+	}{
+		{"not enough for a level", 1000, 20, false, 0},
+		{"two levels", 8000, 20, false, 2},
+		{"not above the hero", 8000, 11, false, 1},
+		{"dead", 8000, 20, true, 0},
+	}
+
+	for _, test := range tests {
+		state := &HirelingState{ID: 1, Difficulty: 1, Level: 10, Health: 1, IsDead: test.dead}
+		state.Experience = factory.HirelingExperienceForLevel(state, state.Level)
+
+		if levels := factory.GiveHirelingExperience(state, test.experience, test.heroLevel); levels != test.wantLevels {
+			t.Errorf("%s: want %d levels, have %d", test.name, test.wantLevels, levels)
+		}
+
+		if state.Level != 10+test.wantLevels {
+			t.Errorf("%s: want level %d, have %d", test.name, 10+test.wantLevels, state.Level)
+		}
+
+		if test.wantLevels > 0 && state.Health != factory.HirelingStats(state).MaxHealth {
+			t.Errorf("%s: want full health after a level up, have %d", test.name, state.Health)
+		}
+	}
+}
+
+func TestHirelingKillRevive(t *testing.T) {
+	factory := testHirelingFactory()
+	state := &HirelingState{ID: 1, Difficulty: 1, Level: 12, Health: 20}
+
+	state.Kill()
+
+	if !state.IsDead || state.Health != 0 {
+		t.Fatalf("want a dead hireling without health, have %+v", state)
+	}
+
+	if levels := factory.GiveHirelingExperience(state, 100000, 99); levels != 0 {
+		t.Errorf("want no experience for a dead hireling, have %d levels", levels)
+	}
+
+	factory.ReviveHireling(state)
+
+	if state.IsDead || state.Health != 120 {
+		t.Errorf("want a living hireling with 120 health, have %+v", state)
+	}
+}
+
+func TestChooseHirelingSkill(t *testing.T) {
+	factory := testHirelingFactory()
+	r := rand.New(rand.NewSource(1)) // nolint:gosec // not concerned with crypto-strong randomness
+
+	// the chance of the second skill is 0 at the level of the record, and 100 ten levels later
+	tests := []struct {
+		level int
+		want  d2enum.MonsterAnimationMode
+	}{
+		{10, d2enum.MonsterAnimationModeAttack1},
+		{20, d2enum.MonsterAnimationModeCast},
+	}
+
+	for _, test := range tests {
+		state := &HirelingState{ID: 1, Difficulty: 1, Level: test.level}
+
+		for idx := 0; idx < 10; idx++ {
+			if skill := factory.ChooseHirelingSkill(state, r); skill.Mode != test.want {
+				t.Fatalf("level %d: want mode %s, have %s", test.level, test.want, skill.Mode)
+			}
+		}
+	}
+}
+
+func TestSwapHirelingEquipment(t *testing.T) {
+	factory := testHirelingFactory()
+	state := &HirelingState{ID: 1, Difficulty: 1, Level: 10}
+	equipment := &d2inventory.CharacterEquipment{Head: &d2inventory.InventoryItemArmor{ItemCode: "cap"}}
+
+	if err := factory.SwapHirelingEquipment(state, equipment, HirelingSlotHead); err != nil {
+		t.Fatal(err)
+	}
+
+	if state.Equipment.Head.GetItemCode() != "cap" || equipment.Head != nil {
+		t.Fatalf("want the cap on the hireling, have %+v and %+v", state.Equipment.Head, equipment.Head)
+	}
+
+	equipment.Head = &d2inventory.InventoryItemArmor{ItemCode: "skp"}
+
+	if err := factory.SwapHirelingEquipment(state, equipment, HirelingSlotHead); err != nil {
+		t.Fatal(err)
+	}
+
+	if state.Equipment.Head.GetItemCode() != "skp" || equipment.Head.GetItemCode() != "cap" {
+		t.Fatalf("want the items swapped, have %+v and %+v", state.Equipment.Head, equipment.Head)
+	}
+
+	equipment.Head = nil
+
+	if err := factory.SwapHirelingEquipment(state, equipment, HirelingSlotHead); err != nil {
+		t.Fatal(err)
+	}
+
+	if state.Equipment.Head != nil || equipment.Head.GetItemCode() != "skp" {
+		t.Fatalf("want the item back from the hireling, have %+v and %+v", state.Equipment.Head, equipment.Head)
+	}
+
+	if err := factory.SwapHirelingEquipment(state, &d2inventory.CharacterEquipment{}, HirelingSlotTorso); err == nil {
+		t.Error("want an error when there is nothing to swap")
+	}
+
+	equipment.Shield = &d2inventory.InventoryItemArmor{ItemCode: "cap"}
+
+	if err := factory.SwapHirelingEquipment(state, equipment, HirelingSlotShield); err == nil {
+		t.Error("want an error for a slot the hireling can't use")
+	}
+}
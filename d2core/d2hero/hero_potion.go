@@ -0,0 +1,128 @@
+package d2hero
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2calculation"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2calculation/d2parser"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2records"
+)
+
+// item type codes of the potions, see itemtypes.txt
+const (
+	potionTypeHealing       = "hpot"
+	potionTypeMana          = "mpot"
+	potionTypeRejuvenation  = "rpot"
+	rejuvenationPercentBase = 100.0
+)
+
+const (
+	// the "len" column of misc.txt is in frames
+	potionFramesPerSecond = 25.0
+	// used for healing and mana potions which don't have a duration
+	defaultPotionSeconds = 7.0
+)
+
+// PotionEffect restores the life and mana of a hero, either instantly or over
+// the duration of the effect
+type PotionEffect struct {
+	Life     int
+	Mana     int
+	Duration float64 // in seconds, zero for instant effects
+
+	elapsed      float64
+	lifeRestored int
+	manaRestored int
+}
+
+// IsPotion returns whether the misc item with the given code is a healing, mana or
+// rejuvenation potion
+func (f *HeroStateFactory) IsPotion(code string) bool {
+	record := f.asset.Records.Item.Misc[code]
+	if record == nil {
+		return false
+	}
+
+	switch record.Type {
+	case potionTypeHealing, potionTypeMana, potionTypeRejuvenation:
+		return true
+	}
+
+	return false
+}
+
+// NewPotionEffect creates the effect of drinking the potion with the given code,
+// the stats are needed for potions which restore a percentage of life and mana
+func (f *HeroStateFactory) NewPotionEffect(code string, stats *HeroStatsState) (*PotionEffect, error) {
+	record := f.asset.Records.Item.Misc[code]
+	if record == nil || !f.IsPotion(code) {
+		return nil, fmt.Errorf("not a potion: %s", code)
+	}
+
+	amount := potionAmount(record)
+
+	duration := float64(record.EffectLength) / potionFramesPerSecond
+	if duration <= 0 {
+		duration = defaultPotionSeconds
+	}
+
+	switch record.Type {
+	case potionTypeHealing:
+		return &PotionEffect{Life: amount, Duration: duration}, nil
+	case potionTypeMana:
+		return &PotionEffect{Mana: amount, Duration: duration}, nil
+	default:
+		percent := float64(amount) / rejuvenationPercentBase
+
+		return &PotionEffect{
+			Life: int(math.Ceil(float64(stats.MaxHealth) * percent)),
+			Mana: int(math.Ceil(float64(stats.MaxMana) * percent)),
+		}, nil
+	}
+}
+
+// potionAmount returns the amount of life or mana restored by the potion, for rejuvenation
+// potions it is the percentage of life and mana. It is the value shown in the description of the
+// potion, or the value of its first usage stat for potions without one.
+func potionAmount(record *d2records.ItemCommonRecord) int {
+	calcs := []d2calculation.CalcString{record.SpellDescriptionCalc}
+
+	for _, usage := range record.UsageStats {
+		calcs = append(calcs, usage.Calc)
+	}
+
+	parser := d2parser.New()
+
+	for _, calc := range calcs {
+		if calculation := parser.Parse(string(calc)); calculation != nil {
+			if amount := calculation.Eval(); amount > 0 {
+				return amount
+			}
+		}
+	}
+
+	return 0
+}
+
+// Advance restores the life and mana for the elapsed time to the given stats,
+// returns true once the effect has finished
+func (e *PotionEffect) Advance(elapsed float64, stats *HeroStatsState) (done bool) {
+	e.elapsed += elapsed
+
+	progress := 1.0
+	if e.Duration > 0 {
+		progress = math.Min(e.elapsed/e.Duration, 1)
+	}
+
+	life := int(float64(e.Life)*progress) - e.lifeRestored
+	mana := int(float64(e.Mana)*progress) - e.manaRestored
+
+	stats.RestoreHealth(life)
+	stats.RestoreMana(mana)
+
+	e.lifeRestored += life
+	e.manaRestored += mana
+
+	return progress >= 1
+}
@@ -2,7 +2,9 @@ package d2hero
 
 import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2automap"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2inventory"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2quest"
 )
 
 // HeroState stores the state of the player
@@ -12,6 +14,7 @@ type HeroState struct {
 	Act        int                            `json:"act"`
 	FilePath   string                         `json:"-"`
 	Equipment  d2inventory.CharacterEquipment `json:"equipment"`
+	Belt       *d2inventory.Belt              `json:"belt"`
 	Stats      *HeroStatsState                `json:"stats"`
 	Skills     map[int]*HeroSkill             `json:"skills"`
 	X          float64                        `json:"x"`
@@ -20,4 +23,10 @@ type HeroState struct {
 	RightSkill int                            `json:"rightSkill"`
 	Gold       int                            `json:"Gold"`
 	Difficulty d2enum.DifficultyType          `json:"difficulty"`
+	Hireling   *HirelingState                 `json:"hireling"`
+	Quests     *d2quest.State                 `json:"quests"`
+	Corpses    []*Corpse                      `json:"corpses"`
+	Hardcore   bool                           `json:"hardcore"`
+	IsDead     bool                           `json:"isDead"` // hardcore heroes stay dead
+	Automap    *d2automap.State               `json:"automap"`
 }
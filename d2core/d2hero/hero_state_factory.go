@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2inventory"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2quest"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2records"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
@@ -57,6 +58,9 @@ func (f *HeroStateFactory) CreateHeroState(
 		FilePath:  "",
 	}
 
+	result.Belt = d2inventory.NewBelt(f.BeltRows(&result.Equipment))
+	result.Quests = d2quest.NewState()
+
 	defaultStats := f.asset.Records.Character.Stats[hero]
 	skillState, err := f.CreateHeroSkillsState(defaultStats, hero)
 
@@ -199,6 +203,16 @@ func (f *HeroStateFactory) LoadHeroState(filePath string) *HeroState {
 		return nil
 	}
 
+	// characters saved before belts were introduced start with an empty belt
+	if result.Belt == nil {
+		result.Belt = d2inventory.NewBelt(f.BeltRows(&result.Equipment))
+	}
+
+	// and with no quests started
+	if result.Quests == nil {
+		result.Quests = d2quest.NewState()
+	}
+
 	// Here, we turn the Shallow skill data back into records from the asset manager.
 	// This is because this factory has a reference to the asset manager with loaded records.
 	// We cant do this while unmarshalling because there is no reference to the asset manager.
@@ -2,6 +2,7 @@ package d2hero
 
 import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2quest"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2records"
 )
 
@@ -54,3 +55,37 @@ func (f *HeroStateFactory) CreateHeroStatsState(heroClass d2enum.Hero, classStat
 
 	return &result
 }
+
+// RestoreHealth adds the given amount of health, up to the maximum health
+func (s *HeroStatsState) RestoreHealth(amount int) {
+	s.Health += amount
+
+	if s.Health > s.MaxHealth {
+		s.Health = s.MaxHealth
+	}
+}
+
+// RestoreMana adds the given amount of mana, up to the maximum mana
+func (s *HeroStatsState) RestoreMana(amount int) {
+	s.Mana += amount
+
+	if s.Mana > s.MaxMana {
+		s.Mana = s.MaxMana
+	}
+}
+
+// ApplyQuestRewards gives the stat rewards of a completed quest, the service rewards are
+// kept in the quest state until they are claimed
+func (s *HeroStatsState) ApplyQuestRewards(rewards []d2quest.Reward) {
+	for _, reward := range rewards {
+		switch reward.Type {
+		case d2quest.RewardSkillPoints:
+			s.SkillPoints += reward.Value
+		case d2quest.RewardStatPoints:
+			s.StatsPoints += reward.Value
+		case d2quest.RewardLife:
+			s.MaxHealth += reward.Value
+			s.Health += reward.Value
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package d2inventory
+
+const (
+	// BeltColumns is the number of columns of a belt, each column is bound to a quick-key
+	BeltColumns = 4
+	// DefaultBeltRows is the number of rows available when no belt is equipped
+	DefaultBeltRows = 1
+	// MaxBeltRows is the number of rows of the biggest belts
+	MaxBeltRows = 4
+)
+
+// Belt stores the potions (and scrolls) which can be used with the belt quick-keys.
+// Each column is a stack of items, the item at index 0 of a column is the bottom
+// item, which is the one used by the quick-key.
+type Belt struct {
+	Rows    int                               `json:"rows"`
+	Columns [BeltColumns][]*InventoryItemMisc `json:"columns"`
+}
+
+// NewBelt creates an empty belt with the given number of rows
+func NewBelt(rows int) *Belt {
+	belt := &Belt{}
+	belt.SetRows(rows)
+
+	return belt
+}
+
+// NumRows returns the number of rows of the belt
+func (b *Belt) NumRows() int {
+	return b.Rows
+}
+
+// SetRows changes the number of rows of the belt, like when a different belt is
+// equipped. Returns the items which no longer fit in the belt.
+func (b *Belt) SetRows(rows int) (overflow []*InventoryItemMisc) {
+	if rows < DefaultBeltRows {
+		rows = DefaultBeltRows
+	} else if rows > MaxBeltRows {
+		rows = MaxBeltRows
+	}
+
+	b.Rows = rows
+
+	for column := range b.Columns {
+		if len(b.Columns[column]) > rows {
+			overflow = append(overflow, b.Columns[column][rows:]...)
+			b.Columns[column] = b.Columns[column][:rows]
+		}
+	}
+
+	return overflow
+}
+
+// Add puts the item into the belt, it goes into the first column which already holds
+// the same kind of item, otherwise into the first empty column. Returns false if
+// there is no room for the item.
+func (b *Belt) Add(item *InventoryItemMisc) bool {
+	if item == nil {
+		return false
+	}
+
+	column := b.columnFor(item.ItemCode)
+	if column < 0 {
+		return false
+	}
+
+	b.Columns[column] = append(b.Columns[column], item)
+
+	return true
+}
+
+// CanAdd returns whether there is room in the belt for an item with the given code
+func (b *Belt) CanAdd(code string) bool {
+	return b.columnFor(code) >= 0
+}
+
+// Peek returns the bottom item of the given column, or nil if the column is empty
+func (b *Belt) Peek(column int) *InventoryItemMisc {
+	if column < 0 || column >= BeltColumns || len(b.Columns[column]) == 0 {
+		return nil
+	}
+
+	return b.Columns[column][0]
+}
+
+// Use removes and returns the bottom item of the given column, the items above it
+// move down. Returns nil if the column is empty.
+func (b *Belt) Use(column int) *InventoryItemMisc {
+	item := b.Peek(column)
+	if item == nil {
+		return nil
+	}
+
+	b.Columns[column] = b.Columns[column][1:]
+
+	return item
+}
+
+// Items returns all of the items in the belt
+func (b *Belt) Items() []*InventoryItemMisc {
+	result := make([]*InventoryItemMisc, 0)
+
+	for column := range b.Columns {
+		result = append(result, b.Columns[column]...)
+	}
+
+	return result
+}
+
+func (b *Belt) columnFor(code string) int {
+	for column := range b.Columns {
+		items := b.Columns[column]
+		if len(items) > 0 && len(items) < b.Rows && items[0].ItemCode == code {
+			return column
+		}
+	}
+
+	for column := range b.Columns {
+		if len(b.Columns[column]) == 0 {
+			return column
+		}
+	}
+
+	return -1
+}
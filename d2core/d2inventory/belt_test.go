@@ -0,0 +1,75 @@
+package d2inventory
+
+import (
+	"testing"
+)
+
+func TestBeltAddFillsMatchingColumn(t *testing.T) {
+	belt := NewBelt(2)
+
+	for _, code := range []string{"hp1", "mp1", "hp1", "hp1"} {
+		if !belt.Add(&InventoryItemMisc{ItemCode: code}) {
+			t.Fatalf("failed to add %s to the belt", code)
+		}
+	}
+
+	expected := [BeltColumns]int{2, 1, 1, 0}
+
+	for column := range expected {
+		if have := len(belt.Columns[column]); have != expected[column] {
+			t.Errorf("unexpected number of items in column %d, want %d, have %d", column, expected[column], have)
+		}
+	}
+}
+
+func TestBeltFull(t *testing.T) {
+	belt := NewBelt(DefaultBeltRows)
+
+	for column := 0; column < BeltColumns; column++ {
+		belt.Add(&InventoryItemMisc{ItemCode: "hp1"})
+	}
+
+	if belt.CanAdd("hp1") || belt.Add(&InventoryItemMisc{ItemCode: "hp1"}) {
+		t.Error("expected a full belt to reject the item")
+	}
+}
+
+func TestBeltUse(t *testing.T) {
+	belt := NewBelt(MaxBeltRows)
+	belt.Add(&InventoryItemMisc{ItemCode: "hp1"})
+	belt.Add(&InventoryItemMisc{ItemCode: "hp1"})
+
+	if item := belt.Use(0); item == nil || item.ItemCode != "hp1" {
+		t.Fatal("expected to use the bottom item of the column")
+	}
+
+	if len(belt.Columns[0]) != 1 {
+		t.Errorf("unexpected number of items after use, want %d, have %d", 1, len(belt.Columns[0]))
+	}
+
+	if item := belt.Use(1); item != nil {
+		t.Error("expected an empty column to have nothing to use")
+	}
+
+	if item := belt.Use(BeltColumns); item != nil {
+		t.Error("expected an invalid column to have nothing to use")
+	}
+}
+
+func TestBeltSetRowsOverflow(t *testing.T) {
+	belt := NewBelt(MaxBeltRows)
+
+	for idx := 0; idx < 3; idx++ {
+		belt.Add(&InventoryItemMisc{ItemCode: "mp1"})
+	}
+
+	overflow := belt.SetRows(1)
+
+	if len(overflow) != 2 {
+		t.Errorf("unexpected number of overflowing items, want %d, have %d", 2, len(overflow))
+	}
+
+	if len(belt.Items()) != 1 {
+		t.Errorf("unexpected number of items left in the belt, want %d, have %d", 1, len(belt.Items()))
+	}
+}
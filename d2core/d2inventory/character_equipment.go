@@ -1,5 +1,11 @@
 package d2inventory
 
+import (
+	"math/rand"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2item"
+)
+
 // CharacterEquipment stores equipments of a character
 type CharacterEquipment struct {
 	Head      *InventoryItemArmor  `json:"head"`      // Head
@@ -10,5 +16,136 @@ type CharacterEquipment struct {
 	LeftHand  *InventoryItemWeapon `json:"leftHand"`  // LH
 	RightHand *InventoryItemWeapon `json:"rightHand"` // RH
 	Shield    *InventoryItemArmor  `json:"shield"`    // SH
+	Belt      *InventoryItemArmor  `json:"belt"`      // the equipped belt, decides the rows of the potion belt
 	// S1-S8?
 }
+
+type durableEquipment struct {
+	code       string
+	durability *d2item.ItemDurability
+	wearable   d2item.Durable
+	shield     bool
+}
+
+// durableItems returns the equipped items which can lose durability
+func (c *CharacterEquipment) durableItems() []durableEquipment {
+	result := make([]durableEquipment, 0)
+
+	for _, armor := range []*InventoryItemArmor{c.Head, c.Torso, c.Legs, c.RightArm, c.LeftArm, c.Shield, c.Belt} {
+		if armor != nil && armor.HasDurability() {
+			result = append(result, durableEquipment{
+				code:       armor.ItemCode,
+				durability: &armor.ItemDurability,
+				wearable:   armor,
+				shield:     armor == c.Shield,
+			})
+		}
+	}
+
+	for _, weapon := range []*InventoryItemWeapon{c.LeftHand, c.RightHand} {
+		if weapon != nil && weapon.HasDurability() {
+			result = append(result, durableEquipment{
+				code:       weapon.ItemCode,
+				durability: &weapon.ItemDurability,
+				wearable:   weapon,
+			})
+		}
+	}
+
+	return result
+}
+
+// ApplyWearEvent rolls for the loss of durability of the equipped items for the given
+// combat event, and returns the codes of the items which lost durability and of those which
+// broke. When struck, only one random piece of armor can lose durability, and only the
+// shield can wear from blocking.
+func (c *CharacterEquipment) ApplyWearEvent(event d2item.WearEvent, r *rand.Rand) (worn, broken []string) {
+	worn, broken = make([]string, 0), make([]string, 0)
+	candidates := make([]durableEquipment, 0)
+
+	for _, item := range c.durableItems() {
+		if !item.wearable.WearsFrom(event) {
+			continue
+		}
+
+		if event == d2item.WearEventBlock && !item.shield {
+			continue
+		}
+
+		candidates = append(candidates, item)
+	}
+
+	if len(candidates) < 1 {
+		return worn, broken
+	}
+
+	if event == d2item.WearEventStruck {
+		picked := r.Intn(len(candidates))
+		candidates = candidates[picked : picked+1]
+	}
+
+	for _, item := range candidates {
+		current, _ := item.durability.Durability()
+
+		if d2item.RollWear(item.wearable, event, r) {
+			broken = append(broken, item.code)
+		}
+
+		if after, _ := item.durability.Durability(); after < current {
+			worn = append(worn, item.code)
+		}
+	}
+
+	return worn, broken
+}
+
+// RepairAll restores the durability of all equipped items which can be repaired
+func (c *CharacterEquipment) RepairAll() {
+	for _, item := range c.durableItems() {
+		item.durability.Repair()
+	}
+}
+
+// Replenish restores durability over time for all equipped items, given the
+// amount of durability restored per second by replenish-durability stats
+func (c *CharacterEquipment) Replenish(elapsed, rate float64) {
+	for _, item := range c.durableItems() {
+		item.durability.Replenish(elapsed, rate)
+	}
+}
+
+// IsEmpty returns true if no item is equipped
+func (c *CharacterEquipment) IsEmpty() bool {
+	return *c == CharacterEquipment{}
+}
+
+// TakeFrom moves the items of the other equipment into the slots of this equipment which are
+// empty, the items whose slots are taken stay in the other equipment
+func (c *CharacterEquipment) TakeFrom(other *CharacterEquipment) {
+	armors := []struct{ to, from **InventoryItemArmor }{
+		{&c.Head, &other.Head},
+		{&c.Torso, &other.Torso},
+		{&c.Legs, &other.Legs},
+		{&c.RightArm, &other.RightArm},
+		{&c.LeftArm, &other.LeftArm},
+		{&c.Shield, &other.Shield},
+		{&c.Belt, &other.Belt},
+	}
+
+	for _, slot := range armors {
+		if *slot.to == nil && *slot.from != nil {
+			*slot.to, *slot.from = *slot.from, nil
+		}
+	}
+
+	weapons := []struct{ to, from **InventoryItemWeapon }{
+		{&c.LeftHand, &other.LeftHand},
+		{&c.RightHand, &other.RightHand},
+	}
+
+	for _, slot := range weapons {
+		if *slot.to == nil && *slot.from != nil {
+			*slot.to, *slot.from = *slot.from, nil
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package d2inventory
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2item"
+)
+
+func TestCharacterEquipmentTakeFrom(t *testing.T) {
+	helm := &InventoryItemArmor{ItemCode: "cap"}
+	corpseHelm := &InventoryItemArmor{ItemCode: "skp"}
+	sword := &InventoryItemWeapon{ItemCode: "ssd"}
+
+	equipment := &CharacterEquipment{Head: helm}
+	corpse := &CharacterEquipment{Head: corpseHelm, RightHand: sword}
+
+	equipment.TakeFrom(corpse)
+
+	if equipment.Head != helm || corpse.Head != corpseHelm {
+		t.Error("an equipped item should not be replaced")
+	}
+
+	if equipment.RightHand != sword || corpse.RightHand != nil {
+		t.Error("an item of an empty slot should be moved")
+	}
+
+	if corpse.IsEmpty() {
+		t.Error("the corpse should keep the item whose slot was taken")
+	}
+
+	equipment.Head = nil
+	equipment.TakeFrom(corpse)
+
+	if !corpse.IsEmpty() {
+		t.Error("the corpse should be empty")
+	}
+}
+
+func testDurableEquipment() *CharacterEquipment {
+	return &CharacterEquipment{
+		Head:      &InventoryItemArmor{ItemCode: "cap", ItemDurability: d2item.NewItemDurability(100, false, false)},
+		Torso:     &InventoryItemArmor{ItemCode: "qui", ItemDurability: d2item.NewItemDurability(100, false, false)},
+		Shield:    &InventoryItemArmor{ItemCode: "buc", ItemDurability: d2item.NewItemDurability(100, false, false)},
+		RightHand: &InventoryItemWeapon{ItemCode: "ssd", ItemDurability: d2item.NewItemDurability(100, false, false)},
+	}
+}
+
+func TestCharacterEquipmentApplyWearEvent(t *testing.T) {
+	r := rand.New(rand.NewSource(1)) // nolint:gosec // not concerned with crypto-strong randomness
+
+	tests := []struct {
+		name  string
+		event d2item.WearEvent
+		wears []string
+	}{
+		{"attack", d2item.WearEventAttack, []string{"ssd"}},
+		{"struck", d2item.WearEventStruck, []string{"cap", "qui", "buc"}},
+		{"block", d2item.WearEventBlock, []string{"buc"}},
+	}
+
+	for _, test := range tests {
+		equipment := testDurableEquipment()
+		wornCodes := make(map[string]bool)
+
+		for idx := 0; idx < 1000; idx++ {
+			worn, _ := equipment.ApplyWearEvent(test.event, r)
+			if len(worn) > 1 {
+				t.Fatalf("%s: want at most one item to wear at a time, have %v", test.name, worn)
+			}
+
+			for _, code := range worn {
+				wornCodes[code] = true
+			}
+		}
+
+		for _, code := range test.wears {
+			if !wornCodes[code] {
+				t.Errorf("%s: want %s to wear", test.name, code)
+			}
+
+			delete(wornCodes, code)
+		}
+
+		if len(wornCodes) > 0 {
+			t.Errorf("%s: want no other items to wear, have %v", test.name, wornCodes)
+		}
+	}
+}
+
+func TestCharacterEquipmentRepairAll(t *testing.T) {
+	equipment := testDurableEquipment()
+	equipment.Torso.ItemDurability = d2item.NewItemDurability(100, true, false)
+
+	for _, durability := range []*d2item.ItemDurability{&equipment.Head.ItemDurability,
+		&equipment.Torso.ItemDurability, &equipment.RightHand.ItemDurability} {
+		durability.Wear(10)
+	}
+
+	equipment.RepairAll()
+
+	if equipment.Head.Current != equipment.Head.Max || equipment.RightHand.Current != equipment.RightHand.Max {
+		t.Error("want the equipment repaired")
+	}
+
+	if equipment.Torso.Current == equipment.Torso.Max {
+		t.Error("want the ethereal armor not to be repaired")
+	}
+}
@@ -2,17 +2,19 @@ package d2inventory
 
 import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2item"
 )
 
 // InventoryItemArmor stores the info of an armor item in the inventory
 type InventoryItemArmor struct {
-	InventorySizeX int    `json:"inventorySizeX"`
-	InventorySizeY int    `json:"inventorySizeY"`
-	InventorySlotX int    `json:"inventorySlotX"`
-	InventorySlotY int    `json:"inventorySlotY"`
-	ItemName       string `json:"itemName"`
-	ItemCode       string `json:"itemCode"`
-	ArmorClass     string `json:"armorClass"`
+	InventorySizeX        int    `json:"inventorySizeX"`
+	InventorySizeY        int    `json:"inventorySizeY"`
+	InventorySlotX        int    `json:"inventorySlotX"`
+	InventorySlotY        int    `json:"inventorySlotY"`
+	ItemName              string `json:"itemName"`
+	ItemCode              string `json:"itemCode"`
+	ArmorClass            string `json:"armorClass"`
+	d2item.ItemDurability `json:"durability"`
 }
 
 // GetArmorClass returns the class of the armor
@@ -66,3 +68,8 @@ func (v *InventoryItemArmor) GetItemCode() string {
 
 	return v.ItemCode
 }
+
+// WearsFrom returns true if the armor can lose durability from the given event
+func (v *InventoryItemArmor) WearsFrom(event d2item.WearEvent) bool {
+	return event == d2item.WearEventStruck || event == d2item.WearEventBlock
+}
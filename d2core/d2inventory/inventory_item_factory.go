@@ -5,8 +5,13 @@ import (
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2item"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2records"
 )
 
+// the item type of the belts in itemtypes.txt
+const beltItemType = "belt"
+
 // NewInventoryItemFactory creates a new InventoryItemFactory and initializes it
 func NewInventoryItemFactory(asset *d2asset.AssetManager) (*InventoryItemFactory, error) {
 	factory := &InventoryItemFactory{asset: asset}
@@ -105,6 +110,16 @@ func (f *InventoryItemFactory) loadHeroObjects() error {
 
 // GetArmorItemByCode returns the armor item for the given code
 func (f *InventoryItemFactory) GetArmorItemByCode(code string) (*InventoryItemArmor, error) {
+	return f.newArmorItem(code, false)
+}
+
+// GetEtherealArmorItemByCode returns the ethereal armor item for the given code, it has less
+// durability and can't be repaired
+func (f *InventoryItemFactory) GetEtherealArmorItemByCode(code string) (*InventoryItemArmor, error) {
+	return f.newArmorItem(code, true)
+}
+
+func (f *InventoryItemFactory) newArmorItem(code string, ethereal bool) (*InventoryItemArmor, error) {
 	result := f.asset.Records.Item.Armors[code]
 	if result == nil {
 		return nil, fmt.Errorf("could not find armor entry for code '%s'", code)
@@ -116,6 +131,7 @@ func (f *InventoryItemFactory) GetArmorItemByCode(code string) (*InventoryItemAr
 		ItemName:       result.Name,
 		ItemCode:       result.Code,
 		ArmorClass:     d2enum.ArmorClassLite, // comes from ArmType.txt
+		ItemDurability: newDurability(result, ethereal),
 	}, nil
 }
 
@@ -136,6 +152,16 @@ func (f *InventoryItemFactory) GetMiscItemByCode(code string) (*InventoryItemMis
 
 // GetWeaponItemByCode returns the weapon item for the given code
 func (f *InventoryItemFactory) GetWeaponItemByCode(code string) (*InventoryItemWeapon, error) {
+	return f.newWeaponItem(code, false)
+}
+
+// GetEtherealWeaponItemByCode returns the ethereal weapon item for the given code, it has less
+// durability and can't be repaired
+func (f *InventoryItemFactory) GetEtherealWeaponItemByCode(code string) (*InventoryItemWeapon, error) {
+	return f.newWeaponItem(code, true)
+}
+
+func (f *InventoryItemFactory) newWeaponItem(code string, ethereal bool) (*InventoryItemWeapon, error) {
 	result := f.asset.Records.Item.Weapons[code]
 	if result == nil {
 		return nil, fmt.Errorf("could not find weapon entry for code '%s'", code)
@@ -148,5 +174,124 @@ func (f *InventoryItemFactory) GetWeaponItemByCode(code string) (*InventoryItemW
 		ItemCode:           result.Code,
 		WeaponClass:        result.WeaponClass,
 		WeaponClassOffHand: result.WeaponClass2Hand,
+		ItemDurability:     newDurability(result, ethereal),
 	}, nil
 }
+
+// RepairCost returns the gold cost of repairing the item with the given code and durability
+func (f *InventoryItemFactory) RepairCost(code string, durability *d2item.ItemDurability) int {
+	record := f.asset.Records.Item.All[code]
+	if record == nil {
+		return 0
+	}
+
+	return durability.RepairCost(record.Cost)
+}
+
+// RepairAllCost returns the gold cost of repairing all of the given equipment at a vendor with
+// the given repair multiplier of npc.txt
+func (f *InventoryItemFactory) RepairAllCost(equipment *CharacterEquipment, multiplier float64) int {
+	total := 0
+
+	for _, item := range equipment.durableItems() {
+		total += f.RepairCost(item.code, item.durability)
+	}
+
+	cost := int(float64(total) * multiplier)
+	if total > 0 && cost < 1 {
+		cost = 1
+	}
+
+	return cost
+}
+
+// BeltRows returns the number of belt rows granted by the equipped belt
+func (f *InventoryItemFactory) BeltRows(equipment *CharacterEquipment) int {
+	if equipment == nil || equipment.Belt == nil {
+		return DefaultBeltRows
+	}
+
+	record := f.asset.Records.Item.Armors[equipment.Belt.ItemCode]
+	if record == nil {
+		return DefaultBeltRows
+	}
+
+	belt := f.asset.Records.GetBeltByIndex(record.BeltType)
+	if belt == nil || belt.NumBoxes < BeltColumns {
+		return DefaultBeltRows
+	}
+
+	return belt.NumBoxes / BeltColumns
+}
+
+// IsBeltable returns whether the item with the given code can be put into the belt
+func (f *InventoryItemFactory) IsBeltable(code string) bool {
+	record := f.asset.Records.Item.Misc[code]
+	if record == nil {
+		return false
+	}
+
+	itemType := f.asset.Records.Item.Types[record.Type]
+
+	return itemType != nil && itemType.Beltable
+}
+
+// AutoBelt puts the given item into the belt if it is automatically belted when
+// picked up, like potions. Returns false if the item was not put into the belt.
+func (f *InventoryItemFactory) AutoBelt(belt *Belt, item *InventoryItemMisc) bool {
+	record := f.asset.Records.Item.Misc[item.GetItemCode()]
+	if belt == nil || record == nil || !record.AutoBelt || !f.IsBeltable(record.Code) {
+		return false
+	}
+
+	return belt.Add(item)
+}
+
+// EquipBelt equips the belt and changes the rows of the potion belt to the ones it grants.
+// Returns the potions which no longer fit in the belt.
+func (f *InventoryItemFactory) EquipBelt(equipment *CharacterEquipment, belt *Belt,
+	item *InventoryItemArmor) []*InventoryItemMisc {
+	equipment.Belt = item
+
+	return belt.SetRows(f.BeltRows(equipment))
+}
+
+// PickupItem puts the item with the given code, picked up from the ground, into the belt:
+// potions are belted, and a belt is equipped when none is. Returns false if there is no
+// room for the item. Ethereal belts have less durability.
+func (f *InventoryItemFactory) PickupItem(equipment *CharacterEquipment, belt *Belt, code string, ethereal bool) bool {
+	if belt == nil || equipment == nil {
+		return false
+	}
+
+	if record := f.asset.Records.Item.Armors[code]; record != nil && record.Type == beltItemType {
+		if equipment.Belt != nil {
+			return false
+		}
+
+		item, err := f.newArmorItem(code, ethereal)
+		if err != nil {
+			return false
+		}
+
+		// no belt has less rows than having no belt at all, so nothing overflows
+		f.EquipBelt(equipment, belt, item)
+
+		return true
+	}
+
+	item, err := f.GetMiscItemByCode(code)
+	if err != nil {
+		return false
+	}
+
+	return f.AutoBelt(belt, item)
+}
+
+func newDurability(record *d2records.ItemCommonRecord, ethereal bool) d2item.ItemDurability {
+	if record.NoDurability {
+		return d2item.ItemDurability{}
+	}
+
+	return d2item.NewItemDurability(record.Durability, ethereal, false)
+}
@@ -0,0 +1,92 @@
+package d2inventory
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2records"
+)
+
+func testItemFactory() *InventoryItemFactory {
+	asset := &d2asset.AssetManager{}
+	asset.Records = &d2records.RecordManager{}
+	asset.Records.Item.Armors = d2records.CommonItems{
+		"cap": {Code: "cap", Durability: 12, Cost: 100},
+		"cir": {Code: "cir", NoDurability: true, Cost: 100},
+	}
+	asset.Records.Item.Weapons = d2records.CommonItems{
+		"ssd": {Code: "ssd", Durability: 24, Cost: 200},
+	}
+	asset.Records.Item.All = d2records.CommonItems{
+		"cap": asset.Records.Item.Armors["cap"],
+		"cir": asset.Records.Item.Armors["cir"],
+		"ssd": asset.Records.Item.Weapons["ssd"],
+	}
+
+	return &InventoryItemFactory{asset: asset}
+}
+
+func TestEtherealItems(t *testing.T) {
+	factory := testItemFactory()
+
+	armor, err := factory.GetEtherealArmorItemByCode("cap")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !armor.Ethereal || armor.Max != 7 || armor.IsRepairable() {
+		t.Errorf("want an ethereal cap with 7 durability, have %+v", armor.ItemDurability)
+	}
+
+	weapon, err := factory.GetEtherealWeaponItemByCode("ssd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !weapon.Ethereal || weapon.Max != 13 {
+		t.Errorf("want an ethereal sword with 13 durability, have %+v", weapon.ItemDurability)
+	}
+
+	circlet, err := factory.GetEtherealArmorItemByCode("cir")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if circlet.HasDurability() {
+		t.Errorf("want no durability for an item without durability, have %+v", circlet.ItemDurability)
+	}
+
+	if armor, _ := factory.GetArmorItemByCode("cap"); armor.Ethereal || armor.Max != 12 {
+		t.Errorf("want a normal cap with 12 durability, have %+v", armor.ItemDurability)
+	}
+}
+
+func TestRepairAllCost(t *testing.T) {
+	factory := testItemFactory()
+
+	helm, _ := factory.GetArmorItemByCode("cap")
+	sword, _ := factory.GetWeaponItemByCode("ssd")
+	equipment := &CharacterEquipment{Head: helm, RightHand: sword}
+
+	if cost := factory.RepairAllCost(equipment, 1); cost != 0 {
+		t.Errorf("want no cost without lost durability, have %d", cost)
+	}
+
+	helm.Wear(6)
+	sword.Wear(6)
+
+	tests := []struct {
+		multiplier float64
+		want       int
+	}{
+		{1, 100},
+		{0.5, 50},
+		{0.001, 1},
+	}
+
+	for _, test := range tests {
+		if cost := factory.RepairAllCost(equipment, test.multiplier); cost != test.want {
+			t.Errorf("multiplier %g: want %d, have %d", test.multiplier, test.want, cost)
+		}
+	}
+}
@@ -2,18 +2,20 @@ package d2inventory
 
 import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2item"
 )
 
 // InventoryItemWeapon stores the info of an weapon item in the inventory
 type InventoryItemWeapon struct {
-	InventorySizeX     int    `json:"inventorySizeX"`
-	InventorySizeY     int    `json:"inventorySizeY"`
-	InventorySlotX     int    `json:"inventorySlotX"`
-	InventorySlotY     int    `json:"inventorySlotY"`
-	ItemName           string `json:"itemName"`
-	ItemCode           string `json:"itemCode"`
-	WeaponClass        string `json:"weaponClass"`
-	WeaponClassOffHand string `json:"weaponClassOffHand"`
+	InventorySizeX        int    `json:"inventorySizeX"`
+	InventorySizeY        int    `json:"inventorySizeY"`
+	InventorySlotX        int    `json:"inventorySlotX"`
+	InventorySlotY        int    `json:"inventorySlotY"`
+	ItemName              string `json:"itemName"`
+	ItemCode              string `json:"itemCode"`
+	WeaponClass           string `json:"weaponClass"`
+	WeaponClassOffHand    string `json:"weaponClassOffHand"`
+	d2item.ItemDurability `json:"durability"`
 }
 
 // GetWeaponClass returns the class of the weapon
@@ -76,3 +78,8 @@ func (v *InventoryItemWeapon) GetItemCode() string {
 
 	return v.ItemCode
 }
+
+// WearsFrom returns true if the weapon can lose durability from the given event
+func (v *InventoryItemWeapon) WearsFrom(event d2item.WearEvent) bool {
+	return event == d2item.WearEventAttack
+}
@@ -75,6 +75,7 @@ type Item struct {
 	statList        d2stats.StatList
 	uniqueStatList  d2stats.StatList
 	setItemStatList d2stats.StatList
+	setItemBonuses  []*SetBonus
 
 	attributes *itemAttributes
 
@@ -100,7 +101,6 @@ type itemAttributes struct {
 	quality                 int
 	defense                 int
 	currentStackSize        int
-	currentDurability       int
 	baseItemLevel           int
 	requiredLevel           int
 	numSockets              int
@@ -109,6 +109,8 @@ type itemAttributes struct {
 	requiredDexterity       int
 	classSpecific           d2enum.Hero
 
+	itemDurability d2item.ItemDurability // the current durability, with the durability stats applied
+
 	identitified   bool
 	crafted        bool
 	durable        bool // some items specify that they have no durability
@@ -402,6 +404,8 @@ func (i *Item) init() *Item {
 	i.generateAllProperties()
 	i.updateItemAttributes()
 
+	i.attributes.currentStackSize = i.initialCharges()
+
 	return i
 }
 
@@ -443,7 +447,10 @@ func (i *Item) generateProperties(pool PropertyPool) {
 		if generated := i.generateSetItemProperties(); generated != nil {
 			props = generated
 		}
-	case PropertyPoolSet: // https://github.com/OpenDiablo2/OpenDiablo2/issues/817
+	case PropertyPoolSet:
+		// set bonuses depend on how many set items are equipped, so they are kept
+		// apart from the other properties and evaluated against the equipper
+		i.generateSetItemBonuses()
 	}
 
 	if props == nil {
@@ -465,7 +472,7 @@ func (i *Item) generateProperties(pool PropertyPool) {
 		case propertyEthereal:
 			i.attributes.ethereal = i.attributes.ethereal || prop.computedBool
 		case propertyIndestructable:
-			i.attributes.indestructable = i.attributes.ethereal || prop.computedBool
+			i.attributes.indestructable = i.attributes.indestructable || prop.computedBool
 		}
 	}
 }
@@ -473,6 +480,12 @@ func (i *Item) generateProperties(pool PropertyPool) {
 func (i *Item) updateItemAttributes() {
 	i.generateName()
 
+	// flags which were rolled by the properties, or set by identifying the item
+	previous := i.attributes
+	if previous == nil {
+		previous = &itemAttributes{}
+	}
+
 	r := i.CommonRecord()
 	i.attributes = &itemAttributes{
 		damageOneHand: minMaxEnhanceable{
@@ -504,6 +517,12 @@ func (i *Item) updateItemAttributes() {
 		requiredDexterity: r.RequiredDexterity,
 		durable:           !r.NoDurability,
 		throwable:         r.Throwable,
+
+		identitified:     previous.identitified,
+		crafted:          previous.crafted,
+		ethereal:         previous.ethereal,
+		indestructable:   previous.indestructable,
+		currentStackSize: previous.currentStackSize,
 	}
 
 	def, minDef, maxDef := 0, r.MinAC, r.MaxAC
@@ -517,6 +536,16 @@ func (i *Item) updateItemAttributes() {
 	}
 
 	i.attributes.defense = def
+
+	if i.attributes.ethereal {
+		i.applyEtherealModifiers()
+	}
+
+	i.attributes.itemDurability = i.newDurability()
+	if current := previous.itemDurability.Current; previous.itemDurability.Max > 0 &&
+		current < i.attributes.itemDurability.Current {
+		i.attributes.itemDurability.Current = current
+	}
 }
 
 func (i *Item) generateAffixProperties(pool PropertyPool) []*Property {
@@ -667,7 +696,11 @@ func (i *Item) generateName() {
 
 // GetStatStrings is a test function for getting all stat strings
 func (i *Item) GetStatStrings() []string {
-	result := make([]string, 0)
+	return i.statStrings(i.propertyStats())
+}
+
+// propertyStats returns the stats of all of the item's properties
+func (i *Item) propertyStats() []d2stats.Stat {
 	stats := make([]d2stats.Stat, 0)
 
 	for pool := range i.properties {
@@ -689,6 +722,12 @@ func (i *Item) GetStatStrings() []string {
 		}
 	}
 
+	return stats
+}
+
+func (i *Item) statStrings(stats []d2stats.Stat) []string {
+	result := make([]string, 0)
+
 	if len(stats) > 0 {
 		stats = i.factory.stat.NewStatList(stats...).ReduceStats().Stats()
 	}
@@ -778,6 +817,24 @@ func (i *Item) GetItemCode() string {
 	return i.CommonRecord().Code
 }
 
+// Codes returns the codes the item can be created with by ItemFactory.NewItem, like when a
+// dropped item is sent to the clients
+func (i *Item) Codes() []string {
+	codes := []string{i.CommonCode}
+
+	switch {
+	case i.SetItemCode != "":
+		codes = append(codes, i.SetItemCode)
+	case i.UniqueCode != "":
+		codes = append(codes, i.UniqueCode)
+	default:
+		codes = append(codes, i.PrefixCodes...)
+		codes = append(codes, i.SuffixCodes...)
+	}
+
+	return codes
+}
+
 // Serialize the item to a byte slize
 func (i *Item) Serialize() []byte {
 	panic("item serialization not yet implemented")
@@ -868,6 +925,8 @@ func (i *Item) GetItemDescription() []string {
 		lines = append(lines, str)
 	}
 
+	lines = append(lines, i.getDurabilityDescription()...)
+
 	if common.RequiredStrength > 1 {
 		str = fmt.Sprintf("%s %v", i.factory.asset.TranslateString(reqStrength),
 			common.RequiredStrength)
@@ -888,6 +947,12 @@ func (i *Item) GetItemDescription() []string {
 		lines = append(lines, str)
 	}
 
+	lines = append(lines, i.getChargesDescription()...)
+
+	if !i.IsIdentified() {
+		return append(lines, i.getUnidentifiedDescription()...)
+	}
+
 	statStrings := i.GetStatStrings()
 
 	for _, statStr := range statStrings {
@@ -895,5 +960,68 @@ func (i *Item) GetItemDescription() []string {
 		lines = append(lines, str)
 	}
 
+	lines = append(lines, i.getSetDescription()...)
+
+	return lines
+}
+
+// getSetDescription returns the set bonus lines of the item description.
+// Active bonuses are green (per-item) or gold (partial/full set), bonuses
+// which are not yet active are grey. The items of the set are listed in green
+// when equipped, and in red otherwise
+func (i *Item) getSetDescription() []string {
+	lines := make([]string, 0)
+
+	setRecord := i.SetRecord()
+	if i.SetItemRecord() == nil || setRecord == nil {
+		return lines
+	}
+
+	numEquipped := i.NumEquippedSetItems()
+	setItems := i.factory.SetItemRecords(i.SetCode)
+	numInSet := len(setItems)
+
+	for _, bonus := range i.setItemBonuses {
+		token := d2ui.ColorTokenGrey
+		if bonus.IsActive(numEquipped, numInSet) {
+			token = d2ui.ColorTokenSetItem
+		}
+
+		for _, statStr := range i.statStrings(bonus.Stats()) {
+			lines = append(lines, d2ui.ColorTokenize(statStr, token))
+		}
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, d2ui.ColorTokenize(
+		i.factory.asset.TranslateString(setRecord.StringTableKey), d2ui.ColorTokenGold))
+
+	equipped := make(map[string]bool)
+	for _, item := range equippedSetItems(i.statContext) {
+		equipped[item.SetItemCode] = true
+	}
+
+	sort.Slice(setItems, func(a, b int) bool { return setItems[a].SetItemKey < setItems[b].SetItemKey })
+
+	for _, record := range setItems {
+		token := d2ui.ColorTokenRed
+		if equipped[record.SetItemKey] {
+			token = d2ui.ColorTokenSetItem
+		}
+
+		lines = append(lines, d2ui.ColorTokenize(i.factory.asset.TranslateString(record.SetItemKey), token))
+	}
+
+	for _, bonus := range i.factory.SetBonuses(i.SetCode) {
+		token := d2ui.ColorTokenGrey
+		if bonus.IsActive(numEquipped, numInSet) {
+			token = d2ui.ColorTokenGold
+		}
+
+		for _, statStr := range i.statStrings(bonus.Stats()) {
+			lines = append(lines, d2ui.ColorTokenize(statStr, token))
+		}
+	}
+
 	return lines
 }
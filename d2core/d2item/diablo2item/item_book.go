@@ -0,0 +1,196 @@
+package diablo2item
+
+import (
+	"fmt"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2records"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2ui"
+)
+
+// item codes of the identify and town portal scrolls and tomes, see books.txt
+const (
+	identifyScrollCode   = "isc"
+	identifyTomeCode     = "ibk"
+	townPortalScrollCode = "tsc"
+	townPortalTomeCode   = "tbk"
+)
+
+// IsIdentified returns true if the item has been identified
+func (i *Item) IsIdentified() bool {
+	return i.attributes.identitified
+}
+
+// NeedsIdentification returns true if the item has hidden properties which are revealed
+// by identifying it, which is the case for magic, rare, set and unique items
+func (i *Item) NeedsIdentification() bool {
+	if i.SetItemRecord() != nil || i.UniqueRecord() != nil {
+		return true
+	}
+
+	return len(i.PrefixRecords())+len(i.SuffixRecords()) > 0
+}
+
+// BookRecord returns the books.txt record of a tome or scroll, with the given item code
+func (f *ItemFactory) BookRecord(code string) *d2records.BookRecord {
+	for _, record := range f.asset.Records.Item.Books {
+		if record.ScrollSpellCode == code || record.BookSpellCode == code {
+			return record
+		}
+	}
+
+	return nil
+}
+
+// BookRecord returns the books.txt record if the item is a tome or scroll, otherwise nil
+func (i *Item) BookRecord() *d2records.BookRecord {
+	return i.factory.BookRecord(i.CommonCode)
+}
+
+// IsTome returns true if the item is a tome, which holds charges of its scroll
+func (i *Item) IsTome() bool {
+	record := i.BookRecord()
+	return record != nil && record.BookSpellCode == i.CommonCode
+}
+
+// IsIdentifyBook returns true if the item is a scroll or tome of identify
+func (i *Item) IsIdentifyBook() bool {
+	return i.CommonCode == identifyScrollCode || i.CommonCode == identifyTomeCode
+}
+
+// IsTownPortalBook returns true if the item is a scroll or tome of town portal
+func (i *Item) IsTownPortalBook() bool {
+	return i.CommonCode == townPortalScrollCode || i.CommonCode == townPortalTomeCode
+}
+
+// BookSkill returns the name of the skill cast by reading the scroll or tome, or an empty string
+func (i *Item) BookSkill() string {
+	record := i.BookRecord()
+
+	switch {
+	case record == nil:
+		return ""
+	case i.IsTome():
+		return record.BookSkill
+	default:
+		return record.ScrollSkill
+	}
+}
+
+// IsTownPortalSkill returns true if the skill with the given name opens a town portal, which is
+// the skill of the scroll and tome of town portal
+func (f *ItemFactory) IsTownPortalSkill(skill string) bool {
+	record := f.BookRecord(townPortalScrollCode)
+	return record != nil && skill != "" && (record.ScrollSkill == skill || record.BookSkill == skill)
+}
+
+// Charges returns the number of remaining charges, for a tome this is the number of scrolls in it
+func (i *Item) Charges() int {
+	return i.attributes.currentStackSize
+}
+
+// MaxCharges returns the maximum number of charges the item can hold
+func (i *Item) MaxCharges() int {
+	if i.IsTome() {
+		return i.attributes.stackSize.max
+	}
+
+	return 1
+}
+
+// UseCharge uses one of the charges of a tome or scroll, returns false if there are none left
+func (i *Item) UseCharge() bool {
+	if i.BookRecord() == nil || i.attributes.currentStackSize < 1 {
+		return false
+	}
+
+	i.attributes.currentStackSize--
+
+	return true
+}
+
+// AddScroll puts the charge of the given scroll into the tome, returns false if the scroll
+// doesn't belong to the tome or the tome is full
+func (i *Item) AddScroll(scroll *Item) bool {
+	record := i.BookRecord()
+	if !i.IsTome() || record.ScrollSpellCode != scroll.CommonCode || scroll.Charges() < 1 {
+		return false
+	}
+
+	if i.Charges() >= i.MaxCharges() {
+		return false
+	}
+
+	scroll.attributes.currentStackSize--
+	i.attributes.currentStackSize++
+
+	return true
+}
+
+// ChargesCost returns the gold value of the charges of a tome or scroll
+func (i *Item) ChargesCost() int {
+	record := i.BookRecord()
+	if record == nil {
+		return 0
+	}
+
+	return record.BaseCost + record.CostPerCharge*i.Charges()
+}
+
+// IdentifyItem identifies the target item using a charge of this scroll or tome of identify,
+// returns false if this isn't an identify book, it has no charges, or the target doesn't
+// need to be identified
+func (i *Item) IdentifyItem(target *Item) bool {
+	if !i.IsIdentifyBook() || target.IsIdentified() || !i.UseCharge() {
+		return false
+	}
+
+	target.Identify()
+
+	return true
+}
+
+// setDropIdentified identifies dropped items without hidden properties, magic and better
+// items drop unidentified
+func (i *Item) setDropIdentified() {
+	i.attributes.identitified = !i.NeedsIdentification()
+}
+
+func (i *Item) getChargesDescription() []string {
+	if !i.IsTome() {
+		return nil
+	}
+
+	str := fmt.Sprintf("%s %d", i.factory.asset.TranslateString(quantity), i.Charges())
+
+	return []string{d2ui.ColorTokenize(str, d2ui.ColorTokenWhite)}
+}
+
+func (i *Item) getUnidentifiedDescription() []string {
+	if i.IsIdentified() {
+		return nil
+	}
+
+	str := i.factory.asset.TranslateString(unidentified)
+
+	return []string{d2ui.ColorTokenize(str, d2ui.ColorTokenRed)}
+}
+
+// initialCharges returns the stack size of a newly created item
+func (i *Item) initialCharges() int {
+	r := i.CommonRecord()
+
+	charges := r.SpawnStack
+	if charges < r.MinStack {
+		charges = r.MinStack
+	}
+
+	if charges < 1 {
+		charges = 1
+	}
+
+	if r.MaxStack > 0 && charges > r.MaxStack {
+		charges = r.MaxStack
+	}
+
+	return charges
+}
@@ -0,0 +1,43 @@
+package diablo2item
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2records"
+)
+
+func TestItemBookCharges(t *testing.T) {
+	asset := &d2asset.AssetManager{}
+	asset.Records = &d2records.RecordManager{}
+	asset.Records.Item.Books = d2records.Books{
+		"ib": &d2records.BookRecord{ScrollSpellCode: identifyScrollCode, BookSpellCode: identifyTomeCode},
+	}
+
+	factory, err := NewItemFactory(asset)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tome := &Item{factory: factory, CommonCode: identifyTomeCode, attributes: &itemAttributes{}}
+	tome.attributes.stackSize.max = 2
+	scroll := &Item{factory: factory, CommonCode: identifyScrollCode, attributes: &itemAttributes{}}
+	scroll.attributes.currentStackSize = 1
+	target := &Item{factory: factory, CommonCode: "buc", attributes: &itemAttributes{}}
+
+	if !tome.IsTome() || scroll.IsTome() {
+		t.Error("expected only the tome to be a tome")
+	}
+
+	if tome.IdentifyItem(target) {
+		t.Error("empty tome should not identify")
+	}
+
+	if !tome.AddScroll(scroll) || tome.Charges() != 1 || scroll.Charges() != 0 {
+		t.Errorf("unexpected charges after adding scroll, tome %d, scroll %d", tome.Charges(), scroll.Charges())
+	}
+
+	if !tome.IdentifyItem(target) || !target.IsIdentified() || tome.Charges() != 0 {
+		t.Error("expected tome charge to identify the item")
+	}
+}
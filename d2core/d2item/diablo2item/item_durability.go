@@ -0,0 +1,184 @@
+package diablo2item
+
+import (
+	"fmt"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2item"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2ui"
+)
+
+// stats which change the durability of an item
+const (
+	statMaxDurability        = "maxdurability"
+	statMaxDurabilityPercent = "item_maxdurability_percent"
+	statReplenishDurability  = "item_replenish_durability"
+)
+
+const (
+	// ethereal items get 50% more defense and damage, and need 10 less strength and dexterity
+	etherealBonusNumerator   = 3
+	etherealBonusDenominator = 2
+	etherealRequirementBonus = 10
+
+	percentDivisor = 100
+
+	// the replenish durability stat repairs 1 durability in 100/value seconds
+	replenishSecondsNumerator = 100.0
+)
+
+// string table keys
+const (
+	indestructibleStringKey = "ModStre9s"   // "Indestructible"
+	etherealStringKey       = "strethereal" // "Ethereal (Cannot be Repaired)"
+)
+
+// static check that Item implements d2item.Durable
+var _ d2item.Durable = &Item{}
+
+// IsEthereal returns true if the item is ethereal
+func (i *Item) IsEthereal() bool {
+	return i.attributes.ethereal
+}
+
+// IsIndestructible returns true if the item can't lose durability
+func (i *Item) IsIndestructible() bool {
+	return i.attributes.indestructable
+}
+
+// newDurability creates the durability of the item, ethereal items have less durability and
+// the durability stats of the item's properties add to it
+func (i *Item) newDurability() d2item.ItemDurability {
+	base := 0
+	if i.attributes.durable {
+		base = i.attributes.durability.max
+	}
+
+	durability := d2item.NewItemDurability(base, i.attributes.ethereal, i.attributes.indestructable)
+	if durability.Max < 1 {
+		return durability
+	}
+
+	durability.Max += durability.Max * i.propertyStatValue(statMaxDurabilityPercent) / percentDivisor
+	durability.Max += i.propertyStatValue(statMaxDurability)
+	durability.Current = durability.Max
+
+	return durability
+}
+
+// MaxDurability returns the maximum durability, including any durability stats
+func (i *Item) MaxDurability() int {
+	return i.attributes.itemDurability.Max
+}
+
+// Durability returns the current and maximum durability of the item
+func (i *Item) Durability() (current, max int) {
+	return i.attributes.itemDurability.Durability()
+}
+
+// HasDurability returns false for items which can never lose durability
+func (i *Item) HasDurability() bool {
+	return i.attributes.itemDurability.HasDurability()
+}
+
+// WearsFrom returns whether the item can lose durability from the given event
+func (i *Item) WearsFrom(event d2item.WearEvent) bool {
+	switch i.GetInventoryItemType() {
+	case d2enum.InventoryItemTypeWeapon:
+		return event == d2item.WearEventAttack
+	case d2enum.InventoryItemTypeArmor:
+		return event == d2item.WearEventStruck || event == d2item.WearEventBlock
+	}
+
+	return false
+}
+
+// Wear removes the given amount of durability, returns true if the item broke
+func (i *Item) Wear(amount int) (broke bool) {
+	return i.attributes.itemDurability.Wear(amount)
+}
+
+// IsBroken returns true if the item has no durability left
+func (i *Item) IsBroken() bool {
+	return i.attributes.itemDurability.IsBroken()
+}
+
+// IsRepairable returns false for ethereal items and items without durability
+func (i *Item) IsRepairable() bool {
+	return i.attributes.itemDurability.IsRepairable()
+}
+
+// Repair restores the durability of the item to the maximum
+func (i *Item) Repair() {
+	i.attributes.itemDurability.Repair()
+}
+
+// RepairCost returns the gold cost of repairing the item at a vendor
+func (i *Item) RepairCost() int {
+	return i.attributes.itemDurability.RepairCost(i.CommonRecord().Cost)
+}
+
+// Advance restores durability over time, for items with the replenish durability stat
+func (i *Item) Advance(elapsed float64) {
+	rate := float64(i.propertyStatValue(statReplenishDurability)) / replenishSecondsNumerator
+	i.attributes.itemDurability.Replenish(elapsed, rate)
+}
+
+func (i *Item) applyEtherealModifiers() {
+	a := i.attributes
+
+	a.defense = a.defense * etherealBonusNumerator / etherealBonusDenominator
+
+	for _, dmg := range []*minMaxEnhanceable{&a.damageOneHand, &a.damageTwoHand, &a.damageMissile} {
+		dmg.min = dmg.min * etherealBonusNumerator / etherealBonusDenominator
+		dmg.max = dmg.max * etherealBonusNumerator / etherealBonusDenominator
+	}
+
+	if a.requiredStrength -= etherealRequirementBonus; a.requiredStrength < 0 {
+		a.requiredStrength = 0
+	}
+
+	if a.requiredDexterity -= etherealRequirementBonus; a.requiredDexterity < 0 {
+		a.requiredDexterity = 0
+	}
+}
+
+// propertyStatValue returns the combined value of all of the item's stats with the given name
+func (i *Item) propertyStatValue(name string) int {
+	total := 0
+
+	for _, stat := range i.propertyStats() {
+		if stat.Name() != name || len(stat.Values()) < 1 {
+			continue
+		}
+
+		total += stat.Values()[0].Int()
+	}
+
+	return total
+}
+
+func (i *Item) getDurabilityDescription() []string {
+	lines := make([]string, 0)
+
+	if i.attributes.indestructable {
+		lines = append(lines, d2ui.ColorTokenize(
+			i.factory.asset.TranslateString(indestructibleStringKey), d2ui.ColorTokenWhite))
+	} else if current, max := i.Durability(); max > 0 {
+		token := d2ui.ColorTokenWhite
+		if current == 0 {
+			token = d2ui.ColorTokenRed
+		}
+
+		str := fmt.Sprintf("%s %v %s %v", i.factory.asset.TranslateString(durability), current,
+			i.factory.asset.TranslateString(of), max)
+		lines = append(lines, d2ui.ColorTokenize(str, token))
+	}
+
+	if i.attributes.ethereal {
+		lines = append(lines, d2ui.ColorTokenize(
+			i.factory.asset.TranslateString(etherealStringKey), d2ui.ColorTokenBlue))
+	}
+
+	return lines
+}
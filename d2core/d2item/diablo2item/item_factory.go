@@ -63,11 +63,12 @@ func NewItemFactory(asset *d2asset.AssetManager) (*ItemFactory, error) {
 
 // ItemFactory is a diablo 2 implementation of an item generator
 type ItemFactory struct {
-	asset  *d2asset.AssetManager
-	stat   *diablo2stats.StatFactory
-	rand   *rand.Rand
-	source rand.Source
-	Seed   int64
+	asset      *d2asset.AssetManager
+	stat       *diablo2stats.StatFactory
+	rand       *rand.Rand
+	source     rand.Source
+	setBonuses map[string][]*SetBonus
+	Seed       int64
 }
 
 // SetSeed sets the item generator seed
@@ -124,6 +125,8 @@ func (f *ItemFactory) NewItem(codes ...string) (*Item, error) {
 
 	if set != "" { // it's a set item
 		item.SetItemCode = set
+		item.SetCode = f.asset.Records.Item.SetItems[set].SetKey
+
 		return item.init(), nil
 	}
 
@@ -265,7 +268,7 @@ func (f *ItemFactory) ItemsFromTreasureClass(tcr *d2records.TreasureClassRecord)
 			itemSlice := f.ItemsFromTreasureClass(record)
 			for itemIdx := range itemSlice {
 				itemSlice[itemIdx].applyDropModifier(f.rollDropModifier(tcr))
-				itemSlice[itemIdx].init()
+				itemSlice[itemIdx].init().setDropIdentified()
 				result = append(result, itemSlice[itemIdx])
 			}
 		} else {
@@ -273,7 +276,7 @@ func (f *ItemFactory) ItemsFromTreasureClass(tcr *d2records.TreasureClassRecord)
 			item := f.ItemFromTreasure(picked)
 			if item != nil {
 				item.applyDropModifier(f.rollDropModifier(tcr))
-				item.init()
+				item.init().setDropIdentified()
 				result = append(result, item)
 			}
 		}
@@ -285,6 +288,7 @@ func (f *ItemFactory) ItemsFromTreasureClass(tcr *d2records.TreasureClassRecord)
 // ItemFromTreasure rolls for a f.rand.m item using the Treasure struct (from d2datadict)
 func (f *ItemFactory) ItemFromTreasure(treasure *d2records.Treasure) *Item {
 	result := &Item{
+		factory: f,
 		// nolint:gosec // we're not concerned with crypto-strong randomness
 		rand: rand.New(rand.NewSource(f.Seed)),
 	}
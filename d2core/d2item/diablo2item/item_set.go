@@ -0,0 +1,253 @@
+package diablo2item
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2item"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2records"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2stats"
+)
+
+const (
+	// the first partial set bonus needs two items of the set to be equipped
+	minSetItemsForBonus = 2
+)
+
+const (
+	// the "add func" of set items without any aprop bonuses, see setitems.txt
+	setItemAddFnNone = 0
+)
+
+// SetBonus is a group of set bonus properties, which become active once
+// enough items of the same set are equipped
+type SetBonus struct {
+	// NumRequired is the number of equipped set items needed for the bonus
+	NumRequired int
+	// Full is true for the bonus which requires the complete set to be equipped
+	Full       bool
+	properties []*Property
+}
+
+// Stats returns the stats of all of the properties of the set bonus
+func (b *SetBonus) Stats() []d2stats.Stat {
+	stats := make([]d2stats.Stat, 0)
+
+	for propIdx := range b.properties {
+		stats = append(stats, b.properties[propIdx].stats...)
+	}
+
+	return stats
+}
+
+// IsActive returns whether the bonus is granted for the given number of equipped
+// set items, where numItemsInSet is the total number of items in the set
+func (b *SetBonus) IsActive(numEquipped, numItemsInSet int) bool {
+	if b.Full {
+		return numEquipped >= numItemsInSet
+	}
+
+	return numEquipped >= b.NumRequired
+}
+
+// SetBonuses returns the partial and full set bonuses (the gold tooltip lines)
+// of the set with the given key. The bonuses are rolled only once per set.
+func (f *ItemFactory) SetBonuses(setKey string) []*SetBonus {
+	if bonuses, found := f.setBonuses[setKey]; found {
+		return bonuses
+	}
+
+	record := f.asset.Records.Item.Sets[setKey]
+	if record == nil {
+		return nil
+	}
+
+	bonuses := make([]*SetBonus, 0)
+
+	partials := [][]*d2records.SetProperty{
+		record.Properties.PartialA,
+		record.Properties.PartialB,
+	}
+
+	for _, partial := range partials {
+		for idx := range partial {
+			bonus := f.newSetBonus(idx+minSetItemsForBonus, false, partial[idx])
+			if bonus != nil {
+				bonuses = append(bonuses, bonus)
+			}
+		}
+	}
+
+	if full := f.newSetBonus(0, true, record.Properties.Full...); full != nil {
+		bonuses = append(bonuses, full)
+	}
+
+	if f.setBonuses == nil {
+		f.setBonuses = make(map[string][]*SetBonus)
+	}
+
+	f.setBonuses[setKey] = bonuses
+
+	return bonuses
+}
+
+// SetItemRecords returns all of the set item records which belong to the given set
+func (f *ItemFactory) SetItemRecords(setKey string) []*d2records.SetItemRecord {
+	result := make([]*d2records.SetItemRecord, 0)
+
+	for key := range f.asset.Records.Item.SetItems {
+		if record := f.asset.Records.Item.SetItems[key]; record.SetKey == setKey {
+			result = append(result, record)
+		}
+	}
+
+	return result
+}
+
+// SetBonusStatList returns the stats granted to the equipper by the set bonuses of
+// all of the equipped set items. This includes the per-item bonuses from setitems.txt,
+// as well as the partial and full set bonuses from sets.txt
+func (f *ItemFactory) SetBonusStatList(equipper d2item.Equipper) d2stats.StatList {
+	stats := make([]d2stats.Stat, 0)
+	setCounts := equippedSetItemCounts(equipper)
+
+	for _, item := range equippedSetItems(equipper) {
+		numEquipped := setCounts[item.SetCode]
+		numInSet := len(f.SetItemRecords(item.SetCode))
+
+		for _, bonus := range item.setItemBonuses {
+			if bonus.IsActive(numEquipped, numInSet) {
+				stats = append(stats, bonus.Stats()...)
+			}
+		}
+	}
+
+	for setKey, numEquipped := range setCounts {
+		numInSet := len(f.SetItemRecords(setKey))
+
+		for _, bonus := range f.SetBonuses(setKey) {
+			if bonus.IsActive(numEquipped, numInSet) {
+				stats = append(stats, bonus.Stats()...)
+			}
+		}
+	}
+
+	return f.stat.NewStatList(stats...)
+}
+
+// EquippedStatList returns the given base stat list, combined with the stats of
+// all equipped items and the active set bonuses of the equipper
+func (f *ItemFactory) EquippedStatList(base d2stats.StatList, equipper d2item.Equipper) d2stats.StatList {
+	result := f.stat.NewStatList()
+
+	if base != nil {
+		result = result.AppendStatList(base)
+	}
+
+	for _, equipped := range equipper.EquippedItems() {
+		if item, ok := equipped.(*Item); ok {
+			result = result.AppendStatList(f.stat.NewStatList(item.propertyStats()...))
+		}
+	}
+
+	return result.AppendStatList(f.SetBonusStatList(equipper)).ReduceStats()
+}
+
+// NumEquippedSetItems returns the number of items of this item's set that are
+// equipped by the item's context. Returns 0 if this is not a set item.
+func (i *Item) NumEquippedSetItems() int {
+	if i.SetCode == "" || i.statContext == nil {
+		return 0
+	}
+
+	return equippedSetItemCounts(i.statContext)[i.SetCode]
+}
+
+// SetItemBonuses returns the per-item set bonuses (the green tooltip lines)
+func (i *Item) SetItemBonuses() []*SetBonus {
+	return i.setItemBonuses
+}
+
+func (i *Item) generateSetItemBonuses() {
+	i.setItemBonuses = nil
+
+	record := i.SetItemRecord()
+	if record == nil || record.AddFn == setItemAddFnNone {
+		return
+	}
+
+	bonuses := make([]*SetBonus, 0)
+
+	// both add functions are handled by the number of equipped set items, as
+	// the set item records do not keep the ordering of the items within a set
+	for idx := range record.SetPropertiesLevel1 {
+		numRequired := idx + minSetItemsForBonus
+		props := []*d2records.SetItemProperty{
+			record.SetPropertiesLevel1[idx],
+			record.SetPropertiesLevel2[idx],
+		}
+
+		if bonus := i.factory.newSetBonus(numRequired, false, props...); bonus != nil {
+			bonuses = append(bonuses, bonus)
+		}
+	}
+
+	i.setItemBonuses = bonuses
+}
+
+func (f *ItemFactory) newSetBonus(numRequired int, full bool, descs ...*d2records.PropertyDescriptor) *SetBonus {
+	props := make([]*Property, 0)
+
+	for _, desc := range descs {
+		if desc == nil || desc.Code == "" {
+			continue
+		}
+
+		paramInt := getNumericComponent(desc.Parameter)
+		if prop := f.NewProperty(desc.Code, paramInt, desc.Min, desc.Max); prop != nil {
+			props = append(props, prop)
+		}
+	}
+
+	if len(props) < 1 {
+		return nil
+	}
+
+	return &SetBonus{
+		NumRequired: numRequired,
+		Full:        full,
+		properties:  props,
+	}
+}
+
+func equippedSetItems(equipper d2item.Equipper) []*Item {
+	result := make([]*Item, 0)
+
+	if equipper == nil {
+		return result
+	}
+
+	for _, equipped := range equipper.EquippedItems() {
+		if item, ok := equipped.(*Item); ok && item.SetCode != "" {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+// equippedSetItemCounts returns a map of set key to the number of distinct
+// items of that set which are equipped
+func equippedSetItemCounts(equipper d2item.Equipper) map[string]int {
+	counts := make(map[string]int)
+	seen := make(map[string]bool)
+
+	for _, item := range equippedSetItems(equipper) {
+		// wearing the same set item twice (like two rings) only counts once
+		if seen[item.SetItemCode] {
+			continue
+		}
+
+		seen[item.SetItemCode] = true
+		counts[item.SetCode]++
+	}
+
+	return counts
+}
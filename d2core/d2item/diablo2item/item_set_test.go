@@ -0,0 +1,131 @@
+package diablo2item
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2item"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2records"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2stats"
+)
+
+type testEquipper struct {
+	equipped []d2item.Item
+}
+
+func (e *testEquipper) EquippedItems() []d2item.Item {
+	return e.equipped
+}
+
+func (e *testEquipper) CarriedItems() []d2item.Item {
+	return nil
+}
+
+func TestSetBonusIsActive(t *testing.T) {
+	tests := []struct {
+		bonus       *SetBonus
+		numEquipped int
+		numInSet    int
+		expect      bool
+	}{
+		{&SetBonus{NumRequired: 2}, 1, 4, false},
+		{&SetBonus{NumRequired: 2}, 2, 4, true},
+		{&SetBonus{NumRequired: 3}, 2, 4, false},
+		{&SetBonus{NumRequired: 3}, 4, 4, true},
+		{&SetBonus{Full: true}, 3, 4, false},
+		{&SetBonus{Full: true}, 4, 4, true},
+	}
+
+	for idx := range tests {
+		test := tests[idx]
+
+		if got := test.bonus.IsActive(test.numEquipped, test.numInSet); got != test.expect {
+			t.Errorf("test %d: unexpected active state, want %v, have %v", idx, test.expect, got)
+		}
+	}
+}
+
+func TestEquippedSetItemCounts(t *testing.T) {
+	equipper := &testEquipper{
+		equipped: []d2item.Item{
+			&Item{SetCode: "Angelic Raiment", SetItemCode: "Angelic Wings"},
+			&Item{SetCode: "Angelic Raiment", SetItemCode: "Angelic Halo"},
+			&Item{SetCode: "Angelic Raiment", SetItemCode: "Angelic Halo"}, // second ring doesn't count
+			&Item{SetCode: "Cleglaw's Brace", SetItemCode: "Cleglaw's Claw"},
+			&Item{CommonCode: "buc"},
+		},
+	}
+
+	counts := equippedSetItemCounts(equipper)
+
+	if counts["Angelic Raiment"] != 2 {
+		t.Errorf("unexpected set item count, want %v, have %v", 2, counts["Angelic Raiment"])
+	}
+
+	if counts["Cleglaw's Brace"] != 1 {
+		t.Errorf("unexpected set item count, want %v, have %v", 1, counts["Cleglaw's Brace"])
+	}
+
+	if len(counts) != 2 {
+		t.Errorf("unexpected number of sets, want %v, have %v", 2, len(counts))
+	}
+}
+
+func statValue(list d2stats.StatList, name string) int {
+	for _, stat := range list.Stats() {
+		if stat.Name() == name {
+			return stat.Values()[0].Int()
+		}
+	}
+
+	return 0
+}
+
+func TestEquippedStatListPartialSet(t *testing.T) {
+	asset := &d2asset.AssetManager{}
+	asset.Records = &d2records.RecordManager{}
+	asset.Records.Item.Stats = itemStatCosts
+	asset.Records.Properties = properties
+
+	set := &d2records.SetRecord{Key: "Test Set"}
+	set.Properties.PartialA = []*d2records.SetProperty{{Code: "allstats", Min: 5, Max: 5}}
+	set.Properties.Full = []*d2records.SetProperty{{Code: "allstats", Min: 20, Max: 20}}
+
+	asset.Records.Item.Sets = map[string]*d2records.SetRecord{"Test Set": set}
+	asset.Records.Item.SetItems = map[string]*d2records.SetItemRecord{
+		"Test Helm":   {SetItemKey: "Test Helm", SetKey: "Test Set"},
+		"Test Armor":  {SetItemKey: "Test Armor", SetKey: "Test Set"},
+		"Test Gloves": {SetItemKey: "Test Gloves", SetKey: "Test Set"},
+	}
+
+	factory, err := NewItemFactory(asset)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := factory.stat.NewStatList(factory.stat.NewStat("strength", 10))
+	equipper := &testEquipper{equipped: []d2item.Item{
+		&Item{SetCode: "Test Set", SetItemCode: "Test Helm"},
+	}}
+
+	if strength := statValue(factory.EquippedStatList(base, equipper), "strength"); strength != 10 {
+		t.Errorf("one set item: want strength 10, have %d", strength)
+	}
+
+	equipper.equipped = append(equipper.equipped, &Item{SetCode: "Test Set", SetItemCode: "Test Armor"})
+
+	stats := factory.EquippedStatList(base, equipper)
+	if strength := statValue(stats, "strength"); strength != 15 {
+		t.Errorf("partial set: want strength 15, have %d", strength)
+	}
+
+	if energy := statValue(stats, "energy"); energy != 5 {
+		t.Errorf("partial set: want energy 5, have %d", energy)
+	}
+
+	equipper.equipped = append(equipper.equipped, &Item{SetCode: "Test Set", SetItemCode: "Test Gloves"})
+
+	if strength := statValue(factory.EquippedStatList(base, equipper), "strength"); strength != 35 {
+		t.Errorf("full set: want strength 35, have %d", strength)
+	}
+}
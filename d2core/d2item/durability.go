@@ -0,0 +1,64 @@
+package d2item
+
+import "math/rand"
+
+// chances (in percent) for an equipped item to lose a point of durability
+const (
+	wearChanceAttack = 4
+	wearChanceStruck = 10
+	wearChanceBlock  = 10
+	wearChanceMax    = 100
+)
+
+// WearEvent is a combat event which can cause an equipped item to lose durability
+type WearEvent int
+
+// Wear events
+const (
+	WearEventAttack WearEvent = iota // the wielder hit something with a weapon
+	WearEventStruck                  // the wearer was hit by something
+	WearEventBlock                   // the wearer blocked an attack with a shield
+)
+
+// Durable is an item which can lose durability, break, and be repaired
+type Durable interface {
+	// Durability returns the current and maximum durability of the item
+	Durability() (current, max int)
+	// HasDurability returns false for items which can never lose durability
+	HasDurability() bool
+	// WearsFrom returns whether the item can lose durability from the given event
+	WearsFrom(event WearEvent) bool
+	// Wear removes the given amount of durability, returns true if the item broke
+	Wear(amount int) (broke bool)
+	// IsBroken returns true if the item has no durability left
+	IsBroken() bool
+	// IsRepairable returns false for items which can't be repaired, like ethereal items
+	IsRepairable() bool
+	// Repair restores the durability of the item to the maximum
+	Repair()
+}
+
+// RollWear rolls for the loss of a point of durability for the given event,
+// returns true if the item broke
+func RollWear(item Durable, event WearEvent, r *rand.Rand) (broke bool) {
+	if !item.HasDurability() || !item.WearsFrom(event) {
+		return false
+	}
+
+	chance := 0
+
+	switch event {
+	case WearEventAttack:
+		chance = wearChanceAttack
+	case WearEventStruck:
+		chance = wearChanceStruck
+	case WearEventBlock:
+		chance = wearChanceBlock
+	}
+
+	if r.Intn(wearChanceMax) >= chance {
+		return false
+	}
+
+	return item.Wear(1)
+}
@@ -0,0 +1,117 @@
+package d2item
+
+const (
+	// ethereal items have half of the base durability, plus one
+	etherealDurabilityDivisor = 2
+	etherealDurabilityBonus   = 1
+)
+
+// static check that ItemDurability implements Durable
+var _ Durable = &ItemDurability{}
+
+// ItemDurability is the serializable durability state of an item
+type ItemDurability struct {
+	Current        int  `json:"current"`
+	Max            int  `json:"max"`
+	Ethereal       bool `json:"ethereal"`
+	Indestructible bool `json:"indestructible"`
+	// replenished accumulates the partial durability restored by replenish-durability stats
+	replenished float64
+}
+
+// NewItemDurability creates the durability state of a new item with the given base durability
+func NewItemDurability(base int, ethereal, indestructible bool) ItemDurability {
+	max := base
+
+	if ethereal && max > 0 {
+		max = max/etherealDurabilityDivisor + etherealDurabilityBonus
+	}
+
+	return ItemDurability{
+		Current:        max,
+		Max:            max,
+		Ethereal:       ethereal,
+		Indestructible: indestructible,
+	}
+}
+
+// Durability returns the current and maximum durability
+func (d *ItemDurability) Durability() (current, max int) {
+	return d.Current, d.Max
+}
+
+// HasDurability returns false if the item can't lose durability
+func (d *ItemDurability) HasDurability() bool {
+	return d != nil && d.Max > 0 && !d.Indestructible
+}
+
+// WearsFrom returns whether the item can lose durability from the given event,
+// the item types which embed the durability state decide this
+func (d *ItemDurability) WearsFrom(_ WearEvent) bool {
+	return false
+}
+
+// Wear removes durability from the item, returns true if the item broke
+func (d *ItemDurability) Wear(amount int) (broke bool) {
+	if !d.HasDurability() || d.Current <= 0 {
+		return false
+	}
+
+	d.Current -= amount
+
+	if d.Current <= 0 {
+		d.Current = 0
+		return true
+	}
+
+	return false
+}
+
+// IsBroken returns true if the item has no durability left
+func (d *ItemDurability) IsBroken() bool {
+	return d.HasDurability() && d.Current <= 0
+}
+
+// IsRepairable returns false for ethereal items and items without durability
+func (d *ItemDurability) IsRepairable() bool {
+	return d.HasDurability() && !d.Ethereal
+}
+
+// Repair restores the durability to the maximum, if the item can be repaired
+func (d *ItemDurability) Repair() {
+	if d.IsRepairable() {
+		d.Current = d.Max
+	}
+}
+
+// Replenish restores durability over time. The rate is the amount of durability
+// restored per second, which comes from the replenish-durability stats
+func (d *ItemDurability) Replenish(elapsed, rate float64) {
+	if !d.HasDurability() || rate <= 0 || d.Current >= d.Max {
+		d.replenished = 0
+		return
+	}
+
+	d.replenished += elapsed * rate
+
+	for d.replenished >= 1 && d.Current < d.Max {
+		d.replenished--
+		d.Current++
+	}
+}
+
+// RepairCost returns the gold cost to repair the item, given the base cost of the item
+func (d *ItemDurability) RepairCost(baseCost int) int {
+	if !d.IsRepairable() || d.Current >= d.Max {
+		return 0
+	}
+
+	missing := d.Max - d.Current
+	cost := baseCost * missing / d.Max
+
+	if cost < 1 {
+		cost = 1
+	}
+
+	return cost
+}
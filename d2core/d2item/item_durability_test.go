@@ -0,0 +1,79 @@
+package d2item
+
+import (
+	"testing"
+)
+
+func TestNewItemDurability(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     int
+		ethereal bool
+		wantMax  int
+	}{
+		{"normal", 30, false, 30},
+		{"ethereal", 30, true, 16},
+		{"no durability", 0, true, 0},
+	}
+
+	for _, test := range tests {
+		durability := NewItemDurability(test.base, test.ethereal, false)
+
+		if current, max := durability.Durability(); current != test.wantMax || max != test.wantMax {
+			t.Errorf("%s: want %d/%d, have %d/%d", test.name, test.wantMax, test.wantMax, current, max)
+		}
+	}
+}
+
+func TestItemDurabilityWearRepair(t *testing.T) {
+	durability := NewItemDurability(2, false, false)
+
+	if durability.Wear(1) || durability.IsBroken() {
+		t.Fatal("the item should not break with durability left")
+	}
+
+	if cost := durability.RepairCost(100); cost != 50 {
+		t.Errorf("want a repair cost of 50 for half of the durability, have %d", cost)
+	}
+
+	if !durability.Wear(1) || !durability.IsBroken() {
+		t.Fatal("the item should break without durability left")
+	}
+
+	durability.Repair()
+
+	if current, max := durability.Durability(); current != max || durability.RepairCost(100) != 0 {
+		t.Errorf("want a repaired item, have %d/%d", current, max)
+	}
+
+	ethereal := NewItemDurability(10, true, false)
+	ethereal.Wear(1)
+	ethereal.Repair()
+
+	if ethereal.IsRepairable() || ethereal.Current == ethereal.Max || ethereal.RepairCost(100) != 0 {
+		t.Errorf("an ethereal item should not be repaired, have %+v", ethereal)
+	}
+
+	indestructible := NewItemDurability(10, false, true)
+
+	if indestructible.Wear(10) || indestructible.Current != 10 {
+		t.Errorf("an indestructible item should not lose durability, have %+v", indestructible)
+	}
+}
+
+func TestItemDurabilityReplenish(t *testing.T) {
+	durability := NewItemDurability(10, false, false)
+	durability.Wear(3)
+
+	durability.Replenish(1.5, 1)
+
+	if durability.Current != 8 {
+		t.Errorf("want 1 durability replenished, have %d", durability.Current)
+	}
+
+	durability.Replenish(10, 1)
+
+	if durability.Current != durability.Max {
+		t.Errorf("want the durability replenished up to the maximum, have %d", durability.Current)
+	}
+}
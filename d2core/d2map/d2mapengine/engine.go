@@ -34,6 +34,8 @@ type MapEngine struct {
 	startSubTileX int                       // Starting X position
 	startSubTileY int                       // Starting Y position
 	dt1Files      []string                  // List of DS1 strings
+	// the sub-tiles blocked by the doors, by object id
+	doorCollisions map[string][]*d2dt1.SubTileFlags
 
 	// https://github.com/OpenDiablo2/OpenDiablo2/issues/789
 	IsLoading bool // (temp) Whether we have processed the GenerateMapPacket(only for remote client)
@@ -54,6 +56,7 @@ func CreateMapEngine(l d2util.LogLevel, asset *d2asset.AssetManager) *MapEngine
 		asset:            asset,
 		MapEntityFactory: entity,
 		StampFactory:     stamp,
+		entities:         make(map[string]d2interface.MapEntity),
 		// This will be set to true when we are using a remote client connection, and then set to false after we process the GenerateMapPacket
 		IsLoading: false,
 	}
@@ -73,17 +76,79 @@ func (m *MapEngine) GetStartingPosition() (x, y int) {
 // ResetMap clears all map and entity data and reloads it from the cached files.
 func (m *MapEngine) ResetMap(levelType d2enum.RegionIdType, width, height int) {
 	m.entities = make(map[string]d2interface.MapEntity)
+	m.doorCollisions = make(map[string][]*d2dt1.SubTileFlags)
 	m.levelType = *m.asset.Records.Level.Types[levelType]
 	m.size = d2geom.Size{Width: width, Height: height}
 	m.tiles = make([]MapTile, width*height)
 	m.dt1TileData = make([]d2dt1.Tile, 0)
 	m.dt1Files = make([]string, 0)
 
+	// the DT1 files are loaded in parallel, then their tiles are added in order. Load errors are
+	// logged by addDT1.
+	dt1Files := make([]string, 0, len(m.levelType.Files))
+
+	for _, fileName := range m.levelType.Files {
+		if fileName != "" && fileName != "0" {
+			dt1Files = append(dt1Files, strings.ToLower(fileName))
+		}
+	}
+
+	_ = m.asset.Prefetch(dt1Files...).Wait()
+
 	for idx := range m.levelType.Files {
 		m.addDT1(m.levelType.Files[idx])
 	}
 }
 
+// PrefetchLevel loads the tiles, presets and monster animations of a level in the background,
+// for example when the player is about to enter it. It doesn't block, load errors are logged by
+// the asset manager.
+func (m *MapEngine) PrefetchLevel(levelID int, difficulty d2enum.DifficultyType) {
+	level, found := m.asset.Records.Level.Details[levelID]
+	if !found {
+		return
+	}
+
+	var paths []string
+
+	if level.LevelType >= 0 && level.LevelType < len(m.asset.Records.Level.Types) {
+		for _, fileName := range m.asset.Records.Level.Types[level.LevelType].Files {
+			if fileName != "" && fileName != "0" {
+				paths = append(paths, strings.ToLower(fileName))
+			}
+		}
+	}
+
+	for _, preset := range m.asset.Records.Level.Presets {
+		if preset.LevelID != levelID {
+			continue
+		}
+
+		for _, fileName := range preset.Files {
+			if fileName != "" && fileName != "0" {
+				paths = append(paths, fileName)
+			}
+		}
+	}
+
+	m.asset.Prefetch(paths...)
+
+	for _, monsterID := range level.MonsterIDs(difficulty) {
+		monstat := m.asset.Records.Monster.Stats[monsterID]
+		if monstat == nil {
+			continue
+		}
+
+		monstatEx := m.asset.Records.Monster.Stats2[monstat.ExtraDataKey]
+		if monstatEx == nil {
+			continue
+		}
+
+		m.asset.PrefetchComposite(d2enum.ObjectTypeCharacter, monstat.AnimationDirectoryToken,
+			d2enum.MonsterAnimationModeNeutral.String(), monstatEx.BaseWeaponClass, &monstatEx.EquipmentOptions)
+	}
+}
+
 func (m *MapEngine) addDT1(fileName string) {
 	if fileName == "" || fileName == "0" {
 		return
@@ -191,9 +256,55 @@ func (m *MapEngine) PlaceStamp(stamp *d2mapstamp.Stamp, tileOffsetX, tileOffsetY
 	for idx := range stampEntities {
 		e := stampEntities[idx]
 		m.entities[e.ID()] = e
+
+		if object, ok := e.(*d2mapentity.Object); ok {
+			m.UpdateObjectCollision(object)
+		}
 	}
 }
 
+// UpdateObjectCollision blocks walking on the sub-tiles under a closed door, and unblocks
+// them once the door is opened. Only the sub-tiles which the door blocked are unblocked.
+func (m *MapEngine) UpdateObjectCollision(object *d2mapentity.Object) {
+	if object.Behavior() != d2mapentity.ObjectBehaviorDoor {
+		return
+	}
+
+	for _, flags := range m.doorCollisions[object.ID()] {
+		flags.BlockWalk = false
+	}
+
+	delete(m.doorCollisions, object.ID())
+
+	record := object.Record()
+	if object.IsOperated() || !record.HasCollision[d2enum.ObjectAnimationModeNeutral] {
+		return
+	}
+
+	blocked := make([]*d2dt1.SubTileFlags, 0)
+	position := object.GetPosition()
+	startX := int(position.X()) - (record.SizeX-1)/2
+	startY := int(position.Y()) - (record.SizeY-1)/2
+
+	for subY := startY; subY < startY+record.SizeY; subY++ {
+		for subX := startX; subX < startX+record.SizeX; subX++ {
+			if subX < 0 || subY < 0 || m.TileAt(subX/subtilesPerTile, subY/subtilesPerTile) == nil {
+				continue
+			}
+
+			flags := m.SubTileAt(subX, subY)
+			if flags.BlockWalk {
+				continue
+			}
+
+			flags.BlockWalk = true
+			blocked = append(blocked, flags)
+		}
+	}
+
+	m.doorCollisions[object.ID()] = blocked
+}
+
 // converts x,y tile coordinate into index in MapEngine.tiles
 func (m *MapEngine) tileCoordinateToIndex(x, y int) int {
 	return x + (y * m.size.Width)
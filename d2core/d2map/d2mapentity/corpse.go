@@ -0,0 +1,110 @@
+package d2mapentity
+
+import (
+	"fmt"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2math/d2vector"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2inventory"
+)
+
+// Corpse is the body a player leaves where they died, wearing the items the player had equipped
+type Corpse struct {
+	mapEntity
+	composite *d2asset.Composite
+	// Owner is the id of the player who died
+	Owner string
+	name  string
+}
+
+// NewCorpse creates the corpse of a player at the given sub-tile position, the id is given by
+// the server and the label is the name of the owner
+func (f *MapEntityFactory) NewCorpse(id, owner, label string, heroType d2enum.Hero, x, y int,
+	equipment *d2inventory.CharacterEquipment) (*Corpse, error) {
+	composite, err := f.asset.LoadComposite(d2enum.ObjectTypePlayer, heroType.GetToken(),
+		d2resource.PaletteUnits)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := composite.SetMode(d2enum.PlayerAnimationModeDead, equipment.RightHand.GetWeaponClass()); err != nil {
+		return nil, err
+	}
+
+	if err := composite.Equip(compositeEquipment(equipment)); err != nil {
+		return nil, err
+	}
+
+	result := &Corpse{
+		mapEntity: newMapEntity(x, y),
+		composite: composite,
+		Owner:     owner,
+		name:      label,
+	}
+
+	result.mapEntity.uuid = id
+
+	return result, nil
+}
+
+// ID returns the corpse uuid
+func (c *Corpse) ID() string {
+	return c.mapEntity.uuid
+}
+
+// Advance is called once per frame and processes a single game tick.
+func (c *Corpse) Advance(tickTime float64) {
+	if err := c.composite.Advance(tickTime); err != nil {
+		fmt.Printf("failed to advance composite animation of corpse: %s, err: %v\n", c.ID(), err)
+	}
+}
+
+// Render renders the animated composite for this entity.
+func (c *Corpse) Render(target d2interface.Surface) {
+	renderOffset := c.Position.RenderOffset()
+	target.PushTranslation(
+		int((renderOffset.X()-renderOffset.Y())*subtileWidth),
+		int(((renderOffset.X()+renderOffset.Y())*subtileHeight)+subtileOffsetY),
+	)
+
+	defer target.Pop()
+
+	if err := c.composite.Render(target); err != nil {
+		fmt.Printf("failed to render the composite of corpse: %s, err: %v\n", c.ID(), err)
+	}
+}
+
+// SetEquipment changes the items worn by the corpse
+func (c *Corpse) SetEquipment(equipment *d2inventory.CharacterEquipment) {
+	if err := c.composite.Equip(compositeEquipment(equipment)); err != nil {
+		fmt.Printf("failed to equip corpse: %s, err: %v\n", c.ID(), err)
+	}
+}
+
+// Selectable returns true, the owner can always take the items back
+func (c *Corpse) Selectable() bool {
+	return true
+}
+
+// Label returns the name of the owner
+func (c *Corpse) Label() string {
+	return c.name
+}
+
+// GetPosition returns the corpse's position
+func (c *Corpse) GetPosition() d2vector.Position {
+	return c.mapEntity.Position
+}
+
+// GetVelocity returns the corpse's velocity vector
+func (c *Corpse) GetVelocity() d2vector.Vector {
+	return c.mapEntity.velocity
+}
+
+// GetSize returns the current frame size
+func (c *Corpse) GetSize() (width, height int) {
+	return c.composite.GetSize()
+}
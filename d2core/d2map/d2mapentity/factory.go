@@ -10,14 +10,18 @@ import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2math/d2vector"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2automap"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2hero"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2inventory"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2item/diablo2item"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2records"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2states"
 )
 
 const (
-	subtilesPerTile       = 5
+	subtilesPerTile = 5
+	// the first two color maps of a palshift.dat file aren't used by the monster sub-types
+	paletteShiftOffset    = 2
 	retailFps             = 25.0
 	millisecondsPerSecond = 1000.0
 )
@@ -38,6 +42,7 @@ func NewMapEntityFactory(asset *d2asset.AssetManager) (*MapEntityFactory, error)
 		stateFactory,
 		asset,
 		itemFactory,
+		d2enum.DifficultyNormal,
 	}
 
 	return entityFactory, nil
@@ -46,8 +51,14 @@ func NewMapEntityFactory(asset *d2asset.AssetManager) (*MapEntityFactory, error)
 // MapEntityFactory creates map entities for the MapEngine
 type MapEntityFactory struct {
 	*d2hero.HeroStateFactory
-	asset *d2asset.AssetManager
-	item  *diablo2item.ItemFactory
+	asset      *d2asset.AssetManager
+	item       *diablo2item.ItemFactory
+	difficulty d2enum.DifficultyType
+}
+
+// SetDifficulty sets the difficulty of the game, which selects the colors of the monsters
+func (f *MapEntityFactory) SetDifficulty(difficulty d2enum.DifficultyType) {
+	f.difficulty = difficulty
 }
 
 // NewAnimatedEntity creates an instance of AnimatedEntity
@@ -64,18 +75,7 @@ func NewAnimatedEntity(x, y int, animation d2interface.Animation) *AnimatedEntit
 // NewPlayer creates a new player entity and returns a pointer to it.
 func (f *MapEntityFactory) NewPlayer(id, name string, x, y, direction int, heroType d2enum.Hero,
 	stats *d2hero.HeroStatsState, skills map[int]*d2hero.HeroSkill, equipment *d2inventory.CharacterEquipment,
-	leftSkill, rightSkill, gold int) *Player {
-	layerEquipment := &[d2enum.CompositeTypeMax]string{
-		d2enum.CompositeTypeHead:      equipment.Head.GetArmorClass(),
-		d2enum.CompositeTypeTorso:     equipment.Torso.GetArmorClass(),
-		d2enum.CompositeTypeLegs:      equipment.Legs.GetArmorClass(),
-		d2enum.CompositeTypeRightArm:  equipment.RightArm.GetArmorClass(),
-		d2enum.CompositeTypeLeftArm:   equipment.LeftArm.GetArmorClass(),
-		d2enum.CompositeTypeRightHand: equipment.RightHand.GetItemCode(),
-		d2enum.CompositeTypeLeftHand:  equipment.LeftHand.GetItemCode(),
-		d2enum.CompositeTypeShield:    equipment.Shield.GetItemCode(),
-	}
-
+	belt *d2inventory.Belt, leftSkill, rightSkill, gold int) *Player {
 	composite, err := f.asset.LoadComposite(d2enum.ObjectTypePlayer, heroType.GetToken(),
 		d2resource.PaletteUnits)
 	if err != nil {
@@ -87,10 +87,15 @@ func (f *MapEntityFactory) NewPlayer(id, name string, x, y, direction int, heroT
 
 	heroState, _ := f.CreateHeroState(name, heroType, stats)
 
+	if belt == nil {
+		belt = d2inventory.NewBelt(f.BeltRows(equipment))
+	}
+
 	result := &Player{
 		mapEntity:  newMapEntity(x, y),
 		composite:  composite,
 		Equipment:  equipment,
+		Belt:       belt,
 		Stats:      heroState.Stats,
 		Skills:     heroState.Skills,
 		LeftSkill:  heroState.Skills[leftSkill],
@@ -103,6 +108,8 @@ func (f *MapEntityFactory) NewPlayer(id, name string, x, y, direction int, heroT
 		isRunning:    false,
 		Gold:         gold,
 		Act:          1,
+		States:       d2states.NewList(f.asset.Records.States),
+		Automap:      d2automap.NewState(),
 	}
 
 	result.mapEntity.uuid = id
@@ -116,13 +123,27 @@ func (f *MapEntityFactory) NewPlayer(id, name string, x, y, direction int, heroT
 
 	composite.SetDirection(direction)
 
-	if err := composite.Equip(layerEquipment); err != nil {
+	if err := composite.Equip(compositeEquipment(equipment)); err != nil {
 		fmt.Printf("failed to equip, err: %v\n", err)
 	}
 
 	return result
 }
 
+// compositeEquipment returns the composite layers of the equipment
+func compositeEquipment(equipment *d2inventory.CharacterEquipment) *[d2enum.CompositeTypeMax]string {
+	return &[d2enum.CompositeTypeMax]string{
+		d2enum.CompositeTypeHead:      equipment.Head.GetArmorClass(),
+		d2enum.CompositeTypeTorso:     equipment.Torso.GetArmorClass(),
+		d2enum.CompositeTypeLegs:      equipment.Legs.GetArmorClass(),
+		d2enum.CompositeTypeRightArm:  equipment.RightArm.GetArmorClass(),
+		d2enum.CompositeTypeLeftArm:   equipment.LeftArm.GetArmorClass(),
+		d2enum.CompositeTypeRightHand: equipment.RightHand.GetItemCode(),
+		d2enum.CompositeTypeLeftHand:  equipment.LeftHand.GetItemCode(),
+		d2enum.CompositeTypeShield:    equipment.Shield.GetItemCode(),
+	}
+}
+
 // NewMissile creates a new Missile and initializes it's animation.
 func (f *MapEntityFactory) NewMissile(x, y int, record *d2records.MissileRecord) (*Missile, error) {
 	animation, err := f.asset.LoadAnimation(
@@ -151,8 +172,8 @@ func (f *MapEntityFactory) NewMissile(x, y int, record *d2records.MissileRecord)
 	return result, nil
 }
 
-// NewItem creates an item map entity
-func (f *MapEntityFactory) NewItem(x, y int, codes ...string) (*Item, error) {
+// NewItem creates an item map entity, a random ID is given to the item when the ID is empty
+func (f *MapEntityFactory) NewItem(id string, x, y int, codes ...string) (*Item, error) {
 	item, err := f.item.NewItem(codes...)
 
 	if err != nil {
@@ -171,6 +192,10 @@ func (f *MapEntityFactory) NewItem(x, y int, codes ...string) (*Item, error) {
 	animation.SetPlayLoop(false)
 	entity := NewAnimatedEntity(x*subtilesPerTile, y*subtilesPerTile, animation)
 
+	if id != "" {
+		entity.uuid = id
+	}
+
 	result := &Item{
 		AnimatedEntity: entity,
 		Item:           item,
@@ -189,6 +214,9 @@ func (f *MapEntityFactory) NewNPC(x, y int, monstat *d2records.MonStatRecord, di
 		monstatEx:     f.asset.Records.Monster.Stats2[monstat.ExtraDataKey],
 	}
 
+	// the server and the clients generate the same map, the ids tell them which monster is meant
+	result.mapEntity.uuid = uuid.NewSHA1(uuid.NameSpaceOID, []byte(fmt.Sprintf("npc/%s/%d/%d", monstat.Key, x, y))).String()
+
 	var equipment [16]string
 
 	for compType, opts := range result.monstatEx.EquipmentOptions {
@@ -204,6 +232,10 @@ func (f *MapEntityFactory) NewNPC(x, y int, monstat *d2records.MonStatRecord, di
 
 	result.composite = composite
 
+	if palette := result.monstatEx.Palette(f.difficulty); palette > 0 {
+		composite.SetPaletteShift(palette + paletteShiftOffset)
+	}
+
 	if err := composite.SetMode(d2enum.MonsterAnimationModeNeutral,
 		result.monstatEx.BaseWeaponClass); err != nil {
 		return nil, err
@@ -218,34 +250,81 @@ func (f *MapEntityFactory) NewNPC(x, y int, monstat *d2records.MonStatRecord, di
 
 	result.composite.SetDirection(direction)
 
-	if result.monstatRecord != nil && result.monstatRecord.IsInteractable {
+	if result.monstatRecord.IsInteractable || result.IsHostile() {
 		result.name = f.asset.TranslateString(result.monstatRecord.NameString)
 	}
 
 	return result, nil
 }
 
-// NewCastOverlay creates a cast overlay map entity
-func (f *MapEntityFactory) NewCastOverlay(x, y int, overlayRecord *d2records.OverlayRecord) (*CastOverlay, error) {
-	animation, err := f.asset.LoadAnimationWithEffect(
-		fmt.Sprintf("/data/Global/Overlays/%s.dcc", overlayRecord.Filename),
-		d2resource.PaletteUnits,
-		d2enum.DrawEffectModulate,
-	)
+// NewHireling creates a hireling map entity for the given hireling state, following the owner
+func (f *MapEntityFactory) NewHireling(x, y int, state *d2hero.HirelingState, owner *Player) (*Hireling, error) {
+	record := f.HirelingRecord(state)
+	if record == nil {
+		return nil, fmt.Errorf("unknown hireling: %d", state.ID)
+	}
+
+	monstat := f.asset.Records.GetMonStatByIndex(record.Class)
+	if monstat == nil {
+		return nil, fmt.Errorf("unknown hireling class: %d", record.Class)
+	}
+
+	result := &Hireling{
+		mapEntity:     newMapEntity(x, y),
+		State:         state,
+		monstatRecord: monstat,
+		monstatEx:     f.asset.Records.Monster.Stats2[monstat.ExtraDataKey],
+		owner:         owner,
+		attackRange:   hirelingMeleeDistance,
+	}
+
+	if result.monstatEx == nil {
+		return nil, fmt.Errorf("unknown hireling monstats2 record: %s", monstat.ExtraDataKey)
+	}
+
+	if owner != nil {
+		result.uuid = d2hero.HirelingID(owner.ID())
+	}
+
+	var equipment [16]string
+
+	for compType, opts := range result.monstatEx.EquipmentOptions {
+		equipment[compType] = selectEquip(opts)
+	}
 
+	composite, err := f.asset.LoadComposite(d2enum.ObjectTypeCharacter, monstat.AnimationDirectoryToken,
+		d2resource.PaletteUnits)
 	if err != nil {
 		return nil, err
 	}
 
-	animation.Rewind()
-	animation.ResetPlayedCount()
+	result.composite = composite
 
-	animationSpeed := float64(overlayRecord.AnimRate*retailFps) / millisecondsPerSecond
+	if err := composite.SetMode(d2enum.MonsterAnimationModeNeutral,
+		result.monstatEx.BaseWeaponClass); err != nil {
+		return nil, err
+	}
+
+	if err := composite.Equip(&equipment); err != nil {
+		return nil, err
+	}
+
+	// hirelings run to keep up with their owner
+	result.SetSpeed(baseRunSpeed)
+	result.mapEntity.directioner = result.rotate
+	result.name = f.asset.TranslateString(state.Name)
+
+	return result, nil
+}
+
+// NewCastOverlay creates a cast overlay map entity
+func (f *MapEntityFactory) NewCastOverlay(x, y int, overlayRecord *d2records.OverlayRecord) (*CastOverlay, error) {
 	playLoop := false // https://github.com/OpenDiablo2/OpenDiablo2/issues/804
 
-	animation.SetPlayLength(animationSpeed)
-	animation.SetPlayLoop(playLoop)
-	animation.PlayForward()
+	animation, err := f.loadOverlayAnimation(overlayRecord, playLoop)
+	if err != nil {
+		return nil, err
+	}
 
 	targetX := x + overlayRecord.XOffset
 	targetY := y + overlayRecord.YOffset
@@ -261,12 +340,43 @@ func (f *MapEntityFactory) NewCastOverlay(x, y int, overlayRecord *d2records.Ove
 	return result, nil
 }
 
+// NewStateOverlay creates the looping animation shown on an entity while one of its states is active
+func (f *MapEntityFactory) NewStateOverlay(overlayRecord *d2records.OverlayRecord) (d2interface.Animation, error) {
+	return f.loadOverlayAnimation(overlayRecord, true)
+}
+
+func (f *MapEntityFactory) loadOverlayAnimation(overlayRecord *d2records.OverlayRecord,
+	playLoop bool) (d2interface.Animation, error) {
+	animation, err := f.asset.LoadAnimationWithEffect(
+		fmt.Sprintf("/data/Global/Overlays/%s.dcc", overlayRecord.Filename),
+		d2resource.PaletteUnits,
+		d2enum.DrawEffectModulate,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	animation.Rewind()
+	animation.ResetPlayedCount()
+
+	animationSpeed := float64(overlayRecord.AnimRate*retailFps) / millisecondsPerSecond
+
+	animation.SetPlayLength(animationSpeed)
+	animation.SetPlayLoop(playLoop)
+	animation.PlayForward()
+
+	return animation, nil
+}
+
 // NewObject creates an instance of AnimatedComposite
 func (f *MapEntityFactory) NewObject(x, y int, objectRec *d2records.ObjectDetailRecord,
 	palettePath string) (*Object, error) {
 	locX, locY := float64(x), float64(y)
 	entity := &Object{
-		uuid:         uuid.New().String(),
+		// the id is derived from the placement, so that the server and the clients, which generate
+		// the same map from the seed, agree on the ids of the objects
+		uuid:         uuid.NewSHA1(uuid.NameSpaceOID, []byte(fmt.Sprintf("object/%d/%d/%d", objectRec.Index, x, y))).String(),
 		objectRecord: objectRec,
 		Position:     d2vector.NewPosition(locX, locY),
 		name:         f.asset.TranslateString(objectRec.Name),
@@ -0,0 +1,226 @@
+package d2mapentity
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2math/d2vector"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2hero"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2records"
+)
+
+// distances are in subtiles
+const (
+	// the hireling walks back to its owner when it is further away than this
+	hirelingFollowDistance = 15
+	// the hireling stops this close to its owner
+	hirelingStopDistance = 5
+	// the hireling attacks monsters within this distance of itself
+	hirelingAggroDistance = 20
+	// the hireling can hit monsters within this distance, ranged hirelings use their missile range instead
+	hirelingMeleeDistance = 3
+)
+
+// Hireling is a mercenary map entity, it follows its owner and walks up to nearby hostile
+// monsters. The server rolls its attacks, see Attack.
+type Hireling struct {
+	mapEntity
+	State         *d2hero.HirelingState
+	name          string
+	composite     *d2asset.Composite
+	monstatRecord *d2records.MonStatRecord
+	monstatEx     *d2records.MonStat2Record
+	owner         *Player
+	target        d2interface.MapEntity
+	findTargets   func() map[string]d2interface.MapEntity
+	attackRange   float64
+	isAttacking   bool
+	isDying       bool
+}
+
+// ID returns the hireling's uuid
+func (h *Hireling) ID() string {
+	return h.mapEntity.uuid
+}
+
+// Owner returns the player who hired the hireling
+func (h *Hireling) Owner() *Player {
+	return h.owner
+}
+
+// SetTargetSource sets the function used to find the entities the hireling can attack
+func (h *Hireling) SetTargetSource(findTargets func() map[string]d2interface.MapEntity) {
+	h.findTargets = findTargets
+}
+
+// Render renders this entity's animated composite.
+func (h *Hireling) Render(target d2interface.Surface) {
+	renderOffset := h.Position.RenderOffset()
+	target.PushTranslation(
+		int((renderOffset.X()-renderOffset.Y())*magicOffsetScalarY),
+		int(((renderOffset.X()+renderOffset.Y())*magicOffsetScalarX)-magicOffsetX),
+	)
+
+	defer target.Pop()
+
+	if h.composite.Render(target) != nil {
+		return
+	}
+}
+
+// Advance is called once per frame and processes a single game tick. The hireling
+// follows its owner, and walks up to the nearest hostile monster.
+func (h *Hireling) Advance(tickTime float64) {
+	h.Step(tickTime)
+
+	if err := h.composite.Advance(tickTime); err != nil {
+		return
+	}
+
+	if h.isDying {
+		if h.composite.GetPlayedCount() >= 1 {
+			h.isDying = false
+			h.setMode(d2enum.MonsterAnimationModeDead)
+		}
+
+		return
+	}
+
+	if h.State.IsDead {
+		return
+	}
+
+	if h.isAttacking {
+		if h.composite.GetPlayedCount() < 1 {
+			return
+		}
+
+		h.isAttacking = false
+		h.setMode(d2enum.MonsterAnimationModeNeutral)
+	}
+
+	if h.owner != nil && h.distanceTo(h.owner.Position) > hirelingFollowDistance {
+		h.target = nil
+		h.moveTo(h.owner.Position, hirelingStopDistance)
+
+		return
+	}
+
+	h.target = h.nearestTarget()
+	if h.target == nil {
+		return
+	}
+
+	h.moveTo(h.target.GetPosition(), h.attackRange)
+}
+
+func (h *Hireling) nearestTarget() d2interface.MapEntity {
+	if h.findTargets == nil {
+		return nil
+	}
+
+	var (
+		nearest         d2interface.MapEntity
+		nearestDistance float64 = hirelingAggroDistance
+	)
+
+	for _, entity := range h.findTargets() {
+		npc, ok := entity.(*NPC)
+		if !ok || !npc.IsHostile() || npc.IsDead() {
+			continue
+		}
+
+		if distance := h.distanceTo(npc.GetPosition()); distance <= nearestDistance {
+			nearest, nearestDistance = npc, distance
+		}
+	}
+
+	return nearest
+}
+
+// Attack plays the animation of the mode of the skill the hireling attacked with, facing the target
+func (h *Hireling) Attack(target d2vector.Position, mode d2enum.MonsterAnimationMode) {
+	if h.State.IsDead {
+		return
+	}
+
+	h.StopMoving()
+	h.composite.SetDirection(h.Position.DirectionTo(target.Vector))
+	h.setMode(mode)
+	h.isAttacking = true
+}
+
+// Die kills the hireling and plays its death animation
+func (h *Hireling) Die() {
+	h.StopMoving()
+	h.State.Kill()
+	h.isAttacking = false
+	h.isDying = true
+	h.target = nil
+
+	h.setMode(d2enum.MonsterAnimationModeDeath)
+}
+
+func (h *Hireling) moveTo(target d2vector.Position, stopDistance float64) {
+	if h.distanceTo(target) <= stopDistance {
+		return
+	}
+
+	h.setTarget(target, func() {
+		h.setMode(d2enum.MonsterAnimationModeNeutral)
+	})
+}
+
+func (h *Hireling) distanceTo(target d2vector.Position) float64 {
+	delta := target.Clone()
+	delta.Subtract(&h.Position.Vector)
+
+	return delta.Length()
+}
+
+func (h *Hireling) setMode(mode d2enum.MonsterAnimationMode) {
+	if h.composite.GetAnimationMode() == mode.String() {
+		return
+	}
+
+	// not every hireling has an animation for every mode, the current animation keeps playing then
+	if err := h.composite.SetMode(mode, h.composite.GetWeaponClass()); err != nil {
+		return
+	}
+}
+
+// rotate sets direction and changes animation
+func (h *Hireling) rotate(direction int) {
+	if !h.atTarget() && !h.isAttacking {
+		h.setMode(d2enum.MonsterAnimationModeWalk)
+	}
+
+	if h.composite.GetDirection() != direction {
+		h.composite.SetDirection(direction)
+	}
+}
+
+// Selectable returns true if the hireling is alive
+func (h *Hireling) Selectable() bool {
+	return !h.State.IsDead
+}
+
+// Label returns the hireling's name
+func (h *Hireling) Label() string {
+	return h.name
+}
+
+// GetPosition returns the hireling's position
+func (h *Hireling) GetPosition() d2vector.Position {
+	return h.mapEntity.Position
+}
+
+// GetVelocity returns the hireling's velocity vector
+func (h *Hireling) GetVelocity() d2vector.Vector {
+	return h.mapEntity.velocity
+}
+
+// GetSize returns the current frame size
+func (h *Hireling) GetSize() (width, height int) {
+	return h.composite.GetSize()
+}
@@ -63,6 +63,13 @@ func (m *mapEntity) StopMoving() {
 	m.setTarget(m.Position, nil)
 }
 
+// Warp stops the entity and moves it to the given position at once, like when going through a portal.
+func (m *mapEntity) Warp(p d2vector.Position) {
+	m.ClearPath()
+	m.Position.Copy(&p.Vector)
+	m.setTarget(p, nil)
+}
+
 // SetSpeed sets the entity movement speed.
 func (m *mapEntity) SetSpeed(speed float64) {
 	m.Speed = speed
@@ -24,8 +24,13 @@ type NPC struct {
 	repetitions   int
 	monstatRecord *d2records.MonStatRecord
 	monstatEx     *d2records.MonStat2Record
+	superUnique   string
 	HasPaths      bool
 	isDone        bool
+	isTalking     bool
+	isDying       bool
+	isDead        bool
+	isAttacking   bool
 }
 
 const (
@@ -98,7 +103,29 @@ func (v *NPC) Advance(tickTime float64) {
 		return
 	}
 
-	if v.HasPaths && v.wait() {
+	if v.isDying {
+		if v.composite.GetPlayedCount() >= 1 {
+			v.isDying = false
+			v.setMode(d2enum.MonsterAnimationModeDead)
+		}
+
+		return
+	}
+
+	if v.isDead {
+		return
+	}
+
+	if v.isAttacking {
+		if v.composite.GetPlayedCount() >= 1 {
+			v.isAttacking = false
+			v.setMode(d2enum.MonsterAnimationModeNeutral)
+		}
+
+		return
+	}
+
+	if v.HasPaths && !v.isTalking && v.wait() {
 		// If at the target, set target to the next path.
 		v.isDone = false
 		path := v.NextPath()
@@ -144,8 +171,36 @@ func (v *NPC) next() {
 	}
 }
 
+// StartTalking stops the NPC where it stands and turns it toward the listener,
+// the NPC doesn't follow its paths until StopTalking is called
+func (v *NPC) StartTalking(listener d2vector.Position) {
+	v.isTalking = true
+	v.StopMoving()
+
+	v.rotate(v.Position.DirectionTo(listener.Vector))
+}
+
+// StopTalking lets the NPC continue on its paths
+func (v *NPC) StopTalking() {
+	if !v.isTalking {
+		return
+	}
+
+	v.isTalking = false
+	v.isDone = true
+}
+
+// IsTalking returns true if the NPC is talking to the hero
+func (v *NPC) IsTalking() bool {
+	return v.isTalking
+}
+
 // rotate sets direction and changes animation
 func (v *NPC) rotate(direction int) {
+	if v.isDead {
+		return
+	}
+
 	var newMode d2enum.MonsterAnimationMode
 	if !v.atTarget() {
 		newMode = d2enum.MonsterAnimationModeWalk
@@ -164,10 +219,46 @@ func (v *NPC) rotate(direction int) {
 	}
 }
 
+// Attack turns the NPC to the target and plays its attack animation once
+func (v *NPC) Attack(target d2vector.Position) {
+	if v.isDead {
+		return
+	}
+
+	v.StopMoving()
+	v.rotate(v.Position.DirectionTo(target.Vector))
+	v.isAttacking = true
+	v.setMode(d2enum.MonsterAnimationModeAttack1)
+}
+
+// Die stops the NPC and plays its death animation, it stays on the map as a corpse
+func (v *NPC) Die() {
+	v.StopMoving()
+	v.HasPaths = false
+	v.isTalking = false
+	v.isAttacking = false
+	v.isDying = true
+	v.isDead = true
+
+	v.setMode(d2enum.MonsterAnimationModeDeath)
+}
+
+// IsDead returns true if the NPC was killed
+func (v *NPC) IsDead() bool {
+	return v.isDead
+}
+
+func (v *NPC) setMode(mode d2enum.MonsterAnimationMode) {
+	// not every monster has an animation for every mode, the current animation keeps playing then
+	if err := v.composite.SetMode(mode, v.composite.GetWeaponClass()); err != nil {
+		return
+	}
+}
+
 // Selectable returns true if the object can be highlighted/selected.
 func (v *NPC) Selectable() bool {
 	// is there something handy that determines selectable npc's?
-	return v.name != ""
+	return v.name != "" && !v.isDead
 }
 
 // Label returns the NPC's in-game name (e.g. "Deckard Cain") or an empty string if it does not have a name.
@@ -189,3 +280,29 @@ func (v *NPC) GetVelocity() d2vector.Vector {
 func (v *NPC) GetSize() (width, height int) {
 	return v.composite.GetSize()
 }
+
+// MonsterStats returns the monstats.txt record of the NPC
+func (v *NPC) MonsterStats() *d2records.MonStatRecord {
+	return v.monstatRecord
+}
+
+// SetMonsterStats sets the monstats.txt record of the NPC
+func (v *NPC) SetMonsterStats(record *d2records.MonStatRecord) {
+	v.monstatRecord = record
+}
+
+// SuperUnique returns the superuniques.txt key of the NPC, or an empty string if the NPC isn't a
+// superunique monster
+func (v *NPC) SuperUnique() string {
+	return v.superUnique
+}
+
+// SetSuperUnique sets the superuniques.txt key of the NPC
+func (v *NPC) SetSuperUnique(key string) {
+	v.superUnique = key
+}
+
+// IsHostile returns true if the NPC is a monster that can be attacked
+func (v *NPC) IsHostile() bool {
+	return v.monstatRecord != nil && !v.monstatRecord.IsNpc && v.monstatRecord.IsKillable && !v.isDead
+}
@@ -21,8 +21,11 @@ type Object struct {
 	highlight bool
 	// nameLabel    d2ui.Label
 	objectRecord *d2records.ObjectDetailRecord
+	shrine       *d2records.ShrineRecord
 	drawLayer    int
 	name         string
+	// the operating animation is playing, the object is opened once it's done
+	isOperating bool
 }
 
 // setMode changes the graphical mode of this animated entity
@@ -104,6 +107,14 @@ func (ob *Object) Advance(elapsed float64) {
 	if err := ob.composite.Advance(elapsed); err != nil {
 		fmt.Printf("failed to advance composiste animation, err: %v\n", err)
 	}
+
+	if ob.isOperating && ob.composite.GetPlayedCount() >= 1 {
+		ob.isOperating = false
+
+		if err := ob.setMode(d2enum.ObjectAnimationModeOpened, 0, false); err != nil {
+			fmt.Printf("failed to open object, err: %v\n", err)
+		}
+	}
 }
 
 // GetLayer returns which layer of the map the object is drawn
@@ -136,3 +147,58 @@ func (ob *Object) GetVelocity() d2vector.Vector {
 func (ob *Object) GetSize() (width, height int) {
 	return ob.composite.GetSize()
 }
+
+// Record returns the objects.txt record of the object
+func (ob *Object) Record() *d2records.ObjectDetailRecord {
+	return ob.objectRecord
+}
+
+// Mode returns the animation mode of the object, an operated object is in the operating
+// or opened mode
+func (ob *Object) Mode() d2enum.ObjectAnimationMode {
+	if ob.isOperating {
+		return d2enum.ObjectAnimationModeOperating
+	}
+
+	return ob.composite.ObjectAnimationMode()
+}
+
+// IsOperated returns true if the object has been operated, like an opened chest or door
+func (ob *Object) IsOperated() bool {
+	return ob.Mode() != d2enum.ObjectAnimationModeNeutral
+}
+
+// SetMode changes the mode of the object. Operating plays the operating animation once and
+// then opens the object, the modes the object doesn't have are skipped.
+func (ob *Object) SetMode(mode d2enum.ObjectAnimationMode) error {
+	ob.isOperating = false
+
+	if mode == d2enum.ObjectAnimationModeOperating {
+		if ob.objectRecord.HasAnimationMode[mode] {
+			ob.isOperating = true
+			return ob.setMode(mode, 0, false)
+		}
+
+		mode = d2enum.ObjectAnimationModeOpened
+	}
+
+	if !ob.objectRecord.HasAnimationMode[mode] {
+		return nil
+	}
+
+	return ob.setMode(mode, 0, false)
+}
+
+// Shrine returns the shrines.txt record of a shrine object, or nil
+func (ob *Object) Shrine() *d2records.ShrineRecord {
+	return ob.shrine
+}
+
+// SetShrine sets the effect of a shrine object, the object is named after the shrine
+func (ob *Object) SetShrine(shrine *d2records.ShrineRecord) {
+	ob.shrine = shrine
+
+	if shrine != nil {
+		ob.name = shrine.ShrineName
+	}
+}
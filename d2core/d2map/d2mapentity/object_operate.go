@@ -0,0 +1,64 @@
+package d2mapentity
+
+// ObjectBehavior is what happens when a player operates an object
+type ObjectBehavior int
+
+// Object behaviors
+const (
+	// ObjectBehaviorNone is for objects which can't be operated
+	ObjectBehaviorNone ObjectBehavior = iota
+	// ObjectBehaviorContainer opens the object and drops treasure, like chests and caskets
+	ObjectBehaviorContainer
+	// ObjectBehaviorBreakable breaks the object and drops treasure, like barrels and urns
+	ObjectBehaviorBreakable
+	// ObjectBehaviorDoor opens and closes the door, which changes the walkable sub-tiles
+	ObjectBehaviorDoor
+	// ObjectBehaviorWell restores the life of the player
+	ObjectBehaviorWell
+	// ObjectBehaviorShrine applies the effect of the shrine to the player
+	ObjectBehaviorShrine
+)
+
+// objects.txt sub classes of the operable objects
+const (
+	subClassShrine    = 1
+	subClassContainer = 8
+	subClassWell      = 32
+)
+
+// Behavior returns what happens when the object is operated. It is found with the operate
+// function of the object, or with its sub class for the operate functions which aren't known.
+func (ob *Object) Behavior() ObjectBehavior {
+	behaviors := map[int]ObjectBehavior{
+		1:  ObjectBehaviorContainer, // casket
+		2:  ObjectBehaviorShrine,
+		3:  ObjectBehaviorBreakable, // urn
+		4:  ObjectBehaviorContainer, // chest
+		5:  ObjectBehaviorBreakable, // barrel
+		7:  ObjectBehaviorBreakable, // exploding barrel
+		8:  ObjectBehaviorDoor,
+		22: ObjectBehaviorWell,
+	}
+
+	if behavior, ok := behaviors[ob.objectRecord.OperateFn]; ok {
+		return behavior
+	}
+
+	switch {
+	case ob.objectRecord.IsDoor:
+		return ObjectBehaviorDoor
+	case ob.objectRecord.SubClass&subClassShrine != 0:
+		return ObjectBehaviorShrine
+	case ob.objectRecord.SubClass&subClassWell != 0:
+		return ObjectBehaviorWell
+	case ob.objectRecord.SubClass&subClassContainer != 0:
+		return ObjectBehaviorContainer
+	}
+
+	return ObjectBehaviorNone
+}
+
+// DropsTreasure returns true if operating the object drops treasure
+func (b ObjectBehavior) DropsTreasure() bool {
+	return b == ObjectBehaviorContainer || b == ObjectBehaviorBreakable
+}
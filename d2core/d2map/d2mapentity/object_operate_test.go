@@ -0,0 +1,30 @@
+package d2mapentity
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2records"
+)
+
+func TestObjectBehavior(t *testing.T) {
+	tests := []struct {
+		record   d2records.ObjectDetailRecord
+		behavior ObjectBehavior
+	}{
+		{d2records.ObjectDetailRecord{OperateFn: 4}, ObjectBehaviorContainer},
+		{d2records.ObjectDetailRecord{OperateFn: 5}, ObjectBehaviorBreakable},
+		{d2records.ObjectDetailRecord{OperateFn: 2}, ObjectBehaviorShrine},
+		{d2records.ObjectDetailRecord{IsDoor: true}, ObjectBehaviorDoor},
+		{d2records.ObjectDetailRecord{SubClass: subClassWell}, ObjectBehaviorWell},
+		{d2records.ObjectDetailRecord{SubClass: subClassContainer}, ObjectBehaviorContainer},
+		{d2records.ObjectDetailRecord{}, ObjectBehaviorNone},
+	}
+
+	for idx := range tests {
+		ob := &Object{objectRecord: &tests[idx].record}
+
+		if behavior := ob.Behavior(); behavior != tests[idx].behavior {
+			t.Errorf("test %d: unexpected behavior, want %d, have %d", idx, tests[idx].behavior, behavior)
+		}
+	}
+}
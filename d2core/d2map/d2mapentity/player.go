@@ -7,8 +7,11 @@ import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2math/d2vector"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2automap"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2hero"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2inventory"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2quest"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2states"
 )
 
 // Player is the player character entity.
@@ -18,6 +21,11 @@ type Player struct {
 	animationMode     string
 	composite         *d2asset.Composite
 	Equipment         *d2inventory.CharacterEquipment
+	Belt              *d2inventory.Belt
+	Hireling          *d2hero.HirelingState
+	Quests            *d2quest.State
+	Automap           *d2automap.State
+	States            *d2states.List
 	Stats             *d2hero.HeroStatsState
 	Skills            map[int]*d2hero.HeroSkill
 	LeftSkill         *d2hero.HeroSkill
@@ -26,11 +34,18 @@ type Player struct {
 	Gold              int
 	lastPathSize      int
 	isInTown          bool
+	isHostile         bool
 	isRunToggled      bool
 	isRunning         bool
 	isCasting         bool
 	onFinishedCasting func()
+	isDying           bool
+	isDead            bool
+	onDead            func()
+	potionEffects     []*d2hero.PotionEffect
+	stateOverlays     map[string]d2interface.Animation
 	Act               int
+	Difficulty        d2enum.DifficultyType
 }
 
 // run speed should be walkspeed * 1.5, since in the original game it is 6 yards walk and 9 yards run.
@@ -95,6 +110,16 @@ func (p *Player) Advance(tickTime float64) {
 		fmt.Printf("failed to set animationMode to: %d, err: %v\n", p.GetAnimationMode(), err)
 	}
 
+	if p.isDying && p.composite.GetPlayedCount() >= 1 {
+		p.isDying = false
+		p.isDead = true
+
+		if p.onDead != nil {
+			p.onDead()
+			p.onDead = nil
+		}
+	}
+
 	if p.IsCasting() {
 		if p.composite.GetPlayedCount() >= 1 {
 			p.isCasting = false
@@ -144,6 +169,26 @@ func (p *Player) Advance(tickTime float64) {
 			p.SetSpeed(baseRunSpeed)
 		}
 	}
+
+	p.advancePotionEffects(tickTime)
+	p.advanceStateOverlays(tickTime)
+}
+
+// AddPotionEffect starts restoring the life and mana of the given potion effect
+func (p *Player) AddPotionEffect(effect *d2hero.PotionEffect) {
+	p.potionEffects = append(p.potionEffects, effect)
+}
+
+func (p *Player) advancePotionEffects(tickTime float64) {
+	active := p.potionEffects[:0]
+
+	for _, effect := range p.potionEffects {
+		if !effect.Advance(tickTime, p.Stats) {
+			active = append(active, effect)
+		}
+	}
+
+	p.potionEffects = active
 }
 
 // Render renders the animated composite for this entity.
@@ -156,13 +201,29 @@ func (p *Player) Render(target d2interface.Surface) {
 
 	defer target.Pop()
 
+	// the light of the states tints the player
+	if light, ok := p.States.Light(); ok {
+		target.PushColor(light)
+		defer target.Pop()
+	}
+
 	if err := p.composite.Render(target); err != nil {
 		fmt.Printf("failed to render the composite of player: %s, err: %v\n", p.ID(), err)
 	}
+
+	p.renderStateOverlays(target)
 }
 
 // GetAnimationMode returns the current animation mode based on what the player is doing and where they are.
 func (p *Player) GetAnimationMode() d2enum.PlayerAnimationMode {
+	if p.isDying {
+		return d2enum.PlayerAnimationModeDeath
+	}
+
+	if p.isDead {
+		return d2enum.PlayerAnimationModeDead
+	}
+
 	if p.IsRunning() && !p.atTarget() {
 		return d2enum.PlayerAnimationModeRun
 	}
@@ -240,10 +301,59 @@ func (p *Player) StartCasting(animMode d2enum.PlayerAnimationMode, onFinishedCas
 	}
 }
 
-// Selectable returns true if the player is in town.
+// Die stops the player and plays the death animation, onDead is called once it has played
+func (p *Player) Die(onDead func()) {
+	p.StopMoving()
+	p.isCasting = false
+	p.onFinishedCasting = nil
+	p.potionEffects = nil
+	p.isDying = true
+	p.onDead = onDead
+
+	if err := p.SetAnimationMode(d2enum.PlayerAnimationModeDeath); err != nil {
+		fmt.Printf("failed to set the death animation of player: %s, err: %v\n", p.ID(), err)
+	}
+}
+
+// IsDead returns true if the player is dying or dead
+func (p *Player) IsDead() bool {
+	return p.isDying || p.isDead
+}
+
+// Respawn brings the dead player back to life
+func (p *Player) Respawn() {
+	p.isDying = false
+	p.isDead = false
+	p.onDead = nil
+}
+
+// SetEquipment changes the equipment of the player, and the layers of the player's composite
+func (p *Player) SetEquipment(equipment *d2inventory.CharacterEquipment) {
+	p.Equipment = equipment
+
+	if err := p.composite.SetMode(p.GetAnimationMode(), equipment.RightHand.GetWeaponClass()); err != nil {
+		fmt.Printf("failed to set the weapon class of player: %s, err: %v\n", p.ID(), err)
+	}
+
+	if err := p.composite.Equip(compositeEquipment(equipment)); err != nil {
+		fmt.Printf("failed to equip player: %s, err: %v\n", p.ID(), err)
+	}
+}
+
+// Selectable returns true if the player is in town, or hostile to the local player.
 func (p *Player) Selectable() bool {
-	// Players are selectable when in town
-	return p.IsInTown()
+	// Players are selectable when in town, and can be attacked out of town once hostile
+	return p.IsInTown() || p.isHostile
+}
+
+// IsHostile returns true if the local player and the player declared hostility
+func (p *Player) IsHostile() bool {
+	return p.isHostile
+}
+
+// SetHostile changes whether the local player and the player declared hostility
+func (p *Player) SetHostile(hostile bool) {
+	p.isHostile = hostile
 }
 
 // GetPosition returns the entity's position
@@ -0,0 +1,53 @@
+package d2mapentity
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+// HasStateOverlay returns true if the overlay of a state is shown on the player
+func (p *Player) HasStateOverlay(name string) bool {
+	_, found := p.stateOverlays[name]
+	return found
+}
+
+// AddStateOverlay shows the overlay animation of a state on the player
+func (p *Player) AddStateOverlay(name string, animation d2interface.Animation) {
+	if p.stateOverlays == nil {
+		p.stateOverlays = make(map[string]d2interface.Animation)
+	}
+
+	p.stateOverlays[name] = animation
+}
+
+// RemoveStateOverlays removes the overlays which are not in the list of overlays
+// of the active states
+func (p *Player) RemoveStateOverlays(active []string) {
+	for name := range p.stateOverlays {
+		found := false
+
+		for _, activeName := range active {
+			if activeName == name {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			delete(p.stateOverlays, name)
+		}
+	}
+}
+
+func (p *Player) advanceStateOverlays(elapsed float64) {
+	for _, overlay := range p.stateOverlays {
+		if err := overlay.Advance(elapsed); err != nil {
+			continue
+		}
+	}
+}
+
+func (p *Player) renderStateOverlays(target d2interface.Surface) {
+	for _, overlay := range p.stateOverlays {
+		overlay.Render(target)
+	}
+}
@@ -0,0 +1,49 @@
+package d2mapentity
+
+import (
+	"errors"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
+)
+
+// the objects.txt index of the town portal
+const townPortalObjectID = 59
+
+var errUnknownPortalObject = errors.New("unknown town portal object")
+
+// Portal is one end of a town portal, the players who use it are taken to the other end
+type Portal struct {
+	*Object
+	// Owner is the id of the player who opened the portal
+	Owner string
+}
+
+// NewPortal opens a town portal at the given sub-tile position, the id is given by the server
+// and the label is the name of the owner
+func (f *MapEntityFactory) NewPortal(id, owner, label string, x, y int) (*Portal, error) {
+	record := f.asset.Records.Object.Details[townPortalObjectID]
+	if record == nil {
+		return nil, errUnknownPortalObject
+	}
+
+	object, err := f.NewObject(x, y, record, d2resource.PaletteUnits)
+	if err != nil {
+		return nil, err
+	}
+
+	object.uuid = id
+	object.name = label
+
+	// the portal plays its opening animation and then stays open
+	if err := object.SetMode(d2enum.ObjectAnimationModeOperating); err != nil {
+		return nil, err
+	}
+
+	return &Portal{Object: object, Owner: owner}, nil
+}
+
+// Selectable returns true, portals can always be used
+func (p *Portal) Selectable() bool {
+	return true
+}
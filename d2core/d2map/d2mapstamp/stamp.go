@@ -112,6 +112,13 @@ func (mr *Stamp) Entities(tileOffsetX, tileOffsetY int) []d2interface.MapEntity
 		if object.Type == int(d2enum.ObjectTypeCharacter) {
 			monPreset := mr.factory.asset.Records.Monster.Presets[mr.ds1.Act][object.ID]
 			monstat := mr.factory.asset.Records.Monster.Stats[monPreset]
+
+			// superunique presets are named after the superunique, which spawns its monster class
+			superUnique := mr.factory.asset.Records.Monster.Unique.Super[monPreset]
+			if monstat == nil && superUnique != nil {
+				monstat = mr.factory.asset.Records.Monster.Stats[superUnique.Class]
+			}
+
 			// If monstat is nil here it is a place_ type object, idk how to handle those yet.
 			// (See monpreset and monplace txts for reference)
 			if monstat != nil {
@@ -121,6 +128,10 @@ func (mr *Stamp) Entities(tileOffsetX, tileOffsetY int) []d2interface.MapEntity
 				npc, err := mr.entity.NewNPC(npcX, npcY, monstat, 0)
 
 				if err == nil {
+					if superUnique != nil {
+						npc.SetSuperUnique(superUnique.Key)
+					}
+
 					npc.SetPaths(convertPaths(tileOffsetX, tileOffsetY, object.Paths))
 					entities = append(entities, npc)
 				}
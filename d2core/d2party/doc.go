@@ -0,0 +1,3 @@
+// Package d2party provides the parties of a game, which players are in a party together, who
+// invited whom and which players declared hostility.
+package d2party
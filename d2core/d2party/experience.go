@@ -0,0 +1,33 @@
+package d2party
+
+// each party member in range after the first adds this many percent to the experience of a kill
+const partyExperienceBonus = 35
+
+// ExperienceShares splits the experience of a kill between the party members in range, given
+// by their levels. The experience grows with the number of members, and is split by level so
+// higher level members get a bigger share.
+func ExperienceShares(experience int, levels []int) []int {
+	shares := make([]int, len(levels))
+
+	if len(levels) == 0 || experience <= 0 {
+		return shares
+	}
+
+	levelSum := 0
+	for _, level := range levels {
+		levelSum += level
+	}
+
+	total := experience * (100 + partyExperienceBonus*(len(levels)-1)) / 100 // nolint:gomnd // percentage
+
+	for idx, level := range levels {
+		if levelSum <= 0 {
+			shares[idx] = total / len(levels)
+			continue
+		}
+
+		shares[idx] = total * level / levelSum
+	}
+
+	return shares
+}
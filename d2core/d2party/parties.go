@@ -0,0 +1,214 @@
+package d2party
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+// Errors returned when a party action isn't possible
+var (
+	ErrSelf        = errors.New("a player can't do this with themselves")
+	ErrInParty     = errors.New("player is already in a party")
+	ErrNotInParty  = errors.New("player is not in a party")
+	ErrSameParty   = errors.New("players are in the same party")
+	ErrHostile     = errors.New("players are hostile")
+	ErrNotInvited  = errors.New("player was not invited")
+	ErrNotHostile  = errors.New("players are not hostile")
+	ErrPartyIsFull = errors.New("party is full")
+)
+
+// NewParties creates the parties of a new game, with every player on their own
+func NewParties() *Parties {
+	return &Parties{
+		Members: make(map[string]string),
+		Invites: make(map[string]map[string]bool),
+		Hostile: make(map[string]map[string]bool),
+	}
+}
+
+// Parties is the serializable party state of a game. Hostility is mutual, when a player
+// declares hostility both players are hostile to each other.
+type Parties struct {
+	// Members is the party id of each player in a party, by player id
+	Members map[string]string `json:"members"`
+	// Invites is the set of players who invited each player, by invited player id
+	Invites map[string]map[string]bool `json:"invites"`
+	// Hostile is the set of players each player is hostile to, by player id
+	Hostile map[string]map[string]bool `json:"hostile"`
+	// LastPartyID is the id of the last party which was formed
+	LastPartyID int `json:"lastPartyId"`
+}
+
+// PartyOf returns the id of the party of the player, if the player is in a party
+func (p *Parties) PartyOf(playerID string) (partyID string, found bool) {
+	partyID, found = p.Members[playerID]
+	return partyID, found
+}
+
+// PartyMembers returns the ids of the players in the party
+func (p *Parties) PartyMembers(partyID string) []string {
+	members := make([]string, 0)
+
+	for playerID, party := range p.Members {
+		if party == partyID {
+			members = append(members, playerID)
+		}
+	}
+
+	return members
+}
+
+// InSameParty returns true if both players are in the same party
+func (p *Parties) InSameParty(playerID, otherID string) bool {
+	party, found := p.Members[playerID]
+	return found && p.Members[otherID] == party
+}
+
+// IsHostile returns true if the players declared hostility
+func (p *Parties) IsHostile(playerID, otherID string) bool {
+	return p.Hostile[playerID][otherID]
+}
+
+// IsInvited returns true if the player was invited by the inviter
+func (p *Parties) IsInvited(playerID, inviterID string) bool {
+	return p.Invites[playerID][inviterID]
+}
+
+// Relationship returns how the player sees the other player
+func (p *Parties) Relationship(playerID, otherID string) d2enum.PlayersRelationships {
+	switch {
+	case p.IsHostile(playerID, otherID):
+		return d2enum.PlayerRelationEnemy
+	case p.InSameParty(playerID, otherID):
+		return d2enum.PlayerRelationFriend
+	default:
+		return d2enum.PlayerRelationNeutral
+	}
+}
+
+// Invite invites the player to the party of the inviter. Players in a party can't be invited,
+// they have to leave their party first.
+func (p *Parties) Invite(inviterID, playerID string) error {
+	switch {
+	case inviterID == playerID:
+		return ErrSelf
+	case p.IsHostile(inviterID, playerID):
+		return ErrHostile
+	}
+
+	if _, found := p.Members[playerID]; found {
+		return ErrInParty
+	}
+
+	if partyID, found := p.Members[inviterID]; found && len(p.PartyMembers(partyID)) >= d2enum.MaxPlayersInGame {
+		return ErrPartyIsFull
+	}
+
+	setPair(p.Invites, playerID, inviterID)
+
+	return nil
+}
+
+// Accept puts the player into the party of the inviter, a new party is formed if the inviter
+// isn't in one. The other invitations of the player are dropped.
+func (p *Parties) Accept(playerID, inviterID string) error {
+	if !p.IsInvited(playerID, inviterID) {
+		return ErrNotInvited
+	}
+
+	if _, found := p.Members[playerID]; found {
+		return ErrInParty
+	}
+
+	partyID, found := p.Members[inviterID]
+	if !found {
+		p.LastPartyID++
+		partyID = strconv.Itoa(p.LastPartyID)
+		p.Members[inviterID] = partyID
+	}
+
+	p.Members[playerID] = partyID
+	delete(p.Invites, playerID)
+
+	return nil
+}
+
+// Leave takes the player out of their party, a party with a single player left is disbanded
+func (p *Parties) Leave(playerID string) error {
+	partyID, found := p.Members[playerID]
+	if !found {
+		return ErrNotInParty
+	}
+
+	delete(p.Members, playerID)
+
+	if members := p.PartyMembers(partyID); len(members) == 1 {
+		delete(p.Members, members[0])
+	}
+
+	return nil
+}
+
+// DeclareHostile makes both players hostile to each other, and drops the invitations between
+// them. Players of the same party have to leave it first.
+func (p *Parties) DeclareHostile(playerID, otherID string) error {
+	switch {
+	case playerID == otherID:
+		return ErrSelf
+	case p.InSameParty(playerID, otherID):
+		return ErrSameParty
+	}
+
+	setPair(p.Hostile, playerID, otherID)
+	setPair(p.Hostile, otherID, playerID)
+	deletePair(p.Invites, playerID, otherID)
+	deletePair(p.Invites, otherID, playerID)
+
+	return nil
+}
+
+// EndHostility ends the hostility between both players
+func (p *Parties) EndHostility(playerID, otherID string) error {
+	if !p.IsHostile(playerID, otherID) {
+		return ErrNotHostile
+	}
+
+	deletePair(p.Hostile, playerID, otherID)
+	deletePair(p.Hostile, otherID, playerID)
+
+	return nil
+}
+
+// Remove removes a player who left the game, with their party membership, invitations and
+// hostility
+func (p *Parties) Remove(playerID string) {
+	if _, found := p.Members[playerID]; found {
+		_ = p.Leave(playerID)
+	}
+
+	for _, set := range []map[string]map[string]bool{p.Invites, p.Hostile} {
+		delete(set, playerID)
+
+		for otherID := range set {
+			deletePair(set, otherID, playerID)
+		}
+	}
+}
+
+func setPair(set map[string]map[string]bool, key, value string) {
+	if set[key] == nil {
+		set[key] = make(map[string]bool)
+	}
+
+	set[key][value] = true
+}
+
+func deletePair(set map[string]map[string]bool, key, value string) {
+	delete(set[key], value)
+
+	if len(set[key]) == 0 {
+		delete(set, key)
+	}
+}
@@ -0,0 +1,162 @@
+package d2party
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+func TestPartiesInviteAccept(t *testing.T) {
+	parties := NewParties()
+
+	if err := parties.Accept("bob", "alice"); !errors.Is(err, ErrNotInvited) {
+		t.Fatalf("accepted without an invitation, err: %v", err)
+	}
+
+	if err := parties.Invite("alice", "bob"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := parties.Invite("carol", "bob"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := parties.Accept("bob", "alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !parties.InSameParty("alice", "bob") || parties.InSameParty("alice", "carol") {
+		t.Errorf("unexpected party members: %v", parties.Members)
+	}
+
+	if parties.IsInvited("bob", "carol") {
+		t.Errorf("invitations of a player who joined a party weren't dropped")
+	}
+
+	if err := parties.Invite("carol", "bob"); !errors.Is(err, ErrInParty) {
+		t.Errorf("invited a player who is in a party, err: %v", err)
+	}
+
+	if relation := parties.Relationship("bob", "alice"); relation != d2enum.PlayerRelationFriend {
+		t.Errorf("want party members to be friends, have %d", relation)
+	}
+}
+
+func TestPartiesLeave(t *testing.T) {
+	parties := NewParties()
+
+	for _, player := range []string{"bob", "carol"} {
+		if err := parties.Invite("alice", player); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := parties.Accept(player, "alice"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	partyID, _ := parties.PartyOf("alice")
+	if members := parties.PartyMembers(partyID); len(members) != 3 {
+		t.Fatalf("want 3 party members, have %v", members)
+	}
+
+	if err := parties.Leave("alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !parties.InSameParty("bob", "carol") {
+		t.Errorf("party was disbanded while two players are left")
+	}
+
+	parties.Remove("carol")
+
+	if _, found := parties.PartyOf("bob"); found {
+		t.Errorf("party of a single player wasn't disbanded")
+	}
+
+	if err := parties.Leave("bob"); !errors.Is(err, ErrNotInParty) {
+		t.Errorf("left without a party, err: %v", err)
+	}
+}
+
+func TestPartiesHostility(t *testing.T) {
+	parties := NewParties()
+
+	if err := parties.Invite("alice", "bob"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := parties.DeclareHostile("bob", "alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !parties.IsHostile("alice", "bob") || parties.IsInvited("bob", "alice") {
+		t.Errorf("hostility isn't mutual or didn't drop the invitation")
+	}
+
+	if err := parties.Invite("alice", "bob"); !errors.Is(err, ErrHostile) {
+		t.Errorf("invited a hostile player, err: %v", err)
+	}
+
+	if relation := parties.Relationship("alice", "bob"); relation != d2enum.PlayerRelationEnemy {
+		t.Errorf("want hostile players to be enemies, have %d", relation)
+	}
+
+	if err := parties.EndHostility("alice", "bob"); err != nil {
+		t.Fatal(err)
+	}
+
+	if parties.IsHostile("bob", "alice") {
+		t.Errorf("hostility didn't end for both players")
+	}
+}
+
+func TestPartiesJSON(t *testing.T) {
+	parties := NewParties()
+	_ = parties.Invite("alice", "bob")
+	_ = parties.Accept("bob", "alice")
+	_ = parties.DeclareHostile("alice", "carol")
+
+	data, err := json.Marshal(parties)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := NewParties()
+	if err := json.Unmarshal(data, loaded); err != nil {
+		t.Fatal(err)
+	}
+
+	if !loaded.InSameParty("alice", "bob") || !loaded.IsHostile("carol", "alice") {
+		t.Errorf("party state changed after loading")
+	}
+}
+
+func TestExperienceShares(t *testing.T) {
+	tests := []struct {
+		experience int
+		levels     []int
+		want       []int
+	}{
+		{100, []int{10}, []int{100}},
+		{100, []int{10, 10}, []int{67, 67}},
+		{100, []int{30, 10}, []int{101, 33}},
+		{100, nil, []int{}},
+	}
+
+	for _, test := range tests {
+		shares := ExperienceShares(test.experience, test.levels)
+		if len(shares) != len(test.want) {
+			t.Fatalf("levels %v: want %v, have %v", test.levels, test.want, shares)
+		}
+
+		for idx := range shares {
+			if shares[idx] != test.want[idx] {
+				t.Errorf("levels %v: want %v, have %v", test.levels, test.want, shares)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,2 @@
+// Package d2quest provides the quest state machines, and the quest state of a hero.
+package d2quest
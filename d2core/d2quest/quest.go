@@ -0,0 +1,136 @@
+package d2quest
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+// NumQuests is the number of quests in all acts
+const NumQuests = (d2enum.ActsNumber-1)*d2enum.NormalActQuestsNumber + d2enum.HalfQuestsNumber
+
+// RewardType is the kind of reward given for completing a quest
+type RewardType int
+
+// Reward types
+const (
+	// RewardSkillPoints gives unspent skill points
+	RewardSkillPoints RewardType = iota
+	// RewardStatPoints gives unspent stat points
+	RewardStatPoints
+	// RewardLife raises the maximum life
+	RewardLife
+	// RewardResistance raises all resistances
+	RewardResistance
+	// RewardImbue lets Charsi imbue an item
+	RewardImbue
+	// RewardSocket lets Larzuk add sockets to an item
+	RewardSocket
+	// RewardPersonalize lets Anya personalize an item
+	RewardPersonalize
+)
+
+// IsService returns true if the reward is a service of an npc, which has to be claimed later
+func (r RewardType) IsService() bool {
+	switch r {
+	case RewardImbue, RewardSocket, RewardPersonalize:
+		return true
+	}
+
+	return false
+}
+
+// Reward is given to the hero for completing a quest
+type Reward struct {
+	Type  RewardType
+	Value int
+}
+
+// step is a transition of the quest state machine, the quest moves from one of the
+// `from` statuses to the `to` status when the trigger happens
+type step struct {
+	from    []int
+	trigger Trigger
+	to      int
+	// the progress is shared with the party members in the game
+	shared bool
+}
+
+func (s *step) appliesTo(status int) bool {
+	for _, from := range s.from {
+		if from == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Definition is a quest, with the steps of its state machine and the rewards for completing it
+type Definition struct {
+	// ID is the position of the quest in the quest log, act 1 quest 1 is 0
+	ID int
+	// Act and Number of the quest in the act, both starting from 1
+	Act    int
+	Number int
+	// Rewards are given once for each difficulty
+	Rewards []Reward
+	// FinishesAct is true for the last quest of an act, completing it opens the next act
+	FinishesAct bool
+
+	steps []step
+}
+
+// ID returns the quest id of the given quest of the act, both starting from 1
+func ID(act, number int) int {
+	id := (act-1)*d2enum.NormalActQuestsNumber + number - 1
+	if act > d2enum.Act4 {
+		id -= d2enum.HalfQuestsNumber
+	}
+
+	return id
+}
+
+// QuestsInAct returns the number of quests in the act
+func QuestsInAct(act int) int {
+	if act == d2enum.Act4 {
+		return d2enum.HalfQuestsNumber
+	}
+
+	return d2enum.NormalActQuestsNumber
+}
+
+// Quests returns the definitions of all quests, ordered by id
+func Quests() []*Definition {
+	return questDefinitions
+}
+
+// Quest returns the definition of the quest with the given id, or nil
+func Quest(id int) *Definition {
+	if id < 0 || id >= len(questDefinitions) {
+		return nil
+	}
+
+	return questDefinitions[id]
+}
+
+// TalkStep is a step of a quest which is taken by talking to an npc
+type TalkStep struct {
+	// From are the statuses in which talking to the npc takes the step
+	From []int
+	// To is the status of the quest after the step
+	To int
+}
+
+// TalkSteps returns the steps of the quest which are taken by talking to the npc
+// with the given monstats.txt id
+func (q *Definition) TalkSteps(npc string) []TalkStep {
+	talk := TalkTrigger(npc)
+	steps := make([]TalkStep, 0)
+
+	for idx := range q.steps {
+		if q.steps[idx].trigger.Matches(talk) {
+			steps = append(steps, TalkStep{From: q.steps[idx].from, To: q.steps[idx].to})
+		}
+	}
+
+	return steps
+}
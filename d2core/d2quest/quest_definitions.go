@@ -0,0 +1,221 @@
+package d2quest
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+const (
+	notStarted = d2enum.QuestStatusNotStarted
+	completing = d2enum.QuestStatusCompleting
+)
+
+// levels.txt ids of the levels used by the quests
+const (
+	levelDenOfEvil           = 8
+	levelTristram            = 38
+	levelCatacombsLevel4     = 37
+	levelLostCity            = 44
+	levelCanyonOfTheMagi     = 46
+	levelArcaneSanctuary     = 74
+	levelTravincal           = 83
+	levelDuranceOfHate3      = 102
+	levelPandemoniumFortress = 103
+	levelChaosSanctuary      = 108
+	levelFrozenRiver         = 114
+	levelArreatSummit        = 120
+	levelWorldstoneKeep1     = 128
+)
+
+// the quest rewards
+const (
+	denOfEvilSkillPoints   = 1
+	radamentSkillPoints    = 1
+	goldenBirdLife         = 20
+	lamEsenStatPoints      = 5
+	fallenAngelSkillPoints = 2
+	prisonOfIceResistance  = 10
+)
+
+// on is a step which advances the quest of the hero who caused the trigger
+func on(trigger Trigger, to int, from ...int) step {
+	return step{from: from, trigger: trigger, to: to}
+}
+
+// onShared is a step which also advances the quest of the party members in the game
+func onShared(trigger Trigger, to int, from ...int) step {
+	return step{from: from, trigger: trigger, to: to, shared: true}
+}
+
+func quest(act, number int, rewards []Reward, steps ...step) *Definition {
+	return &Definition{
+		ID:      ID(act, number),
+		Act:     act,
+		Number:  number,
+		Rewards: rewards,
+		steps:   steps,
+	}
+}
+
+func finalQuest(act, number int, rewards []Reward, steps ...step) *Definition {
+	q := quest(act, number, rewards, steps...)
+	q.FinishesAct = true
+
+	return q
+}
+
+// questDefinitions are the state machines of all quests, ordered by id. The in progress
+// statuses match the quest descriptions in the string tables (qstsa<act>q<number><status>).
+// nolint:gochecknoglobals,gomnd,funlen // lookup table
+var questDefinitions = []*Definition{
+	// Act 1
+	quest(d2enum.Act1, 1, []Reward{{RewardSkillPoints, denOfEvilSkillPoints}}, // Den of Evil
+		on(TalkTrigger("Akara"), 1, notStarted),
+		onShared(ClearLevelTrigger(levelDenOfEvil), 2, notStarted, 1),
+		on(TalkTrigger("Akara"), completing, 2),
+	),
+	quest(d2enum.Act1, 2, nil, // Sisters' Burial Grounds
+		on(TalkTrigger("Kashya"), 1, notStarted),
+		onShared(KillTrigger("bloodraven"), 2, notStarted, 1),
+		on(TalkTrigger("Kashya"), completing, 2),
+	),
+	quest(d2enum.Act1, 3, []Reward{{RewardImbue, 1}}, // Tools of the Trade
+		on(TalkTrigger("Charsi"), 1, notStarted),
+		on(PickupTrigger("hdm"), 2, notStarted, 1),
+		on(TalkTrigger("Charsi"), completing, 2),
+	),
+	quest(d2enum.Act1, 4, nil, // The Search for Cain
+		on(TalkTrigger("Akara"), 1, notStarted),
+		on(PickupTrigger("bks"), 2, notStarted, 1),
+		on(TalkTrigger("Akara"), 3, 2),
+		on(EnterLevelTrigger(levelTristram), 4, 3),
+		onShared(UseObjectTrigger("CainGibbet"), 5, 3, 4),
+		on(TalkTrigger("cain1"), completing, 5),
+	),
+	quest(d2enum.Act1, 5, nil, // The Forgotten Tower
+		on(UseObjectTrigger("MoldyTome"), 1, notStarted),
+		onShared(KillTrigger("The Countess"), completing, notStarted, 1),
+	),
+	finalQuest(d2enum.Act1, 6, nil, // Sisters to the Slaughter
+		on(TalkTrigger("cain1"), 1, notStarted),
+		on(EnterLevelTrigger(levelCatacombsLevel4), 2, notStarted, 1),
+		onShared(KillTrigger("andariel"), 3, notStarted, 1, 2),
+		on(TalkTrigger("warriv1"), completing, 3),
+	),
+
+	// Act 2
+	quest(d2enum.Act2, 1, []Reward{{RewardSkillPoints, radamentSkillPoints}}, // Radament's Lair
+		on(TalkTrigger("atma"), 1, notStarted),
+		onShared(KillTrigger("radament"), 2, notStarted, 1),
+		on(TalkTrigger("atma"), completing, 2),
+	),
+	quest(d2enum.Act2, 2, nil, // The Horadric Staff
+		on(PickupTrigger("box"), 1, notStarted),
+		on(PickupTrigger("msf"), 2, notStarted, 1),
+		on(PickupTrigger("vip"), 3, notStarted, 1),
+		on(PickupTrigger("vip"), 4, 2),
+		on(PickupTrigger("msf"), 4, 3),
+		on(PickupTrigger("hst"), completing, notStarted, 1, 2, 3, 4),
+	),
+	quest(d2enum.Act2, 3, nil, // Tainted Sun
+		on(EnterLevelTrigger(levelLostCity), 1, notStarted),
+		onShared(UseObjectTrigger("ViperAltar"), 2, notStarted, 1),
+		on(TalkTrigger("drognan"), completing, 2),
+	),
+	quest(d2enum.Act2, 4, nil, // Arcane Sanctuary
+		on(TalkTrigger("drognan"), 1, notStarted),
+		on(TalkTrigger("jerhyn"), 2, 1),
+		on(EnterLevelTrigger(levelArcaneSanctuary), completing, notStarted, 1, 2),
+	),
+	quest(d2enum.Act2, 5, nil, // The Summoner
+		on(EnterLevelTrigger(levelArcaneSanctuary), 1, notStarted),
+		onShared(KillTrigger("summoner"), completing, notStarted, 1),
+	),
+	finalQuest(d2enum.Act2, 6, nil, // The Seven Tombs
+		on(TalkTrigger("jerhyn"), 1, notStarted),
+		on(EnterLevelTrigger(levelCanyonOfTheMagi), 2, notStarted, 1),
+		onShared(KillTrigger("duriel"), 3, notStarted, 1, 2),
+		on(TalkTrigger("tyrael1"), 4, 3),
+		on(TalkTrigger("jerhyn"), 5, 3, 4),
+		on(TalkTrigger("meshif1"), completing, 3, 4, 5),
+	),
+
+	// Act 3
+	quest(d2enum.Act3, 1, []Reward{{RewardLife, goldenBirdLife}}, // The Golden Bird
+		on(PickupTrigger("j34"), 1, notStarted),
+		on(TalkTrigger("meshif2"), 2, 1),
+		on(TalkTrigger("alkor"), 3, 2),
+		on(UseItemTrigger("xyz"), completing, 3),
+	),
+	quest(d2enum.Act3, 2, nil, // Blade of the Old Religion
+		on(TalkTrigger("hratli"), 1, notStarted),
+		on(PickupTrigger("g33"), 2, notStarted, 1),
+		on(TalkTrigger("ormus"), completing, 2),
+	),
+	quest(d2enum.Act3, 3, nil, // Khalim's Will
+		on(TalkTrigger("cain4"), 1, notStarted),
+		on(PickupTrigger("qf2"), 2, notStarted, 1),
+		on(UseObjectTrigger("CompellingOrb"), completing, 2),
+	),
+	quest(d2enum.Act3, 4, []Reward{{RewardStatPoints, lamEsenStatPoints}}, // Lam Esen's Tome
+		on(TalkTrigger("alkor"), 1, notStarted),
+		on(PickupTrigger("bbb"), 2, notStarted, 1),
+		on(TalkTrigger("alkor"), completing, 2),
+	),
+	quest(d2enum.Act3, 5, nil, // The Blackened Temple
+		on(EnterLevelTrigger(levelTravincal), 1, notStarted),
+		onShared(ClearLevelTrigger(levelTravincal), 2, notStarted, 1),
+		on(TalkTrigger("cain4"), completing, 2),
+	),
+	finalQuest(d2enum.Act3, 6, nil, // The Guardian
+		on(EnterLevelTrigger(levelDuranceOfHate3), 1, notStarted),
+		onShared(KillTrigger("mephisto"), 2, notStarted, 1),
+		on(EnterLevelTrigger(levelPandemoniumFortress), completing, 2),
+	),
+
+	// Act 4
+	quest(d2enum.Act4, 1, []Reward{{RewardSkillPoints, fallenAngelSkillPoints}}, // The Fallen Angel
+		on(TalkTrigger("tyrael2"), 1, notStarted),
+		onShared(KillTrigger("izual"), 2, notStarted, 1),
+		on(TalkTrigger("tyrael2"), completing, 2),
+	),
+	finalQuest(d2enum.Act4, 2, nil, // Terror's End
+		on(EnterLevelTrigger(levelChaosSanctuary), 1, notStarted),
+		onShared(KillTrigger("diablo"), completing, notStarted, 1),
+	),
+	quest(d2enum.Act4, 3, nil, // Hell's Forge
+		on(TalkTrigger("cain5"), 1, notStarted),
+		on(UseObjectTrigger("Hellforge"), completing, 1),
+	),
+
+	// Act 5
+	quest(d2enum.Act5, 1, []Reward{{RewardSocket, 1}}, // Siege on Harrogath
+		on(TalkTrigger("larzuk"), 1, notStarted),
+		onShared(KillTrigger("Shenk the Overseer"), 2, notStarted, 1),
+		on(TalkTrigger("larzuk"), completing, 2),
+	),
+	quest(d2enum.Act5, 2, nil, // Rescue on Mount Arreat
+		on(TalkTrigger("qual-kehk"), 1, notStarted),
+		onShared(UseObjectTrigger("BarbarianPrison"), 2, notStarted, 1),
+		on(TalkTrigger("qual-kehk"), completing, 2),
+	),
+	quest(d2enum.Act5, 3, []Reward{{RewardResistance, prisonOfIceResistance}}, // Prison of Ice
+		on(TalkTrigger("malah"), 1, notStarted),
+		on(EnterLevelTrigger(levelFrozenRiver), 2, notStarted, 1),
+		on(PickupTrigger("ice"), 3, 2),
+		on(UseObjectTrigger("FrozenAnya"), 4, 3),
+		on(TalkTrigger("drehya"), completing, 4),
+	),
+	quest(d2enum.Act5, 4, []Reward{{RewardPersonalize, 1}}, // Betrayal of Harrogath
+		on(TalkTrigger("drehya"), 1, notStarted),
+		onShared(KillTrigger("nihlathakboss"), 2, notStarted, 1),
+		on(TalkTrigger("drehya"), completing, 2),
+	),
+	quest(d2enum.Act5, 5, nil, // Rite of Passage
+		on(EnterLevelTrigger(levelArreatSummit), 1, notStarted),
+		onShared(ClearLevelTrigger(levelArreatSummit), completing, 1),
+	),
+	finalQuest(d2enum.Act5, 6, nil, // Eve of Destruction
+		on(EnterLevelTrigger(levelWorldstoneKeep1), 1, notStarted),
+		onShared(KillTrigger("baalcrab"), completing, notStarted, 1),
+	),
+}
@@ -0,0 +1,220 @@
+package d2quest
+
+import (
+	"strconv"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+// NewState creates the quest state of a new hero, with no quests started
+func NewState() *State {
+	return &State{
+		Statuses:  make(map[d2enum.DifficultyType][]int),
+		Unclaimed: make(map[d2enum.DifficultyType][]RewardType),
+	}
+}
+
+// State is the serializable quest state of a hero. The status of a quest is one of the
+// d2enum.QuestStatus values, or one of the in progress statuses of its state machine.
+type State struct {
+	// Statuses are the statuses of the quests, by difficulty and quest id
+	Statuses map[d2enum.DifficultyType][]int `json:"statuses"`
+	// Unclaimed are the service rewards which the hero has not used yet, by difficulty
+	Unclaimed map[d2enum.DifficultyType][]RewardType `json:"unclaimed"`
+}
+
+// Update is a change of the status of a quest
+type Update struct {
+	Quest     *Definition
+	Status    int
+	Completed bool
+}
+
+func (s *State) statuses(difficulty d2enum.DifficultyType) []int {
+	if s.Statuses == nil {
+		s.Statuses = make(map[d2enum.DifficultyType][]int)
+	}
+
+	statuses := s.Statuses[difficulty]
+	if len(statuses) < NumQuests {
+		// a save can be from before quests were added, the missing ones are not started
+		statuses = append(statuses, make([]int, NumQuests-len(statuses))...)
+		s.Statuses[difficulty] = statuses
+	}
+
+	return statuses
+}
+
+// Status returns the status of the quest
+func (s *State) Status(difficulty d2enum.DifficultyType, questID int) int {
+	if questID < 0 || questID >= NumQuests {
+		return d2enum.QuestStatusNotStarted
+	}
+
+	return s.statuses(difficulty)[questID]
+}
+
+// SetStatus sets the status of the quest
+func (s *State) SetStatus(difficulty d2enum.DifficultyType, questID, status int) {
+	if questID < 0 || questID >= NumQuests {
+		return
+	}
+
+	s.statuses(difficulty)[questID] = status
+}
+
+// IsCompleted returns true if the quest has been completed
+func (s *State) IsCompleted(difficulty d2enum.DifficultyType, questID int) bool {
+	status := s.Status(difficulty, questID)
+	return status == d2enum.QuestStatusCompleted || status == d2enum.QuestStatusCompleting
+}
+
+// MaxAct returns the highest act the hero can enter, on the given difficulty
+func (s *State) MaxAct(difficulty d2enum.DifficultyType) int {
+	act := d2enum.Act1
+
+	for _, quest := range questDefinitions {
+		if quest.FinishesAct && s.IsCompleted(difficulty, quest.ID) && act <= quest.Act {
+			act = quest.Act + 1
+		}
+	}
+
+	if act > d2enum.ActsNumber {
+		act = d2enum.ActsNumber
+	}
+
+	return act
+}
+
+// MaxDifficulty returns the highest difficulty the hero can play, a difficulty is unlocked by
+// finishing the last act of the difficulty before it
+func (s *State) MaxDifficulty() d2enum.DifficultyType {
+	difficulty := d2enum.DifficultyNormal
+
+	for difficulty < d2enum.DifficultyHell && s.finishedLastAct(difficulty) {
+		difficulty++
+	}
+
+	return difficulty
+}
+
+func (s *State) finishedLastAct(difficulty d2enum.DifficultyType) bool {
+	for _, quest := range questDefinitions {
+		if quest.FinishesAct && quest.Act == d2enum.ActsNumber {
+			return s.IsCompleted(difficulty, quest.ID)
+		}
+	}
+
+	return false
+}
+
+// Fire advances the quests of the given difficulty which have a step for the trigger. The quests
+// of acts which the hero can't enter yet are not advanced. If sharedOnly is true, only the steps
+// which are shared with the party are taken. Returns the quests which changed.
+func (s *State) Fire(difficulty d2enum.DifficultyType, trigger Trigger, sharedOnly bool) []Update {
+	if trigger.Type == TriggerQuestLogViewed {
+		return s.fireQuestLogViewed(difficulty, trigger)
+	}
+
+	updates := make([]Update, 0)
+	maxAct := s.MaxAct(difficulty)
+
+	for _, quest := range questDefinitions {
+		if quest.Act > maxAct {
+			continue
+		}
+
+		status := s.Status(difficulty, quest.ID)
+
+		for idx := range quest.steps {
+			step := &quest.steps[idx]
+
+			if (sharedOnly && !step.shared) || !step.appliesTo(status) || !step.trigger.Matches(trigger) {
+				continue
+			}
+
+			s.SetStatus(difficulty, quest.ID, step.to)
+
+			update := Update{Quest: quest, Status: step.to, Completed: step.to == d2enum.QuestStatusCompleting}
+			if update.Completed {
+				s.addUnclaimed(difficulty, quest.Rewards)
+			}
+
+			updates = append(updates, update)
+
+			break
+		}
+	}
+
+	return updates
+}
+
+// fireQuestLogViewed marks the completion animation of the quest as played
+func (s *State) fireQuestLogViewed(difficulty d2enum.DifficultyType, trigger Trigger) []Update {
+	questID, err := strconv.Atoi(trigger.Target)
+	if err != nil || s.Status(difficulty, questID) != d2enum.QuestStatusCompleting {
+		return nil
+	}
+
+	s.SetStatus(difficulty, questID, d2enum.QuestStatusCompleted)
+
+	return []Update{{Quest: Quest(questID), Status: d2enum.QuestStatusCompleted}}
+}
+
+func (s *State) addUnclaimed(difficulty d2enum.DifficultyType, rewards []Reward) {
+	if s.Unclaimed == nil {
+		s.Unclaimed = make(map[d2enum.DifficultyType][]RewardType)
+	}
+
+	for _, reward := range rewards {
+		if reward.Type.IsService() {
+			s.Unclaimed[difficulty] = append(s.Unclaimed[difficulty], reward.Type)
+		}
+	}
+}
+
+// HasUnclaimed returns true if the hero has not used the service reward yet
+func (s *State) HasUnclaimed(difficulty d2enum.DifficultyType, reward RewardType) bool {
+	for _, unclaimed := range s.Unclaimed[difficulty] {
+		if unclaimed == reward {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ClaimReward uses the service reward, returns false if the hero doesn't have it
+func (s *State) ClaimReward(difficulty d2enum.DifficultyType, reward RewardType) bool {
+	unclaimed := s.Unclaimed[difficulty]
+
+	for idx := range unclaimed {
+		if unclaimed[idx] == reward {
+			s.Unclaimed[difficulty] = append(unclaimed[:idx], unclaimed[idx+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// Resistance returns the bonus to all resistances from completed quests, of all difficulties
+func (s *State) Resistance() int {
+	total := 0
+
+	for difficulty := range s.Statuses {
+		for _, quest := range questDefinitions {
+			if !s.IsCompleted(difficulty, quest.ID) {
+				continue
+			}
+
+			for _, reward := range quest.Rewards {
+				if reward.Type == RewardResistance {
+					total += reward.Value
+				}
+			}
+		}
+	}
+
+	return total
+}
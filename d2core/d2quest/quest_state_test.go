@@ -0,0 +1,154 @@
+package d2quest
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+func TestQuestDefinitionIDs(t *testing.T) {
+	if len(Quests()) != NumQuests {
+		t.Fatalf("unexpected number of quests, want %d, have %d", NumQuests, len(Quests()))
+	}
+
+	for idx, quest := range Quests() {
+		if quest.ID != idx {
+			t.Errorf("quest %d of act %d has id %d, want %d", quest.Number, quest.Act, quest.ID, idx)
+		}
+	}
+}
+
+func TestStateFire(t *testing.T) {
+	const denOfEvil = 0
+
+	state := NewState()
+	normal := d2enum.DifficultyNormal
+
+	tests := []struct {
+		trigger    Trigger
+		sharedOnly bool
+		status     int
+		completed  bool
+	}{
+		{KillTrigger("andariel"), false, d2enum.QuestStatusNotStarted, false},
+		{TalkTrigger("akara"), true, d2enum.QuestStatusNotStarted, false},
+		{TalkTrigger("akara"), false, 1, false},
+		{ClearLevelTrigger(levelDenOfEvil), true, 2, false},
+		{TalkTrigger("Akara"), false, d2enum.QuestStatusCompleting, true},
+		{QuestLogViewedTrigger(denOfEvil), false, d2enum.QuestStatusCompleted, false},
+	}
+
+	for idx, test := range tests {
+		completed := false
+
+		for _, update := range state.Fire(normal, test.trigger, test.sharedOnly) {
+			if update.Quest.ID == denOfEvil {
+				completed = update.Completed
+			}
+		}
+
+		if status := state.Status(normal, denOfEvil); status != test.status {
+			t.Errorf("test %d: unexpected status, want %d, have %d", idx, test.status, status)
+		}
+
+		if completed != test.completed {
+			t.Errorf("test %d: unexpected completion, want %v, have %v", idx, test.completed, completed)
+		}
+	}
+
+	if state.Status(d2enum.DifficultyNightmare, denOfEvil) != d2enum.QuestStatusNotStarted {
+		t.Error("quests of other difficulties should not be affected")
+	}
+}
+
+func TestStateMaxAct(t *testing.T) {
+	state := NewState()
+	normal := d2enum.DifficultyNormal
+
+	// act 2 quests can't progress before act 1 is finished
+	state.Fire(normal, TalkTrigger("atma"), false)
+
+	if state.Status(normal, ID(d2enum.Act2, 1)) != d2enum.QuestStatusNotStarted {
+		t.Error("quest of a closed act should not progress")
+	}
+
+	state.SetStatus(normal, ID(d2enum.Act1, 6), d2enum.QuestStatusCompleted)
+
+	if act := state.MaxAct(normal); act != d2enum.Act2 {
+		t.Errorf("unexpected max act, want %d, have %d", d2enum.Act2, act)
+	}
+
+	state.Fire(normal, TalkTrigger("atma"), false)
+
+	if state.Status(normal, ID(d2enum.Act2, 1)) != 1 {
+		t.Error("quest of an open act should progress")
+	}
+}
+
+func TestStateMaxDifficulty(t *testing.T) {
+	const eveOfDestruction = 6
+
+	state := NewState()
+
+	if difficulty := state.MaxDifficulty(); difficulty != d2enum.DifficultyNormal {
+		t.Errorf("unexpected max difficulty, want %d, have %d", d2enum.DifficultyNormal, difficulty)
+	}
+
+	state.SetStatus(d2enum.DifficultyNormal, ID(d2enum.Act5, eveOfDestruction), d2enum.QuestStatusCompleted)
+
+	if difficulty := state.MaxDifficulty(); difficulty != d2enum.DifficultyNightmare {
+		t.Errorf("unexpected max difficulty, want %d, have %d", d2enum.DifficultyNightmare, difficulty)
+	}
+
+	// hell needs nightmare to be finished, not normal twice
+	state.SetStatus(d2enum.DifficultyHell, ID(d2enum.Act5, eveOfDestruction), d2enum.QuestStatusCompleted)
+
+	if difficulty := state.MaxDifficulty(); difficulty != d2enum.DifficultyNightmare {
+		t.Errorf("unexpected max difficulty, want %d, have %d", d2enum.DifficultyNightmare, difficulty)
+	}
+
+	state.SetStatus(d2enum.DifficultyNightmare, ID(d2enum.Act5, eveOfDestruction), d2enum.QuestStatusCompleting)
+
+	if difficulty := state.MaxDifficulty(); difficulty != d2enum.DifficultyHell {
+		t.Errorf("unexpected max difficulty, want %d, have %d", d2enum.DifficultyHell, difficulty)
+	}
+}
+
+func TestStateRewards(t *testing.T) {
+	state := NewState()
+	hell := d2enum.DifficultyHell
+
+	state.SetStatus(hell, ID(d2enum.Act1, 3), 2)
+	state.Fire(hell, TalkTrigger("charsi"), false)
+
+	if !state.ClaimReward(hell, RewardImbue) || state.ClaimReward(hell, RewardImbue) {
+		t.Error("expected the imbue reward to be claimable once")
+	}
+
+	state.SetStatus(d2enum.DifficultyNormal, ID(d2enum.Act5, 3), d2enum.QuestStatusCompleted)
+	state.SetStatus(hell, ID(d2enum.Act5, 3), d2enum.QuestStatusCompleting)
+
+	if res := state.Resistance(); res != 2*prisonOfIceResistance {
+		t.Errorf("unexpected resistance bonus, want %d, have %d", 2*prisonOfIceResistance, res)
+	}
+}
+
+func TestStateJSON(t *testing.T) {
+	state := NewState()
+	state.SetStatus(d2enum.DifficultyNightmare, ID(d2enum.Act3, 4), 2)
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := &State{}
+	if err := json.Unmarshal(data, loaded); err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded.Status(d2enum.DifficultyNightmare, ID(d2enum.Act3, 4)) != 2 {
+		t.Error("status was not restored")
+	}
+}
@@ -0,0 +1,82 @@
+package d2quest
+
+import (
+	"strconv"
+	"strings"
+)
+
+// TriggerType is the kind of event which can advance a quest
+type TriggerType int
+
+// Trigger types
+const (
+	// TriggerTalkToNPC happens when the hero talks to the npc, target is the monstats.txt id
+	TriggerTalkToNPC TriggerType = iota
+	// TriggerEnterLevel happens when the hero enters the level, target is the levels.txt id
+	TriggerEnterLevel
+	// TriggerClearLevel happens when all monsters of the level are killed, target is the levels.txt id
+	TriggerClearLevel
+	// TriggerKillMonster happens when the monster is killed, target is the monstats.txt id
+	// or the superuniques.txt name
+	TriggerKillMonster
+	// TriggerPickupItem happens when the hero picks up the item, target is the item code
+	TriggerPickupItem
+	// TriggerUseItem happens when the hero uses the item, target is the item code
+	TriggerUseItem
+	// TriggerUseObject happens when the hero operates the object, target is the objects.txt name
+	TriggerUseObject
+	// TriggerQuestLogViewed happens when the completion of the quest was shown in the quest log,
+	// target is the quest id
+	TriggerQuestLogViewed
+)
+
+// Trigger is an event which can advance the quests of a hero
+type Trigger struct {
+	Type   TriggerType `json:"type"`
+	Target string      `json:"target"`
+}
+
+// Matches returns true if both triggers are the same event
+func (t Trigger) Matches(other Trigger) bool {
+	return t.Type == other.Type && strings.EqualFold(t.Target, other.Target)
+}
+
+// TalkTrigger returns the trigger for talking to the npc with the given monstats.txt id
+func TalkTrigger(npc string) Trigger {
+	return Trigger{Type: TriggerTalkToNPC, Target: npc}
+}
+
+// EnterLevelTrigger returns the trigger for entering the level with the given levels.txt id
+func EnterLevelTrigger(levelID int) Trigger {
+	return Trigger{Type: TriggerEnterLevel, Target: strconv.Itoa(levelID)}
+}
+
+// ClearLevelTrigger returns the trigger for killing all monsters of the level with the given levels.txt id
+func ClearLevelTrigger(levelID int) Trigger {
+	return Trigger{Type: TriggerClearLevel, Target: strconv.Itoa(levelID)}
+}
+
+// KillTrigger returns the trigger for killing the given monster
+func KillTrigger(monster string) Trigger {
+	return Trigger{Type: TriggerKillMonster, Target: monster}
+}
+
+// PickupTrigger returns the trigger for picking up the item with the given code
+func PickupTrigger(itemCode string) Trigger {
+	return Trigger{Type: TriggerPickupItem, Target: itemCode}
+}
+
+// UseItemTrigger returns the trigger for using the item with the given code
+func UseItemTrigger(itemCode string) Trigger {
+	return Trigger{Type: TriggerUseItem, Target: itemCode}
+}
+
+// UseObjectTrigger returns the trigger for operating the given object
+func UseObjectTrigger(object string) Trigger {
+	return Trigger{Type: TriggerUseObject, Target: object}
+}
+
+// QuestLogViewedTrigger returns the trigger for having seen the completion of the quest in the quest log
+func QuestLogViewedTrigger(questID int) Trigger {
+	return Trigger{Type: TriggerQuestLogViewed, Target: strconv.Itoa(questID)}
+}
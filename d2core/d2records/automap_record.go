@@ -48,3 +48,25 @@ type AutoMapRecord struct {
 
 // AutoMaps contains all data in AutoMap.txt.
 type AutoMaps []*AutoMapRecord
+
+// Matches returns true if the record covers the tiles of the given style and sequence
+func (record *AutoMapRecord) Matches(style, sequence int) bool {
+	if record.Style != style {
+		return false
+	}
+
+	return record.StartSequence == -1 || (sequence >= record.StartSequence && sequence <= record.EndSequence)
+}
+
+// Cells returns the frames of the record which are set, the game chooses between them
+func (record *AutoMapRecord) Cells() []int {
+	cells := make([]int, 0, len(record.Frames))
+
+	for _, frame := range record.Frames {
+		if frame >= 0 {
+			cells = append(cells, frame)
+		}
+	}
+
+	return cells
+}
@@ -8,8 +8,9 @@ import (
 func beltsLoader(r *RecordManager, d *d2txt.DataDictionary) error {
 	records := make(Belts)
 
-	for d.Next() {
+	for index := 0; d.Next(); index++ {
 		record := &BeltRecord{
+			Index:     index,
 			Name:      d.String("name"),
 			NumBoxes:  d.Number("numboxes"),
 			BoxWidth:  d.Number("boxwidth"),
@@ -5,6 +5,8 @@ type Belts map[string]*BeltRecord
 
 // BeltRecord is a representation of the belt ui-panel dimensions/positioning
 type BeltRecord struct {
+	// Index is the row of the record, the "belt" column of armor.txt refers to it
+	Index     int
 	Name      string
 	NumBoxes  int
 	BoxWidth  int
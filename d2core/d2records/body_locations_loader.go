@@ -16,7 +16,7 @@ func bodyLocationsLoader(r *RecordManager, d *d2txt.DataDictionary) error {
 	}
 
 	if d.Err != nil {
-		panic(d.Err)
+		return d.Err
 	}
 
 	r.Debugf("Loaded %d BodyLocation records", len(records))
@@ -0,0 +1,20 @@
+package d2records
+
+import "github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2txt"
+
+// Encode writes the record into a row of books.txt
+func (record *BookRecord) Encode(row *d2txt.Row) error {
+	return encodeRecord(row,
+		stringField("Name", record.Name),
+		stringField("Namco", record.Namco),
+		stringField("Completed", record.Completed),
+		stringField("ScrollSpellCode", record.ScrollSpellCode),
+		stringField("BooksSpellCode", record.BookSpellCode),
+		numberField("pSpell", record.Pspell),
+		numberField("SpellIcon", record.SpellIcon),
+		stringField("ScrollSkill", record.ScrollSkill),
+		stringField("BookSkill", record.BookSkill),
+		numberField("BaseCost", record.BaseCost),
+		numberField("CostPerCharge", record.CostPerCharge),
+	)
+}
@@ -25,7 +25,7 @@ func booksLoader(r *RecordManager, d *d2txt.DataDictionary) error {
 	}
 
 	if d.Err != nil {
-		panic(d.Err)
+		return d.Err
 	}
 
 	r.Debugf("Loaded %d Book records", len(records))
@@ -17,7 +17,7 @@ func cubeModifierLoader(r *RecordManager, d *d2txt.DataDictionary) error {
 	}
 
 	if d.Err != nil {
-		panic(d.Err)
+		return d.Err
 	}
 
 	r.Item.Cube.Modifiers = records
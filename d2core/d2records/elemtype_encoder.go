@@ -0,0 +1,11 @@
+package d2records
+
+import "github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2txt"
+
+// Encode writes the record into a row of ElemTypes.txt
+func (record *ElemTypeRecord) Encode(row *d2txt.Row) error {
+	return encodeRecord(row,
+		stringField("Elemental Type", record.ElemType),
+		stringField("Code", record.Code),
+	)
+}
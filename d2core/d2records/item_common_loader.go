@@ -90,6 +90,7 @@ func loadCommonItems(d *d2txt.DataDictionary, source d2enum.InventoryItemType) (
 			Quivered:    d.Number("quivered") > 0,
 			LightRadius: d.Number("lightradius"),
 			Belt:        d.Number("belt") > 0,
+			BeltType:    d.Number("belt"),
 
 			Quest: d.Number("quest"),
 
@@ -221,9 +222,10 @@ func createItemVendorParams(d *d2txt.DataDictionary) map[string]*ItemVendorParam
 
 func createItemUsageStats(d *d2txt.DataDictionary) [3]ItemUsageStat {
 	result := [3]ItemUsageStat{}
+	// the columns are stat1 to stat3
 	for i := 0; i < 3; i++ {
-		result[i].Stat = d.String("stat" + strconv.Itoa(i))
-		result[i].Calc = d2calculation.CalcString(d.String("calc" + strconv.Itoa(i)))
+		result[i].Stat = d.String("stat" + strconv.Itoa(i+1))
+		result[i].Calc = d2calculation.CalcString(d.String("calc" + strconv.Itoa(i+1)))
 	}
 
 	return result
@@ -16,7 +16,7 @@ func lowQualityLoader(r *RecordManager, d *d2txt.DataDictionary) error {
 	}
 
 	if d.Err != nil {
-		panic(d.Err)
+		return d.Err
 	}
 
 	r.Item.LowQualityPrefixes = records
@@ -360,3 +360,65 @@ type LevelDetailRecord struct {
 	MonsterPreferRanged bool // rangedspawn
 
 }
+
+// MonsterIDs returns the ids of the monsters that spawn in the level on the given difficulty, the
+// normal columns are used for unknown difficulties
+func (record *LevelDetailRecord) MonsterIDs(difficulty d2enum.DifficultyType) []string {
+	var ids [10]string
+
+	switch difficulty {
+	case d2enum.DifficultyNightmare:
+		ids = [...]string{record.MonsterID1Nightmare, record.MonsterID2Nightmare, record.MonsterID3Nightmare,
+			record.MonsterID4Nightmare, record.MonsterID5Nightmare, record.MonsterID6Nightmare,
+			record.MonsterID7Nightmare, record.MonsterID8Nightmare, record.MonsterID9Nightmare,
+			record.MonsterID10Nightmare}
+	case d2enum.DifficultyHell:
+		ids = [...]string{record.MonsterID1Hell, record.MonsterID2Hell, record.MonsterID3Hell,
+			record.MonsterID4Hell, record.MonsterID5Hell, record.MonsterID6Hell, record.MonsterID7Hell,
+			record.MonsterID8Hell, record.MonsterID9Hell, record.MonsterID10Hell}
+	default:
+		ids = [...]string{record.MonsterID1Normal, record.MonsterID2Normal, record.MonsterID3Normal,
+			record.MonsterID4Normal, record.MonsterID5Normal, record.MonsterID6Normal,
+			record.MonsterID7Normal, record.MonsterID8Normal, record.MonsterID9Normal,
+			record.MonsterID10Normal}
+	}
+
+	result := make([]string, 0, len(ids))
+
+	for _, id := range ids {
+		if id != "" {
+			result = append(result, id)
+		}
+	}
+
+	return result
+}
+
+// MonsterLevel returns the level of the monsters in the level on the given difficulty, the
+// normal column is used for unknown difficulties
+func (record *LevelDetailRecord) MonsterLevel(difficulty d2enum.DifficultyType) int {
+	switch difficulty {
+	case d2enum.DifficultyNightmare:
+		return record.MonsterLevelNightmare
+	case d2enum.DifficultyHell:
+		return record.MonsterLevelHell
+	}
+
+	return record.MonsterLevelNormal
+}
+
+// LinkedLevels returns the ids of the levels the level is linked with, by the Vis columns
+func (record *LevelDetailRecord) LinkedLevels() []int {
+	links := [...]int{record.LevelLinkID0, record.LevelLinkID1, record.LevelLinkID2, record.LevelLinkID3,
+		record.LevelLinkID4, record.LevelLinkID5, record.LevelLinkID6, record.LevelLinkID7}
+
+	result := make([]int, 0, len(links))
+
+	for _, link := range links {
+		if link > 0 {
+			result = append(result, link)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,16 @@
+package d2records
+
+import "github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2txt"
+
+// Encode writes the record into a row of LvlMaze.txt
+func (record *LevelMazeDetailRecord) Encode(row *d2txt.Row) error {
+	return encodeRecord(row,
+		stringField("Name", record.Name),
+		numberField("Level", record.LevelID),
+		numberField("Rooms", record.NumRoomsNormal),
+		numberField("Rooms(N)", record.NumRoomsNightmare),
+		numberField("Rooms(H)", record.NumRoomsHell),
+		numberField("SizeX", record.SizeX),
+		numberField("SizeY", record.SizeY),
+	)
+}
@@ -1,5 +1,10 @@
 package d2records
 
+import (
+	"fmt"
+	"strings"
+)
+
 // LevelTypes stores all of the LevelTypeRecords
 type LevelTypes []*LevelTypeRecord
 
@@ -13,3 +18,14 @@ type LevelTypeRecord struct {
 	Beta      bool
 	Expansion bool
 }
+
+// AutoMapName returns the name of the level type in AutoMap.txt, the act followed by the name
+// of the level type without the act. For example, "Act 1 - Town" is "1 Town".
+func (record *LevelTypeRecord) AutoMapName() string {
+	name := record.Name
+	if idx := strings.Index(name, " - "); idx >= 0 {
+		name = name[idx+len(" - "):]
+	}
+
+	return fmt.Sprintf("%d %s", record.Act, name)
+}
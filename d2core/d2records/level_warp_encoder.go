@@ -0,0 +1,22 @@
+package d2records
+
+import "github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2txt"
+
+// Encode writes the record into a row of LvlWarp.txt
+func (record *LevelWarpRecord) Encode(row *d2txt.Row) error {
+	return encodeRecord(row,
+		stringField("Name", record.Name),
+		numberField("Id", record.ID),
+		numberField("SelectX", record.SelectX),
+		numberField("SelectY", record.SelectY),
+		numberField("SelectDX", record.SelectDX),
+		numberField("SelectDY", record.SelectDY),
+		numberField("ExitWalkX", record.ExitWalkX),
+		numberField("ExitWalkY", record.ExitWalkY),
+		numberField("OffsetX", record.OffsetX),
+		numberField("OffsetY", record.OffsetY),
+		boolField("LitVersion", record.LitVersion),
+		numberField("Tiles", record.Tiles),
+		stringField("Direction", record.Direction),
+	)
+}
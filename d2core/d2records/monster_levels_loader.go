@@ -1,5 +1,7 @@
 package d2records
 
+import "github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+
 // MonsterLevels stores the MonsterLevelRecords
 type MonsterLevels map[int]*MonsterLevelRecord
 
@@ -47,3 +49,16 @@ type monsterLevelValues struct {
 	// the formula is (MonLvl.txt XP * Monstats.txt Exp) / 100
 	Experience int
 }
+
+// Experience returns the experience of the monster level on the given difficulty, the
+// single player columns are used
+func (r *MonsterLevelRecord) Experience(difficulty d2enum.DifficultyType) int {
+	switch difficulty {
+	case d2enum.DifficultyNightmare:
+		return r.Ladder.Nightmare.Experience
+	case d2enum.DifficultyHell:
+		return r.Ladder.Hell.Experience
+	default:
+		return r.Ladder.Normal.Experience
+	}
+}
@@ -158,7 +158,7 @@ func monsterStats2Loader(r *RecordManager, d *d2txt.DataDictionary) error {
 	}
 
 	if d.Err != nil {
-		panic(d.Err)
+		return d.Err
 	}
 
 	r.Debugf("Loaded %d MonStat2 records", len(records))
@@ -0,0 +1,153 @@
+package d2records
+
+import "github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+
+// MonsterTreasureClass is the kind of monster a treasure class of monstats.txt is dropped by
+type MonsterTreasureClass int
+
+// Monster treasure classes
+const (
+	// MonsterTreasureClassRegular is dropped by regular monsters
+	MonsterTreasureClassRegular MonsterTreasureClass = iota
+	// MonsterTreasureClassChampion is dropped by champions
+	MonsterTreasureClassChampion
+	// MonsterTreasureClassUnique is dropped by uniques and their minions
+	MonsterTreasureClassUnique
+	// MonsterTreasureClassQuest is dropped by quest monsters while their quest is active
+	MonsterTreasureClassQuest
+
+	numMonsterTreasureClasses
+)
+
+// MonsterDifficultyStats are the columns of a monstats.txt record which have a value for each
+// difficulty, taken from the columns of a single difficulty
+type MonsterDifficultyStats struct {
+	Level            int
+	MinHP            int
+	MaxHP            int
+	ArmorClass       int
+	Experience       int
+	ChanceToBlock    int
+	LeechSensitivity int
+	ColdSensitivity  int
+
+	// the damage and attack rating of the first attack
+	DamageMin    int
+	DamageMax    int
+	AttackRating int
+
+	ResistancePhysical  int
+	ResistanceMagic     int
+	ResistanceFire      int
+	ResistanceLightning int
+	ResistanceCold      int
+	ResistancePoison    int
+
+	TreasureClasses [numMonsterTreasureClasses]string
+}
+
+// TreasureClass returns the treasure class dropped by the given kind of monster
+func (s *MonsterDifficultyStats) TreasureClass(kind MonsterTreasureClass) string {
+	if kind < 0 || kind >= numMonsterTreasureClasses {
+		return ""
+	}
+
+	return s.TreasureClasses[kind]
+}
+
+// Stats returns the stats of the monster on the given difficulty, the normal columns are used
+// for unknown difficulties
+func (record *MonStatRecord) Stats(difficulty d2enum.DifficultyType) *MonsterDifficultyStats {
+	switch difficulty {
+	case d2enum.DifficultyNightmare:
+		return &MonsterDifficultyStats{
+			Level:               record.LevelNightmare,
+			MinHP:               record.MinHPNightmare,
+			MaxHP:               record.MaxHPNightmare,
+			ArmorClass:          record.ArmorClassNightmare,
+			Experience:          record.ExperienceNightmare,
+			ChanceToBlock:       record.ChanceToBlockNightmare,
+			DamageMin:           record.DamageMinA1Nightmare,
+			DamageMax:           record.DamageMaxA1Nightmare,
+			AttackRating:        record.AttackRatingA1Nightmare,
+			LeechSensitivity:    record.LeechSensitivityNightmare,
+			ColdSensitivity:     record.ColdSensitivityNightmare,
+			ResistancePhysical:  record.ResistancePhysicalNightmare,
+			ResistanceMagic:     record.ResistanceMagicNightmare,
+			ResistanceFire:      record.ResistanceFireNightmare,
+			ResistanceLightning: record.ResistanceLightningNightmare,
+			ResistanceCold:      record.ResistanceColdNightmare,
+			ResistancePoison:    record.ResistancePoisonNightmare,
+			TreasureClasses: [numMonsterTreasureClasses]string{
+				record.TreasureClassNightmare,
+				record.TreasureClassChampionNightmare,
+				record.TreasureClass3UniqueNightmare,
+				record.TreasureClassQuestNightmare,
+			},
+		}
+	case d2enum.DifficultyHell:
+		return &MonsterDifficultyStats{
+			Level:               record.LevelHell,
+			MinHP:               record.MinHPHell,
+			MaxHP:               record.MaxHPHell,
+			ArmorClass:          record.ArmorClassHell,
+			Experience:          record.ExperienceHell,
+			ChanceToBlock:       record.ChanceToBlockHell,
+			DamageMin:           record.DamageMinA1Hell,
+			DamageMax:           record.DamageMaxA1Hell,
+			AttackRating:        record.AttackRatingA1Hell,
+			LeechSensitivity:    record.LeechSensitivityHell,
+			ColdSensitivity:     record.ColdSensitivityHell,
+			ResistancePhysical:  record.ResistancePhysicalHell,
+			ResistanceMagic:     record.ResistanceMagicHell,
+			ResistanceFire:      record.ResistanceFireHell,
+			ResistanceLightning: record.ResistanceLightningHell,
+			ResistanceCold:      record.ResistanceColdHell,
+			ResistancePoison:    record.ResistancePoisonHell,
+			TreasureClasses: [numMonsterTreasureClasses]string{
+				record.TreasureClassHell,
+				record.TreasureClassChampionHell,
+				record.TreasureClass3UniqueHell,
+				record.TreasureClassQuestHell,
+			},
+		}
+	}
+
+	return &MonsterDifficultyStats{
+		Level:               record.LevelNormal,
+		MinHP:               record.MinHPNormal,
+		MaxHP:               record.MaxHPNormal,
+		ArmorClass:          record.ArmorClassNormal,
+		Experience:          record.ExperienceNormal,
+		ChanceToBlock:       record.ChanceToBlockNormal,
+		DamageMin:           record.DamageMinA1Normal,
+		DamageMax:           record.DamageMaxA1Normal,
+		AttackRating:        record.AttackRatingA1Normal,
+		LeechSensitivity:    record.LeechSensitivityNormal,
+		ColdSensitivity:     record.ColdSensitivityNormal,
+		ResistancePhysical:  record.ResistancePhysicalNormal,
+		ResistanceMagic:     record.ResistanceMagicNormal,
+		ResistanceFire:      record.ResistanceFireNormal,
+		ResistanceLightning: record.ResistanceLightningNormal,
+		ResistanceCold:      record.ResistanceColdNormal,
+		ResistancePoison:    record.ResistancePoisonNormal,
+		TreasureClasses: [numMonsterTreasureClasses]string{
+			record.TreasureClassNormal,
+			record.TreasureClassChampionNormal,
+			record.TreasureClass3UniqueNormal,
+			record.TreasureClassQuestNormal,
+		},
+	}
+}
+
+// Palette returns the palette index of the monster on the given difficulty
+func (record *MonStat2Record) Palette(difficulty d2enum.DifficultyType) int {
+	switch difficulty {
+	case d2enum.DifficultyNightmare:
+		return record.NightmarePalette
+	case d2enum.DifficultyHell:
+		return record.HellPalatte
+	}
+
+	return record.NormalPalette
+}
@@ -20,7 +20,7 @@ func monsterTypesLoader(r *RecordManager, d *d2txt.DataDictionary) error {
 	}
 
 	if d.Err != nil {
-		panic(d.Err)
+		return d.Err
 	}
 
 	r.Debugf("Loaded %d MonType records", len(records))
@@ -21,7 +21,7 @@ func playerTypeLoader(r *RecordManager, d *d2txt.DataDictionary) error {
 	}
 
 	if d.Err != nil {
-		panic(d.Err)
+		return d.Err
 	}
 
 	r.Debugf("Loaded %d PlayerType records", len(records))
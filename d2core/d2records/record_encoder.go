@@ -0,0 +1,36 @@
+package d2records
+
+import "github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2txt"
+
+// fieldEncoder writes a field of a record into a row of its data dictionary
+type fieldEncoder func(row *d2txt.Row) error
+
+func stringField(field, value string) fieldEncoder {
+	return func(row *d2txt.Row) error {
+		return row.SetString(field, value)
+	}
+}
+
+func numberField(field string, value int) fieldEncoder {
+	return func(row *d2txt.Row) error {
+		return row.SetNumber(field, value)
+	}
+}
+
+func boolField(field string, value bool) fieldEncoder {
+	return func(row *d2txt.Row) error {
+		return row.SetBool(field, value)
+	}
+}
+
+// encodeRecord writes the fields of a record into a row, fields which keep their value are left
+// untouched so that the row is written back as it was read
+func encodeRecord(row *d2txt.Row, fields ...fieldEncoder) error {
+	for _, field := range fields {
+		if err := field(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package d2records
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2txt"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2util"
+)
+
+func TestLevelWarpEncode(t *testing.T) {
+	const data = "Name\tId\tSelectX\tSelectY\tSelectDX\tSelectDY\tExitWalkX\tExitWalkY\tOffsetX\tOffsetY\t" +
+		"LitVersion\tTiles\tDirection\r\n" +
+		"Act 1 - Cave Up\t1\t-16\t-70\t32\t70\t0\t0\t-16\t-70\t1\t4\tl\r\n" +
+		"Expansion\r\n" +
+		"Act 5 - Ice Up\t2\t\t\t\t\t\t\t\t\t0\t\tr\r\n"
+
+	r, err := NewRecordManager(d2util.LogLevelDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dict, err := d2txt.LoadDataDictionary([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := levelWarpsLoader(r, dict); err != nil {
+		t.Fatal(err)
+	}
+
+	// records that didn't change are written back as they were read
+	for id, record := range r.Level.Warp {
+		if err := record.Encode(dict.Find("Id", strconv.Itoa(id))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if have := string(dict.Marshal()); have != data {
+		t.Errorf("want %q, have %q", data, have)
+	}
+
+	r.Level.Warp[2].OffsetX = 8
+	r.Level.Warp[2].LitVersion = true
+
+	if err := r.Level.Warp[2].Encode(dict.Find("Id", "2")); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Act 5 - Ice Up\t2\t\t\t\t\t\t\t8\t\t1\t\tr"
+	if have := dict.Find("Id", "2"); have == nil || strings.Join(have.Fields, "\t") != want {
+		t.Errorf("want %q, have %v", want, have)
+	}
+}
@@ -457,3 +457,26 @@ func (r *RecordManager) GetSkillByName(skillName string) *SkillRecord {
 func (r *RecordManager) GetMissileByName(missileName string) *MissileRecord {
 	return r.missilesByName[sanitizeMissilesKey(missileName)]
 }
+
+// GetBeltByIndex returns the belt record with the given index, this is the
+// value of the "belt" column of armor.txt
+func (r *RecordManager) GetBeltByIndex(index int) *BeltRecord {
+	for name := range r.Item.Belts {
+		if r.Item.Belts[name].Index == index {
+			return r.Item.Belts[name]
+		}
+	}
+
+	return nil
+}
+
+// GetMonStatByIndex returns the monstats.txt record with the given hcIdx
+func (r *RecordManager) GetMonStatByIndex(index int) *MonStatRecord {
+	for key := range r.Monster.Stats {
+		if r.Monster.Stats[key].ID == index {
+			return r.Monster.Stats[key]
+		}
+	}
+
+	return nil
+}
@@ -91,9 +91,11 @@ type SetItemRecord struct {
 	// Properties are a propert code, parameter, min, max for generating an item propert
 	Properties [numPropertiesOnSetItem]*SetItemProperty
 
-	// SetPropertiesLevel1 is the first version of bonus properties for the set
+	// SetPropertiesLevel1 is the first version of bonus properties for the set (aprop#a),
+	// SetPropertiesLevel1[n] is granted when n+2 items of the set are equipped
 	SetPropertiesLevel1 [numBonusPropertiesOnSetItem]*SetItemProperty
 
-	// SetPropertiesLevel2 is the second version of bonus properties for the set
+	// SetPropertiesLevel2 is the second version of bonus properties for the set (aprop#b),
+	// SetPropertiesLevel2[n] is granted when n+2 items of the set are equipped
 	SetPropertiesLevel2 [numBonusPropertiesOnSetItem]*SetItemProperty
 }
@@ -48,35 +48,33 @@ func setLoader(r *RecordManager, d *d2txt.DataDictionary) error {
 			columnA := fmt.Sprintf(fmtPropCode, setPartialToken, num, setPartialTokenA)
 			columnB := fmt.Sprintf(fmtPropCode, setPartialToken, num, setPartialTokenB)
 
-			if codeA := d.String(columnA); codeA != "" {
-				paramColumn := fmt.Sprintf(fmtPropParam, setPartialToken, num, setPartialTokenA)
-				minColumn := fmt.Sprintf(fmtPropMin, setPartialToken, num, setPartialTokenA)
-				maxColumn := fmt.Sprintf(fmtPropMax, setPartialToken, num, setPartialTokenA)
-
-				propA := &SetProperty{
-					Code:      codeA,
-					Parameter: d.String(paramColumn),
-					Min:       d.Number(minColumn),
-					Max:       d.Number(maxColumn),
-				}
-
-				record.Properties.PartialA = append(record.Properties.PartialA, propA)
+			// the partial properties are always appended, even when the code is empty,
+			// so that the slice index tells how many set items are needed for the bonus
+			paramColumnA := fmt.Sprintf(fmtPropParam, setPartialToken, num, setPartialTokenA)
+			minColumnA := fmt.Sprintf(fmtPropMin, setPartialToken, num, setPartialTokenA)
+			maxColumnA := fmt.Sprintf(fmtPropMax, setPartialToken, num, setPartialTokenA)
+
+			propA := &SetProperty{
+				Code:      d.String(columnA),
+				Parameter: d.String(paramColumnA),
+				Min:       d.Number(minColumnA),
+				Max:       d.Number(maxColumnA),
 			}
 
-			if codeB := d.String(columnB); codeB != "" {
-				paramColumn := fmt.Sprintf(fmtPropParam, setPartialToken, num, setPartialTokenB)
-				minColumn := fmt.Sprintf(fmtPropMin, setPartialToken, num, setPartialTokenB)
-				maxColumn := fmt.Sprintf(fmtPropMax, setPartialToken, num, setPartialTokenB)
+			record.Properties.PartialA = append(record.Properties.PartialA, propA)
 
-				propB := &SetProperty{
-					Code:      codeB,
-					Parameter: d.String(paramColumn),
-					Min:       d.Number(minColumn),
-					Max:       d.Number(maxColumn),
-				}
+			paramColumnB := fmt.Sprintf(fmtPropParam, setPartialToken, num, setPartialTokenB)
+			minColumnB := fmt.Sprintf(fmtPropMin, setPartialToken, num, setPartialTokenB)
+			maxColumnB := fmt.Sprintf(fmtPropMax, setPartialToken, num, setPartialTokenB)
 
-				record.Properties.PartialB = append(record.Properties.PartialB, propB)
+			propB := &SetProperty{
+				Code:      d.String(columnB),
+				Parameter: d.String(paramColumnB),
+				Min:       d.Number(minColumnB),
+				Max:       d.Number(maxColumnB),
 			}
+
+			record.Properties.PartialB = append(record.Properties.PartialB, propB)
 		}
 
 		for idx := 0; idx < numFullSetProperties; idx++ {
@@ -23,6 +23,8 @@ type SetRecord struct {
 	Level int
 
 	// Properties contains the partial and full set bonus properties.
+	// PartialA[n] and PartialB[n] are granted when n+2 items of the set are equipped,
+	// entries with an empty code are placeholders for unused columns.
 	Properties struct {
 		PartialA []*SetProperty
 		PartialB []*SetProperty
@@ -0,0 +1,20 @@
+package d2records
+
+import "github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2txt"
+
+// Encode writes the record into a row of shrines.txt
+func (record *ShrineRecord) Encode(row *d2txt.Row) error {
+	return encodeRecord(row,
+		stringField("Shrine Type", record.ShrineType),
+		stringField("Shrine name", record.ShrineName),
+		stringField("Effect", record.Effect),
+		numberField("Code", record.Code),
+		numberField("Arg0", record.Arg0),
+		numberField("Arg1", record.Arg1),
+		numberField("Duration in frames", record.DurationFrames),
+		numberField("reset time in minutes", record.ResetTimeMinutes),
+		numberField("rarity", record.Rarity),
+		numberField("effectclass", record.EffectClass),
+		numberField("LevelMin", record.LevelMin),
+	)
+}
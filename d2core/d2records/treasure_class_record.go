@@ -23,3 +23,21 @@ type Treasure struct {
 	Code        string
 	Probability int
 }
+
+// Upgrade returns the treasure class which replaces the named one for a monster of the given
+// level. This is the treasure class of the same group with the highest level that isn't
+// above the monster level, treasure classes without a group are never upgraded.
+func (tc TreasureClass) Upgrade(name string, level int) *TreasureClassRecord {
+	result := tc[name]
+	if result == nil || result.Group == 0 || level <= result.Level {
+		return result
+	}
+
+	for _, record := range tc {
+		if record.Group == result.Group && record.Level > result.Level && record.Level <= level {
+			result = record
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,39 @@
+package d2records
+
+import (
+	"testing"
+)
+
+func TestTreasureClassUpgrade(t *testing.T) {
+	treasureClasses := TreasureClass{
+		"Act 1 H2H A": {Name: "Act 1 H2H A", Group: 1, Level: 1},
+		"Act 1 H2H B": {Name: "Act 1 H2H B", Group: 1, Level: 5},
+		"Act 1 H2H C": {Name: "Act 1 H2H C", Group: 1, Level: 9},
+		"Act 2 H2H A": {Name: "Act 2 H2H A", Group: 2, Level: 12},
+		"Act 1 Junk":  {Name: "Act 1 Junk"},
+	}
+
+	tests := []struct {
+		name  string
+		level int
+		want  string
+	}{
+		{"Act 1 H2H A", 1, "Act 1 H2H A"},
+		{"Act 1 H2H A", 4, "Act 1 H2H A"},
+		{"Act 1 H2H A", 5, "Act 1 H2H B"},
+		{"Act 1 H2H A", 30, "Act 1 H2H C"},
+		{"Act 1 H2H C", 2, "Act 1 H2H C"},
+		{"Act 1 Junk", 30, "Act 1 Junk"},
+	}
+
+	for _, test := range tests {
+		record := treasureClasses.Upgrade(test.name, test.level)
+		if record == nil || record.Name != test.want {
+			t.Errorf("%s at level %d: want %s, have %+v", test.name, test.level, test.want, record)
+		}
+	}
+
+	if record := treasureClasses.Upgrade("unknown", 1); record != nil {
+		t.Errorf("want no treasure class for an unknown name, have %+v", record)
+	}
+}
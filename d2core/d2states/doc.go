@@ -0,0 +1,3 @@
+// Package d2states provides the timed states of entities from states.txt, like buffs, auras, curses,
+// poison and freeze, and the rules for stacking and removing them.
+package d2states
@@ -0,0 +1,60 @@
+package d2states
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2records"
+)
+
+// Modifier changes a stat of the entity while the state is active, the stat is
+// an itemstatcost.txt stat name
+type Modifier struct {
+	Stat  string `json:"stat"`
+	Value int    `json:"value"`
+}
+
+// State is a states.txt state active on an entity
+type State struct {
+	// Name is the states.txt name of the state
+	Name string `json:"name"`
+	// Source is the id of the entity which applied the state
+	Source string `json:"source"`
+	// Duration in seconds, zero for states which last until they are removed, like auras
+	Duration float64 `json:"duration"`
+	// Remaining is the time in seconds until the state expires
+	Remaining float64 `json:"remaining"`
+	// Stacks is the number of times the state was applied by the source without expiring
+	Stacks    int        `json:"stacks"`
+	Modifiers []Modifier `json:"modifiers"`
+
+	record *d2records.StateRecord
+}
+
+// NewState creates a state with the given name, applied by the source for the duration
+// in seconds. A zero duration means the state lasts until it is removed.
+func NewState(name, source string, duration float64, modifiers ...Modifier) *State {
+	return &State{
+		Name:      name,
+		Source:    source,
+		Duration:  duration,
+		Remaining: duration,
+		Stacks:    1,
+		Modifiers: modifiers,
+	}
+}
+
+// Record returns the states.txt record of the state, it is set when the state is added to a list
+func (s *State) Record() *d2records.StateRecord {
+	return s.record
+}
+
+// IsTimed returns true if the state expires after its duration
+func (s *State) IsTimed() bool {
+	return s.Duration > 0
+}
+
+// refresh applies the state again, it is timed from the start with the new modifiers
+func (s *State) refresh(other *State) {
+	s.Duration = other.Duration
+	s.Remaining = other.Duration
+	s.Modifiers = other.Modifiers
+	s.Stacks++
+}
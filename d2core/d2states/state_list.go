@@ -0,0 +1,233 @@
+package d2states
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2records"
+)
+
+var errUnknownState = errors.New("unknown state")
+
+const lightAlpha = 0xff
+
+// NewList creates an empty list of states, the states are looked up in the given states.txt records
+func NewList(records d2records.States) *List {
+	return &List{
+		records: records,
+		states:  make([]*State, 0),
+	}
+}
+
+// List is the list of states active on an entity. A state applied again by the same source
+// is refreshed and stacks, aura states of different sources are kept side by side. Curses
+// replace each other, and so do states of the same group and auras of the same source.
+type List struct {
+	records d2records.States
+	states  []*State
+}
+
+// Add applies the state, returns the states which were removed because they can't be
+// active together with it
+func (l *List) Add(state *State) (removed []*State, err error) {
+	record, found := l.records[state.Name]
+	if !found {
+		return nil, fmt.Errorf("%w: %s", errUnknownState, state.Name)
+	}
+
+	state.record = record
+
+	if existing := l.find(state.Name, state.Source); existing != nil {
+		existing.refresh(state)
+		return nil, nil
+	}
+
+	removed = l.removeWhere(func(other *State) bool {
+		return excludes(state, other)
+	})
+
+	l.states = append(l.states, state)
+
+	return removed, nil
+}
+
+// excludes returns true if the new state replaces the other state
+func excludes(state, other *State) bool {
+	newRecord, otherRecord := state.record, other.record
+
+	if other.Name == state.Name {
+		// only auras of different sources stack side by side
+		return !newRecord.Aura
+	}
+
+	switch {
+	case newRecord.Curse && otherRecord.Curse:
+		return true
+	case newRecord.Group > 0 && newRecord.Group == otherRecord.Group:
+		return true
+	case newRecord.Aura && otherRecord.Aura && state.Source == other.Source:
+		return true
+	}
+
+	return false
+}
+
+func (l *List) find(name, source string) *State {
+	for _, state := range l.states {
+		if state.Name == name && state.Source == source {
+			return state
+		}
+	}
+
+	return nil
+}
+
+// Remove removes the state applied by the source, returns nil if it wasn't active
+func (l *List) Remove(name, source string) *State {
+	removed := l.removeWhere(func(state *State) bool {
+		return state.Name == name && state.Source == source
+	})
+
+	if len(removed) == 0 {
+		return nil
+	}
+
+	return removed[0]
+}
+
+// Dispel removes the states for which the filter returns true, and returns them
+func (l *List) Dispel(filter func(record *d2records.StateRecord) bool) []*State {
+	return l.removeWhere(func(state *State) bool {
+		return filter(state.record)
+	})
+}
+
+// OnHit removes the states which are dispelled when the entity gets hit
+func (l *List) OnHit() []*State {
+	return l.Dispel(func(record *d2records.StateRecord) bool {
+		return record.RemHit
+	})
+}
+
+// Cure removes the states which a healer can remove, like poison and curses
+func (l *List) Cure() []*State {
+	return l.Dispel(func(record *d2records.StateRecord) bool {
+		return record.Cureable
+	})
+}
+
+// OnDeath removes the states which don't stay on a dead player or monster
+func (l *List) OnDeath(isPlayer bool) []*State {
+	return l.Dispel(func(record *d2records.StateRecord) bool {
+		if isPlayer {
+			return !record.PlrStayDeath
+		}
+
+		return !record.MonStayDeath
+	})
+}
+
+func (l *List) removeWhere(filter func(state *State) bool) []*State {
+	removed := make([]*State, 0)
+	kept := l.states[:0]
+
+	for _, state := range l.states {
+		if filter(state) {
+			removed = append(removed, state)
+		} else {
+			kept = append(kept, state)
+		}
+	}
+
+	l.states = kept
+
+	return removed
+}
+
+// Advance counts down the timed states, returns the states which expired
+func (l *List) Advance(elapsed float64) []*State {
+	return l.removeWhere(func(state *State) bool {
+		if !state.IsTimed() {
+			return false
+		}
+
+		state.Remaining -= elapsed
+
+		return state.Remaining <= 0
+	})
+}
+
+// Has returns true if the state is active, from any source
+func (l *List) Has(name string) bool {
+	for _, state := range l.states {
+		if state.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// States returns the active states, in the order they were applied
+func (l *List) States() []*State {
+	return l.states
+}
+
+// Modifier returns the sum of the changes to the stat by the active states
+func (l *List) Modifier(stat string) int {
+	total := 0
+
+	for _, state := range l.states {
+		for _, modifier := range state.Modifiers {
+			if modifier.Stat == stat {
+				total += modifier.Value
+			}
+		}
+	}
+
+	return total
+}
+
+// Overlays returns the overlay.txt names of the overlays shown for the active states
+func (l *List) Overlays() []string {
+	overlays := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, state := range l.states {
+		overlay := state.record.Overlay1
+		if overlay == "" || state.record.NoOverlays || seen[overlay] {
+			continue
+		}
+
+		seen[overlay] = true
+
+		overlays = append(overlays, overlay)
+	}
+
+	return overlays
+}
+
+// Light returns the color of the light of the entity, from the state with the highest
+// color priority which changes it. Returns false if no state changes the light.
+func (l *List) Light() (color.Color, bool) {
+	var light *d2records.StateRecord
+
+	for _, state := range l.states {
+		record := state.record
+
+		if record.LightR == 0 && record.LightG == 0 && record.LightB == 0 {
+			continue
+		}
+
+		if light == nil || record.ColorPri > light.ColorPri {
+			light = record
+		}
+	}
+
+	if light == nil {
+		return nil, false
+	}
+
+	return color.RGBA{R: uint8(light.LightR), G: uint8(light.LightG), B: uint8(light.LightB), A: lightAlpha}, true
+}
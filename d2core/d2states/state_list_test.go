@@ -0,0 +1,107 @@
+package d2states
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2records"
+)
+
+func testRecords() d2records.States {
+	return d2records.States{
+		"poison":     {State: "poison", Cureable: true, LightG: 255, ColorPri: 1},
+		"frozen":     {State: "frozen", RemHit: true, LightB: 255, ColorPri: 2},
+		"amplify":    {State: "amplify", Curse: true, Cureable: true},
+		"weaken":     {State: "weaken", Curse: true, Cureable: true},
+		"bone":       {State: "bone", Group: 1},
+		"shiver":     {State: "shiver", Group: 1},
+		"might":      {State: "might", Aura: true},
+		"holyfire":   {State: "holyfire", Aura: true},
+		"staminapot": {State: "staminapot", PlrStayDeath: true},
+	}
+}
+
+func TestListStacking(t *testing.T) {
+	list := NewList(testRecords())
+
+	if _, err := list.Add(NewState("unknown", "a", 1)); err == nil {
+		t.Error("expected an error for an unknown state")
+	}
+
+	mustAdd(t, list, NewState("poison", "a", 2, Modifier{"poisonresist", -10}))
+	mustAdd(t, list, NewState("poison", "a", 3, Modifier{"poisonresist", -20}))
+
+	if len(list.States()) != 1 || list.States()[0].Stacks != 2 || list.States()[0].Remaining != 3 {
+		t.Errorf("expected the poison to be refreshed, have %+v", list.States())
+	}
+
+	if mod := list.Modifier("poisonresist"); mod != -20 {
+		t.Errorf("unexpected modifier, want -20, have %d", mod)
+	}
+
+	mustAdd(t, list, NewState("might", "a", 0))
+	mustAdd(t, list, NewState("might", "b", 0))
+
+	if removed := mustAdd(t, list, NewState("holyfire", "a", 0)); len(removed) != 1 || removed[0].Source != "a" {
+		t.Errorf("expected the aura of the same source to be replaced, removed %+v", removed)
+	}
+
+	if !list.Has("might") || !list.Has("holyfire") {
+		t.Error("expected the auras of different sources to stay")
+	}
+}
+
+func TestListExclusion(t *testing.T) {
+	list := NewList(testRecords())
+
+	mustAdd(t, list, NewState("amplify", "a", 5))
+
+	if removed := mustAdd(t, list, NewState("weaken", "b", 5)); len(removed) != 1 || removed[0].Name != "amplify" {
+		t.Errorf("expected the new curse to replace the old one, removed %+v", removed)
+	}
+
+	mustAdd(t, list, NewState("bone", "a", 5))
+
+	if removed := mustAdd(t, list, NewState("shiver", "a", 5)); len(removed) != 1 || removed[0].Name != "bone" {
+		t.Errorf("expected the state of the same group to be replaced, removed %+v", removed)
+	}
+}
+
+func TestListExpiry(t *testing.T) {
+	list := NewList(testRecords())
+
+	mustAdd(t, list, NewState("poison", "a", 1))
+	mustAdd(t, list, NewState("frozen", "a", 3))
+	mustAdd(t, list, NewState("might", "a", 0))
+	mustAdd(t, list, NewState("staminapot", "a", 10))
+
+	if c, ok := list.Light(); !ok || c == nil {
+		t.Error("expected a light color")
+	}
+
+	if expired := list.Advance(2); len(expired) != 1 || expired[0].Name != "poison" {
+		t.Errorf("expected the poison to expire, expired %+v", expired)
+	}
+
+	if dispelled := list.OnHit(); len(dispelled) != 1 || dispelled[0].Name != "frozen" {
+		t.Errorf("expected the freeze to be dispelled by a hit, dispelled %+v", dispelled)
+	}
+
+	if removed := list.OnDeath(true); len(removed) != 1 || removed[0].Name != "might" {
+		t.Errorf("expected only the aura to be removed on death, removed %+v", removed)
+	}
+
+	if !list.Has("staminapot") {
+		t.Error("expected the stamina potion to stay on death")
+	}
+}
+
+func mustAdd(t *testing.T, list *List, state *State) []*State {
+	t.Helper()
+
+	removed, err := list.Add(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return removed
+}
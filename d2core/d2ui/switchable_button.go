@@ -56,12 +56,18 @@ func (sbtn *SwitchableButton) SetVisible(visible bool) {
 	}
 }
 
+// isShown returns true if one of the buttons is visible. The switcher widget itself stays
+// hidden, its buttons are drawn by the ui manager.
+func (sbtn *SwitchableButton) isShown() bool {
+	return sbtn.active.GetVisible() || sbtn.inactive.GetVisible()
+}
+
 // OnActivated sets onActivate callback
 func (sbtn *SwitchableButton) OnActivated(cb func()) {
 	sbtn.active.OnActivated(func() {
 		cb()
 		sbtn.state = false
-		sbtn.SetVisible(sbtn.GetVisible())
+		sbtn.SetVisible(sbtn.isShown())
 	})
 }
 
@@ -75,7 +81,7 @@ func (sbtn *SwitchableButton) OnDeactivated(cb func()) {
 	sbtn.inactive.OnActivated(func() {
 		cb()
 		sbtn.state = true
-		sbtn.SetVisible(sbtn.GetVisible())
+		sbtn.SetVisible(sbtn.isShown())
 	})
 }
 
@@ -87,7 +93,7 @@ func (sbtn *SwitchableButton) Deactivate() {
 // SetState sets button's state
 func (sbtn *SwitchableButton) SetState(state bool) {
 	sbtn.state = state
-	sbtn.SetVisible(sbtn.GetVisible())
+	sbtn.SetVisible(sbtn.isShown())
 }
 
 // SetActiveTooltip sets tooltip of active button's
@@ -26,10 +26,13 @@ type TextBox struct {
 	isFocused    bool
 	isNumberOnly bool
 	maxValue     int
+	maxLength    int
 
 	*d2util.Logger
 }
 
+const defaultTextBoxMaxLength = 15
+
 // NewTextbox creates a new instance of a text box
 func (ui *UIManager) NewTextbox() *TextBox {
 	bgSprite, err := ui.NewSprite(d2resource.TextBox2, d2resource.PaletteUnits)
@@ -50,6 +53,7 @@ func (ui *UIManager) NewTextbox() *TextBox {
 		Logger:       ui.Logger,
 		isNumberOnly: false, // (disabled)
 		maxValue:     -1,    // (disabled)
+		maxLength:    defaultTextBoxMaxLength,
 	}
 	tb.lineBar.SetText("_")
 
@@ -170,8 +174,8 @@ func (v *TextBox) SetText(newText string) {
 		result += string(c)
 	}
 
-	if len(result) > 15 {
-		result = result[0:15]
+	if len(result) > v.maxLength {
+		result = result[0:v.maxLength]
 	}
 
 	v.text = result
@@ -239,6 +243,11 @@ func (v *TextBox) Activate() {
 	v.isFocused = true
 }
 
+// SetMaxLength sets the number of characters the text box accepts
+func (v *TextBox) SetMaxLength(length int) {
+	v.maxLength = length
+}
+
 // SetNumberOnly sets text box to support only numeric values
 func (v *TextBox) SetNumberOnly(max int) {
 	v.isNumberOnly = true
@@ -79,6 +79,8 @@ type CharacterSelect struct {
 	okButton               *d2ui.Button
 	deleteCharCancelButton *d2ui.Button
 	deleteCharOkButton     *d2ui.Button
+	difficultyButtons      []*d2ui.Button
+	difficultyCancelButton *d2ui.Button
 	selectionBox           *d2ui.Sprite
 	okCancelBox            *d2ui.Sprite
 	d2HeroTitle            *d2ui.Label
@@ -93,6 +95,7 @@ type CharacterSelect struct {
 	tickTimer              float64
 	storedTickTimer        float64
 	showDeleteConfirmation bool
+	showDifficultySelect   bool
 	loaded                 bool
 	connectionType         d2clientconnectiontype.ClientConnectionType
 	connectionHost         string
@@ -154,6 +157,8 @@ const (
 	deleteOkX, deleteOkY             = 422, 308
 	exitBtnX, exitBtnY               = 33, 537
 	okBtnX, okBtnY                   = 625, 537
+	difficultyBtnX, difficultyBtnY   = 336, 200
+	difficultyBtnSpacing             = 45
 )
 
 const (
@@ -307,6 +312,39 @@ func (v *CharacterSelect) createButtons(loading d2screen.LoadingState) {
 	v.okButton = v.uiManager.NewButton(d2ui.ButtonTypeMedium, v.asset.TranslateString(d2enum.OKLabel))
 	v.okButton.SetPosition(okBtnX, okBtnY)
 	v.okButton.OnActivated(func() { v.onOkButtonClicked() })
+
+	v.createDifficultyButtons()
+}
+
+// createDifficultyButtons creates the buttons of the difficulties, which are offered when the
+// selected hero has unlocked more than the normal difficulty
+func (v *CharacterSelect) createDifficultyButtons() {
+	difficulties := []d2enum.DifficultyType{
+		d2enum.DifficultyNormal,
+		d2enum.DifficultyNightmare,
+		d2enum.DifficultyHell,
+	}
+
+	v.difficultyButtons = make([]*d2ui.Button, len(difficulties))
+
+	for idx, difficulty := range difficulties {
+		name := ""
+		if record := v.asset.Records.DifficultyLevels[difficulty]; record != nil {
+			name = record.Name
+		}
+
+		d := difficulty
+		button := v.uiManager.NewButton(d2ui.ButtonTypeMedium, name)
+		button.SetPosition(difficultyBtnX, difficultyBtnY+idx*difficultyBtnSpacing)
+		button.SetVisible(false)
+		button.OnActivated(func() { v.startGame(d) })
+		v.difficultyButtons[idx] = button
+	}
+
+	v.difficultyCancelButton = v.uiManager.NewButton(d2ui.ButtonTypeMedium, v.asset.TranslateString(d2enum.CancelLabel))
+	v.difficultyCancelButton.SetPosition(difficultyBtnX, difficultyBtnY+len(difficulties)*difficultyBtnSpacing)
+	v.difficultyCancelButton.SetVisible(false)
+	v.difficultyCancelButton.OnActivated(func() { v.toggleDifficultySelect(false) })
 }
 
 func (v *CharacterSelect) onScrollUpdate() {
@@ -333,7 +371,13 @@ func (v *CharacterSelect) updateCharacterBoxes() {
 		heroInfo := v.asset.TranslateString("level") + " " + strconv.FormatInt(int64(v.gameStates[idx].Stats.Level), 10) +
 			" " + v.asset.TranslateString(v.gameStates[idx].HeroType.String())
 
-		v.characterNameLabel[i].SetText(d2ui.ColorTokenize(heroName, d2ui.ColorTokenGold))
+		// dead hardcore heroes are shown in red
+		nameColor := d2ui.ColorTokenGold
+		if v.gameStates[idx].IsDead {
+			nameColor = d2ui.ColorTokenRed
+		}
+
+		v.characterNameLabel[i].SetText(d2ui.ColorTokenize(heroName, nameColor))
 		v.characterStatsLabel[i].SetText(d2ui.ColorTokenize(heroInfo, d2ui.ColorTokenWhite))
 		v.characterExpLabel[i].SetText(d2ui.ColorTokenize(expText, d2ui.ColorTokenGreen))
 
@@ -346,6 +390,7 @@ func (v *CharacterSelect) updateCharacterBoxes() {
 			v.gameStates[idx].Stats,
 			v.gameStates[idx].Skills,
 			&equipment,
+			v.gameStates[idx].Belt,
 			v.gameStates[idx].LeftSkill,
 			v.gameStates[idx].RightSkill,
 			v.gameStates[idx].Gold,
@@ -395,6 +440,10 @@ func (v *CharacterSelect) Render(screen d2interface.Surface) {
 		v.okCancelBox.RenderSegmented(screen, 2, 1, 0)
 		v.deleteCharConfirmLabel.Render(screen)
 	}
+
+	if v.showDifficultySelect {
+		screen.DrawRect(screenWidth, screenHeight, d2util.Color(blackHalfOpacity))
+	}
 }
 
 func (v *CharacterSelect) moveSelectionBox() {
@@ -420,7 +469,7 @@ func (v *CharacterSelect) OnMouseButtonDown(event d2interface.MouseEvent) bool {
 		return false
 	}
 
-	if v.showDeleteConfirmation {
+	if v.showDeleteConfirmation || v.showDifficultySelect {
 		return false
 	}
 
@@ -535,8 +584,57 @@ func (v *CharacterSelect) refreshGameStates() {
 	v.moveSelectionBox()
 }
 
+// onOkButtonClicked starts the game with the selected hero, the difficulty is chosen first if
+// the hero has unlocked more than the normal difficulty. Joined games have the difficulty of
+// their host.
 func (v *CharacterSelect) onOkButtonClicked() {
-	v.navigator.ToCreateGame(v.gameStates[v.selectedCharacter].FilePath, v.connectionType, v.connectionHost)
+	// dead hardcore heroes can't play anymore
+	if v.gameStates[v.selectedCharacter].IsDead {
+		return
+	}
+
+	maxDifficulty := d2enum.DifficultyNormal
+	if quests := v.gameStates[v.selectedCharacter].Quests; quests != nil {
+		maxDifficulty = quests.MaxDifficulty()
+	}
+
+	if maxDifficulty == d2enum.DifficultyNormal || v.connectionType == d2clientconnectiontype.LANClient {
+		v.startGame(d2enum.DifficultyNormal)
+		return
+	}
+
+	for idx, button := range v.difficultyButtons {
+		button.SetEnabled(d2enum.DifficultyType(idx) <= maxDifficulty)
+	}
+
+	v.toggleDifficultySelect(true)
+}
+
+func (v *CharacterSelect) toggleDifficultySelect(showDialog bool) {
+	v.showDifficultySelect = showDialog
+	v.okButton.SetEnabled(!showDialog)
+	v.deleteCharButton.SetEnabled(!showDialog)
+	v.exitButton.SetEnabled(!showDialog)
+	v.newCharButton.SetEnabled(!showDialog)
+
+	for _, button := range v.difficultyButtons {
+		button.SetVisible(showDialog)
+	}
+
+	v.difficultyCancelButton.SetVisible(showDialog)
+}
+
+// startGame saves the chosen difficulty in the selected hero and starts the game, the server
+// takes the difficulty of the game from the hero of the host
+func (v *CharacterSelect) startGame(difficulty d2enum.DifficultyType) {
+	heroState := v.gameStates[v.selectedCharacter]
+	heroState.Difficulty = difficulty
+
+	if err := v.HeroStateFactory.Save(heroState); err != nil {
+		v.Errorf("unable to save the difficulty of %s: %s", heroState.HeroName, err)
+	}
+
+	v.navigator.ToCreateGame(heroState.FilePath, v.connectionType, v.connectionHost)
 }
 
 // OnUnload candles cleanup when this screen is closed
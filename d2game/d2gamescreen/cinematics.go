@@ -1,6 +1,9 @@
 package d2gamescreen
 
 import (
+	"errors"
+	"io"
+
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2video"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
@@ -24,6 +27,11 @@ const (
 	cinematicsExitBtnX, cinematicsExitBtnY = 340, 470
 )
 
+const (
+	videoAudioTrack = 0
+	black           = 0x000000ff
+)
+
 // CreateCinematics creates an instance of the credits screen
 func CreateCinematics(
 	navigator d2interface.Navigator,
@@ -66,6 +74,9 @@ type Cinematics struct {
 	navigator     d2interface.Navigator
 	uiManager     *d2ui.UIManager
 	videoDecoder  *d2video.BinkDecoder
+	videoSurface  d2interface.Surface
+	videoSound    d2interface.PCMSound
+	videoTime     float64
 	audioProvider d2interface.AudioProvider
 
 	*d2util.Logger
@@ -170,21 +181,127 @@ func (v *Cinematics) onEndCreditExpBtnClicked() {
 }
 
 func (v *Cinematics) playVideo(path string) {
+	v.stopVideo()
+
 	videoBytes, err := v.asset.LoadFile(path)
 	if err != nil {
 		v.Error(err.Error())
 		return
 	}
 
-	v.videoDecoder, err = d2video.CreateBinkDecoder(videoBytes)
+	decoder, err := d2video.CreateBinkDecoder(videoBytes)
 	if err != nil {
 		v.Error(err.Error())
 		return
 	}
+
+	if len(decoder.AudioTracks) > videoAudioTrack {
+		v.videoSound, err = v.loadVideoSound(decoder)
+		if err != nil {
+			// the video is still shown, timed by the frame rate
+			v.Error(err.Error())
+		}
+	}
+
+	v.videoDecoder = decoder
+	v.videoSurface = v.renderer.NewSurface(int(decoder.VideoWidth), int(decoder.VideoHeight))
+	v.videoTime = 0
+
+	v.setButtonsVisible(false)
+
+	if v.videoSound != nil {
+		v.videoSound.Play()
+	}
+
+	v.showNextFrame()
+}
+
+func (v *Cinematics) loadVideoSound(decoder *d2video.BinkDecoder) (d2interface.PCMSound, error) {
+	samples, err := decoder.DecodeAudio(videoAudioTrack)
+	if err != nil {
+		return nil, err
+	}
+
+	track := decoder.AudioTracks[videoAudioTrack]
+
+	channels := 1
+	if track.Stereo {
+		channels = 2
+	}
+
+	return v.audioProvider.LoadPCM(samples, int(track.AudioSampleRateHz), channels)
+}
+
+func (v *Cinematics) stopVideo() {
+	if v.videoSound != nil {
+		v.videoSound.Stop()
+	}
+
+	v.videoDecoder = nil
+	v.videoSurface = nil
+	v.videoSound = nil
+
+	v.setButtonsVisible(true)
+}
+
+func (v *Cinematics) setButtonsVisible(visible bool) {
+	for _, btn := range []*d2ui.Button{v.a1Btn, v.a2Btn, v.a3Btn, v.a4Btn, v.a5Btn, v.endCreditClassBtn,
+		v.endCreditExpBtn, v.cinematicsExitBtn} {
+		btn.SetVisible(visible)
+	}
+}
+
+// showNextFrame decodes the next frame of the video, the video stops after the last one
+func (v *Cinematics) showNextFrame() {
+	frame, err := v.videoDecoder.GetNextFrame()
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			v.Error(err.Error())
+		}
+
+		v.stopVideo()
+
+		return
+	}
+
+	v.videoSurface.ReplacePixels(frame.RGBA())
+}
+
+// Advance shows the frame of the video at the position of its audio, or at the elapsed time for
+// videos without audio
+func (v *Cinematics) Advance(elapsed float64) error {
+	if v.videoDecoder == nil {
+		return nil
+	}
+
+	v.videoTime += elapsed
+	if v.videoSound != nil {
+		v.videoTime = v.videoSound.Position()
+	}
+
+	frame := int(v.videoTime / v.videoDecoder.FrameDuration())
+
+	// decode up to the frame of the position, frames that are late are skipped
+	for v.videoDecoder != nil && v.videoDecoder.FramesDecoded() <= frame {
+		v.showNextFrame()
+	}
+
+	return nil
 }
 
 // Render renders the credits screen
 func (v *Cinematics) Render(screen d2interface.Surface) {
+	if v.videoSurface != nil {
+		width, height := v.videoSurface.GetSize()
+
+		screen.DrawRect(screenWidth, screenHeight, d2util.Color(black))
+		screen.PushTranslation((screenWidth-width)/2, (screenHeight-height)/2)
+		v.videoSurface.Render(screen)
+		screen.Pop()
+
+		return
+	}
+
 	v.background.RenderSegmented(screen, 4, 3, 0)
 	v.cinematicsBackground.RenderSegmented(screen, 2, 2, 0)
 	v.cinematicsLabel.Render(screen)
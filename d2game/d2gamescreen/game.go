@@ -16,8 +16,10 @@ import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2audio"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2hero"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2maprenderer"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2quest"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2screen"
 	"github.com/OpenDiablo2/OpenDiablo2/d2game/d2player"
 	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2client"
@@ -27,10 +29,22 @@ import (
 const hideZoneTextAfterSeconds = 2.0
 
 const (
-	moveErrStr         = "failed to send MovePlayer packet to the server, playerId: %s, x: %g, x: %g\n"
-	bindControlsErrStr = "failed to add gameControls as input handler for player: %s\n"
-	castErrStr         = "failed to send CastSkill packet to the server, playerId: %s, skillId: %d, x: %g, x: %g\n"
-	spawnItemErrStr    = "failed to send SpawnItem packet to the server: (%d, %d) %+v"
+	moveErrStr          = "failed to send MovePlayer packet to the server, playerId: %s, x: %g, x: %g\n"
+	bindControlsErrStr  = "failed to add gameControls as input handler for player: %s\n"
+	castErrStr          = "failed to send CastSkill packet to the server, playerId: %s, skillId: %d, x: %g, x: %g\n"
+	spawnItemErrStr     = "failed to send SpawnItem packet to the server: (%d, %d) %+v"
+	useBeltItemErrStr   = "failed to send UseBeltItem packet to the server, playerId: %s, column: %d, err: %v\n"
+	hirelingErrStr      = "failed to send UpdateHireling packet to the server, playerId: %s, err: %v\n"
+	questEventErrStr    = "failed to send QuestEvent packet to the server, playerId: %s, err: %v\n"
+	operateObjectErrStr = "failed to send OperateObject packet to the server, playerId: %s, err: %v\n"
+	usePortalErrStr     = "failed to send UsePortal packet to the server, playerId: %s, err: %v\n"
+	playerDeathErrStr   = "failed to send PlayerDeath packet to the server, playerId: %s, err: %v\n"
+	corpseErrStr        = "failed to send RetrieveCorpse packet to the server, playerId: %s, err: %v\n"
+	chatErrStr          = "failed to send ChatMessage packet to the server, playerId: %s, err: %v\n"
+	partyErrStr         = "failed to send PartyAction packet to the server, playerId: %s, err: %v\n"
+	attackErrStr        = "failed to send Attack packet to the server, playerId: %s, err: %v\n"
+	pickupItemErrStr    = "failed to send PickupItem packet to the server, playerId: %s, err: %v\n"
+	repairItemsErrStr   = "failed to send RepairItems packet to the server, playerId: %s, err: %v\n"
 )
 
 const (
@@ -142,6 +156,7 @@ func (v *Game) OnLoad(_ d2screen.LoadingState) {
 		{"spawnitemat", "spawns an item at the x,y coordinates",
 			[]string{"x", "y", "code1", "code2", "code3", "code4", "code5"}, v.commandSpawnItemAt},
 		{"spawnmon", "spawn monster at the local player position", []string{"name"}, v.commandSpawnMon},
+		{"kill", "kills the local player", nil, v.commandKill},
 	}
 
 	for _, cmd := range commands {
@@ -171,7 +186,7 @@ func (v *Game) OnUnload() error {
 		return err
 	}
 
-	if err := v.terminal.Unbind("spawnitemat", "spawnitem", "spawnmon"); err != nil {
+	if err := v.terminal.Unbind("spawnitemat", "spawnitem", "spawnmon", "kill"); err != nil {
 		return err
 	}
 
@@ -225,6 +240,10 @@ func (v *Game) Render(screen d2interface.Surface) {
 // Advance runs the update logic on the Gameplay screen
 // nolint:gocyclo // not need to change
 func (v *Game) Advance(elapsed float64) error {
+	if err := v.gameClient.ReceivePackets(); err != nil {
+		v.Errorf("failed to process the packets of the server: %v", err)
+	}
+
 	v.soundEngine.Advance(elapsed)
 
 	if (v.escapeMenu != nil && !v.escapeMenu.IsOpen()) || len(v.gameClient.Players) != 1 {
@@ -257,6 +276,13 @@ func (v *Game) Advance(elapsed float64) error {
 					v.gameControls.HideZoneChangeTextAfter(hideZoneTextAfterSeconds)
 				}
 
+				// the levels linked with a level are loaded in the background on entering it
+				if v.lastRegionType != tile.RegionType {
+					for _, levelID := range levelDetails.LinkedLevels() {
+						v.gameClient.MapEngine.PrefetchLevel(levelID, v.gameClient.Difficulty)
+					}
+				}
+
 				v.lastRegionType = tile.RegionType
 			}
 		}
@@ -283,6 +309,10 @@ func (v *Game) Advance(elapsed float64) error {
 		if v.gameControls.PartyPanel != nil {
 			v.gameControls.PartyPanel.UpdatePlayersList(v.gameClient.Players)
 		}
+
+		for _, message := range v.gameClient.ChatMessages() {
+			v.gameControls.OnChatMessage(message)
+		}
 	}
 
 	return nil
@@ -298,8 +328,8 @@ func (v *Game) bindGameControls() error {
 
 		var err error
 		v.gameControls, err = d2player.NewGameControls(v.asset, v.renderer, player, v.gameClient.MapEngine,
-			v.escapeMenu, v.mapRenderer, v, v.terminal, v.uiManager, v.keyMap, v.audioProvider, v.logLevel,
-			v.gameClient.IsSinglePlayer(), v.gameClient.Players)
+			v.escapeMenu, v.mapRenderer, v, v.terminal, v.uiManager, v.keyMap, v.audioProvider, v.soundEngine, v.logLevel,
+			v.gameClient.IsSinglePlayer(), v.gameClient.Players, v.gameClient.Parties)
 
 		if err != nil {
 			return err
@@ -339,7 +369,7 @@ func (v *Game) OnPlayerMove(targetX, targetY float64) {
 func (v *Game) OnPlayerSave() error {
 	playerState := v.gameClient.Players[v.gameClient.PlayerID]
 
-	sp, err := d2netpacket.CreateSavePlayerPacket(playerState, d2enum.DifficultyNormal)
+	sp, err := d2netpacket.CreateSavePlayerPacket(playerState, playerState.Difficulty)
 	if err != nil {
 		return fmt.Errorf("SavePlayerPacket: %v", err)
 	}
@@ -366,6 +396,208 @@ func (v *Game) OnPlayerCast(skillID int, targetX, targetY float64) {
 	}
 }
 
+// OnPlayerUseBeltItem asks the server to use the bottom item of the given belt column
+func (v *Game) OnPlayerUseBeltItem(column int) {
+	item := v.localPlayer.Belt.Peek(column)
+	if item == nil {
+		return
+	}
+
+	packet, err := d2netpacket.CreateUseBeltItemPacket(v.gameClient.PlayerID, column, item.GetItemCode())
+	if err != nil {
+		v.Errorf("UseBeltItemPacket: %v", err)
+	}
+
+	err = v.gameClient.SendPacketToServer(packet)
+	if err != nil {
+		v.Errorf(useBeltItemErrStr, v.gameClient.PlayerID, column, err)
+	}
+}
+
+// OnPlayerHireHireling asks the server to hire the given hireling
+func (v *Game) OnPlayerHireHireling(hireling *d2hero.HirelingState) {
+	v.sendUpdateHireling(d2netpacket.HirelingActionHire, hireling)
+}
+
+// OnPlayerReviveHireling asks the server to revive the player's hireling
+func (v *Game) OnPlayerReviveHireling() {
+	hireling := v.localPlayer.Hireling
+	if hireling == nil || !hireling.IsDead {
+		return
+	}
+
+	v.sendUpdateHireling(d2netpacket.HirelingActionRevive, hireling)
+}
+
+// OnPlayerEquipHireling asks the server to swap the item of the slot between the player and
+// the player's hireling
+func (v *Game) OnPlayerEquipHireling(slot d2hero.HirelingSlot) {
+	hireling := v.localPlayer.Hireling
+	if hireling == nil || hireling.IsDead {
+		return
+	}
+
+	packet, err := d2netpacket.CreateEquipHirelingPacket(v.gameClient.PlayerID, slot, hireling, v.localPlayer.Gold)
+	if err != nil {
+		v.Errorf("UpdateHirelingPacket: %v", err)
+		return
+	}
+
+	err = v.gameClient.SendPacketToServer(packet)
+	if err != nil {
+		v.Errorf(hirelingErrStr, v.gameClient.PlayerID, err)
+	}
+}
+
+func (v *Game) sendUpdateHireling(action d2netpacket.HirelingAction, hireling *d2hero.HirelingState) {
+	packet, err := d2netpacket.CreateUpdateHirelingPacket(v.gameClient.PlayerID, action, hireling, v.localPlayer.Gold)
+	if err != nil {
+		v.Errorf("UpdateHirelingPacket: %v", err)
+	}
+
+	err = v.gameClient.SendPacketToServer(packet)
+	if err != nil {
+		v.Errorf(hirelingErrStr, v.gameClient.PlayerID, err)
+	}
+}
+
+// OnPlayerQuestEvent tells the server about an event which may advance the player's quests
+func (v *Game) OnPlayerQuestEvent(trigger d2quest.Trigger) {
+	packet, err := d2netpacket.CreateQuestEventPacket(v.gameClient.PlayerID, trigger)
+	if err != nil {
+		v.Errorf("QuestEventPacket: %v", err)
+	}
+
+	err = v.gameClient.SendPacketToServer(packet)
+	if err != nil {
+		v.Errorf(questEventErrStr, v.gameClient.PlayerID, err)
+	}
+}
+
+// OnPlayerOperateObject asks the server to operate the object, like opening a chest
+func (v *Game) OnPlayerOperateObject(objectID string) {
+	packet, err := d2netpacket.CreateOperateObjectPacket(v.gameClient.PlayerID, objectID)
+	if err != nil {
+		v.Errorf("OperateObjectPacket: %v", err)
+	}
+
+	err = v.gameClient.SendPacketToServer(packet)
+	if err != nil {
+		v.Errorf(operateObjectErrStr, v.gameClient.PlayerID, err)
+	}
+}
+
+// OnPlayerAttack asks the server to roll an attack of the player on the monster
+func (v *Game) OnPlayerAttack(targetID string) {
+	packet, err := d2netpacket.CreateAttackPacket(v.gameClient.PlayerID, targetID)
+	if err != nil {
+		v.Errorf("AttackPacket: %v", err)
+	}
+
+	err = v.gameClient.SendPacketToServer(packet)
+	if err != nil {
+		v.Errorf(attackErrStr, v.gameClient.PlayerID, err)
+	}
+}
+
+// OnPlayerPickupItem asks the server to put the item on the ground into the player's belt
+func (v *Game) OnPlayerPickupItem(itemID string) {
+	packet, err := d2netpacket.CreatePickupItemPacket(v.gameClient.PlayerID, itemID, "", false)
+	if err != nil {
+		v.Errorf("PickupItemPacket: %v", err)
+	}
+
+	err = v.gameClient.SendPacketToServer(packet)
+	if err != nil {
+		v.Errorf(pickupItemErrStr, v.gameClient.PlayerID, err)
+	}
+}
+
+// OnPlayerRepairItems asks the server to repair the player's equipment at the vendor
+func (v *Game) OnPlayerRepairItems(npcKey string) {
+	packet, err := d2netpacket.CreateRepairItemsPacket(v.gameClient.PlayerID, npcKey)
+	if err != nil {
+		v.Errorf("RepairItemsPacket: %v", err)
+	}
+
+	err = v.gameClient.SendPacketToServer(packet)
+	if err != nil {
+		v.Errorf(repairItemsErrStr, v.gameClient.PlayerID, err)
+	}
+}
+
+// OnPlayerUsePortal asks the server to take the player through the town portal
+func (v *Game) OnPlayerUsePortal(portalID string) {
+	packet, err := d2netpacket.CreateUsePortalPacket(v.gameClient.PlayerID, portalID)
+	if err != nil {
+		v.Errorf("UsePortalPacket: %v", err)
+	}
+
+	err = v.gameClient.SendPacketToServer(packet)
+	if err != nil {
+		v.Errorf(usePortalErrStr, v.gameClient.PlayerID, err)
+	}
+}
+
+// OnPlayerRetrieveCorpse asks the server to give back the items of the player's corpse
+func (v *Game) OnPlayerRetrieveCorpse(corpseID string) {
+	packet, err := d2netpacket.CreateRetrieveCorpsePacket(v.gameClient.PlayerID, corpseID)
+	if err != nil {
+		v.Errorf("RetrieveCorpsePacket: %v", err)
+	}
+
+	err = v.gameClient.SendPacketToServer(packet)
+	if err != nil {
+		v.Errorf(corpseErrStr, v.gameClient.PlayerID, err)
+	}
+}
+
+// OnPlayerChat sends a chat message of the local player to the server, which relays it to the
+// players of its channel
+func (v *Game) OnPlayerChat(message d2netpacket.ChatMessagePacket) {
+	message.PlayerID = v.gameClient.PlayerID
+
+	packet, err := d2netpacket.CreateChatMessagePacket(message)
+	if err != nil {
+		v.Errorf(chatErrStr, v.gameClient.PlayerID, err)
+	}
+
+	err = v.gameClient.SendPacketToServer(packet)
+	if err != nil {
+		v.Errorf(chatErrStr, v.gameClient.PlayerID, err)
+	}
+}
+
+// OnPlayerPartyAction asks the server to invite, join, leave or change the hostility towards
+// the target player
+func (v *Game) OnPlayerPartyAction(targetID string, action d2netpacket.PartyAction) {
+	packet, err := d2netpacket.CreatePartyActionPacket(v.gameClient.PlayerID, targetID, action)
+	if err != nil {
+		v.Errorf(partyErrStr, v.gameClient.PlayerID, err)
+	}
+
+	err = v.gameClient.SendPacketToServer(packet)
+	if err != nil {
+		v.Errorf(partyErrStr, v.gameClient.PlayerID, err)
+	}
+}
+
+// onPlayerDeath starts the death animation of the local player and asks the server to kill
+// them, deaths by damage are decided by the server
+func (v *Game) onPlayerDeath() {
+	v.localPlayer.Die(nil)
+
+	packet, err := d2netpacket.CreatePlayerDeathPacket(v.gameClient.PlayerID)
+	if err != nil {
+		v.Errorf("PlayerDeathPacket: %v", err)
+	}
+
+	err = v.gameClient.SendPacketToServer(packet)
+	if err != nil {
+		v.Errorf(playerDeathErrStr, v.gameClient.PlayerID, err)
+	}
+}
+
 func (v *Game) debugSpawnItemAtPlayer(codes ...string) {
 	if v.localPlayer == nil {
 		return
@@ -379,7 +611,7 @@ func (v *Game) debugSpawnItemAtPlayer(codes ...string) {
 }
 
 func (v *Game) debugSpawnItemAtLocation(x, y int, codes ...string) {
-	packet, err := d2netpacket.CreateSpawnItemPacket(x, y, codes...)
+	packet, err := d2netpacket.CreateSpawnItemPacket("", x, y, codes...)
 	if err != nil {
 		v.Errorf("SpawnItemPacket: %v", err)
 	}
@@ -412,6 +644,14 @@ func (v *Game) commandSpawnItemAt(args []string) error {
 	return nil
 }
 
+func (v *Game) commandKill([]string) error {
+	if v.localPlayer != nil && !v.localPlayer.IsDead() {
+		v.onPlayerDeath()
+	}
+
+	return nil
+}
+
 func (v *Game) commandSpawnMon(args []string) error {
 	name := args[0]
 	x := int(v.localPlayer.Position.X())
@@ -504,6 +504,8 @@ func (v *SelectHeroClass) onOkButtonClicked() {
 		return
 	}
 
+	playerState.Hardcore = v.hardcoreCheckbox.GetCheckState()
+
 	err = v.Save(playerState)
 	if err != nil {
 		v.Errorf("failed to save game state!, err: %v", err.Error())
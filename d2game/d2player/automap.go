@@ -0,0 +1,388 @@
+package d2player
+
+import (
+	"image/color"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2ds1"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2util"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapengine"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2party"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2ui"
+)
+
+const (
+	// the tiles within this many tiles of the hero are explored
+	automapExploreRadius = 8
+
+	// the size of a tile on the automap, in pixels
+	automapTileWidth, automapTileHeight = 16, 8
+
+	// the full screen automap is drawn around the center of the screen, above the hud
+	automapFullCenterX, automapFullCenterY = 400, 275
+	automapFullRadius                      = 52
+
+	// the mini map is drawn in a box in the upper right corner of the screen
+	automapMiniX, automapMiniY          = 600, 10
+	automapMiniWidth, automapMiniHeight = 190, 110
+	automapMiniRadius                   = 11
+
+	automapFadeBrightness = 0.5
+	automapMarkerSize     = 5
+	automapNameOffsetY    = 12
+
+	automapMiniBackground = 0x00000080
+	automapHeroColor      = 0xffffffff
+	automapPartyColor     = 0x00ff00ff
+	automapNPCColor       = 0xffff00ff
+	automapWarpColor      = 0x8080ffff
+)
+
+// the names of the tile orientations in AutoMap.txt, by tile type
+// nolint:gochecknoglobals // lookup table
+var automapTileNames = map[d2enum.TileType]string{
+	d2enum.TileFloor:                                          "fl",
+	d2enum.TileLeftWall:                                       "wl",
+	d2enum.TileRightWall:                                      "wr",
+	d2enum.TileRightPartOfNorthCornerWall:                     "wtlr",
+	d2enum.TileLeftPartOfNorthCornerWall:                      "wtll",
+	d2enum.TileLeftEndWall:                                    "wtr",
+	d2enum.TileRightEndWall:                                   "wbl",
+	d2enum.TileSouthCornerWall:                                "wbr",
+	d2enum.TileLeftWallWithDoor:                               "wld",
+	d2enum.TileRightWallWithDoor:                              "wrd",
+	d2enum.TileSpecialTile1:                                   "wle",
+	d2enum.TileSpecialTile2:                                   "wre",
+	d2enum.TilePillarsColumnsAndStandaloneObjects:             "co",
+	d2enum.TileShadow:                                         "sh",
+	d2enum.TileTree:                                           "tr",
+	d2enum.TileRoof:                                           "rf",
+	d2enum.TileLowerWallsEquivalentToLeftWall:                 "ld",
+	d2enum.TileLowerWallsEquivalentToRightWall:                "lr",
+	d2enum.TileLowerWallsEquivalentToRightLeftNorthCornerWall: "lf",
+	d2enum.TileLowerWallsEquivalentToSouthCornerwall:          "lu",
+}
+
+// automapCellKey identifies the tiles which are drawn with the same automap cells
+type automapCellKey struct {
+	levelType int
+	tileType  d2enum.TileType
+	style     byte
+	sequence  byte
+}
+
+// NewAutomap creates the automap of the hero
+func NewAutomap(asset *d2asset.AssetManager,
+	ui *d2ui.UIManager,
+	l d2util.LogLevel,
+	mapEngine *d2mapengine.MapEngine,
+	hero *d2mapentity.Player,
+	players map[string]*d2mapentity.Player,
+	parties *d2party.Parties) *Automap {
+	am := &Automap{
+		asset:        asset,
+		uiManager:    ui,
+		mapEngine:    mapEngine,
+		hero:         hero,
+		players:      players,
+		parties:      parties,
+		cells:        make(map[automapCellKey][]int),
+		followHero:   true,
+		showParty:    true,
+		showNames:    true,
+		isFullScreen: true,
+	}
+
+	am.Logger = d2util.NewLogger()
+	am.Logger.SetLevel(l)
+	am.Logger.SetPrefix(logPrefix)
+
+	return am
+}
+
+// Automap tracks the tiles explored by the hero, and draws them with the party members, npcs
+// and warps on top of the game, full screen or as a mini map
+type Automap struct {
+	asset     *d2asset.AssetManager
+	uiManager *d2ui.UIManager
+	mapEngine *d2mapengine.MapEngine
+	hero      *d2mapentity.Player
+	players   map[string]*d2mapentity.Player
+	parties   *d2party.Parties
+	cellSheet d2interface.Animation
+	cells     map[automapCellKey][]int
+	nameLabel *d2ui.Label
+
+	// the position the automap is centered on, in tiles
+	centerX, centerY float64
+
+	isOpen       bool
+	isFullScreen bool
+	isFaded      bool
+	followHero   bool
+	showParty    bool
+	showNames    bool
+
+	*d2util.Logger
+}
+
+// Load the data for the automap
+func (a *Automap) Load() {
+	cellSheet, err := a.asset.LoadAnimation(d2resource.AutoMapCells, d2resource.PaletteSky)
+	if err != nil {
+		a.Errorf("failed to load the automap cells: %v", err)
+	}
+
+	a.cellSheet = cellSheet
+
+	a.nameLabel = a.uiManager.NewLabel(d2resource.Font6, d2resource.PaletteStatic)
+	a.nameLabel.Alignment = d2ui.HorizontalAlignCenter
+}
+
+// IsOpen returns true if the automap is shown
+func (a *Automap) IsOpen() bool {
+	return a.isOpen
+}
+
+// Toggle shows or hides the automap
+func (a *Automap) Toggle() {
+	a.isOpen = !a.isOpen
+
+	if a.isOpen {
+		a.Center()
+	}
+}
+
+// ToggleMiniMap switches the automap between full screen and the mini map, and shows it
+func (a *Automap) ToggleMiniMap() {
+	a.isFullScreen = !a.isFullScreen
+
+	if !a.isOpen {
+		a.Toggle()
+	}
+}
+
+// ToggleFade lowers or restores the brightness of the tiles of the automap
+func (a *Automap) ToggleFade() {
+	a.isFaded = !a.isFaded
+}
+
+// ToggleCenter switches between the automap following the hero, and staying where it is
+func (a *Automap) ToggleCenter() {
+	a.followHero = !a.followHero
+	a.Center()
+}
+
+// TogglePartyMarkers shows or hides the party members on the automap
+func (a *Automap) TogglePartyMarkers() {
+	a.showParty = !a.showParty
+}
+
+// ToggleNames shows or hides the names of the party members and npcs on the automap
+func (a *Automap) ToggleNames() {
+	a.showNames = !a.showNames
+}
+
+// Center centers the automap on the hero
+func (a *Automap) Center() {
+	a.centerX, a.centerY = a.hero.GetPositionF()
+}
+
+// Advance explores the tiles around the hero
+func (a *Automap) Advance(_ float64) {
+	if a.hero.Automap == nil {
+		return
+	}
+
+	position := a.hero.Position.Tile()
+	x, y := int(position.X()), int(position.Y())
+
+	if tile := a.tileAt(x, y); tile != nil {
+		a.hero.Automap.Explore(int(tile.RegionType), x, y, automapExploreRadius)
+	}
+
+	if a.followHero {
+		a.Center()
+	}
+}
+
+func (a *Automap) tileAt(x, y int) *d2mapengine.MapTile {
+	size := a.mapEngine.Size()
+	if x < 0 || y < 0 || x >= size.Width || y >= size.Height {
+		return nil
+	}
+
+	return a.mapEngine.TileAt(x, y)
+}
+
+// Render draws the explored tiles around the center of the automap, and the markers on them
+func (a *Automap) Render(target d2interface.Surface) {
+	if !a.isOpen || a.hero.Automap == nil {
+		return
+	}
+
+	originX, originY, radius := automapFullCenterX, automapFullCenterY, automapFullRadius
+
+	if !a.isFullScreen {
+		originX = automapMiniX + automapMiniWidth/2
+		originY = automapMiniY + automapMiniHeight/2
+		radius = automapMiniRadius
+
+		target.PushTranslation(automapMiniX, automapMiniY)
+		target.DrawRect(automapMiniWidth, automapMiniHeight, d2util.Color(automapMiniBackground))
+		target.Pop()
+	}
+
+	target.PushTranslation(originX, originY)
+	defer target.Pop()
+
+	a.renderTiles(target, radius)
+	a.renderMarkers(target, radius)
+}
+
+func (a *Automap) renderTiles(target d2interface.Surface, radius int) {
+	if a.cellSheet == nil {
+		return
+	}
+
+	if a.isFaded {
+		target.PushBrightness(automapFadeBrightness)
+		defer target.Pop()
+	}
+
+	centerX, centerY := int(a.centerX), int(a.centerY)
+
+	for y := centerY - radius; y <= centerY+radius; y++ {
+		for x := centerX - radius; x <= centerX+radius; x++ {
+			tile := a.tileAt(x, y)
+			if tile == nil || !a.hero.Automap.IsExplored(int(tile.RegionType), x, y) {
+				continue
+			}
+
+			screenX, screenY := a.toScreen(float64(x), float64(y))
+
+			for idx := range tile.Components.Floors {
+				a.renderCell(target, int(tile.RegionType), d2enum.TileFloor, &tile.Components.Floors[idx],
+					screenX, screenY, x+y)
+			}
+
+			for idx := range tile.Components.Walls {
+				wall := &tile.Components.Walls[idx]
+				if wall.Hidden() {
+					continue
+				}
+
+				a.renderCell(target, int(tile.RegionType), wall.Type, wall, screenX, screenY, x+y)
+			}
+		}
+	}
+}
+
+func (a *Automap) renderCell(target d2interface.Surface, levelType int, tileType d2enum.TileType,
+	tile *d2ds1.Tile, screenX, screenY, variation int) {
+	cells := a.cellsOf(automapCellKey{levelType, tileType, tile.Style, tile.Sequence})
+	if len(cells) == 0 {
+		return
+	}
+
+	if err := a.cellSheet.SetCurrentFrame(cells[variation%len(cells)]); err != nil {
+		return
+	}
+
+	target.PushTranslation(screenX, screenY)
+	a.cellSheet.Render(target)
+	target.Pop()
+}
+
+// cellsOf returns the frames of the automap cells of the tiles, from the AutoMap.txt records of
+// the level type. The cells of each kind of tile are only looked up once.
+func (a *Automap) cellsOf(key automapCellKey) []int {
+	if cells, found := a.cells[key]; found {
+		return cells
+	}
+
+	cells := make([]int, 0)
+	tileName, knownTile := automapTileNames[key.tileType]
+	levelTypes := a.asset.Records.Level.Types
+
+	if knownTile && key.levelType >= 0 && key.levelType < len(levelTypes) && levelTypes[key.levelType] != nil {
+		levelName := levelTypes[key.levelType].AutoMapName()
+
+		for _, record := range a.asset.Records.Level.AutoMaps {
+			if record.LevelName == levelName && record.TileName == tileName &&
+				record.Matches(int(key.style), int(key.sequence)) {
+				cells = append(cells, record.Cells()...)
+			}
+		}
+	}
+
+	a.cells[key] = cells
+
+	return cells
+}
+
+func (a *Automap) renderMarkers(target d2interface.Surface, radius int) {
+	for _, entity := range a.mapEngine.Entities() {
+		switch e := entity.(type) {
+		case *d2mapentity.NPC:
+			if e.Selectable() && !e.IsHostile() {
+				a.renderMarker(target, e, radius, automapNPCColor, e.Label())
+			}
+		case *d2mapentity.Portal:
+			a.renderMarker(target, e, radius, automapWarpColor, e.Label())
+		}
+	}
+
+	if a.showParty {
+		for _, player := range a.players {
+			if player != a.hero && a.parties.InSameParty(a.hero.ID(), player.ID()) {
+				a.renderMarker(target, player, radius, automapPartyColor, player.Name())
+			}
+		}
+	}
+
+	a.renderMarker(target, a.hero, radius, automapHeroColor, "")
+}
+
+func (a *Automap) renderMarker(target d2interface.Surface, entity d2interface.MapEntity, radius int,
+	rgba uint32, name string) {
+	x, y := entity.GetPositionF()
+	if dx, dy := x-a.centerX, y-a.centerY; dx < -float64(radius) || dx > float64(radius) ||
+		dy < -float64(radius) || dy > float64(radius) {
+		return
+	}
+
+	screenX, screenY := a.toScreen(x, y)
+	markerColor := d2util.Color(rgba)
+
+	drawAutomapCross(target, screenX, screenY, markerColor)
+
+	if a.showNames && name != "" {
+		a.nameLabel.SetText(name)
+		a.nameLabel.SetPosition(screenX, screenY-automapNameOffsetY)
+		a.nameLabel.Render(target)
+	}
+}
+
+// toScreen returns the position of the tile position on the automap, relative to its center
+func (a *Automap) toScreen(x, y float64) (screenX, screenY int) {
+	dx, dy := x-a.centerX, y-a.centerY
+
+	return int((dx - dy) * automapTileWidth / 2), int((dx + dy) * automapTileHeight / 2)
+}
+
+func drawAutomapCross(target d2interface.Surface, x, y int, c color.Color) {
+	const half = automapMarkerSize / 2
+
+	target.PushTranslation(x-half, y)
+	target.DrawRect(automapMarkerSize, 1, c)
+	target.Pop()
+
+	target.PushTranslation(x, y-half)
+	target.DrawRect(1, automapMarkerSize, c)
+	target.Pop()
+}
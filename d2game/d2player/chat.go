@@ -0,0 +1,445 @@
+package d2player
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2util"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2audio"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2maprenderer"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2ui"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket"
+)
+
+const (
+	// the input box is drawn above the left side of the hud, the log grows upwards from it
+	chatInputX, chatInputY = 10, 515
+	chatLogX, chatLogY     = 14, 494
+	chatLogLineHeight      = 16
+
+	chatLogLines      = 10
+	chatInputMaxChars = 200
+
+	// lines of the log are shown this many seconds, and fade out during the last seconds
+	chatLineDuration = 10.0
+	chatFadeDuration = 2.0
+
+	// the text of a player is shown above them this many seconds
+	chatOverheadDuration = 5.0
+	chatOverheadPad      = 8
+
+	chatOverheadBackground = 0x0000007f
+	chatAllColor           = 0xffffffff
+	chatWhisperColor       = 0xffff80ff
+	chatPartyColor         = 0x00ff00ff
+	chatServerColor        = 0xff5050ff
+
+	// chat commands select the channel of a message, other messages are said to all players
+	chatWhisperCommand = "/w "
+	chatPartyCommand   = "/p "
+)
+
+// the characters which can be typed in the chat box
+const chatInputFilter = " !\"#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_`" +
+	"abcdefghijklmnopqrstuvwxyz{|}~"
+
+// the formats of the lines of the log and the notices of the server are looked up in the
+// string tables by their english text
+const (
+	chatWhisperToFormat   = "To %s: %s"
+	chatWhisperFromFormat = "%s whispers: %s"
+	chatPartyFormat       = "%s (party): %s"
+)
+
+// nolint:gochecknoglobals // lookup table
+var chatNotices = map[d2netpacket.ChatNotice]string{
+	d2netpacket.ChatNoticeRateLimit:   "You are sending messages too fast.",
+	d2netpacket.ChatNoticeNoRecipient: "%s is not in the game.",
+	d2netpacket.ChatNoticeNoParty:     "You are not in a party.",
+}
+
+// quickSayPhrase is a phrase said with a single key. The text is the quoted part of the label
+// of its key in the controls menu, and the voice line of the hero class is played by every
+// player who sees the message.
+type quickSayPhrase struct {
+	key   string
+	text  string
+	voice string // the voice line is the sound in the directory of the class with this in its name
+}
+
+// nolint:gochecknoglobals // lookup table
+var quickSayPhrases = map[d2enum.GameEvent]quickSayPhrase{
+	d2enum.SayHelp:         {"CfgSay0", "Help!", "help"},
+	d2enum.SayFollowMe:     {"CfgSay1", "Follow me!", "follow"},
+	d2enum.SayThisIsForYou: {"CfgSay2", "This is for you!", "foryou"},
+	d2enum.SayThanks:       {"CfgSay3", "Thanks!", "thank"},
+	d2enum.SaySorry:        {"CfgSay4", "Sorry!", "sorry"},
+	d2enum.SayBye:          {"CfgSay5", "Bye!", "bye"},
+	d2enum.SayNowYouDie:    {"CfgSay6", "Now you die!", "die"},
+	d2enum.SayRetreat:      {"CfgSay7", "Retreat!", "retreat"},
+}
+
+// chatLine is a line of the message log
+type chatLine struct {
+	text string
+	rgba uint32
+	age  float64
+}
+
+// overheadText is the last message of a player, shown above them
+type overheadText struct {
+	text string
+	age  float64
+}
+
+// NewChat creates the chat box and message log of the hero
+func NewChat(asset *d2asset.AssetManager,
+	ui *d2ui.UIManager,
+	l d2util.LogLevel,
+	hero *d2mapentity.Player,
+	players map[string]*d2mapentity.Player,
+	mapRenderer *d2maprenderer.MapRenderer,
+	soundEngine *d2audio.SoundEngine) *Chat {
+	c := &Chat{
+		asset:       asset,
+		uiManager:   ui,
+		hero:        hero,
+		players:     players,
+		mapRenderer: mapRenderer,
+		soundEngine: soundEngine,
+		overhead:    make(map[string]*overheadText),
+		voices:      make(map[d2enum.Hero]map[d2enum.GameEvent]string),
+	}
+
+	c.Logger = d2util.NewLogger()
+	c.Logger.SetLevel(l)
+	c.Logger.SetPrefix(logPrefix)
+
+	return c
+}
+
+// Chat is the chat box the player types messages in, the log of the messages of the game and
+// the text bubbles above the players who spoke recently
+type Chat struct {
+	asset         *d2asset.AssetManager
+	uiManager     *d2ui.UIManager
+	hero          *d2mapentity.Player
+	players       map[string]*d2mapentity.Player
+	mapRenderer   *d2maprenderer.MapRenderer
+	soundEngine   *d2audio.SoundEngine
+	input         *d2ui.TextBox
+	logLabels     []*d2ui.Label
+	overheadLabel *d2ui.Label
+	lines         []*chatLine
+	overhead      map[string]*overheadText
+	voices        map[d2enum.Hero]map[d2enum.GameEvent]string
+	isTyping      bool
+	showAll       bool
+	onSendCb      func(message d2netpacket.ChatMessagePacket)
+
+	*d2util.Logger
+}
+
+// Load the labels and the input box of the chat
+func (c *Chat) Load() {
+	c.input = c.uiManager.NewTextbox()
+	c.input.SetFilter(chatInputFilter)
+	c.input.SetMaxLength(chatInputMaxChars)
+	c.input.SetPosition(chatInputX, chatInputY)
+	c.input.SetVisible(false)
+
+	c.logLabels = make([]*d2ui.Label, chatLogLines)
+	for idx := range c.logLabels {
+		c.logLabels[idx] = c.uiManager.NewLabel(d2resource.Font16, d2resource.PaletteStatic)
+	}
+
+	c.overheadLabel = c.uiManager.NewLabel(d2resource.Font16, d2resource.PaletteStatic)
+	c.overheadLabel.Alignment = d2ui.HorizontalAlignCenter
+	c.overheadLabel.SetBackgroundColor(d2util.Color(chatOverheadBackground))
+}
+
+// SetOnSendCb sets the callback run when the player sends a message
+func (c *Chat) SetOnSendCb(cb func(message d2netpacket.ChatMessagePacket)) {
+	c.onSendCb = cb
+}
+
+// IsTyping returns true while the chat box is open
+func (c *Chat) IsTyping() bool {
+	return c.isTyping
+}
+
+// OpenInput opens the chat box
+func (c *Chat) OpenInput() {
+	c.isTyping = true
+	c.input.SetText("")
+	c.input.SetVisible(true)
+	c.input.Activate()
+}
+
+// CloseInput closes the chat box, dropping the typed text
+func (c *Chat) CloseInput() {
+	c.isTyping = false
+	c.input.SetVisible(false)
+}
+
+// Submit sends the typed text and closes the chat box. The text is whispered to a player with
+// "/w name text" and said to the party with "/p text".
+func (c *Chat) Submit() {
+	text := strings.TrimSpace(c.input.GetText())
+	c.CloseInput()
+
+	if text == "" {
+		return
+	}
+
+	message := d2netpacket.ChatMessagePacket{Channel: d2netpacket.ChatChannelAll, Text: text}
+
+	switch {
+	case strings.HasPrefix(text, chatWhisperCommand):
+		fields := strings.SplitN(strings.TrimPrefix(text, chatWhisperCommand), " ", 2)
+		if len(fields) < 2 {
+			return
+		}
+
+		message.Channel = d2netpacket.ChatChannelWhisper
+		message.Recipient, message.Text = fields[0], fields[1]
+	case strings.HasPrefix(text, chatPartyCommand):
+		message.Channel = d2netpacket.ChatChannelParty
+		message.Text = strings.TrimPrefix(text, chatPartyCommand)
+	}
+
+	c.send(message)
+}
+
+// QuickSay says the phrase of the given quick-say event to all players
+func (c *Chat) QuickSay(event d2enum.GameEvent) {
+	if _, found := quickSayPhrases[event]; !found {
+		return
+	}
+
+	c.send(d2netpacket.ChatMessagePacket{
+		Channel: d2netpacket.ChatChannelAll,
+		Text:    c.phraseText(event),
+		Phrase:  event,
+	})
+}
+
+func (c *Chat) send(message d2netpacket.ChatMessagePacket) {
+	if c.onSendCb != nil {
+		c.onSendCb(message)
+	}
+}
+
+// ToggleOverlay shows or hides the lines of the log which have already faded out
+func (c *Chat) ToggleOverlay() {
+	c.showAll = !c.showAll
+}
+
+// ClearMessages empties the log and removes the text above the players
+func (c *Chat) ClearMessages() {
+	c.lines = nil
+	c.overhead = make(map[string]*overheadText)
+}
+
+// OnMessage adds a message received from the server to the log. Messages said to all players
+// are shown above the speaker, and quick-say phrases are voiced by the speaker's hero class.
+func (c *Chat) OnMessage(message d2netpacket.ChatMessagePacket) {
+	text := message.Text
+	if message.IsQuickSay() {
+		text = c.phraseText(message.Phrase)
+		c.playVoice(message.PlayerID, message.Phrase)
+	}
+
+	line := &chatLine{rgba: chatAllColor}
+
+	switch message.Channel {
+	case d2netpacket.ChatChannelAll:
+		line.text = fmt.Sprintf("%s: %s", message.PlayerName, text)
+		c.overhead[message.PlayerID] = &overheadText{text: text}
+	case d2netpacket.ChatChannelWhisper:
+		line.rgba = chatWhisperColor
+
+		if message.PlayerID == c.hero.ID() {
+			line.text = fmt.Sprintf(c.asset.TranslateString(chatWhisperToFormat), message.Recipient, text)
+		} else {
+			line.text = fmt.Sprintf(c.asset.TranslateString(chatWhisperFromFormat), message.PlayerName, text)
+		}
+	case d2netpacket.ChatChannelParty:
+		line.rgba = chatPartyColor
+		line.text = fmt.Sprintf(c.asset.TranslateString(chatPartyFormat), message.PlayerName, text)
+	case d2netpacket.ChatChannelServer:
+		line.rgba = chatServerColor
+		line.text = c.noticeText(message)
+	default:
+		c.Warningf("chat message on unknown channel %d", message.Channel)
+		return
+	}
+
+	c.lines = append(c.lines, line)
+	if len(c.lines) > chatLogLines {
+		c.lines = c.lines[len(c.lines)-chatLogLines:]
+	}
+}
+
+func (c *Chat) noticeText(message d2netpacket.ChatMessagePacket) string {
+	format, found := chatNotices[message.Notice]
+	if !found {
+		return message.Text
+	}
+
+	text := c.asset.TranslateString(format)
+	if strings.Contains(text, "%s") {
+		text = fmt.Sprintf(text, message.Recipient)
+	}
+
+	return text
+}
+
+func (c *Chat) phraseText(event d2enum.GameEvent) string {
+	phrase := quickSayPhrases[event]
+
+	if text := quotedText(c.asset.TranslateString(phrase.key)); text != "" {
+		return text
+	}
+
+	return phrase.text
+}
+
+// quotedText returns the text between the first pair of quotes of the label, the labels of
+// the quick-say keys quote the phrase they say
+func quotedText(label string) string {
+	for _, quote := range []string{"'", "\""} {
+		start := strings.Index(label, quote)
+		if start < 0 {
+			continue
+		}
+
+		if length := strings.Index(label[start+1:], quote); length > 0 {
+			return label[start+1 : start+1+length]
+		}
+	}
+
+	return ""
+}
+
+func (c *Chat) playVoice(playerID string, event d2enum.GameEvent) {
+	player, found := c.players[playerID]
+	if !found || c.soundEngine == nil {
+		return
+	}
+
+	handle, found := c.voiceHandles(player.Class)[event]
+	if !found {
+		c.Debugf("no voice line of %s for phrase %d", player.Class, event)
+		return
+	}
+
+	c.soundEngine.PlaySoundHandle(handle)
+}
+
+// voiceHandles returns the sounds.txt handles of the voice lines of the hero class, found by
+// the names of the sound files in the directory of the class
+func (c *Chat) voiceHandles(class d2enum.Hero) map[d2enum.GameEvent]string {
+	if handles, found := c.voices[class]; found {
+		return handles
+	}
+
+	handles := make(map[d2enum.GameEvent]string)
+	c.voices[class] = handles
+
+	directory := strings.ToLower(class.String()) + "\\"
+
+	for handle, sound := range c.asset.Records.Sound.Details {
+		fileName := strings.ToLower(strings.ReplaceAll(sound.FileName, "/", "\\"))
+		if !strings.HasPrefix(fileName, directory) {
+			continue
+		}
+
+		name := strings.NewReplacer("_", "", " ", "").Replace(strings.TrimPrefix(fileName, directory))
+
+		for event, phrase := range quickSayPhrases {
+			// the shortest handle is the plain line, longer ones are variations
+			if current, found := handles[event]; strings.Contains(name, phrase.voice) &&
+				(!found || len(handle) < len(current) || len(handle) == len(current) && handle < current) {
+				handles[event] = handle
+			}
+		}
+	}
+
+	return handles
+}
+
+// Advance ages the lines of the log and the text above the players
+func (c *Chat) Advance(elapsed float64) {
+	for _, line := range c.lines {
+		line.age += elapsed
+	}
+
+	for id, text := range c.overhead {
+		text.age += elapsed
+		if text.age >= chatOverheadDuration {
+			delete(c.overhead, id)
+		}
+	}
+}
+
+// Render draws the text above the players and the log, the chat box is drawn by the ui manager
+func (c *Chat) Render(target d2interface.Surface) {
+	c.renderOverhead(target)
+
+	y := chatLogY
+	row := 0
+
+	for idx := len(c.lines) - 1; idx >= 0; idx-- {
+		line := c.lines[idx]
+
+		alpha := 1.0
+		if !c.showAll {
+			alpha = (chatLineDuration - line.age) / chatFadeDuration
+		}
+
+		if alpha <= 0 {
+			break
+		}
+
+		if alpha > 1 {
+			alpha = 1
+		}
+
+		const alphaMask = 0xff
+
+		label := c.logLabels[row]
+		label.SetText(line.text)
+		label.Color[0] = d2util.Color(line.rgba&^alphaMask | uint32(alpha*alphaMask))
+
+		y -= chatLogLineHeight
+		label.SetPosition(chatLogX, y)
+		label.Render(target)
+
+		row++
+	}
+}
+
+func (c *Chat) renderOverhead(target d2interface.Surface) {
+	for id, text := range c.overhead {
+		player, found := c.players[id]
+		if !found {
+			continue
+		}
+
+		position := player.GetPosition()
+		offset := position.RenderOffset()
+		screenX, screenY := c.mapRenderer.WorldToScreen(player.GetPositionF())
+		_, height := player.GetSize()
+
+		c.overheadLabel.SetText(text.text)
+		c.overheadLabel.SetPosition(screenX-int(offset.X()),
+			screenY-int(offset.Y())-height-chatOverheadPad)
+		c.overheadLabel.Render(target)
+	}
+}
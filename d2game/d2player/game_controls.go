@@ -2,6 +2,7 @@ package d2player
 
 import (
 	"fmt"
+	"math/rand"
 	"strconv"
 	"strings"
 	"time"
@@ -15,10 +16,14 @@ import (
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2audio"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapengine"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2maprenderer"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2party"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2quest"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2ui"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket"
 )
 
 const (
@@ -34,6 +39,18 @@ type Panel interface {
 
 const mouseBtnActionsThreshold = 0.25
 
+// the monstats.txt ids of Deckard Cain in each act start with this
+const deckardCainKeyPrefix = "cain"
+
+// the string table keys of the npc talk menu entries for the npc services. The repair key is
+// the one of the repair button of the trade panel, the others still need to be checked against
+// string.tbl.
+const (
+	npcHireKey     = "strHire"
+	npcIdentifyKey = "strIdentifyItems"
+	npcRepairKey   = "NPCRepairItems"
+)
+
 const (
 	// Since they require special handling, not considering (1) globes, (2) content of the mini panel, (3) belt
 	leftSkill actionableType = iota
@@ -107,9 +124,11 @@ func NewGameControls(
 	ui *d2ui.UIManager,
 	keyMap *KeyMap,
 	audioProvider d2interface.AudioProvider,
+	soundEngine *d2audio.SoundEngine,
 	l d2util.LogLevel,
 	isSinglePlayer bool,
 	players map[string]*d2mapentity.Player,
+	parties *d2party.Parties,
 ) (*GameControls, error) {
 	var inventoryRecordKey string
 
@@ -172,11 +191,11 @@ func NewGameControls(
 	}
 	inventoryRecord := asset.Records.Layout.Inventory[inventoryRecordKey]
 
-	heroStatsPanel := NewHeroStatsPanel(asset, ui, hero.Name(), hero.Class, l, hero.Stats)
+	heroStatsPanel := NewHeroStatsPanel(asset, ui, hero.Name(), hero.Class, l, hero.Stats, hero.States)
 
-	questLog := NewQuestLog(asset, ui, l, audioProvider, hero.Act)
+	questLog := NewQuestLog(asset, ui, l, audioProvider, hero)
 
-	inventory, err := NewInventory(asset, ui, l, hero.Gold, inventoryRecord)
+	inventory, err := NewInventory(asset, ui, l, hero.Gold, hero.Stats, inventoryRecord)
 	if err != nil {
 		return nil, err
 	}
@@ -192,6 +211,12 @@ func NewGameControls(
 
 	helpOverlay := NewHelpOverlay(asset, ui, l, keyMap)
 
+	hirelingPanel := NewHirelingPanel(asset, ui, l, hero, heroState)
+	hireList := NewHireList(asset, ui, l, heroState)
+	npcDialogue := NewNPCDialogue(asset, ui, l, hero, soundEngine)
+	automap := NewAutomap(asset, ui, l, mapEngine, hero, players, parties)
+	chat := NewChat(asset, ui, l, hero, players, mapRenderer, soundEngine)
+
 	const blackAlpha50percent = 0x0000007f
 
 	gc := &GameControls{
@@ -207,6 +232,11 @@ func NewGameControls(
 		skilltree:      skilltree,
 		heroStatsPanel: heroStatsPanel,
 		questLog:       questLog,
+		hirelingPanel:  hirelingPanel,
+		hireList:       hireList,
+		npcDialogue:    npcDialogue,
+		automap:        automap,
+		chat:           chat,
 		HelpOverlay:    helpOverlay,
 		keyMap:         keyMap,
 		bottomMenuRect: &d2geom.Rectangle{
@@ -234,7 +264,8 @@ func NewGameControls(
 	}
 
 	if !isSinglePlayer {
-		PartyPanel := NewPartyPanel(asset, ui, hero.Name(), l, hero, hero.Stats, players)
+		PartyPanel := NewPartyPanel(asset, ui, hero.Name(), l, hero, hero.Stats, players, parties)
+		PartyPanel.SetOnPartyActionCb(inputListener.OnPlayerPartyAction)
 		gc.PartyPanel = PartyPanel
 	}
 
@@ -246,8 +277,18 @@ func NewGameControls(
 
 	gc.heroStatsPanel.SetOnCloseCb(gc.onCloseHeroStatsPanel)
 	gc.questLog.SetOnCloseCb(gc.onCloseQuestLog)
+	gc.questLog.SetOnQuestViewedCb(gc.onQuestViewed)
 	gc.inventory.SetOnCloseCb(gc.onCloseInventory)
+	gc.inventory.SetOnReadBookCb(gc.castBookSkill)
+	gc.inventory.SetOnEquipmentChangedCb(gc.updateItemStats)
 	gc.skilltree.SetOnCloseCb(gc.onCloseSkilltree)
+	gc.hirelingPanel.SetOnCloseCb(gc.onCloseHirelingPanel)
+	gc.hirelingPanel.SetOnReviveCb(gc.inputListener.OnPlayerReviveHireling)
+	gc.hirelingPanel.SetOnEquipCb(gc.inputListener.OnPlayerEquipHireling)
+	gc.hireList.SetOnCloseCb(gc.onCloseHirelingPanel)
+	gc.hireList.SetOnHireCb(gc.inputListener.OnPlayerHireHireling)
+	gc.npcDialogue.SetOnTopicCb(gc.inputListener.OnPlayerQuestEvent)
+	gc.chat.SetOnSendCb(gc.inputListener.OnPlayerChat)
 
 	gc.escapeMenu.SetOnCloseCb(gc.hud.miniPanel.restoreDisabled)
 	gc.HelpOverlay.SetOnCloseCb(gc.hud.miniPanel.restoreDisabled)
@@ -282,6 +323,11 @@ type GameControls struct {
 	heroStatsPanel         *HeroStatsPanel
 	PartyPanel             *PartyPanel
 	questLog               *QuestLog
+	hirelingPanel          *HirelingPanel
+	hireList               *HireList
+	npcDialogue            *NPCDialogue
+	automap                *Automap
+	chat                   *Chat
 	HelpOverlay            *HelpOverlay
 	bottomMenuRect         *d2geom.Rectangle
 	leftMenuRect           *d2geom.Rectangle
@@ -354,6 +400,17 @@ func (g *GameControls) OnKeyRepeat(event d2interface.KeyEvent) bool {
 
 // OnKeyDown handles key presses
 func (g *GameControls) OnKeyDown(event d2interface.KeyEvent) bool {
+	if g.chat.IsTyping() {
+		switch event.Key() {
+		case d2enum.KeyEnter, d2enum.KeyKPEnter:
+			g.chat.Submit()
+		case d2enum.KeyEscape:
+			g.chat.CloseInput()
+		}
+
+		return true
+	}
+
 	if event.Key() == d2enum.KeyEscape {
 		g.onEscKey()
 		return true
@@ -377,12 +434,39 @@ func (g *GameControls) OnKeyDown(event d2interface.KeyEvent) bool {
 		g.toggleHeroStatsPanel()
 	case d2enum.ToggleQuestLog:
 		g.toggleQuestLog()
+	case d2enum.ToggleHirelingPanel:
+		g.toggleHirelingPanel()
 	case d2enum.ToggleRunWalk:
 		g.hud.onToggleRunButton(false)
 	case d2enum.HoldRun:
 		g.hud.onToggleRunButton(true)
 	case d2enum.ToggleHelpScreen:
 		g.toggleHelpOverlay()
+	case d2enum.ToggleBelts:
+		g.hud.toggleBelt()
+	case d2enum.UseBeltSlot1, d2enum.UseBeltSlot2, d2enum.UseBeltSlot3, d2enum.UseBeltSlot4:
+		g.inputListener.OnPlayerUseBeltItem(int(gameEvent - d2enum.UseBeltSlot1))
+	case d2enum.ToggleAutomap:
+		g.automap.Toggle()
+	case d2enum.ToggleMiniMap:
+		g.automap.ToggleMiniMap()
+	case d2enum.CenterAutomap:
+		g.automap.ToggleCenter()
+	case d2enum.FadeAutomap:
+		g.automap.ToggleFade()
+	case d2enum.TogglePartyOnAutomap:
+		g.automap.TogglePartyMarkers()
+	case d2enum.ToggleNamesOnAutomap:
+		g.automap.ToggleNames()
+	case d2enum.ToggleChatBox:
+		g.chat.OpenInput()
+	case d2enum.ToggleChatOverlay:
+		g.chat.ToggleOverlay()
+	case d2enum.ClearMessages:
+		g.chat.ClearMessages()
+	case d2enum.SayHelp, d2enum.SayFollowMe, d2enum.SayThisIsForYou, d2enum.SayThanks, d2enum.SaySorry,
+		d2enum.SayBye, d2enum.SayNowYouDie, d2enum.SayRetreat:
+		g.chat.QuickSay(gameEvent)
 	default:
 		return false
 	}
@@ -408,7 +492,8 @@ func (g *GameControls) OnKeyUp(event d2interface.KeyEvent) bool {
 func (g *GameControls) onEscKey() {
 	escHandled := false
 
-	escHandled = g.hasOpenPanels() || g.HelpOverlay.IsOpen() || g.hud.skillSelectMenu.IsOpen()
+	escHandled = g.hasOpenPanels() || g.HelpOverlay.IsOpen() || g.hud.skillSelectMenu.IsOpen() ||
+		g.npcDialogue.IsOpen()
 	g.clearScreen()
 
 	if escHandled {
@@ -450,7 +535,7 @@ func (g *GameControls) OnMouseButtonRepeat(event d2interface.MouseEvent) bool {
 	shouldDoLeft := lastLeft >= mouseBtnActionsThreshold
 	shouldDoRight := lastRight >= mouseBtnActionsThreshold
 
-	if isLeft && shouldDoLeft && inRect && !g.hero.IsCasting() {
+	if isLeft && shouldDoLeft && inRect && !g.hero.IsCasting() && !g.hero.IsDead() {
 		g.lastLeftBtnActionTime = now
 
 		if event.KeyMod() == d2enum.KeyModShift {
@@ -478,7 +563,7 @@ func (g *GameControls) OnMouseButtonRepeat(event d2interface.MouseEvent) bool {
 		return true
 	}
 
-	if isRight && shouldDoRight && inRect && !g.hero.IsCasting() {
+	if isRight && shouldDoRight && inRect && !g.hero.IsCasting() && !g.hero.IsDead() {
 		g.lastRightBtnActionTime = now
 
 		g.inputListener.OnPlayerCast(g.hero.RightSkill.ID, px, py)
@@ -530,6 +615,20 @@ func (g *GameControls) OnMouseButtonDown(event d2interface.MouseEvent) bool {
 		}
 	}
 
+	if g.inventory.OnMouseButtonDown(event) {
+		return true
+	}
+
+	if g.npcDialogue.IsOpen() {
+		if g.npcDialogue.Contains(mx, my) {
+			// the menu entries are handled by the ui manager, a click on the text skips it
+			g.npcDialogue.Skip()
+			return true
+		}
+
+		g.npcDialogue.Close()
+	}
+
 	if g.hud.skillSelectMenu.IsOpen() && event.Button() == d2enum.MouseButtonLeft {
 		g.lastLeftBtnActionTime = d2util.Now()
 		g.hud.skillSelectMenu.HandleClick(mx, my)
@@ -542,9 +641,36 @@ func (g *GameControls) OnMouseButtonDown(event d2interface.MouseEvent) bool {
 	px = truncateFloat64(px)
 	py = truncateFloat64(py)
 
-	if event.Button() == d2enum.MouseButtonLeft && !g.isInActiveMenusRect(mx, my) && !g.hero.IsCasting() {
+	if event.Button() == d2enum.MouseButtonLeft && !g.isInActiveMenusRect(mx, my) && !g.hero.IsCasting() &&
+		!g.hero.IsDead() {
 		g.lastLeftBtnActionTime = d2util.Now()
 
+		if npc, ok := g.hud.hoveredEntity.(*d2mapentity.NPC); ok && (g.talkToNPC(npc) || g.attackMonster(npc)) {
+			return true
+		}
+
+		if object, ok := g.hud.hoveredEntity.(*d2mapentity.Object); ok && g.operateObject(object) {
+			return true
+		}
+
+		if player, ok := g.hud.hoveredEntity.(*d2mapentity.Player); ok && g.attackPlayer(player) {
+			return true
+		}
+
+		if item, ok := g.hud.hoveredEntity.(*d2mapentity.Item); ok {
+			g.pickupItem(item)
+			return true
+		}
+
+		if portal, ok := g.hud.hoveredEntity.(*d2mapentity.Portal); ok {
+			g.usePortal(portal)
+			return true
+		}
+
+		if corpse, ok := g.hud.hoveredEntity.(*d2mapentity.Corpse); ok && g.retrieveCorpse(corpse) {
+			return true
+		}
+
 		if event.KeyMod() == d2enum.KeyModShift {
 			g.inputListener.OnPlayerCast(g.hero.LeftSkill.ID, px, py)
 		} else {
@@ -554,7 +680,8 @@ func (g *GameControls) OnMouseButtonDown(event d2interface.MouseEvent) bool {
 		return true
 	}
 
-	if event.Button() == d2enum.MouseButtonRight && !g.isInActiveMenusRect(mx, my) && !g.hero.IsCasting() {
+	if event.Button() == d2enum.MouseButtonRight && !g.isInActiveMenusRect(mx, my) && !g.hero.IsCasting() &&
+		!g.hero.IsDead() {
 		g.lastRightBtnActionTime = d2util.Now()
 
 		g.inputListener.OnPlayerCast(g.hero.RightSkill.ID, px, py)
@@ -573,6 +700,8 @@ func (g *GameControls) clearLeftScreenSide() {
 	}
 
 	g.questLog.Close()
+	g.hirelingPanel.Close()
+	g.hireList.Close()
 	g.hud.skillSelectMenu.ClosePanels()
 	g.updateLayout()
 }
@@ -588,6 +717,7 @@ func (g *GameControls) clearScreen() {
 	g.clearRightScreenSide()
 	g.clearLeftScreenSide()
 	g.hud.skillSelectMenu.ClosePanels()
+	g.npcDialogue.Close()
 	g.HelpOverlay.Close()
 }
 
@@ -618,9 +748,16 @@ func (g *GameControls) openRightPanel(panel Panel) {
 }
 
 func (g *GameControls) toggleHeroStatsPanel() {
+	g.heroStatsPanel.SetResistance(g.heroState.Resistance(g.hero.Quests, g.hero.Difficulty))
 	g.openLeftPanel(g.heroStatsPanel)
 }
 
+// updateItemStats shows the stats of the equipped items and the active set bonuses in the hero
+// stats panel
+func (g *GameControls) updateItemStats() {
+	g.heroStatsPanel.SetItemStats(g.inventory.ItemStatList())
+}
+
 func (g *GameControls) togglePartyPanel() {
 	g.openLeftPanel(g.PartyPanel)
 }
@@ -651,6 +788,185 @@ func (g *GameControls) onCloseQuestLog() {
 	g.updateLayout()
 }
 
+func (g *GameControls) onQuestViewed(questID int) {
+	g.inputListener.OnPlayerQuestEvent(d2quest.QuestLogViewedTrigger(questID))
+}
+
+// talkToNPC opens the talk menu of a town npc, returns false if the npc can't be talked to
+func (g *GameControls) talkToNPC(npc *d2mapentity.NPC) bool {
+	monstat := npc.MonsterStats()
+	if monstat == nil || npc.IsHostile() {
+		return false
+	}
+
+	options := make([]npcOption, 0)
+
+	if g.heroState.IsHirelingSeller(monstat.ID, g.hero.Act, g.hero.Difficulty) {
+		options = append(options, npcOption{label: g.asset.TranslateString(npcHireKey), action: func() {
+			g.npcDialogue.Close()
+			g.openHireList(npc)
+		}})
+	}
+
+	if g.isRepairVendor(monstat.Key) {
+		options = append(options, npcOption{label: g.asset.TranslateString(npcRepairKey), action: func() {
+			g.npcDialogue.Close()
+			g.inputListener.OnPlayerRepairItems(monstat.Key)
+		}})
+	}
+
+	if strings.HasPrefix(monstat.Key, deckardCainKeyPrefix) {
+		options = append(options, npcOption{label: g.asset.TranslateString(npcIdentifyKey), action: func() {
+			g.npcDialogue.Close()
+			g.identifyWithCain(npc)
+		}})
+	}
+
+	x, y := npc.GetPositionF()
+	g.inputListener.OnPlayerMove(x, y)
+
+	g.clearLeftScreenSide()
+	g.npcDialogue.Open(npc, options...)
+
+	return true
+}
+
+// operateObject walks to the object and operates it, returns false if the object can't be operated
+func (g *GameControls) operateObject(object *d2mapentity.Object) bool {
+	if object.Behavior() == d2mapentity.ObjectBehaviorNone {
+		return false
+	}
+
+	x, y := object.GetPositionF()
+	g.inputListener.OnPlayerMove(x, y)
+	g.inputListener.OnPlayerOperateObject(object.ID())
+
+	return true
+}
+
+// attackMonster walks to the monster and attacks it, returns false if the NPC can't be attacked
+func (g *GameControls) attackMonster(npc *d2mapentity.NPC) bool {
+	if !npc.IsHostile() {
+		return false
+	}
+
+	x, y := npc.GetPositionF()
+	g.inputListener.OnPlayerMove(x, y)
+	g.inputListener.OnPlayerAttack(npc.ID())
+
+	return true
+}
+
+// attackPlayer walks to a player hostile to the hero and attacks them, returns false if the
+// player can't be attacked
+func (g *GameControls) attackPlayer(player *d2mapentity.Player) bool {
+	if !player.IsHostile() || player.IsInTown() || g.hero.IsInTown() {
+		return false
+	}
+
+	x, y := player.GetPositionF()
+	g.inputListener.OnPlayerMove(x, y)
+	g.inputListener.OnPlayerAttack(player.ID())
+
+	return true
+}
+
+// pickupItem walks to the item on the ground and picks it up
+func (g *GameControls) pickupItem(item *d2mapentity.Item) {
+	x, y := item.GetPositionF()
+	g.inputListener.OnPlayerMove(x, y)
+	g.inputListener.OnPlayerPickupItem(item.ID())
+}
+
+// usePortal walks to the town portal and goes through it
+func (g *GameControls) usePortal(portal *d2mapentity.Portal) {
+	x, y := portal.GetPositionF()
+	g.inputListener.OnPlayerMove(x, y)
+	g.inputListener.OnPlayerUsePortal(portal.ID())
+}
+
+// retrieveCorpse walks to a corpse of the hero and takes the items back, returns false if the
+// corpse belongs to another player
+func (g *GameControls) retrieveCorpse(corpse *d2mapentity.Corpse) bool {
+	if corpse.Owner != g.hero.ID() {
+		return false
+	}
+
+	x, y := corpse.GetPositionF()
+	g.inputListener.OnPlayerMove(x, y)
+	g.inputListener.OnPlayerRetrieveCorpse(corpse.ID())
+
+	return true
+}
+
+// castBookSkill casts the skill of a scroll or tome read in the inventory, returns false if it
+// can't be cast. Town portals can't be opened in town.
+func (g *GameControls) castBookSkill(skillName string) bool {
+	skill := g.asset.Records.GetSkillByName(skillName)
+	if skill == nil || g.hero.IsInTown() || g.hero.IsCasting() {
+		return false
+	}
+
+	x, y := g.hero.GetPositionF()
+	g.inputListener.OnPlayerCast(skill.ID, x, y)
+
+	return true
+}
+
+func (g *GameControls) toggleHirelingPanel() {
+	g.openLeftPanel(g.hirelingPanel)
+}
+
+func (g *GameControls) onCloseHirelingPanel() {
+	g.updateLayout()
+}
+
+// isRepairVendor returns whether the npc with the given monstats.txt id repairs items, which
+// are the npcs with a repair multiplier in npc.txt
+func (g *GameControls) isRepairVendor(npcKey string) bool {
+	for name, record := range g.asset.Records.NPCs {
+		if strings.EqualFold(name, npcKey) && record.Multipliers != nil {
+			return record.Multipliers.Repair > 0
+		}
+	}
+
+	return false
+}
+
+// openHireList opens the list of hirelings offered by the NPC, returns false if the NPC doesn't sell hirelings
+func (g *GameControls) openHireList(npc *d2mapentity.NPC) bool {
+	monstat := npc.MonsterStats()
+	if monstat == nil || !g.heroState.IsHirelingSeller(monstat.ID, g.hero.Act, g.hero.Difficulty) {
+		return false
+	}
+
+	// nolint:gosec // not concerned with crypto-strong randomness
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	g.hireList.SetHirelings(g.heroState.CreateHireableHirelings(g.hero.Act, g.hero.Difficulty,
+		g.hero.Stats.Level, r))
+
+	if !g.hireList.IsOpen() {
+		g.openLeftPanel(g.hireList)
+	}
+
+	return true
+}
+
+// identifyWithCain identifies all of the hero's items for free, if the npc is Deckard Cain
+func (g *GameControls) identifyWithCain(npc *d2mapentity.NPC) bool {
+	monstat := npc.MonsterStats()
+	if monstat == nil || !strings.HasPrefix(monstat.Key, deckardCainKeyPrefix) {
+		return false
+	}
+
+	if count := g.inventory.IdentifyAll(); count > 0 {
+		g.Infof("Deckard Cain identified %d items", count)
+	}
+
+	return true
+}
+
 func (g *GameControls) toggleHelpOverlay() {
 	if !g.isRightPanelOpen() || g.isLeftPanelOpen() {
 		g.HelpOverlay.updateKeyMap(g.keyMap)
@@ -696,6 +1012,11 @@ func (g *GameControls) Load() {
 	}
 
 	g.questLog.Load()
+	g.hirelingPanel.Load()
+	g.hireList.Load()
+	g.npcDialogue.Load()
+	g.automap.Load()
+	g.chat.Load()
 	g.HelpOverlay.Load()
 
 	g.loadAddButtons()
@@ -718,6 +1039,10 @@ func (g *GameControls) Advance(elapsed float64) error {
 	g.hud.Advance(elapsed)
 	g.inventory.Advance(elapsed)
 	g.questLog.Advance(elapsed)
+	g.hirelingPanel.Advance(elapsed)
+	g.npcDialogue.Advance(elapsed)
+	g.automap.Advance(elapsed)
+	g.chat.Advance(elapsed)
 
 	if g.PartyPanel != nil {
 		g.PartyPanel.Advance(elapsed)
@@ -760,7 +1085,8 @@ func (g *GameControls) isLeftPanelOpen() bool {
 		partyPanel = false
 	}
 
-	return g.heroStatsPanel.IsOpen() || partyPanel || g.questLog.IsOpen() || g.inventory.moveGoldPanel.IsOpen()
+	return g.heroStatsPanel.IsOpen() || partyPanel || g.questLog.IsOpen() || g.inventory.moveGoldPanel.IsOpen() ||
+		g.hirelingPanel.IsOpen() || g.hireList.IsOpen()
 }
 
 func (g *GameControls) isRightPanelOpen() bool {
@@ -805,6 +1131,9 @@ func (g *GameControls) isInActiveMenusRect(px, py int) bool {
 
 // Render draws the GameControls onto the target
 func (g *GameControls) Render(target d2interface.Surface) error {
+	g.automap.Render(target)
+	g.chat.Render(target)
+
 	if err := g.hud.Render(target); err != nil {
 		return err
 	}
@@ -826,6 +1155,11 @@ func (g *GameControls) renderPanels(target d2interface.Surface) error {
 	return nil
 }
 
+// OnChatMessage shows a chat message received from the server
+func (g *GameControls) OnChatMessage(message d2netpacket.ChatMessagePacket) {
+	g.chat.OnMessage(message)
+}
+
 // SetZoneChangeText sets the zoneChangeText
 func (g *GameControls) SetZoneChangeText(text string) {
 	g.hud.zoneChangeText.SetText(text)
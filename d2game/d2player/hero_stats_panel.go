@@ -11,6 +11,8 @@ import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2gui"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2hero"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2states"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2stats"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2ui"
 )
 
@@ -52,6 +54,11 @@ const (
 	labelResLightLine2X, labelResLightLine2Y = 310, 428
 	labelResPoisLine1X, labelResPoisLine1Y   = 310, 468
 	labelResPoisLine2X, labelResPoisLine2Y   = 310, 476
+
+	valueResFireX, valueResFireY   = 370, 396
+	valueResLightX, valueResLightY = 370, 420
+	valueResColdX, valueResColdY   = 370, 444
+	valueResPoisX, valueResPoisY   = 370, 468
 )
 
 const (
@@ -67,6 +74,23 @@ const (
 	newStatsRemainingPointsValueX, newStatsRemainingPointsValueY = 188, 411
 )
 
+const (
+	statValueColor   = 0xffffffff
+	statRaisedColor  = 0x6969ffff
+	statLoweredColor = 0xff5050ff
+	statStrength     = "strength"
+	statDexterity    = "dexterity"
+	statVitality     = "vitality"
+	statEnergy       = "energy"
+	statMaxHealth    = "maxhp"
+	statMaxMana      = "maxmana"
+	statMaxStamina   = "maxstamina"
+	statFireResist   = "fireresist"
+	statColdResist   = "coldresist"
+	statLightResist  = "lightresist"
+	statPoisonResist = "poisonresist"
+)
+
 // PanelText represents text on the panel
 type PanelText struct {
 	X           int
@@ -91,6 +115,10 @@ type StatsPanelLabels struct {
 	MaxMana      *d2ui.Label
 	MaxStamina   *d2ui.Label
 	Stamina      *d2ui.Label
+	FireResist   *d2ui.Label
+	ColdResist   *d2ui.Label
+	LightResist  *d2ui.Label
+	PoisonResist *d2ui.Label
 }
 
 // NewHeroStatsPanel creates a new hero status panel
@@ -99,7 +127,8 @@ func NewHeroStatsPanel(asset *d2asset.AssetManager,
 	heroName string,
 	heroClass d2enum.Hero,
 	l d2util.LogLevel,
-	heroState *d2hero.HeroStatsState) *HeroStatsPanel {
+	heroState *d2hero.HeroStatsState,
+	states *d2states.List) *HeroStatsPanel {
 	originX := 0
 	originY := 0
 
@@ -109,6 +138,7 @@ func NewHeroStatsPanel(asset *d2asset.AssetManager,
 		originX:   originX,
 		originY:   originY,
 		heroState: heroState,
+		states:    states,
 		heroName:  heroName,
 		heroClass: heroClass,
 		labels:    &StatsPanelLabels{},
@@ -127,6 +157,7 @@ type HeroStatsPanel struct {
 	uiManager       *d2ui.UIManager
 	panel           *d2ui.Sprite
 	heroState       *d2hero.HeroStatsState
+	states          *d2states.List
 	heroName        string
 	heroClass       d2enum.Hero
 	labels          *StatsPanelLabels
@@ -134,6 +165,8 @@ type HeroStatsPanel struct {
 	panelGroup      *d2ui.WidgetGroup
 	newStatPoints   *d2ui.WidgetGroup
 	remainingPoints *d2ui.Label
+	resistance      int
+	itemStats       d2stats.StatList
 
 	originX int
 	originY int
@@ -266,6 +299,16 @@ func (s *HeroStatsPanel) Toggle() {
 	}
 }
 
+// SetResistance sets the base of all resistances, see d2hero.HeroStateFactory.Resistance
+func (s *HeroStatsPanel) SetResistance(resistance int) {
+	s.resistance = resistance
+}
+
+// SetItemStats sets the stats the equipped items and set bonuses add to the hero
+func (s *HeroStatsPanel) SetItemStats(stats d2stats.StatList) {
+	s.itemStats = stats
+}
+
 // Open opens the hero status panel
 func (s *HeroStatsPanel) Open() {
 	s.isOpen = true
@@ -410,6 +453,10 @@ func (s *HeroStatsPanel) initStatValueLabels() {
 		{&s.labels.Health, s.heroState.Health, 370, 320},
 		{&s.labels.MaxMana, s.heroState.MaxMana, 330, 355},
 		{&s.labels.Mana, s.heroState.Mana, 370, 355},
+		{&s.labels.FireResist, s.resistance, valueResFireX, valueResFireY},
+		{&s.labels.LightResist, s.resistance, valueResLightX, valueResLightY},
+		{&s.labels.ColdResist, s.resistance, valueResColdX, valueResColdY},
+		{&s.labels.PoisonResist, s.resistance, valueResPoisX, valueResPoisY},
 	}
 
 	for _, cfg := range valueLabelConfigs {
@@ -422,19 +469,80 @@ func (s *HeroStatsPanel) setStatValues() {
 	s.labels.Experience.SetText(strconv.Itoa(s.heroState.Experience))
 	s.labels.NextLevelExp.SetText(strconv.Itoa(s.heroState.NextLevelExp))
 
-	s.labels.Strength.SetText(strconv.Itoa(s.heroState.Strength))
-	s.labels.Dexterity.SetText(strconv.Itoa(s.heroState.Dexterity))
-	s.labels.Vitality.SetText(strconv.Itoa(s.heroState.Vitality))
-	s.labels.Energy.SetText(strconv.Itoa(s.heroState.Energy))
+	s.setModifiedStatValue(s.labels.Strength, s.heroState.Strength, statStrength)
+	s.setModifiedStatValue(s.labels.Dexterity, s.heroState.Dexterity, statDexterity)
+	s.setModifiedStatValue(s.labels.Vitality, s.heroState.Vitality, statVitality)
+	s.setModifiedStatValue(s.labels.Energy, s.heroState.Energy, statEnergy)
 
-	s.labels.MaxHealth.SetText(strconv.Itoa(s.heroState.MaxHealth))
+	s.setModifiedStatValue(s.labels.MaxHealth, s.heroState.MaxHealth, statMaxHealth)
 	s.labels.Health.SetText(strconv.Itoa(s.heroState.Health))
 
-	s.labels.MaxStamina.SetText(strconv.Itoa(s.heroState.MaxStamina))
+	s.setModifiedStatValue(s.labels.MaxStamina, s.heroState.MaxStamina, statMaxStamina)
 	s.labels.Stamina.SetText(strconv.Itoa(int(s.heroState.Stamina)))
 
-	s.labels.MaxMana.SetText(strconv.Itoa(s.heroState.MaxMana))
+	s.setModifiedStatValue(s.labels.MaxMana, s.heroState.MaxMana, statMaxMana)
 	s.labels.Mana.SetText(strconv.Itoa(s.heroState.Mana))
+
+	s.setResistanceValue(s.labels.FireResist, statFireResist)
+	s.setResistanceValue(s.labels.LightResist, statLightResist)
+	s.setResistanceValue(s.labels.ColdResist, statColdResist)
+	s.setResistanceValue(s.labels.PoisonResist, statPoisonResist)
+}
+
+// setResistanceValue shows the resistance with the modifiers of the active states, capped at
+// the highest resistance, in red when it is negative
+func (s *HeroStatsPanel) setResistanceValue(label *d2ui.Label, stat string) {
+	s.setModifiedStatValue(label, s.resistance, stat)
+
+	value := s.resistance + s.itemStatValue(stat)
+	if s.states != nil {
+		value += s.states.Modifier(stat)
+	}
+
+	if value > d2hero.MaxResistance {
+		label.SetText(strconv.Itoa(d2hero.MaxResistance))
+	}
+
+	if value < 0 {
+		label.Color[0] = d2util.Color(statLoweredColor)
+	}
+}
+
+// setModifiedStatValue shows the stat with the modifiers of the active states and the equipped
+// items, in blue when they raise it and in red when they lower it
+func (s *HeroStatsPanel) setModifiedStatValue(label *d2ui.Label, value int, stat string) {
+	modifier := s.itemStatValue(stat)
+	if s.states != nil {
+		modifier += s.states.Modifier(stat)
+	}
+
+	label.SetText(strconv.Itoa(value + modifier))
+
+	switch {
+	case modifier > 0:
+		label.Color[0] = d2util.Color(statRaisedColor)
+	case modifier < 0:
+		label.Color[0] = d2util.Color(statLoweredColor)
+	default:
+		label.Color[0] = d2util.Color(statValueColor)
+	}
+}
+
+// itemStatValue returns the sum of the stat over the stats of the equipped items
+func (s *HeroStatsPanel) itemStatValue(stat string) int {
+	if s.itemStats == nil {
+		return 0
+	}
+
+	total := 0
+
+	for _, itemStat := range s.itemStats.Stats() {
+		if itemStat.Name() == stat && len(itemStat.Values()) > 0 {
+			total += itemStat.Values()[0].Int()
+		}
+	}
+
+	return total
 }
 
 func (s *HeroStatsPanel) createStatValueLabel(stat, x, y int) *d2ui.Label {
@@ -0,0 +1,219 @@
+package d2player
+
+import (
+	"fmt"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2util"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2hero"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2ui"
+)
+
+const (
+	hireListMaxRows = 8
+
+	hireListTitleX, hireListTitleY = 240, 80
+	hireListRowX, hireListRowY     = 100, 120
+	hireListButtonX                = 330
+	hireListRowSpacing             = 36
+
+	hireListTitleText = "Hire"
+	hireListRowFormat = "%s (%s %d)\n%d gold"
+)
+
+// NewHireList creates a new list of the hirelings offered for hire by an NPC
+func NewHireList(asset *d2asset.AssetManager,
+	ui *d2ui.UIManager,
+	l d2util.LogLevel,
+	heroState *d2hero.HeroStateFactory) *HireList {
+	hl := &HireList{
+		asset:     asset,
+		uiManager: ui,
+		heroState: heroState,
+	}
+
+	hl.Logger = d2util.NewLogger()
+	hl.Logger.SetLevel(l)
+	hl.Logger.SetPrefix(logPrefix)
+
+	return hl
+}
+
+// HireList is a panel listing the hirelings offered for hire, with a button to hire each of them
+type HireList struct {
+	asset      *d2asset.AssetManager
+	uiManager  *d2ui.UIManager
+	heroState  *d2hero.HeroStateFactory
+	panel      *d2ui.Sprite
+	panelGroup *d2ui.WidgetGroup
+	rowLabels  [hireListMaxRows]*d2ui.Label
+	rowButtons [hireListMaxRows]*d2ui.Button
+	hirelings  []*d2hero.HirelingState
+	onCloseCb  func()
+	onHireCb   func(hireling *d2hero.HirelingState)
+
+	isOpen bool
+
+	*d2util.Logger
+}
+
+// Load the data for the hire list
+func (s *HireList) Load() {
+	var err error
+
+	s.panelGroup = s.uiManager.NewWidgetGroup(d2ui.RenderPriorityHeroStatsPanel)
+
+	frame := s.uiManager.NewUIFrame(d2ui.FrameLeft)
+	s.panelGroup.AddWidget(frame)
+
+	s.panel, err = s.uiManager.NewSprite(d2resource.HirelingPanel, d2resource.PaletteSky)
+	if err != nil {
+		s.Error(err.Error())
+	}
+
+	w, h := frame.GetSize()
+	staticPanel := s.uiManager.NewCustomWidgetCached(s.renderStaticPanelFrames, w, h)
+	s.panelGroup.AddWidget(staticPanel)
+
+	closeButton := s.uiManager.NewButton(d2ui.ButtonTypeSquareClose, "")
+	closeButton.SetVisible(false)
+	closeButton.SetPosition(hirelingPanelCloseButtonX, hirelingPanelCloseButtonY)
+	closeButton.OnActivated(func() { s.Close() })
+	s.panelGroup.AddWidget(closeButton)
+
+	title := s.uiManager.NewLabel(d2resource.Font16, d2resource.PaletteSky)
+	title.SetText(hireListTitleText)
+	title.SetPosition(hireListTitleX, hireListTitleY)
+	title.Alignment = d2ui.HorizontalAlignCenter
+	s.panelGroup.AddWidget(title)
+
+	for idx := range s.rowLabels {
+		row := idx
+
+		s.rowLabels[idx] = s.uiManager.NewLabel(d2resource.Font6, d2resource.PaletteSky)
+		s.rowLabels[idx].SetPosition(hireListRowX, hireListRowY+idx*hireListRowSpacing)
+		s.panelGroup.AddWidget(s.rowLabels[idx])
+
+		s.rowButtons[idx] = s.uiManager.NewButton(d2ui.ButtonTypeSquareOk, "")
+		s.rowButtons[idx].SetPosition(hireListButtonX, hireListRowY+idx*hireListRowSpacing)
+		s.rowButtons[idx].OnActivated(func() { s.onHire(row) })
+		s.panelGroup.AddWidget(s.rowButtons[idx])
+	}
+
+	s.panelGroup.SetVisible(false)
+	s.setRowsVisible()
+}
+
+// SetHirelings sets the hirelings offered for hire
+func (s *HireList) SetHirelings(hirelings []*d2hero.HirelingState) {
+	if len(hirelings) > hireListMaxRows {
+		hirelings = hirelings[:hireListMaxRows]
+	}
+
+	s.hirelings = hirelings
+
+	for idx, hireling := range s.hirelings {
+		s.rowLabels[idx].SetText(fmt.Sprintf(hireListRowFormat,
+			s.asset.TranslateString(hireling.Name),
+			s.asset.TranslateString("strchrlvl"),
+			hireling.Level,
+			s.heroState.HirelingHireCost(hireling),
+		))
+	}
+
+	s.setRowsVisible()
+}
+
+func (s *HireList) setRowsVisible() {
+	for idx := range s.rowLabels {
+		visible := s.isOpen && idx < len(s.hirelings)
+		s.rowLabels[idx].SetVisible(visible)
+		s.rowButtons[idx].SetVisible(visible)
+	}
+}
+
+// SetOnHireCb sets the callback run when one of the hirelings is chosen
+func (s *HireList) SetOnHireCb(cb func(hireling *d2hero.HirelingState)) {
+	s.onHireCb = cb
+}
+
+func (s *HireList) onHire(row int) {
+	if row >= len(s.hirelings) || s.onHireCb == nil {
+		return
+	}
+
+	s.onHireCb(s.hirelings[row])
+	s.Close()
+}
+
+// IsOpen returns true if the hire list is open
+func (s *HireList) IsOpen() bool {
+	return s.isOpen
+}
+
+// Toggle toggles the visibility of the hire list
+func (s *HireList) Toggle() {
+	if s.isOpen {
+		s.Close()
+	} else {
+		s.Open()
+	}
+}
+
+// Open opens the hire list
+func (s *HireList) Open() {
+	s.isOpen = true
+	s.panelGroup.SetVisible(true)
+	s.setRowsVisible()
+}
+
+// Close closes the hire list
+func (s *HireList) Close() {
+	s.isOpen = false
+	s.panelGroup.SetVisible(false)
+	s.setRowsVisible()
+	s.onCloseCb()
+}
+
+// SetOnCloseCb the callback run on closing the HireList
+func (s *HireList) SetOnCloseCb(cb func()) {
+	s.onCloseCb = cb
+}
+
+// nolint:dupl // see quest_log.go.renderStaticPanelFrames comment
+func (s *HireList) renderStaticPanelFrames(target d2interface.Surface) {
+	frames := []int{
+		hirelingPanelTopLeft,
+		hirelingPanelTopRight,
+		hirelingPanelBottomRight,
+		hirelingPanelBottomLeft,
+	}
+
+	currentX := hirelingPanelOffsetX
+	currentY := hirelingPanelOffsetY
+
+	for _, frameIndex := range frames {
+		if err := s.panel.SetCurrentFrame(frameIndex); err != nil {
+			s.Error(err.Error())
+		}
+
+		w, h := s.panel.GetCurrentFrameSize()
+
+		switch frameIndex {
+		case hirelingPanelTopLeft:
+			s.panel.SetPosition(currentX, currentY+h)
+			currentX += w
+		case hirelingPanelTopRight:
+			s.panel.SetPosition(currentX, currentY+h)
+			currentY += h
+		case hirelingPanelBottomRight:
+			s.panel.SetPosition(currentX, currentY+h)
+		case hirelingPanelBottomLeft:
+			s.panel.SetPosition(currentX-w, currentY+h)
+		}
+
+		s.panel.Render(target)
+	}
+}
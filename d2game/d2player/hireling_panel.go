@@ -0,0 +1,354 @@
+package d2player
+
+import (
+	"fmt"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2util"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2hero"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2ui"
+)
+
+const ( // for the dc6 frames
+	hirelingPanelTopLeft = iota
+	hirelingPanelTopRight
+	hirelingPanelBottomLeft
+	hirelingPanelBottomRight
+)
+
+const (
+	hirelingPanelOffsetX, hirelingPanelOffsetY = 80, 64
+
+	hirelingPanelCloseButtonX, hirelingPanelCloseButtonY   = 208, 453
+	hirelingPanelReviveButtonX, hirelingPanelReviveButtonY = 150, 420
+
+	hirelingNameLabelX, hirelingNameLabelY = 240, 80
+	hirelingStatLabelX, hirelingStatLabelY = 100, 120
+	hirelingEquipLabelX                    = 100
+	hirelingEquipButtonX                   = 300
+	hirelingLabelSpacing                   = 18
+)
+
+const (
+	hirelingReviveText  = "Revive"
+	hirelingSwapText    = "Swap"
+	hirelingDamageText  = "Damage"
+	hirelingNoItemText  = "-"
+	hirelingStatFormat  = "%s: %d"
+	hirelingRangeFormat = "%s: %d-%d"
+	hirelingLifeFormat  = "%s: %d/%d"
+	hirelingCostFormat  = "%d gold"
+)
+
+// hirelingSlotNames are the names of the equipment slots shown in the hireling panel
+// nolint:gochecknoglobals // lookup table
+var hirelingSlotNames = []struct {
+	slot d2hero.HirelingSlot
+	name string
+}{
+	{d2hero.HirelingSlotHead, "Head"},
+	{d2hero.HirelingSlotTorso, "Torso"},
+	{d2hero.HirelingSlotWeapon, "Weapon"},
+	{d2hero.HirelingSlotShield, "Shield"},
+}
+
+// NewHirelingPanel creates a new hireling panel, which shows the stats and equipment
+// of the hero's hireling
+func NewHirelingPanel(asset *d2asset.AssetManager,
+	ui *d2ui.UIManager,
+	l d2util.LogLevel,
+	hero *d2mapentity.Player,
+	heroState *d2hero.HeroStateFactory) *HirelingPanel {
+	hp := &HirelingPanel{
+		asset:     asset,
+		uiManager: ui,
+		hero:      hero,
+		heroState: heroState,
+	}
+
+	hp.Logger = d2util.NewLogger()
+	hp.Logger.SetLevel(l)
+	hp.Logger.SetPrefix(logPrefix)
+
+	return hp
+}
+
+// HirelingPanel represents the hireling panel
+type HirelingPanel struct {
+	asset        *d2asset.AssetManager
+	uiManager    *d2ui.UIManager
+	hero         *d2mapentity.Player
+	heroState    *d2hero.HeroStateFactory
+	panel        *d2ui.Sprite
+	panelGroup   *d2ui.WidgetGroup
+	nameLabel    *d2ui.Label
+	statLabels   []*d2ui.Label
+	equipLabels  []*d2ui.Label
+	equipButtons []*d2ui.Button
+	reviveButton *d2ui.Button
+	reviveCost   *d2ui.Label
+	onCloseCb    func()
+	onReviveCb   func()
+	onEquipCb    func(slot d2hero.HirelingSlot)
+
+	isOpen bool
+
+	*d2util.Logger
+}
+
+// Load the data for the hireling panel
+func (s *HirelingPanel) Load() {
+	var err error
+
+	s.panelGroup = s.uiManager.NewWidgetGroup(d2ui.RenderPriorityHeroStatsPanel)
+
+	frame := s.uiManager.NewUIFrame(d2ui.FrameLeft)
+	s.panelGroup.AddWidget(frame)
+
+	s.panel, err = s.uiManager.NewSprite(d2resource.HirelingPanel, d2resource.PaletteSky)
+	if err != nil {
+		s.Error(err.Error())
+	}
+
+	w, h := frame.GetSize()
+	staticPanel := s.uiManager.NewCustomWidgetCached(s.renderStaticPanelFrames, w, h)
+	s.panelGroup.AddWidget(staticPanel)
+
+	closeButton := s.uiManager.NewButton(d2ui.ButtonTypeSquareClose, "")
+	closeButton.SetVisible(false)
+	closeButton.SetPosition(hirelingPanelCloseButtonX, hirelingPanelCloseButtonY)
+	closeButton.OnActivated(func() { s.Close() })
+	s.panelGroup.AddWidget(closeButton)
+
+	s.nameLabel = s.uiManager.NewLabel(d2resource.Font16, d2resource.PaletteSky)
+	s.nameLabel.SetPosition(hirelingNameLabelX, hirelingNameLabelY)
+	s.nameLabel.Alignment = d2ui.HorizontalAlignCenter
+	s.panelGroup.AddWidget(s.nameLabel)
+
+	const numStatLabels = 7
+
+	s.statLabels = s.createLabels(numStatLabels, hirelingStatLabelX, hirelingStatLabelY)
+
+	equipLabelY := hirelingStatLabelY + (numStatLabels+1)*hirelingLabelSpacing
+	s.equipLabels = s.createLabels(len(hirelingSlotNames), hirelingEquipLabelX, equipLabelY)
+	s.equipButtons = s.createEquipButtons(equipLabelY)
+
+	s.reviveButton = s.uiManager.NewButton(d2ui.ButtonTypeMedium, hirelingReviveText)
+	s.reviveButton.SetPosition(hirelingPanelReviveButtonX, hirelingPanelReviveButtonY)
+	s.reviveButton.OnActivated(s.onRevive)
+	s.panelGroup.AddWidget(s.reviveButton)
+
+	s.reviveCost = s.uiManager.NewLabel(d2resource.Font6, d2resource.PaletteSky)
+	s.reviveCost.SetPosition(hirelingPanelReviveButtonX, hirelingPanelReviveButtonY-hirelingLabelSpacing)
+	s.panelGroup.AddWidget(s.reviveCost)
+
+	s.panelGroup.SetVisible(false)
+}
+
+func (s *HirelingPanel) createLabels(count, x, y int) []*d2ui.Label {
+	labels := make([]*d2ui.Label, count)
+
+	for idx := range labels {
+		labels[idx] = s.uiManager.NewLabel(d2resource.Font6, d2resource.PaletteSky)
+		labels[idx].SetPosition(x, y+idx*hirelingLabelSpacing)
+		s.panelGroup.AddWidget(labels[idx])
+	}
+
+	return labels
+}
+
+// createEquipButtons creates a button next to each equipment label, which swaps the item of the
+// slot between the hero and the hireling
+func (s *HirelingPanel) createEquipButtons(y int) []*d2ui.Button {
+	buttons := make([]*d2ui.Button, len(hirelingSlotNames))
+
+	for idx := range buttons {
+		slot := hirelingSlotNames[idx].slot
+
+		buttons[idx] = s.uiManager.NewButton(d2ui.ButtonTypeShort, hirelingSwapText)
+		buttons[idx].SetPosition(hirelingEquipButtonX, y+idx*hirelingLabelSpacing)
+		buttons[idx].OnActivated(func() { s.onEquip(slot) })
+		s.panelGroup.AddWidget(buttons[idx])
+	}
+
+	return buttons
+}
+
+// SetOnEquipCb sets the callback run when the item of a slot is swapped with the hireling
+func (s *HirelingPanel) SetOnEquipCb(cb func(slot d2hero.HirelingSlot)) {
+	s.onEquipCb = cb
+}
+
+func (s *HirelingPanel) onEquip(slot d2hero.HirelingSlot) {
+	if s.onEquipCb != nil {
+		s.onEquipCb(slot)
+	}
+}
+
+// SetOnReviveCb sets the callback run when the revive button is pressed
+func (s *HirelingPanel) SetOnReviveCb(cb func()) {
+	s.onReviveCb = cb
+}
+
+func (s *HirelingPanel) onRevive() {
+	if s.onReviveCb != nil {
+		s.onReviveCb()
+	}
+}
+
+// IsOpen returns true if the hireling panel is open
+func (s *HirelingPanel) IsOpen() bool {
+	return s.isOpen
+}
+
+// Toggle toggles the visibility of the hireling panel
+func (s *HirelingPanel) Toggle() {
+	if s.isOpen {
+		s.Close()
+	} else {
+		s.Open()
+	}
+}
+
+// Open opens the hireling panel
+func (s *HirelingPanel) Open() {
+	s.isOpen = true
+	s.panelGroup.SetVisible(true)
+	s.setValues()
+}
+
+// Close closes the hireling panel
+func (s *HirelingPanel) Close() {
+	s.isOpen = false
+	s.panelGroup.SetVisible(false)
+	s.onCloseCb()
+}
+
+// SetOnCloseCb the callback run on closing the HirelingPanel
+func (s *HirelingPanel) SetOnCloseCb(cb func()) {
+	s.onCloseCb = cb
+}
+
+// Advance updates labels on the panel
+func (s *HirelingPanel) Advance(_ float64) {
+	if !s.isOpen {
+		return
+	}
+
+	s.setValues()
+}
+
+func (s *HirelingPanel) setValues() {
+	hireling := s.hero.Hireling
+
+	s.reviveButton.SetVisible(hireling != nil && hireling.IsDead)
+	s.reviveCost.SetVisible(hireling != nil && hireling.IsDead)
+
+	if hireling == nil {
+		s.nameLabel.SetText("")
+
+		for _, labels := range [][]*d2ui.Label{s.statLabels, s.equipLabels} {
+			for _, label := range labels {
+				label.SetText("")
+			}
+		}
+
+		for _, button := range s.equipButtons {
+			button.SetVisible(false)
+		}
+
+		return
+	}
+
+	stats := s.heroState.HirelingStats(hireling)
+
+	s.nameLabel.SetText(s.asset.TranslateString(hireling.Name))
+
+	statTexts := []string{
+		fmt.Sprintf(hirelingStatFormat, s.asset.TranslateString("strchrlvl"), hireling.Level),
+		fmt.Sprintf(hirelingStatFormat, s.asset.TranslateString("strchrexp"), hireling.Experience),
+		fmt.Sprintf(hirelingStatFormat, s.asset.TranslateString("strchrstr"), stats.Strength),
+		fmt.Sprintf(hirelingStatFormat, s.asset.TranslateString("strchrdex"), stats.Dexterity),
+		fmt.Sprintf(hirelingLifeFormat, s.asset.TranslateString("strchrlif"), hireling.Health, stats.MaxHealth),
+		fmt.Sprintf(hirelingStatFormat, s.asset.TranslateString("strchrdef"), stats.Defense),
+		fmt.Sprintf(hirelingRangeFormat, hirelingDamageText, stats.DamageMin, stats.DamageMax),
+	}
+
+	for idx, text := range statTexts {
+		s.statLabels[idx].SetText(text)
+	}
+
+	for idx, slot := range hirelingSlotNames {
+		canUse := s.heroState.CanHirelingUseSlot(hireling, slot.slot)
+		s.equipButtons[idx].SetVisible(canUse && !hireling.IsDead)
+
+		if !canUse {
+			s.equipLabels[idx].SetText("")
+			continue
+		}
+
+		s.equipLabels[idx].SetText(fmt.Sprintf("%s: %s", slot.name, s.equippedItemName(hireling, slot.slot)))
+	}
+
+	s.reviveCost.SetText(fmt.Sprintf(hirelingCostFormat, s.heroState.ReviveHirelingCost(hireling)))
+}
+
+func (s *HirelingPanel) equippedItemName(hireling *d2hero.HirelingState, slot d2hero.HirelingSlot) string {
+	var name string
+
+	switch slot {
+	case d2hero.HirelingSlotHead:
+		name = hireling.Equipment.Head.InventoryItemName()
+	case d2hero.HirelingSlotTorso:
+		name = hireling.Equipment.Torso.InventoryItemName()
+	case d2hero.HirelingSlotWeapon:
+		name = hireling.Equipment.Weapon.InventoryItemName()
+	case d2hero.HirelingSlotShield:
+		name = hireling.Equipment.Shield.InventoryItemName()
+	}
+
+	if name == "" {
+		return hirelingNoItemText
+	}
+
+	return name
+}
+
+// nolint:dupl // see quest_log.go.renderStaticPanelFrames comment
+func (s *HirelingPanel) renderStaticPanelFrames(target d2interface.Surface) {
+	frames := []int{
+		hirelingPanelTopLeft,
+		hirelingPanelTopRight,
+		hirelingPanelBottomRight,
+		hirelingPanelBottomLeft,
+	}
+
+	currentX := hirelingPanelOffsetX
+	currentY := hirelingPanelOffsetY
+
+	for _, frameIndex := range frames {
+		if err := s.panel.SetCurrentFrame(frameIndex); err != nil {
+			s.Error(err.Error())
+		}
+
+		w, h := s.panel.GetCurrentFrameSize()
+
+		switch frameIndex {
+		case hirelingPanelTopLeft:
+			s.panel.SetPosition(currentX, currentY+h)
+			currentX += w
+		case hirelingPanelTopRight:
+			s.panel.SetPosition(currentX, currentY+h)
+			currentY += h
+		case hirelingPanelBottomRight:
+			s.panel.SetPosition(currentX, currentY+h)
+		case hirelingPanelBottomLeft:
+			s.panel.SetPosition(currentX-w, currentY+h)
+		}
+
+		s.panel.Render(target)
+	}
+}
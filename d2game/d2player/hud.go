@@ -112,6 +112,9 @@ type HUD struct {
 	addSkillButton     *d2ui.Button
 	panelGroup         *d2ui.WidgetGroup
 	gameControls       *GameControls
+	beltSprites        map[string]*d2ui.Sprite
+	isBeltExpanded     bool
+	hoveredEntity      d2interface.MapEntity
 
 	*d2util.Logger
 }
@@ -160,6 +163,7 @@ func NewHUD(
 		healthGlobe:       healthGlobe,
 		manaGlobe:         manaGlobe,
 		gameControls:      gameControls,
+		beltSprites:       make(map[string]*d2ui.Sprite),
 	}
 
 	hud.Logger = d2util.NewLogger()
@@ -584,6 +588,7 @@ func (h *HUD) setExperienceTooltipText() {
 
 func (h *HUD) renderForSelectableEntitiesHovered(target d2interface.Surface) {
 	mx, my := h.lastMouseX, h.lastMouseY
+	h.hoveredEntity = nil
 
 	for entityIdx := range h.mapEngine.Entities() {
 		entity := (h.mapEngine.Entities())[entityIdx]
@@ -615,6 +620,8 @@ func (h *HUD) renderForSelectableEntitiesHovered(target d2interface.Surface) {
 			h.nameLabel.Render(target)
 			entity.Highlight()
 
+			h.hoveredEntity = entity
+
 			break
 		}
 	}
@@ -623,6 +630,7 @@ func (h *HUD) renderForSelectableEntitiesHovered(target d2interface.Surface) {
 // Render draws the HUD to the screen
 func (h *HUD) Render(target d2interface.Surface) error {
 	h.renderForSelectableEntitiesHovered(target)
+	h.renderBelt(target)
 
 	if h.isZoneTextShown {
 		h.zoneChangeText.SetPosition(zoneChangeTextX, zoneChangeTextY)
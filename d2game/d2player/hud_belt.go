@@ -0,0 +1,69 @@
+package d2player
+
+import (
+	"fmt"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2inventory"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2ui"
+)
+
+const (
+	// top left corner of the bottom row of the belt, in the main panel
+	beltSlotX = 423
+	beltSlotY = 562
+
+	beltSlotWidth  = 31
+	beltSlotHeight = 32
+)
+
+// toggleBelt shows or hides the upper rows of the belt
+func (h *HUD) toggleBelt() {
+	h.isBeltExpanded = !h.isBeltExpanded
+}
+
+func (h *HUD) beltItemSprite(code string) *d2ui.Sprite {
+	if sprite, found := h.beltSprites[code]; found {
+		return sprite
+	}
+
+	sprite, err := h.uiManager.NewSprite(fmt.Sprintf(fmtFlippyFile, code), d2resource.PaletteSky)
+	if err != nil {
+		h.Error("Failed to load belt item sprite, error: " + err.Error())
+	}
+
+	h.beltSprites[code] = sprite
+
+	return sprite
+}
+
+// renderBelt draws the items of the belt, only the bottom row is shown unless the belt is expanded
+func (h *HUD) renderBelt(target d2interface.Surface) {
+	belt := h.hero.Belt
+	if belt == nil {
+		return
+	}
+
+	rows := 1
+	if h.isBeltExpanded {
+		rows = belt.NumRows()
+	}
+
+	for column := 0; column < d2inventory.BeltColumns; column++ {
+		items := belt.Columns[column]
+
+		for row := 0; row < rows && row < len(items); row++ {
+			sprite := h.beltItemSprite(items[row].GetItemCode())
+			if sprite == nil {
+				continue
+			}
+
+			x := beltSlotX + column*beltSlotWidth
+			y := beltSlotY - row*beltSlotHeight + beltSlotHeight
+
+			sprite.SetPosition(x, y)
+			sprite.Render(target)
+		}
+	}
+}
@@ -1,6 +1,25 @@
 package d2player
 
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2hero"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2quest"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket"
+)
+
 type inputCallbackListener interface {
 	OnPlayerMove(x, y float64)
 	OnPlayerCast(skillID int, x, y float64)
+	OnPlayerUseBeltItem(column int)
+	OnPlayerHireHireling(hireling *d2hero.HirelingState)
+	OnPlayerReviveHireling()
+	OnPlayerEquipHireling(slot d2hero.HirelingSlot)
+	OnPlayerQuestEvent(trigger d2quest.Trigger)
+	OnPlayerOperateObject(objectID string)
+	OnPlayerAttack(targetID string)
+	OnPlayerPickupItem(itemID string)
+	OnPlayerRepairItems(npcKey string)
+	OnPlayerUsePortal(portalID string)
+	OnPlayerRetrieveCorpse(corpseID string)
+	OnPlayerChat(message d2netpacket.ChatMessagePacket)
+	OnPlayerPartyAction(targetID string, action d2netpacket.PartyAction)
 }
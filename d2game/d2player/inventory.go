@@ -10,7 +10,11 @@ import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2util"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2hero"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2item"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2item/diablo2item"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2stats"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2stats/diablo2stats"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2ui"
 )
 
@@ -27,11 +31,15 @@ const (
 	invGoldLabelX, invGoldLabelY     = 510, 455
 )
 
+// static check that Inventory implements d2item.StatContext
+var _ d2item.StatContext = &Inventory{}
+
 // NewInventory creates an inventory instance and returns a pointer to it
 func NewInventory(asset *d2asset.AssetManager,
 	ui *d2ui.UIManager,
 	l d2util.LogLevel,
 	gold int,
+	heroStats *d2hero.HeroStatsState,
 	record *d2records.InventoryRecord) (*Inventory, error) {
 	itemTooltip := ui.NewTooltip(d2resource.FontFormal11, d2resource.PaletteStatic, d2ui.TooltipXCenter, d2ui.TooltipYBottom)
 
@@ -40,12 +48,19 @@ func NewInventory(asset *d2asset.AssetManager,
 		return nil, fmt.Errorf("during creating new item factory: %s", err)
 	}
 
+	statFactory, err := diablo2stats.NewStatFactory(asset)
+	if err != nil {
+		return nil, fmt.Errorf("during creating new stat factory: %s", err)
+	}
+
 	mgp := NewMoveGoldPanel(asset, ui, gold, l)
 
 	inventory := &Inventory{
 		asset:       asset,
 		uiManager:   ui,
 		item:        itemFactory,
+		stat:        statFactory,
+		heroStats:   heroStats,
 		grid:        NewItemGrid(asset, ui, l, record),
 		originX:     record.Panel.Left,
 		itemTooltip: itemTooltip,
@@ -68,6 +83,8 @@ func NewInventory(asset *d2asset.AssetManager,
 type Inventory struct {
 	asset         *d2asset.AssetManager
 	item          *diablo2item.ItemFactory
+	stat          *diablo2stats.StatFactory
+	heroStats     *d2hero.HeroStatsState
 	uiManager     *d2ui.UIManager
 	panel         *d2ui.Sprite
 	goldLabel     *d2ui.Label
@@ -86,6 +103,14 @@ type Inventory struct {
 	gold          int
 	moveGoldPanel *MoveGoldPanel
 
+	// run when the equipped items change, the hero stats include their stats and set bonuses
+	onEquipmentChangedCb func()
+
+	// the scroll or tome of identify in use, while choosing the item to identify
+	identifyBook   *diablo2item.Item
+	identifyCursor *d2ui.Sprite
+	onReadBookCb   func(skill string) bool
+
 	*d2util.Logger
 }
 
@@ -143,12 +168,20 @@ func (g *Inventory) Load() {
 	g.goldLabel.SetPosition(invGoldLabelX, invGoldLabelY)
 	g.panelGroup.AddWidget(g.goldLabel)
 
+	g.identifyCursor, err = g.uiManager.NewSprite(d2resource.CursorIdentify, d2resource.PaletteUnits)
+	if err != nil {
+		g.Error(err.Error())
+	}
+
 	// https://github.com/OpenDiablo2/OpenDiablo2/issues/795
 	testInventoryCodes := [][]string{
 		{"kit", "Crimson", "of the Bat", "of Frost"},
 		{"rin", "Steel", "of Shock"},
 		{"jav"},
 		{"buc"},
+		{"ibk"},
+		{"isc"},
+		{"tbk"},
 	}
 
 	inventoryItems := make([]InventoryItem, 0)
@@ -159,7 +192,10 @@ func (g *Inventory) Load() {
 			continue
 		}
 
-		item.Identify()
+		if !item.NeedsIdentification() {
+			item.Identify()
+		}
+
 		inventoryItems = append(inventoryItems, item)
 	}
 
@@ -191,6 +227,8 @@ func (g *Inventory) Load() {
 		g.Errorf("could not add items to the inventory, err: %v", err.Error())
 	}
 
+	g.equipmentChanged()
+
 	g.moveGoldPanel.Load()
 
 	g.panelGroup.SetVisible(false)
@@ -205,12 +243,19 @@ func (g *Inventory) Open() {
 // Close closes the inventory
 func (g *Inventory) Close() {
 	g.isOpen = false
+	g.identifyBook = nil
 	g.moveGoldPanel.Close()
 	g.panelGroup.SetVisible(false)
 	g.itemTooltip.SetVisible(false)
 	g.onCloseCb()
 }
 
+// SetOnReadBookCb sets the callback run on reading a scroll or tome which casts a skill, like
+// town portal. The callback returns false if the skill can't be cast, then no charge is used.
+func (g *Inventory) SetOnReadBookCb(cb func(skill string) bool) {
+	g.onReadBookCb = cb
+}
+
 // SetOnCloseCb the callback run on closing the inventory
 func (g *Inventory) SetOnCloseCb(cb func()) {
 	g.onCloseCb = cb
@@ -235,7 +280,13 @@ func (g *Inventory) IsOpen() bool {
 }
 
 // Advance advances the state of the Inventory
-func (g *Inventory) Advance(_ float64) {
+func (g *Inventory) Advance(elapsed float64) {
+	for _, item := range g.EquippedItems() {
+		if diabloItem, ok := item.(*diablo2item.Item); ok {
+			diabloItem.Advance(elapsed)
+		}
+	}
+
 	if !g.IsOpen() {
 		return
 	}
@@ -253,6 +304,7 @@ func (g *Inventory) Render(target d2interface.Surface) {
 
 	g.grid.Render(target)
 	g.showItemDescriptionTooltip()
+	g.renderIdentifyCursor(target)
 }
 
 func (g *Inventory) renderFrame(target d2interface.Surface) {
@@ -371,3 +423,91 @@ func (g *Inventory) showEquippedItemDescriptionTooltip(slot EquipmentSlot) {
 		g.itemTooltip.SetVisible(true)
 	}
 }
+
+// EquippedItems returns the items in the equipment slots
+func (g *Inventory) EquippedItems() []d2item.Item {
+	return g.grid.EquippedItems()
+}
+
+// CarriedItems returns the items in the inventory grid
+func (g *Inventory) CarriedItems() []d2item.Item {
+	return g.grid.CarriedItems()
+}
+
+// BaseStatList returns the stats of the hero, without any item stats
+func (g *Inventory) BaseStatList() d2stats.StatList {
+	stats := make([]d2stats.Stat, 0)
+
+	if g.heroStats == nil {
+		return g.stat.NewStatList(stats...)
+	}
+
+	baseStats := map[string]int{
+		"level":      g.heroStats.Level,
+		"strength":   g.heroStats.Strength,
+		"dexterity":  g.heroStats.Dexterity,
+		"vitality":   g.heroStats.Vitality,
+		"energy":     g.heroStats.Energy,
+		"maxhp":      g.heroStats.MaxHealth,
+		"maxmana":    g.heroStats.MaxMana,
+		"maxstamina": g.heroStats.MaxStamina,
+	}
+
+	for key, value := range baseStats {
+		if stat := g.stat.NewStat(key, float64(value)); stat != nil {
+			stats = append(stats, stat)
+		}
+	}
+
+	return g.stat.NewStatList(stats...)
+}
+
+// StatList returns the stats of the hero, including the stats of the equipped items
+// and any active set bonuses
+func (g *Inventory) StatList() d2stats.StatList {
+	return g.item.EquippedStatList(g.BaseStatList(), g)
+}
+
+// ItemStatList returns only the stats the equipped items and the active set bonuses add to
+// the hero
+func (g *Inventory) ItemStatList() d2stats.StatList {
+	return g.item.EquippedStatList(nil, g)
+}
+
+// ChangeEquippedSlot equips the item in the slot, or unequips the slot when the item is nil
+func (g *Inventory) ChangeEquippedSlot(slot d2enum.EquippedSlot, item InventoryItem) {
+	g.grid.ChangeEquippedSlot(slot, item)
+
+	if item != nil {
+		g.grid.Load(item)
+	}
+
+	g.equipmentChanged()
+}
+
+// SetOnEquipmentChangedCb sets the callback run when the equipped items change
+func (g *Inventory) SetOnEquipmentChangedCb(cb func()) {
+	g.onEquipmentChangedCb = cb
+}
+
+// equipmentChanged updates the item contexts, as the set items count the equipped items of
+// their set, and notifies the hero stats
+func (g *Inventory) equipmentChanged() {
+	g.updateItemContexts()
+
+	if g.onEquipmentChangedCb != nil {
+		g.onEquipmentChangedCb()
+	}
+}
+
+// updateItemContexts sets the inventory as the stat context of all items,
+// so that the item descriptions reflect what the hero has equipped
+func (g *Inventory) updateItemContexts() {
+	for _, item := range g.EquippedItems() {
+		item.SetContext(g)
+	}
+
+	for _, item := range g.CarriedItems() {
+		item.SetContext(g)
+	}
+}
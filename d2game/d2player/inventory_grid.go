@@ -10,6 +10,7 @@ import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2item"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2ui"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
@@ -116,6 +117,32 @@ func (g *ItemGrid) ChangeEquippedSlot(slot d2enum.EquippedSlot, item InventoryIt
 	g.equipmentSlots[slot] = curItem
 }
 
+// EquippedItems returns the items in the equipment slots
+func (g *ItemGrid) EquippedItems() []d2item.Item {
+	result := make([]d2item.Item, 0)
+
+	for _, slot := range g.equipmentSlots {
+		if item, ok := slot.item.(d2item.Item); ok {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+// CarriedItems returns the items placed in the grid
+func (g *ItemGrid) CarriedItems() []d2item.Item {
+	result := make([]d2item.Item, 0)
+
+	for idx := range g.items {
+		if item, ok := g.items[idx].(d2item.Item); ok {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
 // Add places a given set of items into the first available slots.
 // Returns a count of the number of items which could be inserted.
 func (g *ItemGrid) Add(items ...InventoryItem) (int, error) {
@@ -0,0 +1,155 @@
+package d2player
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2item/diablo2item"
+)
+
+// OnMouseButtonDown handles clicks on the items of the inventory. Right clicking a scroll
+// or tome of identify switches the cursor to identify mode, the next left click identifies
+// the item under the cursor. Right clicking a scroll or tome of town portal reads it.
+// Returns true if the click was handled.
+func (g *Inventory) OnMouseButtonDown(event d2interface.MouseEvent) bool {
+	if !g.isOpen || g.moveGoldPanel.IsOpen() {
+		return false
+	}
+
+	mx, my := event.X(), event.Y()
+
+	switch event.Button() {
+	case d2enum.MouseButtonRight:
+		book, ok := g.itemAt(mx, my).(*diablo2item.Item)
+		if ok && book.IsTownPortalBook() {
+			g.readBook(book)
+			return true
+		}
+
+		if !ok || !book.IsIdentifyBook() {
+			return false
+		}
+
+		if book.Charges() > 0 {
+			g.identifyBook = book
+		}
+
+		return true
+	case d2enum.MouseButtonLeft:
+		if g.identifyBook == nil {
+			return false
+		}
+
+		if target, ok := g.itemAt(mx, my).(*diablo2item.Item); ok {
+			g.identifyWithBook(target)
+		}
+
+		g.identifyBook = nil
+
+		return true
+	}
+
+	return false
+}
+
+func (g *Inventory) identifyWithBook(target *diablo2item.Item) {
+	book := g.identifyBook
+	if !book.IdentifyItem(target) {
+		return
+	}
+
+	g.Infof("identified item %s", target.GetItemCode())
+
+	// a used up scroll is gone, an empty tome stays in the inventory
+	if !book.IsTome() && book.Charges() < 1 {
+		g.grid.Remove(book)
+	}
+}
+
+// readBook casts the skill of the scroll or tome, using one of its charges
+func (g *Inventory) readBook(book *diablo2item.Item) {
+	if book.Charges() < 1 || g.onReadBookCb == nil || !g.onReadBookCb(book.BookSkill()) {
+		return
+	}
+
+	book.UseCharge()
+
+	// a used up scroll is gone, an empty tome stays in the inventory
+	if !book.IsTome() && book.Charges() < 1 {
+		g.grid.Remove(book)
+	}
+}
+
+// IsIdentifying returns true if the cursor is in identify mode
+func (g *Inventory) IsIdentifying() bool {
+	return g.identifyBook != nil
+}
+
+// IdentifyAll identifies all of the carried and equipped items, for free
+func (g *Inventory) IdentifyAll() int {
+	count := 0
+
+	for _, items := range [][]InventoryItem{g.grid.items, g.equippedInventoryItems()} {
+		for _, item := range items {
+			diabloItem, ok := item.(*diablo2item.Item)
+			if !ok || diabloItem.IsIdentified() {
+				continue
+			}
+
+			diabloItem.Identify()
+			count++
+		}
+	}
+
+	return count
+}
+
+func (g *Inventory) equippedInventoryItems() []InventoryItem {
+	items := make([]InventoryItem, 0, len(g.grid.equipmentSlots))
+
+	for _, slot := range g.grid.equipmentSlots {
+		if slot.item != nil {
+			items = append(items, slot.item)
+		}
+	}
+
+	return items
+}
+
+// itemAt returns the carried or equipped item at the given screen position
+func (g *Inventory) itemAt(mx, my int) InventoryItem {
+	for _, slot := range g.grid.equipmentSlots {
+		if slot.item == nil {
+			continue
+		}
+
+		if (mx > slot.x) && (mx < slot.x+slot.width) && (my < slot.y) && (my > slot.y-slot.height) {
+			return slot.item
+		}
+	}
+
+	for _, item := range g.grid.items {
+		itemSprite := g.grid.sprites[item.GetItemCode()]
+		if itemSprite == nil {
+			continue
+		}
+
+		ix, iy := g.grid.SlotToScreen(item.InventoryGridSlot())
+		iw, ih := itemSprite.GetCurrentFrameSize()
+
+		if (mx > ix) && (mx < ix+iw) && (my > iy) && (my < iy+ih) {
+			return item
+		}
+	}
+
+	return nil
+}
+
+func (g *Inventory) renderIdentifyCursor(target d2interface.Surface) {
+	if g.identifyBook == nil || g.identifyCursor == nil {
+		return
+	}
+
+	_, h := g.identifyCursor.GetCurrentFrameSize()
+	g.identifyCursor.SetPosition(g.lastMouseX, g.lastMouseY+h)
+	g.identifyCursor.Render(target)
+}
@@ -274,7 +274,7 @@ func (menu *KeyBindingMenu) getBindingGroups() [][]keyBindingSetting {
 			{menu.asset.TranslateString("CfgSay4"), d2enum.SaySorry},
 			{menu.asset.TranslateString("CfgSay5"), d2enum.SayBye},
 			{menu.asset.TranslateString("CfgSay6"), d2enum.SayNowYouDie},
-			{menu.asset.TranslateString("CfgSay7"), d2enum.SayNowYouDie},
+			{menu.asset.TranslateString("CfgSay7"), d2enum.SayRetreat},
 		},
 		{
 			{menu.asset.TranslateString("CfgSnapshot"), d2enum.TakeScreenShot},
@@ -0,0 +1,347 @@
+package d2player
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2util"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2audio"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2dialogue"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2quest"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2ui"
+)
+
+const (
+	npcDialogueMaxRows = 8
+
+	npcDialogueX, npcDialogueY          = 200, 60
+	npcDialogueWidth, npcDialogueHeight = 400, 240
+	npcDialogueTitleY                   = 75
+	npcDialogueRowY                     = 110
+	npcDialogueRowSpacing               = 22
+	npcDialogueTextX, npcDialogueTextY  = 215, 110
+
+	npcDialogueVisibleLines  = 8
+	npcDialogueLineChars     = 55
+	npcDialogueLinesPerSec   = 0.6
+	npcDialogueHoldSeconds   = 2
+	npcDialogueBackground    = 0x000000c0
+	npcDialogueRowColor      = 0xffffffff
+	npcDialogueHoverColor    = 0xc7b377ff
+	npcDialogueQuestLabelFmt = "qstsa%dq%d"
+)
+
+// the string table keys of the talk menu entries. Only the quest titles are known to match
+// the tables, the other keys still need to be checked against string.tbl.
+const (
+	npcDialogueIntroKey  = "strIntroduction"
+	npcDialogueGossipKey = "strTalk"
+	npcDialogueCancelKey = "strCancel"
+)
+
+// npcOption is an entry of the talk menu which is not a dialogue topic, like hiring or trading
+type npcOption struct {
+	label  string
+	action func()
+}
+
+// NewNPCDialogue creates the talk menu of the town npcs
+func NewNPCDialogue(asset *d2asset.AssetManager,
+	ui *d2ui.UIManager,
+	l d2util.LogLevel,
+	hero *d2mapentity.Player,
+	soundEngine *d2audio.SoundEngine) *NPCDialogue {
+	nd := &NPCDialogue{
+		asset:       asset,
+		uiManager:   ui,
+		hero:        hero,
+		soundEngine: soundEngine,
+		talkCounts:  make(map[string]int),
+	}
+
+	nd.Logger = d2util.NewLogger()
+	nd.Logger.SetLevel(l)
+	nd.Logger.SetPrefix(logPrefix)
+
+	return nd
+}
+
+// NPCDialogue is the talk menu of an npc, choosing one of the topics makes the npc
+// tell it, with the text scrolling in the dialogue box and the speech playing
+type NPCDialogue struct {
+	asset       *d2asset.AssetManager
+	uiManager   *d2ui.UIManager
+	soundEngine *d2audio.SoundEngine
+	hero        *d2mapentity.Player
+	npc         *d2mapentity.NPC
+	tree        *d2dialogue.Tree
+	options     []npcOption
+	talkCounts  map[string]int
+	panelGroup  *d2ui.WidgetGroup
+	title       *d2ui.Label
+	rows        [npcDialogueMaxRows]*d2ui.LabelButton
+	text        *d2ui.Label
+	menu        []npcOption
+	lines       []string
+	speech      *d2audio.Sound
+	elapsed     float64
+	onCloseCb   func()
+	onTopicCb   func(trigger d2quest.Trigger)
+
+	isOpen     bool
+	isSpeaking bool
+
+	*d2util.Logger
+}
+
+// Load the data for the talk menu
+func (s *NPCDialogue) Load() {
+	s.panelGroup = s.uiManager.NewWidgetGroup(d2ui.RenderPriorityHUDPanel)
+
+	background := s.uiManager.NewCustomWidget(s.renderBackground, npcDialogueWidth, npcDialogueHeight)
+	background.SetPosition(npcDialogueX, npcDialogueY)
+	s.panelGroup.AddWidget(background)
+
+	s.title = s.uiManager.NewLabel(d2resource.Font16, d2resource.PaletteSky)
+	s.title.SetPosition(npcDialogueX+npcDialogueWidth/2, npcDialogueTitleY)
+	s.title.Alignment = d2ui.HorizontalAlignCenter
+	s.panelGroup.AddWidget(s.title)
+
+	for idx := range s.rows {
+		row := idx
+
+		s.rows[idx] = s.uiManager.NewLabelButton(d2resource.Font16, d2resource.PaletteSky)
+		s.rows[idx].SetPosition(npcDialogueX+npcDialogueWidth/2, npcDialogueRowY+idx*npcDialogueRowSpacing)
+		s.rows[idx].SetColors(d2util.Color(npcDialogueRowColor), d2util.Color(npcDialogueHoverColor))
+		s.rows[idx].OnActivated(func() { s.onRowActivated(row) })
+		s.panelGroup.AddWidget(s.rows[idx])
+	}
+
+	s.text = s.uiManager.NewLabel(d2resource.Font16, d2resource.PaletteSky)
+	s.text.SetPosition(npcDialogueTextX, npcDialogueTextY)
+	s.panelGroup.AddWidget(s.text)
+
+	s.panelGroup.SetVisible(false)
+	s.setRowsVisible()
+}
+
+func (s *NPCDialogue) renderBackground(target d2interface.Surface) {
+	target.PushTranslation(npcDialogueX, npcDialogueY)
+	defer target.Pop()
+
+	target.DrawRect(npcDialogueWidth, npcDialogueHeight, d2util.Color(npcDialogueBackground))
+}
+
+// Open opens the talk menu of the npc, the options are added after the dialogue topics
+func (s *NPCDialogue) Open(npc *d2mapentity.NPC, options ...npcOption) {
+	monstat := npc.MonsterStats()
+	if monstat == nil {
+		return
+	}
+
+	if s.isOpen {
+		s.Close()
+	}
+
+	s.npc = npc
+	s.tree = d2dialogue.ForNPC(monstat.Key)
+	s.options = options
+
+	s.npc.StartTalking(s.hero.Position)
+	s.title.SetText(npc.Label())
+
+	s.isOpen = true
+	s.panelGroup.SetVisible(true)
+	s.showMenu()
+}
+
+// Close closes the talk menu, stops the speech and lets the npc walk again
+func (s *NPCDialogue) Close() {
+	if !s.isOpen {
+		return
+	}
+
+	s.stopSpeech()
+
+	if s.npc != nil {
+		s.npc.StopTalking()
+		s.npc = nil
+	}
+
+	s.isOpen = false
+	s.panelGroup.SetVisible(false)
+	s.setRowsVisible()
+
+	if s.onCloseCb != nil {
+		s.onCloseCb()
+	}
+}
+
+// IsOpen returns true if the talk menu is open
+func (s *NPCDialogue) IsOpen() bool {
+	return s.isOpen
+}
+
+// IsSpeaking returns true if the npc is telling one of the topics
+func (s *NPCDialogue) IsSpeaking() bool {
+	return s.isOpen && s.isSpeaking
+}
+
+// Contains returns true if the screen position is inside the dialogue box
+func (s *NPCDialogue) Contains(x, y int) bool {
+	return x >= npcDialogueX && x < npcDialogueX+npcDialogueWidth &&
+		y >= npcDialogueY && y < npcDialogueY+npcDialogueHeight
+}
+
+// SetOnCloseCb sets the callback run on closing the talk menu
+func (s *NPCDialogue) SetOnCloseCb(cb func()) {
+	s.onCloseCb = cb
+}
+
+// SetOnTopicCb sets the callback run when the hero talks about a quest topic, with the
+// trigger to send to the server
+func (s *NPCDialogue) SetOnTopicCb(cb func(trigger d2quest.Trigger)) {
+	s.onTopicCb = cb
+}
+
+// Skip stops telling the current topic and goes back to the menu
+func (s *NPCDialogue) Skip() {
+	if s.IsSpeaking() {
+		s.showMenu()
+	}
+}
+
+// Advance scrolls the text of the topic being told
+func (s *NPCDialogue) Advance(elapsed float64) {
+	if !s.IsSpeaking() {
+		return
+	}
+
+	s.elapsed += elapsed
+
+	// the text is shown for as long as it would take to scroll all of its lines, then the menu comes back
+	if s.elapsed > float64(len(s.lines))/npcDialogueLinesPerSec+npcDialogueHoldSeconds {
+		s.showMenu()
+		return
+	}
+
+	scrolled := int(s.elapsed * npcDialogueLinesPerSec)
+	if maxScroll := len(s.lines) - npcDialogueVisibleLines; scrolled > maxScroll {
+		scrolled = maxScroll
+	}
+
+	if scrolled < 0 {
+		scrolled = 0
+	}
+
+	last := scrolled + npcDialogueVisibleLines
+	if last > len(s.lines) {
+		last = len(s.lines)
+	}
+
+	s.text.SetText(strings.Join(s.lines[scrolled:last], "\n"))
+}
+
+// showMenu lists the topics the npc can talk about now, they depend on the quest state of the hero
+func (s *NPCDialogue) showMenu() {
+	s.stopSpeech()
+
+	s.isSpeaking = false
+	s.text.SetVisible(false)
+	s.title.SetVisible(true)
+
+	s.menu = make([]npcOption, 0, npcDialogueMaxRows)
+
+	if s.tree != nil {
+		key := s.tree.NPC
+
+		for _, topic := range s.tree.Topics(s.hero.Quests, s.hero.Difficulty, s.talkCounts[key]) {
+			t := topic
+			s.menu = append(s.menu, npcOption{label: s.topicLabel(t), action: func() { s.tell(t) }})
+		}
+	}
+
+	s.menu = append(s.menu, s.options...)
+	s.menu = append(s.menu, npcOption{label: s.asset.TranslateString(npcDialogueCancelKey), action: s.Close})
+
+	if len(s.menu) > npcDialogueMaxRows {
+		// cancel is always the last entry
+		s.menu = append(s.menu[:npcDialogueMaxRows-1], s.menu[len(s.menu)-1])
+	}
+
+	for idx, entry := range s.menu {
+		s.rows[idx].SetText(entry.label)
+	}
+
+	s.setRowsVisible()
+}
+
+func (s *NPCDialogue) topicLabel(topic *d2dialogue.Topic) string {
+	switch topic.Type {
+	case d2dialogue.TopicIntro:
+		return s.asset.TranslateString(npcDialogueIntroKey)
+	case d2dialogue.TopicGossip:
+		return s.asset.TranslateString(npcDialogueGossipKey)
+	}
+
+	quest := d2quest.Quest(topic.Quest)
+	if quest == nil {
+		return s.asset.TranslateString(npcDialogueGossipKey)
+	}
+
+	return s.asset.TranslateString(fmt.Sprintf(npcDialogueQuestLabelFmt, quest.Act, quest.Number))
+}
+
+// tell makes the npc tell the topic, talking about a quest may advance it
+func (s *NPCDialogue) tell(topic *d2dialogue.Topic) {
+	switch topic.Type {
+	case d2dialogue.TopicGossip:
+		s.talkCounts[s.tree.NPC]++
+	case d2dialogue.TopicQuest:
+		if s.onTopicCb != nil {
+			s.onTopicCb(d2quest.TalkTrigger(s.tree.NPC))
+		}
+	}
+
+	text := s.asset.TranslateString(topic.Speech)
+	s.lines = make([]string, 0)
+
+	for _, paragraph := range strings.Split(text, "\n") {
+		s.lines = append(s.lines, d2util.SplitIntoLinesWithMaxWidth(paragraph, npcDialogueLineChars)...)
+	}
+
+	s.elapsed = 0
+	s.isSpeaking = true
+	s.title.SetVisible(false)
+	s.setRowsVisible()
+	s.text.SetVisible(true)
+	s.Advance(0)
+
+	// not every topic has a speech, the sound engine only plays the ones found in sounds.txt
+	s.speech = s.soundEngine.PlaySoundHandle(topic.Speech)
+}
+
+func (s *NPCDialogue) stopSpeech() {
+	if s.speech != nil {
+		s.speech.Stop()
+		s.speech = nil
+	}
+}
+
+func (s *NPCDialogue) onRowActivated(row int) {
+	if !s.isOpen || s.isSpeaking || row >= len(s.menu) {
+		return
+	}
+
+	s.menu[row].action()
+}
+
+func (s *NPCDialogue) setRowsVisible() {
+	for idx := range s.rows {
+		s.rows[idx].SetVisible(s.isOpen && !s.isSpeaking && idx < len(s.menu))
+	}
+}
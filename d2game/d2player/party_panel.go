@@ -12,7 +12,9 @@ import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2hero"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2party"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2ui"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket"
 )
 
 const (
@@ -67,7 +69,8 @@ func NewPartyPanel(asset *d2asset.AssetManager,
 	l d2util.LogLevel,
 	me *d2mapentity.Player,
 	heroState *d2hero.HeroStatsState,
-	players map[string]*d2mapentity.Player) *PartyPanel {
+	players map[string]*d2mapentity.Player,
+	parties *d2party.Parties) *PartyPanel {
 	log.Print("OpenDiablo2 - Party Panel - development")
 
 	originX := 0
@@ -84,6 +87,7 @@ func NewPartyPanel(asset *d2asset.AssetManager,
 		barX:      barX,
 		barY:      baseBarY,
 		players:   players,
+		parties:   parties,
 		me:        me,
 	}
 
@@ -120,8 +124,10 @@ type PartyPanel struct {
 	partyIndexes [d2enum.MaxPlayersInGame]*partyIndex
 	indexes      [d2enum.MaxPlayersInGame]*d2ui.WidgetGroup
 
-	players map[string]*d2mapentity.Player
-	me      *d2mapentity.Player
+	players    map[string]*d2mapentity.Player
+	parties    *d2party.Parties
+	me         *d2mapentity.Player
+	onActionCb func(targetID string, action d2netpacket.PartyAction)
 
 	originX int
 	originY int
@@ -164,6 +170,9 @@ func (s *PartyPanel) newPartyIndex() *partyIndex {
 	result.relationshipsInactiveTooltip.SetText(s.asset.TranslateString("strParty9") + "\n" + s.asset.TranslateString("strParty8"))
 	relationships.SetInactiveTooltip(result.relationshipsInactiveTooltip)
 
+	relationships.OnActivated(func() { s.partyAction(result, d2netpacket.PartyActionDeclareHostile) })
+	relationships.OnDeactivated(func() { s.partyAction(result, d2netpacket.PartyActionEndHostility) })
+
 	result.relationshipSwitcher = relationships
 
 	seeing := s.createSwitcher(d2enum.PartyButtonSeeingFrame)
@@ -192,6 +201,15 @@ func (s *PartyPanel) newPartyIndex() *partyIndex {
 
 	result.inviteAcceptButton = s.uiManager.NewButton(d2ui.ButtonTypePartyButton, s.asset.TranslateString("Invite"))
 	result.inviteAcceptButton.SetVisible(false)
+	result.inviteAcceptButton.OnActivated(func() { s.partyAction(result, d2netpacket.PartyActionInvite) })
+
+	result.acceptButton = s.uiManager.NewButton(d2ui.ButtonTypePartyButton, s.asset.TranslateString("Accept"))
+	result.acceptButton.SetVisible(false)
+	result.acceptButton.OnActivated(func() { s.partyAction(result, d2netpacket.PartyActionAccept) })
+
+	result.leaveButton = s.uiManager.NewButton(d2ui.ButtonTypePartyButton, s.asset.TranslateString("Leave"))
+	result.leaveButton.SetVisible(false)
+	result.leaveButton.OnActivated(func() { s.partyAction(result, d2netpacket.PartyActionLeave) })
 
 	return result
 }
@@ -217,6 +235,8 @@ type partyIndex struct {
 	listeningActiveTooltip       *d2ui.Tooltip
 	listeningInactiveTooltip     *d2ui.Tooltip
 	inviteAcceptButton           *d2ui.Button
+	acceptButton                 *d2ui.Button
+	leaveButton                  *d2ui.Button
 	relationships                d2enum.PlayersRelationships
 }
 
@@ -240,9 +260,16 @@ func (pi *partyIndex) setColor(relations d2enum.PlayersRelationships) {
 		pi.relationshipSwitcher.SetState(false)
 	case d2enum.PlayerRelationFriend:
 		color = d2util.Color(lightGreen)
+
+		pi.relationshipSwitcher.SetState(true)
+		pi.relationshipSwitcher.SetEnabled(false)
 	case d2enum.PlayerRelationNeutral:
+		pi.relationshipSwitcher.SetState(true)
+
 		if pi.CanGoHostile() {
 			color = d2util.Color(white)
+
+			pi.relationshipSwitcher.SetEnabled(true)
 		} else {
 			color = d2util.Color(orange)
 			pi.relationshipSwitcher.SetEnabled(false)
@@ -293,6 +320,26 @@ func (pi *partyIndex) setPositions(idx int) {
 	pi.listeningInactiveTooltip.SetPosition(listeningSwitcherX+buttonSize, baseListeningSwitcherY+idx*indexOffset-h)
 
 	pi.inviteAcceptButton.SetPosition(inviteAcceptButtonX, baseInviteAcceptButtonY+idx*indexOffset)
+	pi.acceptButton.SetPosition(inviteAcceptButtonX, baseInviteAcceptButtonY+idx*indexOffset)
+	pi.leaveButton.SetPosition(inviteAcceptButtonX, baseInviteAcceptButtonY+idx*indexOffset)
+}
+
+// updatePartyButtons shows the button of the party action which is possible with the player,
+// inviting them, accepting their invitation or leaving the party shared with them
+func (pi *partyIndex) updatePartyButtons(parties *d2party.Parties, visible bool) {
+	visible = visible && pi.hero != nil
+
+	var inParty, invited bool
+
+	if visible {
+		inParty = parties.InSameParty(pi.me.ID(), pi.hero.ID())
+		invited = parties.IsInvited(pi.me.ID(), pi.hero.ID())
+	}
+
+	pi.leaveButton.SetVisible(visible && inParty)
+	pi.acceptButton.SetVisible(visible && !inParty && invited)
+	pi.inviteAcceptButton.SetVisible(visible && !inParty && !invited &&
+		pi.relationships != d2enum.PlayerRelationEnemy)
 }
 
 func (pi *partyIndex) CanGoHostile() bool {
@@ -348,6 +395,8 @@ func (s *PartyPanel) Load() {
 		s.indexes[n].AddWidget(i.listeningSwitcher)
 		s.indexes[n].AddWidget(i.level)
 		s.indexes[n].AddWidget(i.inviteAcceptButton)
+		s.indexes[n].AddWidget(i.acceptButton)
+		s.indexes[n].AddWidget(i.leaveButton)
 	}
 
 	// create bar
@@ -397,6 +446,7 @@ func (s *PartyPanel) Open() {
 	for n, i := range s.indexes {
 		if s.partyIndexes[n].hero != nil {
 			i.SetVisible(true)
+			s.partyIndexes[n].updatePartyButtons(s.parties, true)
 		}
 	}
 }
@@ -411,6 +461,20 @@ func (s *PartyPanel) Close() {
 	}
 }
 
+// SetOnPartyActionCb sets the callback run when the player invites, accepts, leaves or
+// changes their hostility towards another player
+func (s *PartyPanel) SetOnPartyActionCb(cb func(targetID string, action d2netpacket.PartyAction)) {
+	s.onActionCb = cb
+}
+
+func (s *PartyPanel) partyAction(index *partyIndex, action d2netpacket.PartyAction) {
+	if index.hero == nil || s.onActionCb == nil {
+		return
+	}
+
+	s.onActionCb(index.hero.ID(), action)
+}
+
 // SetOnCloseCb the callback run on closing the PartyPanel
 func (s *PartyPanel) SetOnCloseCb(cb func()) {
 	s.onCloseCb = cb
@@ -547,7 +611,7 @@ func (s *PartyPanel) setBarPosition() {
 func (s *PartyPanel) UpdatePanel() {
 	for _, i := range s.players {
 		if !s.IsInPanel(i) && !s.IsMe(i) {
-			s.AddPlayer(i, d2enum.PlayerRelationNeutral)
+			s.AddPlayer(i, s.parties.Relationship(s.me.ID(), i.ID()))
 
 			// we need to switch all hidden widgets to be visible
 			// s.Open contains appropriate code to do that.
@@ -570,6 +634,24 @@ func (s *PartyPanel) Advance(_ float64) {
 	}
 
 	s.UpdatePanel()
+	s.updateRelationships()
+}
+
+// updateRelationships applies the parties and hostility sent by the server to the party indexes
+func (s *PartyPanel) updateRelationships() {
+	for _, i := range s.partyIndexes {
+		if i.hero == nil {
+			continue
+		}
+
+		if relations := s.parties.Relationship(s.me.ID(), i.hero.ID()); relations != i.relationships {
+			i.relationships = relations
+			i.setColor(relations)
+			i.setNameTooltipText()
+		}
+
+		i.updatePartyButtons(s.parties, true)
+	}
 }
 
 // OnMouseMove handles mouse movement events
@@ -9,6 +9,8 @@ import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2util"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2quest"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2ui"
 )
 
@@ -74,41 +76,10 @@ func NewQuestLog(asset *d2asset.AssetManager,
 	ui *d2ui.UIManager,
 	l d2util.LogLevel,
 	audioProvider d2interface.AudioProvider,
-	act int) *QuestLog {
+	hero *d2mapentity.Player) *QuestLog {
 	originX := 0
 	originY := 0
 
-	//nolint:gomnd // this is only test
-	qs := map[int]int{
-		0:  -2,
-		1:  -2,
-		2:  -2,
-		3:  0,
-		4:  1,
-		5:  4,
-		6:  3,
-		7:  -1,
-		8:  0,
-		9:  0,
-		10: 0,
-		11: 0,
-		12: 0,
-		13: 0,
-		14: 0,
-		15: 0,
-		16: 0,
-		17: 0,
-		18: 0,
-		19: 0,
-		20: 0,
-		21: 0,
-		22: 0,
-		23: 0,
-		24: 0,
-		25: 0,
-		26: 0,
-	}
-
 	var quests [d2enum.ActsNumber]*questEntire
 	for i := 0; i < d2enum.ActsNumber; i++ {
 		quests[i] = &questEntire{WidgetGroup: ui.NewWidgetGroup(d2ui.RenderPriorityQuestLog)}
@@ -119,19 +90,17 @@ func NewQuestLog(asset *d2asset.AssetManager,
 		tabs[i] = questLogTab{}
 	}
 
-	// nolint:gomnd // this is only test, it also should come from save file
-	mpa := 2
-
 	ql := &QuestLog{
 		asset:         asset,
 		uiManager:     ui,
 		originX:       originX,
 		originY:       originY,
-		act:           act,
+		hero:          hero,
+		act:           hero.Act,
+		difficulty:    hero.Difficulty,
 		tab:           tabs,
 		quests:        quests,
-		questStatus:   qs,
-		maxPlayersAct: mpa,
+		maxPlayersAct: hero.Act,
 		audioProvider: audioProvider,
 	}
 
@@ -159,9 +128,12 @@ type QuestLog struct {
 	questName     *d2ui.Label
 	questDescr    *d2ui.Label
 	quests        [d2enum.ActsNumber]*questEntire
-	questStatus   map[int]int
+	hero          *d2mapentity.Player
+	difficulty    d2enum.DifficultyType
 	maxPlayersAct int
 
+	onQuestViewedCb func(questID int)
+
 	originX int
 	originY int
 	isOpen  bool
@@ -266,8 +238,8 @@ func (s *QuestLog) loadTabs() {
 
 	tabsResource := d2resource.WPTabs
 
-	// create tabs only for 'discovered' acts
-	for i := 0; i < s.maxPlayersAct; i++ {
+	// tabs of acts which aren't 'discovered' yet are hidden, see updateTabs
+	for i := 0; i < d2enum.ActsNumber; i++ {
 		currentValue := i
 
 		s.tab[i].sprite, err = s.uiManager.NewSprite(tabsResource, d2resource.PaletteSky)
@@ -331,7 +303,7 @@ func (s *QuestLog) loadQuestBoard(act int) (wg *d2ui.WidgetGroup, icons []*d2ui.
 
 		button := s.uiManager.NewButton(d2ui.ButtonTypeBlankQuestBtn, "")
 		button.SetPosition(x+questOffsetX, y+questOffsetY)
-		button.SetEnabled(s.questStatus[s.cordsToQuestID(act, cw)] != d2enum.QuestStatusNotStarted)
+		button.SetEnabled(s.questStatus(s.cordsToQuestID(act, cw)) != d2enum.QuestStatusNotStarted)
 		buttons = append(buttons, button)
 	}
 
@@ -389,28 +361,95 @@ func (s *QuestLog) makeQuestIconForAct(act, n, x, y int) (*d2ui.Sprite, error) {
 		s.Fatalf("during creating new quest icons for act %d (icon sprite %s doesn't exist). %s", act, iconResource, err.Error())
 	}
 
-	switch s.questStatus[s.cordsToQuestID(act, n)] {
+	err = icon.SetCurrentFrame(questIconFrame(s.questStatus(s.cordsToQuestID(act, n))))
+
+	icon.SetPosition(x+questOffsetX, y+questOffsetY+iconOffsetY)
+
+	return icon, err
+}
+
+// questIconFrame returns the frame of the quest icon, for the quest status
+func questIconFrame(status int) int {
+	switch status {
 	case d2enum.QuestStatusCompleted:
-		err = icon.SetCurrentFrame(completedFrame)
+		return completedFrame
 	case d2enum.QuestStatusCompleting:
 		// animation will be played after quest-log panel is opened (see s.playQuestAnimation)
-		err = icon.SetCurrentFrame(0)
+		return 0
 	case d2enum.QuestStatusNotStarted:
-		err = icon.SetCurrentFrame(notStartedFrame)
+		return notStartedFrame
 	default:
-		err = icon.SetCurrentFrame(inProgresFrame)
+		return inProgresFrame
 	}
+}
 
-	icon.SetPosition(x+questOffsetX, y+questOffsetY+iconOffsetY)
+// questStatus returns the status of the quest, from the quest state of the hero
+func (s *QuestLog) questStatus(questID int) int {
+	if s.hero.Quests == nil {
+		return d2enum.QuestStatusNotStarted
+	}
 
-	return icon, err
+	return s.hero.Quests.Status(s.difficulty, questID)
+}
+
+// setQuestCompleted marks the completion animation of the quest as played
+func (s *QuestLog) setQuestCompleted(questID int) {
+	if s.hero.Quests == nil {
+		return
+	}
+
+	s.hero.Quests.SetStatus(s.difficulty, questID, d2enum.QuestStatusCompleted)
+
+	if s.onQuestViewedCb != nil {
+		s.onQuestViewedCb(questID)
+	}
+}
+
+// SetOnQuestViewedCb sets the callback run when the completion animation of a quest was shown
+func (s *QuestLog) SetOnQuestViewedCb(cb func(questID int)) {
+	s.onQuestViewedCb = cb
+}
+
+// updateQuestBoards sets the quest icons and buttons of all acts to the current quest statuses
+func (s *QuestLog) updateQuestBoards() {
+	for act := d2enum.Act1; act <= d2enum.ActsNumber; act++ {
+		board := s.quests[act-1]
+
+		for n, icon := range board.icons {
+			status := s.questStatus(s.cordsToQuestID(act, n))
+
+			board.buttons[n].SetEnabled(status != d2enum.QuestStatusNotStarted)
+
+			if err := icon.SetCurrentFrame(questIconFrame(status)); err != nil {
+				s.Error(err.Error())
+			}
+		}
+	}
+}
+
+// updateTabs shows the tabs of the acts which the hero has 'discovered'
+func (s *QuestLog) updateTabs() {
+	s.maxPlayersAct = s.hero.Act
+
+	if s.hero.Quests != nil && s.hero.Quests.MaxAct(s.difficulty) > s.maxPlayersAct {
+		s.maxPlayersAct = s.hero.Quests.MaxAct(s.difficulty)
+	}
+
+	for i := 0; i < d2enum.ActsNumber; i++ {
+		s.tab[i].sprite.SetVisible(s.isOpen && i < s.maxPlayersAct)
+		s.tab[i].invisibleButton.SetVisible(s.isOpen && i < s.maxPlayersAct)
+	}
+
+	if s.selectedTab >= s.maxPlayersAct {
+		s.selectedTab = s.maxPlayersAct - 1
+	}
 }
 
 // playQuestAnimations plays animations for quests (when status=questStatusCompleting)
 func (s *QuestLog) playQuestAnimations() {
 	for j, i := range s.quests[s.selectedTab].icons {
 		questID := s.cordsToQuestID(s.selectedTab+1, j)
-		if s.questStatus[questID] == d2enum.QuestStatusCompleting {
+		if s.questStatus(questID) == d2enum.QuestStatusCompleting {
 			s.completeSound.Play()
 
 			// quest should be highlighted and it's label should be displayed
@@ -429,8 +468,8 @@ func (s *QuestLog) stopPlayedAnimations() {
 	// stops all played animations
 	for j, i := range s.quests[s.selectedTab].icons {
 		questID := s.cordsToQuestID(s.selectedTab+1, j)
-		if s.questStatus[questID] == d2enum.QuestStatusCompleting {
-			s.questStatus[questID] = d2enum.QuestStatusCompleted
+		if s.questStatus(questID) == d2enum.QuestStatusCompleting {
+			s.setQuestCompleted(questID)
 
 			err := i.SetCurrentFrame(completedFrame)
 			if err != nil {
@@ -451,7 +490,7 @@ func (s *QuestLog) setQuestLabel() {
 
 	s.questName.SetText(s.asset.TranslateString(fmt.Sprintf("qstsa%dq%d", s.selectedTab+1, s.selectedQuest)))
 
-	status := s.questStatus[s.cordsToQuestID(s.selectedTab+1, s.selectedQuest)-1]
+	status := s.questStatus(s.cordsToQuestID(s.selectedTab+1, s.selectedQuest-1))
 	switch status {
 	case d2enum.QuestStatusCompleted, d2enum.QuestStatusCompleting:
 		s.questDescr.SetText(
@@ -500,11 +539,14 @@ func (s *QuestLog) setTab(tab int) {
 	s.selectedTab = tab
 	s.selectedQuest = d2enum.QuestNone
 	s.setQuestLabel()
-	s.playQuestAnimations()
+
+	if s.isOpen {
+		s.playQuestAnimations()
+	}
 
 	// displays appropriate quests board
-	for i := 0; i < s.maxPlayersAct; i++ {
-		s.quests[i].SetVisible(tab == i)
+	for i := 0; i < d2enum.ActsNumber; i++ {
+		s.quests[i].SetVisible(s.isOpen && tab == i)
 	}
 
 	// "highlights" appropriate tab
@@ -555,8 +597,9 @@ func (s *QuestLog) Toggle() {
 func (s *QuestLog) Open() {
 	s.isOpen = true
 	s.panelGroup.SetVisible(true)
+	s.updateTabs()
+	s.updateQuestBoards()
 	s.setTab(s.selectedTab)
-	s.playQuestAnimations()
 }
 
 // Close closed the hero status panel
@@ -564,7 +607,7 @@ func (s *QuestLog) Close() {
 	s.isOpen = false
 	s.panelGroup.SetVisible(false)
 
-	for i := 0; i < s.maxPlayersAct; i++ {
+	for i := 0; i < d2enum.ActsNumber; i++ {
 		s.quests[i].SetVisible(false)
 	}
 
@@ -586,13 +629,13 @@ func (s *QuestLog) Advance(elapsed float64) {
 
 	for j, i := range s.quests[s.selectedTab].icons {
 		questID := s.cordsToQuestID(s.selectedTab+1, j)
-		if s.questStatus[questID] == d2enum.QuestStatusCompleting {
+		if s.questStatus(questID) == d2enum.QuestStatusCompleting {
 			if err := i.Advance(elapsed); err != nil {
 				s.Error(err.Error())
 			}
 
 			if i.GetCurrentFrame() == completedFrame {
-				s.questStatus[questID] = d2enum.QuestStatusCompleted
+				s.setQuestCompleted(questID)
 			}
 		}
 	}
@@ -635,10 +678,5 @@ func (s *QuestLog) renderStaticPanelFrames(target d2interface.Surface) {
 }
 
 func (s *QuestLog) cordsToQuestID(act, number int) int {
-	key := (act-1)*d2enum.NormalActQuestsNumber + number
-	if act > d2enum.Act4 {
-		key -= d2enum.HalfQuestsNumber
-	}
-
-	return key
+	return d2quest.ID(act, number+1)
 }
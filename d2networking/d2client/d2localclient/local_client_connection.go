@@ -23,6 +23,7 @@ type LocalClientConnection struct {
 	openNetworkServer bool                        // True if this is a server
 	playerState       *d2hero.HeroState           // Local player state
 	gameServer        *d2server.GameServer        // Game Server
+	packets           d2networking.PacketQueue    // Packets of the server, not received yet
 
 	logLevel d2util.LogLevel
 }
@@ -38,9 +39,18 @@ func (l *LocalClientConnection) GetConnectionType() d2clientconnectiontype.Clien
 	return d2clientconnectiontype.Local
 }
 
-// SendPacketToClient passes a packet to the game client for processing.
+// SendPacketToClient queues a packet for the game client, it is processed when the client
+// receives the packets. The server calls this while holding its lock, so the game client never
+// handles packets on the goroutine of the server.
 func (l *LocalClientConnection) SendPacketToClient(packet d2netpacket.NetPacket) error {
-	return l.clientListener.OnPacketReceived(packet)
+	l.packets.Push(packet)
+
+	return nil
+}
+
+// ReceivePackets passes the queued packets of the server to the game client.
+func (l *LocalClientConnection) ReceivePackets() error {
+	return l.packets.Receive(l.clientListener)
 }
 
 // Create constructs a new LocalClientConnection and returns
@@ -82,7 +92,8 @@ func (l *LocalClientConnection) Open(_, saveFilePath string) error {
 
 	l.gameServer.OnClientConnected(l)
 
-	return nil
+	// the game is created from the packets sent on connecting
+	return l.ReceivePackets()
 }
 
 // Close disconnects from the server and destroys it.
@@ -100,9 +111,14 @@ func (l *LocalClientConnection) Close() error {
 	return nil
 }
 
-// SendPacketToServer calls d2server.OnPacketReceived with the given packet.
+// SendPacketToServer calls d2server.OnPacketReceived with the given packet, and passes the
+// packets the server answered with to the game client.
 func (l *LocalClientConnection) SendPacketToServer(packet d2netpacket.NetPacket) error {
-	return l.gameServer.OnPacketReceived(l, packet)
+	if err := l.gameServer.OnPacketReceived(l, packet); err != nil {
+		return err
+	}
+
+	return l.ReceivePackets()
 }
 
 // SetClientListener sets LocalClientConnection.clientListener to the given value.
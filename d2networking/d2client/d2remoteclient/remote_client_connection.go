@@ -29,6 +29,7 @@ type RemoteClientConnection struct {
 	uniqueID       string                      // Unique ID generated on construction
 	tcpConnection  *net.TCPConn                // UDP connection to the server
 	active         bool                        // The connection is currently open
+	packets        d2networking.PacketQueue    // Packets of the server, not received yet
 
 	*d2util.Logger
 }
@@ -114,13 +115,13 @@ func (r *RemoteClientConnection) Close() error {
 }
 
 // GetUniqueID returns RemoteClientConnection.uniqueID.
-func (r RemoteClientConnection) GetUniqueID() string {
+func (r *RemoteClientConnection) GetUniqueID() string {
 	return r.uniqueID
 }
 
 // GetConnectionType returns an enum representing the connection type.
 // See: d2clientconnectiontype
-func (r RemoteClientConnection) GetConnectionType() d2clientconnectiontype.ClientConnectionType {
+func (r *RemoteClientConnection) GetConnectionType() d2clientconnectiontype.ClientConnectionType {
 	return d2clientconnectiontype.LANClient
 }
 
@@ -167,13 +168,15 @@ func (r *RemoteClientConnection) serverListener() {
 			r.Errorf("%v %v", packet.PacketType, err)
 		}
 
-		err = r.clientListener.OnPacketReceived(p)
-		if err != nil {
-			r.Errorf("%v %v", packet.PacketType, err)
-		}
+		r.packets.Push(p)
 	}
 }
 
+// ReceivePackets passes the packets read from the server to the game client.
+func (r *RemoteClientConnection) ReceivePackets() error {
+	return r.packets.Receive(r.clientListener)
+}
+
 // bytesToJSON reads the packet type, decompresses the packet and returns a JSON string.
 // nolint:unused // WIP
 func (r *RemoteClientConnection) bytesToJSON(buffer []byte) (string, d2netpackettype.NetPacketType, error) {
@@ -3,8 +3,10 @@ package d2client
 import (
 	"fmt"
 	"os"
+	"sync"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2hero"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2inventory"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapgen"
 
@@ -17,6 +19,8 @@ import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2util"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapengine"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2party"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2quest"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2records"
 	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2client/d2clientconnectiontype"
 	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2client/d2localclient"
@@ -39,13 +43,21 @@ type GameClient struct {
 	connectionType   d2clientconnectiontype.ClientConnectionType // Type of connection (local or remote)
 	asset            *d2asset.AssetManager
 	scriptEngine     *d2script.ScriptEngine
-	GameState        *d2hero.HeroState              // local player state
-	MapEngine        *d2mapengine.MapEngine         // Map and entities
-	mapGen           *d2mapgen.MapGenerator         // map generator
-	PlayerID         string                         // ID of the local player
-	Players          map[string]*d2mapentity.Player // IDs of the other players
-	Seed             int64                          // Map seed
-	RegenMap         bool                           // Regenerate tile cache on render (map has changed)
+	heroStateFactory *d2hero.HeroStateFactory
+	GameState        *d2hero.HeroState                         // local player state
+	MapEngine        *d2mapengine.MapEngine                    // Map and entities
+	mapGen           *d2mapgen.MapGenerator                    // map generator
+	PlayerID         string                                    // ID of the local player
+	Players          map[string]*d2mapentity.Player            // IDs of the other players
+	Hirelings        map[string]*d2mapentity.Hireling          // hirelings of the players, by player ID
+	Parties          *d2party.Parties                          // parties and hostility of the players
+	portals          map[string]d2netpacket.UpdatePortalPacket // open town portals, by owner ID
+	corpses          map[string]d2netpacket.UpdateCorpsePacket // corpses of the players, by corpse ID
+	chatMessages     []d2netpacket.ChatMessagePacket           // chat messages not yet shown by the game screen
+	chatMutex        sync.Mutex                                // guards chatMessages
+	Difficulty       d2enum.DifficultyType                     // Difficulty of the game
+	Seed             int64                                     // Map seed
+	RegenMap         bool                                      // Regenerate tile cache on render (map has changed)
 
 	*d2util.Logger
 }
@@ -59,6 +71,10 @@ func Create(connectionType d2clientconnectiontype.ClientConnectionType,
 		asset:          asset,
 		MapEngine:      d2mapengine.CreateMapEngine(l, asset),
 		Players:        make(map[string]*d2mapentity.Player),
+		Hirelings:      make(map[string]*d2mapentity.Hireling),
+		Parties:        d2party.NewParties(),
+		portals:        make(map[string]d2netpacket.UpdatePortalPacket),
+		corpses:        make(map[string]d2netpacket.UpdateCorpsePacket),
 		connectionType: connectionType,
 		scriptEngine:   scriptEngine,
 	}
@@ -78,6 +94,11 @@ func Create(connectionType d2clientconnectiontype.ClientConnectionType,
 
 	result.mapGen = mapGen
 
+	result.heroStateFactory, err = d2hero.NewHeroStateFactory(asset)
+	if err != nil {
+		return nil, err
+	}
+
 	switch connectionType {
 	case d2clientconnectiontype.LANClient:
 		result.clientConnection, err = d2remoteclient.Create(l, asset)
@@ -126,6 +147,12 @@ func (g *GameClient) Destroy() error {
 	return g.Close()
 }
 
+// ReceivePackets processes the packets the server sent since the last call, it is called by the
+// game loop so the packets are processed on its goroutine
+func (g *GameClient) ReceivePackets() error {
+	return g.clientConnection.ReceivePackets()
+}
+
 // OnPacketReceived is called by the ClientConection and processes incoming
 // packets.
 // nolint:gocyclo // switch statement on packet type makes sense, no need to change
@@ -155,6 +182,70 @@ func (g *GameClient) OnPacketReceived(packet d2netpacket.NetPacket) error {
 		if err := g.handleSpawnItemPacket(packet); err != nil {
 			return err
 		}
+	case d2netpackettype.UseBeltItem:
+		if err := g.handleUseBeltItemPacket(packet); err != nil {
+			return err
+		}
+	case d2netpackettype.PickupItem:
+		if err := g.handlePickupItemPacket(packet); err != nil {
+			return err
+		}
+	case d2netpackettype.UpdateEquipment:
+		if err := g.handleUpdateEquipmentPacket(packet); err != nil {
+			return err
+		}
+	case d2netpackettype.UpdateHireling:
+		if err := g.handleUpdateHirelingPacket(packet); err != nil {
+			return err
+		}
+	case d2netpackettype.UpdateQuests:
+		if err := g.handleUpdateQuestsPacket(packet); err != nil {
+			return err
+		}
+	case d2netpackettype.UpdateAutomap:
+		if err := g.handleUpdateAutomapPacket(packet); err != nil {
+			return err
+		}
+	case d2netpackettype.UpdateStates:
+		if err := g.handleUpdateStatesPacket(packet); err != nil {
+			return err
+		}
+	case d2netpackettype.UpdateObject:
+		if err := g.handleUpdateObjectPacket(packet); err != nil {
+			return err
+		}
+	case d2netpackettype.UpdatePortal:
+		if err := g.handleUpdatePortalPacket(packet); err != nil {
+			return err
+		}
+	case d2netpackettype.WarpPlayer:
+		if err := g.handleWarpPlayerPacket(packet); err != nil {
+			return err
+		}
+	case d2netpackettype.KillPlayer:
+		if err := g.handleKillPlayerPacket(packet); err != nil {
+			return err
+		}
+	case d2netpackettype.UpdateCorpse:
+		if err := g.handleUpdateCorpsePacket(packet); err != nil {
+			return err
+		}
+	case d2netpackettype.UpdateParty:
+		if err := g.handleUpdatePartyPacket(packet); err != nil {
+			return err
+		}
+	case d2netpackettype.GainExperience:
+		if err := g.handleGainExperiencePacket(packet); err != nil {
+			return err
+		}
+	case d2netpackettype.ChatMessage:
+		if err := g.handleChatMessagePacket(packet); err != nil {
+			return err
+		}
+	case d2netpackettype.Hit:
+		if err := g.handleHitPacket(packet); err != nil {
+			return err
+		}
 	case d2netpackettype.Ping:
 		if err := g.handlePingPacket(); err != nil {
 			g.Errorf("GameClient: error responding to server ping: %s", err)
@@ -193,6 +284,20 @@ func (g *GameClient) handleGenerateMapPacket(packet d2netpacket.NetPacket) error
 		g.mapGen.GenerateAct1Overworld()
 	}
 
+	// the town portals outlive the map they are on, they are put back on the new map
+	for _, portal := range g.portals {
+		if err := g.addPortalEntities(portal); err != nil {
+			return err
+		}
+	}
+
+	// so do the corpses
+	for _, corpse := range g.corpses {
+		if err := g.addCorpseEntity(corpse); err != nil {
+			return err
+		}
+	}
+
 	g.RegenMap = true
 
 	return nil
@@ -207,6 +312,8 @@ func (g *GameClient) handleUpdateServerInfoPacket(packet d2netpacket.NetPacket)
 	g.MapEngine.SetSeed(serverInfo.Seed)
 	g.PlayerID = serverInfo.PlayerID
 	g.Seed = serverInfo.Seed
+	g.Difficulty = serverInfo.Difficulty
+	g.MapEngine.SetDifficulty(serverInfo.Difficulty)
 	g.Infof("Player id set to %s", serverInfo.PlayerID)
 
 	return nil
@@ -221,7 +328,9 @@ func (g *GameClient) handleAddPlayerPacket(packet d2netpacket.NetPacket) error {
 	d2hero.HydrateSkills(player.Skills, g.asset)
 
 	newPlayer := g.MapEngine.NewPlayer(player.ID, player.Name, player.X, player.Y, 0,
-		player.HeroType, player.Stats, player.Skills, &player.Equipment, player.LeftSkill, player.RightSkill, player.Gold)
+		player.HeroType, player.Stats, player.Skills, &player.Equipment, player.Belt, player.LeftSkill, player.RightSkill, player.Gold)
+
+	newPlayer.Difficulty = g.Difficulty
 
 	g.Players[newPlayer.ID()] = newPlayer
 	g.MapEngine.AddEntity(newPlayer)
@@ -235,7 +344,7 @@ func (g *GameClient) handleSpawnItemPacket(packet d2netpacket.NetPacket) error {
 		return err
 	}
 
-	itemEntity, err := g.MapEngine.NewItem(item.X, item.Y, item.Codes...)
+	itemEntity, err := g.MapEngine.NewItem(item.ID, item.X, item.Y, item.Codes...)
 
 	if err == nil {
 		g.MapEngine.AddEntity(itemEntity)
@@ -244,6 +353,389 @@ func (g *GameClient) handleSpawnItemPacket(packet d2netpacket.NetPacket) error {
 	return err
 }
 
+func (g *GameClient) handleUseBeltItemPacket(packet d2netpacket.NetPacket) error {
+	usePacket, err := d2netpacket.UnmarshalUseBeltItem(packet.PacketData)
+	if err != nil {
+		return err
+	}
+
+	player := g.Players[usePacket.PlayerID]
+	if player == nil {
+		return fmt.Errorf("unknown player: %s", usePacket.PlayerID)
+	}
+
+	if item := player.Belt.Peek(usePacket.Column); item == nil || item.ItemCode != usePacket.ItemCode {
+		g.Warningf("belt of player %s is out of sync in column %d", usePacket.PlayerID, usePacket.Column)
+		return nil
+	}
+
+	player.Belt.Use(usePacket.Column)
+
+	effect, err := g.heroStateFactory.NewPotionEffect(usePacket.ItemCode, player.Stats)
+	if err != nil {
+		return err
+	}
+
+	player.AddPotionEffect(effect)
+
+	return nil
+}
+
+func (g *GameClient) handlePickupItemPacket(packet d2netpacket.NetPacket) error {
+	pickupPacket, err := d2netpacket.UnmarshalPickupItem(packet.PacketData)
+	if err != nil {
+		return err
+	}
+
+	if item, ok := g.MapEngine.Entities()[pickupPacket.ItemID]; ok {
+		g.MapEngine.RemoveEntity(item)
+	}
+
+	player := g.Players[pickupPacket.PlayerID]
+	if player == nil {
+		return fmt.Errorf("unknown player: %s", pickupPacket.PlayerID)
+	}
+
+	if !g.heroStateFactory.PickupItem(player.Equipment, player.Belt, pickupPacket.ItemCode, pickupPacket.Ethereal) {
+		g.Warningf("belt of player %s is out of sync, no room for %s", pickupPacket.PlayerID, pickupPacket.ItemCode)
+	}
+
+	return nil
+}
+
+// handleUpdateEquipmentPacket applies the durability of the equipment and the gold of a player
+// as told by the server
+func (g *GameClient) handleUpdateEquipmentPacket(packet d2netpacket.NetPacket) error {
+	updatePacket, err := d2netpacket.UnmarshalUpdateEquipment(packet.PacketData)
+	if err != nil {
+		return err
+	}
+
+	player := g.Players[updatePacket.PlayerID]
+	if player == nil || player.Equipment == nil {
+		return fmt.Errorf("unknown player: %s", updatePacket.PlayerID)
+	}
+
+	// only the durability changed, the items and so the composite of the player stay the same
+	*player.Equipment = updatePacket.Equipment
+	player.Gold = updatePacket.Gold
+
+	return nil
+}
+
+func (g *GameClient) handleUpdateHirelingPacket(packet d2netpacket.NetPacket) error {
+	updatePacket, err := d2netpacket.UnmarshalUpdateHireling(packet.PacketData)
+	if err != nil {
+		return err
+	}
+
+	player := g.Players[updatePacket.PlayerID]
+	if player == nil {
+		return fmt.Errorf("unknown player: %s", updatePacket.PlayerID)
+	}
+
+	player.Gold = updatePacket.Gold
+
+	if updatePacket.Action == d2netpacket.HirelingActionEquip && player.Hireling != nil {
+		err := g.heroStateFactory.SwapHirelingEquipment(player.Hireling, player.Equipment, updatePacket.Slot)
+		if err != nil {
+			g.Warningf("equipment of player %s is out of sync: %v", updatePacket.PlayerID, err)
+		}
+	}
+
+	player.Hireling = updatePacket.Hireling
+
+	// equipping and gaining experience only change the state of the hireling on the map
+	if updatePacket.Action == d2netpacket.HirelingActionEquip || updatePacket.Action == d2netpacket.HirelingActionUpdate {
+		if hireling, found := g.Hirelings[updatePacket.PlayerID]; found && updatePacket.Hireling != nil {
+			hireling.State = updatePacket.Hireling
+			return nil
+		}
+	}
+
+	if hireling, found := g.Hirelings[updatePacket.PlayerID]; found {
+		g.MapEngine.RemoveEntity(hireling)
+		delete(g.Hirelings, updatePacket.PlayerID)
+	}
+
+	if updatePacket.Hireling == nil {
+		return nil
+	}
+
+	hireling, err := g.MapEngine.NewHireling(int(player.Position.X()), int(player.Position.Y()),
+		updatePacket.Hireling, player)
+	if err != nil {
+		return err
+	}
+
+	hireling.SetTargetSource(g.MapEngine.Entities)
+
+	g.Hirelings[updatePacket.PlayerID] = hireling
+	g.MapEngine.AddEntity(hireling)
+
+	return nil
+}
+
+func (g *GameClient) handleUpdateQuestsPacket(packet d2netpacket.NetPacket) error {
+	updatePacket, err := d2netpacket.UnmarshalUpdateQuests(packet.PacketData)
+	if err != nil {
+		return err
+	}
+
+	player := g.Players[updatePacket.PlayerID]
+	if player == nil {
+		return fmt.Errorf("unknown player: %s", updatePacket.PlayerID)
+	}
+
+	player.Quests = updatePacket.Quests
+
+	for _, questID := range updatePacket.Completed {
+		if quest := d2quest.Quest(questID); quest != nil {
+			player.Stats.ApplyQuestRewards(quest.Rewards)
+		}
+	}
+
+	return nil
+}
+
+// handleUpdateAutomapPacket sets the explored tiles of the automap of the local player
+func (g *GameClient) handleUpdateAutomapPacket(packet d2netpacket.NetPacket) error {
+	updatePacket, err := d2netpacket.UnmarshalUpdateAutomap(packet.PacketData)
+	if err != nil {
+		return err
+	}
+
+	player := g.Players[updatePacket.PlayerID]
+	if player == nil {
+		return fmt.Errorf("unknown player: %s", updatePacket.PlayerID)
+	}
+
+	if updatePacket.Automap != nil {
+		player.Automap = updatePacket.Automap
+	}
+
+	return nil
+}
+
+// handleUpdatePartyPacket replaces the parties with the ones sent by the server, the parties
+// are shared with the game controls so they are updated in place
+func (g *GameClient) handleUpdatePartyPacket(packet d2netpacket.NetPacket) error {
+	updatePacket, err := d2netpacket.UnmarshalUpdateParty(packet.PacketData)
+	if err != nil {
+		return err
+	}
+
+	if updatePacket.Parties != nil {
+		*g.Parties = *updatePacket.Parties
+	}
+
+	for id, player := range g.Players {
+		player.SetHostile(g.Parties.IsHostile(g.PlayerID, id))
+	}
+
+	return nil
+}
+
+func (g *GameClient) handleGainExperiencePacket(packet d2netpacket.NetPacket) error {
+	gainPacket, err := d2netpacket.UnmarshalGainExperience(packet.PacketData)
+	if err != nil {
+		return err
+	}
+
+	player := g.Players[gainPacket.PlayerID]
+	if player == nil {
+		return fmt.Errorf("unknown player: %s", gainPacket.PlayerID)
+	}
+
+	player.Stats.Experience += gainPacket.Experience
+
+	return nil
+}
+
+// handleHitPacket plays the attack animation of the attacker, and the death animation of a
+// killed monster. Hit players take the life left from the server.
+func (g *GameClient) handleHitPacket(packet d2netpacket.NetPacket) error {
+	hitPacket, err := d2netpacket.UnmarshalHit(packet.PacketData)
+	if err != nil {
+		return err
+	}
+
+	if target := g.Players[hitPacket.TargetID]; target != nil {
+		if player := g.Players[hitPacket.AttackerID]; player != nil {
+			player.SetDirection(player.Position.DirectionTo(target.Position.Vector))
+			player.StartCasting(d2enum.PlayerAnimationModeAttack1, nil)
+		} else if npc, ok := g.MapEngine.Entities()[hitPacket.AttackerID].(*d2mapentity.NPC); ok {
+			npc.Attack(target.Position)
+		}
+
+		// the death of the player is told by a KillPlayer packet
+		if target.Stats != nil {
+			target.Stats.Health = hitPacket.Health
+		}
+
+		return nil
+	}
+
+	if hireling, ok := g.MapEngine.Entities()[hitPacket.TargetID].(*d2mapentity.Hireling); ok {
+		if npc, ok := g.MapEngine.Entities()[hitPacket.AttackerID].(*d2mapentity.NPC); ok {
+			npc.Attack(hireling.Position)
+		}
+
+		hireling.State.Health = hitPacket.Health
+
+		if hitPacket.Killed && !hireling.State.IsDead {
+			hireling.Die()
+		}
+
+		return nil
+	}
+
+	npc, ok := g.MapEngine.Entities()[hitPacket.TargetID].(*d2mapentity.NPC)
+	if !ok {
+		return fmt.Errorf("unknown monster: %s", hitPacket.TargetID)
+	}
+
+	if player := g.Players[hitPacket.AttackerID]; player != nil {
+		player.SetDirection(player.Position.DirectionTo(npc.Position.Vector))
+		player.StartCasting(d2enum.PlayerAnimationModeAttack1, nil)
+	} else if hireling, ok := g.MapEngine.Entities()[hitPacket.AttackerID].(*d2mapentity.Hireling); ok {
+		hireling.Attack(npc.Position, hitPacket.Mode)
+	}
+
+	if hitPacket.Killed && !npc.IsDead() {
+		npc.Die()
+	}
+
+	return nil
+}
+
+func (g *GameClient) handleChatMessagePacket(packet d2netpacket.NetPacket) error {
+	message, err := d2netpacket.UnmarshalChatMessage(packet.PacketData)
+	if err != nil {
+		return err
+	}
+
+	g.chatMutex.Lock()
+	g.chatMessages = append(g.chatMessages, message)
+	g.chatMutex.Unlock()
+
+	return nil
+}
+
+// ChatMessages returns the chat messages received since the last call, packets may be received
+// on the connection's goroutine so the game screen collects them once per frame
+func (g *GameClient) ChatMessages() []d2netpacket.ChatMessagePacket {
+	g.chatMutex.Lock()
+	defer g.chatMutex.Unlock()
+
+	messages := g.chatMessages
+	g.chatMessages = nil
+
+	return messages
+}
+
+// handleUpdateObjectPacket changes the mode of an object as told by the server, and applies the
+// life and mana restored by operating it to the player who operated it
+func (g *GameClient) handleUpdateObjectPacket(packet d2netpacket.NetPacket) error {
+	updatePacket, err := d2netpacket.UnmarshalUpdateObject(packet.PacketData)
+	if err != nil {
+		return err
+	}
+
+	object, ok := g.MapEngine.Entities()[updatePacket.ObjectID].(*d2mapentity.Object)
+	if !ok {
+		return fmt.Errorf("unknown object: %s", updatePacket.ObjectID)
+	}
+
+	if updatePacket.Shrine != "" {
+		object.SetShrine(g.asset.Records.Object.Shrines[updatePacket.Shrine])
+	}
+
+	if object.Mode() != updatePacket.Mode {
+		if err := object.SetMode(updatePacket.Mode); err != nil {
+			return err
+		}
+
+		g.MapEngine.UpdateObjectCollision(object)
+	}
+
+	if updatePacket.Life == 0 && updatePacket.Mana == 0 {
+		return nil
+	}
+
+	player := g.Players[updatePacket.PlayerID]
+	if player == nil {
+		return fmt.Errorf("unknown player: %s", updatePacket.PlayerID)
+	}
+
+	player.AddPotionEffect(&d2hero.PotionEffect{Life: updatePacket.Life, Mana: updatePacket.Mana})
+
+	return nil
+}
+
+// handleUpdateStatesPacket keeps the states of a player in sync with the server, the server
+// decides when states expire, so the client doesn't count them down
+func (g *GameClient) handleUpdateStatesPacket(packet d2netpacket.NetPacket) error {
+	updatePacket, err := d2netpacket.UnmarshalUpdateStates(packet.PacketData)
+	if err != nil {
+		return err
+	}
+
+	player := g.Players[updatePacket.EntityID]
+	if player == nil {
+		return fmt.Errorf("unknown player: %s", updatePacket.EntityID)
+	}
+
+	state := updatePacket.State
+	if state == nil {
+		return fmt.Errorf("no state for player: %s", updatePacket.EntityID)
+	}
+
+	var overlay string
+
+	switch updatePacket.Action {
+	case d2netpacket.StateActionAdd:
+		if _, err := player.States.Add(state); err != nil {
+			return err
+		}
+
+		overlay = state.Record().CastOverlay
+	case d2netpacket.StateActionRemove:
+		if removed := player.States.Remove(state.Name, state.Source); removed != nil {
+			overlay = removed.Record().RemOverlay
+		}
+	default:
+		return fmt.Errorf("unknown state action: %d", updatePacket.Action)
+	}
+
+	g.updateStateOverlays(player)
+
+	return g.playCastOverlay(g.asset.Records.Layout.Overlays[overlay], int(player.Position.X()),
+		int(player.Position.Y()))
+}
+
+// updateStateOverlays shows the overlays of the active states of the player, and removes the others
+func (g *GameClient) updateStateOverlays(player *d2mapentity.Player) {
+	active := player.States.Overlays()
+
+	for _, name := range active {
+		overlayRecord := g.asset.Records.Layout.Overlays[name]
+		if overlayRecord == nil || player.HasStateOverlay(name) {
+			continue
+		}
+
+		animation, err := g.MapEngine.NewStateOverlay(overlayRecord)
+		if err != nil {
+			g.Errorf("GameClient: error loading overlay %s: %s", name, err)
+			continue
+		}
+
+		player.AddStateOverlay(name, animation)
+	}
+
+	player.RemoveStateOverlays(active)
+}
+
 func (g *GameClient) handleMovePlayerPacket(packet d2netpacket.NetPacket) error {
 	movePlayer, err := d2netpacket.UnmarshalMovePlayer(packet.PacketData)
 	if err != nil {
@@ -278,6 +770,170 @@ func (g *GameClient) handleMovePlayerPacket(packet d2netpacket.NetPacket) error
 	return nil
 }
 
+// handleUpdatePortalPacket adds or removes both portals of a town portal
+func (g *GameClient) handleUpdatePortalPacket(packet d2netpacket.NetPacket) error {
+	updatePacket, err := d2netpacket.UnmarshalUpdatePortal(packet.PacketData)
+	if err != nil {
+		return err
+	}
+
+	if previous, ok := g.portals[updatePacket.OwnerID]; ok {
+		g.removePortalEntities(previous)
+		delete(g.portals, updatePacket.OwnerID)
+	}
+
+	switch updatePacket.Action {
+	case d2netpacket.PortalActionOpen:
+		g.portals[updatePacket.OwnerID] = updatePacket
+		return g.addPortalEntities(updatePacket)
+	case d2netpacket.PortalActionClose:
+		return nil
+	default:
+		return fmt.Errorf("unknown portal action: %d", updatePacket.Action)
+	}
+}
+
+func (g *GameClient) addPortalEntities(portal d2netpacket.UpdatePortalPacket) error {
+	for _, end := range []d2netpacket.PortalEnd{portal.Field, portal.Town} {
+		entity, err := g.MapEngine.NewPortal(end.ID, portal.OwnerID, portal.OwnerName,
+			int(end.X*numSubtilesPerTile), int(end.Y*numSubtilesPerTile))
+		if err != nil {
+			return err
+		}
+
+		g.MapEngine.AddEntity(entity)
+	}
+
+	return nil
+}
+
+func (g *GameClient) removePortalEntities(portal d2netpacket.UpdatePortalPacket) {
+	for _, id := range []string{portal.Field.ID, portal.Town.ID} {
+		g.MapEngine.RemoveEntity(g.MapEngine.Entities()[id])
+	}
+}
+
+// handleWarpPlayerPacket moves a player to the other end of the town portal they used
+func (g *GameClient) handleWarpPlayerPacket(packet d2netpacket.NetPacket) error {
+	warpPacket, err := d2netpacket.UnmarshalWarpPlayer(packet.PacketData)
+	if err != nil {
+		return err
+	}
+
+	player := g.Players[warpPacket.PlayerID]
+	if player == nil {
+		return fmt.Errorf("unknown player: %s", warpPacket.PlayerID)
+	}
+
+	player.Warp(d2vector.NewPositionTile(warpPacket.X, warpPacket.Y))
+
+	if tile := g.MapEngine.TileAt(int(warpPacket.X), int(warpPacket.Y)); tile != nil {
+		player.SetIsInTown(tile.RegionType == d2enum.RegionAct1Town)
+
+		// start loading what the level at the other end shows, while the warp plays
+		if warpPacket.PlayerID == g.PlayerID {
+			g.MapEngine.PrefetchLevel(int(tile.RegionType), g.Difficulty)
+		}
+	}
+
+	return player.SetAnimationMode(player.GetAnimationMode())
+}
+
+// handleKillPlayerPacket plays the death animation of a player, who then respawns in town
+// unless the player was hardcore. The equipped items of the player are on the corpse now.
+func (g *GameClient) handleKillPlayerPacket(packet d2netpacket.NetPacket) error {
+	killPacket, err := d2netpacket.UnmarshalKillPlayer(packet.PacketData)
+	if err != nil {
+		return err
+	}
+
+	player := g.Players[killPacket.PlayerID]
+	if player == nil {
+		return fmt.Errorf("unknown player: %s", killPacket.PlayerID)
+	}
+
+	player.Stats.Experience -= killPacket.ExperienceLoss
+	player.Gold -= killPacket.GoldLoss
+	player.States.OnDeath(true)
+	g.updateStateOverlays(player)
+
+	player.Die(func() {
+		player.SetEquipment(&d2inventory.CharacterEquipment{})
+
+		if killPacket.IsDead {
+			return
+		}
+
+		player.Respawn()
+		player.Warp(d2vector.NewPositionTile(killPacket.RespawnX, killPacket.RespawnY))
+		player.SetIsInTown(true)
+		player.Stats.Health = player.Stats.MaxHealth
+		player.Stats.Mana = player.Stats.MaxMana
+	})
+
+	return nil
+}
+
+// handleUpdateCorpsePacket adds a corpse, or gives the items taken from it back to its owner
+func (g *GameClient) handleUpdateCorpsePacket(packet d2netpacket.NetPacket) error {
+	updatePacket, err := d2netpacket.UnmarshalUpdateCorpse(packet.PacketData)
+	if err != nil {
+		return err
+	}
+
+	if updatePacket.Corpse == nil {
+		return fmt.Errorf("corpse update of player %s without a corpse", updatePacket.OwnerID)
+	}
+
+	corpseID := updatePacket.Corpse.ID
+
+	// dying shrinks the potion belt, retrieving the corpse restores it
+	if owner := g.Players[updatePacket.OwnerID]; owner != nil && updatePacket.Belt != nil {
+		owner.Belt = updatePacket.Belt
+	}
+
+	switch updatePacket.Action {
+	case d2netpacket.CorpseActionAdd:
+		g.corpses[corpseID] = updatePacket
+		return g.addCorpseEntity(updatePacket)
+	case d2netpacket.CorpseActionRetrieve:
+		if owner := g.Players[updatePacket.OwnerID]; owner != nil && updatePacket.Equipment != nil {
+			owner.SetEquipment(updatePacket.Equipment)
+		}
+
+		if updatePacket.Corpse.IsEmpty() {
+			delete(g.corpses, corpseID)
+			g.MapEngine.RemoveEntity(g.MapEngine.Entities()[corpseID])
+
+			return nil
+		}
+
+		g.corpses[corpseID] = updatePacket
+
+		if entity, ok := g.MapEngine.Entities()[corpseID].(*d2mapentity.Corpse); ok {
+			entity.SetEquipment(&updatePacket.Corpse.Equipment)
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("unknown corpse action: %d", updatePacket.Action)
+	}
+}
+
+func (g *GameClient) addCorpseEntity(update d2netpacket.UpdateCorpsePacket) error {
+	corpse := update.Corpse
+
+	entity, err := g.MapEngine.NewCorpse(corpse.ID, update.OwnerID, update.OwnerName, update.HeroType,
+		int(corpse.X*numSubtilesPerTile), int(corpse.Y*numSubtilesPerTile), &corpse.Equipment)
+	if err != nil {
+		return err
+	}
+
+	g.MapEngine.AddEntity(entity)
+
+	return nil
+}
+
 func (g *GameClient) handleCastSkillPacket(packet d2netpacket.NetPacket) error {
 	playerCast, err := d2netpacket.UnmarshalCast(packet.PacketData)
 	if err != nil {
@@ -457,6 +1113,11 @@ func (g *GameClient) handlePlayerDisconnectionPacket(packet d2netpacket.NetPacke
 	g.MapEngine.RemoveEntity(player)
 	delete(g.Players, disconnectPacket.ID)
 
+	if hireling, found := g.Hirelings[disconnectPacket.ID]; found {
+		g.MapEngine.RemoveEntity(hireling)
+		delete(g.Hirelings, disconnectPacket.ID)
+	}
+
 	return nil
 }
 
@@ -12,4 +12,5 @@ type ServerConnection interface {
 	Close() error
 	SendPacketToServer(packet d2netpacket.NetPacket) error
 	SetClientListener(listener d2networking.ClientListener)
+	ReceivePackets() error
 }
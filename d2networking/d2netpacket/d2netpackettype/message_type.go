@@ -31,6 +31,30 @@ const (
 	SpawnItem                                            // Sent by server
 	SavePlayer                                           // Sent by the client, saves the player
 	ServerFull                                           // Sent by server when server has reached max connections
+	UseBeltItem                                          // Sent by client or server, uses the bottom item of a belt column
+	UpdateHireling                                       // Sent by client or server, hires or revives a hireling
+	QuestEvent                                           // Sent by client, an event which may advance the player's quests
+	UpdateQuests                                         // Sent by server, the quest state of the player
+	UpdateStates                                         // Sent by server, adds or removes a state of an entity
+	OperateObject                                        // Sent by client, the player operates an object
+	UpdateObject                                         // Sent by server, the mode of an object and the effect of operating it
+	UpdatePortal                                         // Sent by server, opens or closes a town portal
+	UsePortal                                            // Sent by client, the player goes through a town portal
+	WarpPlayer                                           // Sent by server, moves a player at once
+	PlayerDeath                                          // Sent by client, the life of the player reached zero
+	KillPlayer                                           // Sent by server, a player died, with the penalties and where they respawn
+	RetrieveCorpse                                       // Sent by client, the player takes the items back from their corpse
+	UpdateCorpse                                         // Sent by server, adds or removes the corpse of a player
+	UpdateAutomap                                        // Sent by server, the explored tiles of the player's automap
+	ChatMessage                                          // Sent by client or server, a chat message of a player
+	PartyAction                                          // Sent by client, invites, joins, leaves or changes hostility
+	UpdateParty                                          // Sent by server, the parties and hostility of the players
+	GainExperience                                       // Sent by server, a player gains experience
+	Attack                                               // Sent by client, the player attacks a monster
+	Hit                                                  // Sent by server, an attack hit or missed, with the life left
+	PickupItem                                           // Sent by client or server, the player picks up an item from the ground
+	RepairItems                                          // Sent by client, the player repairs their equipment at a vendor
+	UpdateEquipment                                      // Sent by server, the durability of a player's equipment changed
 
 	UnknownPacketType = 666
 )
@@ -50,6 +74,30 @@ func (n NetPacketType) String() string {
 		SpawnItem:                       "SpawnItem",
 		SavePlayer:                      "SavePlayer",
 		ServerFull:                      "ServerFull",
+		UseBeltItem:                     "UseBeltItem",
+		UpdateHireling:                  "UpdateHireling",
+		QuestEvent:                      "QuestEvent",
+		UpdateQuests:                    "UpdateQuests",
+		UpdateStates:                    "UpdateStates",
+		OperateObject:                   "OperateObject",
+		UpdateObject:                    "UpdateObject",
+		UpdatePortal:                    "UpdatePortal",
+		UsePortal:                       "UsePortal",
+		WarpPlayer:                      "WarpPlayer",
+		PlayerDeath:                     "PlayerDeath",
+		KillPlayer:                      "KillPlayer",
+		RetrieveCorpse:                  "RetrieveCorpse",
+		UpdateCorpse:                    "UpdateCorpse",
+		UpdateAutomap:                   "UpdateAutomap",
+		ChatMessage:                     "ChatMessage",
+		PartyAction:                     "PartyAction",
+		UpdateParty:                     "UpdateParty",
+		GainExperience:                  "GainExperience",
+		Attack:                          "Attack",
+		Hit:                             "Hit",
+		PickupItem:                      "PickupItem",
+		RepairItems:                     "RepairItems",
+		UpdateEquipment:                 "UpdateEquipment",
 	}
 
 	return strings[n]
@@ -19,6 +19,7 @@ type AddPlayerPacket struct {
 	Y          int                            `json:"y"`
 	HeroType   d2enum.Hero                    `json:"hero"`
 	Equipment  d2inventory.CharacterEquipment `json:"equipment"`
+	Belt       *d2inventory.Belt              `json:"belt"`
 	Stats      *d2hero.HeroStatsState         `json:"heroStats"`
 	Skills     map[int]*d2hero.HeroSkill      `json:"heroSkills"`
 	LeftSkill  int                            `json:"leftSkill"`
@@ -35,6 +36,7 @@ func CreateAddPlayerPacket(
 	stats *d2hero.HeroStatsState,
 	skills map[int]*d2hero.HeroSkill,
 	equipment d2inventory.CharacterEquipment,
+	belt *d2inventory.Belt,
 	leftSkill, rightSkill, gold int) (NetPacket, error) {
 	addPlayerPacket := AddPlayerPacket{
 		ID:         id,
@@ -43,6 +45,7 @@ func CreateAddPlayerPacket(
 		Y:          y,
 		HeroType:   heroType,
 		Equipment:  equipment,
+		Belt:       belt,
 		Stats:      stats,
 		Skills:     skills,
 		LeftSkill:  leftSkill,
@@ -0,0 +1,43 @@
+package d2netpacket
+
+import (
+	"encoding/json"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+)
+
+// AttackPacket is sent by the client when the player attacks a monster. The server rolls the
+// attack and answers with a HitPacket.
+type AttackPacket struct {
+	PlayerID string `json:"playerId"`
+	TargetID string `json:"targetId"`
+}
+
+// CreateAttackPacket returns a NetPacket which declares an AttackPacket for the given player
+// and target.
+func CreateAttackPacket(playerID, targetID string) (NetPacket, error) {
+	attack := AttackPacket{
+		PlayerID: playerID,
+		TargetID: targetID,
+	}
+
+	b, err := json.Marshal(attack)
+	if err != nil {
+		return NetPacket{PacketType: d2netpackettype.Attack}, err
+	}
+
+	return NetPacket{
+		PacketType: d2netpackettype.Attack,
+		PacketData: b,
+	}, nil
+}
+
+// UnmarshalAttack unmarshals the given data to an AttackPacket struct
+func UnmarshalAttack(packet []byte) (AttackPacket, error) {
+	var p AttackPacket
+	if err := json.Unmarshal(packet, &p); err != nil {
+		return p, err
+	}
+
+	return p, nil
+}
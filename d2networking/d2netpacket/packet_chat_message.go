@@ -0,0 +1,72 @@
+package d2netpacket
+
+import (
+	"encoding/json"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+)
+
+// ChatChannel is who receives a chat message
+type ChatChannel int
+
+// Chat channels
+const (
+	ChatChannelAll     ChatChannel = iota // every player of the game
+	ChatChannelWhisper                    // a single player, by hero name
+	ChatChannelParty                      // the players in the party of the sender
+	ChatChannelServer                     // a notice of the server to a single player
+)
+
+// ChatNotice is the notice of the server on the server channel, receivers translate it
+type ChatNotice int
+
+// Chat notices
+const (
+	ChatNoticeNone        ChatNotice = iota
+	ChatNoticeRateLimit              // the sender sends messages too fast
+	ChatNoticeNoRecipient            // the recipient of a whisper is not in the game
+	ChatNoticeNoParty                // the sender of a party message is not in a party
+)
+
+// ChatMessagePacket is sent by the client when the player says something, and relayed by the
+// server to the players of the channel. The server sets the name of the sender. Quick-say
+// messages carry the phrase, which receivers translate and voice themselves. Notices of the
+// server carry the notice instead of its text, for the same reason.
+type ChatMessagePacket struct {
+	Channel    ChatChannel      `json:"channel"`
+	PlayerID   string           `json:"playerId"`
+	PlayerName string           `json:"playerName"`
+	Recipient  string           `json:"recipient"`
+	Text       string           `json:"text"`
+	Phrase     d2enum.GameEvent `json:"phrase"`
+	Notice     ChatNotice       `json:"notice"`
+}
+
+// CreateChatMessagePacket returns a NetPacket which declares a ChatMessagePacket
+func CreateChatMessagePacket(message ChatMessagePacket) (NetPacket, error) {
+	b, err := json.Marshal(message)
+	if err != nil {
+		return NetPacket{PacketType: d2netpackettype.ChatMessage}, err
+	}
+
+	return NetPacket{
+		PacketType: d2netpackettype.ChatMessage,
+		PacketData: b,
+	}, nil
+}
+
+// UnmarshalChatMessage unmarshals the given data to a ChatMessagePacket struct
+func UnmarshalChatMessage(packet []byte) (ChatMessagePacket, error) {
+	var p ChatMessagePacket
+	if err := json.Unmarshal(packet, &p); err != nil {
+		return p, err
+	}
+
+	return p, nil
+}
+
+// IsQuickSay returns true if the message is one of the quick-say phrases
+func (p *ChatMessagePacket) IsQuickSay() bool {
+	return p.Phrase >= d2enum.SayHelp && p.Phrase <= d2enum.SayRetreat
+}
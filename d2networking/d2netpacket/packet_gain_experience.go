@@ -0,0 +1,43 @@
+package d2netpacket
+
+import (
+	"encoding/json"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+)
+
+// GainExperiencePacket is sent by the server to all clients when a player gains experience,
+// the experience of a kill is shared with the party members in range.
+type GainExperiencePacket struct {
+	PlayerID   string `json:"playerId"`
+	Experience int    `json:"experience"`
+}
+
+// CreateGainExperiencePacket returns a NetPacket which declares a GainExperiencePacket for
+// the given player.
+func CreateGainExperiencePacket(playerID string, experience int) (NetPacket, error) {
+	gainExperience := GainExperiencePacket{
+		PlayerID:   playerID,
+		Experience: experience,
+	}
+
+	b, err := json.Marshal(gainExperience)
+	if err != nil {
+		return NetPacket{PacketType: d2netpackettype.GainExperience}, err
+	}
+
+	return NetPacket{
+		PacketType: d2netpackettype.GainExperience,
+		PacketData: b,
+	}, nil
+}
+
+// UnmarshalGainExperience unmarshals the given data to a GainExperiencePacket struct
+func UnmarshalGainExperience(packet []byte) (GainExperiencePacket, error) {
+	var p GainExperiencePacket
+	if err := json.Unmarshal(packet, &p); err != nil {
+		return p, err
+	}
+
+	return p, nil
+}
@@ -0,0 +1,48 @@
+package d2netpacket
+
+import (
+	"encoding/json"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+)
+
+// HitPacket is sent by the server to all clients when an attack is rolled. It has the damage
+// dealt and the life the target has left, a missed attack deals no damage. Killed targets play
+// their death animation, a hit without an attacker tells a client which joined later about a
+// target killed before. Hirelings attack with the animation mode of the skill the server chose.
+type HitPacket struct {
+	AttackerID string                      `json:"attackerId"`
+	TargetID   string                      `json:"targetId"`
+	Missed     bool                        `json:"missed"`
+	Damage     int                         `json:"damage"`
+	Health     int                         `json:"health"`
+	MaxHealth  int                         `json:"maxHealth"`
+	Killed     bool                        `json:"killed"`
+	Mode       d2enum.MonsterAnimationMode `json:"mode"`
+}
+
+// CreateHitPacket returns a NetPacket which declares a HitPacket with the given result of an
+// attack.
+func CreateHitPacket(hit HitPacket) (NetPacket, error) {
+	b, err := json.Marshal(hit)
+	if err != nil {
+		return NetPacket{PacketType: d2netpackettype.Hit}, err
+	}
+
+	return NetPacket{
+		PacketType: d2netpackettype.Hit,
+		PacketData: b,
+	}, nil
+}
+
+// UnmarshalHit unmarshals the given data to a HitPacket struct
+func UnmarshalHit(packet []byte) (HitPacket, error) {
+	var p HitPacket
+	if err := json.Unmarshal(packet, &p); err != nil {
+		return p, err
+	}
+
+	return p, nil
+}
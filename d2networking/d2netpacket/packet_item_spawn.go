@@ -6,8 +6,10 @@ import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
 )
 
-// SpawnItemPacket contains the data required to create a Item entity
+// SpawnItemPacket contains the data required to create a Item entity. The server gives every
+// item on the ground an ID, the ID is empty when the client asks for an item to be spawned.
 type SpawnItemPacket struct {
+	ID    string   `json:"id"`
 	X     int      `json:"x"`
 	Y     int      `json:"y"`
 	Codes []string `json:"codes"`
@@ -15,8 +17,9 @@ type SpawnItemPacket struct {
 
 // CreateSpawnItemPacket returns a NetPacket which declares a
 // SpawnItemPacket with the data in given parameters.
-func CreateSpawnItemPacket(x, y int, codes ...string) (NetPacket, error) {
+func CreateSpawnItemPacket(id string, x, y int, codes ...string) (NetPacket, error) {
 	spawnItemPacket := SpawnItemPacket{
+		ID:    id,
 		X:     x,
 		Y:     y,
 		Codes: codes,
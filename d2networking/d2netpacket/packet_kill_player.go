@@ -0,0 +1,43 @@
+package d2netpacket
+
+import (
+	"encoding/json"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+)
+
+// KillPlayerPacket is sent by the server when a player dies. It has the experience and gold the
+// player lost, and the position in town where the player respawns, in tiles. Hardcore players
+// don't respawn.
+type KillPlayerPacket struct {
+	PlayerID       string  `json:"playerId"`
+	ExperienceLoss int     `json:"experienceLoss"`
+	GoldLoss       int     `json:"goldLoss"`
+	IsDead         bool    `json:"isDead"`
+	RespawnX       float64 `json:"respawnX"`
+	RespawnY       float64 `json:"respawnY"`
+}
+
+// CreateKillPlayerPacket returns a NetPacket which declares a KillPlayerPacket with the given
+// death of a player.
+func CreateKillPlayerPacket(kill KillPlayerPacket) (NetPacket, error) {
+	b, err := json.Marshal(kill)
+	if err != nil {
+		return NetPacket{PacketType: d2netpackettype.KillPlayer}, err
+	}
+
+	return NetPacket{
+		PacketType: d2netpackettype.KillPlayer,
+		PacketData: b,
+	}, nil
+}
+
+// UnmarshalKillPlayer unmarshals the given data to a KillPlayerPacket struct
+func UnmarshalKillPlayer(packet []byte) (KillPlayerPacket, error) {
+	var p KillPlayerPacket
+	if err := json.Unmarshal(packet, &p); err != nil {
+		return p, err
+	}
+
+	return p, nil
+}
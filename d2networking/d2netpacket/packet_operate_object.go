@@ -0,0 +1,44 @@
+package d2netpacket
+
+import (
+	"encoding/json"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+)
+
+// OperateObjectPacket is sent by the client when the player operates an object, like opening
+// a chest or clicking on a shrine. The server decides what happens and answers with an
+// UpdateObjectPacket.
+type OperateObjectPacket struct {
+	PlayerID string `json:"playerId"`
+	ObjectID string `json:"objectId"`
+}
+
+// CreateOperateObjectPacket returns a NetPacket which declares an OperateObjectPacket for
+// the given player and object.
+func CreateOperateObjectPacket(playerID, objectID string) (NetPacket, error) {
+	operateObject := OperateObjectPacket{
+		PlayerID: playerID,
+		ObjectID: objectID,
+	}
+
+	b, err := json.Marshal(operateObject)
+	if err != nil {
+		return NetPacket{PacketType: d2netpackettype.OperateObject}, err
+	}
+
+	return NetPacket{
+		PacketType: d2netpackettype.OperateObject,
+		PacketData: b,
+	}, nil
+}
+
+// UnmarshalOperateObject unmarshals the given data to an OperateObjectPacket struct
+func UnmarshalOperateObject(packet []byte) (OperateObjectPacket, error) {
+	var p OperateObjectPacket
+	if err := json.Unmarshal(packet, &p); err != nil {
+		return p, err
+	}
+
+	return p, nil
+}
@@ -0,0 +1,58 @@
+package d2netpacket
+
+import (
+	"encoding/json"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+)
+
+// PartyAction is the change a player asks for with a PartyActionPacket
+type PartyAction int
+
+// Party actions
+const (
+	PartyActionInvite         PartyAction = iota // invites the target to the party of the player
+	PartyActionAccept                            // joins the party of the target, who invited the player
+	PartyActionLeave                             // leaves the party of the player, the target is ignored
+	PartyActionDeclareHostile                    // makes the player and the target hostile to each other
+	PartyActionEndHostility                      // ends the hostility between the player and the target
+)
+
+// PartyActionPacket is sent by the client when the player invites another player, accepts an
+// invitation, leaves their party or changes their hostility. The server validates the action
+// and sends an UpdatePartyPacket with the resulting parties to all clients.
+type PartyActionPacket struct {
+	PlayerID string      `json:"playerId"`
+	TargetID string      `json:"targetId"`
+	Action   PartyAction `json:"action"`
+}
+
+// CreatePartyActionPacket returns a NetPacket which declares a PartyActionPacket with the given
+// action of the player towards the target.
+func CreatePartyActionPacket(playerID, targetID string, action PartyAction) (NetPacket, error) {
+	partyAction := PartyActionPacket{
+		PlayerID: playerID,
+		TargetID: targetID,
+		Action:   action,
+	}
+
+	b, err := json.Marshal(partyAction)
+	if err != nil {
+		return NetPacket{PacketType: d2netpackettype.PartyAction}, err
+	}
+
+	return NetPacket{
+		PacketType: d2netpackettype.PartyAction,
+		PacketData: b,
+	}, nil
+}
+
+// UnmarshalPartyAction unmarshals the given data to a PartyActionPacket struct
+func UnmarshalPartyAction(packet []byte) (PartyActionPacket, error) {
+	var p PartyActionPacket
+	if err := json.Unmarshal(packet, &p); err != nil {
+		return p, err
+	}
+
+	return p, nil
+}
@@ -0,0 +1,48 @@
+package d2netpacket
+
+import (
+	"encoding/json"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+)
+
+// PickupItemPacket is sent by the client when the player clicks on an item on the ground. The
+// server answers all clients with the same packet, with the code of the item, once the item
+// was put into the player's belt, and whether the item is ethereal.
+type PickupItemPacket struct {
+	PlayerID string `json:"playerId"`
+	ItemID   string `json:"itemId"`
+	ItemCode string `json:"itemCode"`
+	Ethereal bool   `json:"ethereal"`
+}
+
+// CreatePickupItemPacket returns a NetPacket which declares a PickupItemPacket for the given
+// player and item.
+func CreatePickupItemPacket(playerID, itemID, itemCode string, ethereal bool) (NetPacket, error) {
+	pickupItem := PickupItemPacket{
+		PlayerID: playerID,
+		ItemID:   itemID,
+		ItemCode: itemCode,
+		Ethereal: ethereal,
+	}
+
+	b, err := json.Marshal(pickupItem)
+	if err != nil {
+		return NetPacket{PacketType: d2netpackettype.PickupItem}, err
+	}
+
+	return NetPacket{
+		PacketType: d2netpackettype.PickupItem,
+		PacketData: b,
+	}, nil
+}
+
+// UnmarshalPickupItem unmarshals the given data to a PickupItemPacket struct
+func UnmarshalPickupItem(packet []byte) (PickupItemPacket, error) {
+	var p PickupItemPacket
+	if err := json.Unmarshal(packet, &p); err != nil {
+		return p, err
+	}
+
+	return p, nil
+}
@@ -0,0 +1,41 @@
+package d2netpacket
+
+import (
+	"encoding/json"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+)
+
+// PlayerDeathPacket is sent by the client when the life of the player reaches zero. The server
+// applies the penalties of death and answers with a KillPlayerPacket.
+type PlayerDeathPacket struct {
+	PlayerID string `json:"playerId"`
+}
+
+// CreatePlayerDeathPacket returns a NetPacket which declares a PlayerDeathPacket for the given
+// player.
+func CreatePlayerDeathPacket(playerID string) (NetPacket, error) {
+	playerDeath := PlayerDeathPacket{
+		PlayerID: playerID,
+	}
+
+	b, err := json.Marshal(playerDeath)
+	if err != nil {
+		return NetPacket{PacketType: d2netpackettype.PlayerDeath}, err
+	}
+
+	return NetPacket{
+		PacketType: d2netpackettype.PlayerDeath,
+		PacketData: b,
+	}, nil
+}
+
+// UnmarshalPlayerDeath unmarshals the given data to a PlayerDeathPacket struct
+func UnmarshalPlayerDeath(packet []byte) (PlayerDeathPacket, error) {
+	var p PlayerDeathPacket
+	if err := json.Unmarshal(packet, &p); err != nil {
+		return p, err
+	}
+
+	return p, nil
+}
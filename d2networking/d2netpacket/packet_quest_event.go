@@ -0,0 +1,45 @@
+package d2netpacket
+
+import (
+	"encoding/json"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2quest"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+)
+
+// QuestEventPacket is sent by the client when the player causes an event which may advance
+// the quests, like talking to an npc. The server advances the quests and answers with an
+// UpdateQuestsPacket.
+type QuestEventPacket struct {
+	PlayerID string          `json:"playerId"`
+	Trigger  d2quest.Trigger `json:"trigger"`
+}
+
+// CreateQuestEventPacket returns a NetPacket which declares a QuestEventPacket with the
+// given trigger caused by the given player.
+func CreateQuestEventPacket(playerID string, trigger d2quest.Trigger) (NetPacket, error) {
+	questEvent := QuestEventPacket{
+		PlayerID: playerID,
+		Trigger:  trigger,
+	}
+
+	b, err := json.Marshal(questEvent)
+	if err != nil {
+		return NetPacket{PacketType: d2netpackettype.QuestEvent}, err
+	}
+
+	return NetPacket{
+		PacketType: d2netpackettype.QuestEvent,
+		PacketData: b,
+	}, nil
+}
+
+// UnmarshalQuestEvent unmarshals the given data to a QuestEventPacket struct
+func UnmarshalQuestEvent(packet []byte) (QuestEventPacket, error) {
+	var p QuestEventPacket
+	if err := json.Unmarshal(packet, &p); err != nil {
+		return p, err
+	}
+
+	return p, nil
+}
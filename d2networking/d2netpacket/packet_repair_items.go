@@ -0,0 +1,43 @@
+package d2netpacket
+
+import (
+	"encoding/json"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+)
+
+// RepairItemsPacket is sent by the client when the player asks a vendor to repair their
+// equipment. The server answers with an UpdateEquipmentPacket.
+type RepairItemsPacket struct {
+	PlayerID string `json:"playerId"`
+	NPCKey   string `json:"npcKey"`
+}
+
+// CreateRepairItemsPacket returns a NetPacket which declares a RepairItemsPacket for the given
+// player and the monstats.txt id of the vendor.
+func CreateRepairItemsPacket(playerID, npcKey string) (NetPacket, error) {
+	repairItems := RepairItemsPacket{
+		PlayerID: playerID,
+		NPCKey:   npcKey,
+	}
+
+	b, err := json.Marshal(repairItems)
+	if err != nil {
+		return NetPacket{PacketType: d2netpackettype.RepairItems}, err
+	}
+
+	return NetPacket{
+		PacketType: d2netpackettype.RepairItems,
+		PacketData: b,
+	}, nil
+}
+
+// UnmarshalRepairItems unmarshals the given data to a RepairItemsPacket struct
+func UnmarshalRepairItems(packet []byte) (RepairItemsPacket, error) {
+	var p RepairItemsPacket
+	if err := json.Unmarshal(packet, &p); err != nil {
+		return p, err
+	}
+
+	return p, nil
+}
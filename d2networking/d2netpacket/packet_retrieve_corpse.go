@@ -0,0 +1,43 @@
+package d2netpacket
+
+import (
+	"encoding/json"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+)
+
+// RetrieveCorpsePacket is sent by the client when the player clicks on their corpse. The server
+// moves the items of the corpse back to the player and answers with an UpdateCorpsePacket.
+type RetrieveCorpsePacket struct {
+	PlayerID string `json:"playerId"`
+	CorpseID string `json:"corpseId"`
+}
+
+// CreateRetrieveCorpsePacket returns a NetPacket which declares a RetrieveCorpsePacket for the
+// given player and corpse.
+func CreateRetrieveCorpsePacket(playerID, corpseID string) (NetPacket, error) {
+	retrieveCorpse := RetrieveCorpsePacket{
+		PlayerID: playerID,
+		CorpseID: corpseID,
+	}
+
+	b, err := json.Marshal(retrieveCorpse)
+	if err != nil {
+		return NetPacket{PacketType: d2netpackettype.RetrieveCorpse}, err
+	}
+
+	return NetPacket{
+		PacketType: d2netpackettype.RetrieveCorpse,
+		PacketData: b,
+	}, nil
+}
+
+// UnmarshalRetrieveCorpse unmarshals the given data to a RetrieveCorpsePacket struct
+func UnmarshalRetrieveCorpse(packet []byte) (RetrieveCorpsePacket, error) {
+	var p RetrieveCorpsePacket
+	if err := json.Unmarshal(packet, &p); err != nil {
+		return p, err
+	}
+
+	return p, nil
+}
@@ -0,0 +1,45 @@
+package d2netpacket
+
+import (
+	"encoding/json"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2automap"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+)
+
+// UpdateAutomapPacket is sent by the server to a player when joining the game, with the tiles
+// of the player's automap explored in previous games. The client sends the explored tiles back
+// with the player when saving.
+type UpdateAutomapPacket struct {
+	PlayerID string           `json:"playerId"`
+	Automap  *d2automap.State `json:"automap"`
+}
+
+// CreateUpdateAutomapPacket returns a NetPacket which declares an UpdateAutomapPacket with the
+// automap of the given player.
+func CreateUpdateAutomapPacket(playerID string, automap *d2automap.State) (NetPacket, error) {
+	updateAutomap := UpdateAutomapPacket{
+		PlayerID: playerID,
+		Automap:  automap,
+	}
+
+	b, err := json.Marshal(updateAutomap)
+	if err != nil {
+		return NetPacket{PacketType: d2netpackettype.UpdateAutomap}, err
+	}
+
+	return NetPacket{
+		PacketType: d2netpackettype.UpdateAutomap,
+		PacketData: b,
+	}, nil
+}
+
+// UnmarshalUpdateAutomap unmarshals the given data to an UpdateAutomapPacket struct
+func UnmarshalUpdateAutomap(packet []byte) (UpdateAutomapPacket, error) {
+	var p UpdateAutomapPacket
+	if err := json.Unmarshal(packet, &p); err != nil {
+		return p, err
+	}
+
+	return p, nil
+}
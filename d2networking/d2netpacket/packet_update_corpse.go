@@ -0,0 +1,59 @@
+package d2netpacket
+
+import (
+	"encoding/json"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2hero"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2inventory"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+)
+
+// CorpseAction is the change made to a corpse by an UpdateCorpsePacket
+type CorpseAction int
+
+// Corpse actions
+const (
+	// CorpseActionAdd adds the corpse, when the player dies or joins the game
+	CorpseActionAdd CorpseAction = iota
+	// CorpseActionRetrieve moves items of the corpse back to the player, the corpse is removed
+	// once it is empty
+	CorpseActionRetrieve
+)
+
+// UpdateCorpsePacket is sent by the server when a corpse is added, or when the owner takes items
+// back from it. Equipment and Belt are the equipment and the potion belt of the owner after the
+// change.
+type UpdateCorpsePacket struct {
+	Action    CorpseAction                    `json:"action"`
+	OwnerID   string                          `json:"ownerId"`
+	OwnerName string                          `json:"ownerName"`
+	HeroType  d2enum.Hero                     `json:"heroType"`
+	Corpse    *d2hero.Corpse                  `json:"corpse"`
+	Equipment *d2inventory.CharacterEquipment `json:"equipment"`
+	Belt      *d2inventory.Belt               `json:"belt"`
+}
+
+// CreateUpdateCorpsePacket returns a NetPacket which declares an UpdateCorpsePacket with the
+// given corpse update.
+func CreateUpdateCorpsePacket(update UpdateCorpsePacket) (NetPacket, error) {
+	b, err := json.Marshal(update)
+	if err != nil {
+		return NetPacket{PacketType: d2netpackettype.UpdateCorpse}, err
+	}
+
+	return NetPacket{
+		PacketType: d2netpackettype.UpdateCorpse,
+		PacketData: b,
+	}, nil
+}
+
+// UnmarshalUpdateCorpse unmarshals the given data to an UpdateCorpsePacket struct
+func UnmarshalUpdateCorpse(packet []byte) (UpdateCorpsePacket, error) {
+	var p UpdateCorpsePacket
+	if err := json.Unmarshal(packet, &p); err != nil {
+		return p, err
+	}
+
+	return p, nil
+}
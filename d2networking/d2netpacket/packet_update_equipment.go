@@ -0,0 +1,47 @@
+package d2netpacket
+
+import (
+	"encoding/json"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2inventory"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+)
+
+// UpdateEquipmentPacket is sent by the server to all clients when the equipment of a player
+// lost durability in combat, or was repaired, with the player's remaining gold.
+type UpdateEquipmentPacket struct {
+	PlayerID  string                         `json:"playerId"`
+	Equipment d2inventory.CharacterEquipment `json:"equipment"`
+	Gold      int                            `json:"gold"`
+}
+
+// CreateUpdateEquipmentPacket returns a NetPacket which declares an UpdateEquipmentPacket with
+// the equipment of the given player.
+func CreateUpdateEquipmentPacket(playerID string, equipment d2inventory.CharacterEquipment,
+	gold int) (NetPacket, error) {
+	updateEquipment := UpdateEquipmentPacket{
+		PlayerID:  playerID,
+		Equipment: equipment,
+		Gold:      gold,
+	}
+
+	b, err := json.Marshal(updateEquipment)
+	if err != nil {
+		return NetPacket{PacketType: d2netpackettype.UpdateEquipment}, err
+	}
+
+	return NetPacket{
+		PacketType: d2netpackettype.UpdateEquipment,
+		PacketData: b,
+	}, nil
+}
+
+// UnmarshalUpdateEquipment unmarshals the given data to an UpdateEquipmentPacket struct
+func UnmarshalUpdateEquipment(packet []byte) (UpdateEquipmentPacket, error) {
+	var p UpdateEquipmentPacket
+	if err := json.Unmarshal(packet, &p); err != nil {
+		return p, err
+	}
+
+	return p, nil
+}
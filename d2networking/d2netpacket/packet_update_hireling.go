@@ -0,0 +1,86 @@
+package d2netpacket
+
+import (
+	"encoding/json"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2hero"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+)
+
+// HirelingAction is the change made to a player's hireling by an UpdateHirelingPacket
+type HirelingAction int
+
+// Hireling actions
+const (
+	HirelingActionHire   HirelingAction = iota // hires the hireling, replacing the current one
+	HirelingActionRevive                       // revives the current hireling
+	HirelingActionSpawn                        // sent by the server, adds an already hired hireling
+	HirelingActionEquip                        // swaps the item of the slot between the player and the hireling
+	HirelingActionUpdate                       // sent by the server, the hireling gained experience
+)
+
+// UpdateHirelingPacket is sent by the client to hire, revive or equip a hireling. The server
+// validates the request, takes the gold from the player and sends the packet to all
+// clients with the resulting hireling state and the player's remaining gold.
+type UpdateHirelingPacket struct {
+	PlayerID string                `json:"playerId"`
+	Action   HirelingAction        `json:"action"`
+	Hireling *d2hero.HirelingState `json:"hireling"`
+	Gold     int                   `json:"gold"`
+	Slot     d2hero.HirelingSlot   `json:"slot"`
+}
+
+// CreateUpdateHirelingPacket returns a NetPacket which declares an UpdateHirelingPacket
+// with the given action for the hireling of the given player.
+func CreateUpdateHirelingPacket(playerID string, action HirelingAction, hireling *d2hero.HirelingState,
+	gold int) (NetPacket, error) {
+	updateHireling := UpdateHirelingPacket{
+		PlayerID: playerID,
+		Action:   action,
+		Hireling: hireling,
+		Gold:     gold,
+	}
+
+	b, err := json.Marshal(updateHireling)
+	if err != nil {
+		return NetPacket{PacketType: d2netpackettype.UpdateHireling}, err
+	}
+
+	return NetPacket{
+		PacketType: d2netpackettype.UpdateHireling,
+		PacketData: b,
+	}, nil
+}
+
+// CreateEquipHirelingPacket returns a NetPacket which declares an UpdateHirelingPacket which
+// swaps the item of the given slot between the player and the player's hireling.
+func CreateEquipHirelingPacket(playerID string, slot d2hero.HirelingSlot, hireling *d2hero.HirelingState,
+	gold int) (NetPacket, error) {
+	equipHireling := UpdateHirelingPacket{
+		PlayerID: playerID,
+		Action:   HirelingActionEquip,
+		Hireling: hireling,
+		Gold:     gold,
+		Slot:     slot,
+	}
+
+	b, err := json.Marshal(equipHireling)
+	if err != nil {
+		return NetPacket{PacketType: d2netpackettype.UpdateHireling}, err
+	}
+
+	return NetPacket{
+		PacketType: d2netpackettype.UpdateHireling,
+		PacketData: b,
+	}, nil
+}
+
+// UnmarshalUpdateHireling unmarshals the given data to an UpdateHirelingPacket struct
+func UnmarshalUpdateHireling(packet []byte) (UpdateHirelingPacket, error) {
+	var p UpdateHirelingPacket
+	if err := json.Unmarshal(packet, &p); err != nil {
+		return p, err
+	}
+
+	return p, nil
+}
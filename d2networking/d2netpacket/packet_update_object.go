@@ -0,0 +1,45 @@
+package d2netpacket
+
+import (
+	"encoding/json"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+)
+
+// UpdateObjectPacket is sent by the server when an object changes, and to the connecting
+// clients for the objects which aren't in their initial state. When a player operated the
+// object, the life and mana restored to the player are applied by all clients.
+type UpdateObjectPacket struct {
+	ObjectID string                     `json:"objectId"`
+	Mode     d2enum.ObjectAnimationMode `json:"mode"`
+	// Shrine is the shrines.txt name of a shrine object
+	Shrine   string `json:"shrine"`
+	PlayerID string `json:"playerId"`
+	Life     int    `json:"life"`
+	Mana     int    `json:"mana"`
+}
+
+// CreateUpdateObjectPacket returns a NetPacket which declares an UpdateObjectPacket with the
+// given object update.
+func CreateUpdateObjectPacket(update UpdateObjectPacket) (NetPacket, error) {
+	b, err := json.Marshal(update)
+	if err != nil {
+		return NetPacket{PacketType: d2netpackettype.UpdateObject}, err
+	}
+
+	return NetPacket{
+		PacketType: d2netpackettype.UpdateObject,
+		PacketData: b,
+	}, nil
+}
+
+// UnmarshalUpdateObject unmarshals the given data to an UpdateObjectPacket struct
+func UnmarshalUpdateObject(packet []byte) (UpdateObjectPacket, error) {
+	var p UpdateObjectPacket
+	if err := json.Unmarshal(packet, &p); err != nil {
+		return p, err
+	}
+
+	return p, nil
+}
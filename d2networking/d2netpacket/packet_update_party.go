@@ -0,0 +1,39 @@
+package d2netpacket
+
+import (
+	"encoding/json"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2party"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+)
+
+// UpdatePartyPacket is sent by the server to all clients when a party or the hostility
+// between players changes, and to a client when it connects. It holds the whole party state
+// of the game.
+type UpdatePartyPacket struct {
+	Parties *d2party.Parties `json:"parties"`
+}
+
+// CreateUpdatePartyPacket returns a NetPacket which declares an UpdatePartyPacket with the
+// given parties.
+func CreateUpdatePartyPacket(parties *d2party.Parties) (NetPacket, error) {
+	b, err := json.Marshal(UpdatePartyPacket{Parties: parties})
+	if err != nil {
+		return NetPacket{PacketType: d2netpackettype.UpdateParty}, err
+	}
+
+	return NetPacket{
+		PacketType: d2netpackettype.UpdateParty,
+		PacketData: b,
+	}, nil
+}
+
+// UnmarshalUpdateParty unmarshals the given data to an UpdatePartyPacket struct
+func UnmarshalUpdateParty(packet []byte) (UpdatePartyPacket, error) {
+	var p UpdatePartyPacket
+	if err := json.Unmarshal(packet, &p); err != nil {
+		return p, err
+	}
+
+	return p, nil
+}
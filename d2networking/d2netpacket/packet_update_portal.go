@@ -0,0 +1,62 @@
+package d2netpacket
+
+import (
+	"encoding/json"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+)
+
+// PortalAction is what happens to a town portal
+type PortalAction int
+
+// Portal actions
+const (
+	// PortalActionOpen opens the town portal
+	PortalActionOpen PortalAction = iota
+	// PortalActionClose closes the town portal
+	PortalActionClose
+)
+
+// PortalEnd is one of the two portals of a town portal, the position is in tiles
+type PortalEnd struct {
+	ID     string              `json:"id"`
+	Region d2enum.RegionIdType `json:"region"`
+	X      float64             `json:"x"`
+	Y      float64             `json:"y"`
+}
+
+// UpdatePortalPacket is sent by the server when a town portal is opened or closed. A town
+// portal is a pair of portals, one in the field where it was cast and one in the town of the
+// act, owned by the player who cast it.
+type UpdatePortalPacket struct {
+	Action    PortalAction `json:"action"`
+	OwnerID   string       `json:"ownerId"`
+	OwnerName string       `json:"ownerName"`
+	Field     PortalEnd    `json:"field"`
+	Town      PortalEnd    `json:"town"`
+}
+
+// CreateUpdatePortalPacket returns a NetPacket which declares an UpdatePortalPacket with the
+// given town portal update.
+func CreateUpdatePortalPacket(update UpdatePortalPacket) (NetPacket, error) {
+	b, err := json.Marshal(update)
+	if err != nil {
+		return NetPacket{PacketType: d2netpackettype.UpdatePortal}, err
+	}
+
+	return NetPacket{
+		PacketType: d2netpackettype.UpdatePortal,
+		PacketData: b,
+	}, nil
+}
+
+// UnmarshalUpdatePortal unmarshals the given data to an UpdatePortalPacket struct
+func UnmarshalUpdatePortal(packet []byte) (UpdatePortalPacket, error) {
+	var p UpdatePortalPacket
+	if err := json.Unmarshal(packet, &p); err != nil {
+		return p, err
+	}
+
+	return p, nil
+}
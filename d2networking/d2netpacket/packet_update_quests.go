@@ -0,0 +1,47 @@
+package d2netpacket
+
+import (
+	"encoding/json"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2quest"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+)
+
+// UpdateQuestsPacket is sent by the server to a client, with the quest state of its player.
+// Completed are the ids of the quests which were completed by the event which caused the
+// update, the client gives their rewards to the player.
+type UpdateQuestsPacket struct {
+	PlayerID  string         `json:"playerId"`
+	Quests    *d2quest.State `json:"quests"`
+	Completed []int          `json:"completed"`
+}
+
+// CreateUpdateQuestsPacket returns a NetPacket which declares an UpdateQuestsPacket with the
+// quest state of the given player.
+func CreateUpdateQuestsPacket(playerID string, quests *d2quest.State, completed []int) (NetPacket, error) {
+	updateQuests := UpdateQuestsPacket{
+		PlayerID:  playerID,
+		Quests:    quests,
+		Completed: completed,
+	}
+
+	b, err := json.Marshal(updateQuests)
+	if err != nil {
+		return NetPacket{PacketType: d2netpackettype.UpdateQuests}, err
+	}
+
+	return NetPacket{
+		PacketType: d2netpackettype.UpdateQuests,
+		PacketData: b,
+	}, nil
+}
+
+// UnmarshalUpdateQuests unmarshals the given data to an UpdateQuestsPacket struct
+func UnmarshalUpdateQuests(packet []byte) (UpdateQuestsPacket, error) {
+	var p UpdateQuestsPacket
+	if err := json.Unmarshal(packet, &p); err != nil {
+		return p, err
+	}
+
+	return p, nil
+}
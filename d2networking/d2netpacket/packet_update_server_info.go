@@ -3,22 +3,26 @@ package d2netpacket
 import (
 	"encoding/json"
 
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+
 	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
 )
 
-// UpdateServerInfoPacket contains the ID for a player and the map seed.
-// It is sent by the server to synchronize these values on the client.
+// UpdateServerInfoPacket contains the ID for a player, the map seed and the difficulty
+// of the game. It is sent by the server to synchronize these values on the client.
 type UpdateServerInfoPacket struct {
-	Seed     int64  `json:"seed"`
-	PlayerID string `json:"playerId"`
+	Seed       int64                 `json:"seed"`
+	PlayerID   string                `json:"playerId"`
+	Difficulty d2enum.DifficultyType `json:"difficulty"`
 }
 
 // CreateUpdateServerInfoPacket returns a NetPacket which declares an
-// UpdateServerInfoPacket with the given player ID and map seed.
-func CreateUpdateServerInfoPacket(seed int64, playerID string) (NetPacket, error) {
+// UpdateServerInfoPacket with the given player ID, map seed and difficulty.
+func CreateUpdateServerInfoPacket(seed int64, playerID string, difficulty d2enum.DifficultyType) (NetPacket, error) {
 	updateServerInfo := UpdateServerInfoPacket{
-		Seed:     seed,
-		PlayerID: playerID,
+		Seed:       seed,
+		PlayerID:   playerID,
+		Difficulty: difficulty,
 	}
 
 	b, err := json.Marshal(updateServerInfo)
@@ -0,0 +1,55 @@
+package d2netpacket
+
+import (
+	"encoding/json"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2states"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+)
+
+// StateAction is the change made to the states of an entity by an UpdateStatesPacket
+type StateAction int
+
+// State actions
+const (
+	StateActionAdd    StateAction = iota // applies the state, with the stacking and exclusion rules
+	StateActionRemove                    // removes the state, it expired or was dispelled
+)
+
+// UpdateStatesPacket is sent by the server to all clients when a state is applied to or
+// removed from an entity, the clients keep their copy of the entity's states in sync.
+type UpdateStatesPacket struct {
+	EntityID string          `json:"entityId"`
+	Action   StateAction     `json:"action"`
+	State    *d2states.State `json:"state"`
+}
+
+// CreateUpdateStatesPacket returns a NetPacket which declares an UpdateStatesPacket with the
+// given state change.
+func CreateUpdateStatesPacket(entityID string, action StateAction, state *d2states.State) (NetPacket, error) {
+	updateStates := UpdateStatesPacket{
+		EntityID: entityID,
+		Action:   action,
+		State:    state,
+	}
+
+	b, err := json.Marshal(updateStates)
+	if err != nil {
+		return NetPacket{PacketType: d2netpackettype.UpdateStates}, err
+	}
+
+	return NetPacket{
+		PacketType: d2netpackettype.UpdateStates,
+		PacketData: b,
+	}, nil
+}
+
+// UnmarshalUpdateStates unmarshals the given data to an UpdateStatesPacket struct
+func UnmarshalUpdateStates(packet []byte) (UpdateStatesPacket, error) {
+	var p UpdateStatesPacket
+	if err := json.Unmarshal(packet, &p); err != nil {
+		return p, err
+	}
+
+	return p, nil
+}
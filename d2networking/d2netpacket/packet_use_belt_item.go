@@ -0,0 +1,46 @@
+package d2netpacket
+
+import (
+	"encoding/json"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+)
+
+// UseBeltItemPacket is sent by the client when a belt quick-key is pressed. The
+// server validates that the belt column holds a usable item, consumes it, and
+// sends the packet to all clients, which then apply the item's effect.
+type UseBeltItemPacket struct {
+	PlayerID string `json:"playerId"`
+	Column   int    `json:"column"`
+	ItemCode string `json:"itemCode"`
+}
+
+// CreateUseBeltItemPacket returns a NetPacket which declares a UseBeltItemPacket
+// for the item with the given code in the given belt column.
+func CreateUseBeltItemPacket(playerID string, column int, itemCode string) (NetPacket, error) {
+	useBeltItem := UseBeltItemPacket{
+		PlayerID: playerID,
+		Column:   column,
+		ItemCode: itemCode,
+	}
+
+	b, err := json.Marshal(useBeltItem)
+	if err != nil {
+		return NetPacket{PacketType: d2netpackettype.UseBeltItem}, err
+	}
+
+	return NetPacket{
+		PacketType: d2netpackettype.UseBeltItem,
+		PacketData: b,
+	}, nil
+}
+
+// UnmarshalUseBeltItem unmarshals the given data to a UseBeltItemPacket struct
+func UnmarshalUseBeltItem(packet []byte) (UseBeltItemPacket, error) {
+	var p UseBeltItemPacket
+	if err := json.Unmarshal(packet, &p); err != nil {
+		return p, err
+	}
+
+	return p, nil
+}
@@ -0,0 +1,43 @@
+package d2netpacket
+
+import (
+	"encoding/json"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+)
+
+// UsePortalPacket is sent by the client when the player clicks on a town portal. The server
+// checks that the player may use it and answers with a WarpPlayerPacket.
+type UsePortalPacket struct {
+	PlayerID string `json:"playerId"`
+	PortalID string `json:"portalId"`
+}
+
+// CreateUsePortalPacket returns a NetPacket which declares a UsePortalPacket for the given
+// player and portal.
+func CreateUsePortalPacket(playerID, portalID string) (NetPacket, error) {
+	usePortal := UsePortalPacket{
+		PlayerID: playerID,
+		PortalID: portalID,
+	}
+
+	b, err := json.Marshal(usePortal)
+	if err != nil {
+		return NetPacket{PacketType: d2netpackettype.UsePortal}, err
+	}
+
+	return NetPacket{
+		PacketType: d2netpackettype.UsePortal,
+		PacketData: b,
+	}, nil
+}
+
+// UnmarshalUsePortal unmarshals the given data to a UsePortalPacket struct
+func UnmarshalUsePortal(packet []byte) (UsePortalPacket, error) {
+	var p UsePortalPacket
+	if err := json.Unmarshal(packet, &p); err != nil {
+		return p, err
+	}
+
+	return p, nil
+}
@@ -0,0 +1,45 @@
+package d2netpacket
+
+import (
+	"encoding/json"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+)
+
+// WarpPlayerPacket is sent by the server to move a player to a position at once, like when
+// going through a town portal. The position is in tiles.
+type WarpPlayerPacket struct {
+	PlayerID string  `json:"playerId"`
+	X        float64 `json:"x"`
+	Y        float64 `json:"y"`
+}
+
+// CreateWarpPlayerPacket returns a NetPacket which declares a WarpPlayerPacket for the given
+// player and position.
+func CreateWarpPlayerPacket(playerID string, x, y float64) (NetPacket, error) {
+	warpPlayer := WarpPlayerPacket{
+		PlayerID: playerID,
+		X:        x,
+		Y:        y,
+	}
+
+	b, err := json.Marshal(warpPlayer)
+	if err != nil {
+		return NetPacket{PacketType: d2netpackettype.WarpPlayer}, err
+	}
+
+	return NetPacket{
+		PacketType: d2netpackettype.WarpPlayer,
+		PacketData: b,
+	}, nil
+}
+
+// UnmarshalWarpPlayer unmarshals the given data to a WarpPlayerPacket struct
+func UnmarshalWarpPlayer(packet []byte) (WarpPlayerPacket, error) {
+	var p WarpPlayerPacket
+	if err := json.Unmarshal(packet, &p); err != nil {
+		return p, err
+	}
+
+	return p, nil
+}
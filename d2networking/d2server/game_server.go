@@ -4,19 +4,28 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"math/rand"
 	"net"
 	"sync"
 	"time"
 
 	"github.com/robertkrimen/otto"
 
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2calculation"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2util"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2automap"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2hero"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2item/diablo2item"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapengine"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapgen"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2party"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2quest"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2records"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2states"
 	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2client/d2clientconnectiontype"
 	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket"
 	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
@@ -33,14 +42,31 @@ const (
 	middleOfTileOffset     = 3
 )
 
+const (
+	// how often the timed states of the entities are counted down
+	stateTickInterval = 250 * time.Millisecond
+	// the length calculations of skills.txt are in frames
+	skillFramesPerSecond = 25.0
+	// used for the timed states of skills whose length can't be calculated yet
+	defaultStateSeconds = 10.0
+)
+
 var (
 	errPlayerAlreadyExists = errors.New("player already exists")
-	errServerFull          = errors.New("server full") // Server currently at maximum TCP connections
+	errServerFull          = errors.New("server full")       // Server currently at maximum TCP connections
+	errDifficultyLocked    = errors.New("difficulty locked") // The hero hasn't unlocked the difficulty of the game
+	errInvalidBeltItemUse  = errors.New("invalid belt item use")
+	errInvalidHireling     = errors.New("invalid hireling update")
+	errNotEnoughGold       = errors.New("not enough gold")
+	errInvalidQuestEvent   = errors.New("invalid quest event")
+	errServerStopped       = errors.New("game server stopped")
 )
 
 // GameServer manages a copy of the map and entities as well as manages packet routing and connections.
 // It can accept connections from localhost as well remote clients. It can also be started in a standalone mode.
 type GameServer struct {
+	// guards the whole server state, the packet handlers, the tickers and the connection
+	// changes hold it, so they never run at the same time
 	sync.RWMutex
 	connections       map[string]ClientConnection
 	listener          net.Listener
@@ -51,9 +77,29 @@ type GameServer struct {
 	mapEngines        []*d2mapengine.MapEngine
 	scriptEngine      *d2script.ScriptEngine
 	seed              int64
+	difficulty        d2enum.DifficultyType
 	maxConnections    int
 	packetManagerChan chan ReceivedPacket
 	heroStateFactory  *d2hero.HeroStateFactory
+	states            map[string]*d2states.List
+	statesMutex       sync.Mutex
+	itemFactory       *diablo2item.ItemFactory
+	objectResets      map[string]time.Time
+	objectsMutex      sync.Mutex
+	portals           map[string]*townPortal
+	portalsMutex      sync.Mutex
+	parties           *d2party.Parties
+	partiesMutex      sync.Mutex
+	chatLimiters      map[string]*chatLimiter
+	chatMutex         sync.Mutex
+	monsters          map[string]*monsterState
+	nextAttacks       map[string]time.Time
+	combatRand        *rand.Rand
+	combatMutex       sync.Mutex
+	groundItems       map[string]*groundItem
+	playerLevels      map[string]int
+	levelsMutex       sync.Mutex
+	itemsMutex        sync.Mutex
 
 	*d2util.Logger
 }
@@ -84,6 +130,11 @@ func NewGameServer(asset *d2asset.AssetManager,
 		return nil, err
 	}
 
+	itemFactory, err := diablo2item.NewItemFactory(asset)
+	if err != nil {
+		return nil, err
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	gameServer := &GameServer{
@@ -98,8 +149,23 @@ func NewGameServer(asset *d2asset.AssetManager,
 		scriptEngine:      d2script.CreateScriptEngine(),
 		seed:              time.Now().UnixNano(),
 		heroStateFactory:  heroStateFactory,
+		states:            make(map[string]*d2states.List),
+		itemFactory:       itemFactory,
+		objectResets:      make(map[string]time.Time),
+		portals:           make(map[string]*townPortal),
+		parties:           d2party.NewParties(),
+		chatLimiters:      make(map[string]*chatLimiter),
+		monsters:          make(map[string]*monsterState),
+		nextAttacks:       make(map[string]time.Time),
+		groundItems:       make(map[string]*groundItem),
+		playerLevels:      make(map[string]int),
 	}
 
+	// nolint:gosec // not concerned with crypto-strong randomness
+	gameServer.combatRand = rand.New(rand.NewSource(gameServer.seed))
+
+	itemFactory.SetSeed(gameServer.seed)
+
 	gameServer.Logger = d2util.NewLogger()
 	gameServer.Logger.SetPrefix(logPrefix)
 	gameServer.Logger.SetLevel(l)
@@ -114,6 +180,7 @@ func NewGameServer(asset *d2asset.AssetManager,
 	}
 
 	mapGen.GenerateAct1Overworld()
+	gameServer.initObjects(mapEngine)
 
 	gameServer.mapEngines = append(gameServer.mapEngines, mapEngine)
 
@@ -171,6 +238,12 @@ func (g *GameServer) Start() error {
 // Stop stops the game server
 func (g *GameServer) Stop() {
 	g.Lock()
+	defer g.Unlock()
+
+	g.stop()
+}
+
+func (g *GameServer) stop() {
 	g.cancel()
 	g.connections = make(map[string]ClientConnection)
 
@@ -183,11 +256,29 @@ func (g *GameServer) Stop() {
 func (g *GameServer) packetManager() {
 	defer close(g.packetManagerChan)
 
+	stateTicker := time.NewTicker(stateTickInterval)
+	defer stateTicker.Stop()
+
+	objectTicker := time.NewTicker(objectTickInterval)
+	defer objectTicker.Stop()
+
+	monsterTicker := time.NewTicker(monsterTickInterval)
+	defer monsterTicker.Stop()
+
 	for {
 		select {
 		// If the server is stopped we need to clean up the packet manager goroutine
 		case <-g.ctx.Done():
 			return
+		case <-stateTicker.C:
+			g.tick(func() { g.advanceStates(stateTickInterval.Seconds()) })
+		case now := <-objectTicker.C:
+			g.tick(func() { g.resetObjects(now) })
+		case <-monsterTicker.C:
+			g.tick(func() {
+				g.hirelingsAttack()
+				g.monstersAttack()
+			})
 		case p := <-g.packetManagerChan:
 			err := g.OnPacketReceived(p.Client, p.Packet)
 			if err != nil {
@@ -197,6 +288,19 @@ func (g *GameServer) packetManager() {
 	}
 }
 
+// tick runs a periodic update of the server state, it holds the lock of the server state like the
+// packet handlers do
+func (g *GameServer) tick(update func()) {
+	g.Lock()
+	defer g.Unlock()
+
+	if g.ctx.Err() != nil {
+		return
+	}
+
+	update()
+}
+
 func (g *GameServer) sendPacketToClients(packet d2netpacket.NetPacket) {
 	for _, c := range g.connections {
 		if err := c.SendPacketToClient(packet); err != nil {
@@ -270,6 +374,7 @@ func (g *GameServer) handleConnection(conn net.Conn) {
 // Errors:
 // - errServerFull
 // - errPlayerAlreadyExists
+// - errDifficultyLocked
 func (g *GameServer) registerConnection(b []byte, conn net.Conn) (ClientConnection, error) {
 	var client ClientConnection
 
@@ -306,11 +411,24 @@ func (g *GameServer) registerConnection(b []byte, conn net.Conn) (ClientConnecti
 		return client, errPlayerAlreadyExists
 	}
 
+	// check to see if the hero can play the difficulty of the game
+	if packet.PlayerState != nil && packet.PlayerState.Quests != nil &&
+		packet.PlayerState.Quests.MaxDifficulty() < g.difficulty {
+		g.Errorf("%v: %s", errDifficultyLocked, packet.PlayerState.HeroName)
+		return client, errDifficultyLocked
+	}
+
+	// dead hardcore heroes can't play anymore
+	if packet.PlayerState != nil && packet.PlayerState.IsDead {
+		g.Errorf("%v: %s", errHeroIsDead, packet.PlayerState.HeroName)
+		return client, errHeroIsDead
+	}
+
 	// Client a new TCP Client Connection and add it to the connections map
 	client = d2tcpclientconnection.CreateTCPClientConnection(conn, packet.ID)
 	client.SetPlayerState(packet.PlayerState)
 
-	g.OnClientConnected(client)
+	g.onClientConnected(client)
 
 	return client, nil
 }
@@ -324,6 +442,13 @@ func (g *GameServer) registerConnection(b []byte, conn net.Conn) (ClientConnecti
 //
 // For more information, see d2networking.d2netpacket.
 func (g *GameServer) OnClientConnected(client ClientConnection) {
+	g.Lock()
+	defer g.Unlock()
+
+	g.onClientConnected(client)
+}
+
+func (g *GameServer) onClientConnected(client ClientConnection) {
 	// Temporary position hack --------------------------------------------
 	// https://github.com/OpenDiablo2/OpenDiablo2/issues/829
 	sx, sy := g.mapEngines[0].GetStartPosition()
@@ -332,6 +457,17 @@ func (g *GameServer) OnClientConnected(client ClientConnection) {
 	clientPlayerState.Y = sy
 	// --------------------------------------------------------------------
 
+	// the host chooses the difficulty of the game, the other players join it
+	if client.GetConnectionType() == d2clientconnectiontype.Local {
+		g.difficulty = clientPlayerState.Difficulty
+
+		if clientPlayerState.Quests != nil && clientPlayerState.Quests.MaxDifficulty() < g.difficulty {
+			g.difficulty = clientPlayerState.Quests.MaxDifficulty()
+		}
+	}
+
+	clientPlayerState.Difficulty = g.difficulty
+
 	g.Infof("Client connected with an id of %s", client.GetUniqueID())
 	g.connections[client.GetUniqueID()] = client
 
@@ -339,7 +475,7 @@ func (g *GameServer) OnClientConnected(client ClientConnection) {
 }
 
 func (g *GameServer) handleClientConnection(client ClientConnection, x, y float64) {
-	usi, err := d2netpacket.CreateUpdateServerInfoPacket(g.seed, client.GetUniqueID())
+	usi, err := d2netpacket.CreateUpdateServerInfoPacket(g.seed, client.GetUniqueID(), g.difficulty)
 	if err != nil {
 		g.Errorf("UpdateServerInfoPacket: %v", err)
 	}
@@ -376,6 +512,7 @@ func (g *GameServer) handleClientConnection(client ClientConnection, x, y float6
 		playerState.Stats,
 		playerState.Skills,
 		playerState.Equipment,
+		playerState.Belt,
 		playerState.LeftSkill,
 		playerState.RightSkill,
 		playerState.Gold,
@@ -406,6 +543,7 @@ func (g *GameServer) handleClientConnection(client ClientConnection, x, y float6
 			conPlayerState.Stats,
 			conPlayerState.Skills,
 			conPlayerState.Equipment,
+			conPlayerState.Belt,
 			conPlayerState.LeftSkill,
 			conPlayerState.RightSkill,
 			conPlayerState.Gold,
@@ -420,6 +558,80 @@ func (g *GameServer) handleClientConnection(client ClientConnection, x, y float6
 		if err != nil {
 			g.Errorf("GameServer: error sending CreateAddPlayerPacket to client %s: %s", connection.GetUniqueID(), err)
 		}
+
+		g.sendHirelingToClient(client, connection.GetUniqueID(), conPlayerState)
+		g.sendCorpsesToClient(client, connection.GetUniqueID(), conPlayerState)
+	}
+
+	for _, connection := range g.connections {
+		g.sendHirelingToClient(connection, client.GetUniqueID(), playerState)
+		g.sendCorpsesToClient(connection, client.GetUniqueID(), playerState)
+	}
+
+	if playerState.Quests == nil {
+		playerState.Quests = d2quest.NewState()
+	}
+
+	if playerState.Automap == nil {
+		playerState.Automap = d2automap.NewState()
+	}
+
+	playerState.Automap.Reseed(g.seed)
+
+	g.sendQuestsToClient(client, nil)
+	g.sendAutomapToClient(client)
+	g.addStateList(client)
+	g.sendObjectsToClient(client)
+	g.sendMonstersToClient(client)
+	g.sendItemsToClient(client)
+	g.updatePlayerLevel(client)
+	g.sendPortalsToClient(client)
+	g.sendPartiesToClient(client)
+}
+
+// sendQuestsToClient sends the quest state of the client's player to the client
+func (g *GameServer) sendQuestsToClient(client ClientConnection, completed []int) {
+	playerState := client.GetPlayerState()
+
+	update, err := d2netpacket.CreateUpdateQuestsPacket(client.GetUniqueID(), playerState.Quests, completed)
+	if err != nil {
+		g.Errorf("UpdateQuestsPacket: %v", err)
+		return
+	}
+
+	if err := client.SendPacketToClient(update); err != nil {
+		g.Errorf("GameServer: error sending UpdateQuestsPacket to client %s: %s", client.GetUniqueID(), err)
+	}
+}
+
+// sendAutomapToClient sends the explored tiles of the client's player to the client
+func (g *GameServer) sendAutomapToClient(client ClientConnection) {
+	update, err := d2netpacket.CreateUpdateAutomapPacket(client.GetUniqueID(), client.GetPlayerState().Automap)
+	if err != nil {
+		g.Errorf("UpdateAutomapPacket: %v", err)
+		return
+	}
+
+	if err := client.SendPacketToClient(update); err != nil {
+		g.Errorf("GameServer: error sending UpdateAutomapPacket to client %s: %s", client.GetUniqueID(), err)
+	}
+}
+
+// sendHirelingToClient sends the hireling of the given player, if it has one, to the client
+func (g *GameServer) sendHirelingToClient(client ClientConnection, playerID string, playerState *d2hero.HeroState) {
+	if playerState.Hireling == nil {
+		return
+	}
+
+	spawn, err := d2netpacket.CreateUpdateHirelingPacket(playerID, d2netpacket.HirelingActionSpawn,
+		playerState.Hireling, playerState.Gold)
+	if err != nil {
+		g.Errorf("UpdateHirelingPacket: %v", err)
+		return
+	}
+
+	if err := client.SendPacketToClient(spawn); err != nil {
+		g.Errorf("GameServer: error sending UpdateHirelingPacket to client %s: %s", client.GetUniqueID(), err)
 	}
 }
 
@@ -427,9 +639,38 @@ func (g *GameServer) handleClientConnection(client ClientConnection, x, y float6
 // of client connections.
 // If this client was the host, disconnects all clients and kills GameServer.
 func (g *GameServer) OnClientDisconnected(client ClientConnection) {
+	g.Lock()
+	defer g.Unlock()
+
+	g.onClientDisconnected(client)
+}
+
+func (g *GameServer) onClientDisconnected(client ClientConnection) {
 	g.Infof("Client disconnected with an id of %s", client.GetUniqueID())
 	delete(g.connections, client.GetUniqueID())
 
+	g.statesMutex.Lock()
+	delete(g.states, client.GetUniqueID())
+	g.statesMutex.Unlock()
+
+	g.portalsMutex.Lock()
+	g.closePortal(client.GetUniqueID())
+	g.portalsMutex.Unlock()
+
+	g.chatMutex.Lock()
+	delete(g.chatLimiters, client.GetUniqueID())
+	g.chatMutex.Unlock()
+
+	g.combatMutex.Lock()
+	delete(g.nextAttacks, client.GetUniqueID())
+	g.combatMutex.Unlock()
+
+	g.levelsMutex.Lock()
+	delete(g.playerLevels, client.GetUniqueID())
+	g.levelsMutex.Unlock()
+
+	g.removeFromParties(client.GetUniqueID())
+
 	if client.GetConnectionType() == d2clientconnectiontype.Local {
 		g.Info("Host disconnected, game server shuting down")
 
@@ -440,18 +681,30 @@ func (g *GameServer) OnClientDisconnected(client ClientConnection) {
 			g.sendPacketToClients(serverClosed)
 		}
 
-		g.Stop()
+		g.stop()
 	}
 }
 
 // OnPacketReceived is called when a packet has been received from a remote client,
 // and by the local client to 'send' a packet to the server,
-// nolint:gocyclo // switch statement on packet type makes sense, no need to change
 func (g *GameServer) OnPacketReceived(client ClientConnection, packet d2netpacket.NetPacket) error {
 	if g == nil {
 		return errors.New("game server is nil")
 	}
 
+	g.Lock()
+	defer g.Unlock()
+
+	if g.ctx.Err() != nil {
+		return errServerStopped
+	}
+
+	return g.handlePacket(client, packet)
+}
+
+// handlePacket handles a packet of the client, with the lock of the server state held
+// nolint:gocyclo // switch statement on packet type makes sense, no need to change
+func (g *GameServer) handlePacket(client ClientConnection, packet d2netpacket.NetPacket) error {
 	switch packet.PacketType {
 	case d2netpackettype.MovePlayer:
 		movePacket, err := d2netpacket.UnmarshalMovePlayer(packet.PacketData)
@@ -464,8 +717,29 @@ func (g *GameServer) OnPacketReceived(client ClientConnection, packet d2netpacke
 		playerState.Y = movePacket.DestY
 
 		g.sendPacketToClients(packet)
-	case d2netpackettype.CastSkill, d2netpackettype.SpawnItem:
+		g.updatePlayerLevel(client)
+	case d2netpackettype.CastSkill:
 		g.sendPacketToClients(packet)
+
+		if err := g.handleCastSkillStates(client, packet); err != nil {
+			return err
+		}
+
+		if err := g.handleCastSkillPortal(client, packet); err != nil {
+			return err
+		}
+	case d2netpackettype.SpawnItem:
+		if err := g.handleSpawnItem(client, packet); err != nil {
+			return err
+		}
+	case d2netpackettype.PickupItem:
+		if err := g.handlePickupItem(client, packet); err != nil {
+			return err
+		}
+	case d2netpackettype.RepairItems:
+		if err := g.handleRepairItems(client, packet); err != nil {
+			return err
+		}
 	case d2netpackettype.SavePlayer:
 		savePacket, err := d2netpacket.UnmarshalSavePlayer(packet.PacketData)
 		if err != nil {
@@ -477,20 +751,382 @@ func (g *GameServer) OnPacketReceived(client ClientConnection, packet d2netpacke
 		playerState.RightSkill = savePacket.Player.RightSkill.Shallow.SkillID
 		playerState.Stats = savePacket.Player.Stats
 		playerState.Act = savePacket.Player.Act
-		playerState.Difficulty = savePacket.Difficulty
+		playerState.Difficulty = g.difficulty
+
+		if savePacket.Player.Automap != nil && savePacket.Player.Automap.Seed == g.seed {
+			playerState.Automap = savePacket.Player.Automap
+		}
 
 		err = g.heroStateFactory.Save(playerState)
 		if err != nil {
 			g.Errorf("GameServer: error saving saving Player: %s", err)
 		}
+	case d2netpackettype.UseBeltItem:
+		if err := g.handleUseBeltItem(client, packet); err != nil {
+			return err
+		}
+	case d2netpackettype.UpdateHireling:
+		if err := g.handleUpdateHireling(client, packet); err != nil {
+			return err
+		}
+	case d2netpackettype.QuestEvent:
+		if err := g.handleQuestEvent(client, packet); err != nil {
+			return err
+		}
+	case d2netpackettype.OperateObject:
+		if err := g.handleOperateObject(client, packet); err != nil {
+			return err
+		}
+	case d2netpackettype.UsePortal:
+		if err := g.handleUsePortal(client, packet); err != nil {
+			return err
+		}
+	case d2netpackettype.PlayerDeath:
+		if err := g.handlePlayerDeath(client, packet); err != nil {
+			return err
+		}
+	case d2netpackettype.RetrieveCorpse:
+		if err := g.handleRetrieveCorpse(client, packet); err != nil {
+			return err
+		}
+	case d2netpackettype.PartyAction:
+		if err := g.handlePartyAction(client, packet); err != nil {
+			return err
+		}
+	case d2netpackettype.ChatMessage:
+		if err := g.handleChatMessage(client, packet); err != nil {
+			return err
+		}
+	case d2netpackettype.Attack:
+		if err := g.handleAttack(client, packet); err != nil {
+			return err
+		}
 	case d2netpackettype.PlayerConnectionRequest:
 		break // prevent log message. these are handled by handleConnection
 	case d2netpackettype.PlayerDisconnectionNotification:
 		g.sendPacketToClients(packet)
-		g.OnClientDisconnected(client)
+		g.onClientDisconnected(client)
 	default:
 		g.Warningf("GameServer: received unknown packet %s", packet.PacketType)
 	}
 
 	return nil
 }
+
+// handleUseBeltItem validates the use of a belt item by the client, consumes the item from
+// the server's copy of the belt, and tells all clients to consume it and apply its effect
+func (g *GameServer) handleUseBeltItem(client ClientConnection, packet d2netpacket.NetPacket) error {
+	usePacket, err := d2netpacket.UnmarshalUseBeltItem(packet.PacketData)
+	if err != nil {
+		return err
+	}
+
+	playerState := client.GetPlayerState()
+	if usePacket.PlayerID != client.GetUniqueID() || playerState == nil || playerState.Belt == nil {
+		return fmt.Errorf("%w: player %s", errInvalidBeltItemUse, usePacket.PlayerID)
+	}
+
+	item := playerState.Belt.Peek(usePacket.Column)
+	if item == nil || item.ItemCode != usePacket.ItemCode || !g.heroStateFactory.IsPotion(item.ItemCode) {
+		return fmt.Errorf("%w: column %d, item %s", errInvalidBeltItemUse, usePacket.Column, usePacket.ItemCode)
+	}
+
+	playerState.Belt.Use(usePacket.Column)
+
+	used, err := d2netpacket.CreateUseBeltItemPacket(client.GetUniqueID(), usePacket.Column, item.ItemCode)
+	if err != nil {
+		return err
+	}
+
+	// the server restores the whole amount at once, the clients restore it over time
+	if effect, err := g.heroStateFactory.NewPotionEffect(item.ItemCode, playerState.Stats); err == nil &&
+		playerState.Stats != nil {
+		g.combatMutex.Lock()
+		playerState.Stats.RestoreHealth(effect.Life)
+		playerState.Stats.RestoreMana(effect.Mana)
+		g.combatMutex.Unlock()
+	}
+
+	g.sendPacketToClients(used)
+	g.advanceQuests(client, d2quest.UseItemTrigger(item.ItemCode))
+
+	return nil
+}
+
+// handleUpdateHireling validates a request of the client to hire or revive a hireling, takes
+// the cost from the player's gold, and sends the resulting hireling to all clients
+func (g *GameServer) handleUpdateHireling(client ClientConnection, packet d2netpacket.NetPacket) error {
+	updatePacket, err := d2netpacket.UnmarshalUpdateHireling(packet.PacketData)
+	if err != nil {
+		return err
+	}
+
+	playerState := client.GetPlayerState()
+	if updatePacket.PlayerID != client.GetUniqueID() || playerState == nil || playerState.Stats == nil {
+		return fmt.Errorf("%w: player %s", errInvalidHireling, updatePacket.PlayerID)
+	}
+
+	if updatePacket.Action == d2netpacket.HirelingActionEquip {
+		return g.equipHireling(client, updatePacket.Slot)
+	}
+
+	// hirelings are hired and revived at the npcs selling them
+	if !g.nearHirelingSeller(client) {
+		return fmt.Errorf("%w: player %s is not next to a hireling seller", errInvalidHireling, updatePacket.PlayerID)
+	}
+
+	var cost int
+
+	hireling := playerState.Hireling
+
+	switch updatePacket.Action {
+	case d2netpacket.HirelingActionHire:
+		hireling = updatePacket.Hireling
+		if hireling == nil || !g.heroStateFactory.IsHireableHireling(hireling, playerState.Act,
+			playerState.Difficulty, playerState.Stats.Level) {
+			return fmt.Errorf("%w: hireling can't be hired by player %s", errInvalidHireling, updatePacket.PlayerID)
+		}
+
+		// the client only chooses which hireling to hire, everything else is up to the server
+		hireling = &d2hero.HirelingState{
+			ID:         hireling.ID,
+			Difficulty: hireling.Difficulty,
+			Name:       hireling.Name,
+			Level:      hireling.Level,
+		}
+		hireling.Experience = g.heroStateFactory.HirelingExperienceForLevel(hireling, hireling.Level)
+		hireling.Health = g.heroStateFactory.HirelingStats(hireling).MaxHealth
+		cost = g.heroStateFactory.HirelingHireCost(hireling)
+	case d2netpacket.HirelingActionRevive:
+		if hireling == nil || !hireling.IsDead {
+			return fmt.Errorf("%w: player %s has no dead hireling", errInvalidHireling, updatePacket.PlayerID)
+		}
+
+		cost = g.heroStateFactory.ReviveHirelingCost(hireling)
+	default:
+		return fmt.Errorf("%w: unknown action %d", errInvalidHireling, updatePacket.Action)
+	}
+
+	if playerState.Gold < cost {
+		return fmt.Errorf("%w: player %s has %d gold, needs %d", errNotEnoughGold, updatePacket.PlayerID,
+			playerState.Gold, cost)
+	}
+
+	if updatePacket.Action == d2netpacket.HirelingActionRevive {
+		g.heroStateFactory.ReviveHireling(hireling)
+	}
+
+	playerState.Gold -= cost
+	playerState.Hireling = hireling
+
+	updated, err := d2netpacket.CreateUpdateHirelingPacket(client.GetUniqueID(), updatePacket.Action, hireling,
+		playerState.Gold)
+	if err != nil {
+		return err
+	}
+
+	g.sendPacketToClients(updated)
+
+	return nil
+}
+
+// handleQuestEvent advances the quests for an event caused by the client's player
+func (g *GameServer) handleQuestEvent(client ClientConnection, packet d2netpacket.NetPacket) error {
+	eventPacket, err := d2netpacket.UnmarshalQuestEvent(packet.PacketData)
+	if err != nil {
+		return err
+	}
+
+	playerState := client.GetPlayerState()
+	if eventPacket.PlayerID != client.GetUniqueID() || playerState == nil || playerState.Quests == nil {
+		return fmt.Errorf("%w: player %s", errInvalidQuestEvent, eventPacket.PlayerID)
+	}
+
+	// the client can only claim to talk to a town npc next to the player, and to have seen the
+	// quest log, the other events are up to the server
+	switch eventPacket.Trigger.Type {
+	case d2quest.TriggerTalkToNPC:
+		if !g.nearNPC(client, eventPacket.Trigger.Target) {
+			return fmt.Errorf("%w: player %s is not next to npc %s", errInvalidQuestEvent, eventPacket.PlayerID,
+				eventPacket.Trigger.Target)
+		}
+	case d2quest.TriggerQuestLogViewed:
+	default:
+		return fmt.Errorf("%w: player %s can't send trigger %d", errInvalidQuestEvent, eventPacket.PlayerID,
+			eventPacket.Trigger.Type)
+	}
+
+	g.advanceQuests(client, eventPacket.Trigger)
+
+	return nil
+}
+
+// advanceQuests advances the quests of the client's player for the trigger. The progress of
+// the steps which are shared with the party is also given to the party members in range.
+func (g *GameServer) advanceQuests(client ClientConnection, trigger d2quest.Trigger) {
+	g.advancePlayerQuests(client, trigger, false)
+
+	if trigger.Type == d2quest.TriggerQuestLogViewed {
+		return
+	}
+
+	for _, member := range g.partyInRange(client) {
+		if member.GetUniqueID() != client.GetUniqueID() {
+			g.advancePlayerQuests(member, trigger, true)
+		}
+	}
+}
+
+// advancePlayerQuests advances the quests of the client's player, gives the rewards of
+// the completed quests and sends the changed quest state to the client
+func (g *GameServer) advancePlayerQuests(client ClientConnection, trigger d2quest.Trigger, sharedOnly bool) {
+	playerState := client.GetPlayerState()
+	if playerState.Quests == nil {
+		return
+	}
+
+	updates := playerState.Quests.Fire(playerState.Difficulty, trigger, sharedOnly)
+	if len(updates) == 0 {
+		return
+	}
+
+	completed := make([]int, 0)
+
+	for _, update := range updates {
+		if !update.Completed {
+			continue
+		}
+
+		playerState.Stats.ApplyQuestRewards(update.Quest.Rewards)
+		completed = append(completed, update.Quest.ID)
+
+		g.Infof("Player %s completed quest %d of act %d", client.GetUniqueID(), update.Quest.Number,
+			update.Quest.Act)
+	}
+
+	g.sendQuestsToClient(client, completed)
+}
+
+// addStateList creates the list of states of the client's player, and sends the states
+// which are active on the other players to the client
+func (g *GameServer) addStateList(client ClientConnection) {
+	g.statesMutex.Lock()
+	defer g.statesMutex.Unlock()
+
+	g.states[client.GetUniqueID()] = d2states.NewList(g.asset.Records.States)
+
+	for entityID, list := range g.states {
+		for _, state := range list.States() {
+			add, err := d2netpacket.CreateUpdateStatesPacket(entityID, d2netpacket.StateActionAdd, state)
+			if err != nil {
+				g.Errorf("UpdateStatesPacket: %v", err)
+				continue
+			}
+
+			if err := client.SendPacketToClient(add); err != nil {
+				g.Errorf("GameServer: error sending UpdateStatesPacket to client %s: %s", client.GetUniqueID(), err)
+			}
+		}
+	}
+}
+
+// handleCastSkillStates applies the state of the skill cast by the client's player, if it has one
+func (g *GameServer) handleCastSkillStates(client ClientConnection, packet d2netpacket.NetPacket) error {
+	castPacket, err := d2netpacket.UnmarshalCast(packet.PacketData)
+	if err != nil {
+		return err
+	}
+
+	skill := g.asset.Records.Skill.Details[castPacket.SkillID]
+	if skill == nil || skill.Aurastate == "" {
+		return nil
+	}
+
+	return g.applyState(client.GetUniqueID(), g.skillState(skill, client.GetUniqueID()))
+}
+
+// skillState creates the state applied by the skill, with the stat modifiers of the skill
+func (g *GameServer) skillState(skill *d2records.SkillRecord, source string) *d2states.State {
+	auraStats := []struct {
+		stat string
+		calc d2calculation.Calculation
+	}{
+		{skill.Aurastat1, skill.Aurastatcalc1},
+		{skill.Aurastat2, skill.Aurastatcalc2},
+		{skill.Aurastat3, skill.Aurastatcalc3},
+		{skill.Aurastat4, skill.Aurastatcalc4},
+		{skill.Aurastat5, skill.Aurastatcalc5},
+		{skill.Aurastat6, skill.Aurastatcalc6},
+	}
+
+	modifiers := make([]d2states.Modifier, 0)
+
+	for _, auraStat := range auraStats {
+		if auraStat.stat != "" && auraStat.calc != nil {
+			modifiers = append(modifiers, d2states.Modifier{Stat: auraStat.stat, Value: auraStat.calc.Eval()})
+		}
+	}
+
+	// auras last until they are replaced by another aura, other states are timed
+	duration := 0.0
+
+	if record := g.asset.Records.States[skill.Aurastate]; record != nil && !record.Aura {
+		duration = defaultStateSeconds
+
+		if skill.Auralencalc != nil {
+			if frames := skill.Auralencalc.Eval(); frames > 0 {
+				duration = float64(frames) / skillFramesPerSecond
+			}
+		}
+	}
+
+	return d2states.NewState(skill.Aurastate, source, duration, modifiers...)
+}
+
+// applyState adds the state to the entity, and tells all clients about the added state and
+// the states it replaced
+func (g *GameServer) applyState(entityID string, state *d2states.State) error {
+	g.statesMutex.Lock()
+	defer g.statesMutex.Unlock()
+
+	list, found := g.states[entityID]
+	if !found {
+		return nil
+	}
+
+	removed, err := list.Add(state)
+	if err != nil {
+		return err
+	}
+
+	g.sendStateRemovals(entityID, removed)
+	g.sendStateUpdate(entityID, d2netpacket.StateActionAdd, state)
+
+	return nil
+}
+
+// advanceStates counts down the timed states of all entities, and removes the expired ones
+func (g *GameServer) advanceStates(elapsed float64) {
+	g.statesMutex.Lock()
+	defer g.statesMutex.Unlock()
+
+	for entityID, list := range g.states {
+		g.sendStateRemovals(entityID, list.Advance(elapsed))
+	}
+}
+
+func (g *GameServer) sendStateRemovals(entityID string, removed []*d2states.State) {
+	for _, state := range removed {
+		g.sendStateUpdate(entityID, d2netpacket.StateActionRemove, state)
+	}
+}
+
+func (g *GameServer) sendStateUpdate(entityID string, action d2netpacket.StateAction, state *d2states.State) {
+	update, err := d2netpacket.CreateUpdateStatesPacket(entityID, action, state)
+	if err != nil {
+		g.Errorf("UpdateStatesPacket: %v", err)
+		return
+	}
+
+	g.sendPacketToClients(update)
+}
@@ -0,0 +1,168 @@
+package d2server
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket"
+)
+
+const (
+	// a player can send this many messages within the interval, further messages are dropped
+	chatBurst         = 5
+	chatBurstInterval = 10 * time.Second
+
+	// longer messages are cut
+	chatMaxLength = 200
+)
+
+var errInvalidChatMessage = errors.New("invalid chat message")
+
+// chatLimiter drops the messages of a player beyond the burst within the burst interval
+type chatLimiter struct {
+	sent []time.Time
+}
+
+// allow returns true and counts the message if the player may send a message at the given time
+func (l *chatLimiter) allow(now time.Time) bool {
+	recent := l.sent[:0]
+
+	for _, sent := range l.sent {
+		if now.Sub(sent) < chatBurstInterval {
+			recent = append(recent, sent)
+		}
+	}
+
+	l.sent = recent
+
+	if len(l.sent) >= chatBurst {
+		return false
+	}
+
+	l.sent = append(l.sent, now)
+
+	return true
+}
+
+// handleChatMessage relays the message of the client's player to the players of its channel.
+// Messages sent too fast, to unknown players or to a party the player isn't in are answered
+// with a notice to the sender.
+func (g *GameServer) handleChatMessage(client ClientConnection, packet d2netpacket.NetPacket) error {
+	message, err := d2netpacket.UnmarshalChatMessage(packet.PacketData)
+	if err != nil {
+		return err
+	}
+
+	playerID := client.GetUniqueID()
+	if message.PlayerID != playerID {
+		return fmt.Errorf("%w: player %s can't speak for %s", errInvalidChatMessage, playerID, message.PlayerID)
+	}
+
+	if message.Phrase != 0 && !message.IsQuickSay() {
+		return fmt.Errorf("%w: player %s sent unknown phrase %d", errInvalidChatMessage, playerID, message.Phrase)
+	}
+
+	message.Text = strings.TrimSpace(message.Text)
+	if message.Text == "" {
+		return nil
+	}
+
+	if utf8.RuneCountInString(message.Text) > chatMaxLength {
+		message.Text = string([]rune(message.Text)[:chatMaxLength])
+	}
+
+	g.chatMutex.Lock()
+	limiter, found := g.chatLimiters[playerID]
+
+	if !found {
+		limiter = &chatLimiter{}
+		g.chatLimiters[playerID] = limiter
+	}
+
+	allowed := limiter.allow(time.Now())
+	g.chatMutex.Unlock()
+
+	if !allowed {
+		g.sendChatNotice(client, d2netpacket.ChatNoticeRateLimit, "")
+		return nil
+	}
+
+	message.PlayerName = client.GetPlayerState().HeroName
+
+	relay, err := d2netpacket.CreateChatMessagePacket(message)
+	if err != nil {
+		return err
+	}
+
+	switch message.Channel {
+	case d2netpacket.ChatChannelAll:
+		g.sendPacketToClients(relay)
+	case d2netpacket.ChatChannelWhisper:
+		recipient := g.connectionByHeroName(message.Recipient)
+		if recipient == nil {
+			g.sendChatNotice(client, d2netpacket.ChatNoticeNoRecipient, message.Recipient)
+			return nil
+		}
+
+		g.sendChatMessage(recipient, relay)
+
+		if recipient != client {
+			g.sendChatMessage(client, relay)
+		}
+	case d2netpacket.ChatChannelParty:
+		if !g.isInParty(playerID) {
+			g.sendChatNotice(client, d2netpacket.ChatNoticeNoParty, "")
+			return nil
+		}
+
+		for id, connection := range g.connections {
+			if id == playerID || g.inSameParty(playerID, id) {
+				g.sendChatMessage(connection, relay)
+			}
+		}
+	default:
+		return fmt.Errorf("%w: player %s sent to unknown channel %d", errInvalidChatMessage, playerID,
+			message.Channel)
+	}
+
+	return nil
+}
+
+// connectionByHeroName returns the connection of the player with the given hero name, names
+// are compared ignoring the case
+func (g *GameServer) connectionByHeroName(name string) ClientConnection {
+	for _, connection := range g.connections {
+		if playerState := connection.GetPlayerState(); playerState != nil &&
+			strings.EqualFold(playerState.HeroName, name) {
+			return connection
+		}
+	}
+
+	return nil
+}
+
+// sendChatNotice sends a notice about the last message of the client, the recipient is the
+// player it was sent to, if any
+func (g *GameServer) sendChatNotice(client ClientConnection, notice d2netpacket.ChatNotice, recipient string) {
+	packet, err := d2netpacket.CreateChatMessagePacket(d2netpacket.ChatMessagePacket{
+		Channel:   d2netpacket.ChatChannelServer,
+		PlayerID:  client.GetUniqueID(),
+		Recipient: recipient,
+		Notice:    notice,
+	})
+	if err != nil {
+		g.Errorf("ChatMessagePacket: %v", err)
+		return
+	}
+
+	g.sendChatMessage(client, packet)
+}
+
+func (g *GameServer) sendChatMessage(client ClientConnection, packet d2netpacket.NetPacket) {
+	if err := client.SendPacketToClient(packet); err != nil {
+		g.Errorf("GameServer: error sending ChatMessagePacket to client %s: %s", client.GetUniqueID(), err)
+	}
+}
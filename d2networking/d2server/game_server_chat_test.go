@@ -0,0 +1,152 @@
+package d2server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+)
+
+func TestChatLimiterAllow(t *testing.T) {
+	limiter := &chatLimiter{}
+	start := time.Unix(0, 0)
+
+	for idx := 0; idx < chatBurst; idx++ {
+		if !limiter.allow(start.Add(time.Duration(idx) * time.Second)) {
+			t.Fatalf("want message %d of the burst to be allowed", idx)
+		}
+	}
+
+	last := start.Add((chatBurst - 1) * time.Second)
+
+	if limiter.allow(last) {
+		t.Fatal("want the message after the burst to be dropped")
+	}
+
+	// the first message of the burst is out of the interval
+	if !limiter.allow(start.Add(chatBurstInterval)) {
+		t.Fatal("want a message to be allowed once the first message is out of the interval")
+	}
+
+	if limiter.allow(start.Add(chatBurstInterval)) {
+		t.Fatal("want the message after the burst to be dropped")
+	}
+
+	// dropped messages don't count
+	if len(limiter.sent) != chatBurst {
+		t.Fatalf("want %d messages to be counted, have %d", chatBurst, len(limiter.sent))
+	}
+
+	if !limiter.allow(last.Add(2 * chatBurstInterval)) {
+		t.Fatal("want a message to be allowed once the burst is out of the interval")
+	}
+
+	if len(limiter.sent) != 1 {
+		t.Fatalf("want the old messages to be forgotten, have %d", len(limiter.sent))
+	}
+}
+
+func sendChat(t *testing.T, server *GameServer, client *testClient, message d2netpacket.ChatMessagePacket) {
+	t.Helper()
+
+	message.PlayerID = client.id
+
+	packet, err := d2netpacket.CreateChatMessagePacket(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := server.handleChatMessage(client, packet); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// chatReceived returns the chat messages sent to the client, and forgets them
+func chatReceived(t *testing.T, client *testClient) []d2netpacket.ChatMessagePacket {
+	t.Helper()
+
+	messages := make([]d2netpacket.ChatMessagePacket, 0)
+
+	for _, packet := range client.packets {
+		if packet.PacketType != d2netpackettype.ChatMessage {
+			continue
+		}
+
+		message, err := d2netpacket.UnmarshalChatMessage(packet.PacketData)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		messages = append(messages, message)
+	}
+
+	client.packets = nil
+
+	return messages
+}
+
+func TestHandleChatMessageChannels(t *testing.T) {
+	sender := newTestClient("sender", 0, 0)
+	member := newTestClient("member", 0, 0)
+	other := newTestClient("other", 0, 0)
+	server := testServer(sender, member, other)
+
+	// not in a party yet
+	sendChat(t, server, sender, d2netpacket.ChatMessagePacket{Channel: d2netpacket.ChatChannelParty, Text: "hi"})
+
+	if received := chatReceived(t, sender); len(received) != 1 || received[0].Notice != d2netpacket.ChatNoticeNoParty {
+		t.Errorf("want a notice that the sender is not in a party, have %+v", received)
+	}
+
+	joinParty(t, server, "sender", "member")
+	sendChat(t, server, sender, d2netpacket.ChatMessagePacket{Channel: d2netpacket.ChatChannelParty, Text: "hi"})
+
+	for _, client := range []*testClient{sender, member} {
+		if received := chatReceived(t, client); len(received) != 1 || received[0].PlayerName != "sender" {
+			t.Errorf("%s: want the party message, have %+v", client.id, received)
+		}
+	}
+
+	if received := chatReceived(t, other); len(received) != 0 {
+		t.Errorf("want no party message outside of the party, have %+v", received)
+	}
+
+	sendChat(t, server, sender, d2netpacket.ChatMessagePacket{
+		Channel:   d2netpacket.ChatChannelWhisper,
+		Recipient: "nobody",
+		Text:      "hi",
+	})
+
+	if received := chatReceived(t, sender); len(received) != 1 ||
+		received[0].Notice != d2netpacket.ChatNoticeNoRecipient || received[0].Recipient != "nobody" {
+		t.Errorf("want a notice that the recipient is not in the game, have %+v", received)
+	}
+
+	sendChat(t, server, sender, d2netpacket.ChatMessagePacket{
+		Channel:   d2netpacket.ChatChannelWhisper,
+		Recipient: "OTHER",
+		Text:      "hi",
+	})
+
+	if received := chatReceived(t, other); len(received) != 1 || received[0].Channel != d2netpacket.ChatChannelWhisper {
+		t.Errorf("want the whisper, have %+v", received)
+	}
+
+	if received := chatReceived(t, member); len(received) != 0 {
+		t.Errorf("want no whisper for other players, have %+v", received)
+	}
+
+	// the messages so far fill the burst
+	sendChat(t, server, sender, d2netpacket.ChatMessagePacket{Channel: d2netpacket.ChatChannelAll, Text: "hi"})
+	chatReceived(t, sender)
+	sendChat(t, server, sender, d2netpacket.ChatMessagePacket{Channel: d2netpacket.ChatChannelAll, Text: "hi"})
+
+	if received := chatReceived(t, sender); len(received) != 1 || received[0].Notice != d2netpacket.ChatNoticeRateLimit {
+		t.Errorf("want a notice that the sender sends too fast, have %+v", received)
+	}
+
+	if received := chatReceived(t, other); len(received) != 1 {
+		t.Errorf("want only the messages within the burst, have %+v", received)
+	}
+}
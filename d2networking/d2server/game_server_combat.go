@@ -0,0 +1,489 @@
+package d2server
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2math/d2vector"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2combat"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2inventory"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2item"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapengine"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2quest"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2records"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket"
+)
+
+const (
+	// the distance in tiles from which a player can hit a monster
+	meleeAttackDistance = 3.0
+	// players can't attack faster than this
+	attackCooldown = 400 * time.Millisecond
+	// how often the monsters next to a player attack them
+	monsterTickInterval = time.Second
+	percent             = 100
+)
+
+var errInvalidAttack = errors.New("invalid attack")
+
+// monsterState is the life of a monster of the map, it is created when the monster is first
+// attacked, with the stats of the difficulty of the game
+type monsterState struct {
+	npc       *d2mapentity.NPC
+	mapEngine *d2mapengine.MapEngine
+	stats     *d2records.MonsterDifficultyStats
+	level     int
+	health    int
+	maxHealth int
+}
+
+// monster returns the state of the hostile monster with the given id, monsters killed before
+// are returned too
+func (g *GameServer) monster(monsterID string) *monsterState {
+	if monster, found := g.monsters[monsterID]; found {
+		return monster
+	}
+
+	for _, mapEngine := range g.mapEngines {
+		npc, ok := mapEngine.Entities()[monsterID].(*d2mapentity.NPC)
+		if !ok || !npc.IsHostile() {
+			continue
+		}
+
+		stats := npc.MonsterStats().Stats(g.difficulty)
+		monster := &monsterState{
+			npc:       npc,
+			mapEngine: mapEngine,
+			stats:     stats,
+			level:     stats.Level,
+		}
+
+		// the monsters of nightmare and hell have the level of the area they are in
+		levelDetails := g.levelDetails(mapEngine, npc.GetPosition())
+		if levelDetails != nil && g.difficulty != d2enum.DifficultyNormal {
+			if level := levelDetails.MonsterLevel(g.difficulty); level > 0 {
+				monster.level = level
+			}
+		}
+
+		monster.maxHealth = stats.MinHP
+		if stats.MaxHP > stats.MinHP {
+			monster.maxHealth += g.combatRand.Intn(stats.MaxHP - stats.MinHP + 1)
+		}
+
+		if monster.maxHealth < 1 {
+			monster.maxHealth = 1
+		}
+
+		monster.health = monster.maxHealth
+		g.monsters[monsterID] = monster
+
+		return monster
+	}
+
+	return nil
+}
+
+// levelDetails returns the levels.txt record of the area at the position
+func (g *GameServer) levelDetails(mapEngine *d2mapengine.MapEngine, position d2vector.Position) *d2records.LevelDetailRecord {
+	tilePosition := position.Tile()
+
+	tile := mapEngine.TileAt(int(tilePosition.X()), int(tilePosition.Y()))
+	if tile == nil {
+		return nil
+	}
+
+	return g.asset.Records.Level.Details[int(tile.RegionType)]
+}
+
+// handleAttack rolls the attack of the client's player on a monster, and tells all clients
+// about the hit. The player must stand next to the monster.
+func (g *GameServer) handleAttack(client ClientConnection, packet d2netpacket.NetPacket) error {
+	attackPacket, err := d2netpacket.UnmarshalAttack(packet.PacketData)
+	if err != nil {
+		return err
+	}
+
+	playerID := client.GetUniqueID()
+	if attackPacket.PlayerID != playerID {
+		return fmt.Errorf("%w: player %s can't attack for %s", errInvalidAttack, playerID, attackPacket.PlayerID)
+	}
+
+	playerState := client.GetPlayerState()
+	if playerState.IsDead || playerState.Stats == nil || playerState.Stats.Health <= 0 {
+		return fmt.Errorf("%w: player %s is dead", errInvalidAttack, playerID)
+	}
+
+	g.combatMutex.Lock()
+	defer g.combatMutex.Unlock()
+
+	now := time.Now()
+	if now.Before(g.nextAttacks[playerID]) {
+		return nil
+	}
+
+	if target, found := g.connections[attackPacket.TargetID]; found {
+		return g.attackPlayer(client, target, now)
+	}
+
+	monster := g.monster(attackPacket.TargetID)
+	if monster == nil || monster.health <= 0 {
+		return fmt.Errorf("%w: unknown monster %s", errInvalidAttack, attackPacket.TargetID)
+	}
+
+	position := monster.npc.GetPosition()
+	world := position.World()
+
+	if dx, dy := world.X()-playerState.X, world.Y()-playerState.Y; dx*dx+dy*dy >
+		meleeAttackDistance*meleeAttackDistance {
+		return fmt.Errorf("%w: monster %s is too far from player %s", errInvalidAttack, attackPacket.TargetID, playerID)
+	}
+
+	g.nextAttacks[playerID] = now.Add(attackCooldown)
+
+	hit := d2netpacket.HitPacket{AttackerID: playerID, TargetID: attackPacket.TargetID}
+	stats := playerState.Stats
+
+	hitChance := d2combat.HitChance(d2combat.AttackRating(stats.Dexterity), stats.Level, monster.stats.ArmorClass,
+		monster.level)
+	if !d2combat.Roll(g.combatRand, hitChance) {
+		hit.Missed = true
+	} else {
+		minDamage, maxDamage := g.weaponDamage(&playerState.Equipment)
+		damage := d2combat.StrengthBonus(d2combat.RollDamage(g.combatRand, minDamage, maxDamage), stats.Strength)
+		hit.Damage = d2combat.ApplyResistance(damage, monster.stats.ResistancePhysical)
+
+		g.wearEquipment(client, d2item.WearEventAttack)
+	}
+
+	monster.health -= hit.Damage
+	if monster.health < 0 {
+		monster.health = 0
+	}
+
+	hit.Health, hit.MaxHealth = monster.health, monster.maxHealth
+	hit.Killed = monster.health == 0
+
+	hitPacket, err := d2netpacket.CreateHitPacket(hit)
+	if err != nil {
+		return err
+	}
+
+	g.sendPacketToClients(hitPacket)
+
+	if hit.Killed {
+		g.killMonster(client, monster)
+	}
+
+	return nil
+}
+
+// attackPlayer rolls the attack of the client's player on another player, players can only
+// damage the players they are hostile to. The target dies when its life reaches zero.
+func (g *GameServer) attackPlayer(client, target ClientConnection, now time.Time) error {
+	playerID, targetID := client.GetUniqueID(), target.GetUniqueID()
+	if !g.CanDamagePlayer(playerID, targetID) {
+		return fmt.Errorf("%w: player %s is not hostile to %s", errInvalidAttack, playerID, targetID)
+	}
+
+	playerState, targetState := client.GetPlayerState(), target.GetPlayerState()
+	if targetState.IsDead || targetState.Stats == nil || targetState.Stats.Health <= 0 {
+		return fmt.Errorf("%w: player %s is dead", errInvalidAttack, targetID)
+	}
+
+	if dx, dy := targetState.X-playerState.X, targetState.Y-playerState.Y; dx*dx+dy*dy >
+		meleeAttackDistance*meleeAttackDistance {
+		return fmt.Errorf("%w: player %s is too far from player %s", errInvalidAttack, targetID, playerID)
+	}
+
+	g.nextAttacks[playerID] = now.Add(attackCooldown)
+
+	hit := d2netpacket.HitPacket{AttackerID: playerID, TargetID: targetID}
+	stats, targetStats := playerState.Stats, targetState.Stats
+	armor, block := g.armorDefense(&targetState.Equipment)
+
+	hitChance := d2combat.HitChance(d2combat.AttackRating(stats.Dexterity), stats.Level,
+		d2combat.Defense(targetStats.Dexterity, armor), targetStats.Level)
+
+	switch {
+	case !d2combat.Roll(g.combatRand, hitChance):
+		hit.Missed = true
+	case d2combat.Roll(g.combatRand, d2combat.BlockChance(block, targetStats.Dexterity, targetStats.Level)):
+		hit.Missed = true
+
+		g.wearEquipment(target, d2item.WearEventBlock)
+	default:
+		minDamage, maxDamage := g.weaponDamage(&playerState.Equipment)
+		hit.Damage = d2combat.StrengthBonus(d2combat.RollDamage(g.combatRand, minDamage, maxDamage), stats.Strength)
+
+		g.wearEquipment(client, d2item.WearEventAttack)
+		g.wearEquipment(target, d2item.WearEventStruck)
+	}
+
+	targetStats.Health -= hit.Damage
+	if targetStats.Health < 0 {
+		targetStats.Health = 0
+	}
+
+	hit.Health, hit.MaxHealth = targetStats.Health, targetStats.MaxHealth
+	hit.Killed = targetStats.Health == 0
+
+	hitPacket, err := d2netpacket.CreateHitPacket(hit)
+	if err != nil {
+		return err
+	}
+
+	g.sendPacketToClients(hitPacket)
+
+	if hit.Killed {
+		return g.killPlayer(target)
+	}
+
+	return nil
+}
+
+// armorDefense returns the defense of the equipped armor and the chance to block of the
+// equipped shield, broken items don't count
+func (g *GameServer) armorDefense(equipment *d2inventory.CharacterEquipment) (defense, block int) {
+	for _, armor := range []*d2inventory.InventoryItemArmor{equipment.Head, equipment.Torso, equipment.Legs,
+		equipment.RightArm, equipment.LeftArm, equipment.Shield, equipment.Belt} {
+		if armor == nil || armor.ItemCode == "" || armor.IsBroken() {
+			continue
+		}
+
+		record := g.asset.Records.Item.Armors[armor.ItemCode]
+		if record == nil {
+			continue
+		}
+
+		defense += record.MinAC
+
+		if armor == equipment.Shield {
+			block = record.Block
+		}
+	}
+
+	return defense, block
+}
+
+// weaponDamage returns the damage range of the equipped weapon, both zero for unarmed attacks
+func (g *GameServer) weaponDamage(equipment *d2inventory.CharacterEquipment) (min, max int) {
+	for _, weapon := range []*d2inventory.InventoryItemWeapon{equipment.RightHand, equipment.LeftHand} {
+		if weapon == nil || weapon.ItemCode == "" {
+			continue
+		}
+
+		record := g.asset.Records.Item.Weapons[weapon.ItemCode]
+		if record == nil {
+			continue
+		}
+
+		if record.UsesTwoHands {
+			return record.Min2HandDamage, record.Max2HandDamage
+		}
+
+		return record.MinDamage, record.MaxDamage
+	}
+
+	return 0, 0
+}
+
+// monsterKillTriggers returns the quest triggers of killing the monster, the quests of
+// superunique monsters are keyed by their superuniques.txt key, the others by the monstats key
+func monsterKillTriggers(npc *d2mapentity.NPC) []d2quest.Trigger {
+	triggers := make([]d2quest.Trigger, 0)
+
+	if stats := npc.MonsterStats(); stats != nil {
+		triggers = append(triggers, d2quest.KillTrigger(stats.Key))
+	}
+
+	if key := npc.SuperUnique(); key != "" {
+		triggers = append(triggers, d2quest.KillTrigger(key))
+	}
+
+	return triggers
+}
+
+// killMonster removes the monster killed by the client's player from the map, and drops its
+// treasure
+func (g *GameServer) killMonster(client ClientConnection, monster *monsterState) {
+	monster.mapEngine.RemoveEntity(monster.npc)
+
+	key := monster.npc.MonsterStats().Key
+	g.Debugf("Player %s killed monster %s", client.GetUniqueID(), key)
+	experience := g.monsterExperience(monster)
+	g.GiveExperience(client.GetUniqueID(), experience)
+	g.giveHirelingExperience(client, experience)
+	for _, trigger := range monsterKillTriggers(monster.npc) {
+		g.advanceQuests(client, trigger)
+	}
+
+	position := monster.npc.GetPosition()
+	if level := g.levelDetails(monster.mapEngine, position); level != nil && g.isLevelCleared(monster.mapEngine, level.ID) {
+		g.advanceQuests(client, d2quest.ClearLevelTrigger(level.ID))
+	}
+
+	name := monster.stats.TreasureClass(d2records.MonsterTreasureClassRegular)
+	if name == "" {
+		return
+	}
+
+	record := g.asset.Records.Item.Treasure.Normal.Upgrade(name, monster.level)
+	if record == nil {
+		g.Warningf("GameServer: unknown treasure class %s for monster %s", name, key)
+		return
+	}
+
+	tile := position.Tile()
+
+	g.spawnTreasure(record, int(tile.X()), int(tile.Y()))
+}
+
+// monstersAttack makes every monster next to a living player attack the closest one
+func (g *GameServer) monstersAttack() {
+	g.combatMutex.Lock()
+	defer g.combatMutex.Unlock()
+
+	for _, mapEngine := range g.mapEngines {
+		for id, entity := range mapEngine.Entities() {
+			npc, ok := entity.(*d2mapentity.NPC)
+			if !ok || !npc.IsHostile() {
+				continue
+			}
+
+			target := g.closestPlayer(npc.GetPosition())
+			if target == nil {
+				continue
+			}
+
+			monster := g.monster(id)
+			if monster == nil || monster.health <= 0 {
+				continue
+			}
+
+			var err error
+
+			// the hireling fights next to its owner, the monster attacks either of them
+			if hireling := target.GetPlayerState().Hireling; hireling != nil && !hireling.IsDead &&
+				g.combatRand.Intn(2) == 0 { // nolint:gomnd // one in two
+				err = g.monsterAttackHireling(monster, target)
+			} else {
+				err = g.monsterAttack(monster, target)
+			}
+
+			if err != nil {
+				g.Errorf("GameServer: error in the attack of monster %s: %v", id, err)
+			}
+		}
+	}
+}
+
+// closestPlayer returns the closest living player within melee distance of the position
+func (g *GameServer) closestPlayer(position d2vector.Position) ClientConnection {
+	var (
+		closest  ClientConnection
+		distance = meleeAttackDistance * meleeAttackDistance
+	)
+
+	world := position.World()
+
+	for _, client := range g.connections {
+		playerState := client.GetPlayerState()
+		if playerState == nil || playerState.IsDead || playerState.Stats == nil || playerState.Stats.Health <= 0 {
+			continue
+		}
+
+		if dx, dy := world.X()-playerState.X, world.Y()-playerState.Y; dx*dx+dy*dy <= distance {
+			closest, distance = client, dx*dx+dy*dy
+		}
+	}
+
+	return closest
+}
+
+// monsterAttack rolls the attack of the monster on the client's player, with the attack of
+// the monster against the defense of the player. The player dies when their life reaches zero.
+func (g *GameServer) monsterAttack(monster *monsterState, target ClientConnection) error {
+	targetState := target.GetPlayerState()
+	stats := targetState.Stats
+	armor, block := g.armorDefense(&targetState.Equipment)
+
+	hit := d2netpacket.HitPacket{AttackerID: monster.npc.ID(), TargetID: target.GetUniqueID()}
+
+	hitChance := d2combat.HitChance(monster.stats.AttackRating, monster.level,
+		d2combat.Defense(stats.Dexterity, armor), stats.Level)
+
+	switch {
+	case !d2combat.Roll(g.combatRand, hitChance):
+		hit.Missed = true
+	case d2combat.Roll(g.combatRand, d2combat.BlockChance(block, stats.Dexterity, stats.Level)):
+		hit.Missed = true
+
+		g.wearEquipment(target, d2item.WearEventBlock)
+	default:
+		hit.Damage = d2combat.RollDamage(g.combatRand, monster.stats.DamageMin, monster.stats.DamageMax)
+
+		g.wearEquipment(target, d2item.WearEventStruck)
+	}
+
+	stats.Health -= hit.Damage
+	if stats.Health < 0 {
+		stats.Health = 0
+	}
+
+	hit.Health, hit.MaxHealth = stats.Health, stats.MaxHealth
+	hit.Killed = stats.Health == 0
+
+	hitPacket, err := d2netpacket.CreateHitPacket(hit)
+	if err != nil {
+		return err
+	}
+
+	g.sendPacketToClients(hitPacket)
+
+	if hit.Killed {
+		return g.killPlayer(target)
+	}
+
+	return nil
+}
+
+// monsterExperience returns the experience given for killing the monster, the experience of
+// monstats.txt is a percentage of the experience of the monster level
+func (g *GameServer) monsterExperience(monster *monsterState) int {
+	levelRecord := g.asset.Records.Monster.Levels[monster.level]
+	if levelRecord == nil {
+		return monster.stats.Experience
+	}
+
+	return levelRecord.Experience(g.difficulty) * monster.stats.Experience / percent
+}
+
+// sendMonstersToClient sends the monsters killed before the client joined to the client
+func (g *GameServer) sendMonstersToClient(client ClientConnection) {
+	g.combatMutex.Lock()
+	defer g.combatMutex.Unlock()
+
+	for monsterID, monster := range g.monsters {
+		if monster.health > 0 {
+			continue
+		}
+
+		packet, err := d2netpacket.CreateHitPacket(d2netpacket.HitPacket{
+			TargetID:  monsterID,
+			MaxHealth: monster.maxHealth,
+			Killed:    true,
+		})
+		if err != nil {
+			g.Errorf("HitPacket: %v", err)
+			continue
+		}
+
+		if err := client.SendPacketToClient(packet); err != nil {
+			g.Errorf("GameServer: error sending HitPacket to client %s: %s", client.GetUniqueID(), err)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package d2server
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket"
+)
+
+func TestHandleAttackPlayerInvalid(t *testing.T) {
+	attacker := newTestClient("attacker", 10, 20)
+	friend := newTestClient("friend", 10, 21)
+	enemy := newTestClient("enemy", 10, 21)
+	farEnemy := newTestClient("far", 50, 20)
+	deadEnemy := newTestClient("dead", 10, 21)
+	server := testServer(attacker, friend, enemy, farEnemy, deadEnemy)
+
+	for _, client := range []*testClient{attacker, friend, enemy, farEnemy, deadEnemy} {
+		client.playerState.Stats.Health = 10
+	}
+
+	deadEnemy.playerState.Stats.Health = 0
+
+	for _, target := range []string{"enemy", "far", "dead"} {
+		if err := server.parties.DeclareHostile("attacker", target); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		target string
+	}{
+		{"not hostile", "friend"},
+		{"too far", "far"},
+		{"dead", "dead"},
+		{"self", "attacker"},
+	}
+
+	for _, test := range tests {
+		packet, err := d2netpacket.CreateAttackPacket(attacker.id, test.target)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := server.handleAttack(attacker, packet); !errors.Is(err, errInvalidAttack) {
+			t.Errorf("%s: want %v, have %v", test.name, errInvalidAttack, err)
+		}
+	}
+
+	if len(attacker.packets) != 0 {
+		t.Errorf("want no hits to be sent, have %d packets", len(attacker.packets))
+	}
+}
@@ -0,0 +1,177 @@
+package d2server
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2hero"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket"
+)
+
+var (
+	errInvalidDeath  = errors.New("invalid player death")
+	errInvalidCorpse = errors.New("invalid corpse")
+	errHeroIsDead    = errors.New("hardcore hero is dead")
+)
+
+// handlePlayerDeath kills the client's player when the client asks for it. The server decides
+// the deaths by damage itself, see killPlayer, but a client may always kill its own player:
+// the penalties only ever cost that player, so the request is trusted.
+func (g *GameServer) handlePlayerDeath(client ClientConnection, packet d2netpacket.NetPacket) error {
+	deathPacket, err := d2netpacket.UnmarshalPlayerDeath(packet.PacketData)
+	if err != nil {
+		return err
+	}
+
+	playerID := client.GetUniqueID()
+	if deathPacket.PlayerID != playerID {
+		return fmt.Errorf("%w: player %s can't kill %s", errInvalidDeath, playerID, deathPacket.PlayerID)
+	}
+
+	playerState := client.GetPlayerState()
+	if playerState.IsDead {
+		return fmt.Errorf("%w: player %s", errHeroIsDead, playerID)
+	}
+
+	return g.killPlayer(client)
+}
+
+// killPlayer kills the client's player. The player loses experience and gold for the
+// difficulty of the game, and leaves a corpse with the equipped items. Players respawn in the
+// town of their act, hardcore players are dead for good.
+func (g *GameServer) killPlayer(client ClientConnection) error {
+	playerID := client.GetUniqueID()
+	playerState := client.GetPlayerState()
+	kill := d2netpacket.KillPlayerPacket{PlayerID: playerID}
+
+	if stats := playerState.Stats; stats != nil {
+		kill.ExperienceLoss = g.heroStateFactory.DeathExperienceLoss(playerState.HeroType, stats, g.difficulty)
+		kill.GoldLoss = d2hero.DeathGoldLoss(stats.Level, playerState.Gold)
+		stats.Experience -= kill.ExperienceLoss
+	}
+
+	playerState.Gold -= kill.GoldLoss
+	playerState.Difficulty = g.difficulty
+	corpse := playerState.Die(uuid.New().String())
+	kill.IsDead = playerState.IsDead
+
+	g.statesMutex.Lock()
+	if list, found := g.states[playerID]; found {
+		g.sendStateRemovals(playerID, list.OnDeath(true))
+	}
+	g.statesMutex.Unlock()
+
+	if !playerState.IsDead {
+		x, y, err := g.townStartPosition(actTown(playerState.Act))
+		if err != nil {
+			x, y = g.mapEngines[0].GetStartPosition()
+		}
+
+		playerState.X, playerState.Y = x, y
+		kill.RespawnX, kill.RespawnY = x, y
+
+		if stats := playerState.Stats; stats != nil {
+			stats.Health = stats.MaxHealth
+			stats.Mana = stats.MaxMana
+		}
+	}
+
+	if err := g.heroStateFactory.Save(playerState); err != nil {
+		g.Errorf("GameServer: error saving Player: %s", err)
+	}
+
+	killPacket, err := d2netpacket.CreateKillPlayerPacket(kill)
+	if err != nil {
+		return err
+	}
+
+	g.sendPacketToClients(killPacket)
+	g.sendCorpseUpdate(playerID, playerState, corpse, d2netpacket.CorpseActionAdd)
+	g.updatePlayerLevel(client)
+
+	return nil
+}
+
+// handleRetrieveCorpse moves the items of one of the corpses of the client's player back to
+// the player, when the player stands next to it
+func (g *GameServer) handleRetrieveCorpse(client ClientConnection, packet d2netpacket.NetPacket) error {
+	retrievePacket, err := d2netpacket.UnmarshalRetrieveCorpse(packet.PacketData)
+	if err != nil {
+		return err
+	}
+
+	playerID := client.GetUniqueID()
+	if retrievePacket.PlayerID != playerID {
+		return fmt.Errorf("%w: player %s can't retrieve the corpse of %s", errInvalidCorpse, playerID,
+			retrievePacket.PlayerID)
+	}
+
+	playerState := client.GetPlayerState()
+
+	corpse := playerState.Corpse(retrievePacket.CorpseID)
+	if corpse == nil || corpse.Difficulty != g.difficulty {
+		return fmt.Errorf("%w: player %s has no corpse %s", errInvalidCorpse, playerID, retrievePacket.CorpseID)
+	}
+
+	if dx, dy := corpse.X-playerState.X, corpse.Y-playerState.Y; dx*dx+dy*dy >
+		objectOperateDistance*objectOperateDistance {
+		return fmt.Errorf("%w: corpse %s is too far from player %s", errInvalidCorpse, corpse.ID, playerID)
+	}
+
+	playerState.RetrieveCorpse(corpse)
+
+	if err := g.heroStateFactory.Save(playerState); err != nil {
+		g.Errorf("GameServer: error saving Player: %s", err)
+	}
+
+	g.sendCorpseUpdate(playerID, playerState, corpse, d2netpacket.CorpseActionRetrieve)
+
+	return nil
+}
+
+// sendCorpsesToClient sends the corpses the given player left on the difficulty of the game
+// to the client
+func (g *GameServer) sendCorpsesToClient(client ClientConnection, playerID string, playerState *d2hero.HeroState) {
+	for _, corpse := range playerState.Corpses {
+		if corpse.Difficulty != g.difficulty {
+			continue
+		}
+
+		add, err := d2netpacket.CreateUpdateCorpsePacket(corpseUpdate(playerID, playerState, corpse,
+			d2netpacket.CorpseActionAdd))
+		if err != nil {
+			g.Errorf("UpdateCorpsePacket: %v", err)
+			continue
+		}
+
+		if err := client.SendPacketToClient(add); err != nil {
+			g.Errorf("GameServer: error sending UpdateCorpsePacket to client %s: %s", client.GetUniqueID(), err)
+		}
+	}
+}
+
+func (g *GameServer) sendCorpseUpdate(playerID string, playerState *d2hero.HeroState, corpse *d2hero.Corpse,
+	action d2netpacket.CorpseAction) {
+	update, err := d2netpacket.CreateUpdateCorpsePacket(corpseUpdate(playerID, playerState, corpse, action))
+	if err != nil {
+		g.Errorf("UpdateCorpsePacket: %v", err)
+		return
+	}
+
+	g.sendPacketToClients(update)
+}
+
+func corpseUpdate(playerID string, playerState *d2hero.HeroState, corpse *d2hero.Corpse,
+	action d2netpacket.CorpseAction) d2netpacket.UpdateCorpsePacket {
+	return d2netpacket.UpdateCorpsePacket{
+		Action:    action,
+		OwnerID:   playerID,
+		OwnerName: playerState.HeroName,
+		HeroType:  playerState.HeroType,
+		Corpse:    corpse,
+		Equipment: &playerState.Equipment,
+		Belt:      playerState.Belt,
+	}
+}
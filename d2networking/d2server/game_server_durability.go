@@ -0,0 +1,92 @@
+package d2server
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2item"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket"
+)
+
+var errInvalidRepair = errors.New("invalid repair")
+
+// wearEquipment rolls for the loss of durability of the equipment of the client's player for
+// the combat event, and tells all clients when the equipment lost durability
+func (g *GameServer) wearEquipment(client ClientConnection, event d2item.WearEvent) {
+	playerState := client.GetPlayerState()
+
+	worn, broken := playerState.Equipment.ApplyWearEvent(event, g.combatRand)
+	if len(worn) < 1 {
+		return
+	}
+
+	for _, code := range broken {
+		g.Debugf("Item %s of player %s broke", code, client.GetUniqueID())
+	}
+
+	g.sendEquipment(client)
+}
+
+// sendEquipment sends the equipment and the gold of the client's player to all clients
+func (g *GameServer) sendEquipment(client ClientConnection) {
+	playerState := client.GetPlayerState()
+
+	packet, err := d2netpacket.CreateUpdateEquipmentPacket(client.GetUniqueID(), playerState.Equipment,
+		playerState.Gold)
+	if err != nil {
+		g.Errorf("UpdateEquipmentPacket: %v", err)
+		return
+	}
+
+	g.sendPacketToClients(packet)
+}
+
+// handleRepairItems repairs the equipment of the client's player at a vendor next to the player,
+// for the repair cost of the vendor
+func (g *GameServer) handleRepairItems(client ClientConnection, packet d2netpacket.NetPacket) error {
+	repairPacket, err := d2netpacket.UnmarshalRepairItems(packet.PacketData)
+	if err != nil {
+		return err
+	}
+
+	playerID := client.GetUniqueID()
+	playerState := client.GetPlayerState()
+
+	if repairPacket.PlayerID != playerID || playerState == nil || playerState.IsDead {
+		return fmt.Errorf("%w: player %s", errInvalidRepair, repairPacket.PlayerID)
+	}
+
+	multiplier := g.repairMultiplier(repairPacket.NPCKey)
+	if multiplier <= 0 || !g.nearNPC(client, repairPacket.NPCKey) {
+		return fmt.Errorf("%w: %s can't repair the items of player %s", errInvalidRepair, repairPacket.NPCKey, playerID)
+	}
+
+	cost := g.heroStateFactory.RepairAllCost(&playerState.Equipment, multiplier)
+	if cost < 1 {
+		return nil
+	}
+
+	if playerState.Gold < cost {
+		return fmt.Errorf("%w: player %s has %d gold, needs %d", errNotEnoughGold, playerID, playerState.Gold, cost)
+	}
+
+	playerState.Equipment.RepairAll()
+	playerState.Gold -= cost
+
+	g.sendEquipment(client)
+
+	return nil
+}
+
+// repairMultiplier returns the repair multiplier of the vendor of npc.txt with the given
+// monstats.txt id, zero for npcs which don't repair
+func (g *GameServer) repairMultiplier(npcKey string) float64 {
+	for name, record := range g.asset.Records.NPCs {
+		if strings.EqualFold(name, npcKey) && record.Multipliers != nil {
+			return record.Multipliers.Repair
+		}
+	}
+
+	return 0
+}
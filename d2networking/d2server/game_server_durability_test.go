@@ -0,0 +1,200 @@
+package d2server
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2inventory"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2item"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+)
+
+func TestWearEquipment(t *testing.T) {
+	player := newTestClient("player", 10, 20)
+	other := newTestClient("other", 10, 20)
+	server := testServer(player, other)
+
+	player.playerState.Equipment.Torso = &d2inventory.InventoryItemArmor{ItemCode: "qui",
+		ItemDurability: d2item.NewItemDurability(50, false, false)}
+
+	// without a shield nothing wears from blocking
+	for idx := 0; idx < 100; idx++ {
+		server.wearEquipment(player, d2item.WearEventBlock)
+	}
+
+	if len(other.packets) != 0 {
+		t.Fatalf("want no updates without a shield, have %d packets", len(other.packets))
+	}
+
+	for idx := 0; idx < 100; idx++ {
+		server.wearEquipment(player, d2item.WearEventStruck)
+	}
+
+	lost := 50 - player.playerState.Equipment.Torso.Current
+	if lost == 0 || len(other.packets) != lost {
+		t.Fatalf("want an update for each of the %d points of durability lost, have %d packets", lost, len(other.packets))
+	}
+
+	for _, packet := range other.packets {
+		if packet.PacketType != d2netpackettype.UpdateEquipment {
+			t.Errorf("want UpdateEquipment packets, have %s", packet.PacketType)
+		}
+	}
+}
+
+func TestHandleRepairItemsInvalid(t *testing.T) {
+	player := newTestClient("player", 10, 20)
+	dead := newTestClient("dead", 10, 20)
+	server := testServer(player, dead)
+
+	dead.playerState.IsDead = true
+
+	tests := []struct {
+		name     string
+		client   *testClient
+		playerID string
+	}{
+		{"another player", player, "dead"},
+		{"dead", dead, "dead"},
+	}
+
+	for _, test := range tests {
+		packet, err := d2netpacket.CreateRepairItemsPacket(test.playerID, "charsi")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := server.handleRepairItems(test.client, packet); !errors.Is(err, errInvalidRepair) {
+			t.Errorf("%s: want %v, have %v", test.name, errInvalidRepair, err)
+		}
+	}
+}
+
+func TestWearEquipmentBreaks(t *testing.T) {
+	player := newTestClient("player", 10, 20)
+	other := newTestClient("other", 10, 20)
+	server := testServer(player, other)
+
+	torso := &d2inventory.InventoryItemArmor{ItemCode: "qui", ItemDurability: d2item.NewItemDurability(2, false, false)}
+	player.playerState.Equipment.Torso = torso
+
+	for idx := 0; idx < 1000 && !torso.IsBroken(); idx++ {
+		server.wearEquipment(player, d2item.WearEventStruck)
+	}
+
+	if !torso.IsBroken() || torso.Current != 0 {
+		t.Fatalf("want the armor broken, have %+v", torso.ItemDurability)
+	}
+
+	packets := len(other.packets)
+
+	// a broken item doesn't wear any further
+	for idx := 0; idx < 100; idx++ {
+		server.wearEquipment(player, d2item.WearEventStruck)
+	}
+
+	if len(other.packets) != packets || torso.Current != 0 {
+		t.Errorf("want no updates for a broken item, have %d packets and %+v", len(other.packets)-packets,
+			torso.ItemDurability)
+	}
+
+	update, err := d2netpacket.UnmarshalUpdateEquipment(other.packets[packets-1].PacketData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if update.Equipment.Torso == nil || !update.Equipment.Torso.IsBroken() {
+		t.Errorf("want the broken armor sent to the clients, have %+v", update.Equipment.Torso)
+	}
+}
+
+func TestHandleRepairItems(t *testing.T) {
+	player := newTestClient("player", 10, 20)
+	other := newTestClient("other", 10, 20)
+	server := townTestServer(t, testVendor, 15, 20, player, other)
+
+	helm, _ := server.heroStateFactory.GetArmorItemByCode("cap")
+	sword, _ := server.heroStateFactory.GetWeaponItemByCode("ssd")
+	helm.Wear(6)
+	sword.Wear(6)
+
+	player.playerState.Equipment.Head = helm
+	player.playerState.Equipment.RightHand = sword
+	player.playerState.Gold = 80
+
+	packet, err := d2netpacket.CreateRepairItemsPacket(player.id, "charsi")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := server.handleRepairItems(player, packet); err != nil {
+		t.Fatal(err)
+	}
+
+	// half of the 100 gold the lost durability is worth
+	if player.playerState.Gold != 30 {
+		t.Errorf("want 30 gold left, have %d", player.playerState.Gold)
+	}
+
+	if helm.Current != helm.Max || sword.Current != sword.Max {
+		t.Errorf("want the durability restored, have %+v and %+v", helm.ItemDurability, sword.ItemDurability)
+	}
+
+	if len(other.packets) != 1 || other.packets[0].PacketType != d2netpackettype.UpdateEquipment {
+		t.Fatalf("want an UpdateEquipment packet, have %d packets", len(other.packets))
+	}
+
+	update, err := d2netpacket.UnmarshalUpdateEquipment(other.packets[0].PacketData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if update.Gold != 30 || update.Equipment.Head == nil || update.Equipment.Head.Current != helm.Max {
+		t.Errorf("want the repaired equipment and the gold left sent, have %+v", update)
+	}
+
+	// nothing to repair, nothing to pay
+	if err := server.handleRepairItems(player, packet); err != nil || player.playerState.Gold != 30 {
+		t.Errorf("want a free repair of the repaired items, have %v and %d gold", err, player.playerState.Gold)
+	}
+}
+
+func TestHandleRepairItemsRejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		npcX    float64
+		npcKey  string
+		gold    int
+		wantErr error
+	}{
+		{"not enough gold", 15, "charsi", 24, errNotEnoughGold},
+		{"far from the vendor", 500, "charsi", 100, errInvalidRepair},
+		{"not a vendor", 15, "kashya", 100, errInvalidRepair},
+	}
+
+	for _, test := range tests {
+		player := newTestClient("player", 10, 20)
+		server := townTestServer(t, testVendor, test.npcX, 20, player)
+
+		helm, _ := server.heroStateFactory.GetArmorItemByCode("cap")
+		helm.Wear(6)
+
+		player.playerState.Equipment.Head = helm
+		player.playerState.Gold = test.gold
+
+		packet, err := d2netpacket.CreateRepairItemsPacket(player.id, test.npcKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := server.handleRepairItems(player, packet); !errors.Is(err, test.wantErr) {
+			t.Errorf("%s: want %v, have %v", test.name, test.wantErr, err)
+		}
+
+		if player.playerState.Gold != test.gold || helm.Current == helm.Max || len(player.packets) != 0 {
+			t.Errorf("%s: want nothing repaired, have %d gold and %+v", test.name, player.playerState.Gold,
+				helm.ItemDurability)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package d2server
+
+import (
+	"fmt"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2combat"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2hero"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2inventory"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket"
+)
+
+// The server doesn't know where the hirelings walk, they follow their owners closely, so
+// hirelings fight from the position of their owner.
+
+// equipHireling swaps the item of the slot between the client's player and their hireling,
+// and sends the resulting hireling to all clients
+func (g *GameServer) equipHireling(client ClientConnection, slot d2hero.HirelingSlot) error {
+	playerState := client.GetPlayerState()
+
+	hireling := playerState.Hireling
+	if hireling == nil || hireling.IsDead {
+		return fmt.Errorf("%w: player %s has no living hireling", errInvalidHireling, client.GetUniqueID())
+	}
+
+	if err := g.heroStateFactory.SwapHirelingEquipment(hireling, &playerState.Equipment, slot); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidHireling, err)
+	}
+
+	equipped, err := d2netpacket.CreateEquipHirelingPacket(client.GetUniqueID(), slot, hireling, playerState.Gold)
+	if err != nil {
+		return err
+	}
+
+	g.sendPacketToClients(equipped)
+
+	return nil
+}
+
+// giveHirelingExperience gives the experience of a kill to the hireling of the client's player
+func (g *GameServer) giveHirelingExperience(client ClientConnection, experience int) {
+	playerState := client.GetPlayerState()
+	if playerState.Hireling == nil || playerState.Hireling.IsDead || playerState.Stats == nil {
+		return
+	}
+
+	g.heroStateFactory.GiveHirelingExperience(playerState.Hireling, experience, playerState.Stats.Level)
+
+	packet, err := d2netpacket.CreateUpdateHirelingPacket(client.GetUniqueID(), d2netpacket.HirelingActionUpdate,
+		playerState.Hireling, playerState.Gold)
+	if err != nil {
+		g.Errorf("UpdateHirelingPacket: %v", err)
+		return
+	}
+
+	g.sendPacketToClients(packet)
+}
+
+// hirelingsAttack makes every living hireling attack the closest monster next to its owner
+func (g *GameServer) hirelingsAttack() {
+	g.combatMutex.Lock()
+	defer g.combatMutex.Unlock()
+
+	for _, client := range g.connections {
+		playerState := client.GetPlayerState()
+		if playerState == nil || playerState.IsDead || playerState.Hireling == nil || playerState.Hireling.IsDead {
+			continue
+		}
+
+		monster := g.closestMonster(playerState.X, playerState.Y)
+		if monster == nil {
+			continue
+		}
+
+		if err := g.hirelingAttack(client, monster); err != nil {
+			g.Errorf("GameServer: error in the attack of the hireling of player %s: %v", client.GetUniqueID(), err)
+		}
+	}
+}
+
+// closestMonster returns the closest living monster within melee distance of the position in tiles
+func (g *GameServer) closestMonster(x, y float64) *monsterState {
+	var (
+		closest  *monsterState
+		distance = meleeAttackDistance * meleeAttackDistance
+	)
+
+	for _, mapEngine := range g.mapEngines {
+		for id, entity := range mapEngine.Entities() {
+			npc, ok := entity.(*d2mapentity.NPC)
+			if !ok || !npc.IsHostile() {
+				continue
+			}
+
+			position := npc.GetPosition()
+			world := position.World()
+
+			dx, dy := world.X()-x, world.Y()-y
+			if dx*dx+dy*dy > distance {
+				continue
+			}
+
+			if monster := g.monster(id); monster != nil && monster.health > 0 {
+				closest, distance = monster, dx*dx+dy*dy
+			}
+		}
+	}
+
+	return closest
+}
+
+// hirelingAttack rolls the attack of the hireling of the client's player on the monster, with
+// a skill chosen by the chances of hireling.txt. The kills of a hireling count for its owner.
+func (g *GameServer) hirelingAttack(client ClientConnection, monster *monsterState) error {
+	hireling := client.GetPlayerState().Hireling
+	stats := g.heroStateFactory.HirelingStats(hireling)
+
+	hit := d2netpacket.HitPacket{
+		AttackerID: d2hero.HirelingID(client.GetUniqueID()),
+		TargetID:   monster.npc.ID(),
+		Mode:       g.heroStateFactory.ChooseHirelingSkill(hireling, g.combatRand).Mode,
+	}
+
+	hitChance := d2combat.HitChance(stats.AttackRating, hireling.Level, monster.stats.ArmorClass, monster.level)
+	if !d2combat.Roll(g.combatRand, hitChance) {
+		hit.Missed = true
+	} else {
+		minDamage, maxDamage := g.weaponDamage(&d2inventory.CharacterEquipment{RightHand: hireling.Equipment.Weapon})
+		damage := d2combat.RollDamage(g.combatRand, stats.DamageMin+minDamage, stats.DamageMax+maxDamage)
+		hit.Damage = d2combat.ApplyResistance(damage, monster.stats.ResistancePhysical)
+	}
+
+	monster.health -= hit.Damage
+	if monster.health < 0 {
+		monster.health = 0
+	}
+
+	hit.Health, hit.MaxHealth = monster.health, monster.maxHealth
+	hit.Killed = monster.health == 0
+
+	hitPacket, err := d2netpacket.CreateHitPacket(hit)
+	if err != nil {
+		return err
+	}
+
+	g.sendPacketToClients(hitPacket)
+
+	if hit.Killed {
+		g.killMonster(client, monster)
+	}
+
+	return nil
+}
+
+// monsterAttackHireling rolls the attack of the monster on the hireling of the client's player,
+// against the defense of the hireling and its armor. The hireling dies when its life reaches zero.
+func (g *GameServer) monsterAttackHireling(monster *monsterState, owner ClientConnection) error {
+	hireling := owner.GetPlayerState().Hireling
+	stats := g.heroStateFactory.HirelingStats(hireling)
+	armor, block := g.armorDefense(&d2inventory.CharacterEquipment{
+		Head:   hireling.Equipment.Head,
+		Torso:  hireling.Equipment.Torso,
+		Shield: hireling.Equipment.Shield,
+	})
+
+	hit := d2netpacket.HitPacket{AttackerID: monster.npc.ID(), TargetID: d2hero.HirelingID(owner.GetUniqueID())}
+
+	hitChance := d2combat.HitChance(monster.stats.AttackRating, monster.level,
+		d2combat.Defense(stats.Dexterity, stats.Defense+armor), hireling.Level)
+
+	switch {
+	case !d2combat.Roll(g.combatRand, hitChance):
+		hit.Missed = true
+	case d2combat.Roll(g.combatRand, d2combat.BlockChance(block, stats.Dexterity, hireling.Level)):
+		hit.Missed = true
+	default:
+		hit.Damage = d2combat.RollDamage(g.combatRand, monster.stats.DamageMin, monster.stats.DamageMax)
+	}
+
+	hireling.Health -= hit.Damage
+	if hireling.Health <= 0 {
+		hireling.Kill()
+	}
+
+	hit.Health, hit.MaxHealth = hireling.Health, stats.MaxHealth
+	hit.Killed = hireling.IsDead
+
+	hitPacket, err := d2netpacket.CreateHitPacket(hit)
+	if err != nil {
+		return err
+	}
+
+	g.sendPacketToClients(hitPacket)
+
+	return nil
+}
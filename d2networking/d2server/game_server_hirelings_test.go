@@ -0,0 +1,159 @@
+package d2server
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2hero"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket"
+)
+
+func TestHandleEquipHirelingInvalid(t *testing.T) {
+	player := newTestClient("player", 10, 20)
+	other := newTestClient("other", 10, 20)
+	server := testServer(player, other)
+
+	tests := []struct {
+		name     string
+		playerID string
+		hireling *d2hero.HirelingState
+	}{
+		{"no hireling", "player", nil},
+		{"dead hireling", "player", &d2hero.HirelingState{ID: 1, IsDead: true}},
+		{"hireling of another player", "other", &d2hero.HirelingState{ID: 1, Health: 10}},
+	}
+
+	for _, test := range tests {
+		player.playerState.Hireling = test.hireling
+
+		packet, err := d2netpacket.CreateEquipHirelingPacket(test.playerID, d2hero.HirelingSlotHead, test.hireling, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := server.handleUpdateHireling(player, packet); !errors.Is(err, errInvalidHireling) {
+			t.Errorf("%s: want %v, have %v", test.name, errInvalidHireling, err)
+		}
+	}
+
+	if len(player.packets) != 0 || len(other.packets) != 0 {
+		t.Errorf("want no updates to be sent, have %d and %d packets", len(player.packets), len(other.packets))
+	}
+}
+
+func TestHandleHireHireling(t *testing.T) {
+	tests := []struct {
+		name      string
+		sellerX   float64
+		gold      int
+		level     int
+		wantErr   error
+		wantGold  int
+		wantHired bool
+	}{
+		{"hired", 15, 600, 3, nil, 100, true},
+		{"hired above the level of the hero", 15, 2000, 5, errInvalidHireling, 2000, false},
+		{"not enough gold", 15, 499, 3, errNotEnoughGold, 499, false},
+		{"far from the seller", 500, 600, 3, errInvalidHireling, 600, false},
+	}
+
+	for _, test := range tests {
+		player := newTestClient("player", 10, 20)
+		player.playerState.Act = 1
+		player.playerState.Gold = test.gold
+		server := townTestServer(t, testSeller, test.sellerX, 20, player)
+
+		// the client asks for a hireling at full health, the server recomputes everything but the choice
+		hireling := &d2hero.HirelingState{ID: 1, Difficulty: 1, Name: "Ravin", Level: test.level, Health: 1000}
+
+		packet, err := d2netpacket.CreateUpdateHirelingPacket(player.id, d2netpacket.HirelingActionHire, hireling, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = server.handleUpdateHireling(player, packet)
+		if test.wantErr == nil && err != nil || test.wantErr != nil && !errors.Is(err, test.wantErr) {
+			t.Errorf("%s: want %v, have %v", test.name, test.wantErr, err)
+		}
+
+		if player.playerState.Gold != test.wantGold {
+			t.Errorf("%s: want %d gold left, have %d", test.name, test.wantGold, player.playerState.Gold)
+		}
+
+		if (player.playerState.Hireling != nil) != test.wantHired || (len(player.packets) == 1) != test.wantHired {
+			t.Errorf("%s: want the hireling hired %v, have %+v and %d packets", test.name, test.wantHired,
+				player.playerState.Hireling, len(player.packets))
+		}
+
+		if hired := player.playerState.Hireling; hired != nil && (hired.Health != 50 || hired.Experience != 360) {
+			t.Errorf("%s: want the health and experience of a level 3 hireling, have %+v", test.name, hired)
+		}
+	}
+}
+
+func TestHandleReviveHireling(t *testing.T) {
+	tests := []struct {
+		name        string
+		sellerX     float64
+		gold        int
+		dead        bool
+		wantErr     error
+		wantGold    int
+		wantRevived bool
+	}{
+		{"revived", 15, 2000, true, nil, 433, true},
+		{"alive", 15, 2000, false, errInvalidHireling, 2000, false},
+		{"not enough gold", 15, 1566, true, errNotEnoughGold, 1566, false},
+		{"far from the seller", 500, 2000, true, errInvalidHireling, 2000, false},
+	}
+
+	for _, test := range tests {
+		player := newTestClient("player", 10, 20)
+		player.playerState.Act = 1
+		player.playerState.Gold = test.gold
+		player.playerState.Hireling = &d2hero.HirelingState{ID: 1, Difficulty: 1, Level: 3, Health: 10}
+		server := townTestServer(t, testSeller, test.sellerX, 20, player)
+
+		if test.dead {
+			player.playerState.Hireling.Kill()
+		}
+
+		packet, err := d2netpacket.CreateUpdateHirelingPacket(player.id, d2netpacket.HirelingActionRevive,
+			player.playerState.Hireling, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = server.handleUpdateHireling(player, packet)
+		if test.wantErr == nil && err != nil || test.wantErr != nil && !errors.Is(err, test.wantErr) {
+			t.Errorf("%s: want %v, have %v", test.name, test.wantErr, err)
+		}
+
+		if player.playerState.Gold != test.wantGold {
+			t.Errorf("%s: want %d gold left, have %d", test.name, test.wantGold, player.playerState.Gold)
+		}
+
+		if revived := !player.playerState.Hireling.IsDead && player.playerState.Hireling.Health == 50; test.dead &&
+			revived != test.wantRevived {
+			t.Errorf("%s: want the hireling revived %v, have %+v", test.name, test.wantRevived,
+				player.playerState.Hireling)
+		}
+	}
+}
+
+func TestHandleUpdateHirelingWithoutStats(t *testing.T) {
+	player := newTestClient("player", 10, 20)
+	player.playerState.Stats = nil
+	server := townTestServer(t, testSeller, 15, 20, player)
+
+	hireling := &d2hero.HirelingState{ID: 1, Difficulty: 1, Level: 3}
+
+	packet, err := d2netpacket.CreateUpdateHirelingPacket(player.id, d2netpacket.HirelingActionHire, hireling, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := server.handleUpdateHireling(player, packet); !errors.Is(err, errInvalidHireling) {
+		t.Errorf("want %v, have %v", errInvalidHireling, err)
+	}
+}
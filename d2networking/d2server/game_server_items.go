@@ -0,0 +1,138 @@
+package d2server
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2quest"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2client/d2clientconnectiontype"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket"
+)
+
+// the distance in tiles from which a player can pick up an item
+const itemPickupDistance = 3.0
+
+var (
+	errInvalidPickup = errors.New("invalid item pickup")
+	errInvalidSpawn  = errors.New("invalid item spawn")
+)
+
+// groundItem is an item lying on the ground of the map, at a tile
+type groundItem struct {
+	codes    []string
+	x, y     int
+	ethereal bool
+}
+
+// spawnItem puts the item with the given codes on the ground at the tile, and tells all clients
+// about it
+func (g *GameServer) spawnItem(x, y int, ethereal bool, codes ...string) {
+	if len(codes) < 1 {
+		return
+	}
+
+	id := uuid.New().String()
+
+	g.itemsMutex.Lock()
+	g.groundItems[id] = &groundItem{codes: codes, x: x, y: y, ethereal: ethereal}
+	g.itemsMutex.Unlock()
+
+	packet, err := d2netpacket.CreateSpawnItemPacket(id, x, y, codes...)
+	if err != nil {
+		g.Errorf("SpawnItemPacket: %v", err)
+		return
+	}
+
+	g.sendPacketToClients(packet)
+}
+
+// handleSpawnItem spawns the item the client asked for, with a server ID. Only the host can spawn
+// items, it is a debug command of the local client, the other players only pick up the items the
+// server dropped.
+func (g *GameServer) handleSpawnItem(client ClientConnection, packet d2netpacket.NetPacket) error {
+	if client.GetConnectionType() != d2clientconnectiontype.Local {
+		return fmt.Errorf("%w: player %s isn't the host", errInvalidSpawn, client.GetUniqueID())
+	}
+
+	spawnPacket, err := d2netpacket.UnmarshalSpawnItem(packet.PacketData)
+	if err != nil {
+		return err
+	}
+
+	g.spawnItem(spawnPacket.X, spawnPacket.Y, false, spawnPacket.Codes...)
+
+	return nil
+}
+
+// handlePickupItem puts an item on the ground next to the client's player into the player's
+// belt, and tells all clients the item was picked up. Items which don't fit stay on the ground.
+func (g *GameServer) handlePickupItem(client ClientConnection, packet d2netpacket.NetPacket) error {
+	pickupPacket, err := d2netpacket.UnmarshalPickupItem(packet.PacketData)
+	if err != nil {
+		return err
+	}
+
+	playerID := client.GetUniqueID()
+	if pickupPacket.PlayerID != playerID {
+		return fmt.Errorf("%w: player %s can't pick up items for %s", errInvalidPickup, playerID, pickupPacket.PlayerID)
+	}
+
+	playerState := client.GetPlayerState()
+	if playerState.IsDead {
+		return fmt.Errorf("%w: player %s is dead", errInvalidPickup, playerID)
+	}
+
+	g.itemsMutex.Lock()
+	defer g.itemsMutex.Unlock()
+
+	item, found := g.groundItems[pickupPacket.ItemID]
+	if !found {
+		return fmt.Errorf("%w: unknown item %s", errInvalidPickup, pickupPacket.ItemID)
+	}
+
+	if dx, dy := float64(item.x)-playerState.X, float64(item.y)-playerState.Y; dx*dx+dy*dy >
+		itemPickupDistance*itemPickupDistance {
+		return fmt.Errorf("%w: item %s is too far from player %s", errInvalidPickup, pickupPacket.ItemID, playerID)
+	}
+
+	code := item.codes[0]
+	if !g.heroStateFactory.PickupItem(&playerState.Equipment, playerState.Belt, code, item.ethereal) {
+		return nil
+	}
+
+	delete(g.groundItems, pickupPacket.ItemID)
+
+	if err := g.heroStateFactory.Save(playerState); err != nil {
+		g.Errorf("GameServer: error saving Player: %s", err)
+	}
+
+	pickedUp, err := d2netpacket.CreatePickupItemPacket(playerID, pickupPacket.ItemID, code, item.ethereal)
+	if err != nil {
+		return err
+	}
+
+	g.sendPacketToClients(pickedUp)
+	g.advanceQuests(client, d2quest.PickupTrigger(code))
+
+	return nil
+}
+
+// sendItemsToClient sends the items lying on the ground to the client
+func (g *GameServer) sendItemsToClient(client ClientConnection) {
+	g.itemsMutex.Lock()
+	defer g.itemsMutex.Unlock()
+
+	for id, item := range g.groundItems {
+		packet, err := d2netpacket.CreateSpawnItemPacket(id, item.x, item.y, item.codes...)
+		if err != nil {
+			g.Errorf("SpawnItemPacket: %v", err)
+			continue
+		}
+
+		if err := client.SendPacketToClient(packet); err != nil {
+			g.Errorf("GameServer: error sending SpawnItemPacket to client %s: %s", client.GetUniqueID(), err)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package d2server
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+)
+
+func TestSpawnItem(t *testing.T) {
+	client := newTestClient("player", 10, 20)
+	server := testServer(client)
+
+	server.spawnItem(10, 20, false, "hp1")
+	server.spawnItem(10, 20, false)
+
+	if len(server.groundItems) != 1 || len(client.packets) != 1 {
+		t.Fatalf("want one item on the ground, have %d items and %d packets", len(server.groundItems),
+			len(client.packets))
+	}
+
+	if client.packets[0].PacketType != d2netpackettype.SpawnItem {
+		t.Fatalf("want a SpawnItem packet, have %s", client.packets[0].PacketType)
+	}
+
+	spawnPacket, err := d2netpacket.UnmarshalSpawnItem(client.packets[0].PacketData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, found := server.groundItems[spawnPacket.ID]; !found {
+		t.Errorf("want the item to be spawned with the ID of the server, have %q", spawnPacket.ID)
+	}
+}
+
+func TestHandleSpawnItem(t *testing.T) {
+	host := newTestClient("host", 10, 20)
+	host.host = true
+	remote := newTestClient("remote", 10, 20)
+	server := testServer(host, remote)
+
+	packet, err := d2netpacket.CreateSpawnItemPacket("", 10, 20, "hp1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := server.handleSpawnItem(remote, packet); !errors.Is(err, errInvalidSpawn) {
+		t.Errorf("want errInvalidSpawn for a remote player, have %v", err)
+	}
+
+	if len(server.groundItems) != 0 {
+		t.Fatalf("want no item spawned for a remote player, have %d", len(server.groundItems))
+	}
+
+	if err := server.handleSpawnItem(host, packet); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(server.groundItems) != 1 {
+		t.Errorf("want the item of the host spawned, have %d items", len(server.groundItems))
+	}
+}
+
+func TestHandlePickupItemInvalid(t *testing.T) {
+	player := newTestClient("player", 10, 20)
+	other := newTestClient("other", 10, 20)
+	server := testServer(player, other)
+
+	server.groundItems["near"] = &groundItem{codes: []string{"hp1"}, x: 11, y: 21}
+	server.groundItems["far"] = &groundItem{codes: []string{"hp1"}, x: 20, y: 20}
+
+	tests := []struct {
+		name     string
+		client   *testClient
+		playerID string
+		itemID   string
+	}{
+		{"for another player", player, "other", "near"},
+		{"unknown item", player, "player", "unknown"},
+		{"too far from the item", player, "player", "far"},
+	}
+
+	for _, test := range tests {
+		packet, err := d2netpacket.CreatePickupItemPacket(test.playerID, test.itemID, "", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := server.handlePickupItem(test.client, packet); !errors.Is(err, errInvalidPickup) {
+			t.Errorf("%s: want %v, have %v", test.name, errInvalidPickup, err)
+		}
+	}
+
+	if len(server.groundItems) != 2 {
+		t.Errorf("want the items to stay on the ground, have %d items", len(server.groundItems))
+	}
+}
@@ -0,0 +1,394 @@
+package d2server
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapengine"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2quest"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2records"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2states"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket"
+)
+
+const (
+	// how often the operated objects are checked for being usable again
+	objectTickInterval = time.Second
+	// the distance in tiles from which a player can operate an object
+	objectOperateDistance = 3.0
+	// wells can be used again after this time
+	wellResetTime = 2 * time.Minute
+	// the length of the shrine effects is in frames
+	shrineFramesPerSecond = 25.0
+)
+
+// shrines.txt codes of the shrine effects
+const (
+	shrineRefilling       = 1
+	shrineHealth          = 2
+	shrineMana            = 3
+	shrineArmor           = 6
+	shrineCombat          = 7
+	shrineResistFire      = 8
+	shrineResistCold      = 9
+	shrineResistLightning = 10
+	shrineResistPoison    = 11
+	shrineSkill           = 12
+	shrineManaRecharge    = 13
+	shrineStamina         = 14
+	shrineExperience      = 15
+)
+
+var errInvalidObject = errors.New("invalid object")
+
+// shrineBoost is the state given by a booster shrine, the stats are raised by the first or
+// second argument of the shrine
+type shrineBoost struct {
+	state string
+	stats []shrineBoostStat
+}
+
+type shrineBoostStat struct {
+	stat string
+	arg  int
+}
+
+// nolint:gochecknoglobals // lookup table
+var shrineBoosts = map[int]shrineBoost{
+	shrineArmor: {"shrine_armor", []shrineBoostStat{{"item_armor_percent", 0}}},
+	shrineCombat: {"shrine_combat", []shrineBoostStat{
+		{"item_mindamage_percent", 0}, {"item_maxdamage_percent", 0}, {"item_tohit_percent", 1},
+	}},
+	shrineResistFire:      {"shrine_resist_fire", []shrineBoostStat{{"fireresist", 0}}},
+	shrineResistCold:      {"shrine_resist_cold", []shrineBoostStat{{"coldresist", 0}}},
+	shrineResistLightning: {"shrine_resist_lightning", []shrineBoostStat{{"lightresist", 0}}},
+	shrineResistPoison:    {"shrine_resist_poison", []shrineBoostStat{{"poisonresist", 0}}},
+	shrineSkill:           {"shrine_skill", []shrineBoostStat{{"item_allskills", 0}}},
+	shrineManaRecharge:    {"shrine_mana_regen", []shrineBoostStat{{"manarecoverybonus", 0}}},
+	shrineStamina:         {"shrine_stamina", []shrineBoostStat{{"staminarecoverybonus", 0}}},
+	shrineExperience:      {"shrine_experience", []shrineBoostStat{{"item_addexperience", 0}}},
+}
+
+// initObjects gives the shrines of the map their effects
+func (g *GameServer) initObjects(mapEngine *d2mapengine.MapEngine) {
+	// nolint:gosec // not concerned with crypto-strong randomness
+	r := rand.New(rand.NewSource(g.seed))
+
+	shrines := make([]*d2records.ShrineRecord, 0)
+	totalRarity := 0
+
+	for _, record := range g.asset.Records.Object.Shrines {
+		if record.Code > 0 && record.Rarity > 0 {
+			shrines = append(shrines, record)
+			totalRarity += record.Rarity
+		}
+	}
+
+	if totalRarity == 0 {
+		return
+	}
+
+	for _, entity := range mapEngine.Entities() {
+		object, ok := entity.(*d2mapentity.Object)
+		if !ok || object.Behavior() != d2mapentity.ObjectBehaviorShrine {
+			continue
+		}
+
+		roll := r.Intn(totalRarity)
+
+		for _, shrine := range shrines {
+			if roll -= shrine.Rarity; roll < 0 {
+				object.SetShrine(shrine)
+				break
+			}
+		}
+	}
+}
+
+// findObject returns the object with the given id and the map it is on
+func (g *GameServer) findObject(objectID string) (*d2mapengine.MapEngine, *d2mapentity.Object) {
+	for _, mapEngine := range g.mapEngines {
+		if object, ok := mapEngine.Entities()[objectID].(*d2mapentity.Object); ok {
+			return mapEngine, object
+		}
+	}
+
+	return nil, nil
+}
+
+// sendObjectsToClient sends the objects which aren't in their initial state, and the effects of
+// the shrines, to the client
+func (g *GameServer) sendObjectsToClient(client ClientConnection) {
+	g.objectsMutex.Lock()
+	defer g.objectsMutex.Unlock()
+
+	for _, mapEngine := range g.mapEngines {
+		for _, entity := range mapEngine.Entities() {
+			object, ok := entity.(*d2mapentity.Object)
+			if !ok || object.Behavior() == d2mapentity.ObjectBehaviorNone {
+				continue
+			}
+
+			if !object.IsOperated() && object.Shrine() == nil {
+				continue
+			}
+
+			update := objectUpdate(object)
+			if update.Mode == d2enum.ObjectAnimationModeOperating {
+				// the client doesn't need to play the operating animation of an object operated before it joined
+				update.Mode = d2enum.ObjectAnimationModeOpened
+			}
+
+			packet, err := d2netpacket.CreateUpdateObjectPacket(update)
+			if err != nil {
+				g.Errorf("UpdateObjectPacket: %v", err)
+				continue
+			}
+
+			if err := client.SendPacketToClient(packet); err != nil {
+				g.Errorf("GameServer: error sending UpdateObjectPacket to client %s: %s", client.GetUniqueID(), err)
+			}
+		}
+	}
+}
+
+func objectUpdate(object *d2mapentity.Object) d2netpacket.UpdateObjectPacket {
+	update := d2netpacket.UpdateObjectPacket{ObjectID: object.ID(), Mode: object.Mode()}
+
+	if shrine := object.Shrine(); shrine != nil {
+		update.Shrine = shrine.ShrineName
+	}
+
+	return update
+}
+
+// handleOperateObject validates the operation of an object by the client's player, applies
+// its effect and tells all clients about the changed object
+func (g *GameServer) handleOperateObject(client ClientConnection, packet d2netpacket.NetPacket) error {
+	operatePacket, err := d2netpacket.UnmarshalOperateObject(packet.PacketData)
+	if err != nil {
+		return err
+	}
+
+	if operatePacket.PlayerID != client.GetUniqueID() {
+		return fmt.Errorf("%w: player %s can't operate objects for %s", errInvalidObject, client.GetUniqueID(),
+			operatePacket.PlayerID)
+	}
+
+	g.objectsMutex.Lock()
+	defer g.objectsMutex.Unlock()
+
+	mapEngine, object := g.findObject(operatePacket.ObjectID)
+	if object == nil {
+		return fmt.Errorf("%w: unknown object %s", errInvalidObject, operatePacket.ObjectID)
+	}
+
+	behavior := object.Behavior()
+	if behavior == d2mapentity.ObjectBehaviorNone {
+		return fmt.Errorf("%w: object %s can't be operated", errInvalidObject, object.Label())
+	}
+
+	playerState := client.GetPlayerState()
+	position := object.GetPosition()
+	world := position.World()
+
+	if dx, dy := world.X()-playerState.X, world.Y()-playerState.Y; dx*dx+dy*dy >
+		objectOperateDistance*objectOperateDistance {
+		return fmt.Errorf("%w: object %s is too far from player %s", errInvalidObject, object.Label(),
+			client.GetUniqueID())
+	}
+
+	// doors can be closed again, the other objects can only be operated once until they reset
+	if _, resetting := g.objectResets[object.ID()]; resetting {
+		return nil
+	}
+
+	if object.IsOperated() && behavior != d2mapentity.ObjectBehaviorDoor {
+		return nil
+	}
+
+	update, err := g.operateObject(client, mapEngine, object)
+	if err != nil {
+		return err
+	}
+
+	updatePacket, err := d2netpacket.CreateUpdateObjectPacket(update)
+	if err != nil {
+		return err
+	}
+
+	g.sendPacketToClients(updatePacket)
+
+	g.advanceQuests(client, d2quest.UseObjectTrigger(object.Record().Name))
+
+	return nil
+}
+
+// operateObject changes the mode of the object and applies its effect to the client's player
+func (g *GameServer) operateObject(client ClientConnection, mapEngine *d2mapengine.MapEngine,
+	object *d2mapentity.Object) (d2netpacket.UpdateObjectPacket, error) {
+	mode := d2enum.ObjectAnimationModeOperating
+	if object.IsOperated() {
+		// only doors get here, they are closed again
+		mode = d2enum.ObjectAnimationModeNeutral
+	}
+
+	if err := object.SetMode(mode); err != nil {
+		return d2netpacket.UpdateObjectPacket{}, err
+	}
+
+	update := objectUpdate(object)
+	update.PlayerID = client.GetUniqueID()
+
+	switch behavior := object.Behavior(); behavior {
+	case d2mapentity.ObjectBehaviorContainer, d2mapentity.ObjectBehaviorBreakable:
+		g.dropTreasure(mapEngine, object)
+	case d2mapentity.ObjectBehaviorDoor:
+		mapEngine.UpdateObjectCollision(object)
+	case d2mapentity.ObjectBehaviorWell:
+		update.Life = client.GetPlayerState().Stats.MaxHealth
+		g.cureStates(client.GetUniqueID())
+		g.objectResets[object.ID()] = time.Now().Add(wellResetTime)
+	case d2mapentity.ObjectBehaviorShrine:
+		g.applyShrine(client, object, &update)
+	}
+
+	stats := client.GetPlayerState().Stats
+	stats.RestoreHealth(update.Life)
+	stats.RestoreMana(update.Mana)
+
+	return update, nil
+}
+
+// dropTreasure drops the treasure of a container or a breakable object. Objects drop the
+// treasure of the monsters of their area, for the level of the area on the difficulty of the game.
+func (g *GameServer) dropTreasure(mapEngine *d2mapengine.MapEngine, object *d2mapentity.Object) {
+	position := object.GetPosition()
+	tile := position.Tile()
+
+	levelDetails := g.levelDetails(mapEngine, position)
+	if levelDetails == nil {
+		return
+	}
+
+	for _, monsterID := range levelDetails.MonsterIDs(g.difficulty) {
+		monstat := g.asset.Records.Monster.Stats[monsterID]
+		if monstat == nil {
+			continue
+		}
+
+		name := monstat.Stats(g.difficulty).TreasureClass(d2records.MonsterTreasureClassRegular)
+
+		record := g.asset.Records.Item.Treasure.Normal.Upgrade(name, levelDetails.MonsterLevel(g.difficulty))
+		if record == nil {
+			continue
+		}
+
+		g.spawnTreasure(record, int(tile.X()), int(tile.Y()))
+
+		return
+	}
+
+	g.Debugf("GameServer: no treasure class for object %s in level %s", object.Label(), levelDetails.Name)
+}
+
+// spawnTreasure drops the items picked from the treasure class on the tile
+func (g *GameServer) spawnTreasure(record *d2records.TreasureClassRecord, tileX, tileY int) {
+	for _, item := range g.itemFactory.ItemsFromTreasureClass(record) {
+		g.spawnItem(tileX, tileY, item.IsEthereal(), item.Codes()...)
+	}
+}
+
+// applyShrine applies the effect of the shrine to the client's player. Recharge shrines restore
+// life and mana, booster shrines give a timed state.
+func (g *GameServer) applyShrine(client ClientConnection, object *d2mapentity.Object,
+	update *d2netpacket.UpdateObjectPacket) {
+	shrine := object.Shrine()
+	if shrine == nil {
+		return
+	}
+
+	if shrine.ResetTimeMinutes > 0 {
+		g.objectResets[object.ID()] = time.Now().Add(time.Duration(shrine.ResetTimeMinutes) * time.Minute)
+	}
+
+	stats := client.GetPlayerState().Stats
+
+	switch shrine.Code {
+	case shrineRefilling:
+		update.Life, update.Mana = stats.MaxHealth, stats.MaxMana
+		return
+	case shrineHealth:
+		update.Life = stats.MaxHealth
+		return
+	case shrineMana:
+		update.Mana = stats.MaxMana
+		return
+	}
+
+	boost, found := shrineBoosts[shrine.Code]
+	if !found {
+		g.Warningf("GameServer: the effect of %s is not supported yet", shrine.ShrineName)
+		return
+	}
+
+	args := []int{shrine.Arg0, shrine.Arg1}
+	modifiers := make([]d2states.Modifier, len(boost.stats))
+
+	for idx, stat := range boost.stats {
+		modifiers[idx] = d2states.Modifier{Stat: stat.stat, Value: args[stat.arg]}
+	}
+
+	duration := float64(shrine.DurationFrames) / shrineFramesPerSecond
+	state := d2states.NewState(boost.state, object.ID(), duration, modifiers...)
+
+	if err := g.applyState(client.GetUniqueID(), state); err != nil {
+		g.Errorf("GameServer: error applying %s: %v", shrine.ShrineName, err)
+	}
+}
+
+// resetObjects makes the wells and shrines whose reset time has passed usable again
+func (g *GameServer) resetObjects(now time.Time) {
+	g.objectsMutex.Lock()
+	defer g.objectsMutex.Unlock()
+
+	for objectID, resetAt := range g.objectResets {
+		if now.Before(resetAt) {
+			continue
+		}
+
+		delete(g.objectResets, objectID)
+
+		_, object := g.findObject(objectID)
+		if object == nil {
+			continue
+		}
+
+		if err := object.SetMode(d2enum.ObjectAnimationModeNeutral); err != nil {
+			g.Errorf("GameServer: error resetting object %s: %v", object.Label(), err)
+			continue
+		}
+
+		packet, err := d2netpacket.CreateUpdateObjectPacket(objectUpdate(object))
+		if err != nil {
+			g.Errorf("UpdateObjectPacket: %v", err)
+			continue
+		}
+
+		g.sendPacketToClients(packet)
+	}
+}
+
+// cureStates removes the states which can be cured from the entity
+func (g *GameServer) cureStates(entityID string) {
+	g.statesMutex.Lock()
+	defer g.statesMutex.Unlock()
+
+	if list, found := g.states[entityID]; found {
+		g.sendStateRemovals(entityID, list.Cure())
+	}
+}
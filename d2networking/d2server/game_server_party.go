@@ -0,0 +1,200 @@
+package d2server
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2party"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket"
+)
+
+// party members within this many tiles of each other share experience and quest credit
+const partyShareDistance = 40
+
+var errInvalidPartyAction = errors.New("invalid party action")
+
+// handlePartyAction applies the party action of the client's player, and sends the resulting
+// parties to all clients. Only players who both reached the hostile level can declare
+// hostility.
+func (g *GameServer) handlePartyAction(client ClientConnection, packet d2netpacket.NetPacket) error {
+	actionPacket, err := d2netpacket.UnmarshalPartyAction(packet.PacketData)
+	if err != nil {
+		return err
+	}
+
+	playerID, targetID := client.GetUniqueID(), actionPacket.TargetID
+	if actionPacket.PlayerID != playerID {
+		return fmt.Errorf("%w: player %s can't act for %s", errInvalidPartyAction, playerID, actionPacket.PlayerID)
+	}
+
+	target, found := g.connections[targetID]
+	if !found && actionPacket.Action != d2netpacket.PartyActionLeave {
+		return fmt.Errorf("%w: unknown player %s", errInvalidPartyAction, targetID)
+	}
+
+	g.partiesMutex.Lock()
+
+	switch actionPacket.Action {
+	case d2netpacket.PartyActionInvite:
+		err = g.parties.Invite(playerID, targetID)
+	case d2netpacket.PartyActionAccept:
+		err = g.parties.Accept(playerID, targetID)
+	case d2netpacket.PartyActionLeave:
+		err = g.parties.Leave(playerID)
+	case d2netpacket.PartyActionDeclareHostile:
+		if !canGoHostile(client) || !canGoHostile(target) {
+			err = fmt.Errorf("both players have to reach level %d", d2enum.PlayersHostileLevel)
+			break
+		}
+
+		err = g.parties.DeclareHostile(playerID, targetID)
+	case d2netpacket.PartyActionEndHostility:
+		err = g.parties.EndHostility(playerID, targetID)
+	default:
+		err = fmt.Errorf("unknown action %d", actionPacket.Action)
+	}
+
+	g.partiesMutex.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("%w: player %s, target %s: %v", errInvalidPartyAction, playerID, targetID, err)
+	}
+
+	g.sendPartiesToClients()
+
+	return nil
+}
+
+func canGoHostile(client ClientConnection) bool {
+	playerState := client.GetPlayerState()
+	return playerState != nil && playerState.Stats != nil && playerState.Stats.Level >= d2enum.PlayersHostileLevel
+}
+
+// removeFromParties removes a player who left the game from the parties
+func (g *GameServer) removeFromParties(playerID string) {
+	g.partiesMutex.Lock()
+	g.parties.Remove(playerID)
+	g.partiesMutex.Unlock()
+
+	g.sendPartiesToClients()
+}
+
+// sendPartiesToClient sends the parties of the game to the client
+func (g *GameServer) sendPartiesToClient(client ClientConnection) {
+	packet, err := g.createUpdatePartyPacket()
+	if err != nil {
+		g.Errorf("UpdatePartyPacket: %v", err)
+		return
+	}
+
+	if err := client.SendPacketToClient(packet); err != nil {
+		g.Errorf("GameServer: error sending UpdatePartyPacket to client %s: %s", client.GetUniqueID(), err)
+	}
+}
+
+func (g *GameServer) sendPartiesToClients() {
+	packet, err := g.createUpdatePartyPacket()
+	if err != nil {
+		g.Errorf("UpdatePartyPacket: %v", err)
+		return
+	}
+
+	g.sendPacketToClients(packet)
+}
+
+func (g *GameServer) createUpdatePartyPacket() (d2netpacket.NetPacket, error) {
+	g.partiesMutex.Lock()
+	defer g.partiesMutex.Unlock()
+
+	return d2netpacket.CreateUpdatePartyPacket(g.parties)
+}
+
+// inSameParty returns true if both players are in the same party
+func (g *GameServer) inSameParty(playerID, otherID string) bool {
+	g.partiesMutex.Lock()
+	defer g.partiesMutex.Unlock()
+
+	return g.parties.InSameParty(playerID, otherID)
+}
+
+// isInParty returns true if the player is in a party
+func (g *GameServer) isInParty(playerID string) bool {
+	g.partiesMutex.Lock()
+	defer g.partiesMutex.Unlock()
+
+	_, found := g.parties.PartyOf(playerID)
+
+	return found
+}
+
+// CanDamagePlayer returns true if the attacker may damage the target, players can only damage
+// players they are hostile to
+func (g *GameServer) CanDamagePlayer(attackerID, targetID string) bool {
+	g.partiesMutex.Lock()
+	defer g.partiesMutex.Unlock()
+
+	return attackerID != targetID && g.parties.IsHostile(attackerID, targetID)
+}
+
+// partyInRange returns the client and the members of its party in the same act, within the
+// share distance of the client's player
+func (g *GameServer) partyInRange(client ClientConnection) []ClientConnection {
+	members := []ClientConnection{client}
+	playerState := client.GetPlayerState()
+
+	for id, connection := range g.connections {
+		if id == client.GetUniqueID() || !g.inSameParty(client.GetUniqueID(), id) {
+			continue
+		}
+
+		other := connection.GetPlayerState()
+		if other == nil || other.Act != playerState.Act {
+			continue
+		}
+
+		if dx, dy := other.X-playerState.X, other.Y-playerState.Y; dx*dx+dy*dy >
+			partyShareDistance*partyShareDistance {
+			continue
+		}
+
+		members = append(members, connection)
+	}
+
+	return members
+}
+
+// GiveExperience gives the experience of a kill by the player, which is shared with the
+// members of their party in range
+func (g *GameServer) GiveExperience(playerID string, experience int) {
+	client, found := g.connections[playerID]
+	if !found {
+		return
+	}
+
+	members := g.partyInRange(client)
+	levels := make([]int, len(members))
+
+	for idx, member := range members {
+		if stats := member.GetPlayerState().Stats; stats != nil {
+			levels[idx] = stats.Level
+		}
+	}
+
+	for idx, share := range d2party.ExperienceShares(experience, levels) {
+		playerState := members[idx].GetPlayerState()
+		if playerState.Stats == nil || share == 0 {
+			continue
+		}
+
+		playerState.Stats.Experience += share
+
+		packet, err := d2netpacket.CreateGainExperiencePacket(members[idx].GetUniqueID(), share)
+		if err != nil {
+			g.Errorf("GainExperiencePacket: %v", err)
+			continue
+		}
+
+		g.sendPacketToClients(packet)
+	}
+}
@@ -0,0 +1,246 @@
+package d2server
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket"
+)
+
+const (
+	// the town end of a town portal is opened this far from the start position of the town, in tiles
+	townPortalOffsetX, townPortalOffsetY = 2, 2
+	// the players come out this far from the portal they are taken to, so they don't stand on it
+	portalExitOffset = 1.0
+)
+
+var (
+	errInvalidPortal = errors.New("invalid town portal")
+	errTownNotLoaded = errors.New("town not loaded")
+)
+
+// nolint:gochecknoglobals // lookup table
+var actTowns = map[int]d2enum.RegionIdType{
+	d2enum.Act1: d2enum.RegionAct1Town,
+	d2enum.Act2: d2enum.RegionAct2Town,
+	d2enum.Act3: d2enum.RegionAct3Town,
+	d2enum.Act4: d2enum.RegionAct4Town,
+	d2enum.Act5: d2enum.RegonAct5Town,
+}
+
+// townPortal is a pair of portals owned by a player, one in the field and one in the town of
+// the act. It is kept apart from the map engines, so that it survives the levels of its
+// portals being unloaded.
+type townPortal struct {
+	owner     string
+	ownerName string
+	field     d2netpacket.PortalEnd
+	town      d2netpacket.PortalEnd
+}
+
+func (p *townPortal) update(action d2netpacket.PortalAction) d2netpacket.UpdatePortalPacket {
+	return d2netpacket.UpdatePortalPacket{
+		Action:    action,
+		OwnerID:   p.owner,
+		OwnerName: p.ownerName,
+		Field:     p.field,
+		Town:      p.town,
+	}
+}
+
+// ends returns the portal with the given id and the portal it leads to
+func (p *townPortal) ends(portalID string) (entrance, exit *d2netpacket.PortalEnd) {
+	switch portalID {
+	case p.field.ID:
+		return &p.field, &p.town
+	case p.town.ID:
+		return &p.town, &p.field
+	}
+
+	return nil, nil
+}
+
+// regionAt returns the region of the tile at the given position, on the map engine which has it
+func (g *GameServer) regionAt(x, y float64) (d2enum.RegionIdType, bool) {
+	for _, mapEngine := range g.mapEngines {
+		if tile := mapEngine.TileAt(int(x), int(y)); tile != nil && mapEngine.TileExists(int(x), int(y)) {
+			return tile.RegionType, true
+		}
+	}
+
+	return 0, false
+}
+
+// actTown returns the town of the act, unknown acts are in the town of act 1
+func actTown(act int) d2enum.RegionIdType {
+	if town, ok := actTowns[act]; ok {
+		return town
+	}
+
+	return actTowns[d2enum.Act1]
+}
+
+// townStartPosition returns the start position of the given town
+func (g *GameServer) townStartPosition(town d2enum.RegionIdType) (x, y float64, err error) {
+	for _, mapEngine := range g.mapEngines {
+		startX, startY := mapEngine.GetStartPosition()
+
+		if tile := mapEngine.TileAt(int(startX), int(startY)); tile != nil && tile.RegionType == town {
+			return startX, startY, nil
+		}
+	}
+
+	return 0, 0, fmt.Errorf("%w: the town %d isn't loaded", errTownNotLoaded, town)
+}
+
+// handleCastSkillPortal opens a town portal if the skill cast by the client's player is the
+// skill of the scroll or tome of town portal. The player's previous town portal is closed.
+func (g *GameServer) handleCastSkillPortal(client ClientConnection, packet d2netpacket.NetPacket) error {
+	castPacket, err := d2netpacket.UnmarshalCast(packet.PacketData)
+	if err != nil {
+		return err
+	}
+
+	skill := g.asset.Records.Skill.Details[castPacket.SkillID]
+	if skill == nil || !g.itemFactory.IsTownPortalSkill(skill.Skill) {
+		return nil
+	}
+
+	playerState := client.GetPlayerState()
+
+	region, found := g.regionAt(playerState.X, playerState.Y)
+	if !found {
+		return fmt.Errorf("%w: player %s isn't on a map", errInvalidPortal, client.GetUniqueID())
+	}
+
+	town := actTown(playerState.Act)
+	if region == town {
+		return fmt.Errorf("%w: player %s can't open a town portal in town", errInvalidPortal, client.GetUniqueID())
+	}
+
+	townX, townY, err := g.townStartPosition(town)
+	if err != nil {
+		return err
+	}
+
+	townX += townPortalOffsetX
+	townY += townPortalOffsetY
+
+	portal := &townPortal{
+		owner:     client.GetUniqueID(),
+		ownerName: playerState.HeroName,
+		field:     d2netpacket.PortalEnd{ID: uuid.New().String(), Region: region, X: playerState.X, Y: playerState.Y},
+		town:      d2netpacket.PortalEnd{ID: uuid.New().String(), Region: town, X: townX, Y: townY},
+	}
+
+	g.portalsMutex.Lock()
+	defer g.portalsMutex.Unlock()
+
+	g.closePortal(portal.owner)
+	g.portals[portal.owner] = portal
+	g.sendPortalUpdate(portal, d2netpacket.PortalActionOpen)
+
+	return nil
+}
+
+// handleUsePortal takes the client's player through a town portal, if the player owns it or
+// is in the party of its owner. The owner going back to the field closes the portal.
+func (g *GameServer) handleUsePortal(client ClientConnection, packet d2netpacket.NetPacket) error {
+	usePacket, err := d2netpacket.UnmarshalUsePortal(packet.PacketData)
+	if err != nil {
+		return err
+	}
+
+	playerID := client.GetUniqueID()
+	if usePacket.PlayerID != playerID {
+		return fmt.Errorf("%w: player %s can't use portals for %s", errInvalidPortal, playerID, usePacket.PlayerID)
+	}
+
+	g.portalsMutex.Lock()
+	defer g.portalsMutex.Unlock()
+
+	var portal *townPortal
+
+	var entrance, exit *d2netpacket.PortalEnd
+
+	for _, candidate := range g.portals {
+		if entrance, exit = candidate.ends(usePacket.PortalID); entrance != nil {
+			portal = candidate
+			break
+		}
+	}
+
+	if portal == nil {
+		return fmt.Errorf("%w: unknown portal %s", errInvalidPortal, usePacket.PortalID)
+	}
+
+	if portal.owner != playerID && !g.inSameParty(portal.owner, playerID) {
+		return fmt.Errorf("%w: player %s can't use the portal of %s", errInvalidPortal, playerID, portal.owner)
+	}
+
+	playerState := client.GetPlayerState()
+
+	if dx, dy := entrance.X-playerState.X, entrance.Y-playerState.Y; dx*dx+dy*dy >
+		objectOperateDistance*objectOperateDistance {
+		return fmt.Errorf("%w: portal %s is too far from player %s", errInvalidPortal, entrance.ID, playerID)
+	}
+
+	playerState.X = exit.X + portalExitOffset
+	playerState.Y = exit.Y + portalExitOffset
+
+	warp, err := d2netpacket.CreateWarpPlayerPacket(playerID, playerState.X, playerState.Y)
+	if err != nil {
+		return err
+	}
+
+	g.sendPacketToClients(warp)
+	g.updatePlayerLevel(client)
+
+	if portal.owner == playerID && entrance == &portal.town {
+		g.closePortal(playerID)
+	}
+
+	return nil
+}
+
+// closePortal closes the town portal of the player, if there is one
+func (g *GameServer) closePortal(owner string) {
+	portal, found := g.portals[owner]
+	if !found {
+		return
+	}
+
+	delete(g.portals, owner)
+	g.sendPortalUpdate(portal, d2netpacket.PortalActionClose)
+}
+
+// sendPortalsToClient sends the open town portals to the client
+func (g *GameServer) sendPortalsToClient(client ClientConnection) {
+	g.portalsMutex.Lock()
+	defer g.portalsMutex.Unlock()
+
+	for _, portal := range g.portals {
+		packet, err := d2netpacket.CreateUpdatePortalPacket(portal.update(d2netpacket.PortalActionOpen))
+		if err != nil {
+			g.Errorf("UpdatePortalPacket: %v", err)
+			continue
+		}
+
+		if err := client.SendPacketToClient(packet); err != nil {
+			g.Errorf("GameServer: error sending UpdatePortalPacket to client %s: %s", client.GetUniqueID(), err)
+		}
+	}
+}
+
+func (g *GameServer) sendPortalUpdate(portal *townPortal, action d2netpacket.PortalAction) {
+	packet, err := d2netpacket.CreateUpdatePortalPacket(portal.update(action))
+	if err != nil {
+		g.Errorf("UpdatePortalPacket: %v", err)
+		return
+	}
+
+	g.sendPacketToClients(packet)
+}
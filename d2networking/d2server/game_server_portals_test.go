@@ -0,0 +1,162 @@
+package d2server
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+)
+
+func testPortal(owner string) *townPortal {
+	return &townPortal{
+		owner:     owner,
+		ownerName: owner,
+		field:     d2netpacket.PortalEnd{ID: owner + "-field", Region: d2enum.RegionAct1Wilderness, X: 50, Y: 60},
+		town:      d2netpacket.PortalEnd{ID: owner + "-town", Region: d2enum.RegionAct1Town, X: 10, Y: 20},
+	}
+}
+
+func TestTownPortalEnds(t *testing.T) {
+	portal := testPortal("owner")
+
+	entrance, exit := portal.ends("owner-field")
+	if entrance != &portal.field || exit != &portal.town {
+		t.Error("want the field portal to lead to town")
+	}
+
+	entrance, exit = portal.ends("owner-town")
+	if entrance != &portal.town || exit != &portal.field {
+		t.Error("want the town portal to lead to the field")
+	}
+
+	if entrance, exit = portal.ends("other-field"); entrance != nil || exit != nil {
+		t.Error("want no ends for the portal of another player")
+	}
+}
+
+func usePortal(t *testing.T, server *GameServer, client *testClient, portalID string) error {
+	t.Helper()
+
+	packet, err := d2netpacket.CreateUsePortalPacket(client.id, portalID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return server.handleUsePortal(client, packet)
+}
+
+func TestHandleUsePortal(t *testing.T) {
+	owner := newTestClient("owner", 50, 60)
+	member := newTestClient("member", 50, 61)
+	stranger := newTestClient("stranger", 50, 60)
+	server := testServer(owner, member, stranger)
+
+	server.portals["owner"] = testPortal("owner")
+	joinParty(t, server, "owner", "member")
+
+	tests := []struct {
+		name     string
+		client   *testClient
+		portalID string
+		valid    bool
+	}{
+		{"unknown portal", owner, "stranger-field", false},
+		{"not in the party of the owner", stranger, "owner-field", false},
+		{"party member", member, "owner-field", true},
+		{"too far from the portal", member, "owner-field", false},
+		{"owner", owner, "owner-field", true},
+	}
+
+	for _, test := range tests {
+		err := usePortal(t, server, test.client, test.portalID)
+
+		if test.valid && err != nil {
+			t.Errorf("%s: want the portal to be used, have %v", test.name, err)
+		}
+
+		if !test.valid && !errors.Is(err, errInvalidPortal) {
+			t.Errorf("%s: want %v, have %v", test.name, errInvalidPortal, err)
+		}
+	}
+
+	// both went through the field portal, and came out next to the town portal
+	for _, client := range []*testClient{owner, member} {
+		if client.playerState.X != 10+portalExitOffset || client.playerState.Y != 20+portalExitOffset {
+			t.Errorf("%s: want to be warped to town, is at %v,%v", client.id, client.playerState.X, client.playerState.Y)
+		}
+	}
+
+	if _, found := server.portals["owner"]; !found {
+		t.Fatal("want the portal to stay open when it is used to go to town")
+	}
+}
+
+func TestHandleUsePortalCloses(t *testing.T) {
+	owner := newTestClient("owner", 10, 20)
+	member := newTestClient("member", 10, 20)
+	server := testServer(owner, member)
+
+	server.portals["owner"] = testPortal("owner")
+	joinParty(t, server, "owner", "member")
+
+	// party members going back to the field leave the portal open
+	if err := usePortal(t, server, member, "owner-town"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, found := server.portals["owner"]; !found {
+		t.Fatal("want the portal to stay open when a party member goes back to the field")
+	}
+
+	owner.packets = nil
+
+	if err := usePortal(t, server, owner, "owner-town"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, found := server.portals["owner"]; found {
+		t.Fatal("want the portal to close when its owner goes back to the field")
+	}
+
+	var closed bool
+
+	for _, packet := range owner.packets {
+		if packet.PacketType != d2netpackettype.UpdatePortal {
+			continue
+		}
+
+		update, err := d2netpacket.UnmarshalUpdatePortal(packet.PacketData)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		closed = closed || update.Action == d2netpacket.PortalActionClose && update.OwnerID == "owner"
+	}
+
+	if !closed {
+		t.Error("want the clients to be told that the portal is closed")
+	}
+}
+
+func TestHandleUsePortalForOtherPlayer(t *testing.T) {
+	owner := newTestClient("owner", 50, 60)
+	other := newTestClient("other", 50, 60)
+	server := testServer(owner, other)
+
+	server.portals["owner"] = testPortal("owner")
+
+	packet, err := d2netpacket.CreateUsePortalPacket(owner.id, "owner-field")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := server.handleUsePortal(other, packet); !errors.Is(err, errInvalidPortal) {
+		t.Errorf("want %v, have %v", errInvalidPortal, err)
+	}
+
+	if owner.playerState.X != 50 || owner.playerState.Y != 60 {
+		t.Error("want the owner to stay where they are")
+	}
+}
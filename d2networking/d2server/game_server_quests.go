@@ -0,0 +1,112 @@
+package d2server
+
+import (
+	"strings"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2math/d2vector"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapengine"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2quest"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2records"
+)
+
+// the distance in tiles from which a player can talk to a town npc. The npcs walk around town
+// on the clients, the server only knows where they were spawned.
+const npcTalkDistance = 20.0
+
+// nearNPC returns whether a town npc with the given monstats.txt id stands close enough to
+// the client's player to talk to
+func (g *GameServer) nearNPC(client ClientConnection, npcKey string) bool {
+	return g.nearMatchingNPC(client, func(monstat *d2records.MonStatRecord) bool {
+		return strings.EqualFold(monstat.Key, npcKey)
+	})
+}
+
+// nearHirelingSeller returns whether a town npc selling hirelings in the act and difficulty of
+// the client's player stands close enough to the player to talk to
+func (g *GameServer) nearHirelingSeller(client ClientConnection) bool {
+	playerState := client.GetPlayerState()
+
+	return g.nearMatchingNPC(client, func(monstat *d2records.MonStatRecord) bool {
+		return g.heroStateFactory.IsHirelingSeller(monstat.ID, playerState.Act, playerState.Difficulty)
+	})
+}
+
+// nearMatchingNPC returns whether a town npc whose monstats.txt record matches stands close
+// enough to the client's player to talk to
+func (g *GameServer) nearMatchingNPC(client ClientConnection, match func(*d2records.MonStatRecord) bool) bool {
+	playerState := client.GetPlayerState()
+
+	for _, mapEngine := range g.mapEngines {
+		for _, entity := range mapEngine.Entities() {
+			npc, ok := entity.(*d2mapentity.NPC)
+			if !ok || npc.IsHostile() || npc.MonsterStats() == nil || !match(npc.MonsterStats()) {
+				continue
+			}
+
+			position := npc.GetPosition()
+			world := position.World()
+
+			if dx, dy := world.X()-playerState.X, world.Y()-playerState.Y; dx*dx+dy*dy <=
+				npcTalkDistance*npcTalkDistance {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// playerLevel returns the levels.txt record of the area the client's player stands in
+func (g *GameServer) playerLevel(client ClientConnection) *d2records.LevelDetailRecord {
+	playerState := client.GetPlayerState()
+	position := d2vector.NewPosition(playerState.X, playerState.Y)
+
+	for _, mapEngine := range g.mapEngines {
+		if level := g.levelDetails(mapEngine, position); level != nil {
+			return level
+		}
+	}
+
+	return nil
+}
+
+// updatePlayerLevel advances the quests of the client's player when the player moved into
+// another level
+func (g *GameServer) updatePlayerLevel(client ClientConnection) {
+	level := g.playerLevel(client)
+	if level == nil {
+		return
+	}
+
+	g.levelsMutex.Lock()
+	previous, found := g.playerLevels[client.GetUniqueID()]
+	g.playerLevels[client.GetUniqueID()] = level.ID
+	g.levelsMutex.Unlock()
+
+	if found && previous == level.ID {
+		return
+	}
+
+	g.advanceQuests(client, d2quest.EnterLevelTrigger(level.ID))
+}
+
+// isLevelCleared returns whether no monster is left alive in the level of the map
+func (g *GameServer) isLevelCleared(mapEngine *d2mapengine.MapEngine, levelID int) bool {
+	for _, entity := range mapEngine.Entities() {
+		npc, ok := entity.(*d2mapentity.NPC)
+		if !ok || !npc.IsHostile() {
+			continue
+		}
+
+		if monster, found := g.monsters[npc.ID()]; found && monster.health <= 0 {
+			continue
+		}
+
+		if level := g.levelDetails(mapEngine, npc.GetPosition()); level != nil && level.ID == levelID {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,90 @@
+package d2server
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2quest"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket"
+)
+
+func TestHandleQuestEventTriggers(t *testing.T) {
+	client := newTestClient("player", 10, 20)
+	client.playerState.Quests = d2quest.NewState()
+	server := testServer(client)
+
+	tests := []struct {
+		name    string
+		trigger d2quest.Trigger
+		valid   bool
+	}{
+		{"quest log viewed", d2quest.QuestLogViewedTrigger(1), true},
+		{"talk to an npc which is not around", d2quest.TalkTrigger("Akara"), false},
+		{"enter level", d2quest.EnterLevelTrigger(1), false},
+		{"clear level", d2quest.ClearLevelTrigger(1), false},
+		{"kill monster", d2quest.KillTrigger("andariel"), false},
+		{"pick up item", d2quest.PickupTrigger("bks"), false},
+		{"use item", d2quest.UseItemTrigger("xyz"), false},
+		{"use object", d2quest.UseObjectTrigger("CainGibbet"), false},
+	}
+
+	for _, test := range tests {
+		packet, err := d2netpacket.CreateQuestEventPacket(client.id, test.trigger)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = server.handleQuestEvent(client, packet)
+
+		if test.valid && err != nil {
+			t.Errorf("%s: want the trigger to be accepted, have %v", test.name, err)
+		}
+
+		if !test.valid && !errors.Is(err, errInvalidQuestEvent) {
+			t.Errorf("%s: want %v, have %v", test.name, errInvalidQuestEvent, err)
+		}
+	}
+}
+
+func TestSuperUniqueKillTriggers(t *testing.T) {
+	// act 5 is entered after terror's end
+	tests := []struct {
+		superUnique string
+		questID     int
+		finished    []int
+	}{
+		{"The Countess", d2quest.ID(d2enum.Act1, 5), nil},
+		{"Shenk the Overseer", d2quest.ID(d2enum.Act5, 1), []int{d2quest.ID(d2enum.Act4, 2)}},
+	}
+
+	for _, test := range tests {
+		client := newTestClient("player", 10, 20)
+		client.playerState.Quests = d2quest.NewState()
+		server := testServer(client)
+
+		// the tower and the siege start by reading the tome and talking to larzuk
+		client.playerState.Quests.SetStatus(client.playerState.Difficulty, test.questID, 1)
+
+		for _, questID := range test.finished {
+			client.playerState.Quests.SetStatus(client.playerState.Difficulty, questID, d2enum.QuestStatusCompleted)
+		}
+
+		npc := &d2mapentity.NPC{}
+		npc.SetSuperUnique(test.superUnique)
+
+		triggers := monsterKillTriggers(npc)
+		if len(triggers) != 1 || triggers[0] != d2quest.KillTrigger(test.superUnique) {
+			t.Fatalf("%s: want the kill trigger of the superunique, have %v", test.superUnique, triggers)
+		}
+
+		for _, trigger := range triggers {
+			server.advanceQuests(client, trigger)
+		}
+
+		if status := client.playerState.Quests.Status(client.playerState.Difficulty, test.questID); status == 1 {
+			t.Errorf("%s: want quest %d advanced by the kill, have status %d", test.superUnique, test.questID, status)
+		}
+	}
+}
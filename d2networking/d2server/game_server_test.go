@@ -0,0 +1,200 @@
+package d2server
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2txt"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2math/d2vector"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2util"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2hero"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapengine"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2party"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2records"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2client/d2clientconnectiontype"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket"
+)
+
+// testClient is a client connection which keeps the packets sent to it
+type testClient struct {
+	id          string
+	playerState *d2hero.HeroState
+	packets     []d2netpacket.NetPacket
+	host        bool
+}
+
+func (c *testClient) GetUniqueID() string {
+	return c.id
+}
+
+func (c *testClient) GetConnectionType() d2clientconnectiontype.ClientConnectionType {
+	if c.host {
+		return d2clientconnectiontype.Local
+	}
+
+	return d2clientconnectiontype.LANClient
+}
+
+func (c *testClient) SendPacketToClient(packet d2netpacket.NetPacket) error {
+	c.packets = append(c.packets, packet)
+	return nil
+}
+
+func (c *testClient) GetPlayerState() *d2hero.HeroState {
+	return c.playerState
+}
+
+func (c *testClient) SetPlayerState(playerState *d2hero.HeroState) {
+	c.playerState = playerState
+}
+
+// testServer returns a game server without assets or map engines, with the given clients
+// connected to it
+func testServer(clients ...*testClient) *GameServer {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	server := &GameServer{
+		ctx:          ctx,
+		cancel:       cancel,
+		connections:  make(map[string]ClientConnection),
+		portals:      make(map[string]*townPortal),
+		parties:      d2party.NewParties(),
+		chatLimiters: make(map[string]*chatLimiter),
+		monsters:     make(map[string]*monsterState),
+		nextAttacks:  make(map[string]time.Time),
+		combatRand:   rand.New(rand.NewSource(1)), // nolint:gosec // not concerned with crypto-strong randomness
+		groundItems:  make(map[string]*groundItem),
+		playerLevels: make(map[string]int),
+		Logger:       d2util.NewLogger(),
+	}
+
+	server.Logger.SetLevel(d2util.LogLevelNone)
+
+	for _, client := range clients {
+		server.connections[client.id] = client
+	}
+
+	return server
+}
+
+func newTestClient(id string, x, y float64) *testClient {
+	return &testClient{
+		id: id,
+		playerState: &d2hero.HeroState{
+			HeroName: id,
+			X:        x,
+			Y:        y,
+			Stats:    &d2hero.HeroStatsState{Level: 1},
+		},
+	}
+}
+
+// the town npcs of the test records, a hireling seller and a vendor repairing items for half the cost
+// nolint:gochecknoglobals // test data
+var (
+	testSeller = &d2records.MonStatRecord{ID: 150, Key: "Kashya", IsNpc: true}
+	testVendor = &d2records.MonStatRecord{ID: 154, Key: "Charsi", IsNpc: true}
+)
+
+const testNPCRecords = "npc\tbuy mult\tsell mult\trep mult\tmax buy\tmax buy (N)\tmax buy (H)\n" +
+	"Charsi\t1024\t256\t512\t25000\t50000\t100000\n"
+
+// townTestServer returns a game server with the records of a hireling sold in act 1, a few items
+// and the vendors of npc.txt, and the given town npc standing at the given position
+func townTestServer(t *testing.T, npc *d2records.MonStatRecord, npcX, npcY float64,
+	clients ...*testClient) *GameServer {
+	t.Helper()
+
+	records, err := d2records.NewRecordManager(d2util.LogLevelNone)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dict, err := d2txt.LoadDataDictionary([]byte(testNPCRecords))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := records.Load(d2resource.NPC, dict); err != nil {
+		t.Fatal(err)
+	}
+
+	records.Hireling.Details = d2records.Hirelings{
+		{ID: 1, Difficulty: 1, Act: 1, Level: 3, Seller: testSeller.ID, Gold: 500, ExpPerLvl: 10, HP: 50,
+			HPPerLvl: 5},
+	}
+	records.Item.Armors = d2records.CommonItems{
+		"buc": {Code: "buc"},
+		"cap": {Code: "cap", Durability: 12, Cost: 100},
+	}
+	records.Item.Weapons = d2records.CommonItems{"ssd": {Code: "ssd", Durability: 24, Cost: 200}}
+
+	for _, code := range []string{"hax", "wnd", "ktr", "sst", "jav", "clb"} {
+		records.Item.Weapons[code] = &d2records.ItemCommonRecord{Code: code}
+	}
+
+	records.Item.All = d2records.CommonItems{"cap": records.Item.Armors["cap"], "ssd": records.Item.Weapons["ssd"]}
+
+	asset := &d2asset.AssetManager{Records: records}
+
+	factory, err := d2hero.NewHeroStateFactory(asset)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entity := &d2mapentity.NPC{}
+	entity.SetMonsterStats(npc)
+	entity.Position = d2vector.NewPosition(npcX, npcY)
+
+	mapEngine := d2mapengine.CreateMapEngine(d2util.LogLevelNone, asset)
+	mapEngine.AddEntity(entity)
+
+	server := testServer(clients...)
+	server.asset = asset
+	server.heroStateFactory = factory
+	server.mapEngines = append(server.mapEngines, mapEngine)
+
+	return server
+}
+
+func joinParty(t *testing.T, server *GameServer, inviterID, playerID string) {
+	t.Helper()
+
+	if err := server.parties.Invite(inviterID, playerID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := server.parties.Accept(playerID, inviterID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStoppedServer(t *testing.T) {
+	client := newTestClient("player", 0, 0)
+	server := testServer(client)
+
+	ticks := 0
+	server.tick(func() { ticks++ })
+
+	server.cancel()
+	server.tick(func() { ticks++ })
+
+	if ticks != 1 {
+		t.Errorf("want the state updated only while the server runs, have %d ticks", ticks)
+	}
+
+	packet, err := d2netpacket.CreateChatMessagePacket(d2netpacket.ChatMessagePacket{PlayerID: client.id, Text: "hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := server.OnPacketReceived(client, packet); !errors.Is(err, errServerStopped) {
+		t.Errorf("want errServerStopped, have %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package d2networking
+
+import (
+	"sync"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket"
+)
+
+// PacketQueue holds the packets sent to a client until the client receives them, so the packets
+// are handled by the goroutine which updates the game and not by the one of the server.
+type PacketQueue struct {
+	mutex   sync.Mutex
+	packets []d2netpacket.NetPacket
+}
+
+// Push adds a packet to the end of the queue
+func (q *PacketQueue) Push(packet d2netpacket.NetPacket) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.packets = append(q.packets, packet)
+}
+
+// Receive passes the queued packets to the listener in order, including the ones pushed while
+// receiving them. All of the packets are passed on, the first error is returned.
+func (q *PacketQueue) Receive(listener ClientListener) error {
+	var firstErr error
+
+	for {
+		packet, found := q.pop()
+		if !found {
+			return firstErr
+		}
+
+		if err := listener.OnPacketReceived(packet); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+}
+
+func (q *PacketQueue) pop() (d2netpacket.NetPacket, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if len(q.packets) == 0 {
+		return d2netpacket.NetPacket{}, false
+	}
+
+	packet := q.packets[0]
+	q.packets = q.packets[1:]
+
+	return packet, true
+}
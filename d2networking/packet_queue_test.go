@@ -0,0 +1,55 @@
+package d2networking
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+)
+
+type testListener struct {
+	queue    *PacketQueue
+	received []d2netpackettype.NetPacketType
+}
+
+func (l *testListener) OnPacketReceived(packet d2netpacket.NetPacket) error {
+	l.received = append(l.received, packet.PacketType)
+
+	// answering a packet queues the next one, like a client talking to the local server
+	if packet.PacketType == d2netpackettype.MovePlayer {
+		l.queue.Push(d2netpacket.NetPacket{PacketType: d2netpackettype.CastSkill})
+		return errors.New("first error")
+	}
+
+	return errors.New("second error")
+}
+
+func TestPacketQueue(t *testing.T) {
+	queue := &PacketQueue{}
+	listener := &testListener{queue: queue}
+
+	queue.Push(d2netpacket.NetPacket{PacketType: d2netpackettype.MovePlayer})
+	queue.Push(d2netpacket.NetPacket{PacketType: d2netpackettype.AddPlayer})
+
+	if err := queue.Receive(listener); err == nil || err.Error() != "first error" {
+		t.Errorf("want the first error, have %v", err)
+	}
+
+	want := []d2netpackettype.NetPacketType{d2netpackettype.MovePlayer, d2netpackettype.AddPlayer,
+		d2netpackettype.CastSkill}
+
+	if len(listener.received) != len(want) {
+		t.Fatalf("want %v, have %v", want, listener.received)
+	}
+
+	for idx := range want {
+		if listener.received[idx] != want[idx] {
+			t.Errorf("packet %d: want %s, have %s", idx, want[idx], listener.received[idx])
+		}
+	}
+
+	if err := queue.Receive(listener); err != nil || len(listener.received) != len(want) {
+		t.Errorf("want an empty queue, have %v", listener.received)
+	}
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"image/color"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2pl2"
+)
+
+const (
+	directoryPermissions = 0750
+
+	defaultGIFDelay    = 4
+	defaultTileColumns = 8
+)
+
+const (
+	formatPNG = "png"
+	formatGIF = "gif"
+)
+
+var (
+	errNoPalette   = errors.New("no palette given, use -p")
+	errUnsupported = errors.New("unsupported file type")
+	errFormat      = errors.New("unknown output format")
+	errMalformed   = errors.New("malformed file")
+)
+
+type converter struct {
+	outPath   string
+	format    string
+	delay     int
+	columns   int
+	tilesPath string
+	verbose   bool
+	// toDC6 converts PNG sprite sheets back into DC6 files, instead of converting game assets
+	toDC6 bool
+
+	palette   color.Palette
+	transform *d2pl2.PL2PaletteTransform
+}
+
+func main() {
+	var (
+		c                                 converter
+		paletteFile, pl2File, transformID string
+	)
+
+	flag.StringVar(&c.outPath, "o", "./output/", "output directory")
+	flag.StringVar(&paletteFile, "p", "", "palette (.dat) to render with")
+	flag.StringVar(&pl2File, "pl2", "", "palette transforms (.pl2) to render with")
+	flag.StringVar(&transformID, "t", "", "palette transform, for example light:4 or hue:12")
+	flag.StringVar(&c.format, "f", formatPNG, "output format of DC6 and DCC files, png or gif")
+	flag.IntVar(&c.delay, "delay", defaultGIFDelay, "delay between GIF frames, in 100ths of a second")
+	flag.IntVar(&c.columns, "columns", defaultTileColumns, "number of tiles per row of a DT1 tile sheet")
+	flag.StringVar(&c.tilesPath, "tiles", "", "directory of the DT1 files a DS1 refers to")
+	flag.BoolVar(&c.toDC6, "dc6", false, "convert PNG sprite sheets back into DC6, instead of converting game assets")
+	flag.BoolVar(&c.verbose, "v", false, "verbose output")
+	flag.Parse()
+
+	if len(flag.Args()) == 0 {
+		fmt.Printf("Usage: %s [flags] file or directory...\n", os.Args[0])
+		flag.PrintDefaults()
+		os.Exit(1)
+	}
+
+	if c.format != formatPNG && c.format != formatGIF {
+		log.Fatalf("%v: %s", errFormat, c.format)
+	}
+
+	if err := c.loadPalette(paletteFile, pl2File, transformID); err != nil {
+		log.Fatal(err)
+	}
+
+	failed := 0
+
+	for _, path := range flag.Args() {
+		failed += c.convertPath(path)
+	}
+
+	if failed > 0 {
+		log.Fatalf("%d file(s) failed to convert", failed)
+	}
+}
+
+// convertPath converts the given file, or the files of the given directory recursively, and
+// returns the number of files that failed
+func (c *converter) convertPath(root string) int {
+	info, err := os.Stat(root)
+	if err != nil {
+		log.Print(err)
+		return 1
+	}
+
+	if !info.IsDir() {
+		if err := c.convertFile(root, filepath.Base(root)); err != nil {
+			log.Printf("failed to convert %s: %v", root, err)
+			return 1
+		}
+
+		return 0
+	}
+
+	failed := 0
+	base := filepath.Dir(filepath.Clean(root))
+
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || !c.supported(path) {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(base, path)
+		if err != nil {
+			return err
+		}
+
+		if err := c.convertFile(path, relPath); err != nil {
+			log.Printf("failed to convert %s: %v", path, err)
+			failed++
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		log.Print(err)
+		failed++
+	}
+
+	return failed
+}
+
+// supported returns true for the files converted in the mode of the converter, so that the
+// sheets of an earlier run aren't converted back along with the game assets
+func (c *converter) supported(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".dc6", ".dcc", ".dt1", ".ds1":
+		return !c.toDC6
+	case ".png":
+		return c.toDC6
+	}
+
+	return false
+}
+
+// convertFile converts a file, relPath is where its output goes in the output directory
+func (c *converter) convertFile(path, relPath string) (err error) {
+	// the decoders panic on some malformed files
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", errMalformed, r)
+		}
+	}()
+
+	ext := strings.ToLower(filepath.Ext(path))
+	outName := filepath.Join(c.outPath, strings.TrimSuffix(relPath, filepath.Ext(relPath)))
+
+	if !c.supported(path) {
+		if ext == ".png" {
+			return fmt.Errorf("%w: %s, use -dc6 to convert sprite sheets", errUnsupported, ext)
+		}
+
+		return fmt.Errorf("%w: %s", errUnsupported, ext)
+	}
+
+	if c.palette == nil && !c.toDC6 {
+		return errNoPalette
+	}
+
+	data, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outName), directoryPermissions); err != nil {
+		return err
+	}
+
+	if c.verbose {
+		fmt.Printf("Converting: %s\n", path)
+	}
+
+	switch ext {
+	case ".dc6":
+		return c.convertDC6(data, outName)
+	case ".dcc":
+		return c.convertDCC(data, outName)
+	case ".dt1":
+		return c.convertDT1(data, outName)
+	case ".ds1":
+		return c.convertDS1(data, outName)
+	case ".png":
+		return c.convertPNG(path, data, outName)
+	}
+
+	return fmt.Errorf("%w: %s", errUnsupported, ext)
+}
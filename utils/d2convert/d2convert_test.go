@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"image/color"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dc6"
+)
+
+const (
+	testDirections = 2
+	testFrames     = 3
+)
+
+func testConverter(outPath string) *converter {
+	palette := make(color.Palette, 256)
+
+	for idx := range palette {
+		palette[idx] = color.RGBA{R: uint8(idx), G: uint8(idx), B: uint8(idx), A: 255}
+	}
+
+	return &converter{outPath: outPath, format: formatPNG, palette: palette}
+}
+
+// testDC6 returns a DC6 with opaque frames of different sizes and offsets
+func testDC6() *d2dc6.DC6 {
+	dc6 := d2dc6.New()
+	dc6.Version = dc6Version
+	dc6.Flags = dc6Flags
+	dc6.Termination = bytes.Repeat([]byte{dc6Termination}, len(dc6.Termination))
+	dc6.Directions = testDirections
+	dc6.FramesPerDirection = testFrames
+	dc6.Frames = make([]*d2dc6.DC6Frame, testDirections*testFrames)
+
+	for idx := range dc6.Frames {
+		width, height := 2+idx, 3+idx%2
+		pixels := bytes.Repeat([]byte{byte(1 + idx)}, width*height)
+
+		dc6.Frames[idx] = &d2dc6.DC6Frame{OffsetX: int32(-idx), OffsetY: int32(idx - height)}
+		dc6.EncodeFrame(idx, width, height, pixels)
+	}
+
+	return dc6
+}
+
+func writeTestFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), directoryPermissions); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(path, data, filePermissions); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSupported(t *testing.T) {
+	tests := []struct {
+		path  string
+		toDC6 bool
+		want  bool
+	}{
+		{"a/b.DC6", false, true},
+		{"a/b.dcc", false, true},
+		{"a/b.dt1", false, true},
+		{"a/b.ds1", false, true},
+		{"a/b.png", false, false},
+		{"a/b.json", false, false},
+		{"a/b.png", true, true},
+		{"a/b.dc6", true, false},
+	}
+
+	for _, test := range tests {
+		c := converter{toDC6: test.toDC6}
+
+		if have := c.supported(test.path); have != test.want {
+			t.Errorf("%s, toDC6 %v: want %v, have %v", test.path, test.toDC6, test.want, have)
+		}
+	}
+}
+
+func TestDC6RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	want := testDC6()
+
+	writeTestFile(t, filepath.Join(dir, "assets", "sprite.dc6"), want.Marshal())
+
+	// the sprite sheet of the DC6, then the DC6 of the sprite sheet
+	if failed := testConverter(filepath.Join(dir, "sheets")).convertPath(filepath.Join(dir, "assets")); failed != 0 {
+		t.Fatalf("%d files failed to convert to sheets", failed)
+	}
+
+	toDC6 := testConverter(filepath.Join(dir, "dc6"))
+	toDC6.toDC6 = true
+
+	if failed := toDC6.convertPath(filepath.Join(dir, "sheets")); failed != 0 {
+		t.Fatalf("%d files failed to convert back", failed)
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "dc6", "sheets", "assets", "sprite.dc6"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	have, err := d2dc6.Load(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if have.Directions != want.Directions || have.FramesPerDirection != want.FramesPerDirection {
+		t.Fatalf("want %dx%d frames, have %dx%d", want.Directions, want.FramesPerDirection,
+			have.Directions, have.FramesPerDirection)
+	}
+
+	for idx := range want.Frames {
+		wantFrame, haveFrame := want.Frames[idx], have.Frames[idx]
+
+		if haveFrame.Width != wantFrame.Width || haveFrame.Height != wantFrame.Height ||
+			haveFrame.OffsetX != wantFrame.OffsetX || haveFrame.OffsetY != wantFrame.OffsetY {
+			t.Errorf("frame %d: want %+v, have %+v", idx, wantFrame, haveFrame)
+		}
+
+		if !bytes.Equal(have.DecodeFrame(idx), want.DecodeFrame(idx)) {
+			t.Errorf("frame %d: pixels differ", idx)
+		}
+	}
+}
+
+func TestConvertPathModes(t *testing.T) {
+	dir := t.TempDir()
+	assets := filepath.Join(dir, "assets")
+
+	writeTestFile(t, filepath.Join(assets, "sprite.dc6"), testDC6().Marshal())
+
+	// the sheet of an earlier run, in the same directory
+	if failed := testConverter(dir).convertPath(assets); failed != 0 {
+		t.Fatalf("%d files failed to convert", failed)
+	}
+
+	// converting the assets again leaves the sheet alone
+	out := filepath.Join(dir, "out")
+
+	if failed := testConverter(out).convertPath(assets); failed != 0 {
+		t.Fatalf("%d files failed to convert", failed)
+	}
+
+	if _, err := os.Stat(filepath.Join(out, "assets", "sprite.dc6")); !os.IsNotExist(err) {
+		t.Error("want no DC6 converted from the sheet without -dc6")
+	}
+
+	if _, err := os.Stat(filepath.Join(out, "assets", "sprite.png")); err != nil {
+		t.Errorf("want the sheet of the DC6, have %v", err)
+	}
+
+	// a sheet given as a file needs -dc6 as well
+	if failed := testConverter(out).convertPath(filepath.Join(assets, "sprite.png")); failed != 1 {
+		t.Error("want a sheet to fail without -dc6")
+	}
+}
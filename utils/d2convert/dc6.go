@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dc6"
+)
+
+const (
+	dc6Version     = 6
+	dc6Flags       = 1
+	dc6Termination = 0xEE
+)
+
+var (
+	errNotIndexed = errors.New("png is not indexed")
+	errLayout     = errors.New("sprite sheet doesn't match its layout")
+)
+
+// convertPNG converts a sprite sheet back into a DC6. The layout written with the sheet is
+// used when it exists, otherwise the whole image is a single frame.
+func (c *converter) convertPNG(path string, data []byte, outName string) error {
+	decoded, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	img, ok := decoded.(*image.Paletted)
+	if !ok {
+		return errNotIndexed
+	}
+
+	layout, err := readLayout(path, img)
+	if err != nil {
+		return err
+	}
+
+	dc6 := d2dc6.New()
+	dc6.Version = dc6Version
+	dc6.Flags = dc6Flags
+	dc6.Termination = bytes.Repeat([]byte{dc6Termination}, len(dc6.Termination))
+	dc6.Directions = uint32(layout.Directions)
+	dc6.FramesPerDirection = uint32(layout.FramesPerDirection)
+	dc6.Frames = make([]*d2dc6.DC6Frame, layout.Directions*layout.FramesPerDirection)
+
+	for dirIdx := 0; dirIdx < layout.Directions; dirIdx++ {
+		for frameIdx := 0; frameIdx < layout.FramesPerDirection; frameIdx++ {
+			cell := image.Rect(0, 0, layout.CellWidth, layout.CellHeight).
+				Add(img.Rect.Min).
+				Add(image.Pt(frameIdx*layout.CellWidth, dirIdx*layout.CellHeight))
+			bounds := opaqueBounds(img, cell)
+
+			idx := dirIdx*layout.FramesPerDirection + frameIdx
+			dc6.Frames[idx] = &d2dc6.DC6Frame{
+				OffsetX: int32(bounds.Min.X - cell.Min.X - layout.OriginX),
+				OffsetY: int32(bounds.Min.Y - cell.Min.Y - layout.OriginY),
+			}
+
+			dc6.EncodeFrame(idx, bounds.Dx(), bounds.Dy(), indices(img, bounds))
+		}
+	}
+
+	return ioutil.WriteFile(outName+".dc6", dc6.Marshal(), filePermissions)
+}
+
+// readLayout reads the layout written next to a sprite sheet
+func readLayout(path string, img *image.Paletted) (sheetLayout, error) {
+	layout := sheetLayout{
+		Directions:         1,
+		FramesPerDirection: 1,
+		CellWidth:          img.Rect.Dx(),
+		CellHeight:         img.Rect.Dy(),
+	}
+
+	data, err := ioutil.ReadFile(filepath.Clean(strings.TrimSuffix(path, filepath.Ext(path)) + ".json"))
+	if os.IsNotExist(err) {
+		return layout, nil
+	} else if err != nil {
+		return layout, err
+	}
+
+	if err := json.Unmarshal(data, &layout); err != nil {
+		return layout, err
+	}
+
+	if layout.Directions < 1 || layout.FramesPerDirection < 1 || layout.CellWidth < 1 || layout.CellHeight < 1 ||
+		layout.CellWidth*layout.FramesPerDirection > img.Rect.Dx() ||
+		layout.CellHeight*layout.Directions > img.Rect.Dy() {
+		return layout, fmt.Errorf("%w: %+v", errLayout, layout)
+	}
+
+	return layout, nil
+}
+
+// opaqueBounds returns the smallest rectangle of the cell holding all opaque pixels, a frame
+// without any is a single transparent pixel
+func opaqueBounds(img *image.Paletted, cell image.Rectangle) image.Rectangle {
+	bounds := image.Rectangle{}
+
+	for y := cell.Min.Y; y < cell.Max.Y; y++ {
+		for x := cell.Min.X; x < cell.Max.X; x++ {
+			if img.ColorIndexAt(x, y) == 0 {
+				continue
+			}
+
+			pixel := image.Rect(x, y, x+1, y+1)
+
+			if bounds.Empty() {
+				bounds = pixel
+			} else {
+				bounds = bounds.Union(pixel)
+			}
+		}
+	}
+
+	if bounds.Empty() {
+		return image.Rect(cell.Min.X, cell.Min.Y, cell.Min.X+1, cell.Min.Y+1)
+	}
+
+	return bounds
+}
+
+func indices(img *image.Paletted, bounds image.Rectangle) []byte {
+	result := make([]byte, 0, bounds.Dx()*bounds.Dy())
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		offset := img.PixOffset(bounds.Min.X, y)
+		result = append(result, img.Pix[offset:offset+bounds.Dx()]...)
+	}
+
+	return result
+}
@@ -0,0 +1,32 @@
+// This command line utility converts game assets to and from images.
+//
+// DC6 and DCC files are rendered to PNG sprite sheets, with one row per direction and one
+// column per frame, or to animated GIFs, one per direction. Every sprite sheet is written
+// with a JSON file describing its layout, which is used to convert it back into a DC6 with -dc6.
+// DT1 files are rendered to PNG tile sheets and DS1 files to a PNG of the whole map, with the
+// tile layout of the map renderer.
+//
+// Flags:
+// -o [directory] Output directory
+// -p [file] Palette (.dat) to render with
+// -pl2 [file] Palette transforms (.pl2) to render with
+// -t [transform] Palette transform, see below
+// -f [png|gif] Output format of DC6 and DCC files
+// -delay [n] Delay between GIF frames, in 100ths of a second
+// -columns [n] Number of tiles per row of a DT1 tile sheet
+// -tiles [directory] Directory of the DT1 files a DS1 refers to, the extracted data/global/tiles
+// -dc6 Convert PNG sprite sheets back into DC6, only PNG files are converted then
+// -v Enable verbose output
+//
+// The palette transform is one of light, inv, selected, alpha, additive, multiplicative, hue,
+// red, green, blue, unknown, maxcomponent, darkened and text, followed by the index of the
+// transform for the ones that have several, for example hue:12.
+//
+// Usage:
+// First run `go install` in this directory.
+// Then run d2convert with the files or directories to convert, directories are converted
+// recursively, and their layout is kept in the output directory.
+//
+// d2convert -p pal.dat -f gif data/global/monsters
+// d2convert -dc6 -o ./dc6/ ./output/data/global/ui/panel
+package main
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dat"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2pl2"
+)
+
+const numColors = 256
+
+var (
+	errNoPL2     = errors.New("palette transform given without a pl2, use -pl2")
+	errTransform = errors.New("unknown palette transform")
+)
+
+// loadPalette loads the palette to render with, index 0 is transparent
+func (c *converter) loadPalette(paletteFile, pl2File, transformID string) error {
+	if paletteFile == "" {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(filepath.Clean(paletteFile))
+	if err != nil {
+		return err
+	}
+
+	palette, err := d2dat.Load(data)
+	if err != nil {
+		return err
+	}
+
+	c.palette = make(color.Palette, numColors)
+
+	for idx, col := range palette.GetColors() {
+		c.palette[idx] = color.RGBA{R: col.R(), G: col.G(), B: col.B(), A: 0xff}
+	}
+
+	c.palette[0] = color.RGBA{}
+
+	if transformID == "" {
+		return nil
+	}
+
+	if pl2File == "" {
+		return errNoPL2
+	}
+
+	if data, err = ioutil.ReadFile(filepath.Clean(pl2File)); err != nil {
+		return err
+	}
+
+	pl2, err := d2pl2.Load(data)
+	if err != nil {
+		return err
+	}
+
+	c.transform, err = findTransform(pl2, transformID)
+
+	return err
+}
+
+// findTransform returns the transform of the pl2 with the given name and index, like hue:12
+func findTransform(pl2 *d2pl2.PL2, transformID string) (*d2pl2.PL2PaletteTransform, error) {
+	name, indexText := transformID, "0"
+	if idx := strings.IndexByte(transformID, ':'); idx >= 0 {
+		name, indexText = transformID[:idx], transformID[idx+1:]
+	}
+
+	index, err := strconv.Atoi(indexText)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errTransform, transformID)
+	}
+
+	var transforms []d2pl2.PL2PaletteTransform
+
+	switch strings.ToLower(name) {
+	case "light":
+		transforms = pl2.LightLevelVariations[:]
+	case "inv":
+		transforms = pl2.InvColorVariations[:]
+	case "selected":
+		transforms = []d2pl2.PL2PaletteTransform{pl2.SelectedUintShift}
+	case "alpha":
+		// the three alpha levels follow each other
+		for level := range pl2.AlphaBlend {
+			transforms = append(transforms, pl2.AlphaBlend[level][:]...)
+		}
+	case "additive":
+		transforms = pl2.AdditiveBlend[:]
+	case "multiplicative":
+		transforms = pl2.MultiplicativeBlend[:]
+	case "hue":
+		transforms = pl2.HueVariations[:]
+	case "red":
+		transforms = []d2pl2.PL2PaletteTransform{pl2.RedTones}
+	case "green":
+		transforms = []d2pl2.PL2PaletteTransform{pl2.GreenTones}
+	case "blue":
+		transforms = []d2pl2.PL2PaletteTransform{pl2.BlueTones}
+	case "unknown":
+		transforms = pl2.UnknownVariations[:]
+	case "maxcomponent":
+		transforms = pl2.MaxComponentBlend[:]
+	case "darkened":
+		transforms = []d2pl2.PL2PaletteTransform{pl2.DarkendColorShift}
+	case "text":
+		transforms = pl2.TextColorShifts[:]
+	}
+
+	if index < 0 || index >= len(transforms) {
+		return nil, fmt.Errorf("%w: %s", errTransform, transformID)
+	}
+
+	return &transforms[index], nil
+}
+
+// applyTransform maps the palette indices through the palette transform, transparency is kept
+func (c *converter) applyTransform(indexData []byte) []byte {
+	if c.transform == nil {
+		return indexData
+	}
+
+	result := make([]byte, len(indexData))
+
+	for idx, index := range indexData {
+		if index != 0 {
+			result[idx] = c.transform.Indices[index]
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dc6"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dcc"
+)
+
+const filePermissions = 0640
+
+// spriteFrame is a decoded frame, positioned relative to the origin of the sprite
+type spriteFrame struct {
+	left, top     int
+	width, height int
+	pixels        []byte
+}
+
+// sprite holds the frames of each direction
+type sprite [][]spriteFrame
+
+// sheetLayout describes a sprite sheet, it is saved next to the sheet so that it can be
+// converted back
+type sheetLayout struct {
+	Directions         int `json:"directions"`
+	FramesPerDirection int `json:"framesPerDirection"`
+	CellWidth          int `json:"cellWidth"`
+	CellHeight         int `json:"cellHeight"`
+	OriginX            int `json:"originX"`
+	OriginY            int `json:"originY"`
+}
+
+func (c *converter) convertDC6(data []byte, outName string) error {
+	dc6, err := d2dc6.Load(data)
+	if err != nil {
+		return err
+	}
+
+	frames := make(sprite, dc6.Directions)
+
+	for dirIdx := range frames {
+		frames[dirIdx] = make([]spriteFrame, dc6.FramesPerDirection)
+
+		for frameIdx := range frames[dirIdx] {
+			idx := dirIdx*int(dc6.FramesPerDirection) + frameIdx
+			frame := dc6.Frames[idx]
+
+			if frame.Width == 0 || frame.Height == 0 {
+				continue
+			}
+
+			frames[dirIdx][frameIdx] = spriteFrame{
+				left:   int(frame.OffsetX),
+				top:    int(frame.OffsetY),
+				width:  int(frame.Width),
+				height: int(frame.Height),
+				pixels: dc6.DecodeFrame(idx),
+			}
+		}
+	}
+
+	return c.writeSprite(frames, outName)
+}
+
+func (c *converter) convertDCC(data []byte, outName string) error {
+	dcc, err := d2dcc.Load(data)
+	if err != nil {
+		return err
+	}
+
+	frames := make(sprite, len(dcc.Directions))
+
+	// frames are decoded into the box of their direction
+	for dirIdx, direction := range dcc.Directions {
+		frames[dirIdx] = make([]spriteFrame, len(direction.Frames))
+
+		for frameIdx, frame := range direction.Frames {
+			frames[dirIdx][frameIdx] = spriteFrame{
+				left:   direction.Box.Left,
+				top:    direction.Box.Top,
+				width:  direction.Box.Width,
+				height: direction.Box.Height,
+				pixels: frame.PixelData,
+			}
+		}
+	}
+
+	return c.writeSprite(frames, outName)
+}
+
+// layout returns the layout of the sprite sheet, each cell holds the frames of all directions
+func (s sprite) layout() sheetLayout {
+	bounds := image.Rectangle{}
+
+	for _, direction := range s {
+		for _, frame := range direction {
+			if frame.width == 0 || frame.height == 0 {
+				continue
+			}
+
+			rect := image.Rect(frame.left, frame.top, frame.left+frame.width, frame.top+frame.height)
+
+			if bounds.Empty() {
+				bounds = rect
+			} else {
+				bounds = bounds.Union(rect)
+			}
+		}
+	}
+
+	if bounds.Empty() {
+		bounds = image.Rect(0, 0, 1, 1)
+	}
+
+	layout := sheetLayout{
+		Directions: len(s),
+		CellWidth:  bounds.Dx(),
+		CellHeight: bounds.Dy(),
+		OriginX:    -bounds.Min.X,
+		OriginY:    -bounds.Min.Y,
+	}
+
+	if len(s) > 0 {
+		layout.FramesPerDirection = len(s[0])
+	}
+
+	return layout
+}
+
+// drawFrame draws the frame into the cell of the image with the given top left
+func (c *converter) drawFrame(img *image.Paletted, frame spriteFrame, layout sheetLayout, cellX, cellY int) {
+	pixels := c.applyTransform(frame.pixels)
+	left := cellX + layout.OriginX + frame.left
+	top := cellY + layout.OriginY + frame.top
+
+	for y := 0; y < frame.height; y++ {
+		offset := img.PixOffset(left, top+y)
+		copy(img.Pix[offset:offset+frame.width], pixels[y*frame.width:(y+1)*frame.width])
+	}
+}
+
+func (c *converter) writeSprite(frames sprite, outName string) error {
+	layout := frames.layout()
+
+	if c.format == formatGIF {
+		return c.writeGIFs(frames, layout, outName)
+	}
+
+	sheet := image.NewPaletted(image.Rect(0, 0, layout.CellWidth*layout.FramesPerDirection,
+		layout.CellHeight*layout.Directions), c.palette)
+
+	for dirIdx, direction := range frames {
+		for frameIdx, frame := range direction {
+			c.drawFrame(sheet, frame, layout, frameIdx*layout.CellWidth, dirIdx*layout.CellHeight)
+		}
+	}
+
+	if err := writePNG(sheet, outName+".png"); err != nil {
+		return err
+	}
+
+	layoutData, err := json.MarshalIndent(layout, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(outName+".json", layoutData, filePermissions)
+}
+
+// writeGIFs writes an animation for each direction
+func (c *converter) writeGIFs(frames sprite, layout sheetLayout, outName string) error {
+	for dirIdx, direction := range frames {
+		animation := &gif.GIF{}
+
+		for _, frame := range direction {
+			img := image.NewPaletted(image.Rect(0, 0, layout.CellWidth, layout.CellHeight), c.palette)
+			c.drawFrame(img, frame, layout, 0, 0)
+
+			animation.Image = append(animation.Image, img)
+			animation.Delay = append(animation.Delay, c.delay)
+			animation.Disposal = append(animation.Disposal, gif.DisposalBackground)
+		}
+
+		fileName := outName + ".gif"
+		if len(frames) > 1 {
+			fileName = fmt.Sprintf("%s_d%02d.gif", outName, dirIdx)
+		}
+
+		if err := writeFile(fileName, func(f *os.File) error {
+			return gif.EncodeAll(f, animation)
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writePNG(img image.Image, fileName string) error {
+	return writeFile(fileName, func(f *os.File) error {
+		return png.Encode(f, img)
+	})
+}
+
+func writeFile(fileName string, write func(f *os.File) error) error {
+	f, err := os.Create(filepath.Clean(fileName))
+	if err != nil {
+		return err
+	}
+
+	if err := write(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
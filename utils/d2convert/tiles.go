@@ -0,0 +1,291 @@
+package main
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2ds1"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dt1"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2math"
+)
+
+// tile layout of the map renderer
+const (
+	tileWidth         = 80
+	tileHeight        = 40
+	tileSurfaceWidth  = 160
+	tileSurfaceHeight = 80
+	blockHeight       = 32
+	shadowAlpha       = 160
+)
+
+var errNoTiles = errors.New("no tiles directory given, use -tiles")
+
+// tileGraphic is a tile decoded the way the map renderer caches it, drawn at yAdjust below
+// the top of the tile
+type tileGraphic struct {
+	width, height int
+	yAdjust       int
+	pixels        []byte
+}
+
+// decodeTile decodes a DT1 tile, the left part of a north corner wall is drawn with its right
+// part
+func decodeTile(tile, leftPart *d2dt1.Tile) tileGraphic {
+	minY, maxY := 0, 0
+
+	blocks := tile.Blocks
+	if leftPart != nil && leftPart.Height < tile.Height {
+		blocks = leftPart.Blocks
+	}
+
+	for _, block := range blocks {
+		minY = d2math.MinInt(minY, int(block.Y))
+		maxY = d2math.MaxInt(maxY, int(block.Y)+blockHeight)
+	}
+
+	graphic := tileGraphic{width: int(tile.Width), height: int(d2math.AbsInt32(tile.Height))}
+
+	switch d2enum.TileType(tile.Type) {
+	case d2enum.TileFloor:
+	case d2enum.TileShadow:
+		graphic.height = maxY - minY
+		graphic.yAdjust = minY + tileSurfaceHeight
+	default:
+		graphic.width = tileSurfaceWidth
+		graphic.height = d2math.MaxInt(graphic.height, maxY-minY)
+		graphic.yAdjust = minY + tileSurfaceHeight
+
+		if d2enum.TileType(tile.Type) == d2enum.TileRoof {
+			graphic.yAdjust = -int(tile.RoofHeight)
+		}
+	}
+
+	if graphic.width <= 0 || graphic.height <= 0 {
+		return tileGraphic{}
+	}
+
+	graphic.pixels = make([]byte, graphic.width*graphic.height)
+	d2dt1.DecodeTileGfxData(tile.Blocks, &graphic.pixels, int32(-minY), int32(graphic.width))
+
+	if leftPart != nil {
+		d2dt1.DecodeTileGfxData(leftPart.Blocks, &graphic.pixels, int32(-minY), int32(graphic.width))
+	}
+
+	return graphic
+}
+
+// convertDT1 renders the tiles of a DT1 to a tile sheet
+func (c *converter) convertDT1(data []byte, outName string) error {
+	dt1, err := d2dt1.LoadDT1(data)
+	if err != nil {
+		return err
+	}
+
+	graphics := make([]tileGraphic, len(dt1.Tiles))
+	cellWidth, cellHeight := 1, 1
+
+	for idx := range dt1.Tiles {
+		graphics[idx] = decodeTile(&dt1.Tiles[idx], nil)
+		cellWidth = d2math.MaxInt(cellWidth, graphics[idx].width)
+		cellHeight = d2math.MaxInt(cellHeight, graphics[idx].height)
+	}
+
+	columns := d2math.MaxInt(1, d2math.MinInt(c.columns, len(graphics)))
+	rows := (len(graphics) + columns - 1) / columns
+	sheet := image.NewPaletted(image.Rect(0, 0, columns*cellWidth, d2math.MaxInt(1, rows)*cellHeight), c.palette)
+
+	for idx, graphic := range graphics {
+		frame := spriteFrame{width: graphic.width, height: graphic.height, pixels: graphic.pixels}
+		c.drawFrame(sheet, frame, sheetLayout{}, (idx%columns)*cellWidth, (idx/columns)*cellHeight)
+	}
+
+	return writePNG(sheet, outName+".png")
+}
+
+type tileKey struct {
+	style, sequence int32
+	tileType        d2enum.TileType
+}
+
+// tileDraw is a tile graphic placed on the map
+type tileDraw struct {
+	graphic tileGraphic
+	x, y    int
+	alpha   uint8
+}
+
+// mapRenderer lays out the tiles of a DS1 like the map renderer does
+type mapRenderer struct {
+	tiles    map[tileKey][]d2dt1.Tile
+	graphics map[*d2dt1.Tile]tileGraphic
+	draws    []tileDraw
+}
+
+// convertDS1 renders a DS1 with the DT1 files it refers to
+func (c *converter) convertDS1(data []byte, outName string) error {
+	if c.tilesPath == "" {
+		return errNoTiles
+	}
+
+	ds1, err := d2ds1.Unmarshal(data)
+	if err != nil {
+		return err
+	}
+
+	mr := &mapRenderer{tiles: make(map[tileKey][]d2dt1.Tile), graphics: make(map[*d2dt1.Tile]tileGraphic)}
+
+	for _, fileName := range ds1.Files {
+		if err := mr.addDT1(c.tilesPath, fileName); err != nil {
+			log.Printf("failed to load the tiles of %s: %v", fileName, err)
+		}
+	}
+
+	width, height := ds1.Width(), ds1.Height()
+
+	// lower walls, floors and shadows, then upper walls, then roofs
+	for pass := 0; pass < 3; pass++ {
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				mr.placeTile(ds1, pass, x, y)
+			}
+		}
+	}
+
+	return writePNG(mr.render(c), outName+".png")
+}
+
+// addDT1 loads a DT1 the way the map engine resolves the file names of a DS1
+func (mr *mapRenderer) addDT1(tilesPath, fileName string) error {
+	fileName = strings.ToLower(fileName)
+	fileName = strings.ReplaceAll(fileName, "c:", "")
+	fileName = strings.ReplaceAll(fileName, ".tg1", ".dt1")
+	fileName = strings.ReplaceAll(fileName, "\\d2\\data\\global\\tiles\\", "")
+	fileName = strings.ReplaceAll(fileName, "\\", "/")
+
+	data, err := ioutil.ReadFile(filepath.Join(tilesPath, filepath.FromSlash(fileName)))
+	if err != nil {
+		return err
+	}
+
+	dt1, err := d2dt1.LoadDT1(data)
+	if err != nil {
+		return err
+	}
+
+	for _, tile := range dt1.Tiles {
+		key := tileKey{style: tile.Style, sequence: tile.Sequence, tileType: d2enum.TileType(tile.Type)}
+		mr.tiles[key] = append(mr.tiles[key], tile)
+	}
+
+	return nil
+}
+
+// graphic returns the graphic of a DS1 tile, the first of the DT1 tiles it can use
+func (mr *mapRenderer) graphic(tile *d2ds1.Tile, tileType d2enum.TileType) (tileGraphic, bool) {
+	options := mr.tiles[tileKey{style: int32(tile.Style), sequence: int32(tile.Sequence), tileType: tileType}]
+	if len(options) == 0 {
+		return tileGraphic{}, false
+	}
+
+	dt1Tile := &options[0]
+
+	if graphic, found := mr.graphics[dt1Tile]; found {
+		return graphic, graphic.pixels != nil
+	}
+
+	var leftPart *d2dt1.Tile
+
+	if tileType == d2enum.TileRightPartOfNorthCornerWall {
+		key := tileKey{style: int32(tile.Style), sequence: int32(tile.Sequence),
+			tileType: d2enum.TileLeftPartOfNorthCornerWall}
+		if leftParts := mr.tiles[key]; len(leftParts) > 0 {
+			leftPart = &leftParts[0]
+		}
+	}
+
+	graphic := decodeTile(dt1Tile, leftPart)
+	mr.graphics[dt1Tile] = graphic
+
+	return graphic, graphic.pixels != nil
+}
+
+// placeTile places the graphics drawn in the given pass of the tile at x, y
+func (mr *mapRenderer) placeTile(ds1 *d2ds1.DS1, pass, x, y int) {
+	visible := func(tile *d2ds1.Tile) bool {
+		return tile != nil && !tile.Hidden() && tile.Prop1 != 0
+	}
+
+	place := func(tile *d2ds1.Tile, tileType d2enum.TileType, alpha uint8) {
+		graphic, ok := mr.graphic(tile, tileType)
+		if !ok {
+			return
+		}
+
+		mr.draws = append(mr.draws, tileDraw{
+			graphic: graphic,
+			x:       (x-y)*tileWidth - tileWidth,
+			y:       (x+y)*tileHeight + graphic.yAdjust,
+			alpha:   alpha,
+		})
+	}
+
+	for _, wall := range ds1.Walls {
+		tile := wall.Tile(x, y)
+		if !visible(tile) {
+			continue
+		}
+
+		switch {
+		case pass == 0 && tile.Type.LowerWall(),
+			pass == 1 && tile.Type.UpperWall(),
+			pass == 2 && tile.Type == d2enum.TileRoof:
+			place(tile, tile.Type, 0xff)
+		}
+	}
+
+	if pass != 0 {
+		return
+	}
+
+	for _, floor := range ds1.Floors {
+		if tile := floor.Tile(x, y); visible(tile) {
+			place(tile, d2enum.TileFloor, 0xff)
+		}
+	}
+
+	for _, shadow := range ds1.Shadows {
+		if tile := shadow.Tile(x, y); visible(tile) {
+			place(tile, d2enum.TileShadow, shadowAlpha)
+		}
+	}
+}
+
+// render draws the placed tiles onto an image holding all of them
+func (mr *mapRenderer) render(c *converter) image.Image {
+	bounds := image.Rect(0, 0, 1, 1)
+
+	for _, tile := range mr.draws {
+		bounds = bounds.Union(image.Rect(tile.x, tile.y, tile.x+tile.graphic.width, tile.y+tile.graphic.height))
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+
+	for _, tile := range mr.draws {
+		graphic := image.NewPaletted(image.Rect(0, 0, tile.graphic.width, tile.graphic.height), c.palette)
+		graphic.Pix = c.applyTransform(tile.graphic.pixels)
+
+		at := image.Pt(tile.x, tile.y).Sub(bounds.Min)
+		draw.DrawMask(img, graphic.Rect.Add(at), graphic, image.Point{}, image.NewUniform(color.Alpha{A: tile.alpha}),
+			image.Point{}, draw.Over)
+	}
+
+	return img
+}